@@ -0,0 +1,126 @@
+// Package cidr carves the blocks of IP addresses for the subnets out of the block of the network, and validates the ones passed
+// for containment in the network and overlap with the rest. Only the IPv4 blocks are supported, as the networks are created with them.
+package cidr
+
+import (
+	"encoding/binary"
+	"net"
+
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// block is the IPv4 CIDR block as the range of addresses [first, last].
+type block struct {
+	first, last uint64
+	cidr        string
+}
+
+// Carve returns count blocks of the prefix length passed, carved in order out of the blocks of the network (parents).
+// The blocks which overlap the ones taken (ex: the subnets present in the network) are skipped.
+func Carve(parents []string, prefix, count int, taken []string) ([]string, error) {
+
+	if count <= 0 {
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The number of subnets to be carved has to be atleast 1, got %d", count)
+	}
+	if prefix < 1 || prefix > 32 {
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The prefix length of the subnets has to be between 1 and 32, got %d", prefix)
+	}
+	networks, err := parse(parents)
+	if err != nil {
+		return nil, err
+	}
+	used, err := parse(taken)
+	if err != nil {
+		return nil, err
+	}
+	size := uint64(1) << uint(32-prefix)
+
+	carved := make([]string, 0, count)
+	for _, network := range networks {
+		if network.last-network.first+1 < size {
+			continue
+		}
+		for first := network.first; first+size-1 <= network.last && len(carved) < count; {
+			candidate := block{first: first, last: first + size - 1}
+			if overlap, ok := overlapping(candidate, used); ok {
+				// skips to the first aligned block past the one taken.
+				first = (overlap.last/size + 1) * size
+				continue
+			}
+			candidate.cidr = format(candidate.first, prefix)
+			carved = append(carved, candidate.cidr)
+			used = append(used, candidate)
+			first += size
+		}
+	}
+	if len(carved) < count {
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "There is no room for %d subnets of prefix length /%d in %v, only %d could be carved", count, prefix, parents, len(carved))
+	}
+	return carved, nil
+}
+
+// Validate makes sure that each of the subnets passed lies within one of the blocks of the network (parents),
+// and overlaps neither the other subnets passed nor the ones taken (ex: the subnets present in the network).
+func Validate(parents, subnets, taken []string) error {
+
+	networks, err := parse(parents)
+	if err != nil {
+		return err
+	}
+	used, err := parse(taken)
+	if err != nil {
+		return err
+	}
+	requested, err := parse(subnets)
+	if err != nil {
+		return err
+	}
+	for _, subnet := range requested {
+		if !contained(subnet, networks) {
+			return cloudyerror.Newf(cloudyerror.InvalidInput, "The subnet %s does not lie within the blocks %v of the network", subnet.cidr, parents)
+		}
+		if overlap, ok := overlapping(subnet, used); ok {
+			return cloudyerror.Newf(cloudyerror.InvalidInput, "The subnet %s overlaps the subnet %s", subnet.cidr, overlap.cidr)
+		}
+		used = append(used, subnet)
+	}
+	return nil
+}
+
+func parse(cidrs []string) ([]block, error) {
+	blocks := make([]block, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		ip, network, err := net.ParseCIDR(cidr)
+		if err != nil || ip.To4() == nil {
+			return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The CIDR block %q is not a valid IPv4 block", cidr)
+		}
+		ones, bits := network.Mask.Size()
+		first := uint64(binary.BigEndian.Uint32(network.IP.To4()))
+		blocks = append(blocks, block{first: first, last: first + (uint64(1) << uint(bits-ones)) - 1, cidr: cidr})
+	}
+	return blocks, nil
+}
+
+func format(first uint64, prefix int) string {
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, uint32(first))
+	return (&net.IPNet{IP: ip, Mask: net.CIDRMask(prefix, 32)}).String()
+}
+
+func overlapping(candidate block, blocks []block) (block, bool) {
+	for _, b := range blocks {
+		if candidate.first <= b.last && b.first <= candidate.last {
+			return b, true
+		}
+	}
+	return block{}, false
+}
+
+func contained(candidate block, blocks []block) bool {
+	for _, b := range blocks {
+		if b.first <= candidate.first && candidate.last <= b.last {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package cidr
+
+import (
+	"reflect"
+	"testing"
+
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+func TestCarve(t *testing.T) {
+	tests := []struct {
+		name    string
+		parents []string
+		prefix  int
+		count   int
+		taken   []string
+		want    []string
+	}{
+		{"in order", []string{"10.0.0.0/16"}, 24, 3, nil, []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24"}},
+		{"skips taken", []string{"10.0.0.0/16"}, 24, 2, []string{"10.0.0.0/24", "10.0.1.128/25"}, []string{"10.0.2.0/24", "10.0.3.0/24"}},
+		{"fills the gaps", []string{"10.0.0.0/24"}, 26, 2, []string{"10.0.0.64/26"}, []string{"10.0.0.0/26", "10.0.0.128/26"}},
+		{"skips larger taken", []string{"10.0.0.0/16"}, 26, 1, []string{"10.0.0.0/20"}, []string{"10.0.16.0/26"}},
+		{"spans the secondary blocks", []string{"10.0.0.0/24", "10.1.0.0/24"}, 25, 3, nil, []string{"10.0.0.0/25", "10.0.0.128/25", "10.1.0.0/25"}},
+	}
+	for _, test := range tests {
+		got, err := Carve(test.parents, test.prefix, test.count, test.taken)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, got)
+		}
+	}
+}
+
+func TestCarveNoRoom(t *testing.T) {
+	if _, err := Carve([]string{"10.0.0.0/24"}, 25, 3, nil); !cloudyerror.IsInvalidInput(err) {
+		t.Errorf("expected invalid input when the network has no room, got %v", err)
+	}
+	if _, err := Carve([]string{"10.0.0.0/24"}, 16, 1, nil); !cloudyerror.IsInvalidInput(err) {
+		t.Errorf("expected invalid input for the prefix larger than the network, got %v", err)
+	}
+	if _, err := Carve([]string{"10.0.0.0/24"}, 26, 0, nil); !cloudyerror.IsInvalidInput(err) {
+		t.Errorf("expected invalid input for no subnets, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	parents := []string{"10.0.0.0/16", "10.1.0.0/24"}
+	if err := Validate(parents, []string{"10.0.1.0/24", "10.1.0.0/25"}, []string{"10.0.0.0/24"}); err != nil {
+		t.Errorf("expected the subnets to be valid, got %v", err)
+	}
+	invalid := map[string][]string{
+		"outside the network": {"10.2.0.0/24"},
+		"partly outside":      {"10.1.0.0/23"},
+		"overlaps the taken":  {"10.0.0.128/25"},
+		"overlaps each other": {"10.0.4.0/24", "10.0.4.0/25"},
+		"not a block":         {"10.0.300.0/24"},
+		"not an ipv4 block":   {"2001:db8::/64"},
+	}
+	for name, subnets := range invalid {
+		if err := Validate(parents, subnets, []string{"10.0.0.0/24"}); !cloudyerror.IsInvalidInput(err) {
+			t.Errorf("%s: expected invalid input for %v, got %v", name, subnets, err)
+		}
+	}
+}
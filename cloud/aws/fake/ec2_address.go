@@ -0,0 +1,462 @@
+package awsfake
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// AllocateAddressWithContext allocates an elastic ip for use in vpc, the addresses are allocated from the range 203.0.113.0/24.
+func (e *EC2) AllocateAddressWithContext(ctx aws.Context, input *ec2.AllocateAddressInput, _ ...request.Option) (*ec2.AllocateAddressOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "AllocateAddress")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	domain := aws.StringValue(input.Domain)
+	if domain == "" {
+		domain = ec2.DomainTypeVpc
+	}
+	if domain != ec2.DomainTypeVpc {
+		return nil, apiError("InvalidParameterValue", "Value (%s) for parameter domain is invalid. Only vpc is supported.", domain)
+	}
+	reg.addressSeq++
+	address := &ec2.Address{
+		AllocationId: aws.String(e.cloud.id("eipalloc")),
+		Domain:       aws.String(domain),
+		PublicIp:     aws.String(fmt.Sprintf("203.0.113.%d", reg.addressSeq%256)),
+	}
+	reg.addresses[*address.AllocationId] = address
+	return &ec2.AllocateAddressOutput{AllocationId: address.AllocationId, Domain: address.Domain, PublicIp: address.PublicIp}, nil
+}
+
+// DescribeAddressesWithContext describes the addresses selected, supports filters: allocation-id, association-id, domain, instance-id,
+// network-interface-id, private-ip-address, public-ip and tags.
+func (e *EC2) DescribeAddressesWithContext(ctx aws.Context, input *ec2.DescribeAddressesInput, _ ...request.Option) (*ec2.DescribeAddressesOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DescribeAddresses")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	ids, err := reg.selectIDs(input.AllocationIds, reg.addressIDs(), "InvalidAllocationID.NotFound", "allocation")
+	if err != nil {
+		return &ec2.DescribeAddressesOutput{}, err
+	}
+	out := &ec2.DescribeAddressesOutput{Addresses: make([]*ec2.Address, 0)}
+	for _, id := range ids {
+		address := reg.addresses[id]
+		if len(input.PublicIps) != 0 && !contains(aws.StringValueSlice(input.PublicIps), *address.PublicIp) {
+			continue
+		}
+		attrs := attributes{
+			"allocation-id":        {*address.AllocationId},
+			"association-id":       {aws.StringValue(address.AssociationId)},
+			"domain":               {*address.Domain},
+			"instance-id":          {aws.StringValue(address.InstanceId)},
+			"network-interface-id": {aws.StringValue(address.NetworkInterfaceId)},
+			"private-ip-address":   {aws.StringValue(address.PrivateIpAddress)},
+			"public-ip":            {*address.PublicIp},
+		}
+		ok, err := matches(input.Filters, attrs, address.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out.Addresses = append(out.Addresses, awsutil.CopyOf(address).(*ec2.Address))
+		}
+	}
+	return out, nil
+}
+
+// AssociateAddressWithContext associates the address with either the instance or the network interface passed,
+// the address associated with an instance becomes the public ip of the instance.
+func (e *EC2) AssociateAddressWithContext(ctx aws.Context, input *ec2.AssociateAddressInput, _ ...request.Option) (*ec2.AssociateAddressOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "AssociateAddress")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.AllocationId)
+	address, ok := reg.addresses[id]
+	if !ok {
+		return nil, apiError("InvalidAllocationID.NotFound", "The allocation ID '%s' does not exist", id)
+	}
+	if address.AssociationId != nil && !aws.BoolValue(input.AllowReassociation) {
+		return nil, apiError("Resource.AlreadyAssociated", "resource %s is already associated with associate-id %s", id, *address.AssociationId)
+	}
+
+	instanceID, interfaceID := aws.StringValue(input.InstanceId), aws.StringValue(input.NetworkInterfaceId)
+	switch {
+	case instanceID != "":
+		instance := reg.instance(instanceID)
+		if instance == nil || !live(instance) {
+			return nil, apiError("InvalidInstanceID.NotFound", "The instance ID '%s' does not exist", instanceID)
+		}
+		reg.disassociate(address)
+		address.InstanceId, address.PrivateIpAddress = instance.InstanceId, instance.PrivateIpAddress
+		instance.PublicIpAddress = address.PublicIp
+	case interfaceID != "":
+		eni, ok := reg.interfaces[interfaceID]
+		if !ok {
+			return nil, apiError("InvalidNetworkInterfaceID.NotFound", "The networkInterface ID '%s' does not exist", interfaceID)
+		}
+		reg.disassociate(address)
+		address.NetworkInterfaceId, address.PrivateIpAddress = eni.NetworkInterfaceId, eni.PrivateIpAddress
+		address.NetworkInterfaceOwnerId = aws.String(OwnerID)
+		eni.Association = &ec2.NetworkInterfaceAssociation{AllocationId: address.AllocationId, PublicIp: address.PublicIp, IpOwnerId: aws.String(OwnerID)}
+	default:
+		return nil, apiError("MissingParameter", "Either instance ID or network interface ID must be specified")
+	}
+	address.AssociationId = aws.String(e.cloud.id("eipassoc"))
+	if eni, ok := reg.interfaces[interfaceID]; ok {
+		eni.Association.AssociationId = address.AssociationId
+	}
+	return &ec2.AssociateAddressOutput{AssociationId: address.AssociationId}, nil
+}
+
+// DisassociateAddressWithContext disassociates the address from the instance or network interface it is associated with.
+func (e *EC2) DisassociateAddressWithContext(ctx aws.Context, input *ec2.DisassociateAddressInput, _ ...request.Option) (*ec2.DisassociateAddressOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DisassociateAddress")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.AssociationId)
+	for _, address := range reg.addresses {
+		if aws.StringValue(address.AssociationId) == id {
+			if reg.natOfAddress(*address.AllocationId) != nil {
+				return nil, apiError("AuthFailure", "You do not have permission to access the specified resource.")
+			}
+			reg.disassociate(address)
+			return &ec2.DisassociateAddressOutput{}, nil
+		}
+	}
+	return nil, apiError("InvalidAssociationID.NotFound", "The association ID '%s' does not exist", id)
+}
+
+// ReleaseAddressWithContext releases the address, it fails with InvalidIPAddress.InUse while the address is associated.
+func (e *EC2) ReleaseAddressWithContext(ctx aws.Context, input *ec2.ReleaseAddressInput, _ ...request.Option) (*ec2.ReleaseAddressOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "ReleaseAddress")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.AllocationId)
+	address, ok := reg.addresses[id]
+	if !ok {
+		return nil, apiError("InvalidAllocationID.NotFound", "The allocation ID '%s' does not exist", id)
+	}
+	if address.AssociationId != nil {
+		return nil, apiError("InvalidIPAddress.InUse", "Address %s is in use.", *address.PublicIp)
+	}
+	delete(reg.addresses, id)
+	return &ec2.ReleaseAddressOutput{}, nil
+}
+
+// CreateNatGatewayWithContext creates the nat gateway in the subnet with the address passed, the gateway is available as soon as it is created.
+// The network interface of the gateway is managed by aws, hence it cannot be deleted by the user.
+func (e *EC2) CreateNatGatewayWithContext(ctx aws.Context, input *ec2.CreateNatGatewayInput, _ ...request.Option) (*ec2.CreateNatGatewayOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "CreateNatGateway")
+	if err != nil {
+		return nil, err
+	}
+
+	subnet, ok := reg.subnets[aws.StringValue(input.SubnetId)]
+	if !ok {
+		return nil, apiError("InvalidSubnetID.NotFound", "The subnet ID '%s' does not exist", aws.StringValue(input.SubnetId))
+	}
+	address, ok := reg.addresses[aws.StringValue(input.AllocationId)]
+	if !ok {
+		return nil, apiError("InvalidAllocationID.NotFound", "The allocation ID '%s' does not exist", aws.StringValue(input.AllocationId))
+	}
+	if address.AssociationId != nil {
+		return nil, apiError("Resource.AlreadyAssociated", "Elastic IP address [%s] is already associated", *address.AllocationId)
+	}
+
+	eni := reg.newInterface(e.cloud, subnet, nil, "Interface for NAT Gateway")
+	eni.InterfaceType = aws.String(ec2.NetworkInterfaceTypeNatGateway)
+	eni.RequesterManaged = aws.Bool(true)
+	eni.Status = aws.String(ec2.NetworkInterfaceStatusInUse)
+	address.NetworkInterfaceId, address.PrivateIpAddress = eni.NetworkInterfaceId, eni.PrivateIpAddress
+	address.NetworkInterfaceOwnerId = aws.String(OwnerID)
+	address.AssociationId = aws.String(e.cloud.id("eipassoc"))
+	eni.Association = &ec2.NetworkInterfaceAssociation{AllocationId: address.AllocationId, AssociationId: address.AssociationId, PublicIp: address.PublicIp, IpOwnerId: aws.String(OwnerID)}
+
+	nat := &ec2.NatGateway{
+		NatGatewayId: aws.String(e.cloud.id("nat")),
+		SubnetId:     subnet.SubnetId,
+		VpcId:        subnet.VpcId,
+		State:        aws.String(ec2.NatGatewayStateAvailable),
+		CreateTime:   aws.Time(e.cloud.now()),
+		NatGatewayAddresses: []*ec2.NatGatewayAddress{{
+			AllocationId:       address.AllocationId,
+			NetworkInterfaceId: eni.NetworkInterfaceId,
+			PrivateIp:          eni.PrivateIpAddress,
+			PublicIp:           address.PublicIp,
+		}},
+	}
+	reg.natGateways[*nat.NatGatewayId] = nat
+
+	out := awsutil.CopyOf(nat).(*ec2.NatGateway)
+	out.State = aws.String(ec2.NatGatewayStatePending)
+	return &ec2.CreateNatGatewayOutput{NatGateway: out, ClientToken: input.ClientToken}, nil
+}
+
+// DescribeNatGatewaysWithContext describes the nat gateways selected, supports filters: nat-gateway-id, subnet-id, vpc-id, state and tags.
+// The gateways deleted remain visible with the state deleted as they are in aws.
+func (e *EC2) DescribeNatGatewaysWithContext(ctx aws.Context, input *ec2.DescribeNatGatewaysInput, _ ...request.Option) (*ec2.DescribeNatGatewaysOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DescribeNatGateways")
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := reg.selectIDs(input.NatGatewayIds, reg.natGatewayIDs(), "NatGatewayNotFound", "natGateway")
+	if err != nil {
+		return &ec2.DescribeNatGatewaysOutput{}, err
+	}
+	out := &ec2.DescribeNatGatewaysOutput{NatGateways: make([]*ec2.NatGateway, 0)}
+	for _, id := range ids {
+		nat := reg.natGateways[id]
+		attrs := attributes{
+			"nat-gateway-id": {*nat.NatGatewayId},
+			"subnet-id":      {*nat.SubnetId},
+			"vpc-id":         {*nat.VpcId},
+			"state":          {*nat.State},
+		}
+		ok, err := matches(input.Filter, attrs, nat.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out.NatGateways = append(out.NatGateways, awsutil.CopyOf(nat).(*ec2.NatGateway))
+		}
+	}
+	return out, nil
+}
+
+// DeleteNatGatewayWithContext deletes the nat gateway along with its network interface, the address of the gateway is disassociated but not released.
+func (e *EC2) DeleteNatGatewayWithContext(ctx aws.Context, input *ec2.DeleteNatGatewayInput, _ ...request.Option) (*ec2.DeleteNatGatewayOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DeleteNatGateway")
+	if err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.NatGatewayId)
+	nat, ok := reg.natGateways[id]
+	if !ok || *nat.State == ec2.NatGatewayStateDeleted {
+		return nil, apiError("NatGatewayNotFound", "The Nat Gateway %s was not found", id)
+	}
+	for _, gatewayAddress := range nat.NatGatewayAddresses {
+		if address, ok := reg.addresses[aws.StringValue(gatewayAddress.AllocationId)]; ok {
+			reg.disassociate(address)
+		}
+		delete(reg.interfaces, aws.StringValue(gatewayAddress.NetworkInterfaceId))
+	}
+	nat.State = aws.String(ec2.NatGatewayStateDeleted)
+	nat.DeleteTime = aws.Time(e.cloud.now())
+	return &ec2.DeleteNatGatewayOutput{NatGatewayId: nat.NatGatewayId}, nil
+}
+
+// CreateNetworkInterfaceWithContext creates the network interface in the subnet, the default security group of the vpc is used if none are passed.
+func (e *EC2) CreateNetworkInterfaceWithContext(ctx aws.Context, input *ec2.CreateNetworkInterfaceInput, _ ...request.Option) (*ec2.CreateNetworkInterfaceOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "CreateNetworkInterface")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	subnet, ok := reg.subnets[aws.StringValue(input.SubnetId)]
+	if !ok {
+		return nil, apiError("InvalidSubnetID.NotFound", "The subnet ID '%s' does not exist", aws.StringValue(input.SubnetId))
+	}
+	groupIDs := aws.StringValueSlice(input.Groups)
+	if len(groupIDs) == 0 {
+		for _, id := range reg.groupIDs() {
+			if *reg.groups[id].VpcId == *subnet.VpcId && *reg.groups[id].GroupName == "default" {
+				groupIDs = []string{id}
+			}
+		}
+	}
+	groups := make([]*ec2.GroupIdentifier, 0)
+	for _, id := range groupIDs {
+		group, ok := reg.groups[id]
+		if !ok {
+			return nil, apiError("InvalidGroup.NotFound", "The security group '%s' does not exist", id)
+		}
+		if *group.VpcId != *subnet.VpcId {
+			return nil, apiError("InvalidParameter", "Security group %s and subnet %s belong to different networks.", id, *subnet.SubnetId)
+		}
+		groups = append(groups, &ec2.GroupIdentifier{GroupId: group.GroupId, GroupName: group.GroupName})
+	}
+
+	eni := reg.newInterface(e.cloud, subnet, groups, aws.StringValue(input.Description))
+	return &ec2.CreateNetworkInterfaceOutput{NetworkInterface: awsutil.CopyOf(eni).(*ec2.NetworkInterface)}, nil
+}
+
+// DescribeNetworkInterfacesWithContext describes the network interfaces selected, supports filters: network-interface-id, subnet-id, vpc-id,
+// status, group-id, interface-type, requester-managed, private-ip-address, description and tags.
+// Only the interfaces created explicitly and the ones of the nat gateways and application loadbalancers are modelled, the instances and classic
+// loadbalancers have none. The interfaces of the application loadbalancers deleted are released after being described interfaceReleasePolls times.
+func (e *EC2) DescribeNetworkInterfacesWithContext(ctx aws.Context, input *ec2.DescribeNetworkInterfacesInput, _ ...request.Option) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DescribeNetworkInterfaces")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	ids, err := reg.selectIDs(input.NetworkInterfaceIds, reg.interfaceIDs(), "InvalidNetworkInterfaceID.NotFound", "networkInterface")
+	if err != nil {
+		return &ec2.DescribeNetworkInterfacesOutput{}, err
+	}
+	out := &ec2.DescribeNetworkInterfacesOutput{NetworkInterfaces: make([]*ec2.NetworkInterface, 0)}
+	for _, id := range ids {
+		eni := reg.interfaces[id]
+		attrs := attributes{
+			"network-interface-id": {*eni.NetworkInterfaceId},
+			"subnet-id":            {*eni.SubnetId},
+			"vpc-id":               {*eni.VpcId},
+			"status":               {*eni.Status},
+			"group-id":             {},
+			"interface-type":       {*eni.InterfaceType},
+			"requester-managed":    {boolString(aws.BoolValue(eni.RequesterManaged))},
+			"private-ip-address":   {*eni.PrivateIpAddress},
+			"description":          {aws.StringValue(eni.Description)},
+		}
+		for _, group := range eni.Groups {
+			attrs["group-id"] = append(attrs["group-id"], *group.GroupId)
+		}
+		ok, err := matches(input.Filters, attrs, eni.TagSet)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out.NetworkInterfaces = append(out.NetworkInterfaces, awsutil.CopyOf(eni).(*ec2.NetworkInterface))
+		}
+	}
+	for id := range reg.releasing {
+		if reg.releasing[id]--; reg.releasing[id] == 0 {
+			delete(reg.releasing, id)
+			delete(reg.interfaces, id)
+		}
+	}
+	return out, nil
+}
+
+// DeleteNetworkInterfaceWithContext deletes the network interface, the address associated with it is disassociated.
+// It fails with InvalidNetworkInterface.InUse while the interface is in use (ex: by a nat gateway).
+func (e *EC2) DeleteNetworkInterfaceWithContext(ctx aws.Context, input *ec2.DeleteNetworkInterfaceInput, _ ...request.Option) (*ec2.DeleteNetworkInterfaceOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DeleteNetworkInterface")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.NetworkInterfaceId)
+	eni, ok := reg.interfaces[id]
+	if !ok {
+		return nil, apiError("InvalidNetworkInterfaceID.NotFound", "The networkInterface ID '%s' does not exist", id)
+	}
+	if *eni.Status != ec2.NetworkInterfaceStatusAvailable {
+		return nil, apiError("InvalidNetworkInterface.InUse", "The network interface '%s' is currently in use.", id)
+	}
+	for _, address := range reg.addresses {
+		if aws.StringValue(address.NetworkInterfaceId) == id {
+			reg.disassociate(address)
+		}
+	}
+	delete(reg.interfaces, id)
+	return &ec2.DeleteNetworkInterfaceOutput{}, nil
+}
+
+func (r *region) newInterface(c *Cloud, subnet *ec2.Subnet, groups []*ec2.GroupIdentifier, description string) *ec2.NetworkInterface {
+	eni := &ec2.NetworkInterface{
+		NetworkInterfaceId: aws.String(c.id("eni")),
+		SubnetId:           subnet.SubnetId,
+		VpcId:              subnet.VpcId,
+		AvailabilityZone:   subnet.AvailabilityZone,
+		Description:        aws.String(description),
+		Groups:             groups,
+		InterfaceType:      aws.String(ec2.NetworkInterfaceTypeInterface),
+		OwnerId:            aws.String(OwnerID),
+		PrivateIpAddress:   aws.String(r.nextPrivateIP(subnet)),
+		RequesterManaged:   aws.Bool(false),
+		Status:             aws.String(ec2.NetworkInterfaceStatusAvailable),
+	}
+	r.interfaces[*eni.NetworkInterfaceId] = eni
+	return eni
+}
+
+// disassociate removes the association of the address with the instance or the network interface, if any.
+func (r *region) disassociate(address *ec2.Address) {
+	if instanceID := aws.StringValue(address.InstanceId); instanceID != "" {
+		if instance := r.instance(instanceID); instance != nil && aws.StringValue(instance.PublicIpAddress) == *address.PublicIp {
+			instance.PublicIpAddress = nil
+		}
+	}
+	if eni, ok := r.interfaces[aws.StringValue(address.NetworkInterfaceId)]; ok {
+		eni.Association = nil
+	}
+	address.AssociationId, address.InstanceId, address.NetworkInterfaceId = nil, nil, nil
+	address.NetworkInterfaceOwnerId, address.PrivateIpAddress = nil, nil
+}
+
+// natOfAddress returns the nat gateway which is not yet deleted, with which the address is associated.
+func (r *region) natOfAddress(allocationID string) *ec2.NatGateway {
+	for _, nat := range r.natGateways {
+		if *nat.State == ec2.NatGatewayStateDeleted {
+			continue
+		}
+		for _, address := range nat.NatGatewayAddresses {
+			if aws.StringValue(address.AllocationId) == allocationID {
+				return nat
+			}
+		}
+	}
+	return nil
+}
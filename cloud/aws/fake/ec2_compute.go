@@ -0,0 +1,604 @@
+package awsfake
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+var instanceStateCodes = map[string]int64{
+	ec2.InstanceStateNamePending:      0,
+	ec2.InstanceStateNameRunning:      16,
+	ec2.InstanceStateNameShuttingDown: 32,
+	ec2.InstanceStateNameTerminated:   48,
+	ec2.InstanceStateNameStopping:     64,
+	ec2.InstanceStateNameStopped:      80,
+}
+
+func instanceState(name string) *ec2.InstanceState {
+	return &ec2.InstanceState{Code: aws.Int64(instanceStateCodes[name]), Name: aws.String(name)}
+}
+
+// RunInstancesWithContext launches the instances in the subnet passed either in NetworkInterfaces or SubnetId,
+// the instances are running as soon as they are launched though the output reports them as pending.
+// Any ID of image with prefix ami- is accepted, as the public images are not modelled by the fake.
+// The volumes of the block device mappings are created along with the instance, the root volume is created from the image even if it is not mapped.
+// The requests made with a ClientToken used before are answered with the reservation launched by the first one, as aws does.
+// The spot instances are launched as long as their maximum price is not below the spot price of the zone, the capacity is never short.
+func (e *EC2) RunInstancesWithContext(ctx aws.Context, input *ec2.RunInstancesInput, _ ...request.Option) (*ec2.Reservation, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "RunInstances")
+	if err != nil {
+		return nil, err
+	}
+	// the key pair is checked ahead of the permissions as aws does, hence a dry run with the one not yet created fails.
+	if key := aws.StringValue(input.KeyName); key != "" {
+		if _, ok := reg.keyPairs[key]; !ok {
+			return nil, apiError("InvalidKeyPair.NotFound", "The key pair '%s' does not exist", key)
+		}
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	imageID := aws.StringValue(input.ImageId)
+	if !strings.HasPrefix(imageID, "ami-") {
+		return nil, apiError("InvalidAMIID.Malformed", "Invalid id: \"%s\" (expecting \"ami-...\")", imageID)
+	}
+	minCount, maxCount := aws.Int64Value(input.MinCount), aws.Int64Value(input.MaxCount)
+	if minCount < 1 || maxCount < minCount {
+		return nil, apiError("InvalidParameterValue", "Value (%d) for parameter minCount is invalid", minCount)
+	}
+	instanceType := aws.StringValue(input.InstanceType)
+	if instanceType == "" {
+		instanceType = ec2.InstanceTypeM1Small
+	}
+
+	token := aws.StringValue(input.ClientToken)
+	if len(token) > 64 {
+		return nil, apiError("InvalidParameterValue", "Value (%s) for parameter clientToken is invalid. Length exceeds maximum of 64.", token)
+	}
+
+	subnetID, groupIDs, publicIP := aws.StringValue(input.SubnetId), aws.StringValueSlice(input.SecurityGroupIds), false
+	if len(input.NetworkInterfaces) != 0 {
+		spec := input.NetworkInterfaces[0]
+		subnetID, publicIP = aws.StringValue(spec.SubnetId), aws.BoolValue(spec.AssociatePublicIpAddress)
+		if len(spec.Groups) != 0 {
+			groupIDs = aws.StringValueSlice(spec.Groups)
+		}
+	}
+	if subnetID == "" {
+		return nil, apiError("VPCIdNotSpecified", "No default VPC for this user")
+	}
+	subnet, ok := reg.subnets[subnetID]
+	if !ok {
+		return nil, apiError("InvalidSubnetID.NotFound", "The subnet ID '%s' does not exist", subnetID)
+	}
+	if launched := reg.reservationOfToken(token); launched != nil {
+		first := launched.Instances[0]
+		if *first.ImageId != imageID || *first.InstanceType != instanceType || *first.SubnetId != subnetID || int64(len(launched.Instances)) != maxCount {
+			return nil, apiError("IdempotentParameterMismatch", "Arguments on this idempotent request are inconsistent with arguments used in previous request(s).")
+		}
+		return awsutil.CopyOf(launched).(*ec2.Reservation), nil
+	}
+	publicIP = publicIP || aws.BoolValue(subnet.MapPublicIpOnLaunch)
+	if len(groupIDs) == 0 {
+		for _, id := range reg.groupIDs() {
+			if *reg.groups[id].VpcId == *subnet.VpcId && *reg.groups[id].GroupName == "default" {
+				groupIDs = []string{id}
+			}
+		}
+	}
+	groups := make([]*ec2.GroupIdentifier, 0)
+	for _, id := range groupIDs {
+		group, ok := reg.groups[id]
+		if !ok {
+			return nil, apiError("InvalidGroup.NotFound", "The security group '%s' does not exist", id)
+		}
+		if *group.VpcId != *subnet.VpcId {
+			return nil, apiError("InvalidParameter", "Security group %s and subnet %s belong to different networks.", id, subnetID)
+		}
+		groups = append(groups, &ec2.GroupIdentifier{GroupId: group.GroupId, GroupName: group.GroupName})
+	}
+
+	mappings, err := reg.blockDevices(imageID, input.BlockDeviceMappings)
+	if err != nil {
+		return nil, err
+	}
+	markSpot, err := reg.spotInstance(e.cloud, instanceType, *subnet.AvailabilityZone, input.InstanceMarketOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation := &ec2.Reservation{
+		ReservationId: aws.String(e.cloud.id("r")),
+		OwnerId:       aws.String(OwnerID),
+		Instances:     make([]*ec2.Instance, 0),
+	}
+	for index := int64(0); index < maxCount; index++ {
+		privateIP := reg.nextPrivateIP(subnet)
+		instance := &ec2.Instance{
+			InstanceId:       aws.String(e.cloud.id("i")),
+			ImageId:          aws.String(imageID),
+			InstanceType:     aws.String(instanceType),
+			AmiLaunchIndex:   aws.Int64(index),
+			LaunchTime:       aws.Time(e.cloud.now()),
+			Placement:        &ec2.Placement{AvailabilityZone: subnet.AvailabilityZone, Tenancy: aws.String(ec2.TenancyDefault)},
+			PrivateIpAddress: aws.String(privateIP),
+			PrivateDnsName:   aws.String(fmt.Sprintf("ip-%s.%s.compute.internal", strings.Replace(privateIP, ".", "-", -1), reg.name)),
+			PublicDnsName:    aws.String(""),
+			SecurityGroups:   groups,
+			State:            instanceState(ec2.InstanceStateNameRunning),
+			SubnetId:         subnet.SubnetId,
+			VpcId:            subnet.VpcId,
+			RootDeviceName:   mappings[0].DeviceName,
+			RootDeviceType:   aws.String(ec2.DeviceTypeEbs),
+			Architecture:     aws.String(ec2.ArchitectureValuesX8664),
+		}
+		reg.attachVolumes(e.cloud, instance, mappings)
+		markSpot(instance)
+		if token != "" {
+			instance.ClientToken = aws.String(token)
+		}
+		if key := aws.StringValue(input.KeyName); key != "" {
+			instance.KeyName = aws.String(key)
+		}
+		instance.Monitoring = &ec2.Monitoring{State: aws.String(ec2.MonitoringStateDisabled)}
+		if input.Monitoring != nil && aws.BoolValue(input.Monitoring.Enabled) {
+			instance.Monitoring.State = aws.String(ec2.MonitoringStateEnabled)
+		}
+		reg.userData[*instance.InstanceId] = aws.StringValue(input.UserData)
+		reg.protected[*instance.InstanceId] = aws.BoolValue(input.DisableApiTermination)
+		if publicIP {
+			reg.publicIPs[*instance.InstanceId] = true
+			reg.assignPublicIP(instance)
+		}
+		for _, spec := range input.TagSpecifications {
+			if aws.StringValue(spec.ResourceType) == ec2.ResourceTypeInstance {
+				for _, tag := range spec.Tags {
+					instance.Tags = setTag(instance.Tags, aws.StringValue(tag.Key), aws.StringValue(tag.Value))
+				}
+			}
+		}
+		reservation.Instances = append(reservation.Instances, instance)
+	}
+	reg.reservations = append(reg.reservations, reservation)
+
+	out := awsutil.CopyOf(reservation).(*ec2.Reservation)
+	for _, instance := range out.Instances {
+		instance.State = instanceState(ec2.InstanceStateNamePending)
+	}
+	return out, nil
+}
+
+// DescribeInstancesWithContext describes the instances selected, supports filters: instance-id, vpc-id, subnet-id, instance-state-name,
+// instance-type, image-id, availability-zone, key-name, private-ip-address, client-token and tags.
+func (e *EC2) DescribeInstancesWithContext(ctx aws.Context, input *ec2.DescribeInstancesInput, _ ...request.Option) (*ec2.DescribeInstancesOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DescribeInstances")
+	if err != nil {
+		return nil, err
+	}
+	out, err := reg.describeInstances(input)
+	if err != nil {
+		return out, err
+	}
+	start, end, next, err := paginate(len(out.Reservations), input.MaxResults, input.NextToken, 5, 1000, "InvalidParameterValue")
+	if err != nil {
+		return &ec2.DescribeInstancesOutput{}, err
+	}
+	out.Reservations, out.NextToken = out.Reservations[start:end], next
+	return out, nil
+}
+
+// DescribeInstancesPagesWithContext describes the instances selected page by page, fn is called with every page till it returns false or the last page is reached.
+// The pages of the fake are made of reservations, unlike aws which counts the instances.
+func (e *EC2) DescribeInstancesPagesWithContext(ctx aws.Context, input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool, opts ...request.Option) error {
+	in := *input
+	for {
+		out, err := e.DescribeInstancesWithContext(ctx, &in, opts...)
+		if err != nil {
+			return err
+		}
+		last := aws.StringValue(out.NextToken) == ""
+		if !fn(out, last) || last {
+			return nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+// reservationOfToken returns the reservation of the instances launched with the client token passed, nil if there is none or the token is empty.
+func (r *region) reservationOfToken(token string) *ec2.Reservation {
+	if token == "" {
+		return nil
+	}
+	for _, reservation := range r.reservations {
+		if aws.StringValue(reservation.Instances[0].ClientToken) == token {
+			return reservation
+		}
+	}
+	return nil
+}
+
+func (r *region) describeInstances(input *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+	for _, id := range aws.StringValueSlice(input.InstanceIds) {
+		if r.instance(id) == nil {
+			return &ec2.DescribeInstancesOutput{}, apiError("InvalidInstanceID.NotFound", "The instance ID '%s' does not exist", id)
+		}
+	}
+	out := &ec2.DescribeInstancesOutput{Reservations: make([]*ec2.Reservation, 0)}
+	for _, reservation := range r.reservations {
+		selected := make([]*ec2.Instance, 0)
+		for _, instance := range reservation.Instances {
+			if len(input.InstanceIds) != 0 && !contains(aws.StringValueSlice(input.InstanceIds), *instance.InstanceId) {
+				continue
+			}
+			ok, err := matches(input.Filters, attributes{
+				"instance-id":         {*instance.InstanceId},
+				"vpc-id":              {*instance.VpcId},
+				"subnet-id":           {*instance.SubnetId},
+				"instance-state-name": {*instance.State.Name},
+				"instance-type":       {*instance.InstanceType},
+				"image-id":            {*instance.ImageId},
+				"availability-zone":   {*instance.Placement.AvailabilityZone},
+				"key-name":            {aws.StringValue(instance.KeyName)},
+				"private-ip-address":  {*instance.PrivateIpAddress},
+				"client-token":        {aws.StringValue(instance.ClientToken)},
+			}, instance.Tags)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				selected = append(selected, awsutil.CopyOf(instance).(*ec2.Instance))
+			}
+		}
+		if len(selected) != 0 {
+			out.Reservations = append(out.Reservations, &ec2.Reservation{
+				ReservationId: reservation.ReservationId,
+				OwnerId:       reservation.OwnerId,
+				Instances:     selected,
+			})
+		}
+	}
+	return out, nil
+}
+
+// StartInstancesWithContext starts the stopped instances, starting a running instance is a no-op.
+func (e *EC2) StartInstancesWithContext(ctx aws.Context, input *ec2.StartInstancesInput, _ ...request.Option) (*ec2.StartInstancesOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "StartInstances")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	changes, err := reg.changeState(input.InstanceIds, ec2.InstanceStateNameRunning, func(instance *ec2.Instance) error {
+		if !live(instance) {
+			return apiError("IncorrectInstanceState", "The instance '%s' is not in a state from which it can be started.", *instance.InstanceId)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.StartInstancesOutput{StartingInstances: changes}, nil
+}
+
+// StopInstancesWithContext stops the running instances and releases its public IP, a new one is assigned when started again.
+func (e *EC2) StopInstancesWithContext(ctx aws.Context, input *ec2.StopInstancesInput, _ ...request.Option) (*ec2.StopInstancesOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "StopInstances")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	changes, err := reg.changeState(input.InstanceIds, ec2.InstanceStateNameStopped, func(instance *ec2.Instance) error {
+		if !live(instance) {
+			return apiError("IncorrectInstanceState", "This instance '%s' is not in a state from which it can be stopped.", *instance.InstanceId)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.StopInstancesOutput{StoppingInstances: changes}, nil
+}
+
+// TerminateInstancesWithContext terminates the instances, terminating an instance which is already terminated is a no-op.
+// The instances of which the termination is disabled by the attribute disableApiTermination fail with OperationNotPermitted.
+// The terminated instances remain visible in DescribeInstances as they are in aws, the addresses associated with them are disassociated
+// and the volumes attached to them are deleted or detached as per their DeleteOnTermination. The spot requests of the instances are closed,
+// the persistent ones which are not cancelled are opened again.
+func (e *EC2) TerminateInstancesWithContext(ctx aws.Context, input *ec2.TerminateInstancesInput, _ ...request.Option) (*ec2.TerminateInstancesOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "TerminateInstances")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	changes, err := reg.changeState(input.InstanceIds, ec2.InstanceStateNameTerminated, func(instance *ec2.Instance) error {
+		if reg.protected[*instance.InstanceId] {
+			return apiError("OperationNotPermitted", "The instance '%s' may not be terminated. Modify its 'disableApiTermination' instance attribute and try again.", *instance.InstanceId)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.TerminateInstancesOutput{TerminatingInstances: changes}, nil
+}
+
+// changeState moves the instances to the state passed after validating all of them using allowed.
+func (r *region) changeState(ids []*string, state string, allowed func(*ec2.Instance) error) ([]*ec2.InstanceStateChange, error) {
+	instances := make([]*ec2.Instance, 0)
+	for _, id := range aws.StringValueSlice(ids) {
+		instance := r.instance(id)
+		if instance == nil {
+			return nil, apiError("InvalidInstanceID.NotFound", "The instance ID '%s' does not exist", id)
+		}
+		if err := allowed(instance); err != nil {
+			return nil, err
+		}
+		instances = append(instances, instance)
+	}
+	changes := make([]*ec2.InstanceStateChange, 0)
+	for _, instance := range instances {
+		previous := instance.State
+		instance.State = instanceState(state)
+		if state == ec2.InstanceStateNameTerminated {
+			r.releaseVolumes(instance)
+			r.terminateSpot(instance)
+			for _, address := range r.addresses {
+				if aws.StringValue(address.InstanceId) == *instance.InstanceId {
+					r.disassociate(address)
+				}
+			}
+		}
+		switch {
+		case state != ec2.InstanceStateNameRunning:
+			instance.PublicIpAddress, instance.PublicDnsName = nil, aws.String("")
+		case *previous.Name != ec2.InstanceStateNameRunning && r.publicIPs[*instance.InstanceId]:
+			r.assignPublicIP(instance)
+		}
+		changes = append(changes, &ec2.InstanceStateChange{
+			InstanceId:    instance.InstanceId,
+			PreviousState: previous,
+			CurrentState:  instanceState(state),
+		})
+	}
+	return changes, nil
+}
+
+// WaitUntilInstanceRunningWithContext returns once all the instances selected are running.
+func (e *EC2) WaitUntilInstanceRunningWithContext(ctx aws.Context, input *ec2.DescribeInstancesInput, _ ...request.WaiterOption) error {
+	return e.waitInstances(ctx, "WaitUntilInstanceRunning", input, ec2.InstanceStateNameRunning)
+}
+
+// WaitUntilInstanceStoppedWithContext returns once all the instances selected are stopped.
+func (e *EC2) WaitUntilInstanceStoppedWithContext(ctx aws.Context, input *ec2.DescribeInstancesInput, _ ...request.WaiterOption) error {
+	return e.waitInstances(ctx, "WaitUntilInstanceStopped", input, ec2.InstanceStateNameStopped)
+}
+
+// WaitUntilInstanceTerminatedWithContext returns once all the instances selected are terminated.
+func (e *EC2) WaitUntilInstanceTerminatedWithContext(ctx aws.Context, input *ec2.DescribeInstancesInput, _ ...request.WaiterOption) error {
+	return e.waitInstances(ctx, "WaitUntilInstanceTerminated", input, ec2.InstanceStateNameTerminated)
+}
+
+// waitInstances fails with ResourceNotReady, the way waiter of sdk does after exhausting its attempts, if any of the instance is not in the state
+// as the state of instances in the fake changes only on request.
+func (e *EC2) waitInstances(ctx aws.Context, operation string, input *ec2.DescribeInstancesInput, state string) error {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, operation)
+	if err != nil {
+		return err
+	}
+
+	out, err := reg.describeInstances(input)
+	if err != nil {
+		return err
+	}
+	for _, reservation := range out.Reservations {
+		for _, instance := range reservation.Instances {
+			if *instance.State.Name != state {
+				return awserr.New(request.WaiterResourceNotReadyErrorCode, "exceeded wait attempts", nil)
+			}
+		}
+	}
+	return nil
+}
+
+// CreateImageWithContext creates the image of the instance along with the snapshot of its root volume, the name of the image is unique in a region.
+func (e *EC2) CreateImageWithContext(ctx aws.Context, input *ec2.CreateImageInput, _ ...request.Option) (*ec2.CreateImageOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "CreateImage")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	instance := reg.instance(aws.StringValue(input.InstanceId))
+	if instance == nil {
+		return nil, apiError("InvalidInstanceID.NotFound", "The instance ID '%s' does not exist", aws.StringValue(input.InstanceId))
+	}
+	if !live(instance) {
+		return nil, apiError("IncorrectInstanceState", "The instance '%s' is not in a state from which it can be imaged.", *instance.InstanceId)
+	}
+	name := aws.StringValue(input.Name)
+	if name == "" {
+		return nil, apiError("MissingParameter", "The request must contain the parameter name")
+	}
+	for _, image := range reg.images {
+		if *image.Name == name {
+			return nil, apiError("InvalidAMIName.Duplicate", "AMI name %s is already in use by AMI %s", name, *image.ImageId)
+		}
+	}
+
+	// the snapshot is captured out of the root volume of the instance.
+	rootVolume, rootSize := aws.String(e.cloud.id("vol")), int64(rootVolumeSize)
+	for _, mapping := range instance.BlockDeviceMappings {
+		if *mapping.DeviceName == *instance.RootDeviceName {
+			if volume, ok := reg.volumes[*mapping.Ebs.VolumeId]; ok {
+				rootVolume, rootSize = volume.VolumeId, *volume.Size
+			}
+		}
+	}
+	snapshot := &ec2.Snapshot{
+		SnapshotId:  aws.String(e.cloud.id("snap")),
+		VolumeId:    rootVolume,
+		VolumeSize:  aws.Int64(rootSize),
+		OwnerId:     aws.String(OwnerID),
+		State:       aws.String(ec2.SnapshotStateCompleted),
+		Progress:    aws.String("100%"),
+		StartTime:   aws.Time(e.cloud.now()),
+		Encrypted:   aws.Bool(false),
+		Description: aws.String(fmt.Sprintf("Created by CreateImage(%s)", *instance.InstanceId)),
+	}
+	reg.snapshots[*snapshot.SnapshotId] = snapshot
+	image := &ec2.Image{
+		ImageId:            aws.String(e.cloud.id("ami")),
+		Name:               aws.String(name),
+		Description:        input.Description,
+		Architecture:       instance.Architecture,
+		CreationDate:       aws.String(e.cloud.now().Format("2006-01-02T15:04:05.000Z")),
+		ImageType:          aws.String(ec2.ImageTypeValuesMachine),
+		OwnerId:            aws.String(OwnerID),
+		Public:             aws.Bool(false),
+		RootDeviceName:     instance.RootDeviceName,
+		RootDeviceType:     aws.String(ec2.DeviceTypeEbs),
+		State:              aws.String(ec2.ImageStateAvailable),
+		VirtualizationType: aws.String(ec2.VirtualizationTypeHvm),
+		BlockDeviceMappings: []*ec2.BlockDeviceMapping{{
+			DeviceName: instance.RootDeviceName,
+			Ebs: &ec2.EbsBlockDevice{
+				DeleteOnTermination: aws.Bool(true),
+				Encrypted:           aws.Bool(false),
+				SnapshotId:          snapshot.SnapshotId,
+				VolumeSize:          snapshot.VolumeSize,
+				VolumeType:          aws.String(ec2.VolumeTypeGp2),
+			},
+		}},
+	}
+	reg.images[*image.ImageId] = image
+	return &ec2.CreateImageOutput{ImageId: aws.String(*image.ImageId)}, nil
+}
+
+// DescribeImagesWithContext describes the images created in the fake, supports filters: image-id, name, state, is-public, owner-id and tags.
+func (e *EC2) DescribeImagesWithContext(ctx aws.Context, input *ec2.DescribeImagesInput, _ ...request.Option) (*ec2.DescribeImagesOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DescribeImages")
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := reg.selectIDs(input.ImageIds, reg.imageIDs(), "InvalidAMIID.NotFound", "image")
+	if err != nil {
+		return &ec2.DescribeImagesOutput{}, err
+	}
+	out := &ec2.DescribeImagesOutput{Images: make([]*ec2.Image, 0)}
+	for _, id := range ids {
+		image := reg.images[id]
+		ok, err := matches(input.Filters, attributes{
+			"image-id":  {*image.ImageId},
+			"name":      {*image.Name},
+			"state":     {*image.State},
+			"is-public": {boolString(*image.Public)},
+			"owner-id":  {*image.OwnerId},
+		}, image.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out.Images = append(out.Images, awsutil.CopyOf(image).(*ec2.Image))
+		}
+	}
+	return out, nil
+}
+
+// DeregisterImageWithContext deregisters the image, the snapshots of the image are retained.
+func (e *EC2) DeregisterImageWithContext(ctx aws.Context, input *ec2.DeregisterImageInput, _ ...request.Option) (*ec2.DeregisterImageOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DeregisterImage")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.ImageId)
+	if _, ok := reg.images[id]; !ok {
+		return &ec2.DeregisterImageOutput{}, apiError("InvalidAMIID.NotFound", "The image id '[%s]' does not exist", id)
+	}
+	delete(reg.images, id)
+	return &ec2.DeregisterImageOutput{}, nil
+}
+
+// DeleteSnapshotWithContext deletes the snapshot, it fails with InvalidSnapshot.InUse while a registered image is using the snapshot.
+func (e *EC2) DeleteSnapshotWithContext(ctx aws.Context, input *ec2.DeleteSnapshotInput, _ ...request.Option) (*ec2.DeleteSnapshotOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DeleteSnapshot")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.SnapshotId)
+	if _, ok := reg.snapshots[id]; !ok {
+		return &ec2.DeleteSnapshotOutput{}, apiError("InvalidSnapshot.NotFound", "The snapshot '%s' does not exist.", id)
+	}
+	for _, image := range reg.images {
+		for _, mapping := range image.BlockDeviceMappings {
+			if mapping.Ebs != nil && aws.StringValue(mapping.Ebs.SnapshotId) == id {
+				return &ec2.DeleteSnapshotOutput{}, apiError("InvalidSnapshot.InUse", "The snapshot %s is currently in use by %s", id, *image.ImageId)
+			}
+		}
+	}
+	delete(reg.snapshots, id)
+	return &ec2.DeleteSnapshotOutput{}, nil
+}
+
+// nextPrivateIP allocates the next address of the subnet, the first four addresses are reserved as in aws.
+func (r *region) nextPrivateIP(subnet *ec2.Subnet) string {
+	_, network, _ := net.ParseCIDR(*subnet.CidrBlock)
+	r.ipSeq[*subnet.SubnetId]++
+	address := make(net.IP, 4)
+	binary.BigEndian.PutUint32(address, binary.BigEndian.Uint32(network.IP.To4())+uint32(3+r.ipSeq[*subnet.SubnetId]))
+	return address.String()
+}
+
+func (r *region) assignPublicIP(instance *ec2.Instance) {
+	r.publicIPSeq++
+	address := fmt.Sprintf("54.%d.%d.%d", (r.publicIPSeq>>16)&0xff, (r.publicIPSeq>>8)&0xff, r.publicIPSeq&0xff)
+	instance.PublicIpAddress = aws.String(address)
+	instance.PublicDnsName = aws.String(fmt.Sprintf("ec2-%s.compute-1.amazonaws.com", strings.Replace(address, ".", "-", -1)))
+}
@@ -0,0 +1,213 @@
+package awsfake
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// RebootInstancesWithContext reboots the running instances, the instances stay running as the reboot completes instantly in the fake.
+func (e *EC2) RebootInstancesWithContext(ctx aws.Context, input *ec2.RebootInstancesInput, _ ...request.Option) (*ec2.RebootInstancesOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "RebootInstances")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	if _, err := reg.instancesIn(input.InstanceIds, func(instance *ec2.Instance) error {
+		if *instance.State.Name != ec2.InstanceStateNameRunning {
+			return apiError("IncorrectInstanceState", "The instance '%s' is not in a state from which it can be rebooted.", *instance.InstanceId)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &ec2.RebootInstancesOutput{}, nil
+}
+
+// ModifyInstanceAttributeWithContext modifies one attribute of the instance per request as aws does, supports: InstanceType, UserData,
+// DisableApiTermination and Groups. The instance type and the user data can be changed only while the instance is stopped.
+func (e *EC2) ModifyInstanceAttributeWithContext(ctx aws.Context, input *ec2.ModifyInstanceAttributeInput, _ ...request.Option) (*ec2.ModifyInstanceAttributeOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "ModifyInstanceAttribute")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.InstanceId)
+	instance := reg.instance(id)
+	if instance == nil {
+		return nil, apiError("InvalidInstanceID.NotFound", "The instance ID '%s' does not exist", id)
+	}
+	if !live(instance) {
+		return nil, apiError("IncorrectInstanceState", "The instance '%s' is not in a state from which it can be modified.", id)
+	}
+	modified := 0
+	for _, set := range []bool{input.InstanceType != nil, input.UserData != nil, input.DisableApiTermination != nil, len(input.Groups) != 0} {
+		if set {
+			modified++
+		}
+	}
+	if modified != 1 {
+		return nil, apiError("InvalidParameterCombination", "Exactly one attribute of the instance has to be modified per request")
+	}
+	stopped := *instance.State.Name == ec2.InstanceStateNameStopped
+
+	switch {
+	case input.InstanceType != nil:
+		instanceType := aws.StringValue(input.InstanceType.Value)
+		if !strings.Contains(instanceType, ".") {
+			return nil, apiError("InvalidInstanceAttributeValue", "Value (%s) for parameter instanceType is invalid.", instanceType)
+		}
+		if !stopped {
+			return nil, apiError("IncorrectInstanceState", "The instance '%s' is not in the 'stopped' state.", id)
+		}
+		instance.InstanceType = aws.String(instanceType)
+	case input.UserData != nil:
+		if !stopped {
+			return nil, apiError("IncorrectInstanceState", "The instance '%s' is not in the 'stopped' state.", id)
+		}
+		reg.userData[id] = base64.StdEncoding.EncodeToString(input.UserData.Value)
+	case input.DisableApiTermination != nil:
+		reg.protected[id] = aws.BoolValue(input.DisableApiTermination.Value)
+	default:
+		groups := make([]*ec2.GroupIdentifier, 0)
+		for _, groupID := range aws.StringValueSlice(input.Groups) {
+			group, ok := reg.groups[groupID]
+			if !ok {
+				return nil, apiError("InvalidGroup.NotFound", "The security group '%s' does not exist", groupID)
+			}
+			if *group.VpcId != *instance.VpcId {
+				return nil, apiError("InvalidGroup.NotFound", "The security group '%s' does not exist in VPC '%s'", groupID, *instance.VpcId)
+			}
+			groups = append(groups, &ec2.GroupIdentifier{GroupId: group.GroupId, GroupName: group.GroupName})
+		}
+		instance.SecurityGroups = groups
+	}
+	return &ec2.ModifyInstanceAttributeOutput{}, nil
+}
+
+// DescribeInstanceAttributeWithContext describes an attribute of the instance, supports: instanceType, userData, disableApiTermination and groupSet.
+// The user data is reported base64 encoded as aws does, it is empty if the instance is launched without it.
+func (e *EC2) DescribeInstanceAttributeWithContext(ctx aws.Context, input *ec2.DescribeInstanceAttributeInput, _ ...request.Option) (*ec2.DescribeInstanceAttributeOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DescribeInstanceAttribute")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.InstanceId)
+	instance := reg.instance(id)
+	if instance == nil {
+		return nil, apiError("InvalidInstanceID.NotFound", "The instance ID '%s' does not exist", id)
+	}
+	out := &ec2.DescribeInstanceAttributeOutput{InstanceId: aws.String(id)}
+	switch attribute := aws.StringValue(input.Attribute); attribute {
+	case ec2.InstanceAttributeNameInstanceType:
+		out.InstanceType = &ec2.AttributeValue{Value: aws.String(*instance.InstanceType)}
+	case ec2.InstanceAttributeNameUserData:
+		out.UserData = &ec2.AttributeValue{}
+		if data := reg.userData[id]; data != "" {
+			out.UserData.Value = aws.String(data)
+		}
+	case ec2.InstanceAttributeNameDisableApiTermination:
+		out.DisableApiTermination = &ec2.AttributeBooleanValue{Value: aws.Bool(reg.protected[id])}
+	case ec2.InstanceAttributeNameGroupSet:
+		out.Groups = awsutil.CopyOf(instance).(*ec2.Instance).SecurityGroups
+	default:
+		return nil, apiError("InvalidParameterValue", "Value (%s) for parameter attribute is invalid. Unknown attribute.", attribute)
+	}
+	return out, nil
+}
+
+// MonitorInstancesWithContext enables the detailed monitoring of the instances, it is enabled instantly though the output reports it as pending.
+func (e *EC2) MonitorInstancesWithContext(ctx aws.Context, input *ec2.MonitorInstancesInput, _ ...request.Option) (*ec2.MonitorInstancesOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "MonitorInstances")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	monitorings, err := reg.monitor(input.InstanceIds, ec2.MonitoringStateEnabled, ec2.MonitoringStatePending)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.MonitorInstancesOutput{InstanceMonitorings: monitorings}, nil
+}
+
+// UnmonitorInstancesWithContext disables the detailed monitoring of the instances, it is disabled instantly though the output reports it as disabling.
+func (e *EC2) UnmonitorInstancesWithContext(ctx aws.Context, input *ec2.UnmonitorInstancesInput, _ ...request.Option) (*ec2.UnmonitorInstancesOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "UnmonitorInstances")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	monitorings, err := reg.monitor(input.InstanceIds, ec2.MonitoringStateDisabled, ec2.MonitoringStateDisabling)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.UnmonitorInstancesOutput{InstanceMonitorings: monitorings}, nil
+}
+
+// monitor sets the monitoring of the instances to the state passed, reported reports the state the way aws does while the change is in progress.
+func (r *region) monitor(ids []*string, state, reported string) ([]*ec2.InstanceMonitoring, error) {
+	instances, err := r.instancesIn(ids, func(instance *ec2.Instance) error {
+		if !live(instance) {
+			return apiError("IncorrectInstanceState", "The instance '%s' is not in a state from which its monitoring can be changed.", *instance.InstanceId)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	monitorings := make([]*ec2.InstanceMonitoring, 0)
+	for _, instance := range instances {
+		instance.Monitoring = &ec2.Monitoring{State: aws.String(state)}
+		monitorings = append(monitorings, &ec2.InstanceMonitoring{InstanceId: aws.String(*instance.InstanceId), Monitoring: &ec2.Monitoring{State: aws.String(reported)}})
+	}
+	return monitorings, nil
+}
+
+// instancesIn returns the instances passed after validating all of them using allowed.
+func (r *region) instancesIn(ids []*string, allowed func(*ec2.Instance) error) ([]*ec2.Instance, error) {
+	if len(ids) == 0 {
+		return nil, apiError("MissingParameter", "The request must contain the parameter InstanceId")
+	}
+	instances := make([]*ec2.Instance, 0)
+	for _, id := range aws.StringValueSlice(ids) {
+		instance := r.instance(id)
+		if instance == nil {
+			return nil, apiError("InvalidInstanceID.NotFound", "The instance ID '%s' does not exist", id)
+		}
+		if err := allowed(instance); err != nil {
+			return nil, err
+		}
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
@@ -0,0 +1,137 @@
+package awsfake
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/nikhilsbhat/neuron-cloudy/sshkey"
+)
+
+// CreateKeyPairWithContext generates an RSA key pair, the private key is returned only in the response as aws does not retain it.
+// The fingerprint is the SHA-1 digest of the private key, as aws reports it for the key pairs it creates.
+func (e *EC2) CreateKeyPairWithContext(ctx aws.Context, input *ec2.CreateKeyPairInput, _ ...request.Option) (*ec2.CreateKeyPairOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "CreateKeyPair")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.KeyName)
+	if err := reg.validKeyName(name); err != nil {
+		return nil, err
+	}
+	pair, err := sshkey.Generate(sshkey.DefaultBits, "")
+	if err != nil {
+		return nil, err
+	}
+	reg.keyPairs[name] = &ec2.KeyPairInfo{KeyName: aws.String(name), KeyFingerprint: aws.String(pair.Fingerprint)}
+	return &ec2.CreateKeyPairOutput{KeyName: aws.String(name), KeyFingerprint: aws.String(pair.Fingerprint), KeyMaterial: aws.String(pair.PrivateKey)}, nil
+}
+
+// ImportKeyPairWithContext imports the RSA public key in the OpenSSH format, it fails with InvalidKey.Format for the rest.
+// The fingerprint is the MD5 digest of the public key, as aws reports it for the key pairs imported.
+func (e *EC2) ImportKeyPairWithContext(ctx aws.Context, input *ec2.ImportKeyPairInput, _ ...request.Option) (*ec2.ImportKeyPairOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "ImportKeyPair")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.KeyName)
+	if err := reg.validKeyName(name); err != nil {
+		return nil, err
+	}
+	key, err := sshkey.Parse(string(input.PublicKeyMaterial))
+	if err != nil {
+		return nil, apiError("InvalidKey.Format", "Key is not in valid OpenSSH public key format")
+	}
+	fingerprint, err := sshkey.Fingerprint(key)
+	if err != nil {
+		return nil, err
+	}
+	reg.keyPairs[name] = &ec2.KeyPairInfo{KeyName: aws.String(name), KeyFingerprint: aws.String(fingerprint)}
+	return &ec2.ImportKeyPairOutput{KeyName: aws.String(name), KeyFingerprint: aws.String(fingerprint)}, nil
+}
+
+// DescribeKeyPairsWithContext describes the key pairs selected, supports filters: key-name and fingerprint.
+func (e *EC2) DescribeKeyPairsWithContext(ctx aws.Context, input *ec2.DescribeKeyPairsInput, _ ...request.Option) (*ec2.DescribeKeyPairsOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DescribeKeyPairs")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	names := reg.keyPairNames()
+	if len(input.KeyNames) != 0 {
+		names = aws.StringValueSlice(input.KeyNames)
+		for _, name := range names {
+			if _, ok := reg.keyPairs[name]; !ok {
+				return nil, apiError("InvalidKeyPair.NotFound", "The key pair '%s' does not exist", name)
+			}
+		}
+	}
+	out := &ec2.DescribeKeyPairsOutput{KeyPairs: make([]*ec2.KeyPairInfo, 0)}
+	for _, name := range names {
+		pair := reg.keyPairs[name]
+		attrs := attributes{
+			"key-name":    {*pair.KeyName},
+			"fingerprint": {*pair.KeyFingerprint},
+		}
+		ok, err := matches(input.Filters, attrs, nil)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out.KeyPairs = append(out.KeyPairs, awsutil.CopyOf(pair).(*ec2.KeyPairInfo))
+		}
+	}
+	return out, nil
+}
+
+// DeleteKeyPairWithContext deletes the key pair, as in aws deleting the key pair which does not exist succeeds
+// and the instances launched with it are left as is.
+func (e *EC2) DeleteKeyPairWithContext(ctx aws.Context, input *ec2.DeleteKeyPairInput, _ ...request.Option) (*ec2.DeleteKeyPairOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DeleteKeyPair")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.KeyName)
+	if name == "" {
+		return nil, apiError("MissingParameter", "The request must contain the parameter KeyName")
+	}
+	delete(reg.keyPairs, name)
+	return &ec2.DeleteKeyPairOutput{}, nil
+}
+
+// validKeyName validates the name of the key pair to be created/imported, it has to be unique within the region.
+func (r *region) validKeyName(name string) error {
+	if name == "" {
+		return apiError("MissingParameter", "The request must contain the parameter KeyName")
+	}
+	if len(name) > 255 {
+		return apiError("InvalidParameterValue", "Value (%s) for parameter keyName is invalid. Length exceeds maximum of 255.", name)
+	}
+	if _, ok := r.keyPairs[name]; ok {
+		return apiError("InvalidKeyPair.Duplicate", "The keypair '%s' already exists.", name)
+	}
+	return nil
+}
@@ -0,0 +1,1209 @@
+package awsfake
+
+import (
+	"net"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+)
+
+// EC2 is the in-memory client of EC2 for a region, it is obtained from Cloud.EC2.
+type EC2 struct {
+	ec2iface.EC2API
+	cloud  *Cloud
+	region string
+}
+
+func (e *EC2) enter(ctx aws.Context, operation string) (*region, error) {
+	return e.cloud.enter(ctx, operation, e.region)
+}
+
+// CreateVpcWithContext creates the vpc along with its main route table and the default security group.
+func (e *EC2) CreateVpcWithContext(ctx aws.Context, input *ec2.CreateVpcInput, _ ...request.Option) (*ec2.CreateVpcOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "CreateVpc")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	cidr := aws.StringValue(input.CidrBlock)
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, apiError("InvalidParameterValue", "Value (%s) for parameter cidrBlock is invalid. This is not a valid CIDR block.", cidr)
+	}
+	if size, _ := network.Mask.Size(); size < 16 || size > 28 {
+		return nil, apiError("InvalidVpc.Range", "The CIDR '%s' is invalid.", cidr)
+	}
+	tenancy := aws.StringValue(input.InstanceTenancy)
+	if tenancy == "" {
+		tenancy = ec2.TenancyDefault
+	}
+
+	vpc := &ec2.Vpc{
+		VpcId:           aws.String(e.cloud.id("vpc")),
+		CidrBlock:       aws.String(network.String()),
+		DhcpOptionsId:   aws.String(e.cloud.id("dopt")),
+		InstanceTenancy: aws.String(tenancy),
+		IsDefault:       aws.Bool(false),
+		OwnerId:         aws.String(OwnerID),
+		State:           aws.String(ec2.VpcStateAvailable),
+		CidrBlockAssociationSet: []*ec2.VpcCidrBlockAssociation{{
+			AssociationId:  aws.String(e.cloud.id("vpc-cidr-assoc")),
+			CidrBlock:      aws.String(network.String()),
+			CidrBlockState: &ec2.VpcCidrBlockState{State: aws.String(ec2.VpcCidrBlockStateCodeAssociated)},
+		}},
+	}
+	reg.vpcs[*vpc.VpcId] = vpc
+
+	main := reg.newRouteTable(e.cloud, vpc)
+	main.Associations = []*ec2.RouteTableAssociation{{
+		Main:                    aws.Bool(true),
+		RouteTableAssociationId: aws.String(e.cloud.id("rtbassoc")),
+		RouteTableId:            main.RouteTableId,
+	}}
+	reg.newSecurityGroup(e.cloud, vpc, "default", "default VPC security group")
+
+	out := awsutil.CopyOf(vpc).(*ec2.Vpc)
+	out.State = aws.String(ec2.VpcStatePending)
+	return &ec2.CreateVpcOutput{Vpc: out}, nil
+}
+
+// DescribeVpcsWithContext describes the vpcs selected, supports filters: vpc-id, cidr, cidr-block, state, is-default,
+// cidr-block-association.cidr-block, cidr-block-association.association-id, cidr-block-association.state and tags.
+func (e *EC2) DescribeVpcsWithContext(ctx aws.Context, input *ec2.DescribeVpcsInput, _ ...request.Option) (*ec2.DescribeVpcsOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DescribeVpcs")
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := reg.selectIDs(input.VpcIds, reg.vpcIDs(), "InvalidVpcID.NotFound", "vpc")
+	if err != nil {
+		return &ec2.DescribeVpcsOutput{}, err
+	}
+	out := &ec2.DescribeVpcsOutput{Vpcs: make([]*ec2.Vpc, 0)}
+	for _, id := range ids {
+		vpc := reg.vpcs[id]
+		attrs := attributes{
+			"vpc-id":                                {*vpc.VpcId},
+			"cidr":                                  {*vpc.CidrBlock},
+			"cidr-block":                            {*vpc.CidrBlock},
+			"state":                                 {*vpc.State},
+			"is-default":                            {boolString(*vpc.IsDefault)},
+			"owner-id":                              {*vpc.OwnerId},
+			"cidr-block-association.cidr-block":     {},
+			"cidr-block-association.association-id": {},
+			"cidr-block-association.state":          {},
+		}
+		for _, association := range vpc.CidrBlockAssociationSet {
+			attrs["cidr-block-association.cidr-block"] = append(attrs["cidr-block-association.cidr-block"], *association.CidrBlock)
+			attrs["cidr-block-association.association-id"] = append(attrs["cidr-block-association.association-id"], *association.AssociationId)
+			attrs["cidr-block-association.state"] = append(attrs["cidr-block-association.state"], *association.CidrBlockState.State)
+		}
+		ok, err := matches(input.Filters, attrs, vpc.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out.Vpcs = append(out.Vpcs, awsutil.CopyOf(vpc).(*ec2.Vpc))
+		}
+	}
+	start, end, next, err := paginate(len(out.Vpcs), input.MaxResults, input.NextToken, 5, 1000, "InvalidParameterValue")
+	if err != nil {
+		return &ec2.DescribeVpcsOutput{}, err
+	}
+	out.Vpcs, out.NextToken = out.Vpcs[start:end], next
+	return out, nil
+}
+
+// DescribeVpcsPagesWithContext describes the vpcs selected page by page, fn is called with every page till it returns false or the last page is reached.
+func (e *EC2) DescribeVpcsPagesWithContext(ctx aws.Context, input *ec2.DescribeVpcsInput, fn func(*ec2.DescribeVpcsOutput, bool) bool, opts ...request.Option) error {
+	in := *input
+	for {
+		out, err := e.DescribeVpcsWithContext(ctx, &in, opts...)
+		if err != nil {
+			return err
+		}
+		last := aws.StringValue(out.NextToken) == ""
+		if !fn(out, last) || last {
+			return nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+// DeleteVpcWithContext deletes the vpc, it fails with DependencyViolation while subnets, gateways,
+// security groups (other than default) or route tables (other than main) still exists in it.
+func (e *EC2) DeleteVpcWithContext(ctx aws.Context, input *ec2.DeleteVpcInput, _ ...request.Option) (*ec2.DeleteVpcOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DeleteVpc")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.VpcId)
+	if _, ok := reg.vpcs[id]; !ok {
+		return &ec2.DeleteVpcOutput{}, apiError("InvalidVpcID.NotFound", "The vpc ID '%s' does not exist", id)
+	}
+	if reg.vpcHasDependencies(id) {
+		return &ec2.DeleteVpcOutput{}, apiError("DependencyViolation", "The vpc '%s' has dependencies and cannot be deleted.", id)
+	}
+	for rtID, table := range reg.routeTables {
+		if *table.VpcId == id {
+			delete(reg.routeTables, rtID)
+		}
+	}
+	for sgID, group := range reg.groups {
+		if *group.VpcId == id {
+			delete(reg.groups, sgID)
+		}
+	}
+	delete(reg.vpcs, id)
+	return &ec2.DeleteVpcOutput{}, nil
+}
+
+// AssociateVpcCidrBlockWithContext associates the secondary CIDR block with the vpc, the block should not overlap the ones associated already
+// and a vpc can have at most 5 blocks. The local route of the block is added to every route table of the vpc.
+func (e *EC2) AssociateVpcCidrBlockWithContext(ctx aws.Context, input *ec2.AssociateVpcCidrBlockInput, _ ...request.Option) (*ec2.AssociateVpcCidrBlockOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "AssociateVpcCidrBlock")
+	if err != nil {
+		return nil, err
+	}
+
+	vpc, ok := reg.vpcs[aws.StringValue(input.VpcId)]
+	if !ok {
+		return nil, apiError("InvalidVpcID.NotFound", "The vpc ID '%s' does not exist", aws.StringValue(input.VpcId))
+	}
+	cidr := aws.StringValue(input.CidrBlock)
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, apiError("InvalidParameterValue", "Value (%s) for parameter cidrBlock is invalid. This is not a valid CIDR block.", cidr)
+	}
+	if size, _ := network.Mask.Size(); size < 16 || size > 28 {
+		return nil, apiError("InvalidVpc.Range", "The CIDR '%s' is invalid.", cidr)
+	}
+	if len(vpc.CidrBlockAssociationSet) >= 5 {
+		return nil, apiError("CidrLimitExceeded", "This network '%s' has met its maximum number of allowed CIDRs: 5", *vpc.VpcId)
+	}
+	for _, association := range vpc.CidrBlockAssociationSet {
+		_, existing, _ := net.ParseCIDR(*association.CidrBlock)
+		if existing.Contains(network.IP) || network.Contains(existing.IP) {
+			return nil, apiError("InvalidVpc.Range", "The CIDR '%s' conflicts with the CIDR '%s' of the network '%s'", cidr, *association.CidrBlock, *vpc.VpcId)
+		}
+	}
+
+	association := &ec2.VpcCidrBlockAssociation{
+		AssociationId:  aws.String(e.cloud.id("vpc-cidr-assoc")),
+		CidrBlock:      aws.String(network.String()),
+		CidrBlockState: &ec2.VpcCidrBlockState{State: aws.String(ec2.VpcCidrBlockStateCodeAssociated)},
+	}
+	vpc.CidrBlockAssociationSet = append(vpc.CidrBlockAssociationSet, association)
+	for _, table := range reg.routeTables {
+		if *table.VpcId == *vpc.VpcId {
+			table.Routes = append(table.Routes, localRoute(association.CidrBlock))
+		}
+	}
+
+	out := awsutil.CopyOf(association).(*ec2.VpcCidrBlockAssociation)
+	out.CidrBlockState.State = aws.String(ec2.VpcCidrBlockStateCodeAssociating)
+	return &ec2.AssociateVpcCidrBlockOutput{CidrBlockAssociation: out, VpcId: vpc.VpcId}, nil
+}
+
+// DisassociateVpcCidrBlockWithContext removes the secondary CIDR block from the vpc along with its local routes,
+// the primary block cannot be removed and it fails with DependencyViolation while subnets are using the block.
+func (e *EC2) DisassociateVpcCidrBlockWithContext(ctx aws.Context, input *ec2.DisassociateVpcCidrBlockInput, _ ...request.Option) (*ec2.DisassociateVpcCidrBlockOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DisassociateVpcCidrBlock")
+	if err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.AssociationId)
+	for _, vpcID := range reg.vpcIDs() {
+		vpc := reg.vpcs[vpcID]
+		for index, association := range vpc.CidrBlockAssociationSet {
+			if *association.AssociationId != id {
+				continue
+			}
+			if *association.CidrBlock == *vpc.CidrBlock {
+				return nil, apiError("OperationNotPermitted", "The vpc CIDR block with association ID %s may not be disassociated. It is the primary IPv4 CIDR block of the VPC", id)
+			}
+			_, block, _ := net.ParseCIDR(*association.CidrBlock)
+			for _, subnet := range reg.subnets {
+				if *subnet.VpcId != vpcID {
+					continue
+				}
+				if ip, _, _ := net.ParseCIDR(*subnet.CidrBlock); block.Contains(ip) {
+					return nil, apiError("DependencyViolation", "The vpc CIDR block '%s' has dependencies and cannot be disassociated.", *association.CidrBlock)
+				}
+			}
+			vpc.CidrBlockAssociationSet = append(vpc.CidrBlockAssociationSet[:index], vpc.CidrBlockAssociationSet[index+1:]...)
+			for _, table := range reg.routeTables {
+				if *table.VpcId != vpcID {
+					continue
+				}
+				routes := make([]*ec2.Route, 0, len(table.Routes))
+				for _, route := range table.Routes {
+					if aws.StringValue(route.GatewayId) != "local" || aws.StringValue(route.DestinationCidrBlock) != *association.CidrBlock {
+						routes = append(routes, route)
+					}
+				}
+				table.Routes = routes
+			}
+
+			out := awsutil.CopyOf(association).(*ec2.VpcCidrBlockAssociation)
+			out.CidrBlockState.State = aws.String(ec2.VpcCidrBlockStateCodeDisassociating)
+			return &ec2.DisassociateVpcCidrBlockOutput{CidrBlockAssociation: out, VpcId: vpc.VpcId}, nil
+		}
+	}
+	return nil, apiError("InvalidVpcCidrBlockAssociationID.NotFound", "The vpc CIDR block association ID '%s' does not exist", id)
+}
+
+// WaitUntilVpcAvailableWithContext returns once the vpcs selected are available, the vpcs in fake are available as soon as they are created.
+func (e *EC2) WaitUntilVpcAvailableWithContext(ctx aws.Context, input *ec2.DescribeVpcsInput, _ ...request.WaiterOption) error {
+	e.cloud.mu.Lock()
+	_, err := e.enter(ctx, "WaitUntilVpcAvailable")
+	e.cloud.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	_, err = e.DescribeVpcsWithContext(ctx, input)
+	return err
+}
+
+// CreateSubnetWithContext creates the subnet in the vpc, the CIDR of subnet should be within that of vpc and should not overlap with other subnets.
+func (e *EC2) CreateSubnetWithContext(ctx aws.Context, input *ec2.CreateSubnetInput, _ ...request.Option) (*ec2.CreateSubnetOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "CreateSubnet")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	vpc, ok := reg.vpcs[aws.StringValue(input.VpcId)]
+	if !ok {
+		return nil, apiError("InvalidVpcID.NotFound", "The vpc ID '%s' does not exist", aws.StringValue(input.VpcId))
+	}
+	cidr := aws.StringValue(input.CidrBlock)
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, apiError("InvalidParameterValue", "Value (%s) for parameter cidrBlock is invalid. This is not a valid CIDR block.", cidr)
+	}
+	subnetSize, _ := network.Mask.Size()
+	vpcNetwork := blockOf(vpc, network)
+	if vpcNetwork == nil || subnetSize > 28 {
+		return nil, apiError("InvalidSubnet.Range", "The CIDR '%s' is invalid.", cidr)
+	}
+	if vpcSize, _ := vpcNetwork.Mask.Size(); subnetSize < vpcSize {
+		return nil, apiError("InvalidSubnet.Range", "The CIDR '%s' is invalid.", cidr)
+	}
+	for _, subnet := range reg.subnets {
+		if *subnet.VpcId != *vpc.VpcId {
+			continue
+		}
+		_, existing, _ := net.ParseCIDR(*subnet.CidrBlock)
+		if existing.Contains(network.IP) || network.Contains(existing.IP) {
+			return nil, apiError("InvalidSubnet.Conflict", "The CIDR '%s' conflicts with another subnet", cidr)
+		}
+	}
+	zone := aws.StringValue(input.AvailabilityZone)
+	if zone == "" {
+		zone = reg.zones[0]
+	}
+	if !contains(reg.zones, zone) {
+		return nil, apiError("InvalidParameterValue", "Value (%s) for parameter availabilityZone is invalid. Subnets can currently only be created in the following availability zones: %v.", zone, reg.zones)
+	}
+
+	subnet := &ec2.Subnet{
+		SubnetId:                aws.String(e.cloud.id("subnet")),
+		VpcId:                   vpc.VpcId,
+		CidrBlock:               aws.String(network.String()),
+		AvailabilityZone:        aws.String(zone),
+		AvailableIpAddressCount: aws.Int64(int64(1)<<uint(32-subnetSize) - 5),
+		DefaultForAz:            aws.Bool(false),
+		MapPublicIpOnLaunch:     aws.Bool(false),
+		OwnerId:                 aws.String(OwnerID),
+		State:                   aws.String(ec2.SubnetStateAvailable),
+	}
+	reg.subnets[*subnet.SubnetId] = subnet
+
+	out := awsutil.CopyOf(subnet).(*ec2.Subnet)
+	out.State = aws.String(ec2.SubnetStatePending)
+	return &ec2.CreateSubnetOutput{Subnet: out}, nil
+}
+
+// DescribeSubnetsWithContext describes the subnets selected, supports filters: subnet-id, vpc-id, cidr-block, availability-zone, state, default-for-az and tags.
+func (e *EC2) DescribeSubnetsWithContext(ctx aws.Context, input *ec2.DescribeSubnetsInput, _ ...request.Option) (*ec2.DescribeSubnetsOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DescribeSubnets")
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := reg.selectIDs(input.SubnetIds, reg.subnetIDs(), "InvalidSubnetID.NotFound", "subnet")
+	if err != nil {
+		return &ec2.DescribeSubnetsOutput{}, err
+	}
+	out := &ec2.DescribeSubnetsOutput{Subnets: make([]*ec2.Subnet, 0)}
+	for _, id := range ids {
+		subnet := reg.subnets[id]
+		ok, err := matches(input.Filters, attributes{
+			"subnet-id":         {*subnet.SubnetId},
+			"vpc-id":            {*subnet.VpcId},
+			"cidr":              {*subnet.CidrBlock},
+			"cidr-block":        {*subnet.CidrBlock},
+			"cidrBlock":         {*subnet.CidrBlock},
+			"availability-zone": {*subnet.AvailabilityZone},
+			"availabilityZone":  {*subnet.AvailabilityZone},
+			"state":             {*subnet.State},
+			"default-for-az":    {boolString(*subnet.DefaultForAz)},
+			"defaultForAz":      {boolString(*subnet.DefaultForAz)},
+			"owner-id":          {*subnet.OwnerId},
+		}, subnet.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out.Subnets = append(out.Subnets, awsutil.CopyOf(subnet).(*ec2.Subnet))
+		}
+	}
+	start, end, next, err := paginate(len(out.Subnets), input.MaxResults, input.NextToken, 5, 1000, "InvalidParameterValue")
+	if err != nil {
+		return &ec2.DescribeSubnetsOutput{}, err
+	}
+	out.Subnets, out.NextToken = out.Subnets[start:end], next
+	return out, nil
+}
+
+// DescribeSubnetsPagesWithContext describes the subnets selected page by page, fn is called with every page till it returns false or the last page is reached.
+func (e *EC2) DescribeSubnetsPagesWithContext(ctx aws.Context, input *ec2.DescribeSubnetsInput, fn func(*ec2.DescribeSubnetsOutput, bool) bool, opts ...request.Option) error {
+	in := *input
+	for {
+		out, err := e.DescribeSubnetsWithContext(ctx, &in, opts...)
+		if err != nil {
+			return err
+		}
+		last := aws.StringValue(out.NextToken) == ""
+		if !fn(out, last) || last {
+			return nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+// DeleteSubnetWithContext deletes the subnet, it fails with DependencyViolation while instances or loadbalancers are running in it.
+// The route tables associated to the subnet are disassociated as part of the deletion.
+func (e *EC2) DeleteSubnetWithContext(ctx aws.Context, input *ec2.DeleteSubnetInput, _ ...request.Option) (*ec2.DeleteSubnetOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DeleteSubnet")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.SubnetId)
+	if _, ok := reg.subnets[id]; !ok {
+		return &ec2.DeleteSubnetOutput{}, apiError("InvalidSubnetID.NotFound", "The subnet ID '%s' does not exist", id)
+	}
+	if reg.subnetHasDependencies(id) {
+		return &ec2.DeleteSubnetOutput{}, apiError("DependencyViolation", "The subnet '%s' has dependencies and cannot be deleted.", id)
+	}
+	for _, table := range reg.routeTables {
+		associations := make([]*ec2.RouteTableAssociation, 0)
+		for _, association := range table.Associations {
+			if aws.StringValue(association.SubnetId) != id {
+				associations = append(associations, association)
+			}
+		}
+		table.Associations = associations
+	}
+	delete(reg.subnets, id)
+	return &ec2.DeleteSubnetOutput{}, nil
+}
+
+// WaitUntilSubnetAvailableWithContext returns once the subnets selected are available, the subnets in fake are available as soon as they are created.
+func (e *EC2) WaitUntilSubnetAvailableWithContext(ctx aws.Context, input *ec2.DescribeSubnetsInput, _ ...request.WaiterOption) error {
+	e.cloud.mu.Lock()
+	_, err := e.enter(ctx, "WaitUntilSubnetAvailable")
+	e.cloud.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	_, err = e.DescribeSubnetsWithContext(ctx, input)
+	return err
+}
+
+// CreateInternetGatewayWithContext creates a detached internet gateway.
+func (e *EC2) CreateInternetGatewayWithContext(ctx aws.Context, input *ec2.CreateInternetGatewayInput, _ ...request.Option) (*ec2.CreateInternetGatewayOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "CreateInternetGateway")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	igw := &ec2.InternetGateway{
+		InternetGatewayId: aws.String(e.cloud.id("igw")),
+		OwnerId:           aws.String(OwnerID),
+		Attachments:       make([]*ec2.InternetGatewayAttachment, 0),
+	}
+	reg.igws[*igw.InternetGatewayId] = igw
+	return &ec2.CreateInternetGatewayOutput{InternetGateway: awsutil.CopyOf(igw).(*ec2.InternetGateway)}, nil
+}
+
+// AttachInternetGatewayWithContext attaches the gateway to the vpc, a vpc can have only one gateway and a gateway can be attached to only one vpc.
+func (e *EC2) AttachInternetGatewayWithContext(ctx aws.Context, input *ec2.AttachInternetGatewayInput, _ ...request.Option) (*ec2.AttachInternetGatewayOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "AttachInternetGateway")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	igw, ok := reg.igws[aws.StringValue(input.InternetGatewayId)]
+	if !ok {
+		return nil, apiError("InvalidInternetGatewayID.NotFound", "The internetGateway ID '%s' does not exist", aws.StringValue(input.InternetGatewayId))
+	}
+	vpcID := aws.StringValue(input.VpcId)
+	if _, ok := reg.vpcs[vpcID]; !ok {
+		return nil, apiError("InvalidVpcID.NotFound", "The vpc ID '%s' does not exist", vpcID)
+	}
+	if len(igw.Attachments) != 0 {
+		return nil, apiError("Resource.AlreadyAssociated", "resource %s is already attached to network %s", *igw.InternetGatewayId, *igw.Attachments[0].VpcId)
+	}
+	if attached := reg.igwOfVpc(vpcID); attached != nil {
+		return nil, apiError("Resource.AlreadyAssociated", "network %s already has an internet gateway attached", vpcID)
+	}
+	igw.Attachments = []*ec2.InternetGatewayAttachment{{State: aws.String(ec2.AttachmentStatusAttached), VpcId: aws.String(vpcID)}}
+	return &ec2.AttachInternetGatewayOutput{}, nil
+}
+
+// DetachInternetGatewayWithContext detaches the gateway from the vpc.
+func (e *EC2) DetachInternetGatewayWithContext(ctx aws.Context, input *ec2.DetachInternetGatewayInput, _ ...request.Option) (*ec2.DetachInternetGatewayOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DetachInternetGateway")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	igw, ok := reg.igws[aws.StringValue(input.InternetGatewayId)]
+	if !ok {
+		return nil, apiError("InvalidInternetGatewayID.NotFound", "The internetGateway ID '%s' does not exist", aws.StringValue(input.InternetGatewayId))
+	}
+	if len(igw.Attachments) == 0 || *igw.Attachments[0].VpcId != aws.StringValue(input.VpcId) {
+		return nil, apiError("Gateway.NotAttached", "resource %s is not attached to network %s", *igw.InternetGatewayId, aws.StringValue(input.VpcId))
+	}
+	igw.Attachments = make([]*ec2.InternetGatewayAttachment, 0)
+	return &ec2.DetachInternetGatewayOutput{}, nil
+}
+
+// DeleteInternetGatewayWithContext deletes the gateway, it fails with DependencyViolation if the gateway is still attached to a vpc.
+func (e *EC2) DeleteInternetGatewayWithContext(ctx aws.Context, input *ec2.DeleteInternetGatewayInput, _ ...request.Option) (*ec2.DeleteInternetGatewayOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DeleteInternetGateway")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.InternetGatewayId)
+	igw, ok := reg.igws[id]
+	if !ok {
+		return &ec2.DeleteInternetGatewayOutput{}, apiError("InvalidInternetGatewayID.NotFound", "The internetGateway ID '%s' does not exist", id)
+	}
+	if len(igw.Attachments) != 0 {
+		return &ec2.DeleteInternetGatewayOutput{}, apiError("DependencyViolation", "The internetGateway '%s' has dependencies and cannot be deleted.", id)
+	}
+	delete(reg.igws, id)
+	return &ec2.DeleteInternetGatewayOutput{}, nil
+}
+
+// DescribeInternetGatewaysWithContext describes the gateways selected, supports filters: internet-gateway-id, attachment.vpc-id, attachment.state and tags.
+func (e *EC2) DescribeInternetGatewaysWithContext(ctx aws.Context, input *ec2.DescribeInternetGatewaysInput, _ ...request.Option) (*ec2.DescribeInternetGatewaysOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DescribeInternetGateways")
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := reg.selectIDs(input.InternetGatewayIds, reg.igwIDs(), "InvalidInternetGatewayID.NotFound", "internetGateway")
+	if err != nil {
+		return &ec2.DescribeInternetGatewaysOutput{}, err
+	}
+	out := &ec2.DescribeInternetGatewaysOutput{InternetGateways: make([]*ec2.InternetGateway, 0)}
+	for _, id := range ids {
+		igw := reg.igws[id]
+		attrs := attributes{
+			"internet-gateway-id": {*igw.InternetGatewayId},
+			"owner-id":            {*igw.OwnerId},
+			"attachment.vpc-id":   {},
+			"attachment.state":    {},
+		}
+		for _, attachment := range igw.Attachments {
+			attrs["attachment.vpc-id"] = append(attrs["attachment.vpc-id"], *attachment.VpcId)
+			attrs["attachment.state"] = append(attrs["attachment.state"], *attachment.State)
+		}
+		ok, err := matches(input.Filters, attrs, igw.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out.InternetGateways = append(out.InternetGateways, awsutil.CopyOf(igw).(*ec2.InternetGateway))
+		}
+	}
+	return out, nil
+}
+
+// CreateRouteTableWithContext creates the route table in the vpc with the local route.
+func (e *EC2) CreateRouteTableWithContext(ctx aws.Context, input *ec2.CreateRouteTableInput, _ ...request.Option) (*ec2.CreateRouteTableOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "CreateRouteTable")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	vpc, ok := reg.vpcs[aws.StringValue(input.VpcId)]
+	if !ok {
+		return nil, apiError("InvalidVpcID.NotFound", "The vpc ID '%s' does not exist", aws.StringValue(input.VpcId))
+	}
+	table := reg.newRouteTable(e.cloud, vpc)
+	return &ec2.CreateRouteTableOutput{RouteTable: awsutil.CopyOf(table).(*ec2.RouteTable)}, nil
+}
+
+// CreateRouteWithContext adds the route to the gateway in the route table, the gateway should be attached to the vpc of the route table.
+func (e *EC2) CreateRouteWithContext(ctx aws.Context, input *ec2.CreateRouteInput, _ ...request.Option) (*ec2.CreateRouteOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "CreateRoute")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	table, ok := reg.routeTables[aws.StringValue(input.RouteTableId)]
+	if !ok {
+		return nil, apiError("InvalidRouteTableID.NotFound", "The routeTable ID '%s' does not exist", aws.StringValue(input.RouteTableId))
+	}
+	gatewayID := aws.StringValue(input.GatewayId)
+	if err := reg.routableGateway(table, gatewayID); err != nil {
+		return nil, err
+	}
+	destination := aws.StringValue(input.DestinationCidrBlock)
+	if _, _, err := net.ParseCIDR(destination); err != nil {
+		return nil, apiError("InvalidParameterValue", "Value (%s) for parameter destinationCidrBlock is invalid. This is not a valid CIDR block.", destination)
+	}
+	for _, route := range table.Routes {
+		if aws.StringValue(route.DestinationCidrBlock) == destination {
+			return nil, apiError("RouteAlreadyExists", "The route identified by %s already exists.", destination)
+		}
+	}
+	table.Routes = append(table.Routes, &ec2.Route{
+		DestinationCidrBlock: aws.String(destination),
+		GatewayId:            aws.String(gatewayID),
+		Origin:               aws.String(ec2.RouteOriginCreateRoute),
+		State:                aws.String(ec2.RouteStateActive),
+	})
+	return &ec2.CreateRouteOutput{Return: aws.Bool(true)}, nil
+}
+
+// ReplaceRouteWithContext points the existing route of the route table to the gateway passed, the gateway should be attached to the vpc of the route table.
+func (e *EC2) ReplaceRouteWithContext(ctx aws.Context, input *ec2.ReplaceRouteInput, _ ...request.Option) (*ec2.ReplaceRouteOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "ReplaceRoute")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	table, ok := reg.routeTables[aws.StringValue(input.RouteTableId)]
+	if !ok {
+		return nil, apiError("InvalidRouteTableID.NotFound", "The routeTable ID '%s' does not exist", aws.StringValue(input.RouteTableId))
+	}
+	gatewayID := aws.StringValue(input.GatewayId)
+	if err := reg.routableGateway(table, gatewayID); err != nil {
+		return nil, err
+	}
+	route, err := routeOf(table, aws.StringValue(input.DestinationCidrBlock))
+	if err != nil {
+		return nil, err
+	}
+	route.GatewayId = aws.String(gatewayID)
+	route.Origin = aws.String(ec2.RouteOriginCreateRoute)
+	route.State = aws.String(ec2.RouteStateActive)
+	return &ec2.ReplaceRouteOutput{}, nil
+}
+
+// DeleteRouteWithContext removes the route from the route table, the local routes of the vpc cannot be removed.
+func (e *EC2) DeleteRouteWithContext(ctx aws.Context, input *ec2.DeleteRouteInput, _ ...request.Option) (*ec2.DeleteRouteOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DeleteRoute")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	table, ok := reg.routeTables[aws.StringValue(input.RouteTableId)]
+	if !ok {
+		return nil, apiError("InvalidRouteTableID.NotFound", "The routeTable ID '%s' does not exist", aws.StringValue(input.RouteTableId))
+	}
+	route, err := routeOf(table, aws.StringValue(input.DestinationCidrBlock))
+	if err != nil {
+		return nil, err
+	}
+	routes := make([]*ec2.Route, 0, len(table.Routes))
+	for _, existing := range table.Routes {
+		if existing != route {
+			routes = append(routes, existing)
+		}
+	}
+	table.Routes = routes
+	return &ec2.DeleteRouteOutput{}, nil
+}
+
+// AssociateRouteTableWithContext associates the route table with the subnet, a subnet can be associated to only one route table.
+func (e *EC2) AssociateRouteTableWithContext(ctx aws.Context, input *ec2.AssociateRouteTableInput, _ ...request.Option) (*ec2.AssociateRouteTableOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "AssociateRouteTable")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	table, ok := reg.routeTables[aws.StringValue(input.RouteTableId)]
+	if !ok {
+		return nil, apiError("InvalidRouteTableID.NotFound", "The routeTable ID '%s' does not exist", aws.StringValue(input.RouteTableId))
+	}
+	subnet, ok := reg.subnets[aws.StringValue(input.SubnetId)]
+	if !ok {
+		return nil, apiError("InvalidSubnetID.NotFound", "The subnet ID '%s' does not exist", aws.StringValue(input.SubnetId))
+	}
+	if *subnet.VpcId != *table.VpcId {
+		return nil, apiError("InvalidParameterValue", "route table %s and subnet %s belong to different networks", *table.RouteTableId, *subnet.SubnetId)
+	}
+	for _, other := range reg.routeTables {
+		for _, association := range other.Associations {
+			if aws.StringValue(association.SubnetId) == *subnet.SubnetId {
+				return nil, apiError("Resource.AlreadyAssociated", "the specified association for route table %s conflicts with an existing association", *table.RouteTableId)
+			}
+		}
+	}
+	association := &ec2.RouteTableAssociation{
+		Main:                    aws.Bool(false),
+		RouteTableAssociationId: aws.String(e.cloud.id("rtbassoc")),
+		RouteTableId:            table.RouteTableId,
+		SubnetId:                subnet.SubnetId,
+	}
+	table.Associations = append(table.Associations, association)
+	return &ec2.AssociateRouteTableOutput{AssociationId: association.RouteTableAssociationId}, nil
+}
+
+// DisassociateRouteTableWithContext removes the association of route table with the subnet, the main association cannot be removed.
+func (e *EC2) DisassociateRouteTableWithContext(ctx aws.Context, input *ec2.DisassociateRouteTableInput, _ ...request.Option) (*ec2.DisassociateRouteTableOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DisassociateRouteTable")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.AssociationId)
+	for _, table := range reg.routeTables {
+		for index, association := range table.Associations {
+			if *association.RouteTableAssociationId != id {
+				continue
+			}
+			if aws.BoolValue(association.Main) {
+				return nil, apiError("InvalidParameterValue", "cannot disassociate the main route table association %s", id)
+			}
+			table.Associations = append(table.Associations[:index], table.Associations[index+1:]...)
+			return &ec2.DisassociateRouteTableOutput{}, nil
+		}
+	}
+	return nil, apiError("InvalidAssociationID.NotFound", "The association ID '%s' does not exist", id)
+}
+
+// DeleteRouteTableWithContext deletes the route table, it fails with DependencyViolation while the table has associations.
+func (e *EC2) DeleteRouteTableWithContext(ctx aws.Context, input *ec2.DeleteRouteTableInput, _ ...request.Option) (*ec2.DeleteRouteTableOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DeleteRouteTable")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.RouteTableId)
+	table, ok := reg.routeTables[id]
+	if !ok {
+		return &ec2.DeleteRouteTableOutput{}, apiError("InvalidRouteTableID.NotFound", "The routeTable ID '%s' does not exist", id)
+	}
+	if len(table.Associations) != 0 {
+		return &ec2.DeleteRouteTableOutput{}, apiError("DependencyViolation", "The routeTable '%s' has dependencies and cannot be deleted.", id)
+	}
+	delete(reg.routeTables, id)
+	return &ec2.DeleteRouteTableOutput{}, nil
+}
+
+// DescribeRouteTablesWithContext describes the route tables selected, supports filters: route-table-id, vpc-id,
+// association.subnet-id, association.main, association.route-table-association-id and tags.
+func (e *EC2) DescribeRouteTablesWithContext(ctx aws.Context, input *ec2.DescribeRouteTablesInput, _ ...request.Option) (*ec2.DescribeRouteTablesOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DescribeRouteTables")
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := reg.selectIDs(input.RouteTableIds, reg.routeTableIDs(), "InvalidRouteTableID.NotFound", "routeTable")
+	if err != nil {
+		return &ec2.DescribeRouteTablesOutput{}, err
+	}
+	out := &ec2.DescribeRouteTablesOutput{RouteTables: make([]*ec2.RouteTable, 0)}
+	for _, id := range ids {
+		table := reg.routeTables[id]
+		attrs := attributes{
+			"route-table-id":                         {*table.RouteTableId},
+			"vpc-id":                                 {*table.VpcId},
+			"owner-id":                               {OwnerID},
+			"association.subnet-id":                  {},
+			"association.main":                       {},
+			"association.route-table-association-id": {},
+			"route.gateway-id":                       {},
+			"route.destination-cidr-block":           {},
+		}
+		for _, association := range table.Associations {
+			if association.SubnetId != nil {
+				attrs["association.subnet-id"] = append(attrs["association.subnet-id"], *association.SubnetId)
+			}
+			attrs["association.main"] = append(attrs["association.main"], boolString(aws.BoolValue(association.Main)))
+			attrs["association.route-table-association-id"] = append(attrs["association.route-table-association-id"], *association.RouteTableAssociationId)
+		}
+		for _, route := range table.Routes {
+			attrs["route.gateway-id"] = append(attrs["route.gateway-id"], aws.StringValue(route.GatewayId))
+			attrs["route.destination-cidr-block"] = append(attrs["route.destination-cidr-block"], aws.StringValue(route.DestinationCidrBlock))
+		}
+		ok, err := matches(input.Filters, attrs, table.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out.RouteTables = append(out.RouteTables, awsutil.CopyOf(table).(*ec2.RouteTable))
+		}
+	}
+	return out, nil
+}
+
+// CreateSecurityGroupWithContext creates the security group in the vpc with the default egress rule, the name of the group is unique in a vpc.
+func (e *EC2) CreateSecurityGroupWithContext(ctx aws.Context, input *ec2.CreateSecurityGroupInput, _ ...request.Option) (*ec2.CreateSecurityGroupOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "CreateSecurityGroup")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	if input.VpcId == nil {
+		return nil, apiError("VPCIdNotSpecified", "No default VPC for this user")
+	}
+	vpc, ok := reg.vpcs[*input.VpcId]
+	if !ok {
+		return nil, apiError("InvalidVpcID.NotFound", "The vpc ID '%s' does not exist", *input.VpcId)
+	}
+	name := aws.StringValue(input.GroupName)
+	if name == "" || aws.StringValue(input.Description) == "" {
+		return nil, apiError("MissingParameter", "The request must contain the parameters groupName and groupDescription")
+	}
+	for _, group := range reg.groups {
+		if *group.VpcId == *vpc.VpcId && *group.GroupName == name {
+			return nil, apiError("InvalidGroup.Duplicate", "The security group '%s' already exists for VPC '%s'", name, *vpc.VpcId)
+		}
+	}
+	group := reg.newSecurityGroup(e.cloud, vpc, name, *input.Description)
+	return &ec2.CreateSecurityGroupOutput{GroupId: aws.String(*group.GroupId)}, nil
+}
+
+// AuthorizeSecurityGroupIngressWithContext adds the ingress rules to the security group, either from the IpPermissions or the individual fields of the input.
+func (e *EC2) AuthorizeSecurityGroupIngressWithContext(ctx aws.Context, input *ec2.AuthorizeSecurityGroupIngressInput, _ ...request.Option) (*ec2.AuthorizeSecurityGroupIngressOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "AuthorizeSecurityGroupIngress")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	group, ok := reg.groups[aws.StringValue(input.GroupId)]
+	if !ok {
+		return nil, apiError("InvalidGroup.NotFound", "The security group '%s' does not exist", aws.StringValue(input.GroupId))
+	}
+	permissions := input.IpPermissions
+	if len(permissions) == 0 {
+		permission := &ec2.IpPermission{
+			IpProtocol: input.IpProtocol,
+			FromPort:   input.FromPort,
+			ToPort:     input.ToPort,
+		}
+		if input.CidrIp != nil {
+			permission.IpRanges = []*ec2.IpRange{{CidrIp: input.CidrIp}}
+		}
+		permissions = []*ec2.IpPermission{permission}
+	}
+	rules, err := reg.authorize(group.IpPermissions, permissions)
+	if err != nil {
+		return nil, err
+	}
+	group.IpPermissions = rules
+	return &ec2.AuthorizeSecurityGroupIngressOutput{}, nil
+}
+
+// RevokeSecurityGroupIngressWithContext removes the ingress rules from the security group, either the ones in IpPermissions or the one made of the individual fields of the input.
+func (e *EC2) RevokeSecurityGroupIngressWithContext(ctx aws.Context, input *ec2.RevokeSecurityGroupIngressInput, _ ...request.Option) (*ec2.RevokeSecurityGroupIngressOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "RevokeSecurityGroupIngress")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	group, ok := reg.groups[aws.StringValue(input.GroupId)]
+	if !ok {
+		return nil, apiError("InvalidGroup.NotFound", "The security group '%s' does not exist", aws.StringValue(input.GroupId))
+	}
+	permissions := input.IpPermissions
+	if len(permissions) == 0 {
+		permission := &ec2.IpPermission{
+			IpProtocol: input.IpProtocol,
+			FromPort:   input.FromPort,
+			ToPort:     input.ToPort,
+		}
+		if input.CidrIp != nil {
+			permission.IpRanges = []*ec2.IpRange{{CidrIp: input.CidrIp}}
+		}
+		permissions = []*ec2.IpPermission{permission}
+	}
+	rules, err := reg.revoke(group.IpPermissions, permissions)
+	if err != nil {
+		return nil, err
+	}
+	group.IpPermissions = rules
+	return &ec2.RevokeSecurityGroupIngressOutput{}, nil
+}
+
+// AuthorizeSecurityGroupEgressWithContext adds the egress rules to the security group.
+func (e *EC2) AuthorizeSecurityGroupEgressWithContext(ctx aws.Context, input *ec2.AuthorizeSecurityGroupEgressInput, _ ...request.Option) (*ec2.AuthorizeSecurityGroupEgressOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "AuthorizeSecurityGroupEgress")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	group, ok := reg.groups[aws.StringValue(input.GroupId)]
+	if !ok {
+		return nil, apiError("InvalidGroup.NotFound", "The security group '%s' does not exist", aws.StringValue(input.GroupId))
+	}
+	rules, err := reg.authorize(group.IpPermissionsEgress, input.IpPermissions)
+	if err != nil {
+		return nil, err
+	}
+	group.IpPermissionsEgress = rules
+	return &ec2.AuthorizeSecurityGroupEgressOutput{}, nil
+}
+
+// RevokeSecurityGroupEgressWithContext removes the egress rules from the security group.
+func (e *EC2) RevokeSecurityGroupEgressWithContext(ctx aws.Context, input *ec2.RevokeSecurityGroupEgressInput, _ ...request.Option) (*ec2.RevokeSecurityGroupEgressOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "RevokeSecurityGroupEgress")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	group, ok := reg.groups[aws.StringValue(input.GroupId)]
+	if !ok {
+		return nil, apiError("InvalidGroup.NotFound", "The security group '%s' does not exist", aws.StringValue(input.GroupId))
+	}
+	rules, err := reg.revoke(group.IpPermissionsEgress, input.IpPermissions)
+	if err != nil {
+		return nil, err
+	}
+	group.IpPermissionsEgress = rules
+	return &ec2.RevokeSecurityGroupEgressOutput{}, nil
+}
+
+// UpdateSecurityGroupRuleDescriptionsIngressWithContext replaces the descriptions of the ingress rules of the security group.
+func (e *EC2) UpdateSecurityGroupRuleDescriptionsIngressWithContext(ctx aws.Context, input *ec2.UpdateSecurityGroupRuleDescriptionsIngressInput, _ ...request.Option) (*ec2.UpdateSecurityGroupRuleDescriptionsIngressOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "UpdateSecurityGroupRuleDescriptionsIngress")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	group, ok := reg.groups[aws.StringValue(input.GroupId)]
+	if !ok {
+		return nil, apiError("InvalidGroup.NotFound", "The security group '%s' does not exist", aws.StringValue(input.GroupId))
+	}
+	rules, err := reg.describe(group.IpPermissions, input.IpPermissions)
+	if err != nil {
+		return nil, err
+	}
+	group.IpPermissions = rules
+	return &ec2.UpdateSecurityGroupRuleDescriptionsIngressOutput{Return: aws.Bool(true)}, nil
+}
+
+// UpdateSecurityGroupRuleDescriptionsEgressWithContext replaces the descriptions of the egress rules of the security group.
+func (e *EC2) UpdateSecurityGroupRuleDescriptionsEgressWithContext(ctx aws.Context, input *ec2.UpdateSecurityGroupRuleDescriptionsEgressInput, _ ...request.Option) (*ec2.UpdateSecurityGroupRuleDescriptionsEgressOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "UpdateSecurityGroupRuleDescriptionsEgress")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	group, ok := reg.groups[aws.StringValue(input.GroupId)]
+	if !ok {
+		return nil, apiError("InvalidGroup.NotFound", "The security group '%s' does not exist", aws.StringValue(input.GroupId))
+	}
+	rules, err := reg.describe(group.IpPermissionsEgress, input.IpPermissions)
+	if err != nil {
+		return nil, err
+	}
+	group.IpPermissionsEgress = rules
+	return &ec2.UpdateSecurityGroupRuleDescriptionsEgressOutput{Return: aws.Bool(true)}, nil
+}
+
+// DeleteSecurityGroupWithContext deletes the security group, the default group cannot be deleted
+// and it fails with DependencyViolation while instances, loadbalancers or the rules of other groups are using the group.
+func (e *EC2) DeleteSecurityGroupWithContext(ctx aws.Context, input *ec2.DeleteSecurityGroupInput, _ ...request.Option) (*ec2.DeleteSecurityGroupOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DeleteSecurityGroup")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.GroupId)
+	group, ok := reg.groups[id]
+	if !ok {
+		return &ec2.DeleteSecurityGroupOutput{}, apiError("InvalidGroup.NotFound", "The security group '%s' does not exist", id)
+	}
+	if *group.GroupName == "default" {
+		return &ec2.DeleteSecurityGroupOutput{}, apiError("CannotDelete", "the specified group: \"%s\" name: \"default\" cannot be deleted by a user", id)
+	}
+	if reg.groupInUse(id) {
+		return &ec2.DeleteSecurityGroupOutput{}, apiError("DependencyViolation", "resource %s has a dependent object", id)
+	}
+	delete(reg.groups, id)
+	return &ec2.DeleteSecurityGroupOutput{}, nil
+}
+
+// DescribeSecurityGroupsWithContext describes the security groups selected, supports filters: group-id, group-name, vpc-id, description and tags.
+func (e *EC2) DescribeSecurityGroupsWithContext(ctx aws.Context, input *ec2.DescribeSecurityGroupsInput, _ ...request.Option) (*ec2.DescribeSecurityGroupsOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DescribeSecurityGroups")
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := reg.selectIDs(input.GroupIds, reg.groupIDs(), "InvalidGroup.NotFound", "security group")
+	if err != nil {
+		return &ec2.DescribeSecurityGroupsOutput{}, err
+	}
+	out := &ec2.DescribeSecurityGroupsOutput{SecurityGroups: make([]*ec2.SecurityGroup, 0)}
+	for _, id := range ids {
+		group := reg.groups[id]
+		if len(input.GroupNames) != 0 && !contains(aws.StringValueSlice(input.GroupNames), *group.GroupName) {
+			continue
+		}
+		ok, err := matches(input.Filters, attributes{
+			"group-id":    {*group.GroupId},
+			"group-name":  {*group.GroupName},
+			"vpc-id":      {*group.VpcId},
+			"description": {*group.Description},
+			"owner-id":    {*group.OwnerId},
+		}, group.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out.SecurityGroups = append(out.SecurityGroups, awsutil.CopyOf(group).(*ec2.SecurityGroup))
+		}
+	}
+	start, end, next, err := paginate(len(out.SecurityGroups), input.MaxResults, input.NextToken, 5, 1000, "InvalidParameterValue")
+	if err != nil {
+		return &ec2.DescribeSecurityGroupsOutput{}, err
+	}
+	out.SecurityGroups, out.NextToken = out.SecurityGroups[start:end], next
+	return out, nil
+}
+
+// DescribeSecurityGroupsPagesWithContext describes the security groups selected page by page, fn is called with every page till it returns false or the last page is reached.
+func (e *EC2) DescribeSecurityGroupsPagesWithContext(ctx aws.Context, input *ec2.DescribeSecurityGroupsInput, fn func(*ec2.DescribeSecurityGroupsOutput, bool) bool, opts ...request.Option) error {
+	in := *input
+	for {
+		out, err := e.DescribeSecurityGroupsWithContext(ctx, &in, opts...)
+		if err != nil {
+			return err
+		}
+		last := aws.StringValue(out.NextToken) == ""
+		if !fn(out, last) || last {
+			return nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+// CreateTagsWithContext adds or overwrites the tags of the resources passed, it fails if any of the resource does not exist.
+func (e *EC2) CreateTagsWithContext(ctx aws.Context, input *ec2.CreateTagsInput, _ ...request.Option) (*ec2.CreateTagsOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "CreateTags")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	for _, id := range aws.StringValueSlice(input.Resources) {
+		if reg.tagsOf(id) == nil {
+			return nil, reg.notFound(id)
+		}
+	}
+	for _, id := range aws.StringValueSlice(input.Resources) {
+		tags := reg.tagsOf(id)
+		for _, tag := range input.Tags {
+			*tags = setTag(*tags, aws.StringValue(tag.Key), aws.StringValue(tag.Value))
+		}
+	}
+	return &ec2.CreateTagsOutput{}, nil
+}
+
+// DescribeAvailabilityZonesWithContext describes the zones of the region, the zones can be selected using ZoneNames.
+func (e *EC2) DescribeAvailabilityZonesWithContext(ctx aws.Context, input *ec2.DescribeAvailabilityZonesInput, _ ...request.Option) (*ec2.DescribeAvailabilityZonesOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DescribeAvailabilityZones")
+	if err != nil {
+		return nil, err
+	}
+
+	names := reg.zones
+	if len(input.ZoneNames) != 0 {
+		names = aws.StringValueSlice(input.ZoneNames)
+	}
+	out := &ec2.DescribeAvailabilityZonesOutput{AvailabilityZones: make([]*ec2.AvailabilityZone, 0)}
+	for _, name := range names {
+		if !contains(reg.zones, name) {
+			return nil, apiError("InvalidParameterValue", "Invalid availability zone: [%s]", name)
+		}
+		out.AvailabilityZones = append(out.AvailabilityZones, &ec2.AvailabilityZone{
+			RegionName: aws.String(reg.name),
+			State:      aws.String(ec2.AvailabilityZoneStateAvailable),
+			ZoneName:   aws.String(name),
+		})
+	}
+	return out, nil
+}
+
+// DescribeRegionsWithContext describes the regions with which the Cloud was created.
+func (e *EC2) DescribeRegionsWithContext(ctx aws.Context, input *ec2.DescribeRegionsInput, _ ...request.Option) (*ec2.DescribeRegionsOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	if _, err := e.enter(ctx, "DescribeRegions"); err != nil {
+		return nil, err
+	}
+
+	out := &ec2.DescribeRegionsOutput{Regions: make([]*ec2.Region, 0)}
+	for _, name := range e.cloud.names {
+		if len(input.RegionNames) != 0 && !contains(aws.StringValueSlice(input.RegionNames), name) {
+			continue
+		}
+		out.Regions = append(out.Regions, &ec2.Region{
+			Endpoint:   aws.String("ec2." + name + ".amazonaws.com"),
+			RegionName: aws.String(name),
+		})
+	}
+	return out, nil
+}
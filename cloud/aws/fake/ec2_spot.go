@@ -0,0 +1,282 @@
+package awsfake
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// spotBasePrices are the hourly prices of the spot instances of Linux/UNIX in the first zone of every region, the prices in the next zones are
+// 10% lower per zone and the ones of Windows are double. The types not listed here are priced at 0.05, use SetSpotPrice to override them.
+var spotBasePrices = map[string]float64{
+	ec2.InstanceTypeT2Micro:  0.0035,
+	ec2.InstanceTypeT2Small:  0.0069,
+	ec2.InstanceTypeT2Medium: 0.0139,
+	ec2.InstanceTypeT2Large:  0.0278,
+	ec2.InstanceTypeM5Large:  0.0350,
+	ec2.InstanceTypeC5Large:  0.0310,
+}
+
+// spotProducts are the product descriptions of which the spot prices are reported by the fake.
+var spotProducts = []string{"Linux/UNIX", "Windows"}
+
+// SetSpotPrice sets the hourly price of the spot instances of the type passed in the zone passed (ex: us-east-1a), for Linux/UNIX.
+// The spot instances launched with the maximum price lower than it fail with SpotMaxPriceTooLow.
+func (c *Cloud) SetSpotPrice(instanceType, zone string, price float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, reg := range c.regions {
+		if contains(reg.zones, zone) {
+			reg.spotPrices[instanceType+"/"+zone] = price
+		}
+	}
+}
+
+// spotPrice returns the current spot price of the instance type in the zone for the product passed.
+func (r *region) spotPrice(instanceType, zone, product string) float64 {
+	price, ok := r.spotPrices[instanceType+"/"+zone]
+	if !ok {
+		base, known := spotBasePrices[instanceType]
+		if !known {
+			base = 0.05
+		}
+		for index, name := range r.zones {
+			if name == zone {
+				price = base * (1 - 0.1*float64(index))
+			}
+		}
+	}
+	if product == "Windows" {
+		price *= 2
+	}
+	return price
+}
+
+// DescribeSpotPriceHistoryWithContext reports the current spot price of the instance types, zones and products selected, the history is not modelled.
+// All the types with a base price are reported if none are passed, supports filters: instance-type, availability-zone and product-description.
+func (e *EC2) DescribeSpotPriceHistoryWithContext(ctx aws.Context, input *ec2.DescribeSpotPriceHistoryInput, _ ...request.Option) (*ec2.DescribeSpotPriceHistoryOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DescribeSpotPriceHistory")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	types := aws.StringValueSlice(input.InstanceTypes)
+	if len(types) == 0 {
+		for name := range spotBasePrices {
+			types = append(types, name)
+		}
+		sort.Strings(types)
+	}
+	zones := reg.zones
+	if zone := aws.StringValue(input.AvailabilityZone); zone != "" {
+		if !contains(reg.zones, zone) {
+			return nil, apiError("InvalidParameterValue", "Invalid availability zone: [%s]", zone)
+		}
+		zones = []string{zone}
+	}
+	products := spotProducts
+	if len(input.ProductDescriptions) != 0 {
+		products = aws.StringValueSlice(input.ProductDescriptions)
+	}
+
+	prices := make([]*ec2.SpotPrice, 0)
+	for _, instanceType := range types {
+		for _, zone := range zones {
+			for _, product := range products {
+				if !contains(spotProducts, product) {
+					continue
+				}
+				ok, err := matches(input.Filters, attributes{
+					"instance-type":       {instanceType},
+					"availability-zone":   {zone},
+					"product-description": {product},
+				}, nil)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					prices = append(prices, &ec2.SpotPrice{
+						InstanceType:       aws.String(instanceType),
+						AvailabilityZone:   aws.String(zone),
+						ProductDescription: aws.String(product),
+						SpotPrice:          aws.String(strconv.FormatFloat(reg.spotPrice(instanceType, zone, product), 'f', 6, 64)),
+						Timestamp:          aws.Time(e.cloud.now()),
+					})
+				}
+			}
+		}
+	}
+	start, end, next, err := paginate(len(prices), input.MaxResults, input.NextToken, 1, 1000, "InvalidParameterValue")
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeSpotPriceHistoryOutput{SpotPriceHistory: prices[start:end], NextToken: next}, nil
+}
+
+// DescribeSpotPriceHistoryPagesWithContext reports the spot prices selected page by page, fn is called with every page till it returns false or the last page is reached.
+func (e *EC2) DescribeSpotPriceHistoryPagesWithContext(ctx aws.Context, input *ec2.DescribeSpotPriceHistoryInput, fn func(*ec2.DescribeSpotPriceHistoryOutput, bool) bool, opts ...request.Option) error {
+	in := *input
+	for {
+		out, err := e.DescribeSpotPriceHistoryWithContext(ctx, &in, opts...)
+		if err != nil {
+			return err
+		}
+		last := aws.StringValue(out.NextToken) == ""
+		if !fn(out, last) || last {
+			return nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+// spotInstance validates the market options of the instances to be launched in the zone passed, the instances are marked as spot instances
+// by mark when the options are of spot. Only the persistent spot requests can be stopped/hibernated on interruption, as in aws.
+func (r *region) spotInstance(c *Cloud, instanceType, zone string, options *ec2.InstanceMarketOptionsRequest) (func(*ec2.Instance), error) {
+	if options == nil {
+		return func(*ec2.Instance) {}, nil
+	}
+	if market := aws.StringValue(options.MarketType); market != ec2.MarketTypeSpot {
+		return nil, apiError("InvalidParameterValue", "Value (%s) for parameter MarketType is invalid.", market)
+	}
+	spot := options.SpotOptions
+	if spot == nil {
+		spot = &ec2.SpotMarketOptions{}
+	}
+
+	behavior := aws.StringValue(spot.InstanceInterruptionBehavior)
+	switch behavior {
+	case "", ec2.InstanceInterruptionBehaviorTerminate:
+	case ec2.InstanceInterruptionBehaviorStop, ec2.InstanceInterruptionBehaviorHibernate:
+		if aws.StringValue(spot.SpotInstanceType) != ec2.SpotInstanceTypePersistent {
+			return nil, apiError("InvalidParameterCombination", "The interruption behavior %s is supported only by the persistent spot requests", behavior)
+		}
+	default:
+		return nil, apiError("InvalidParameterValue", "Value (%s) for parameter InstanceInterruptionBehavior is invalid.", behavior)
+	}
+	if requestType := aws.StringValue(spot.SpotInstanceType); requestType != "" && requestType != ec2.SpotInstanceTypeOneTime && requestType != ec2.SpotInstanceTypePersistent {
+		return nil, apiError("InvalidParameterValue", "Value (%s) for parameter SpotInstanceType is invalid.", requestType)
+	}
+
+	current := r.spotPrice(instanceType, zone, spotProducts[0])
+	if maxPrice := aws.StringValue(spot.MaxPrice); maxPrice != "" {
+		price, err := strconv.ParseFloat(strings.TrimSpace(maxPrice), 64)
+		if err != nil || price <= 0 {
+			return nil, apiError("InvalidParameterValue", "Value (%s) for parameter MaxPrice is invalid.", maxPrice)
+		}
+		if price < current {
+			return nil, apiError("SpotMaxPriceTooLow", "Your Spot request price of %s is lower than the minimum required Spot request fulfillment price of %s.",
+				maxPrice, strconv.FormatFloat(current, 'f', 6, 64))
+		}
+	}
+	requestType := aws.StringValue(spot.SpotInstanceType)
+	if requestType == "" {
+		requestType = ec2.SpotInstanceTypeOneTime
+	}
+	return func(instance *ec2.Instance) {
+		instance.InstanceLifecycle = aws.String(ec2.InstanceLifecycleTypeSpot)
+		instance.SpotInstanceRequestId = aws.String(c.id("sir"))
+		r.spotRequests[*instance.SpotInstanceRequestId] = &ec2.SpotInstanceRequest{
+			SpotInstanceRequestId:        instance.SpotInstanceRequestId,
+			InstanceId:                   instance.InstanceId,
+			Type:                         aws.String(requestType),
+			State:                        aws.String(ec2.SpotInstanceStateActive),
+			InstanceInterruptionBehavior: aws.String(behavior),
+			SpotPrice:                    spot.MaxPrice,
+			LaunchedAvailabilityZone:     aws.String(zone),
+			CreateTime:                   aws.Time(c.now()),
+		}
+	}, nil
+}
+
+// terminateSpot updates the spot request of the instance terminated, the one-time requests are closed once their instance is terminated.
+// The persistent requests are opened again as aws does, it launches a new instance for them unless they are cancelled before terminating the instance.
+func (r *region) terminateSpot(instance *ec2.Instance) {
+	spotRequest, ok := r.spotRequests[aws.StringValue(instance.SpotInstanceRequestId)]
+	if !ok || aws.StringValue(spotRequest.State) != ec2.SpotInstanceStateActive {
+		return
+	}
+	spotRequest.State = aws.String(ec2.SpotInstanceStateClosed)
+	if aws.StringValue(spotRequest.Type) == ec2.SpotInstanceTypePersistent {
+		spotRequest.State = aws.String(ec2.SpotInstanceStateOpen)
+	}
+}
+
+// DescribeSpotInstanceRequestsWithContext describes the spot requests selected, all the requests of the region are described if none are passed.
+// Supports filters: instance-id, state and type.
+func (e *EC2) DescribeSpotInstanceRequestsWithContext(ctx aws.Context, input *ec2.DescribeSpotInstanceRequestsInput, _ ...request.Option) (*ec2.DescribeSpotInstanceRequestsOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DescribeSpotInstanceRequests")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	ids := aws.StringValueSlice(input.SpotInstanceRequestIds)
+	if len(ids) == 0 {
+		for id := range reg.spotRequests {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+	}
+	requests := make([]*ec2.SpotInstanceRequest, 0)
+	for _, id := range ids {
+		spotRequest, ok := reg.spotRequests[id]
+		if !ok {
+			return nil, apiError("InvalidSpotInstanceRequestID.NotFound", "The spot instance request ID '%s' does not exist", id)
+		}
+		ok, err := matches(input.Filters, attributes{
+			"instance-id": {aws.StringValue(spotRequest.InstanceId)},
+			"state":       {aws.StringValue(spotRequest.State)},
+			"type":        {aws.StringValue(spotRequest.Type)},
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			requests = append(requests, awsutil.CopyOf(spotRequest).(*ec2.SpotInstanceRequest))
+		}
+	}
+	return &ec2.DescribeSpotInstanceRequestsOutput{SpotInstanceRequests: requests}, nil
+}
+
+// CancelSpotInstanceRequestsWithContext cancels the spot requests passed, the instances launched for them are left running as in aws.
+// Cancelling a request which is cancelled already is a no-op.
+func (e *EC2) CancelSpotInstanceRequestsWithContext(ctx aws.Context, input *ec2.CancelSpotInstanceRequestsInput, _ ...request.Option) (*ec2.CancelSpotInstanceRequestsOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "CancelSpotInstanceRequests")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	requests := make([]*ec2.SpotInstanceRequest, 0)
+	for _, id := range aws.StringValueSlice(input.SpotInstanceRequestIds) {
+		spotRequest, ok := reg.spotRequests[id]
+		if !ok {
+			return nil, apiError("InvalidSpotInstanceRequestID.NotFound", "The spot instance request ID '%s' does not exist", id)
+		}
+		requests = append(requests, spotRequest)
+	}
+	cancelled := make([]*ec2.CancelledSpotInstanceRequest, 0)
+	for _, spotRequest := range requests {
+		spotRequest.State = aws.String(ec2.SpotInstanceStateCancelled)
+		cancelled = append(cancelled, &ec2.CancelledSpotInstanceRequest{SpotInstanceRequestId: spotRequest.SpotInstanceRequestId, State: aws.String(ec2.CancelSpotInstanceRequestStateCancelled)})
+	}
+	return &ec2.CancelSpotInstanceRequestsOutput{CancelledSpotInstanceRequests: cancelled}, nil
+}
@@ -0,0 +1,763 @@
+package awsfake
+
+import (
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// the range of size in GiB aws accepts for the volumes of each type.
+var volumeSizes = map[string][2]int64{
+	ec2.VolumeTypeStandard: {1, 1024},
+	ec2.VolumeTypeGp2:      {1, 16384},
+	ec2.VolumeTypeIo1:      {4, 16384},
+	ec2.VolumeTypeSt1:      {500, 16384},
+	ec2.VolumeTypeSc1:      {500, 16384},
+}
+
+// rootVolumeSize is the size of the snapshot backing the root device of the images which are not modelled by the fake.
+const rootVolumeSize = 8
+
+// DescribeVolumesWithContext describes the volumes selected, supports filters: volume-id, status, size, volume-type, availability-zone,
+// encrypted, snapshot-id, attachment.instance-id, attachment.device, attachment.status, attachment.delete-on-termination and tags.
+func (e *EC2) DescribeVolumesWithContext(ctx aws.Context, input *ec2.DescribeVolumesInput, _ ...request.Option) (*ec2.DescribeVolumesOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DescribeVolumes")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	out, err := reg.describeVolumes(input)
+	if err != nil {
+		return out, err
+	}
+	start, end, next, err := paginate(len(out.Volumes), input.MaxResults, input.NextToken, 5, 500, "InvalidParameterValue")
+	if err != nil {
+		return &ec2.DescribeVolumesOutput{}, err
+	}
+	out.Volumes, out.NextToken = out.Volumes[start:end], next
+	return out, nil
+}
+
+func (r *region) describeVolumes(input *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
+	ids, err := r.selectIDs(input.VolumeIds, r.volumeIDs(), "InvalidVolume.NotFound", "volume")
+	if err != nil {
+		return &ec2.DescribeVolumesOutput{}, err
+	}
+	out := &ec2.DescribeVolumesOutput{Volumes: make([]*ec2.Volume, 0)}
+	for _, id := range ids {
+		volume := r.volumes[id]
+		attrs := attributes{
+			"volume-id":         {*volume.VolumeId},
+			"status":            {*volume.State},
+			"size":              {strconv.FormatInt(*volume.Size, 10)},
+			"volume-type":       {*volume.VolumeType},
+			"availability-zone": {*volume.AvailabilityZone},
+			"encrypted":         {boolString(*volume.Encrypted)},
+			"snapshot-id":       {aws.StringValue(volume.SnapshotId)},
+		}
+		for _, attachment := range volume.Attachments {
+			attrs["attachment.instance-id"] = append(attrs["attachment.instance-id"], *attachment.InstanceId)
+			attrs["attachment.device"] = append(attrs["attachment.device"], *attachment.Device)
+			attrs["attachment.status"] = append(attrs["attachment.status"], *attachment.State)
+			attrs["attachment.delete-on-termination"] = append(attrs["attachment.delete-on-termination"], boolString(*attachment.DeleteOnTermination))
+		}
+		ok, err := matches(input.Filters, attrs, volume.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out.Volumes = append(out.Volumes, awsutil.CopyOf(volume).(*ec2.Volume))
+		}
+	}
+	return out, nil
+}
+
+// validVolume validates the volume requested the way aws does, irrespective of whether it is requested by a block device mapping or CreateVolume.
+func validVolume(volumeType string, size, iops int64, encrypted bool, kmsKeyID string) error {
+	sizes, ok := volumeSizes[volumeType]
+	if !ok {
+		return apiError("InvalidParameterValue", "Value (%s) for parameter volumeType is invalid. Valid values are standard, io1, gp2, sc1, st1.", volumeType)
+	}
+	if size < sizes[0] {
+		return apiError("InvalidParameterValue", "Volume of %dGiB is too small; minimum is %dGiB.", size, sizes[0])
+	}
+	if size > sizes[1] {
+		return apiError("InvalidParameterValue", "Volume of %dGiB is too large; maximum is %dGiB.", size, sizes[1])
+	}
+	switch {
+	case volumeType != ec2.VolumeTypeIo1 && iops != 0:
+		return apiError("InvalidParameterCombination", "The parameter iops is not supported for %s volumes.", volumeType)
+	case volumeType == ec2.VolumeTypeIo1 && iops == 0:
+		return apiError("MissingParameter", "The request must contain the parameter iops")
+	case volumeType == ec2.VolumeTypeIo1 && (iops < 100 || iops > 64000):
+		return apiError("InvalidParameterValue", "Volume iops of %d is invalid; iops must be between 100 and 64000.", iops)
+	case volumeType == ec2.VolumeTypeIo1 && iops > 50*size:
+		return apiError("InvalidParameterValue", "Iops to volume size ratio of %.1f is too high; maximum is 50.0", float64(iops)/float64(size))
+	}
+	if kmsKeyID != "" && !encrypted {
+		return apiError("InvalidParameterDependency", "The parameter KmsKeyId requires the parameter Encrypted to be set.")
+	}
+	return nil
+}
+
+// volumeIops returns the iops a volume of the type and size passed is provisioned with, gp2 gets 3 iops per GiB within 100 and 16000.
+func volumeIops(volumeType string, size, iops int64) *int64 {
+	switch volumeType {
+	case ec2.VolumeTypeIo1:
+		return aws.Int64(iops)
+	case ec2.VolumeTypeGp2:
+		baseline := 3 * size
+		if baseline < 100 {
+			baseline = 100
+		}
+		if baseline > 16000 {
+			baseline = 16000
+		}
+		return aws.Int64(baseline)
+	}
+	return nil
+}
+
+// rootDevice returns the name of the root device and the snapshot backing it for the image passed,
+// the images which are not modelled by the fake are backed by a snapshot of 8GiB at /dev/xvda.
+func (r *region) rootDevice(imageID string) (string, *ec2.EbsBlockDevice) {
+	if image, ok := r.images[imageID]; ok {
+		for _, mapping := range image.BlockDeviceMappings {
+			if *mapping.DeviceName == *image.RootDeviceName && mapping.Ebs != nil {
+				return *image.RootDeviceName, mapping.Ebs
+			}
+		}
+	}
+	return "/dev/xvda", &ec2.EbsBlockDevice{DeleteOnTermination: aws.Bool(true), Encrypted: aws.Bool(false), VolumeSize: aws.Int64(rootVolumeSize), VolumeType: aws.String(ec2.VolumeTypeGp2)}
+}
+
+// blockDevices validates the block device mappings requested while launching an instance of the image passed,
+// and returns the mappings of all the volumes to be created for each instance with the root device first.
+// The root device takes the values of the image for the ones not overridden by the mapping of the same device name.
+func (r *region) blockDevices(imageID string, requested []*ec2.BlockDeviceMapping) ([]*ec2.BlockDeviceMapping, error) {
+	rootName, image := r.rootDevice(imageID)
+	root := &ec2.BlockDeviceMapping{DeviceName: aws.String(rootName), Ebs: awsutil.CopyOf(image).(*ec2.EbsBlockDevice)}
+	mappings := []*ec2.BlockDeviceMapping{root}
+	seen := make(map[string]bool)
+	for _, mapping := range requested {
+		device := aws.StringValue(mapping.DeviceName)
+		if device == "" {
+			return nil, apiError("MissingParameter", "The request must contain the parameter deviceName")
+		}
+		if seen[device] {
+			return nil, apiError("InvalidBlockDeviceMapping", "The device '%s' is used in more than one block-device mapping", device)
+		}
+		seen[device] = true
+		if mapping.Ebs == nil {
+			return nil, apiError("InvalidBlockDeviceMapping", "The block-device mapping of '%s' must specify an ebs volume", device)
+		}
+
+		ebs := mapping.Ebs
+		if device == rootName {
+			// the values not passed for the root device are taken from the image.
+			if ebs.VolumeSize != nil {
+				if *ebs.VolumeSize < *image.VolumeSize {
+					return nil, apiError("InvalidBlockDeviceMapping", "Volume of size %dGB is smaller than snapshot of the image, expect size >= %dGB", *ebs.VolumeSize, *image.VolumeSize)
+				}
+				root.Ebs.VolumeSize = ebs.VolumeSize
+			}
+			if ebs.VolumeType != nil {
+				root.Ebs.VolumeType, root.Ebs.Iops = ebs.VolumeType, nil
+			}
+			if ebs.Iops != nil {
+				root.Ebs.Iops = ebs.Iops
+			}
+			if ebs.DeleteOnTermination != nil {
+				root.Ebs.DeleteOnTermination = ebs.DeleteOnTermination
+			}
+			if ebs.Encrypted != nil {
+				root.Ebs.Encrypted = ebs.Encrypted
+			}
+			if ebs.KmsKeyId != nil {
+				root.Ebs.KmsKeyId = ebs.KmsKeyId
+			}
+			if *root.Ebs.VolumeType == ec2.VolumeTypeSt1 || *root.Ebs.VolumeType == ec2.VolumeTypeSc1 {
+				return nil, apiError("InvalidBlockDeviceMapping", "Volume type %s is not supported for boot volumes", *root.Ebs.VolumeType)
+			}
+			continue
+		}
+		if ebs.VolumeSize == nil {
+			return nil, apiError("MissingParameter", "The request must contain the parameter size or snapshotId")
+		}
+		volume := awsutil.CopyOf(ebs).(*ec2.EbsBlockDevice)
+		if volume.VolumeType == nil {
+			volume.VolumeType = aws.String(ec2.VolumeTypeGp2)
+		}
+		if volume.DeleteOnTermination == nil {
+			volume.DeleteOnTermination = aws.Bool(true)
+		}
+		if volume.Encrypted == nil {
+			volume.Encrypted = aws.Bool(false)
+		}
+		mappings = append(mappings, &ec2.BlockDeviceMapping{DeviceName: aws.String(device), Ebs: volume})
+	}
+	for _, mapping := range mappings {
+		ebs := mapping.Ebs
+		if err := validVolume(*ebs.VolumeType, *ebs.VolumeSize, aws.Int64Value(ebs.Iops), aws.BoolValue(ebs.Encrypted), aws.StringValue(ebs.KmsKeyId)); err != nil {
+			return nil, err
+		}
+	}
+	return mappings, nil
+}
+
+// attachVolumes creates the volumes of the mappings passed in the zone of the instance and attaches them to it.
+func (r *region) attachVolumes(c *Cloud, instance *ec2.Instance, mappings []*ec2.BlockDeviceMapping) {
+	for _, mapping := range mappings {
+		volume := r.newVolume(c, *instance.Placement.AvailabilityZone, mapping.Ebs)
+		r.attach(c, volume, instance, *mapping.DeviceName, *mapping.Ebs.DeleteOnTermination)
+	}
+}
+
+// newVolume creates the volume as per the ebs passed in the zone, the volume is available as soon as it is created.
+// The volumes encrypted without a key are encrypted with the default key of ebs.
+func (r *region) newVolume(c *Cloud, zone string, ebs *ec2.EbsBlockDevice) *ec2.Volume {
+	volume := &ec2.Volume{
+		VolumeId:         aws.String(c.id("vol")),
+		AvailabilityZone: aws.String(zone),
+		CreateTime:       aws.Time(c.now()),
+		Size:             aws.Int64(*ebs.VolumeSize),
+		VolumeType:       aws.String(*ebs.VolumeType),
+		Iops:             volumeIops(*ebs.VolumeType, *ebs.VolumeSize, aws.Int64Value(ebs.Iops)),
+		Encrypted:        aws.Bool(aws.BoolValue(ebs.Encrypted)),
+		State:            aws.String(ec2.VolumeStateAvailable),
+	}
+	if ebs.SnapshotId != nil {
+		volume.SnapshotId = aws.String(*ebs.SnapshotId)
+	}
+	if aws.BoolValue(ebs.Encrypted) {
+		volume.KmsKeyId = aws.String(aws.StringValue(ebs.KmsKeyId))
+		if ebs.KmsKeyId == nil {
+			volume.KmsKeyId = aws.String("arn:aws:kms:" + r.name + ":" + OwnerID + ":alias/aws/ebs")
+		}
+	}
+	r.volumes[*volume.VolumeId] = volume
+	return volume
+}
+
+// attach attaches the volume to the instance at the device passed, the attachment completes as soon as it is made.
+func (r *region) attach(c *Cloud, volume *ec2.Volume, instance *ec2.Instance, device string, deleteOnTermination bool) {
+	volume.State = aws.String(ec2.VolumeStateInUse)
+	volume.Attachments = []*ec2.VolumeAttachment{{
+		VolumeId:            volume.VolumeId,
+		InstanceId:          instance.InstanceId,
+		Device:              aws.String(device),
+		State:               aws.String(ec2.VolumeAttachmentStateAttached),
+		AttachTime:          aws.Time(c.now()),
+		DeleteOnTermination: aws.Bool(deleteOnTermination),
+	}}
+	instance.BlockDeviceMappings = append(instance.BlockDeviceMappings, &ec2.InstanceBlockDeviceMapping{
+		DeviceName: aws.String(device),
+		Ebs: &ec2.EbsInstanceBlockDevice{
+			VolumeId:            volume.VolumeId,
+			Status:              aws.String(ec2.AttachmentStatusAttached),
+			AttachTime:          aws.Time(c.now()),
+			DeleteOnTermination: aws.Bool(deleteOnTermination),
+		},
+	})
+}
+
+// detach detaches the volume from the instance it is attached to.
+func (r *region) detach(volume *ec2.Volume) {
+	for _, attachment := range volume.Attachments {
+		if instance := r.instance(*attachment.InstanceId); instance != nil {
+			mappings := make([]*ec2.InstanceBlockDeviceMapping, 0)
+			for _, mapping := range instance.BlockDeviceMappings {
+				if aws.StringValue(mapping.Ebs.VolumeId) != *volume.VolumeId {
+					mappings = append(mappings, mapping)
+				}
+			}
+			instance.BlockDeviceMappings = mappings
+		}
+	}
+	volume.State, volume.Attachments = aws.String(ec2.VolumeStateAvailable), nil
+}
+
+// releaseVolumes deletes the volumes of the instance terminated which are marked to be deleted on termination, and detaches the others.
+func (r *region) releaseVolumes(instance *ec2.Instance) {
+	for _, mapping := range instance.BlockDeviceMappings {
+		volume, ok := r.volumes[aws.StringValue(mapping.Ebs.VolumeId)]
+		if !ok {
+			continue
+		}
+		if aws.BoolValue(mapping.Ebs.DeleteOnTermination) {
+			delete(r.volumes, *volume.VolumeId)
+			continue
+		}
+		volume.State, volume.Attachments = aws.String(ec2.VolumeStateAvailable), nil
+	}
+	instance.BlockDeviceMappings = nil
+}
+
+// CreateVolumeWithContext creates the volume in the zone passed, either of the size passed or out of the snapshot passed.
+// The volume is available as soon as it is created though the output reports it as creating.
+func (e *EC2) CreateVolumeWithContext(ctx aws.Context, input *ec2.CreateVolumeInput, _ ...request.Option) (*ec2.Volume, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "CreateVolume")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	zone := aws.StringValue(input.AvailabilityZone)
+	if zone == "" {
+		return nil, apiError("MissingParameter", "The request must contain the parameter availabilityZone")
+	}
+	if !contains(reg.zones, zone) {
+		return nil, apiError("InvalidZone.NotFound", "The zone '%s' does not exist.", zone)
+	}
+	ebs := &ec2.EbsBlockDevice{
+		VolumeSize: input.Size,
+		VolumeType: input.VolumeType,
+		Iops:       input.Iops,
+		Encrypted:  aws.Bool(aws.BoolValue(input.Encrypted)),
+		KmsKeyId:   input.KmsKeyId,
+	}
+	if ebs.VolumeType == nil {
+		ebs.VolumeType = aws.String(ec2.VolumeTypeGp2)
+	}
+	if id := aws.StringValue(input.SnapshotId); id != "" {
+		snapshot, ok := reg.snapshots[id]
+		if !ok {
+			return nil, apiError("InvalidSnapshot.NotFound", "The snapshot '%s' does not exist.", id)
+		}
+		if ebs.VolumeSize == nil {
+			ebs.VolumeSize = snapshot.VolumeSize
+		}
+		if *ebs.VolumeSize < *snapshot.VolumeSize {
+			return nil, apiError("InvalidParameterValue", "Volume of %dGiB is smaller than snapshot '%s', expect size >= %dGiB", *ebs.VolumeSize, id, *snapshot.VolumeSize)
+		}
+		// the volumes restored from the encrypted snapshots are encrypted with the key of the snapshot.
+		if aws.BoolValue(snapshot.Encrypted) {
+			ebs.Encrypted = aws.Bool(true)
+			if ebs.KmsKeyId == nil {
+				ebs.KmsKeyId = snapshot.KmsKeyId
+			}
+		}
+		ebs.SnapshotId = snapshot.SnapshotId
+	}
+	if ebs.VolumeSize == nil {
+		return nil, apiError("MissingParameter", "The request must contain the parameter size or snapshotId")
+	}
+	if err := validVolume(*ebs.VolumeType, *ebs.VolumeSize, aws.Int64Value(ebs.Iops), *ebs.Encrypted, aws.StringValue(input.KmsKeyId)); err != nil {
+		return nil, err
+	}
+
+	volume := reg.newVolume(e.cloud, zone, ebs)
+	for _, spec := range input.TagSpecifications {
+		if aws.StringValue(spec.ResourceType) == ec2.ResourceTypeVolume {
+			for _, tag := range spec.Tags {
+				volume.Tags = setTag(volume.Tags, aws.StringValue(tag.Key), aws.StringValue(tag.Value))
+			}
+		}
+	}
+	out := awsutil.CopyOf(volume).(*ec2.Volume)
+	out.State = aws.String(ec2.VolumeStateCreating)
+	return out, nil
+}
+
+// AttachVolumeWithContext attaches the available volume to the instance in the same zone at the device passed.
+// The volumes attached this way are retained on the termination of the instance, as in aws.
+func (e *EC2) AttachVolumeWithContext(ctx aws.Context, input *ec2.AttachVolumeInput, _ ...request.Option) (*ec2.VolumeAttachment, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "AttachVolume")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	volume, err := reg.volume(aws.StringValue(input.VolumeId))
+	if err != nil {
+		return nil, err
+	}
+	instance := reg.instance(aws.StringValue(input.InstanceId))
+	if instance == nil || !live(instance) {
+		return nil, apiError("InvalidInstanceID.NotFound", "The instance ID '%s' does not exist", aws.StringValue(input.InstanceId))
+	}
+	device := aws.StringValue(input.Device)
+	if device == "" {
+		return nil, apiError("MissingParameter", "The request must contain the parameter device")
+	}
+	if *volume.State != ec2.VolumeStateAvailable {
+		return nil, apiError("VolumeInUse", "%s is already attached to an instance", *volume.VolumeId)
+	}
+	if *volume.AvailabilityZone != *instance.Placement.AvailabilityZone {
+		return nil, apiError("InvalidVolume.ZoneMismatch", "The volume '%s' is not in the same availability zone as instance '%s'", *volume.VolumeId, *instance.InstanceId)
+	}
+	for _, mapping := range instance.BlockDeviceMappings {
+		if *mapping.DeviceName == device {
+			return nil, apiError("InvalidParameterValue", "Invalid value '%s' for unixDevice. Attachment point %s is already in use", device, device)
+		}
+	}
+
+	reg.attach(e.cloud, volume, instance, device, false)
+	out := awsutil.CopyOf(volume.Attachments[0]).(*ec2.VolumeAttachment)
+	out.State = aws.String(ec2.VolumeAttachmentStateAttaching)
+	return out, nil
+}
+
+// DetachVolumeWithContext detaches the volume from the instance it is attached to, the root volume of an instance which is not stopped cannot be detached.
+func (e *EC2) DetachVolumeWithContext(ctx aws.Context, input *ec2.DetachVolumeInput, _ ...request.Option) (*ec2.VolumeAttachment, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DetachVolume")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	volume, err := reg.volume(aws.StringValue(input.VolumeId))
+	if err != nil {
+		return nil, err
+	}
+	if len(volume.Attachments) == 0 {
+		return nil, apiError("IncorrectState", "Volume '%s' is in the 'available' state.", *volume.VolumeId)
+	}
+	attachment := volume.Attachments[0]
+	if id := aws.StringValue(input.InstanceId); id != "" && id != *attachment.InstanceId {
+		return nil, apiError("InvalidAttachment.NotFound", "Volume '%s' is not attached to instance '%s'", *volume.VolumeId, id)
+	}
+	if device := aws.StringValue(input.Device); device != "" && device != *attachment.Device {
+		return nil, apiError("InvalidAttachment.NotFound", "Volume '%s' is not attached at '%s'", *volume.VolumeId, device)
+	}
+	instance := reg.instance(*attachment.InstanceId)
+	if instance != nil && *instance.RootDeviceName == *attachment.Device && *instance.State.Name != ec2.InstanceStateNameStopped {
+		return nil, apiError("IncorrectState", "Unable to detach root volume '%s' from instance '%s'", *volume.VolumeId, *instance.InstanceId)
+	}
+
+	out := awsutil.CopyOf(attachment).(*ec2.VolumeAttachment)
+	out.State = aws.String(ec2.VolumeAttachmentStateDetaching)
+	reg.detach(volume)
+	return out, nil
+}
+
+// ModifyVolumeWithContext changes the size, type and iops of the volume, the volume can only grow.
+// The modification completes as soon as it is requested though the output reports it as modifying.
+func (e *EC2) ModifyVolumeWithContext(ctx aws.Context, input *ec2.ModifyVolumeInput, _ ...request.Option) (*ec2.ModifyVolumeOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "ModifyVolume")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	volume, err := reg.volume(aws.StringValue(input.VolumeId))
+	if err != nil {
+		return nil, err
+	}
+	size, volumeType, iops := *volume.Size, *volume.VolumeType, int64(0)
+	if volumeType == ec2.VolumeTypeIo1 {
+		iops = *volume.Iops
+	}
+	if input.Size != nil {
+		size = *input.Size
+	}
+	if input.VolumeType != nil {
+		volumeType = *input.VolumeType
+		if volumeType != ec2.VolumeTypeIo1 {
+			iops = 0
+		}
+	}
+	if input.Iops != nil {
+		iops = *input.Iops
+	}
+	if size < *volume.Size {
+		return nil, apiError("InvalidParameterValue", "New size cannot be smaller than existing size")
+	}
+	if size == *volume.Size && volumeType == *volume.VolumeType && (volumeType != ec2.VolumeTypeIo1 || iops == *volume.Iops) {
+		return nil, apiError("InvalidParameterValue", "The modification request would not change the volume '%s'", *volume.VolumeId)
+	}
+	if err := validVolume(volumeType, size, iops, *volume.Encrypted, ""); err != nil {
+		return nil, err
+	}
+	if (volumeType == ec2.VolumeTypeSt1 || volumeType == ec2.VolumeTypeSc1) && len(volume.Attachments) != 0 {
+		if instance := reg.instance(*volume.Attachments[0].InstanceId); instance != nil && *instance.RootDeviceName == *volume.Attachments[0].Device {
+			return nil, apiError("InvalidParameterCombination", "Volume type %s is not supported for boot volumes", volumeType)
+		}
+	}
+
+	modification := &ec2.VolumeModification{
+		VolumeId:           volume.VolumeId,
+		ModificationState:  aws.String(ec2.VolumeModificationStateModifying),
+		StartTime:          aws.Time(e.cloud.now()),
+		Progress:           aws.Int64(0),
+		OriginalSize:       aws.Int64(*volume.Size),
+		OriginalVolumeType: aws.String(*volume.VolumeType),
+		OriginalIops:       volume.Iops,
+		TargetSize:         aws.Int64(size),
+		TargetVolumeType:   aws.String(volumeType),
+		TargetIops:         volumeIops(volumeType, size, iops),
+	}
+	volume.Size, volume.VolumeType, volume.Iops = aws.Int64(size), aws.String(volumeType), volumeIops(volumeType, size, iops)
+
+	completed := awsutil.CopyOf(modification).(*ec2.VolumeModification)
+	completed.ModificationState, completed.Progress, completed.EndTime = aws.String(ec2.VolumeModificationStateCompleted), aws.Int64(100), aws.Time(e.cloud.now())
+	reg.volumeMods[*volume.VolumeId] = completed
+	return &ec2.ModifyVolumeOutput{VolumeModification: modification}, nil
+}
+
+// DescribeVolumesModificationsWithContext describes the latest modifications of the volumes, which are completed as soon as they are requested.
+// It fails with InvalidVolumeModification.NotFound if any of the volumes was never modified.
+func (e *EC2) DescribeVolumesModificationsWithContext(ctx aws.Context, input *ec2.DescribeVolumesModificationsInput, _ ...request.Option) (*ec2.DescribeVolumesModificationsOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DescribeVolumesModifications")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	out := &ec2.DescribeVolumesModificationsOutput{}
+	for _, id := range aws.StringValueSlice(input.VolumeIds) {
+		if _, err := reg.volume(id); err != nil {
+			return nil, err
+		}
+		modification, ok := reg.volumeMods[id]
+		if !ok {
+			return nil, apiError("InvalidVolumeModification.NotFound", "Modification for volume '%s' does not exist.", id)
+		}
+		out.VolumesModifications = append(out.VolumesModifications, awsutil.CopyOf(modification).(*ec2.VolumeModification))
+	}
+	return out, nil
+}
+
+// DeleteVolumeWithContext deletes the volume, it fails with VolumeInUse while the volume is attached to an instance.
+func (e *EC2) DeleteVolumeWithContext(ctx aws.Context, input *ec2.DeleteVolumeInput, _ ...request.Option) (*ec2.DeleteVolumeOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DeleteVolume")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	volume, err := reg.volume(aws.StringValue(input.VolumeId))
+	if err != nil {
+		return &ec2.DeleteVolumeOutput{}, err
+	}
+	if len(volume.Attachments) != 0 {
+		return &ec2.DeleteVolumeOutput{}, apiError("VolumeInUse", "Volume %s is currently attached to %s", *volume.VolumeId, *volume.Attachments[0].InstanceId)
+	}
+	delete(reg.volumes, *volume.VolumeId)
+	return &ec2.DeleteVolumeOutput{}, nil
+}
+
+// DescribeVolumesPagesWithContext describes the volumes selected page by page, fn is called with every page till it returns false or the last page is reached.
+func (e *EC2) DescribeVolumesPagesWithContext(ctx aws.Context, input *ec2.DescribeVolumesInput, fn func(*ec2.DescribeVolumesOutput, bool) bool, opts ...request.Option) error {
+	in := *input
+	for {
+		out, err := e.DescribeVolumesWithContext(ctx, &in, opts...)
+		if err != nil {
+			return err
+		}
+		last := aws.StringValue(out.NextToken) == ""
+		if !fn(out, last) || last {
+			return nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+// WaitUntilVolumeAvailableWithContext returns once all the volumes selected are available.
+func (e *EC2) WaitUntilVolumeAvailableWithContext(ctx aws.Context, input *ec2.DescribeVolumesInput, _ ...request.WaiterOption) error {
+	return e.waitVolumes(ctx, "WaitUntilVolumeAvailable", input, ec2.VolumeStateAvailable)
+}
+
+// WaitUntilVolumeInUseWithContext returns once all the volumes selected are in use.
+func (e *EC2) WaitUntilVolumeInUseWithContext(ctx aws.Context, input *ec2.DescribeVolumesInput, _ ...request.WaiterOption) error {
+	return e.waitVolumes(ctx, "WaitUntilVolumeInUse", input, ec2.VolumeStateInUse)
+}
+
+// waitVolumes fails with ResourceNotReady, the way waiter of sdk does after exhausting its attempts, if any of the volume is not in the state
+// as the state of volumes in the fake changes only on request.
+func (e *EC2) waitVolumes(ctx aws.Context, operation string, input *ec2.DescribeVolumesInput, state string) error {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, operation)
+	if err != nil {
+		return err
+	}
+
+	out, err := reg.describeVolumes(input)
+	if err != nil {
+		return err
+	}
+	for _, volume := range out.Volumes {
+		if *volume.State != state {
+			return awserr.New(request.WaiterResourceNotReadyErrorCode, "exceeded wait attempts", nil)
+		}
+	}
+	return nil
+}
+
+// volume returns the volume of the ID passed, failing with InvalidVolume.NotFound if it does not exist.
+func (r *region) volume(id string) (*ec2.Volume, error) {
+	volume, ok := r.volumes[id]
+	if !ok {
+		return nil, apiError("InvalidVolume.NotFound", "The volume '%s' does not exist.", id)
+	}
+	return volume, nil
+}
+
+// CreateSnapshotWithContext captures the snapshot of the volume, the snapshot completes as soon as it is started though the output reports it as pending.
+func (e *EC2) CreateSnapshotWithContext(ctx aws.Context, input *ec2.CreateSnapshotInput, _ ...request.Option) (*ec2.Snapshot, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "CreateSnapshot")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	volume, err := reg.volume(aws.StringValue(input.VolumeId))
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &ec2.Snapshot{
+		SnapshotId:  aws.String(e.cloud.id("snap")),
+		VolumeId:    volume.VolumeId,
+		VolumeSize:  aws.Int64(*volume.Size),
+		OwnerId:     aws.String(OwnerID),
+		State:       aws.String(ec2.SnapshotStateCompleted),
+		Progress:    aws.String("100%"),
+		StartTime:   aws.Time(e.cloud.now()),
+		Encrypted:   aws.Bool(*volume.Encrypted),
+		KmsKeyId:    volume.KmsKeyId,
+		Description: aws.String(aws.StringValue(input.Description)),
+	}
+	for _, spec := range input.TagSpecifications {
+		if aws.StringValue(spec.ResourceType) == ec2.ResourceTypeSnapshot {
+			for _, tag := range spec.Tags {
+				snapshot.Tags = setTag(snapshot.Tags, aws.StringValue(tag.Key), aws.StringValue(tag.Value))
+			}
+		}
+	}
+	reg.snapshots[*snapshot.SnapshotId] = snapshot
+
+	out := awsutil.CopyOf(snapshot).(*ec2.Snapshot)
+	out.State, out.Progress = aws.String(ec2.SnapshotStatePending), aws.String("0%")
+	return out, nil
+}
+
+// DescribeSnapshotsWithContext describes the snapshots of the account, the public snapshots are not modelled by the fake.
+// Supports filters: snapshot-id, volume-id, status, owner-id, description, encrypted and tags.
+func (e *EC2) DescribeSnapshotsWithContext(ctx aws.Context, input *ec2.DescribeSnapshotsInput, _ ...request.Option) (*ec2.DescribeSnapshotsOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "DescribeSnapshots")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRun(input.DryRun); err != nil {
+		return nil, err
+	}
+
+	out, err := reg.describeSnapshots(input)
+	if err != nil {
+		return out, err
+	}
+	start, end, next, err := paginate(len(out.Snapshots), input.MaxResults, input.NextToken, 5, 1000, "InvalidParameterValue")
+	if err != nil {
+		return &ec2.DescribeSnapshotsOutput{}, err
+	}
+	out.Snapshots, out.NextToken = out.Snapshots[start:end], next
+	return out, nil
+}
+
+func (r *region) describeSnapshots(input *ec2.DescribeSnapshotsInput) (*ec2.DescribeSnapshotsOutput, error) {
+	ids, err := r.selectIDs(input.SnapshotIds, r.snapshotIDs(), "InvalidSnapshot.NotFound", "snapshot")
+	if err != nil {
+		return &ec2.DescribeSnapshotsOutput{}, err
+	}
+	for _, owner := range aws.StringValueSlice(input.OwnerIds) {
+		if owner != "self" && owner != OwnerID {
+			return &ec2.DescribeSnapshotsOutput{Snapshots: make([]*ec2.Snapshot, 0)}, nil
+		}
+	}
+	out := &ec2.DescribeSnapshotsOutput{Snapshots: make([]*ec2.Snapshot, 0)}
+	for _, id := range ids {
+		snapshot := r.snapshots[id]
+		ok, err := matches(input.Filters, attributes{
+			"snapshot-id": {*snapshot.SnapshotId},
+			"volume-id":   {aws.StringValue(snapshot.VolumeId)},
+			"status":      {*snapshot.State},
+			"owner-id":    {*snapshot.OwnerId},
+			"description": {aws.StringValue(snapshot.Description)},
+			"encrypted":   {boolString(aws.BoolValue(snapshot.Encrypted))},
+		}, snapshot.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out.Snapshots = append(out.Snapshots, awsutil.CopyOf(snapshot).(*ec2.Snapshot))
+		}
+	}
+	return out, nil
+}
+
+// DescribeSnapshotsPagesWithContext describes the snapshots selected page by page, fn is called with every page till it returns false or the last page is reached.
+func (e *EC2) DescribeSnapshotsPagesWithContext(ctx aws.Context, input *ec2.DescribeSnapshotsInput, fn func(*ec2.DescribeSnapshotsOutput, bool) bool, opts ...request.Option) error {
+	in := *input
+	for {
+		out, err := e.DescribeSnapshotsWithContext(ctx, &in, opts...)
+		if err != nil {
+			return err
+		}
+		last := aws.StringValue(out.NextToken) == ""
+		if !fn(out, last) || last {
+			return nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+// WaitUntilSnapshotCompletedWithContext returns once all the snapshots selected are completed.
+func (e *EC2) WaitUntilSnapshotCompletedWithContext(ctx aws.Context, input *ec2.DescribeSnapshotsInput, _ ...request.WaiterOption) error {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.enter(ctx, "WaitUntilSnapshotCompleted")
+	if err != nil {
+		return err
+	}
+
+	out, err := reg.describeSnapshots(input)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range out.Snapshots {
+		if *snapshot.State != ec2.SnapshotStateCompleted {
+			return awserr.New(request.WaiterResourceNotReadyErrorCode, "exceeded wait attempts", nil)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package awsfake
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+)
+
+// ELB is the in-memory client of classic loadbalancer for a region, it is obtained from Cloud.ELB.
+type ELB struct {
+	elbiface.ELBAPI
+	cloud  *Cloud
+	region string
+}
+
+// CreateLoadBalancerWithContext creates the classic loadbalancer in the subnets passed, the name of loadbalancer is unique in a region.
+func (e *ELB) CreateLoadBalancerWithContext(ctx aws.Context, input *elb.CreateLoadBalancerInput, _ ...request.Option) (*elb.CreateLoadBalancerOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.cloud.enter(ctx, "CreateLoadBalancer", e.region)
+	if err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.LoadBalancerName)
+	if !validLbName(name) {
+		return nil, apiError("ValidationError", "LoadBalancer name cannot be longer than 32 characters and can contain only alphanumeric characters and hyphens")
+	}
+	if _, ok := reg.classicLbs[name]; ok {
+		return nil, apiError(elb.ErrCodeDuplicateAccessPointNameException, "Load balancer name '%s' already exists", name)
+	}
+	if len(input.Listeners) == 0 {
+		return nil, apiError("ValidationError", "At least one listener must be specified")
+	}
+	if len(input.Subnets) == 0 {
+		return nil, apiError("ValidationError", "Either AvailabilityZones or SubnetIds must be specified")
+	}
+	vpcID, zones := "", make([]*string, 0)
+	for _, id := range aws.StringValueSlice(input.Subnets) {
+		subnet, ok := reg.subnets[id]
+		if !ok {
+			return nil, apiError(elb.ErrCodeSubnetNotFoundException, "One or more subnets were not found: %s", id)
+		}
+		vpcID = *subnet.VpcId
+		zones = append(zones, subnet.AvailabilityZone)
+	}
+	for _, id := range aws.StringValueSlice(input.SecurityGroups) {
+		if group, ok := reg.groups[id]; !ok || *group.VpcId != vpcID {
+			return nil, apiError(elb.ErrCodeInvalidSecurityGroupException, "One or more security groups are invalid: %s", id)
+		}
+	}
+	scheme := aws.StringValue(input.Scheme)
+	if scheme == "" {
+		scheme = "internet-facing"
+	}
+
+	listeners := make([]*elb.ListenerDescription, 0)
+	for _, listener := range input.Listeners {
+		listeners = append(listeners, &elb.ListenerDescription{Listener: awsutil.CopyOf(listener).(*elb.Listener)})
+	}
+	e.cloud.seq++
+	dnsName := fmt.Sprintf("%s-%d.%s.elb.amazonaws.com", name, e.cloud.seq, reg.name)
+	reg.classicLbs[name] = &elb.LoadBalancerDescription{
+		LoadBalancerName:          aws.String(name),
+		DNSName:                   aws.String(dnsName),
+		CanonicalHostedZoneName:   aws.String(dnsName),
+		CanonicalHostedZoneNameID: aws.String("Z35SXDOTRQ7X7K"),
+		CreatedTime:               aws.Time(e.cloud.now()),
+		Scheme:                    aws.String(scheme),
+		VPCId:                     aws.String(vpcID),
+		Subnets:                   aws.StringSlice(aws.StringValueSlice(input.Subnets)),
+		SecurityGroups:            aws.StringSlice(aws.StringValueSlice(input.SecurityGroups)),
+		AvailabilityZones:         zones,
+		ListenerDescriptions:      listeners,
+		Instances:                 make([]*elb.Instance, 0),
+	}
+	return &elb.CreateLoadBalancerOutput{DNSName: aws.String(dnsName)}, nil
+}
+
+// DescribeLoadBalancersWithContext describes the classic loadbalancers selected by its names, all of them are described if none were selected.
+func (e *ELB) DescribeLoadBalancersWithContext(ctx aws.Context, input *elb.DescribeLoadBalancersInput, _ ...request.Option) (*elb.DescribeLoadBalancersOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.cloud.enter(ctx, "DescribeLoadBalancers", e.region)
+	if err != nil {
+		return nil, err
+	}
+
+	names := aws.StringValueSlice(input.LoadBalancerNames)
+	if len(names) == 0 {
+		for name := range reg.classicLbs {
+			names = append(names, name)
+		}
+		names = sortedKeys(names)
+	}
+	out := &elb.DescribeLoadBalancersOutput{LoadBalancerDescriptions: make([]*elb.LoadBalancerDescription, 0)}
+	for _, name := range names {
+		lb, ok := reg.classicLbs[name]
+		if !ok {
+			return &elb.DescribeLoadBalancersOutput{}, apiError(elb.ErrCodeAccessPointNotFoundException, "There is no ACTIVE Load Balancer named '%s'", name)
+		}
+		out.LoadBalancerDescriptions = append(out.LoadBalancerDescriptions, awsutil.CopyOf(lb).(*elb.LoadBalancerDescription))
+	}
+	start, end, next, err := paginate(len(out.LoadBalancerDescriptions), input.PageSize, input.Marker, 1, 400, "ValidationError")
+	if err != nil {
+		return &elb.DescribeLoadBalancersOutput{}, err
+	}
+	out.LoadBalancerDescriptions, out.NextMarker = out.LoadBalancerDescriptions[start:end], next
+	return out, nil
+}
+
+// DescribeLoadBalancersPagesWithContext describes the classic loadbalancers selected page by page, fn is called with every page till it returns false or the last page is reached.
+func (e *ELB) DescribeLoadBalancersPagesWithContext(ctx aws.Context, input *elb.DescribeLoadBalancersInput, fn func(*elb.DescribeLoadBalancersOutput, bool) bool, opts ...request.Option) error {
+	in := *input
+	for {
+		out, err := e.DescribeLoadBalancersWithContext(ctx, &in, opts...)
+		if err != nil {
+			return err
+		}
+		last := aws.StringValue(out.NextMarker) == ""
+		if !fn(out, last) || last {
+			return nil
+		}
+		in.Marker = out.NextMarker
+	}
+}
+
+// DeleteLoadBalancerWithContext deletes the classic loadbalancer, deleting a loadbalancer which does not exist is a no-op as in aws.
+func (e *ELB) DeleteLoadBalancerWithContext(ctx aws.Context, input *elb.DeleteLoadBalancerInput, _ ...request.Option) (*elb.DeleteLoadBalancerOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.cloud.enter(ctx, "DeleteLoadBalancer", e.region)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(reg.classicLbs, aws.StringValue(input.LoadBalancerName))
+	return &elb.DeleteLoadBalancerOutput{}, nil
+}
+
+// RegisterInstancesWithLoadBalancerWithContext registers the instances as the backends of the classic loadbalancer,
+// the instances have to be live and part of the vpc of the loadbalancer. Registering an instance already registered is a no-op as in aws.
+func (e *ELB) RegisterInstancesWithLoadBalancerWithContext(ctx aws.Context, input *elb.RegisterInstancesWithLoadBalancerInput, _ ...request.Option) (*elb.RegisterInstancesWithLoadBalancerOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.cloud.enter(ctx, "RegisterInstancesWithLoadBalancer", e.region)
+	if err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.LoadBalancerName)
+	lb, ok := reg.classicLbs[name]
+	if !ok {
+		return nil, apiError(elb.ErrCodeAccessPointNotFoundException, "There is no ACTIVE Load Balancer named '%s'", name)
+	}
+	registered := make([]string, 0)
+	for _, instance := range lb.Instances {
+		registered = append(registered, *instance.InstanceId)
+	}
+	for _, instance := range input.Instances {
+		id := aws.StringValue(instance.InstanceId)
+		found := reg.instance(id)
+		if found == nil || !live(found) || aws.StringValue(found.VpcId) != *lb.VPCId {
+			return nil, apiError(elb.ErrCodeInvalidEndPointException, "The requested instance '%s' is not valid for the load balancer '%s'", id, name)
+		}
+		if !contains(registered, id) {
+			registered = append(registered, id)
+			lb.Instances = append(lb.Instances, &elb.Instance{InstanceId: aws.String(id)})
+		}
+	}
+	out := &elb.RegisterInstancesWithLoadBalancerOutput{Instances: make([]*elb.Instance, 0)}
+	for _, instance := range lb.Instances {
+		out.Instances = append(out.Instances, &elb.Instance{InstanceId: aws.String(*instance.InstanceId)})
+	}
+	return out, nil
+}
+
+// validLbName reports whether the name is acceptable as the name of loadbalancer or target group in aws.
+func validLbName(name string) bool {
+	if name == "" || len(name) > 32 || name[0] == '-' || name[len(name)-1] == '-' {
+		return false
+	}
+	for _, char := range name {
+		if !(char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z' || char >= '0' && char <= '9' || char == '-') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,575 @@
+package awsfake
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+)
+
+// ELBV2 is the in-memory client of application loadbalancer for a region, it is obtained from Cloud.ELBV2.
+type ELBV2 struct {
+	elbv2iface.ELBV2API
+	cloud  *Cloud
+	region string
+}
+
+func (e *ELBV2) arn(resource string) string {
+	return fmt.Sprintf("arn:aws:elasticloadbalancing:%s:%s:%s/%016x", e.region, OwnerID, resource, e.cloud.seq)
+}
+
+// CreateLoadBalancerWithContext creates the application loadbalancer, it has to be created in subnets of at least two availability zones.
+func (e *ELBV2) CreateLoadBalancerWithContext(ctx aws.Context, input *elbv2.CreateLoadBalancerInput, _ ...request.Option) (*elbv2.CreateLoadBalancerOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.cloud.enter(ctx, "CreateLoadBalancer", e.region)
+	if err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.Name)
+	if !validLbName(name) {
+		return nil, apiError("ValidationError", "LoadBalancer name cannot be longer than 32 characters and can contain only alphanumeric characters and hyphens")
+	}
+	for _, lb := range reg.appLbs {
+		if *lb.LoadBalancerName == name {
+			return nil, apiError(elbv2.ErrCodeDuplicateLoadBalancerNameException, "A load balancer with the same name '%s' exists, but with different settings", name)
+		}
+	}
+	vpcID, zones, seen := "", make([]*elbv2.AvailabilityZone, 0), make(map[string]bool)
+	for _, id := range aws.StringValueSlice(input.Subnets) {
+		subnet, ok := reg.subnets[id]
+		if !ok {
+			return nil, apiError(elbv2.ErrCodeSubnetNotFoundException, "The subnet ID '%s' is not valid", id)
+		}
+		if seen[*subnet.AvailabilityZone] {
+			return nil, apiError(elbv2.ErrCodeInvalidConfigurationRequestException, "A load balancer cannot be attached to multiple subnets in the same Availability Zone")
+		}
+		seen[*subnet.AvailabilityZone], vpcID = true, *subnet.VpcId
+		zones = append(zones, &elbv2.AvailabilityZone{SubnetId: subnet.SubnetId, ZoneName: subnet.AvailabilityZone})
+	}
+	if len(zones) < 2 {
+		return nil, apiError("ValidationError", "At least two subnets in two different Availability Zones must be specified")
+	}
+	for _, id := range aws.StringValueSlice(input.SecurityGroups) {
+		if group, ok := reg.groups[id]; !ok || *group.VpcId != vpcID {
+			return nil, apiError(elbv2.ErrCodeInvalidSecurityGroupException, "Security group '%s' does not exist", id)
+		}
+	}
+	scheme, addressType := aws.StringValue(input.Scheme), aws.StringValue(input.IpAddressType)
+	if scheme == "" {
+		scheme = elbv2.LoadBalancerSchemeEnumInternetFacing
+	}
+	if addressType == "" {
+		addressType = elbv2.IpAddressTypeIpv4
+	}
+
+	e.cloud.seq++
+	lb := &elbv2.LoadBalancer{
+		LoadBalancerArn:       aws.String(e.arn("loadbalancer/app/" + name)),
+		LoadBalancerName:      aws.String(name),
+		DNSName:               aws.String(fmt.Sprintf("%s-%d.%s.elb.amazonaws.com", name, e.cloud.seq, reg.name)),
+		CanonicalHostedZoneId: aws.String("Z35SXDOTRQ7X7K"),
+		CreatedTime:           aws.Time(e.cloud.now()),
+		Scheme:                aws.String(scheme),
+		IpAddressType:         aws.String(addressType),
+		Type:                  aws.String(elbv2.LoadBalancerTypeEnumApplication),
+		State:                 &elbv2.LoadBalancerState{Code: aws.String(elbv2.LoadBalancerStateEnumActive)},
+		VpcId:                 aws.String(vpcID),
+		AvailabilityZones:     zones,
+		SecurityGroups:        aws.StringSlice(aws.StringValueSlice(input.SecurityGroups)),
+	}
+	reg.appLbs[*lb.LoadBalancerArn] = lb
+	groups := make([]*ec2.GroupIdentifier, 0)
+	for _, id := range aws.StringValueSlice(input.SecurityGroups) {
+		groups = append(groups, &ec2.GroupIdentifier{GroupId: aws.String(id), GroupName: reg.groups[id].GroupName})
+	}
+	for _, zone := range zones {
+		eni := reg.newInterface(e.cloud, reg.subnets[*zone.SubnetId], groups, lbInterfaceDescription(*lb.LoadBalancerArn))
+		eni.Status, eni.RequesterManaged = aws.String(ec2.NetworkInterfaceStatusInUse), aws.Bool(true)
+	}
+
+	out := awsutil.CopyOf(lb).(*elbv2.LoadBalancer)
+	out.State.Code = aws.String(elbv2.LoadBalancerStateEnumProvisioning)
+	return &elbv2.CreateLoadBalancerOutput{LoadBalancers: []*elbv2.LoadBalancer{out}}, nil
+}
+
+// DescribeLoadBalancersWithContext describes the application loadbalancers selected either by its ARNs or names.
+func (e *ELBV2) DescribeLoadBalancersWithContext(ctx aws.Context, input *elbv2.DescribeLoadBalancersInput, _ ...request.Option) (*elbv2.DescribeLoadBalancersOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.cloud.enter(ctx, "DescribeLoadBalancers", e.region)
+	if err != nil {
+		return nil, err
+	}
+
+	lbs, err := reg.selectLoadBalancers(input)
+	if err != nil {
+		return &elbv2.DescribeLoadBalancersOutput{}, err
+	}
+	out := &elbv2.DescribeLoadBalancersOutput{LoadBalancers: make([]*elbv2.LoadBalancer, 0)}
+	for _, lb := range lbs {
+		out.LoadBalancers = append(out.LoadBalancers, awsutil.CopyOf(lb).(*elbv2.LoadBalancer))
+	}
+	start, end, next, err := paginate(len(out.LoadBalancers), input.PageSize, input.Marker, 1, 400, "ValidationError")
+	if err != nil {
+		return &elbv2.DescribeLoadBalancersOutput{}, err
+	}
+	out.LoadBalancers, out.NextMarker = out.LoadBalancers[start:end], next
+	return out, nil
+}
+
+// DescribeLoadBalancersPagesWithContext describes the application loadbalancers selected page by page, fn is called with every page till it returns false or the last page is reached.
+func (e *ELBV2) DescribeLoadBalancersPagesWithContext(ctx aws.Context, input *elbv2.DescribeLoadBalancersInput, fn func(*elbv2.DescribeLoadBalancersOutput, bool) bool, opts ...request.Option) error {
+	in := *input
+	for {
+		out, err := e.DescribeLoadBalancersWithContext(ctx, &in, opts...)
+		if err != nil {
+			return err
+		}
+		last := aws.StringValue(out.NextMarker) == ""
+		if !fn(out, last) || last {
+			return nil
+		}
+		in.Marker = out.NextMarker
+	}
+}
+
+func (r *region) selectLoadBalancers(input *elbv2.DescribeLoadBalancersInput) ([]*elbv2.LoadBalancer, error) {
+	arns := make([]string, 0)
+	for arn := range r.appLbs {
+		arns = append(arns, arn)
+	}
+	lbs := make([]*elbv2.LoadBalancer, 0)
+	for _, arn := range aws.StringValueSlice(input.LoadBalancerArns) {
+		lb, ok := r.appLbs[arn]
+		if !ok {
+			return nil, apiError(elbv2.ErrCodeLoadBalancerNotFoundException, "Load balancers '[%s]' not found", arn)
+		}
+		lbs = append(lbs, lb)
+	}
+	for _, name := range aws.StringValueSlice(input.Names) {
+		var found *elbv2.LoadBalancer
+		for _, lb := range r.appLbs {
+			if *lb.LoadBalancerName == name {
+				found = lb
+			}
+		}
+		if found == nil {
+			return nil, apiError(elbv2.ErrCodeLoadBalancerNotFoundException, "One or more load balancers not found")
+		}
+		lbs = append(lbs, found)
+	}
+	if len(input.LoadBalancerArns) == 0 && len(input.Names) == 0 {
+		for _, arn := range sortedKeys(arns) {
+			lbs = append(lbs, r.appLbs[arn])
+		}
+	}
+	return lbs, nil
+}
+
+// DeleteLoadBalancerWithContext deletes the application loadbalancer along with its listeners, the target groups are retained.
+func (e *ELBV2) DeleteLoadBalancerWithContext(ctx aws.Context, input *elbv2.DeleteLoadBalancerInput, _ ...request.Option) (*elbv2.DeleteLoadBalancerOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.cloud.enter(ctx, "DeleteLoadBalancer", e.region)
+	if err != nil {
+		return nil, err
+	}
+
+	arn := aws.StringValue(input.LoadBalancerArn)
+	if _, ok := reg.appLbs[arn]; !ok {
+		return &elbv2.DeleteLoadBalancerOutput{}, apiError(elbv2.ErrCodeLoadBalancerNotFoundException, "Load balancer '%s' not found", arn)
+	}
+	for id, listener := range reg.listeners {
+		if *listener.LoadBalancerArn == arn {
+			delete(reg.listeners, id)
+		}
+	}
+	for _, group := range reg.targetGroups {
+		group.LoadBalancerArns = aws.StringSlice(remove(aws.StringValueSlice(group.LoadBalancerArns), arn))
+	}
+	delete(reg.appLbs, arn)
+	// the network interfaces of the loadbalancer are released a while after its deletion, as aws does.
+	for id, eni := range reg.interfaces {
+		if aws.StringValue(eni.Description) == lbInterfaceDescription(arn) {
+			reg.releasing[id] = interfaceReleasePolls
+		}
+	}
+	return &elbv2.DeleteLoadBalancerOutput{}, nil
+}
+
+// interfaceReleasePolls is the number of times the network interfaces of the application loadbalancer deleted
+// are described before they are released.
+const interfaceReleasePolls = 2
+
+// lbInterfaceDescription returns the description of the network interfaces of the application loadbalancer, ELB app/<name>/<id>.
+func lbInterfaceDescription(arn string) string {
+	return "ELB " + arn[strings.Index(arn, ":loadbalancer/")+len(":loadbalancer/"):]
+}
+
+// WaitUntilLoadBalancersDeletedWithContext returns once the loadbalancers selected are deleted, the loadbalancers are deleted as soon as it is requested in fake.
+func (e *ELBV2) WaitUntilLoadBalancersDeletedWithContext(ctx aws.Context, input *elbv2.DescribeLoadBalancersInput, _ ...request.WaiterOption) error {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.cloud.enter(ctx, "WaitUntilLoadBalancersDeleted", e.region)
+	if err != nil {
+		return err
+	}
+
+	for _, arn := range aws.StringValueSlice(input.LoadBalancerArns) {
+		if _, ok := reg.appLbs[arn]; ok {
+			return awserr.New(request.WaiterResourceNotReadyErrorCode, "exceeded wait attempts", nil)
+		}
+	}
+	return nil
+}
+
+// CreateTargetGroupWithContext creates the target group in the vpc, the name of target group is unique in a region.
+func (e *ELBV2) CreateTargetGroupWithContext(ctx aws.Context, input *elbv2.CreateTargetGroupInput, _ ...request.Option) (*elbv2.CreateTargetGroupOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.cloud.enter(ctx, "CreateTargetGroup", e.region)
+	if err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.Name)
+	if !validLbName(name) {
+		return nil, apiError("ValidationError", "Target group name '%s' cannot be longer than '32' characters", name)
+	}
+	for _, group := range reg.targetGroups {
+		if *group.TargetGroupName == name {
+			return nil, apiError(elbv2.ErrCodeDuplicateTargetGroupNameException, "A target group with the same name '%s' exists, but with different settings", name)
+		}
+	}
+	if _, ok := reg.vpcs[aws.StringValue(input.VpcId)]; !ok {
+		return nil, apiError("ValidationError", "The VPC ID '%s' is not found", aws.StringValue(input.VpcId))
+	}
+	if input.Protocol == nil || input.Port == nil {
+		return nil, apiError("ValidationError", "A protocol and port must be specified")
+	}
+
+	e.cloud.seq++
+	group := &elbv2.TargetGroup{
+		TargetGroupArn:             aws.String(e.arn("targetgroup/" + name)),
+		TargetGroupName:            aws.String(name),
+		Protocol:                   input.Protocol,
+		Port:                       input.Port,
+		VpcId:                      input.VpcId,
+		TargetType:                 aws.String(elbv2.TargetTypeEnumInstance),
+		HealthCheckEnabled:         aws.Bool(true),
+		HealthCheckProtocol:        input.HealthCheckProtocol,
+		HealthCheckPort:            input.HealthCheckPort,
+		HealthCheckPath:            input.HealthCheckPath,
+		HealthCheckIntervalSeconds: input.HealthCheckIntervalSeconds,
+		HealthCheckTimeoutSeconds:  input.HealthCheckTimeoutSeconds,
+		HealthyThresholdCount:      input.HealthyThresholdCount,
+		UnhealthyThresholdCount:    input.UnhealthyThresholdCount,
+		Matcher:                    input.Matcher,
+		LoadBalancerArns:           make([]*string, 0),
+	}
+	group = awsutil.CopyOf(group).(*elbv2.TargetGroup)
+	reg.targetGroups[*group.TargetGroupArn] = group
+	return &elbv2.CreateTargetGroupOutput{TargetGroups: []*elbv2.TargetGroup{awsutil.CopyOf(group).(*elbv2.TargetGroup)}}, nil
+}
+
+// DescribeTargetGroupsWithContext describes the target groups selected either by its ARNs, names or the loadbalancer they are attached to.
+func (e *ELBV2) DescribeTargetGroupsWithContext(ctx aws.Context, input *elbv2.DescribeTargetGroupsInput, _ ...request.Option) (*elbv2.DescribeTargetGroupsOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.cloud.enter(ctx, "DescribeTargetGroups", e.region)
+	if err != nil {
+		return nil, err
+	}
+
+	if arn := aws.StringValue(input.LoadBalancerArn); arn != "" {
+		if _, ok := reg.appLbs[arn]; !ok {
+			return &elbv2.DescribeTargetGroupsOutput{}, apiError(elbv2.ErrCodeLoadBalancerNotFoundException, "Load balancer '%s' not found", arn)
+		}
+	}
+	for _, arn := range aws.StringValueSlice(input.TargetGroupArns) {
+		if _, ok := reg.targetGroups[arn]; !ok {
+			return &elbv2.DescribeTargetGroupsOutput{}, apiError(elbv2.ErrCodeTargetGroupNotFoundException, "Target groups '[%s]' not found", arn)
+		}
+	}
+	arns := make([]string, 0)
+	for arn := range reg.targetGroups {
+		arns = append(arns, arn)
+	}
+	out := &elbv2.DescribeTargetGroupsOutput{TargetGroups: make([]*elbv2.TargetGroup, 0)}
+	for _, arn := range sortedKeys(arns) {
+		group := reg.targetGroups[arn]
+		switch {
+		case len(input.TargetGroupArns) != 0 && !contains(aws.StringValueSlice(input.TargetGroupArns), arn):
+			continue
+		case len(input.Names) != 0 && !contains(aws.StringValueSlice(input.Names), *group.TargetGroupName):
+			continue
+		case input.LoadBalancerArn != nil && !contains(aws.StringValueSlice(group.LoadBalancerArns), *input.LoadBalancerArn):
+			continue
+		}
+		out.TargetGroups = append(out.TargetGroups, awsutil.CopyOf(group).(*elbv2.TargetGroup))
+	}
+	if len(input.Names) != 0 && len(out.TargetGroups) != len(input.Names) {
+		return &elbv2.DescribeTargetGroupsOutput{}, apiError(elbv2.ErrCodeTargetGroupNotFoundException, "One or more target groups not found")
+	}
+	start, end, next, err := paginate(len(out.TargetGroups), input.PageSize, input.Marker, 1, 400, "ValidationError")
+	if err != nil {
+		return &elbv2.DescribeTargetGroupsOutput{}, err
+	}
+	out.TargetGroups, out.NextMarker = out.TargetGroups[start:end], next
+	return out, nil
+}
+
+// DescribeTargetGroupsPagesWithContext describes the target groups selected page by page, fn is called with every page till it returns false or the last page is reached.
+func (e *ELBV2) DescribeTargetGroupsPagesWithContext(ctx aws.Context, input *elbv2.DescribeTargetGroupsInput, fn func(*elbv2.DescribeTargetGroupsOutput, bool) bool, opts ...request.Option) error {
+	in := *input
+	for {
+		out, err := e.DescribeTargetGroupsWithContext(ctx, &in, opts...)
+		if err != nil {
+			return err
+		}
+		last := aws.StringValue(out.NextMarker) == ""
+		if !fn(out, last) || last {
+			return nil
+		}
+		in.Marker = out.NextMarker
+	}
+}
+
+// DeleteTargetGroupWithContext deletes the target group, it fails with ResourceInUse while a listener is forwarding to the group.
+func (e *ELBV2) DeleteTargetGroupWithContext(ctx aws.Context, input *elbv2.DeleteTargetGroupInput, _ ...request.Option) (*elbv2.DeleteTargetGroupOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.cloud.enter(ctx, "DeleteTargetGroup", e.region)
+	if err != nil {
+		return nil, err
+	}
+
+	arn := aws.StringValue(input.TargetGroupArn)
+	group, ok := reg.targetGroups[arn]
+	if !ok {
+		return &elbv2.DeleteTargetGroupOutput{}, apiError(elbv2.ErrCodeTargetGroupNotFoundException, "Target group '%s' not found", arn)
+	}
+	if len(group.LoadBalancerArns) != 0 {
+		return &elbv2.DeleteTargetGroupOutput{}, apiError(elbv2.ErrCodeResourceInUseException, "Target group '%s' is currently in use by a listener or a rule", arn)
+	}
+	delete(reg.targetGroups, arn)
+	delete(reg.targets, arn)
+	return &elbv2.DeleteTargetGroupOutput{}, nil
+}
+
+// RegisterTargetsWithContext registers the instances as the targets of the target group, the instances have to be live and part of the vpc of the group.
+// The targets are registered on the port of the group unless one is passed, registering a target already registered is a no-op as in aws.
+func (e *ELBV2) RegisterTargetsWithContext(ctx aws.Context, input *elbv2.RegisterTargetsInput, _ ...request.Option) (*elbv2.RegisterTargetsOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.cloud.enter(ctx, "RegisterTargets", e.region)
+	if err != nil {
+		return nil, err
+	}
+
+	arn := aws.StringValue(input.TargetGroupArn)
+	group, ok := reg.targetGroups[arn]
+	if !ok {
+		return nil, apiError(elbv2.ErrCodeTargetGroupNotFoundException, "Target group '%s' not found", arn)
+	}
+	if len(input.Targets) == 0 {
+		return nil, apiError("ValidationError", "At least one target must be specified")
+	}
+	for _, target := range input.Targets {
+		id := aws.StringValue(target.Id)
+		instance := reg.instance(id)
+		if instance == nil || !live(instance) || aws.StringValue(instance.VpcId) != *group.VpcId {
+			return nil, apiError(elbv2.ErrCodeInvalidTargetException, "The following targets are not in a running state and cannot be registered: '%s'", id)
+		}
+		port := group.Port
+		if target.Port != nil {
+			port = target.Port
+		}
+		registered := false
+		for _, existing := range reg.targets[arn] {
+			if *existing.Id == id && *existing.Port == *port {
+				registered = true
+			}
+		}
+		if !registered {
+			reg.targets[arn] = append(reg.targets[arn], &elbv2.TargetDescription{Id: aws.String(id), Port: aws.Int64(*port)})
+		}
+	}
+	return &elbv2.RegisterTargetsOutput{}, nil
+}
+
+// DescribeTargetHealthWithContext describes the targets registered with the target group, the targets running are reported healthy and the rest unused.
+func (e *ELBV2) DescribeTargetHealthWithContext(ctx aws.Context, input *elbv2.DescribeTargetHealthInput, _ ...request.Option) (*elbv2.DescribeTargetHealthOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.cloud.enter(ctx, "DescribeTargetHealth", e.region)
+	if err != nil {
+		return nil, err
+	}
+
+	arn := aws.StringValue(input.TargetGroupArn)
+	if _, ok := reg.targetGroups[arn]; !ok {
+		return nil, apiError(elbv2.ErrCodeTargetGroupNotFoundException, "Target group '%s' not found", arn)
+	}
+	out := &elbv2.DescribeTargetHealthOutput{TargetHealthDescriptions: make([]*elbv2.TargetHealthDescription, 0)}
+	for _, target := range reg.targets[arn] {
+		state := elbv2.TargetHealthStateEnumUnused
+		if instance := reg.instance(*target.Id); instance != nil && *instance.State.Name == ec2.InstanceStateNameRunning {
+			state = elbv2.TargetHealthStateEnumHealthy
+		}
+		out.TargetHealthDescriptions = append(out.TargetHealthDescriptions, &elbv2.TargetHealthDescription{
+			Target:       awsutil.CopyOf(target).(*elbv2.TargetDescription),
+			TargetHealth: &elbv2.TargetHealth{State: aws.String(state)},
+		})
+	}
+	return out, nil
+}
+
+// CreateListenerWithContext creates the listener of loadbalancer forwarding to the target group, a target group can be used by only one loadbalancer.
+func (e *ELBV2) CreateListenerWithContext(ctx aws.Context, input *elbv2.CreateListenerInput, _ ...request.Option) (*elbv2.CreateListenerOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.cloud.enter(ctx, "CreateListener", e.region)
+	if err != nil {
+		return nil, err
+	}
+
+	lbArn := aws.StringValue(input.LoadBalancerArn)
+	lb, ok := reg.appLbs[lbArn]
+	if !ok {
+		return nil, apiError(elbv2.ErrCodeLoadBalancerNotFoundException, "Load balancer '%s' not found", lbArn)
+	}
+	for _, listener := range reg.listeners {
+		if *listener.LoadBalancerArn == lbArn && aws.Int64Value(listener.Port) == aws.Int64Value(input.Port) {
+			return nil, apiError(elbv2.ErrCodeDuplicateListenerException, "A listener already exists on this port for this load balancer '%s'", lbArn)
+		}
+	}
+	if aws.StringValue(input.Protocol) == elbv2.ProtocolEnumHttps && len(input.Certificates) == 0 {
+		return nil, apiError(elbv2.ErrCodeCertificateNotFoundException, "A certificate must be specified for HTTPS listeners")
+	}
+	if len(input.DefaultActions) == 0 {
+		return nil, apiError("ValidationError", "A default action must be specified")
+	}
+	groups := make([]*elbv2.TargetGroup, 0)
+	for _, action := range input.DefaultActions {
+		group, ok := reg.targetGroups[aws.StringValue(action.TargetGroupArn)]
+		if !ok {
+			return nil, apiError(elbv2.ErrCodeTargetGroupNotFoundException, "Target group '%s' not found", aws.StringValue(action.TargetGroupArn))
+		}
+		if len(group.LoadBalancerArns) != 0 && !contains(aws.StringValueSlice(group.LoadBalancerArns), lbArn) {
+			return nil, apiError(elbv2.ErrCodeTargetGroupAssociationLimitException, "The following target groups cannot be associated with more than one load balancer: %s", *group.TargetGroupArn)
+		}
+		groups = append(groups, group)
+	}
+
+	e.cloud.seq++
+	listener := awsutil.CopyOf(&elbv2.Listener{
+		ListenerArn:     aws.String(e.arn("listener/app/" + *lb.LoadBalancerName)),
+		LoadBalancerArn: aws.String(lbArn),
+		Port:            input.Port,
+		Protocol:        input.Protocol,
+		Certificates:    input.Certificates,
+		SslPolicy:       input.SslPolicy,
+		DefaultActions:  input.DefaultActions,
+	}).(*elbv2.Listener)
+	reg.listeners[*listener.ListenerArn] = listener
+	for _, group := range groups {
+		if !contains(aws.StringValueSlice(group.LoadBalancerArns), lbArn) {
+			group.LoadBalancerArns = append(group.LoadBalancerArns, aws.String(lbArn))
+		}
+	}
+	return &elbv2.CreateListenerOutput{Listeners: []*elbv2.Listener{awsutil.CopyOf(listener).(*elbv2.Listener)}}, nil
+}
+
+// DescribeListenersWithContext describes the listeners selected either by its ARNs or the loadbalancer they belong to.
+func (e *ELBV2) DescribeListenersWithContext(ctx aws.Context, input *elbv2.DescribeListenersInput, _ ...request.Option) (*elbv2.DescribeListenersOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.cloud.enter(ctx, "DescribeListeners", e.region)
+	if err != nil {
+		return nil, err
+	}
+
+	if arn := aws.StringValue(input.LoadBalancerArn); arn != "" {
+		if _, ok := reg.appLbs[arn]; !ok {
+			return &elbv2.DescribeListenersOutput{}, apiError(elbv2.ErrCodeLoadBalancerNotFoundException, "Load balancer '%s' not found", arn)
+		}
+	}
+	for _, arn := range aws.StringValueSlice(input.ListenerArns) {
+		if _, ok := reg.listeners[arn]; !ok {
+			return &elbv2.DescribeListenersOutput{}, apiError(elbv2.ErrCodeListenerNotFoundException, "One or more listeners not found")
+		}
+	}
+	arns := make([]string, 0)
+	for arn := range reg.listeners {
+		arns = append(arns, arn)
+	}
+	out := &elbv2.DescribeListenersOutput{Listeners: make([]*elbv2.Listener, 0)}
+	for _, arn := range sortedKeys(arns) {
+		listener := reg.listeners[arn]
+		if len(input.ListenerArns) != 0 && !contains(aws.StringValueSlice(input.ListenerArns), arn) {
+			continue
+		}
+		if input.LoadBalancerArn != nil && *listener.LoadBalancerArn != *input.LoadBalancerArn {
+			continue
+		}
+		out.Listeners = append(out.Listeners, awsutil.CopyOf(listener).(*elbv2.Listener))
+	}
+	return out, nil
+}
+
+// DeleteListenerWithContext deletes the listener, the target groups used only by the listener are released from the loadbalancer.
+func (e *ELBV2) DeleteListenerWithContext(ctx aws.Context, input *elbv2.DeleteListenerInput, _ ...request.Option) (*elbv2.DeleteListenerOutput, error) {
+	e.cloud.mu.Lock()
+	defer e.cloud.mu.Unlock()
+	reg, err := e.cloud.enter(ctx, "DeleteListener", e.region)
+	if err != nil {
+		return nil, err
+	}
+
+	arn := aws.StringValue(input.ListenerArn)
+	listener, ok := reg.listeners[arn]
+	if !ok {
+		return &elbv2.DeleteListenerOutput{}, apiError(elbv2.ErrCodeListenerNotFoundException, "Listener '%s' not found", arn)
+	}
+	delete(reg.listeners, arn)
+	for _, group := range reg.targetGroups {
+		if !reg.forwards(*listener.LoadBalancerArn, *group.TargetGroupArn) {
+			group.LoadBalancerArns = aws.StringSlice(remove(aws.StringValueSlice(group.LoadBalancerArns), *listener.LoadBalancerArn))
+		}
+	}
+	return &elbv2.DeleteListenerOutput{}, nil
+}
+
+// forwards reports whether any listener of the loadbalancer forwards to the target group.
+func (r *region) forwards(lbArn, groupArn string) bool {
+	for _, listener := range r.listeners {
+		if *listener.LoadBalancerArn != lbArn {
+			continue
+		}
+		for _, action := range listener.DefaultActions {
+			if aws.StringValue(action.TargetGroupArn) == groupArn {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func remove(list []string, value string) []string {
+	filtered := make([]string, 0)
+	for _, item := range list {
+		if item != value {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
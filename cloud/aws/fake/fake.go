@@ -0,0 +1,320 @@
+// Package awsfake is an in-memory, stateful implementation of the EC2, ELB and ELBV2 apis of aws.
+// It lets the methods of cloud/aws/interface and cloud/aws/operations to be exercised without an account of aws,
+// pass the Cloud as Clients of EstablishConnectionInput (or as the Client of the cloud while calling cloudoperations).
+//
+// The fake models VPCs along with their secondary CIDR blocks, subnets, internet gateways, route tables, security groups, instances along with their attributes, ebs volumes along with their attachments, images, snapshots,
+// key pairs, elastic ips, spot prices and requests, nat gateways, network interfaces, classic/application loadbalancers, target groups along with their targets and listeners along with the dependencies between them,
+// and reports the failures with the same error codes aws does (ex: InvalidVpcID.NotFound, DependencyViolation).
+// The Describe calls are paginated as in aws when a page size is requested (MaxResults/NextToken, PageSize/Marker).
+// The requests of ec2 made with DryRun are answered with DryRunOperation without changing the state, use FailNext to deny them.
+// The apis which are not modelled panics when called, as the embedded interfaces are left nil.
+package awsfake
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+)
+
+// OwnerID is the account ID with which all the resources of the fake are owned.
+const OwnerID = "000000000000"
+
+// Cloud is the in-memory aws, it holds the state of all the regions and implements neuronaws.Clients.
+// A Cloud is safe for concurrent use.
+type Cloud struct {
+	mu       sync.Mutex
+	regions  map[string]*region
+	names    []string
+	seq      int
+	now      func() time.Time
+	failures map[string][]error
+	calls    map[string]int
+	disabled map[string]bool
+}
+
+// New returns a new Cloud with the regions passed, us-east-1 is used if none are passed.
+// Every region gets the availability zones a and b (ex: us-east-1a, us-east-1b).
+func New(regions ...string) *Cloud {
+	if len(regions) == 0 {
+		regions = []string{"us-east-1"}
+	}
+	cloud := &Cloud{
+		regions:  make(map[string]*region),
+		failures: make(map[string][]error),
+		calls:    make(map[string]int),
+		disabled: make(map[string]bool),
+		now: func() time.Time {
+			return time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+		},
+	}
+	for _, name := range regions {
+		cloud.regions[name] = newRegion(name)
+		cloud.names = append(cloud.names, name)
+	}
+	return cloud
+}
+
+// SetClock replaces the clock used to stamp the resources created (launch time, creation date etc.), defaults to 2019-01-01.
+func (c *Cloud) SetClock(now func() time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// FailNext makes the next call to the operation passed (ex: "CreateSubnet", "DeleteLoadBalancer") fail with the error passed.
+// Calling it multiple times queues the errors, which are returned one per call. A nil error lets the call through,
+// hence the later calls can be failed by queueing nil for the ones before.
+func (c *Cloud) FailNext(operation string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures[operation] = append(c.failures[operation], err)
+}
+
+// DisableRegion makes every call to the region passed fail with AuthFailure, as aws does for the regions the account did not opt in to.
+// The region is still listed by DescribeRegions.
+func (c *Cloud) DisableRegion(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.disabled[name] = true
+}
+
+// Calls returns the number of times the operation passed was called, including the calls which failed.
+func (c *Cloud) Calls(operation string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[operation]
+}
+
+// EC2 returns the client of EC2 for the region passed.
+func (c *Cloud) EC2(region string) ec2iface.EC2API {
+	return &EC2{cloud: c, region: region}
+}
+
+// ELB returns the client of classic loadbalancer for the region passed.
+func (c *Cloud) ELB(region string) elbiface.ELBAPI {
+	return &ELB{cloud: c, region: region}
+}
+
+// ELBV2 returns the client of application loadbalancer for the region passed.
+func (c *Cloud) ELBV2(region string) elbv2iface.ELBV2API {
+	return &ELBV2{cloud: c, region: region}
+}
+
+// enter has to be called with lock held by every api, it validates the context and the region
+// and returns the failures queued for the operation.
+func (c *Cloud) enter(ctx context.Context, operation, name string) (*region, error) {
+	c.calls[operation]++
+	if ctx != nil && ctx.Err() != nil {
+		return nil, awserr.New(request.CanceledErrorCode, "request context canceled", ctx.Err())
+	}
+	if queued := c.failures[operation]; len(queued) != 0 {
+		c.failures[operation] = queued[1:]
+		if queued[0] != nil {
+			return nil, queued[0]
+		}
+	}
+	if c.disabled[name] {
+		return nil, awserr.New("AuthFailure", "AWS was not able to validate the provided access credentials", nil)
+	}
+	reg, ok := c.regions[name]
+	if !ok {
+		return nil, awserr.New("UnknownEndpoint", fmt.Sprintf("could not resolve endpoint for the region '%s'", name), nil)
+	}
+	return reg, nil
+}
+
+// id generates the next ID of the resource with the prefix passed, the IDs are sortable in the order of creation.
+func (c *Cloud) id(prefix string) string {
+	c.seq++
+	return fmt.Sprintf("%s-%017x", prefix, c.seq)
+}
+
+type region struct {
+	name         string
+	zones        []string
+	vpcs         map[string]*ec2.Vpc
+	subnets      map[string]*ec2.Subnet
+	igws         map[string]*ec2.InternetGateway
+	routeTables  map[string]*ec2.RouteTable
+	groups       map[string]*ec2.SecurityGroup
+	reservations []*ec2.Reservation
+	images       map[string]*ec2.Image
+	snapshots    map[string]*ec2.Snapshot
+	volumes      map[string]*ec2.Volume
+	volumeMods   map[string]*ec2.VolumeModification
+	ipSeq        map[string]int
+	publicIPSeq  int
+	publicIPs    map[string]bool
+	classicLbs   map[string]*elb.LoadBalancerDescription
+	appLbs       map[string]*elbv2.LoadBalancer
+	targetGroups map[string]*elbv2.TargetGroup
+	targets      map[string][]*elbv2.TargetDescription
+	listeners    map[string]*elbv2.Listener
+	addresses    map[string]*ec2.Address
+	addressSeq   int
+	natGateways  map[string]*ec2.NatGateway
+	interfaces   map[string]*ec2.NetworkInterface
+	releasing    map[string]int
+	keyPairs     map[string]*ec2.KeyPairInfo
+	userData     map[string]string
+	protected    map[string]bool
+	spotPrices   map[string]float64
+	spotRequests map[string]*ec2.SpotInstanceRequest
+}
+
+func newRegion(name string) *region {
+	return &region{
+		name:         name,
+		zones:        []string{name + "a", name + "b"},
+		vpcs:         make(map[string]*ec2.Vpc),
+		subnets:      make(map[string]*ec2.Subnet),
+		igws:         make(map[string]*ec2.InternetGateway),
+		routeTables:  make(map[string]*ec2.RouteTable),
+		groups:       make(map[string]*ec2.SecurityGroup),
+		images:       make(map[string]*ec2.Image),
+		snapshots:    make(map[string]*ec2.Snapshot),
+		volumes:      make(map[string]*ec2.Volume),
+		volumeMods:   make(map[string]*ec2.VolumeModification),
+		ipSeq:        make(map[string]int),
+		publicIPs:    make(map[string]bool),
+		classicLbs:   make(map[string]*elb.LoadBalancerDescription),
+		appLbs:       make(map[string]*elbv2.LoadBalancer),
+		targetGroups: make(map[string]*elbv2.TargetGroup),
+		targets:      make(map[string][]*elbv2.TargetDescription),
+		listeners:    make(map[string]*elbv2.Listener),
+		addresses:    make(map[string]*ec2.Address),
+		natGateways:  make(map[string]*ec2.NatGateway),
+		interfaces:   make(map[string]*ec2.NetworkInterface),
+		releasing:    make(map[string]int),
+		keyPairs:     make(map[string]*ec2.KeyPairInfo),
+		userData:     make(map[string]string),
+		protected:    make(map[string]bool),
+		spotPrices:   make(map[string]float64),
+		spotRequests: make(map[string]*ec2.SpotInstanceRequest),
+	}
+}
+
+// apiError returns the error in the form aws sdk returns it.
+func apiError(code, format string, a ...interface{}) error {
+	return awserr.NewRequestFailure(awserr.New(code, fmt.Sprintf(format, a...), nil), 400, "fake-request-id")
+}
+
+// dryRun returns the error aws answers with, when the request is made with DryRun and the caller is permitted to perform it.
+func dryRun(flag *bool) error {
+	if aws.BoolValue(flag) {
+		return awserr.NewRequestFailure(awserr.New("DryRunOperation", "Request would have succeeded, but DryRun flag is set.", nil), 412, "fake-request-id")
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of the map passed in the order of its creation.
+func sortedKeys(keys []string) []string {
+	sort.Strings(keys)
+	return keys
+}
+
+// paginate returns the bounds of the page selected by the token and size passed, out of the total results along with the token of the next page.
+// The token is the offset of the page and the size has to be within the bounds passed, else the request fails with the code passed as in aws.
+// The whole of the rest of the results makes the page if the size is not set.
+func paginate(total int, size *int64, token *string, min, max int64, code string) (int, int, *string, error) {
+	start, end := 0, total
+	if value := aws.StringValue(token); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 || offset > total {
+			return 0, 0, nil, apiError(code, "The pagination token '%s' is invalid", value)
+		}
+		start = offset
+	}
+	if size == nil {
+		return start, end, nil, nil
+	}
+	if *size < min || *size > max {
+		return 0, 0, nil, apiError(code, "Value ( %d ) for parameter of page size is invalid. Expecting a value between %d and %d", *size, min, max)
+	}
+	if start+int(*size) < total {
+		end = start + int(*size)
+		return start, end, aws.String(strconv.Itoa(end)), nil
+	}
+	return start, end, nil, nil
+}
+
+// attributes are the values of a resource against which the filters are matched.
+type attributes map[string][]string
+
+// matches reports whether the resource with the attributes and tags passed satisfies all the filters.
+func matches(filters []*ec2.Filter, attrs attributes, tags []*ec2.Tag) (bool, error) {
+	for _, filter := range filters {
+		if filter == nil || filter.Name == nil {
+			return false, apiError("InvalidParameterValue", "The filter name cannot be empty")
+		}
+		name := *filter.Name
+		var values []string
+		switch {
+		case strings.HasPrefix(name, "tag:"):
+			for _, tag := range tags {
+				if *tag.Key == strings.TrimPrefix(name, "tag:") {
+					values = append(values, *tag.Value)
+				}
+			}
+		case name == "tag-key":
+			for _, tag := range tags {
+				values = append(values, *tag.Key)
+			}
+		default:
+			value, ok := attrs[name]
+			if !ok {
+				return false, apiError("InvalidParameterValue", "The filter '%s' is invalid", name)
+			}
+			values = value
+		}
+		if !anyMatch(filter.Values, values) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func anyMatch(patterns []*string, values []string) bool {
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if ok, _ := path.Match(*pattern, value); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func setTag(tags []*ec2.Tag, key, value string) []*ec2.Tag {
+	for _, tag := range tags {
+		if *tag.Key == key {
+			tag.Value = &value
+			return tags
+		}
+	}
+	return append(tags, &ec2.Tag{Key: &key, Value: &value})
+}
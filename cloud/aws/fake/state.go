@@ -0,0 +1,629 @@
+package awsfake
+
+import (
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// selectIDs returns the IDs requested after validating its existence, all the IDs are returned if none were requested.
+func (r *region) selectIDs(requested []*string, all []string, code, kind string) ([]string, error) {
+	if len(requested) == 0 {
+		return all, nil
+	}
+	ids := make([]string, 0)
+	for _, id := range aws.StringValueSlice(requested) {
+		if !contains(all, id) {
+			return nil, apiError(code, "The %s ID '%s' does not exist", kind, id)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (r *region) vpcIDs() []string {
+	ids := make([]string, 0)
+	for id := range r.vpcs {
+		ids = append(ids, id)
+	}
+	return sortedKeys(ids)
+}
+
+func (r *region) subnetIDs() []string {
+	ids := make([]string, 0)
+	for id := range r.subnets {
+		ids = append(ids, id)
+	}
+	return sortedKeys(ids)
+}
+
+func (r *region) igwIDs() []string {
+	ids := make([]string, 0)
+	for id := range r.igws {
+		ids = append(ids, id)
+	}
+	return sortedKeys(ids)
+}
+
+func (r *region) routeTableIDs() []string {
+	ids := make([]string, 0)
+	for id := range r.routeTables {
+		ids = append(ids, id)
+	}
+	return sortedKeys(ids)
+}
+
+func (r *region) groupIDs() []string {
+	ids := make([]string, 0)
+	for id := range r.groups {
+		ids = append(ids, id)
+	}
+	return sortedKeys(ids)
+}
+
+func (r *region) imageIDs() []string {
+	ids := make([]string, 0)
+	for id := range r.images {
+		ids = append(ids, id)
+	}
+	return sortedKeys(ids)
+}
+
+func (r *region) snapshotIDs() []string {
+	ids := make([]string, 0)
+	for id := range r.snapshots {
+		ids = append(ids, id)
+	}
+	return sortedKeys(ids)
+}
+
+func (r *region) volumeIDs() []string {
+	ids := make([]string, 0)
+	for id := range r.volumes {
+		ids = append(ids, id)
+	}
+	return sortedKeys(ids)
+}
+
+func (r *region) addressIDs() []string {
+	ids := make([]string, 0)
+	for id := range r.addresses {
+		ids = append(ids, id)
+	}
+	return sortedKeys(ids)
+}
+
+func (r *region) natGatewayIDs() []string {
+	ids := make([]string, 0)
+	for id := range r.natGateways {
+		ids = append(ids, id)
+	}
+	return sortedKeys(ids)
+}
+
+func (r *region) interfaceIDs() []string {
+	ids := make([]string, 0)
+	for id := range r.interfaces {
+		ids = append(ids, id)
+	}
+	return sortedKeys(ids)
+}
+
+func (r *region) keyPairNames() []string {
+	names := make([]string, 0)
+	for name := range r.keyPairs {
+		names = append(names, name)
+	}
+	return sortedKeys(names)
+}
+
+// instances returns all the instances of the region in the order of its launch, including the terminated ones.
+func (r *region) instances() []*ec2.Instance {
+	instances := make([]*ec2.Instance, 0)
+	for _, reservation := range r.reservations {
+		instances = append(instances, reservation.Instances...)
+	}
+	return instances
+}
+
+func (r *region) instance(id string) *ec2.Instance {
+	for _, instance := range r.instances() {
+		if *instance.InstanceId == id {
+			return instance
+		}
+	}
+	return nil
+}
+
+func live(instance *ec2.Instance) bool {
+	return *instance.State.Name != ec2.InstanceStateNameTerminated
+}
+
+func (r *region) newRouteTable(c *Cloud, vpc *ec2.Vpc) *ec2.RouteTable {
+	table := &ec2.RouteTable{
+		RouteTableId: aws.String(c.id("rtb")),
+		VpcId:        vpc.VpcId,
+		OwnerId:      aws.String(OwnerID),
+		Routes:       make([]*ec2.Route, 0, len(vpc.CidrBlockAssociationSet)),
+		Associations: make([]*ec2.RouteTableAssociation, 0),
+	}
+	for _, association := range vpc.CidrBlockAssociationSet {
+		table.Routes = append(table.Routes, localRoute(association.CidrBlock))
+	}
+	r.routeTables[*table.RouteTableId] = table
+	return table
+}
+
+// localRoute is the route of the CIDR block of the vpc, which every route table of the vpc has.
+func localRoute(cidr *string) *ec2.Route {
+	return &ec2.Route{
+		DestinationCidrBlock: aws.String(*cidr),
+		GatewayId:            aws.String("local"),
+		Origin:               aws.String(ec2.RouteOriginCreateRouteTable),
+		State:                aws.String(ec2.RouteStateActive),
+	}
+}
+
+// blockOf returns the CIDR block of the vpc which holds the network passed, nil if none of them does.
+func blockOf(vpc *ec2.Vpc, network *net.IPNet) *net.IPNet {
+	for _, association := range vpc.CidrBlockAssociationSet {
+		_, block, _ := net.ParseCIDR(*association.CidrBlock)
+		if block.Contains(network.IP) {
+			return block
+		}
+	}
+	return nil
+}
+
+func (r *region) newSecurityGroup(c *Cloud, vpc *ec2.Vpc, name, description string) *ec2.SecurityGroup {
+	group := &ec2.SecurityGroup{
+		GroupId:       aws.String(c.id("sg")),
+		GroupName:     aws.String(name),
+		Description:   aws.String(description),
+		VpcId:         vpc.VpcId,
+		OwnerId:       aws.String(OwnerID),
+		IpPermissions: make([]*ec2.IpPermission, 0),
+		IpPermissionsEgress: []*ec2.IpPermission{{
+			IpProtocol: aws.String("-1"),
+			IpRanges:   []*ec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}},
+		}},
+	}
+	r.groups[*group.GroupId] = group
+	return group
+}
+
+func (r *region) igwOfVpc(vpcID string) *ec2.InternetGateway {
+	for _, id := range r.igwIDs() {
+		for _, attachment := range r.igws[id].Attachments {
+			if *attachment.VpcId == vpcID {
+				return r.igws[id]
+			}
+		}
+	}
+	return nil
+}
+
+func isMain(table *ec2.RouteTable) bool {
+	for _, association := range table.Associations {
+		if aws.BoolValue(association.Main) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *region) vpcHasDependencies(vpcID string) bool {
+	for _, subnet := range r.subnets {
+		if *subnet.VpcId == vpcID {
+			return true
+		}
+	}
+	if r.igwOfVpc(vpcID) != nil {
+		return true
+	}
+	for _, group := range r.groups {
+		if *group.VpcId == vpcID && *group.GroupName != "default" {
+			return true
+		}
+	}
+	for _, table := range r.routeTables {
+		if *table.VpcId == vpcID && !isMain(table) {
+			return true
+		}
+	}
+	for _, lb := range r.classicLbs {
+		if aws.StringValue(lb.VPCId) == vpcID {
+			return true
+		}
+	}
+	for _, lb := range r.appLbs {
+		if aws.StringValue(lb.VpcId) == vpcID {
+			return true
+		}
+	}
+	for _, group := range r.targetGroups {
+		if aws.StringValue(group.VpcId) == vpcID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *region) subnetHasDependencies(subnetID string) bool {
+	for _, instance := range r.instances() {
+		if live(instance) && aws.StringValue(instance.SubnetId) == subnetID {
+			return true
+		}
+	}
+	for _, nat := range r.natGateways {
+		if *nat.SubnetId == subnetID && *nat.State != ec2.NatGatewayStateDeleted {
+			return true
+		}
+	}
+	for _, eni := range r.interfaces {
+		if *eni.SubnetId == subnetID {
+			return true
+		}
+	}
+	for _, lb := range r.classicLbs {
+		if contains(aws.StringValueSlice(lb.Subnets), subnetID) {
+			return true
+		}
+	}
+	for _, lb := range r.appLbs {
+		for _, zone := range lb.AvailabilityZones {
+			if aws.StringValue(zone.SubnetId) == subnetID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *region) groupInUse(groupID string) bool {
+	for _, instance := range r.instances() {
+		if !live(instance) {
+			continue
+		}
+		for _, group := range instance.SecurityGroups {
+			if *group.GroupId == groupID {
+				return true
+			}
+		}
+	}
+	for _, eni := range r.interfaces {
+		for _, group := range eni.Groups {
+			if *group.GroupId == groupID {
+				return true
+			}
+		}
+	}
+	for _, lb := range r.classicLbs {
+		if contains(aws.StringValueSlice(lb.SecurityGroups), groupID) {
+			return true
+		}
+	}
+	for _, lb := range r.appLbs {
+		if contains(aws.StringValueSlice(lb.SecurityGroups), groupID) {
+			return true
+		}
+	}
+	// the rules of the other groups referring the group.
+	for id, group := range r.groups {
+		if id == groupID {
+			continue
+		}
+		for _, grant := range split(append(append([]*ec2.IpPermission{}, group.IpPermissions...), group.IpPermissionsEgress...)) {
+			if len(grant.UserIdGroupPairs) != 0 && aws.StringValue(grant.UserIdGroupPairs[0].GroupId) == groupID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tagsOf returns the reference to the tags of the resource, nil is returned if the resource does not exist.
+func (r *region) tagsOf(id string) *[]*ec2.Tag {
+	if vpc, ok := r.vpcs[id]; ok {
+		return &vpc.Tags
+	}
+	if subnet, ok := r.subnets[id]; ok {
+		return &subnet.Tags
+	}
+	if igw, ok := r.igws[id]; ok {
+		return &igw.Tags
+	}
+	if table, ok := r.routeTables[id]; ok {
+		return &table.Tags
+	}
+	if group, ok := r.groups[id]; ok {
+		return &group.Tags
+	}
+	if image, ok := r.images[id]; ok {
+		return &image.Tags
+	}
+	if snapshot, ok := r.snapshots[id]; ok {
+		return &snapshot.Tags
+	}
+	if volume, ok := r.volumes[id]; ok {
+		return &volume.Tags
+	}
+	if instance := r.instance(id); instance != nil {
+		return &instance.Tags
+	}
+	if address, ok := r.addresses[id]; ok {
+		return &address.Tags
+	}
+	if nat, ok := r.natGateways[id]; ok {
+		return &nat.Tags
+	}
+	if eni, ok := r.interfaces[id]; ok {
+		return &eni.TagSet
+	}
+	return nil
+}
+
+// notFound returns the error aws reports when the resource with the ID passed does not exist.
+func (r *region) notFound(id string) error {
+	codes := map[string]string{
+		"vpc":      "InvalidVpcID.NotFound",
+		"subnet":   "InvalidSubnetID.NotFound",
+		"igw":      "InvalidInternetGatewayID.NotFound",
+		"rtb":      "InvalidRouteTableID.NotFound",
+		"sg":       "InvalidGroup.NotFound",
+		"i":        "InvalidInstanceID.NotFound",
+		"ami":      "InvalidAMIID.NotFound",
+		"snap":     "InvalidSnapshot.NotFound",
+		"eipalloc": "InvalidAllocationID.NotFound",
+		"nat":      "NatGatewayNotFound",
+		"eni":      "InvalidNetworkInterfaceID.NotFound",
+	}
+	if index := strings.LastIndex(id, "-"); index > 0 {
+		if code, ok := codes[id[:index]]; ok {
+			return apiError(code, "The ID '%s' does not exist", id)
+		}
+	}
+	return apiError("InvalidID", "The ID '%s' is not valid", id)
+}
+
+// authorize adds the permissions to the rules, it fails if any of the permission already exists.
+// The rules are tracked by their grants as aws does, hence a permission is a duplicate even if only one of its peers is authorized already.
+func (r *region) authorize(rules, permissions []*ec2.IpPermission) ([]*ec2.IpPermission, error) {
+	granted := split(rules)
+	requested, err := r.grants(permissions)
+	if err != nil {
+		return nil, err
+	}
+	for _, permission := range requested {
+		if grantIndex(granted, permission) >= 0 {
+			return nil, apiError("InvalidPermission.Duplicate", "the specified rule \"peer: %s, %s\" already exists",
+				peerOf(permission), aws.StringValue(permission.IpProtocol))
+		}
+		granted = append(granted, permission)
+	}
+	return join(granted), nil
+}
+
+// revoke removes the permissions from the rules, it fails if any of the permission does not exist.
+func (r *region) revoke(rules, permissions []*ec2.IpPermission) ([]*ec2.IpPermission, error) {
+	granted := split(rules)
+	requested, err := r.grants(permissions)
+	if err != nil {
+		return nil, err
+	}
+	for _, permission := range requested {
+		index := grantIndex(granted, permission)
+		if index < 0 {
+			return nil, apiError("InvalidPermission.NotFound", "The specified rule does not exist in this security group: \"peer: %s, %s\"",
+				peerOf(permission), aws.StringValue(permission.IpProtocol))
+		}
+		granted = append(granted[:index], granted[index+1:]...)
+	}
+	return join(granted), nil
+}
+
+// describe replaces the descriptions of the rules with the ones of the permissions, it fails if any of the permission does not exist.
+func (r *region) describe(rules, permissions []*ec2.IpPermission) ([]*ec2.IpPermission, error) {
+	granted := split(rules)
+	requested, err := r.grants(permissions)
+	if err != nil {
+		return nil, err
+	}
+	for _, permission := range requested {
+		index := grantIndex(granted, permission)
+		if index < 0 {
+			return nil, apiError("InvalidPermission.NotFound", "The specified rule does not exist in this security group: \"peer: %s, %s\"",
+				peerOf(permission), aws.StringValue(permission.IpProtocol))
+		}
+		granted[index] = permission
+	}
+	return join(granted), nil
+}
+
+// grants validates the permissions and splits them into grants, the permissions to a single peer (ipv4 range, ipv6 range or security group).
+// The protocols are reported by their names as aws does and the ports are dropped for the protocols which do not have them.
+func (r *region) grants(permissions []*ec2.IpPermission) ([]*ec2.IpPermission, error) {
+	validated := make([]*ec2.IpPermission, 0, len(permissions))
+	for _, permission := range permissions {
+		protocol, from, to, err := protocolPorts(permission)
+		if err != nil {
+			return nil, err
+		}
+		if len(permission.IpRanges)+len(permission.Ipv6Ranges)+len(permission.UserIdGroupPairs) == 0 {
+			return nil, apiError("MissingParameter", "The request must contain the parameter ipRanges, ipv6Ranges or groups")
+		}
+		for _, ipRange := range permission.IpRanges {
+			if cidr := aws.StringValue(ipRange.CidrIp); !isCIDR(cidr, false) {
+				return nil, apiError("InvalidParameterValue", "CIDR block %s is malformed", cidr)
+			}
+		}
+		for _, ipRange := range permission.Ipv6Ranges {
+			if cidr := aws.StringValue(ipRange.CidrIpv6); !isCIDR(cidr, true) {
+				return nil, apiError("InvalidParameterValue", "CIDR block %s is malformed", cidr)
+			}
+		}
+		pairs := make([]*ec2.UserIdGroupPair, 0, len(permission.UserIdGroupPairs))
+		for _, pair := range permission.UserIdGroupPairs {
+			if _, ok := r.groups[aws.StringValue(pair.GroupId)]; !ok {
+				return nil, apiError("InvalidGroup.NotFound", "The security group '%s' does not exist", aws.StringValue(pair.GroupId))
+			}
+			pairs = append(pairs, &ec2.UserIdGroupPair{GroupId: pair.GroupId, UserId: aws.String(OwnerID), Description: pair.Description})
+		}
+		validated = append(validated, &ec2.IpPermission{
+			IpProtocol: aws.String(protocol), FromPort: from, ToPort: to,
+			IpRanges: permission.IpRanges, Ipv6Ranges: permission.Ipv6Ranges, UserIdGroupPairs: pairs,
+		})
+	}
+	return split(validated), nil
+}
+
+// protocolPorts returns the name of the protocol of the permission along with its ports, the ports of tcp/udp has to be a valid range
+// and the ones of icmp are its type and code (-1 for all).
+func protocolPorts(permission *ec2.IpPermission) (string, *int64, *int64, error) {
+	protocol := strings.ToLower(aws.StringValue(permission.IpProtocol))
+	names := map[string]string{"6": "tcp", "17": "udp", "1": "icmp", "58": "icmpv6"}
+	if name, ok := names[protocol]; ok {
+		protocol = name
+	}
+	from, to := aws.Int64Value(permission.FromPort), aws.Int64Value(permission.ToPort)
+	switch protocol {
+	case "tcp", "udp":
+		if permission.FromPort == nil || permission.ToPort == nil || from < 0 || to > 65535 || from > to {
+			return "", nil, nil, apiError("InvalidParameterValue", "Invalid value for portRange. Must specify both from and to ports with TCP/UDP.")
+		}
+	case "icmp", "icmpv6":
+		if from < -1 || from > 255 || to < -1 || to > 255 {
+			return "", nil, nil, apiError("InvalidParameterValue", "Invalid value for portRange. ICMP type and code must be between -1 and 255.")
+		}
+		if permission.FromPort == nil {
+			from, to = -1, -1
+		}
+	case "":
+		return "", nil, nil, apiError("MissingParameter", "The request must contain the parameter ipProtocol")
+	default:
+		if number, err := strconv.Atoi(protocol); protocol != "-1" && (err != nil || number < 0 || number > 255) {
+			return "", nil, nil, apiError("InvalidParameterValue", "Invalid value '%s' for IP protocol. Unknown protocol.", protocol)
+		}
+		return protocol, nil, nil, nil
+	}
+	return protocol, aws.Int64(from), aws.Int64(to), nil
+}
+
+func isCIDR(cidr string, ipv6 bool) bool {
+	ip, _, err := net.ParseCIDR(cidr)
+	return err == nil && (ip.To4() == nil) == ipv6
+}
+
+// split splits the permissions into grants, the permissions to a single peer.
+func split(permissions []*ec2.IpPermission) []*ec2.IpPermission {
+	grants := make([]*ec2.IpPermission, 0)
+	grant := func(permission *ec2.IpPermission) *ec2.IpPermission {
+		return &ec2.IpPermission{IpProtocol: permission.IpProtocol, FromPort: permission.FromPort, ToPort: permission.ToPort}
+	}
+	for _, permission := range permissions {
+		for _, ipRange := range permission.IpRanges {
+			single := grant(permission)
+			single.IpRanges = []*ec2.IpRange{awsutil.CopyOf(ipRange).(*ec2.IpRange)}
+			grants = append(grants, single)
+		}
+		for _, ipRange := range permission.Ipv6Ranges {
+			single := grant(permission)
+			single.Ipv6Ranges = []*ec2.Ipv6Range{awsutil.CopyOf(ipRange).(*ec2.Ipv6Range)}
+			grants = append(grants, single)
+		}
+		for _, pair := range permission.UserIdGroupPairs {
+			single := grant(permission)
+			single.UserIdGroupPairs = []*ec2.UserIdGroupPair{awsutil.CopyOf(pair).(*ec2.UserIdGroupPair)}
+			grants = append(grants, single)
+		}
+	}
+	return grants
+}
+
+// join merges the grants of the same protocol and ports into a permission, the way aws reports them.
+func join(grants []*ec2.IpPermission) []*ec2.IpPermission {
+	permissions := make([]*ec2.IpPermission, 0)
+	for _, grant := range grants {
+		var permission *ec2.IpPermission
+		for _, joined := range permissions {
+			if sameProtocolPorts(joined, grant) {
+				permission = joined
+				break
+			}
+		}
+		if permission == nil {
+			permission = &ec2.IpPermission{IpProtocol: grant.IpProtocol, FromPort: grant.FromPort, ToPort: grant.ToPort}
+			permissions = append(permissions, permission)
+		}
+		permission.IpRanges = append(permission.IpRanges, grant.IpRanges...)
+		permission.Ipv6Ranges = append(permission.Ipv6Ranges, grant.Ipv6Ranges...)
+		permission.UserIdGroupPairs = append(permission.UserIdGroupPairs, grant.UserIdGroupPairs...)
+	}
+	return permissions
+}
+
+func sameProtocolPorts(a, b *ec2.IpPermission) bool {
+	return aws.StringValue(a.IpProtocol) == aws.StringValue(b.IpProtocol) &&
+		reflect.DeepEqual(a.FromPort, b.FromPort) && reflect.DeepEqual(a.ToPort, b.ToPort)
+}
+
+// grantIndex returns the index of the grant to the peer of the permission passed, the descriptions are not compared. -1 is returned if there is none.
+func grantIndex(grants []*ec2.IpPermission, permission *ec2.IpPermission) int {
+	for index, grant := range grants {
+		if sameProtocolPorts(grant, permission) && peerOf(grant) == peerOf(permission) {
+			return index
+		}
+	}
+	return -1
+}
+
+// peerOf returns the peer of the grant, which is either the cidr or the ID of the security group.
+func peerOf(grant *ec2.IpPermission) string {
+	switch {
+	case len(grant.IpRanges) != 0:
+		return aws.StringValue(grant.IpRanges[0].CidrIp)
+	case len(grant.Ipv6Ranges) != 0:
+		return aws.StringValue(grant.Ipv6Ranges[0].CidrIpv6)
+	case len(grant.UserIdGroupPairs) != 0:
+		return aws.StringValue(grant.UserIdGroupPairs[0].GroupId)
+	}
+	return ""
+}
+
+// routableGateway validates that the gateway can be the target of the routes of the route table, it should be attached to the vpc of the table.
+func (r *region) routableGateway(table *ec2.RouteTable, gatewayID string) error {
+	igw, ok := r.igws[gatewayID]
+	if !ok {
+		return apiError("InvalidInternetGatewayID.NotFound", "The internetGateway ID '%s' does not exist", gatewayID)
+	}
+	if len(igw.Attachments) == 0 || *igw.Attachments[0].VpcId != *table.VpcId {
+		return apiError("InvalidParameterValue", "route table %s and network gateway %s belong to different networks", *table.RouteTableId, gatewayID)
+	}
+	return nil
+}
+
+// routeOf returns the route of the route table to the destination passed, the local routes are not returned as they cannot be modified.
+func routeOf(table *ec2.RouteTable, destination string) (*ec2.Route, error) {
+	for _, route := range table.Routes {
+		if aws.StringValue(route.DestinationCidrBlock) != destination {
+			continue
+		}
+		if aws.StringValue(route.GatewayId) == "local" {
+			return nil, apiError("InvalidParameterValue", "cannot remove local route %s in route table %s", destination, *table.RouteTableId)
+		}
+		return route, nil
+	}
+	return nil, apiError("InvalidRoute.NotFound", "no route with destination-cidr-block %s in route table %s", destination, *table.RouteTableId)
+}
+
+func boolString(value bool) string {
+	if value {
+		return "true"
+	}
+	return "false"
+}
@@ -0,0 +1,152 @@
+package neuronaws
+
+import (
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	err "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// AddressInput holds the required values to describe and manage the elastic ips of aws.
+type AddressInput struct {
+	// AllocationIds are the id's of the elastic ips.
+	AllocationIds []string
+	// AssociationId is the id of the association of elastic ip with instance or network interface, it is used while disassociating the elastic ip.
+	AssociationId string
+	// InstanceId is the id of the instance to which the elastic ip has to be associated.
+	InstanceId string
+	// NetworkInterfaceId is the id of the network interface to which the elastic ip has to be associated, it takes precedence over InstanceId.
+	NetworkInterfaceId string
+	// AllowReassociation allows the elastic ip already associated to be moved to the instance or network interface passed.
+	AllowReassociation bool
+	// Filters can be applied over the elastic ips to get more precise data about it.
+	Filters Filters
+}
+
+// AllocateAddress allocates an elastic ip for use with instances in vpc.
+func (sess *EstablishedSession) AllocateAddress(a *AddressInput) (*ec2.AllocateAddressOutput, error) {
+
+	if sess.Ec2 != nil {
+		input := &ec2.AllocateAddressInput{
+			Domain: aws.String("vpc"),
+		}
+		result, err := (sess.Ec2).AllocateAddressWithContext(sess.Context(), input)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return nil, err.InvalidSession()
+}
+
+// AssociateAddress associates the elastic ip selected with the network interface or the instance passed.
+func (sess *EstablishedSession) AssociateAddress(a *AddressInput) (*ec2.AssociateAddressOutput, error) {
+
+	if sess.Ec2 != nil {
+		if (a.AllocationIds != nil) && ((a.InstanceId != "") || (a.NetworkInterfaceId != "")) {
+			input := &ec2.AssociateAddressInput{
+				AllocationId:       aws.String(a.AllocationIds[0]),
+				AllowReassociation: aws.Bool(a.AllowReassociation),
+			}
+			if a.NetworkInterfaceId != "" {
+				input.NetworkInterfaceId = aws.String(a.NetworkInterfaceId)
+			} else {
+				input.InstanceId = aws.String(a.InstanceId)
+			}
+			result, err := (sess.Ec2).AssociateAddressWithContext(sess.Context(), input)
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+		return nil, err.New(err.InvalidInput, "You provided empty struct to AssociateAddress, this is not acceptable")
+	}
+	return nil, err.InvalidSession()
+}
+
+// DescribeAddresses fetches the information about the elastic ips selected either by its id's or the filters.
+func (sess *EstablishedSession) DescribeAddresses(a *AddressInput) (*ec2.DescribeAddressesOutput, error) {
+
+	if sess.Ec2 != nil {
+		if a.AllocationIds != nil {
+			input := &ec2.DescribeAddressesInput{
+				AllocationIds: aws.StringSlice(a.AllocationIds),
+			}
+			result, err := (sess.Ec2).DescribeAddressesWithContext(sess.Context(), input)
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+
+		if reflect.DeepEqual(a.Filters, Filters{}) {
+			return nil, err.New(err.InvalidInput, "You selected filters for DescribeAddresses and did not provide the values for them, this is not acceptable")
+		}
+		input := &ec2.DescribeAddressesInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String(a.Filters.Name),
+					Values: aws.StringSlice(a.Filters.Value),
+				},
+			},
+		}
+		result, err := (sess.Ec2).DescribeAddressesWithContext(sess.Context(), input)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return nil, err.InvalidSession()
+}
+
+// DescribeAllAddresses fetches all the elastic ips of the region, aws does not paginate the elastic ips.
+func (sess *EstablishedSession) DescribeAllAddresses(a *AddressInput) (*ec2.DescribeAddressesOutput, error) {
+
+	if sess.Ec2 != nil {
+		result, err := (sess.Ec2).DescribeAddressesWithContext(sess.Context(), &ec2.DescribeAddressesInput{})
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return nil, err.InvalidSession()
+}
+
+// DisassociateAddress disassociates the elastic ip from the instance or network interface it is associated with.
+func (sess *EstablishedSession) DisassociateAddress(a *AddressInput) error {
+
+	if sess.Ec2 != nil {
+		if a.AssociationId != "" {
+			input := &ec2.DisassociateAddressInput{
+				AssociationId: aws.String(a.AssociationId),
+			}
+			_, err := (sess.Ec2).DisassociateAddressWithContext(sess.Context(), input)
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+		return err.New(err.InvalidInput, "You provided empty struct to DisassociateAddress, this is not acceptable")
+	}
+	return err.InvalidSession()
+}
+
+// ReleaseAddress releases the elastic ip selected back to aws, the elastic ip has to be disassociated before releasing it.
+func (sess *EstablishedSession) ReleaseAddress(a *AddressInput) error {
+
+	if sess.Ec2 != nil {
+		if a.AllocationIds != nil {
+			input := &ec2.ReleaseAddressInput{
+				AllocationId: aws.String(a.AllocationIds[0]),
+			}
+			_, err := (sess.Ec2).ReleaseAddressWithContext(sess.Context(), input)
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+		return err.New(err.InvalidInput, "You provided empty struct to ReleaseAddress, this is not acceptable")
+	}
+	return err.InvalidSession()
+}
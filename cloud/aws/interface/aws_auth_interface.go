@@ -4,24 +4,36 @@
 package neuronaws
 
 import (
-	"fmt"
+	"context"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
 	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
+	"github.com/nikhilsbhat/neuron-cloudy/wait"
 )
 
 // EstablishedSession holds the session establihed to create appropriate resource in cloud aws
 type EstablishedSession struct {
 	//This will hold the session for all ec2 resource
-	Ec2 *ec2.EC2 `json:"Ec2,omitempty"`
+	Ec2 ec2iface.EC2API `json:"Ec2,omitempty"`
 	//This will hold the session for all elb(loadbalancer) resource
-	Elb *elb.ELB `json:"Elb,omitempty"`
+	Elb elbiface.ELBAPI `json:"Elb,omitempty"`
 	//This will hold the session for all elb2(loadbalancer version2) resource
-	Elb2 *elbv2.ELBV2 `json:"Elb2,omitempty"`
+	Elb2 elbv2iface.ELBV2API `json:"Elb2,omitempty"`
+	// ctx is the context carried to every call made to aws with this session.
+	ctx context.Context
+	// retry is the policy with which the calls made with this session are retried.
+	retry retry.Policy
+	// wait is the poller with which the methods of this session wait on the resources of aws.
+	wait wait.Poller
 }
 
 // EstablishConnectionInput implements EstablishConnection which establishes the session for specific resource in aws.
@@ -33,23 +45,95 @@ type EstablishConnectionInput struct {
 	// Session holds the actual session from aws, which means actual session has to be created even before we call this method.
 	// And the same can be done with the help of "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/sessions"
 	Session *session.Session
+	// Context controls the cancellation and deadline of all the calls made to aws with the established session,
+	// if not passed context.Background() is used.
+	Context context.Context
+	// Clients if set, is used to establish the session instead of Session.
+	// This helps in plugging in an alternate implementation of aws, ex: the in-memory fake used for testing.
+	Clients Clients
+	// Retry is the policy with which the failed calls to aws are retried, retry.Default() is used if not passed.
+	// The policy is applied by the sdk of aws to every request made with Session, the implementations of Clients have to retry on their own.
+	Retry *retry.Policy
+	// Wait is the poller with which the resources of aws are waited on (ex: subnet to be deleted), wait.Default() is used if not passed.
+	Wait *wait.Poller
+}
+
+// Clients returns the clients of the resources of aws for the region passed.
+// Implement this to make the methods of this package talk to something other than aws.
+type Clients interface {
+	EC2(region string) ec2iface.EC2API
+	ELB(region string) elbiface.ELBAPI
+	ELBV2(region string) elbv2iface.ELBV2API
 }
 
 // EstablishConnection helps in establishing connection to specific resource in aws.
 func (con *EstablishConnectionInput) EstablishConnection() (EstablishedSession, error) {
 
-	sesscopy := (con.Session).Copy(&aws.Config{Region: aws.String(con.Region)})
+	policy := retry.OrDefault(con.Retry)
+	poller := wait.OrDefault(con.Wait)
+	clients := con.Clients
+	if clients == nil {
+		clients = sessionClients{session: con.Session, retry: policy}
+	}
 
 	switch strings.ToLower(con.Resource) {
 	case "ec2":
-		return EstablishedSession{Ec2: ec2.New(sesscopy)}, nil
+		return EstablishedSession{Ec2: clients.EC2(con.Region), ctx: con.Context, retry: policy, wait: poller}, nil
 	case "elb":
-		return EstablishedSession{Ec2: ec2.New(sesscopy), Elb: elb.New(sesscopy)}, nil
+		return EstablishedSession{Ec2: clients.EC2(con.Region), Elb: clients.ELB(con.Region), ctx: con.Context, retry: policy, wait: poller}, nil
 	case "elb2":
-		return EstablishedSession{Ec2: ec2.New(sesscopy), Elb2: elbv2.New(sesscopy)}, nil
+		return EstablishedSession{Ec2: clients.EC2(con.Region), Elb2: clients.ELBV2(con.Region), ctx: con.Context, retry: policy, wait: poller}, nil
 	case "elb12":
-		return EstablishedSession{Ec2: ec2.New(sesscopy), Elb: elb.New(sesscopy), Elb2: elbv2.New(sesscopy)}, nil
+		return EstablishedSession{Ec2: clients.EC2(con.Region), Elb: clients.ELB(con.Region), Elb2: clients.ELBV2(con.Region), ctx: con.Context, retry: policy, wait: poller}, nil
 	default:
-		return EstablishedSession{}, fmt.Errorf("Session not established..!!. Unknown resource type, either we don't support this resource or entered resource does not exists")
+		return EstablishedSession{}, cloudyerror.Newf(cloudyerror.Unsupported, "Session not established..!!. Unknown resource type %s, either we don't support this resource or entered resource does not exists", con.Resource)
+	}
+}
+
+// sessionClients creates the clients of aws from the session passed, this is the default implementation of Clients.
+// The requests made with the clients are retried by the sdk of aws as per the policy held.
+type sessionClients struct {
+	session *session.Session
+	retry   retry.Policy
+}
+
+func (s sessionClients) config(region string) *session.Session {
+	return (s.session).Copy(&aws.Config{Region: aws.String(region), Retryer: retry.AWSRetryer(s.retry), EnforceShouldRetryCheck: aws.Bool(true)})
+}
+
+func (s sessionClients) EC2(region string) ec2iface.EC2API {
+	return ec2.New(s.config(region))
+}
+
+func (s sessionClients) ELB(region string) elbiface.ELBAPI {
+	return elb.New(s.config(region))
+}
+
+func (s sessionClients) ELBV2(region string) elbv2iface.ELBV2API {
+	return elbv2.New(s.config(region))
+}
+
+// Context returns the context carried by the session, context.Background() is returned if none was set.
+func (sess *EstablishedSession) Context() context.Context {
+	if sess.ctx != nil {
+		return sess.ctx
+	}
+	return context.Background()
+}
+
+// WithContext returns a copy of the session whose calls to aws are bound to the context passed.
+func (sess EstablishedSession) WithContext(ctx context.Context) EstablishedSession {
+	sess.ctx = ctx
+	return sess
+}
+
+// Retry calls fn till it succeeds or the retry policy of the session gives up, this is for the steps which have to wait on aws
+// beyond the retries of the sdk (ex: deleting a target group till its loadbalancer lets go of it).
+// The failures are classified with the classifier passed, the one of the policy (retry.AWS by default) is used if nil.
+func (sess *EstablishedSession) Retry(fn func() error, retryable retry.Classifier) error {
+	policy := sess.retry
+	if retryable != nil {
+		policy.Retryable = retryable
 	}
+	return policy.For(retry.AWS).Do(sess.Context(), fn)
 }
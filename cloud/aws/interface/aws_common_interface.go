@@ -1,10 +1,9 @@
 package neuronaws
 
 import (
-	"fmt"
-
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
 )
 
 // AwsCommonInput holds the common input values to perform the operations.
@@ -28,14 +27,14 @@ func (sess *EstablishedSession) DescribeAllAvailabilityZones(a *AwsCommonInput)
 
 	if sess.Ec2 != nil {
 		input := &ec2.DescribeAvailabilityZonesInput{}
-		result, err := (sess.Ec2).DescribeAvailabilityZones(input)
+		result, err := (sess.Ec2).DescribeAvailabilityZonesWithContext(sess.Context(), input)
 
 		if err != nil {
 			return nil, err
 		}
 		return result, nil
 	}
-	return nil, fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return nil, cloudyerror.InvalidSession()
 
 }
 
@@ -47,16 +46,16 @@ func (sess *EstablishedSession) DescribeAvailabilityZones(a *AwsCommonInput) (*e
 			input := &ec2.DescribeAvailabilityZonesInput{
 				ZoneNames: aws.StringSlice([]string{a.AvailabilityZone}),
 			}
-			result, err := (sess.Ec2).DescribeAvailabilityZones(input)
+			result, err := (sess.Ec2).DescribeAvailabilityZonesWithContext(sess.Context(), input)
 
 			if err != nil {
 				return nil, err
 			}
 			return result, nil
 		}
-		return nil, fmt.Errorf("Did not get session to perform action, cannot proceed further")
+		return nil, cloudyerror.InvalidSession()
 	}
-	return nil, fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return nil, cloudyerror.InvalidSession()
 
 }
 
@@ -75,13 +74,13 @@ func (sess *EstablishedSession) CreateTags(t *CreateTagsInput) error {
 				},
 			},
 		}
-		_, err := (sess.Ec2).CreateTags(input)
+		_, err := (sess.Ec2).CreateTagsWithContext(sess.Context(), input)
 		if err != nil {
 			return err
 		}
 		return nil
 	}
-	return fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return cloudyerror.InvalidSession()
 }
 
 // GetRegions describes the regions available in the cloud aws.
@@ -89,12 +88,12 @@ func (sess *EstablishedSession) GetRegions() (*ec2.DescribeRegionsOutput, error)
 
 	if sess.Ec2 != nil {
 		input := &ec2.DescribeRegionsInput{}
-		result, err := (sess.Ec2).DescribeRegions(input)
+		result, err := (sess.Ec2).DescribeRegionsWithContext(sess.Context(), input)
 
 		if err != nil {
 			return nil, err
 		}
 		return result, nil
 	}
-	return nil, fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return nil, cloudyerror.InvalidSession()
 }
@@ -5,7 +5,9 @@ import (
 	"reflect"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
 )
 
 // CreateServerInput holds all required values to create a server/instance in aws.
@@ -28,6 +30,13 @@ type CreateServerInput struct {
 	UserData string
 	// AssignPubIp is the deciding factor for opening instance to public (defaults to false making instance accessible only at private network).
 	AssignPubIp bool
+	// ClientToken makes the request idempotent, aws answers the requests made again with the same token with the instances launched by the first one.
+	// It is optional and can be up to 64 ASCII characters.
+	ClientToken string
+	// BlockDeviceMappings are the ebs volumes to be created and attached to the instance, the mapping of the root device of the image overrides its volume.
+	BlockDeviceMappings []*ec2.BlockDeviceMapping
+	// InstanceMarketOptions launches the instances as spot instances with the options passed, they are launched on-demand if it is nil.
+	InstanceMarketOptions *ec2.InstanceMarketOptionsRequest
 }
 
 // DescribeComputeInput holds all the required values to describe the instance/vm or any compute resources in aws.
@@ -38,6 +47,8 @@ type DescribeComputeInput struct {
 	ImageIds []string
 	// Filters can be applied on the resource to fetch more appropriate information.
 	Filters Filters
+	// Pagination controls the size of the pages and the number of results fetched by DescribeAllInstances and DescribeAllImages.
+	Pagination
 }
 
 // UpdateComputeInput holds all the required values to update the compute resources in aws.
@@ -48,6 +59,22 @@ type UpdateComputeInput struct {
 	Force string
 }
 
+// InstanceAttributeInput holds the values required to describe/modify an attribute of the instance, aws modifies only one attribute per request.
+type InstanceAttributeInput struct {
+	// InstanceId is the ID of the instance of which the attribute has to be described/modified.
+	InstanceId string
+	// Attribute is the name of the attribute to be described ex: instanceType, userData, disableApiTermination, groupSet.
+	Attribute string
+	// InstanceType is the type to which the instance has to be changed, the instance has to be stopped.
+	InstanceType string
+	// UserData is the command/script which replaces the user data of the instance, the instance has to be stopped.
+	UserData string
+	// DisableApiTermination enables/disables the protection of instance from termination.
+	DisableApiTermination *bool
+	// SecurityGroups are the IDs of the security groups which replaces the ones associated with the instance.
+	SecurityGroups []string
+}
+
 // DeleteComputeInput holds the required details for deleting compute resource.
 type DeleteComputeInput struct {
 	// ImageId is the ID of the image which has to be deleted.
@@ -73,35 +100,48 @@ func (sess *EstablishedSession) CreateInstance(ins *CreateServerInput) (*ec2.Res
 
 	if sess.Ec2 != nil {
 		if (ins.ImageId != "") || (ins.InstanceType != "") || (ins.KeyName != "") || (ins.MinCount != 0) || (ins.MaxCount != 0) || (ins.UserData != "") || (ins.SubnetId != "") || (ins.SecurityGroups != nil) {
-			// support for custom ebs mapping will be rolled out soon
-			createServerInput := &ec2.RunInstancesInput{
-				ImageId:      aws.String(ins.ImageId),
-				InstanceType: aws.String(ins.InstanceType),
-				KeyName:      aws.String(ins.KeyName),
-				MaxCount:     aws.Int64(ins.MaxCount),
-				MinCount:     aws.Int64(ins.MinCount),
-				UserData:     aws.String(ins.UserData),
-				NetworkInterfaces: []*ec2.InstanceNetworkInterfaceSpecification{{
-					AssociatePublicIpAddress: aws.Bool(ins.AssignPubIp),
-					DeviceIndex:              aws.Int64(0),
-					DeleteOnTermination:      aws.Bool(true),
-					SubnetId:                 aws.String(ins.SubnetId),
-					Groups:                   aws.StringSlice(ins.SecurityGroups),
-				}},
-			}
-			serverCreateResult, err := (sess.Ec2).RunInstances(createServerInput)
+			serverCreateResult, err := (sess.Ec2).RunInstancesWithContext(sess.Context(), ins.runInstancesInput())
 			// handling the error if it throws while subnet is under creation process
 			if err != nil {
 				return nil, err
 			}
 			return serverCreateResult, nil
 		}
-		return nil, fmt.Errorf("You provided empty/wrong details to CreateInstance, this is not acceptable")
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty/wrong details to CreateInstance, this is not acceptable")
 	}
-	return nil, fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return nil, cloudyerror.InvalidSession()
 
 }
 
+// runInstancesInput translates the values passed to the request RunInstances of aws.
+func (ins *CreateServerInput) runInstancesInput() *ec2.RunInstancesInput {
+	input := &ec2.RunInstancesInput{
+		ImageId:      aws.String(ins.ImageId),
+		InstanceType: aws.String(ins.InstanceType),
+		KeyName:      aws.String(ins.KeyName),
+		MaxCount:     aws.Int64(ins.MaxCount),
+		MinCount:     aws.Int64(ins.MinCount),
+		UserData:     aws.String(ins.UserData),
+		NetworkInterfaces: []*ec2.InstanceNetworkInterfaceSpecification{{
+			AssociatePublicIpAddress: aws.Bool(ins.AssignPubIp),
+			DeviceIndex:              aws.Int64(0),
+			DeleteOnTermination:      aws.Bool(true),
+			SubnetId:                 aws.String(ins.SubnetId),
+			Groups:                   aws.StringSlice(ins.SecurityGroups),
+		}},
+	}
+	if ins.ClientToken != "" {
+		input.ClientToken = aws.String(ins.ClientToken)
+	}
+	if len(ins.BlockDeviceMappings) != 0 {
+		input.BlockDeviceMappings = ins.BlockDeviceMappings
+	}
+	if ins.InstanceMarketOptions != nil {
+		input.InstanceMarketOptions = ins.InstanceMarketOptions
+	}
+	return input
+}
+
 // DescribeInstance will help in fetching the information about the instance selected, by describing it.
 func (sess *EstablishedSession) DescribeInstance(des *DescribeComputeInput) (*ec2.DescribeInstancesOutput, error) {
 
@@ -110,7 +150,7 @@ func (sess *EstablishedSession) DescribeInstance(des *DescribeComputeInput) (*ec
 			input := &ec2.DescribeInstancesInput{
 				InstanceIds: aws.StringSlice(des.InstanceIds),
 			}
-			result, err := (sess.Ec2).DescribeInstances(input)
+			result, err := (sess.Ec2).DescribeInstancesWithContext(sess.Context(), input)
 
 			if err != nil {
 				return nil, err
@@ -119,10 +159,10 @@ func (sess *EstablishedSession) DescribeInstance(des *DescribeComputeInput) (*ec
 		}
 
 		if reflect.DeepEqual(des.Filters, Filters{}) {
-			return nil, fmt.Errorf("You provided empty struct to DescribeInstance, this is not acceptable")
+			return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to DescribeInstance, this is not acceptable")
 		}
 		if (des.Filters.Name == "") || (des.Filters.Value == nil) {
-			return nil, fmt.Errorf("You chose Filters to fetch server details and did not provided required value for Filters")
+			return nil, cloudyerror.New(cloudyerror.InvalidInput, "You chose Filters to fetch server details and did not provided required value for Filters")
 		}
 		input := &ec2.DescribeInstancesInput{
 			Filters: []*ec2.Filter{
@@ -131,7 +171,7 @@ func (sess *EstablishedSession) DescribeInstance(des *DescribeComputeInput) (*ec
 				},
 			},
 		}
-		result, err := (sess.Ec2).DescribeInstances(input)
+		result, err := (sess.Ec2).DescribeInstancesWithContext(sess.Context(), input)
 
 		if err != nil {
 			return nil, err
@@ -139,22 +179,32 @@ func (sess *EstablishedSession) DescribeInstance(des *DescribeComputeInput) (*ec
 		return result, nil
 
 	}
-	return nil, fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return nil, cloudyerror.InvalidSession()
 }
 
 // DescribeAllInstances will describe all the av available instance in the specified region in aws.
+// All the pages are fetched, use EachInstance to visit the instances without holding all of them.
 func (sess *EstablishedSession) DescribeAllInstances(des *DescribeComputeInput) (*ec2.DescribeInstancesOutput, error) {
 
-	if sess.Ec2 != nil {
-		input := &ec2.DescribeInstancesInput{}
-		result, err := (sess.Ec2).DescribeInstances(input)
-
-		if err != nil {
-			return nil, err
+	result := &ec2.DescribeInstancesOutput{Reservations: make([]*ec2.Reservation, 0)}
+	var current *ec2.Reservation
+	err := sess.EachInstance(des, func(reservation *ec2.Reservation, instance *ec2.Instance) bool {
+		if current == nil || aws.StringValue(current.ReservationId) != aws.StringValue(reservation.ReservationId) {
+			current = &ec2.Reservation{
+				ReservationId: reservation.ReservationId,
+				OwnerId:       reservation.OwnerId,
+				RequesterId:   reservation.RequesterId,
+				Groups:        reservation.Groups,
+			}
+			result.Reservations = append(result.Reservations, current)
 		}
-		return result, nil
+		current.Instances = append(current.Instances, instance)
+		return true
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return result, nil
 }
 
 // DeleteInstance will delete the instance who's Id is specified.
@@ -165,16 +215,16 @@ func (sess *EstablishedSession) DeleteInstance(d *DeleteComputeInput) (*ec2.Term
 			terminateInstanceInput := &ec2.TerminateInstancesInput{
 				InstanceIds: aws.StringSlice(d.InstanceIds),
 			}
-			_, err := (sess.Ec2).TerminateInstances(terminateInstanceInput)
+			result, err := (sess.Ec2).TerminateInstancesWithContext(sess.Context(), terminateInstanceInput)
 
 			if err != nil {
 				return nil, err
 			}
-			return nil, nil
+			return result, nil
 		}
-		return nil, fmt.Errorf("You provided empty struct to DeleteInstance, this is not acceptable")
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to DeleteInstance, this is not acceptable")
 	}
-	return nil, fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return nil, cloudyerror.InvalidSession()
 }
 
 // StartInstances helps in stating the stopped instances in aws.
@@ -185,7 +235,7 @@ func (sess *EstablishedSession) StartInstances(s *UpdateComputeInput) (*ec2.Star
 			input := &ec2.StartInstancesInput{
 				InstanceIds: aws.StringSlice(s.InstanceIds),
 			}
-			result, err := (sess.Ec2).StartInstances(input)
+			result, err := (sess.Ec2).StartInstancesWithContext(sess.Context(), input)
 
 			if err != nil {
 				return nil, err
@@ -193,9 +243,9 @@ func (sess *EstablishedSession) StartInstances(s *UpdateComputeInput) (*ec2.Star
 			return result, nil
 
 		}
-		return nil, fmt.Errorf("You provided empty struct to StartInstances, this is not acceptable")
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to StartInstances, this is not acceptable")
 	}
-	return nil, fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return nil, cloudyerror.InvalidSession()
 }
 
 // StopInstances will help in stopping the currently runnig instance.
@@ -206,7 +256,7 @@ func (sess *EstablishedSession) StopInstances(s *UpdateComputeInput) (*ec2.StopI
 			input := &ec2.StopInstancesInput{
 				InstanceIds: aws.StringSlice(s.InstanceIds),
 			}
-			result, err := (sess.Ec2).StopInstances(input)
+			result, err := (sess.Ec2).StopInstancesWithContext(sess.Context(), input)
 
 			if err != nil {
 				return nil, err
@@ -214,9 +264,122 @@ func (sess *EstablishedSession) StopInstances(s *UpdateComputeInput) (*ec2.StopI
 			return result, nil
 
 		}
-		return nil, fmt.Errorf("You provided empty struct to StopInstances, this is not acceptable")
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to StopInstances, this is not acceptable")
 	}
-	return nil, fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return nil, cloudyerror.InvalidSession()
+}
+
+// RebootInstances requests the reboot of the running instances, aws reboots them asynchronously.
+func (sess *EstablishedSession) RebootInstances(s *UpdateComputeInput) error {
+
+	if sess.Ec2 != nil {
+		if s.InstanceIds != nil {
+			input := &ec2.RebootInstancesInput{
+				InstanceIds: aws.StringSlice(s.InstanceIds),
+			}
+			_, err := (sess.Ec2).RebootInstancesWithContext(sess.Context(), input)
+
+			if err != nil {
+				return err
+			}
+			return nil
+
+		}
+		return cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to RebootInstances, this is not acceptable")
+	}
+	return cloudyerror.InvalidSession()
+}
+
+// MonitorInstances enables the detailed monitoring of the instances.
+func (sess *EstablishedSession) MonitorInstances(s *UpdateComputeInput) (*ec2.MonitorInstancesOutput, error) {
+
+	if sess.Ec2 != nil {
+		if s.InstanceIds != nil {
+			input := &ec2.MonitorInstancesInput{
+				InstanceIds: aws.StringSlice(s.InstanceIds),
+			}
+			result, err := (sess.Ec2).MonitorInstancesWithContext(sess.Context(), input)
+
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+
+		}
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to MonitorInstances, this is not acceptable")
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// UnmonitorInstances disables the detailed monitoring of the instances, the basic monitoring stays enabled.
+func (sess *EstablishedSession) UnmonitorInstances(s *UpdateComputeInput) (*ec2.UnmonitorInstancesOutput, error) {
+
+	if sess.Ec2 != nil {
+		if s.InstanceIds != nil {
+			input := &ec2.UnmonitorInstancesInput{
+				InstanceIds: aws.StringSlice(s.InstanceIds),
+			}
+			result, err := (sess.Ec2).UnmonitorInstancesWithContext(sess.Context(), input)
+
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+
+		}
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to UnmonitorInstances, this is not acceptable")
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// ModifyInstanceAttribute modifies the attribute of the instance which is set in the input, only one of them has to be set.
+func (sess *EstablishedSession) ModifyInstanceAttribute(m *InstanceAttributeInput) error {
+
+	if sess.Ec2 != nil {
+		if m.InstanceId != "" {
+			_, err := (sess.Ec2).ModifyInstanceAttributeWithContext(sess.Context(), m.modifyInstanceAttributeInput())
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+		return cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to ModifyInstanceAttribute, this is not acceptable")
+	}
+	return cloudyerror.InvalidSession()
+}
+
+func (m *InstanceAttributeInput) modifyInstanceAttributeInput() *ec2.ModifyInstanceAttributeInput {
+	input := &ec2.ModifyInstanceAttributeInput{InstanceId: aws.String(m.InstanceId)}
+	if m.InstanceType != "" {
+		input.InstanceType = &ec2.AttributeValue{Value: aws.String(m.InstanceType)}
+	}
+	if m.UserData != "" {
+		input.UserData = &ec2.BlobAttributeValue{Value: []byte(m.UserData)}
+	}
+	if m.DisableApiTermination != nil {
+		input.DisableApiTermination = &ec2.AttributeBooleanValue{Value: aws.Bool(*m.DisableApiTermination)}
+	}
+	if len(m.SecurityGroups) != 0 {
+		input.Groups = aws.StringSlice(m.SecurityGroups)
+	}
+	return input
+}
+
+// DescribeInstanceAttribute describes the attribute of the instance passed, the user data is base64 encoded in the response.
+func (sess *EstablishedSession) DescribeInstanceAttribute(m *InstanceAttributeInput) (*ec2.DescribeInstanceAttributeOutput, error) {
+
+	if sess.Ec2 != nil {
+		if m.InstanceId != "" && m.Attribute != "" {
+			input := &ec2.DescribeInstanceAttributeInput{InstanceId: aws.String(m.InstanceId), Attribute: aws.String(m.Attribute)}
+			result, err := (sess.Ec2).DescribeInstanceAttributeWithContext(sess.Context(), input)
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to DescribeInstanceAttribute, the instance and the attribute are required")
+	}
+	return nil, cloudyerror.InvalidSession()
 }
 
 // CreateImage is responsible for capturing the image of the server who's Id is passed to it.
@@ -224,21 +387,25 @@ func (sess *EstablishedSession) CreateImage(img *ImageCreateInput) (*ec2.CreateI
 
 	if sess.Ec2 != nil {
 		if (img.ServerName != "") || (img.InstanceId != "") || (img.Description != "") {
-			input := &ec2.CreateImageInput{
-				Description: aws.String(img.Description),
-				InstanceId:  aws.String(img.InstanceId),
-				Name:        aws.String(img.ServerName),
-			}
-			result, err := (sess.Ec2).CreateImage(input)
+			result, err := (sess.Ec2).CreateImageWithContext(sess.Context(), img.createImageInput())
 
 			if err != nil {
 				return nil, err
 			}
 			return result, nil
 		}
-		return nil, fmt.Errorf("You provided empty struct to CreateImage, this is not acceptable")
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to CreateImage, this is not acceptable")
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// createImageInput translates the values passed to the request CreateImage of aws.
+func (img *ImageCreateInput) createImageInput() *ec2.CreateImageInput {
+	return &ec2.CreateImageInput{
+		Description: aws.String(img.Description),
+		InstanceId:  aws.String(img.InstanceId),
+		Name:        aws.String(img.ServerName),
 	}
-	return nil, fmt.Errorf("Did not get session to perform action, cannot proceed further")
 }
 
 // DeregisterImage along with DeleteSnapshot has to be used to delete an image.
@@ -248,16 +415,16 @@ func (sess *EstablishedSession) DeregisterImage(img *DeleteComputeInput) error {
 		if img.ImageId != "" {
 			// deregistering image will be done by below code
 			input := &ec2.DeregisterImageInput{ImageId: aws.String(img.ImageId)}
-			_, err := (sess.Ec2).DeregisterImage(input)
+			_, err := (sess.Ec2).DeregisterImageWithContext(sess.Context(), input)
 
 			if err != nil {
 				return err
 			}
 			return nil
 		}
-		return fmt.Errorf("You provided empty struct to DeregisterImage, this is not acceptable")
+		return cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to DeregisterImage, this is not acceptable")
 	}
-	return fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return cloudyerror.InvalidSession()
 }
 
 // DeleteSnapshot helps in deleting the snapshot, but this is more effective while deleting image from aws.
@@ -267,16 +434,16 @@ func (sess *EstablishedSession) DeleteSnapshot(img *DeleteComputeInput) error {
 		if img.SnapshotId != "" {
 			// Deletion of snapshot will addressed by below code
 			input := &ec2.DeleteSnapshotInput{SnapshotId: aws.String(img.SnapshotId)}
-			_, err := (sess.Ec2).DeleteSnapshot(input)
+			_, err := (sess.Ec2).DeleteSnapshotWithContext(sess.Context(), input)
 
 			if err != nil {
 				return err
 			}
 			return nil
 		}
-		return fmt.Errorf("You provided empty struct to DeleteSnapshot, this is not acceptable")
+		return cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to DeleteSnapshot, this is not acceptable")
 	}
-	return fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return cloudyerror.InvalidSession()
 }
 
 // DescribeImages helps in fetching information about the images who's Id is passed. This is achieved by describing it.
@@ -288,38 +455,31 @@ func (sess *EstablishedSession) DescribeImages(img *DescribeComputeInput) (*ec2.
 			searchImageInput := &ec2.DescribeImagesInput{
 				ImageIds: aws.StringSlice(img.ImageIds),
 			}
-			result, err := (sess.Ec2).DescribeImages(searchImageInput)
+			result, err := (sess.Ec2).DescribeImagesWithContext(sess.Context(), searchImageInput)
 
 			if err != nil {
 				return nil, err
 			}
 			return result, nil
 		}
-		return nil, fmt.Errorf("You provided empty struct to DescribeImages, this is not acceptable")
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to DescribeImages, this is not acceptable")
 	}
-	return nil, fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return nil, cloudyerror.InvalidSession()
 }
 
 // DescribeAllImages helps in describing all the images present in region, account or any depending on the filter you apply.
+// Only Limit of Pagination applies here, as DescribeImages of aws is not paginated.
 func (sess *EstablishedSession) DescribeAllImages(img *DescribeComputeInput) (*ec2.DescribeImagesOutput, error) {
 
-	if sess.Ec2 != nil {
-		// desribing image to check if image exists
-		input := &ec2.DescribeImagesInput{
-			Filters: []*ec2.Filter{
-				&ec2.Filter{Name: aws.String("is-public"),
-					Values: aws.StringSlice([]string{"false"}),
-				},
-			},
-		}
-		result, err := (sess.Ec2).DescribeImages(input)
-
-		if err != nil {
-			return nil, err
-		}
-		return result, nil
+	result := &ec2.DescribeImagesOutput{Images: make([]*ec2.Image, 0)}
+	err := sess.EachImage(img, func(image *ec2.Image) bool {
+		result.Images = append(result.Images, image)
+		return true
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return result, nil
 }
 
 // WaitTillInstanceAvailable makes the called method to wait till the created instance becomes available.
@@ -330,15 +490,17 @@ func (sess *EstablishedSession) WaitTillInstanceAvailable(d *DescribeComputeInpu
 			input := &ec2.DescribeInstancesInput{
 				InstanceIds: aws.StringSlice(d.InstanceIds),
 			}
-			err := (sess.Ec2).WaitUntilInstanceRunning(input)
+			err := sess.wait.AWS(sess.Context(), fmt.Sprintf("instances %v", d.InstanceIds), func(ctx aws.Context, options ...request.WaiterOption) error {
+				return (sess.Ec2).WaitUntilInstanceRunningWithContext(ctx, input, options...)
+			})
 			if err != nil {
 				return err
 			}
 			return nil
 		}
-		return fmt.Errorf("You provided empty struct to WaitTillInstanceAvailable, this is not acceptable")
+		return cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to WaitTillInstanceAvailable, this is not acceptable")
 	}
-	return fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return cloudyerror.InvalidSession()
 }
 
 // WaitTillInstanceRunning makes the called method to wait till the created/started instance enters to runnig state.
@@ -349,15 +511,17 @@ func (sess *EstablishedSession) WaitTillInstanceRunning(d *DescribeComputeInput)
 			input := &ec2.DescribeInstancesInput{
 				InstanceIds: aws.StringSlice(d.InstanceIds),
 			}
-			err := (sess.Ec2).WaitUntilInstanceRunning(input)
+			err := sess.wait.AWS(sess.Context(), fmt.Sprintf("instances %v", d.InstanceIds), func(ctx aws.Context, options ...request.WaiterOption) error {
+				return (sess.Ec2).WaitUntilInstanceRunningWithContext(ctx, input, options...)
+			})
 			if err != nil {
 				return err
 			}
 			return nil
 		}
-		return fmt.Errorf("You provided empty struct to WaitTillInstanceRunning, this is not acceptable")
+		return cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to WaitTillInstanceRunning, this is not acceptable")
 	}
-	return fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return cloudyerror.InvalidSession()
 }
 
 // WaitTillInstanceStopped makes the called method to wait till the specified instance enters the stop state.
@@ -368,15 +532,17 @@ func (sess *EstablishedSession) WaitTillInstanceStopped(d *DescribeComputeInput)
 			input := &ec2.DescribeInstancesInput{
 				InstanceIds: aws.StringSlice(d.InstanceIds),
 			}
-			err := (sess.Ec2).WaitUntilInstanceStopped(input)
+			err := sess.wait.AWS(sess.Context(), fmt.Sprintf("instances %v", d.InstanceIds), func(ctx aws.Context, options ...request.WaiterOption) error {
+				return (sess.Ec2).WaitUntilInstanceStoppedWithContext(ctx, input, options...)
+			})
 			if err != nil {
 				return err
 			}
 			return nil
 		}
-		return fmt.Errorf("You provided empty struct to WaitTillInstanceStopped, this is not acceptable")
+		return cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to WaitTillInstanceStopped, this is not acceptable")
 	}
-	return fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return cloudyerror.InvalidSession()
 }
 
 // WaitTillInstanceTerminated makes the called method to wait till the specified instance is terminated successfully.
@@ -387,13 +553,15 @@ func (sess *EstablishedSession) WaitTillInstanceTerminated(d *DescribeComputeInp
 			input := &ec2.DescribeInstancesInput{
 				InstanceIds: aws.StringSlice(d.InstanceIds),
 			}
-			err := (sess.Ec2).WaitUntilInstanceTerminated(input)
+			err := sess.wait.AWS(sess.Context(), fmt.Sprintf("instances %v", d.InstanceIds), func(ctx aws.Context, options ...request.WaiterOption) error {
+				return (sess.Ec2).WaitUntilInstanceTerminatedWithContext(ctx, input, options...)
+			})
 			if err != nil {
 				return err
 			}
 			return nil
 		}
-		return fmt.Errorf("You provided empty struct to WaitTillInstanceTerminated, this is not acceptable")
+		return cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to WaitTillInstanceTerminated, this is not acceptable")
 	}
-	return fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return cloudyerror.InvalidSession()
 }
@@ -0,0 +1,188 @@
+package neuronaws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// DryRunOperationCode is the code of the error with which aws answers the requests made with DryRun,
+// if the caller has the permissions required to perform them.
+const DryRunOperationCode = "DryRunOperation"
+
+// DryRun checks whether the request passed would be permitted by aws, without actually performing it.
+// The request is made with its DryRun flag set, hence aws only validates it; nil is returned if the request would have succeeded.
+// It accepts the inputs of the APIs of ec2 which supports DryRun along with CreateServerInput, ImageCreateInput and InstanceAttributeInput,
+// and the input passed is modified in the process.
+func (sess *EstablishedSession) DryRun(input interface{}) error {
+
+	if sess.Ec2 == nil {
+		return cloudyerror.InvalidSession()
+	}
+
+	ctx := sess.Context()
+	var err error
+	switch in := input.(type) {
+	case *CreateServerInput:
+		return sess.DryRun(in.runInstancesInput())
+	case *ImageCreateInput:
+		return sess.DryRun(in.createImageInput())
+	case *InstanceAttributeInput:
+		return sess.DryRun(in.modifyInstanceAttributeInput())
+	case *ec2.CreateVpcInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).CreateVpcWithContext(ctx, in)
+	case *ec2.DeleteVpcInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).DeleteVpcWithContext(ctx, in)
+	case *ec2.CreateSubnetInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).CreateSubnetWithContext(ctx, in)
+	case *ec2.DeleteSubnetInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).DeleteSubnetWithContext(ctx, in)
+	case *ec2.CreateInternetGatewayInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).CreateInternetGatewayWithContext(ctx, in)
+	case *ec2.AttachInternetGatewayInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).AttachInternetGatewayWithContext(ctx, in)
+	case *ec2.DetachInternetGatewayInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).DetachInternetGatewayWithContext(ctx, in)
+	case *ec2.DeleteInternetGatewayInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).DeleteInternetGatewayWithContext(ctx, in)
+	case *ec2.CreateRouteTableInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).CreateRouteTableWithContext(ctx, in)
+	case *ec2.CreateRouteInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).CreateRouteWithContext(ctx, in)
+	case *ec2.ReplaceRouteInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).ReplaceRouteWithContext(ctx, in)
+	case *ec2.DeleteRouteInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).DeleteRouteWithContext(ctx, in)
+	case *ec2.AssociateRouteTableInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).AssociateRouteTableWithContext(ctx, in)
+	case *ec2.DisassociateRouteTableInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).DisassociateRouteTableWithContext(ctx, in)
+	case *ec2.DeleteRouteTableInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).DeleteRouteTableWithContext(ctx, in)
+	case *ec2.DeleteNetworkInterfaceInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).DeleteNetworkInterfaceWithContext(ctx, in)
+	case *ec2.AllocateAddressInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).AllocateAddressWithContext(ctx, in)
+	case *ec2.AssociateAddressInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).AssociateAddressWithContext(ctx, in)
+	case *ec2.DisassociateAddressInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).DisassociateAddressWithContext(ctx, in)
+	case *ec2.ReleaseAddressInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).ReleaseAddressWithContext(ctx, in)
+	case *ec2.CreateSecurityGroupInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).CreateSecurityGroupWithContext(ctx, in)
+	case *ec2.AuthorizeSecurityGroupIngressInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).AuthorizeSecurityGroupIngressWithContext(ctx, in)
+	case *ec2.RevokeSecurityGroupIngressInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).RevokeSecurityGroupIngressWithContext(ctx, in)
+	case *ec2.AuthorizeSecurityGroupEgressInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).AuthorizeSecurityGroupEgressWithContext(ctx, in)
+	case *ec2.RevokeSecurityGroupEgressInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).RevokeSecurityGroupEgressWithContext(ctx, in)
+	case *ec2.UpdateSecurityGroupRuleDescriptionsIngressInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).UpdateSecurityGroupRuleDescriptionsIngressWithContext(ctx, in)
+	case *ec2.UpdateSecurityGroupRuleDescriptionsEgressInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).UpdateSecurityGroupRuleDescriptionsEgressWithContext(ctx, in)
+	case *ec2.DeleteSecurityGroupInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).DeleteSecurityGroupWithContext(ctx, in)
+	case *ec2.RunInstancesInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).RunInstancesWithContext(ctx, in)
+	case *ec2.StartInstancesInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).StartInstancesWithContext(ctx, in)
+	case *ec2.StopInstancesInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).StopInstancesWithContext(ctx, in)
+	case *ec2.TerminateInstancesInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).TerminateInstancesWithContext(ctx, in)
+	case *ec2.CancelSpotInstanceRequestsInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).CancelSpotInstanceRequestsWithContext(ctx, in)
+	case *ec2.CreateImageInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).CreateImageWithContext(ctx, in)
+	case *ec2.DeregisterImageInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).DeregisterImageWithContext(ctx, in)
+	case *ec2.DeleteSnapshotInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).DeleteSnapshotWithContext(ctx, in)
+	case *ec2.CreateVolumeInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).CreateVolumeWithContext(ctx, in)
+	case *ec2.AttachVolumeInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).AttachVolumeWithContext(ctx, in)
+	case *ec2.DetachVolumeInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).DetachVolumeWithContext(ctx, in)
+	case *ec2.ModifyVolumeInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).ModifyVolumeWithContext(ctx, in)
+	case *ec2.DeleteVolumeInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).DeleteVolumeWithContext(ctx, in)
+	case *ec2.CreateSnapshotInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).CreateSnapshotWithContext(ctx, in)
+	case *ec2.CreateKeyPairInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).CreateKeyPairWithContext(ctx, in)
+	case *ec2.ImportKeyPairInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).ImportKeyPairWithContext(ctx, in)
+	case *ec2.DeleteKeyPairInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).DeleteKeyPairWithContext(ctx, in)
+	case *ec2.RebootInstancesInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).RebootInstancesWithContext(ctx, in)
+	case *ec2.ModifyInstanceAttributeInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).ModifyInstanceAttributeWithContext(ctx, in)
+	case *ec2.MonitorInstancesInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).MonitorInstancesWithContext(ctx, in)
+	case *ec2.UnmonitorInstancesInput:
+		in.DryRun = aws.Bool(true)
+		_, err = (sess.Ec2).UnmonitorInstancesWithContext(ctx, in)
+	default:
+		return cloudyerror.Newf(cloudyerror.Unsupported, "DryRun is not supported for the request %T", input)
+	}
+
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == DryRunOperationCode {
+		return nil
+	}
+	return err
+}
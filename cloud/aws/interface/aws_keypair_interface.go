@@ -0,0 +1,86 @@
+package neuronaws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// KeyPairInput holds the required values to create/import/describe/delete the key pairs with which the instances are logged into.
+type KeyPairInput struct {
+	// KeyNames are the names of the key pairs on which the operation has to be performed, only the first one is considered by the operations on a single key pair.
+	KeyNames []string
+	// PublicKey is the public key in the OpenSSH format (ex: ssh-rsa AAAA... user@host) to be imported as the key pair.
+	PublicKey string
+	// Filters can be applied over the key pairs to get more precise data about it ex: fingerprint.
+	Filters Filters
+}
+
+// CreateKeyPair creates the first of the key pairs passed, the private key of the key pair is available only in the response.
+func (sess *EstablishedSession) CreateKeyPair(k *KeyPairInput) (*ec2.CreateKeyPairOutput, error) {
+
+	if sess.Ec2 != nil {
+		if len(k.KeyNames) != 0 {
+			result, err := (sess.Ec2).CreateKeyPairWithContext(sess.Context(), &ec2.CreateKeyPairInput{KeyName: aws.String(k.KeyNames[0])})
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to CreateKeyPair, this is not acceptable")
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// ImportKeyPair imports the public key passed as the first of the key pairs passed.
+func (sess *EstablishedSession) ImportKeyPair(k *KeyPairInput) (*ec2.ImportKeyPairOutput, error) {
+
+	if sess.Ec2 != nil {
+		if len(k.KeyNames) != 0 && k.PublicKey != "" {
+			input := &ec2.ImportKeyPairInput{KeyName: aws.String(k.KeyNames[0]), PublicKeyMaterial: []byte(k.PublicKey)}
+			result, err := (sess.Ec2).ImportKeyPairWithContext(sess.Context(), input)
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty/wrong details to ImportKeyPair, the name and the public key are required")
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// DescribeKeyPairs fetches the information about the key pairs selected either by its names or the filters, all the key pairs of the region are fetched if neither is passed.
+func (sess *EstablishedSession) DescribeKeyPairs(k *KeyPairInput) (*ec2.DescribeKeyPairsOutput, error) {
+
+	if sess.Ec2 != nil {
+		input := new(ec2.DescribeKeyPairsInput)
+		if len(k.KeyNames) != 0 {
+			input.KeyNames = aws.StringSlice(k.KeyNames)
+		}
+		if k.Filters.Name != "" {
+			input.Filters = []*ec2.Filter{{Name: aws.String(k.Filters.Name), Values: aws.StringSlice(k.Filters.Value)}}
+		}
+		result, err := (sess.Ec2).DescribeKeyPairsWithContext(sess.Context(), input)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// DeleteKeyPair deletes the first of the key pairs passed, the instances launched with it are left as is.
+func (sess *EstablishedSession) DeleteKeyPair(k *KeyPairInput) error {
+
+	if sess.Ec2 != nil {
+		if len(k.KeyNames) != 0 {
+			_, err := (sess.Ec2).DeleteKeyPairWithContext(sess.Context(), &ec2.DeleteKeyPairInput{KeyName: aws.String(k.KeyNames[0])})
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+		return cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to DeleteKeyPair, this is not acceptable")
+	}
+	return cloudyerror.InvalidSession()
+}
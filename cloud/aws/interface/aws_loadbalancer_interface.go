@@ -1,10 +1,14 @@
 package neuronaws
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	err "github.com/nikhilsbhat/neuron-cloudy/errors"
@@ -48,6 +52,8 @@ type LoadBalanceCreateInput struct {
 	TargetArn string
 	// LbArn is the ARN of the loadbalancer that would be created/deleted/updated/retrieved.
 	LbArn string
+	// Instances are the IDs of the instances to be registered as the backends of the loadbalancer.
+	Instances []string
 }
 
 // LoadBalanceResponse returns the filtered/unfiltered results obtained from aws.
@@ -85,6 +91,9 @@ type DescribeLoadbalancersInput struct {
 	LbArns      []string
 	TargetArns  []string
 	ListnerArns []string
+	// Pagination controls the size of the pages and the number of results fetched by DescribeAllClassicLoadbalancer,
+	// DescribeAllApplicationLoadbalancer and DescribeAllTargetgroups.
+	Pagination
 }
 
 // CreateClassicLb helps in creating load balancer of type classic
@@ -109,7 +118,7 @@ func (sess *EstablishedSession) CreateClassicLb(lb LoadBalanceCreateInput) (*elb
 				SSLCertificateId: aws.String(lb.SslCert),
 			})
 		default:
-			return nil, fmt.Errorf("You provided unknown loadbalancer protocol, enter a valid protocol")
+			return nil, err.New(err.InvalidInput, "You provided unknown loadbalancer protocol, enter a valid protocol")
 		}
 		input := &elb.CreateLoadBalancerInput{
 			Listeners:        listeners,
@@ -118,7 +127,7 @@ func (sess *EstablishedSession) CreateClassicLb(lb LoadBalanceCreateInput) (*elb
 			SecurityGroups:   aws.StringSlice(lb.SecurityGroups),
 			Subnets:          aws.StringSlice(lb.Subnets),
 		}
-		result, err := (sess.Elb).CreateLoadBalancer(input)
+		result, err := (sess.Elb).CreateLoadBalancerWithContext(sess.Context(), input)
 		if err != nil {
 			return nil, err
 		}
@@ -144,7 +153,7 @@ func (sess *EstablishedSession) CreateApplicationLb(lb LoadBalanceCreateInput) (
 				}},
 		}
 
-		result, err := (sess.Elb2).CreateLoadBalancer(input)
+		result, err := (sess.Elb2).CreateLoadBalancerWithContext(sess.Context(), input)
 		if err != nil {
 			return nil, err
 		}
@@ -174,7 +183,7 @@ func (sess *EstablishedSession) CreateTargetGroups(lb *LoadBalanceCreateInput) (
 			Matcher:                    &elbv2.Matcher{HttpCode: &lb.HttpCode},
 		}
 
-		result, err := (sess.Elb2).CreateTargetGroup(input)
+		result, err := (sess.Elb2).CreateTargetGroupWithContext(sess.Context(), input)
 		if err != nil {
 			return nil, err
 		}
@@ -220,10 +229,56 @@ func (sess *EstablishedSession) CreateApplicationListners(lb *LoadBalanceCreateI
 				SslPolicy:       aws.String(lb.SslPolicy),
 			}
 		default:
-			return nil, fmt.Errorf("You provided unknown loadbalancer protocol, enter a valid protocol")
+			return nil, err.New(err.InvalidInput, "You provided unknown loadbalancer protocol, enter a valid protocol")
 		}
 
-		result, err := (sess.Elb2).CreateListener(&input)
+		result, err := (sess.Elb2).CreateListenerWithContext(sess.Context(), &input)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return nil, err.InvalidSession()
+}
+
+// RegisterInstancesToClassicLb registers the instances passed as the backends of the classic loadbalancer.
+func (sess *EstablishedSession) RegisterInstancesToClassicLb(lb *LoadBalanceCreateInput) (*elb.RegisterInstancesWithLoadBalancerOutput, error) {
+
+	if sess.Elb != nil {
+		instances := make([]*elb.Instance, 0)
+		for _, id := range lb.Instances {
+			instances = append(instances, &elb.Instance{InstanceId: aws.String(id)})
+		}
+		input := &elb.RegisterInstancesWithLoadBalancerInput{
+			LoadBalancerName: aws.String(lb.Name),
+			Instances:        instances,
+		}
+		result, err := (sess.Elb).RegisterInstancesWithLoadBalancerWithContext(sess.Context(), input)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return nil, err.InvalidSession()
+}
+
+// RegisterTargets registers the instances passed as the targets of the target group of application loadbalancer.
+func (sess *EstablishedSession) RegisterTargets(lb *LoadBalanceCreateInput) (*elbv2.RegisterTargetsOutput, error) {
+
+	if sess.Elb2 != nil {
+		targets := make([]*elbv2.TargetDescription, 0)
+		for _, id := range lb.Instances {
+			target := &elbv2.TargetDescription{Id: aws.String(id)}
+			if lb.InstPort != 0 {
+				target.Port = aws.Int64(lb.InstPort)
+			}
+			targets = append(targets, target)
+		}
+		input := &elbv2.RegisterTargetsInput{
+			TargetGroupArn: aws.String(lb.TargetArn),
+			Targets:        targets,
+		}
+		result, err := (sess.Elb2).RegisterTargetsWithContext(sess.Context(), input)
 		if err != nil {
 			return nil, err
 		}
@@ -240,31 +295,31 @@ func (sess *EstablishedSession) DescribeClassicLoadbalancer(lb *DescribeLoadbala
 			input := &elb.DescribeLoadBalancersInput{
 				LoadBalancerNames: aws.StringSlice(lb.LbNames),
 			}
-			result, err := (sess.Elb).DescribeLoadBalancers(input)
+			result, err := (sess.Elb).DescribeLoadBalancersWithContext(sess.Context(), input)
 
 			if err != nil {
 				return nil, err
 			}
 			return result, nil
 		}
-		return nil, fmt.Errorf(fmt.Sprintf("%v DescribeClassicLoadbalancer", err.EmptyStructError()))
+		return nil, err.New(err.InvalidInput, "You provided empty struct to DescribeClassicLoadbalancer, this is not acceptable")
 	}
 	return nil, err.InvalidSession()
 }
 
 // DescribeAllClassicLoadbalancer describes the details of all the classic loadbalancers in the selected region.
+// All the pages are fetched, use EachClassicLoadbalancer to visit them without holding all of them.
 func (sess *EstablishedSession) DescribeAllClassicLoadbalancer(lb *DescribeLoadbalancersInput) (*elb.DescribeLoadBalancersOutput, error) {
 
-	if sess.Elb != nil {
-		input := &elb.DescribeLoadBalancersInput{}
-		result, err := (sess.Elb).DescribeLoadBalancers(input)
-
-		if err != nil {
-			return nil, err
-		}
-		return result, nil
+	result := &elb.DescribeLoadBalancersOutput{LoadBalancerDescriptions: make([]*elb.LoadBalancerDescription, 0)}
+	eacherr := sess.EachClassicLoadbalancer(lb, func(item *elb.LoadBalancerDescription) bool {
+		result.LoadBalancerDescriptions = append(result.LoadBalancerDescriptions, item)
+		return true
+	})
+	if eacherr != nil {
+		return nil, eacherr
 	}
-	return nil, err.InvalidSession()
+	return result, nil
 }
 
 // DescribeApplicationLoadbalancer describes the details of the selected application loadbalancers.
@@ -275,7 +330,7 @@ func (sess *EstablishedSession) DescribeApplicationLoadbalancer(lb *DescribeLoad
 			input := &elbv2.DescribeLoadBalancersInput{
 				LoadBalancerArns: aws.StringSlice(lb.LbArns),
 			}
-			result, err := (sess.Elb2).DescribeLoadBalancers(input)
+			result, err := (sess.Elb2).DescribeLoadBalancersWithContext(sess.Context(), input)
 
 			if err != nil {
 				return nil, err
@@ -287,7 +342,7 @@ func (sess *EstablishedSession) DescribeApplicationLoadbalancer(lb *DescribeLoad
 			input := &elbv2.DescribeLoadBalancersInput{
 				Names: aws.StringSlice(lb.LbNames),
 			}
-			result, err := (sess.Elb2).DescribeLoadBalancers(input)
+			result, err := (sess.Elb2).DescribeLoadBalancersWithContext(sess.Context(), input)
 
 			if err != nil {
 				return nil, err
@@ -295,24 +350,24 @@ func (sess *EstablishedSession) DescribeApplicationLoadbalancer(lb *DescribeLoad
 			return result, nil
 		}
 
-		return nil, fmt.Errorf(fmt.Sprintf("%v DescribeApplicationLoadbalancer", err.EmptyStructError()))
+		return nil, err.New(err.InvalidInput, "You provided empty struct to DescribeApplicationLoadbalancer, this is not acceptable")
 	}
 	return nil, err.InvalidSession()
 }
 
 // DescribeAllApplicationLoadbalancer describes the details of all the application loadbalancers in the selected region.
+// All the pages are fetched, use EachApplicationLoadbalancer to visit them without holding all of them.
 func (sess *EstablishedSession) DescribeAllApplicationLoadbalancer(lb *DescribeLoadbalancersInput) (*elbv2.DescribeLoadBalancersOutput, error) {
 
-	if sess.Elb2 != nil {
-		input := &elbv2.DescribeLoadBalancersInput{}
-		result, err := (sess.Elb2).DescribeLoadBalancers(input)
-
-		if err != nil {
-			return nil, err
-		}
-		return result, nil
+	result := &elbv2.DescribeLoadBalancersOutput{LoadBalancers: make([]*elbv2.LoadBalancer, 0)}
+	eacherr := sess.EachApplicationLoadbalancer(lb, func(item *elbv2.LoadBalancer) bool {
+		result.LoadBalancers = append(result.LoadBalancers, item)
+		return true
+	})
+	if eacherr != nil {
+		return nil, eacherr
 	}
-	return nil, err.InvalidSession()
+	return result, nil
 }
 
 // DescribeTargetgroups describes the target group of the loadbalancers.
@@ -322,7 +377,7 @@ func (sess *EstablishedSession) DescribeTargetgroups(lb *DescribeLoadbalancersIn
 			input := &elbv2.DescribeTargetGroupsInput{
 				TargetGroupArns: aws.StringSlice(lb.TargetArns),
 			}
-			result, err := (sess.Elb2).DescribeTargetGroups(input)
+			result, err := (sess.Elb2).DescribeTargetGroupsWithContext(sess.Context(), input)
 
 			if err != nil {
 				return nil, err
@@ -334,31 +389,31 @@ func (sess *EstablishedSession) DescribeTargetgroups(lb *DescribeLoadbalancersIn
 			input := &elbv2.DescribeTargetGroupsInput{
 				LoadBalancerArn: aws.String(lb.LbArns[0]),
 			}
-			result, err := (sess.Elb2).DescribeTargetGroups(input)
+			result, err := (sess.Elb2).DescribeTargetGroupsWithContext(sess.Context(), input)
 
 			if err != nil {
 				return nil, err
 			}
 			return result, nil
 		}
-		return nil, fmt.Errorf(fmt.Sprintf("%v DescribeTargetgroups", err.EmptyStructError()))
+		return nil, err.New(err.InvalidInput, "You provided empty struct to DescribeTargetgroups, this is not acceptable")
 	}
 	return nil, err.InvalidSession()
 }
 
 // DescribeAllTargetgroups describes all the target group present in the selected region.
+// All the pages are fetched, use EachTargetgroup to visit them without holding all of them.
 func (sess *EstablishedSession) DescribeAllTargetgroups(lb *DescribeLoadbalancersInput) (*elbv2.DescribeTargetGroupsOutput, error) {
 
-	if sess.Elb2 != nil {
-		input := &elbv2.DescribeTargetGroupsInput{}
-		result, err := (sess.Elb2).DescribeTargetGroups(input)
-
-		if err != nil {
-			return nil, err
-		}
-		return result, nil
+	result := &elbv2.DescribeTargetGroupsOutput{TargetGroups: make([]*elbv2.TargetGroup, 0)}
+	eacherr := sess.EachTargetgroup(lb, func(item *elbv2.TargetGroup) bool {
+		result.TargetGroups = append(result.TargetGroups, item)
+		return true
+	})
+	if eacherr != nil {
+		return nil, eacherr
 	}
-	return nil, err.InvalidSession()
+	return result, nil
 }
 
 // DescribeListners helps in describing the selected listeners.
@@ -369,7 +424,7 @@ func (sess *EstablishedSession) DescribeListners(lb *DescribeLoadbalancersInput)
 			input := &elbv2.DescribeListenersInput{
 				ListenerArns: aws.StringSlice(lb.ListnerArns),
 			}
-			result, err := (sess.Elb2).DescribeListeners(input)
+			result, err := (sess.Elb2).DescribeListenersWithContext(sess.Context(), input)
 
 			if err != nil {
 				return nil, err
@@ -381,14 +436,14 @@ func (sess *EstablishedSession) DescribeListners(lb *DescribeLoadbalancersInput)
 			input := &elbv2.DescribeListenersInput{
 				LoadBalancerArn: aws.String(lb.LbArns[0]),
 			}
-			result, err := (sess.Elb2).DescribeListeners(input)
+			result, err := (sess.Elb2).DescribeListenersWithContext(sess.Context(), input)
 
 			if err != nil {
 				return nil, err
 			}
 			return result, nil
 		}
-		return nil, fmt.Errorf(fmt.Sprintf("%v DescribeListners", err.EmptyStructError()))
+		return nil, err.New(err.InvalidInput, "You provided empty struct to DescribeListners, this is not acceptable")
 	}
 	return nil, err.InvalidSession()
 
@@ -399,7 +454,7 @@ func (sess *EstablishedSession) DescribeAllListners(lb *DescribeLoadbalancersInp
 
 	if sess.Elb2 != nil {
 		input := &elbv2.DescribeListenersInput{}
-		result, err := (sess.Elb2).DescribeListeners(input)
+		result, err := (sess.Elb2).DescribeListenersWithContext(sess.Context(), input)
 
 		if err != nil {
 			return nil, err
@@ -417,7 +472,7 @@ func (sess *EstablishedSession) DeleteClassicLoadbalancer(lb *DeleteLoadbalancer
 		input := &elb.DeleteLoadBalancerInput{
 			LoadBalancerName: aws.String(lb.LbName),
 		}
-		_, err := (sess.Elb).DeleteLoadBalancer(input)
+		_, err := (sess.Elb).DeleteLoadBalancerWithContext(sess.Context(), input)
 
 		if err != nil {
 			return err
@@ -435,7 +490,7 @@ func (sess *EstablishedSession) DeleteAppLoadbalancer(lb *DeleteLoadbalancerInpu
 		input := &elbv2.DeleteLoadBalancerInput{
 			LoadBalancerArn: aws.String(lb.LbArn),
 		}
-		_, err := (sess.Elb2).DeleteLoadBalancer(input)
+		_, err := (sess.Elb2).DeleteLoadBalancerWithContext(sess.Context(), input)
 
 		if err != nil {
 			return err
@@ -453,14 +508,14 @@ func (sess *EstablishedSession) DeleteTargetGroup(lb *DeleteLoadbalancerInput) e
 			input := &elbv2.DeleteTargetGroupInput{
 				TargetGroupArn: aws.String(lb.TargetArn),
 			}
-			_, err := (sess.Elb2).DeleteTargetGroup(input)
+			_, err := (sess.Elb2).DeleteTargetGroupWithContext(sess.Context(), input)
 
 			if err != nil {
 				return err
 			}
 			return nil
 		}
-		return fmt.Errorf(fmt.Sprintf("%v DeleteTargetGroup", err.EmptyStructError()))
+		return err.New(err.InvalidInput, "You provided empty struct to DeleteTargetGroup, this is not acceptable")
 	}
 	return err.InvalidSession()
 }
@@ -473,18 +528,68 @@ func (sess *EstablishedSession) DeleteAppListeners(lb *DeleteLoadbalancerInput)
 			input := &elbv2.DeleteListenerInput{
 				ListenerArn: aws.String(lb.ListenerArn),
 			}
-			_, err := (sess.Elb2).DeleteListener(input)
+			_, err := (sess.Elb2).DeleteListenerWithContext(sess.Context(), input)
 
 			if err != nil {
 				return err
 			}
 			return nil
 		}
-		return fmt.Errorf(fmt.Sprintf("%v DeleteAppListeners", err.EmptyStructError()))
+		return err.New(err.InvalidInput, "You provided empty struct to DeleteAppListeners, this is not acceptable")
 	}
 	return err.InvalidSession()
 }
 
+// WaitUntilClassicLbInterfacesDeleted makes the method called this to wait till the network interfaces of the classic loadbalancers are released.
+// aws releases them a while after the loadbalancers are deleted, and the subnets holding them cannot be deleted until then.
+func (sess *EstablishedSession) WaitUntilClassicLbInterfacesDeleted(lb *DescribeLoadbalancersInput) (bool, error) {
+
+	if sess.Ec2 != nil {
+		if lb.LbNames != nil {
+			descriptions := make([]string, 0, len(lb.LbNames))
+			for _, name := range lb.LbNames {
+				descriptions = append(descriptions, "ELB "+name)
+			}
+			return sess.waitUntilLbInterfacesDeleted(fmt.Sprintf("interfaces of loadbalancers %v", lb.LbNames), descriptions)
+		}
+		return false, err.New(err.InvalidInput, "You provided empty struct to WaitUntilClassicLbInterfacesDeleted, this is not acceptable")
+	}
+	return false, err.InvalidSession()
+}
+
+// WaitUntilAppLbInterfacesDeleted makes the method called this to wait till the network interfaces of the application loadbalancers are released,
+// those are described after the ARN of the loadbalancer (ELB app/<name>/<id>). Like the classic ones, aws releases them a while after the deletion.
+func (sess *EstablishedSession) WaitUntilAppLbInterfacesDeleted(lb *DescribeLoadbalancersInput) (bool, error) {
+
+	if sess.Ec2 != nil {
+		if lb.LbArns != nil {
+			descriptions := make([]string, 0, len(lb.LbArns))
+			for _, arn := range lb.LbArns {
+				if index := strings.Index(arn, ":loadbalancer/"); index != -1 {
+					descriptions = append(descriptions, "ELB "+arn[index+len(":loadbalancer/"):])
+				}
+			}
+			return sess.waitUntilLbInterfacesDeleted(fmt.Sprintf("interfaces of loadbalancers %v", lb.LbArns), descriptions)
+		}
+		return false, err.New(err.InvalidInput, "You provided empty struct to WaitUntilAppLbInterfacesDeleted, this is not acceptable")
+	}
+	return false, err.InvalidSession()
+}
+
+// waitUntilLbInterfacesDeleted waits till no network interface carries any of the descriptions passed.
+func (sess *EstablishedSession) waitUntilLbInterfacesDeleted(what string, descriptions []string) (bool, error) {
+	input := &ec2.DescribeNetworkInterfacesInput{
+		Filters: []*ec2.Filter{{Name: aws.String("description"), Values: aws.StringSlice(descriptions)}},
+	}
+	return sess.waitUntilDeleted(what, "InvalidNetworkInterfaceID.NotFound", func(ctx context.Context) (int, error) {
+		response, deserr := (sess.Ec2).DescribeNetworkInterfacesWithContext(ctx, input)
+		if deserr != nil {
+			return 0, deserr
+		}
+		return len(response.NetworkInterfaces), nil
+	})
+}
+
 // WaitTillLbDeletionSuccessfull will make the method who called this to wait till the deletion of loadbalancer is successful.
 func (sess *EstablishedSession) WaitTillLbDeletionSuccessfull(lb *DescribeLoadbalancersInput) error {
 
@@ -492,7 +597,9 @@ func (sess *EstablishedSession) WaitTillLbDeletionSuccessfull(lb *DescribeLoadba
 		input := &elbv2.DescribeLoadBalancersInput{
 			LoadBalancerArns: aws.StringSlice(lb.LbArns),
 		}
-		err := (sess.Elb2).WaitUntilLoadBalancersDeleted(input)
+		err := sess.wait.AWS(sess.Context(), fmt.Sprintf("loadbalancers %v", lb.LbArns), func(ctx aws.Context, options ...request.WaiterOption) error {
+			return (sess.Elb2).WaitUntilLoadBalancersDeletedWithContext(ctx, input, options...)
+		})
 
 		if err != nil {
 			return err
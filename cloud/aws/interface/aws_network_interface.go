@@ -1,12 +1,13 @@
 package neuronaws
 
 import (
+	"context"
 	"fmt"
 	"reflect"
-	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	err "github.com/nikhilsbhat/neuron-cloudy/errors"
 )
@@ -36,6 +37,8 @@ type CreateNetworkInput struct {
 	DestinationCidr string
 	// RouteTableId is the ID of the routetable created or to be created.
 	RouteTableId string
+	// Description of the security group to be created, a default one is set if not passed.
+	Description string
 }
 
 // IngressEgressInput holds the required values for creating ingress/egress rule for the specified security group and implements the methods for the same.
@@ -44,6 +47,9 @@ type IngressEgressInput struct {
 	Port int64
 	// SecId refers to ID of security group to which the rule has to be applied.
 	SecId string
+	// Permissions are the rules to be authorized/revoked by AuthorizeIngress, RevokeIngress, AuthorizeEgress, RevokeEgress
+	// and the ones of which the descriptions has to be updated by UpdateRuleDescriptions, Port is not considered by these.
+	Permissions []*ec2.IpPermission
 }
 
 // DescribeNetworkInput holds all the required values for fetching the information about the selected network and its components.
@@ -63,6 +69,12 @@ type DescribeNetworkInput struct {
 	Filters Filters
 	// AssociationsId are the id's used to identify the RouteTable are are used while detaching RouteTable from the subnetwork.
 	AssociationsId string
+	// NatGatewayIds are the id's of the nat gateways created within the network.
+	NatGatewayIds []string
+	// NetworkInterfaceIds are the id's of the network interfaces created within the network.
+	NetworkInterfaceIds []string
+	// Pagination controls the size of the pages and the number of results fetched by DescribeAllVpc, DescribeAllSubnet and DescribeAllSecurityGroup.
+	Pagination
 }
 
 // CreateVpc will create the network/VPC in aws based on the values and session passed to it.
@@ -74,14 +86,14 @@ func (sess *EstablishedSession) CreateVpc(v *CreateNetworkInput) (*ec2.CreateVpc
 				CidrBlock:       aws.String(v.Cidr),
 				InstanceTenancy: aws.String(v.Tenancy),
 			}
-			result, err := (sess.Ec2).CreateVpc(input)
+			result, err := (sess.Ec2).CreateVpcWithContext(sess.Context(), input)
 
 			if err != nil {
 				return nil, err
 			}
 			return result, nil
 		}
-		return nil, fmt.Errorf(fmt.Sprintf("%v CreateVpc", err.EmptyStructError()))
+		return nil, err.New(err.InvalidInput, "You provided empty struct to CreateVpc, this is not acceptable")
 	}
 	return nil, err.InvalidSession()
 }
@@ -96,14 +108,14 @@ func (sess *EstablishedSession) CreateSubnet(s *CreateNetworkInput) (*ec2.Create
 				VpcId:            aws.String(s.VpcId),
 				AvailabilityZone: aws.String(s.Zone),
 			}
-			result, err := (sess.Ec2).CreateSubnet(input)
+			result, err := (sess.Ec2).CreateSubnetWithContext(sess.Context(), input)
 
 			if err != nil {
 				return nil, err
 			}
 			return result, nil
 		}
-		return nil, fmt.Errorf(fmt.Sprintf("%v CreateSubnet", err.EmptyStructError()))
+		return nil, err.New(err.InvalidInput, "You provided empty struct to CreateSubnet, this is not acceptable")
 	}
 	return nil, err.InvalidSession()
 }
@@ -113,7 +125,7 @@ func (sess *EstablishedSession) CreateIgw() (*ec2.CreateInternetGatewayOutput, e
 
 	if sess.Ec2 != nil {
 		input := &ec2.CreateInternetGatewayInput{}
-		result, err := (sess.Ec2).CreateInternetGateway(input)
+		result, err := (sess.Ec2).CreateInternetGatewayWithContext(sess.Context(), input)
 
 		if err != nil {
 			return nil, err
@@ -133,14 +145,14 @@ func (sess *EstablishedSession) AttachIgw(a *DescribeNetworkInput) error {
 				InternetGatewayId: aws.String(a.IgwIds[0]),
 				VpcId:             aws.String(a.VpcIds[0]),
 			}
-			_, err := (sess.Ec2).AttachInternetGateway(input)
+			_, err := (sess.Ec2).AttachInternetGatewayWithContext(sess.Context(), input)
 
 			if err != nil {
 				return err
 			}
 			return nil
 		}
-		return fmt.Errorf(fmt.Sprintf("%v AttachIgw", err.EmptyStructError()))
+		return err.New(err.InvalidInput, "You provided empty struct to AttachIgw, this is not acceptable")
 	}
 	return err.InvalidSession()
 }
@@ -150,12 +162,16 @@ func (sess *EstablishedSession) CreateSecurityGroup(s *CreateNetworkInput) (*ec2
 
 	if sess.Ec2 != nil {
 		if s.VpcId != "" {
+			description := s.Description
+			if description == "" {
+				description = "This security group is created by Neuron api"
+			}
 			input := &ec2.CreateSecurityGroupInput{
-				Description: aws.String("This security group is created by Neuron api"),
+				Description: aws.String(description),
 				VpcId:       aws.String(s.VpcId),
 				GroupName:   aws.String(s.Name),
 			}
-			result, err := (sess.Ec2).CreateSecurityGroup(input)
+			result, err := (sess.Ec2).CreateSecurityGroupWithContext(sess.Context(), input)
 
 			if err != nil {
 				return nil, err
@@ -163,7 +179,7 @@ func (sess *EstablishedSession) CreateSecurityGroup(s *CreateNetworkInput) (*ec2
 			return result, nil
 
 		}
-		return nil, fmt.Errorf(fmt.Sprintf("%v CreateSecurityGroup", err.EmptyStructError()))
+		return nil, err.New(err.InvalidInput, "You provided empty struct to CreateSecurityGroup, this is not acceptable")
 	}
 	return nil, err.InvalidSession()
 
@@ -177,14 +193,14 @@ func (sess *EstablishedSession) CreateRouteTable(r *CreateNetworkInput) (*ec2.Cr
 			input := &ec2.CreateRouteTableInput{
 				VpcId: aws.String(r.VpcId),
 			}
-			result, err := (sess.Ec2).CreateRouteTable(input)
+			result, err := (sess.Ec2).CreateRouteTableWithContext(sess.Context(), input)
 
 			if err != nil {
 				return nil, err
 			}
 			return result, nil
 		}
-		return nil, fmt.Errorf(fmt.Sprintf("%v CreateRouteTable", err.EmptyStructError()))
+		return nil, err.New(err.InvalidInput, "You provided empty struct to CreateRouteTable, this is not acceptable")
 	}
 	return nil, err.InvalidSession()
 }
@@ -199,18 +215,59 @@ func (sess *EstablishedSession) WriteRoute(r *CreateNetworkInput) error {
 				GatewayId:            aws.String(r.IgwId),
 				RouteTableId:         aws.String(r.RouteTableId),
 			}
-			_, err := (sess.Ec2).CreateRoute(input)
+			_, err := (sess.Ec2).CreateRouteWithContext(sess.Context(), input)
 			if err != nil {
 				return err
 			}
 			return nil
 		}
-		return fmt.Errorf(fmt.Sprintf("%v WriteRoute", err.EmptyStructError()))
+		return err.New(err.InvalidInput, "You provided empty struct to WriteRoute, this is not acceptable")
 	}
 	return err.InvalidSession()
 
 }
 
+// ReplaceRoute points the route of the specified route table to the internet gateway passed.
+func (sess *EstablishedSession) ReplaceRoute(r *CreateNetworkInput) error {
+
+	if sess.Ec2 != nil {
+		if r.RouteTableId != "" {
+			input := &ec2.ReplaceRouteInput{
+				DestinationCidrBlock: aws.String(r.DestinationCidr),
+				GatewayId:            aws.String(r.IgwId),
+				RouteTableId:         aws.String(r.RouteTableId),
+			}
+			_, err := (sess.Ec2).ReplaceRouteWithContext(sess.Context(), input)
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+		return err.New(err.InvalidInput, "You provided empty struct to ReplaceRoute, this is not acceptable")
+	}
+	return err.InvalidSession()
+}
+
+// DeleteRoute removes the route to the destination from the specified route table.
+func (sess *EstablishedSession) DeleteRoute(r *CreateNetworkInput) error {
+
+	if sess.Ec2 != nil {
+		if r.RouteTableId != "" {
+			input := &ec2.DeleteRouteInput{
+				DestinationCidrBlock: aws.String(r.DestinationCidr),
+				RouteTableId:         aws.String(r.RouteTableId),
+			}
+			_, err := (sess.Ec2).DeleteRouteWithContext(sess.Context(), input)
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+		return err.New(err.InvalidInput, "You provided empty struct to DeleteRoute, this is not acceptable")
+	}
+	return err.InvalidSession()
+}
+
 // AttachRouteTable attaches the route table to the specified subnetwork.
 func (sess *EstablishedSession) AttachRouteTable(r *CreateNetworkInput) error {
 
@@ -220,13 +277,13 @@ func (sess *EstablishedSession) AttachRouteTable(r *CreateNetworkInput) error {
 				RouteTableId: aws.String(r.RouteTableId),
 				SubnetId:     aws.String(r.SubId),
 			}
-			_, err := (sess.Ec2).AssociateRouteTable(input)
+			_, err := (sess.Ec2).AssociateRouteTableWithContext(sess.Context(), input)
 			if err != nil {
 				return err
 			}
 			return nil
 		}
-		return fmt.Errorf(fmt.Sprintf("%v AttachRouteTable", err.EmptyStructError()))
+		return err.New(err.InvalidInput, "You provided empty struct to AttachRouteTable, this is not acceptable")
 	}
 	return err.InvalidSession()
 }
@@ -239,37 +296,36 @@ func (sess *EstablishedSession) DettachRouteTable(r *DescribeNetworkInput) error
 			input := &ec2.DisassociateRouteTableInput{
 				AssociationId: aws.String(r.AssociationsId),
 			}
-			_, err := (sess.Ec2).DisassociateRouteTable(input)
+			_, err := (sess.Ec2).DisassociateRouteTableWithContext(sess.Context(), input)
 			if err != nil {
 				return err
 			}
 			return nil
 		}
-		return fmt.Errorf(fmt.Sprintf("%v AttachRouteTable", err.EmptyStructError()))
+		return err.New(err.InvalidInput, "You provided empty struct to AttachRouteTable, this is not acceptable")
 	}
 	return err.InvalidSession()
 }
 
-// CreateEgressRule creates the egress rule with the specified configuration.
+// CreateEgressRule creates the egress rule opening all the outgoing traffic, aws sets the same rule on the security groups it creates
+// hence this fails with InvalidPermission.Duplicate unless the rule was revoked.
 func (sess *EstablishedSession) CreateEgressRule(i *IngressEgressInput) error {
 
 	if sess.Ec2 != nil {
-		securityIngressInput := &ec2.AuthorizeSecurityGroupEgressInput{
-			//    FromPort   : aws.int64(from_port),
+		securityEgressInput := &ec2.AuthorizeSecurityGroupEgressInput{
 			GroupId: aws.String(i.SecId),
-			//    ToPort     : aws.int64(to_port),
 			IpPermissions: []*ec2.IpPermission{
 				{
 					IpProtocol: aws.String("-1"),
 					IpRanges: []*ec2.IpRange{
 						{
-							//CidrIp: aws.String("0.0.0.0/0"),
+							CidrIp: aws.String("0.0.0.0/0"),
 						},
 					},
 				},
 			},
 		}
-		_, egressErr := (sess.Ec2).AuthorizeSecurityGroupEgress(securityIngressInput)
+		_, egressErr := (sess.Ec2).AuthorizeSecurityGroupEgressWithContext(sess.Context(), securityEgressInput)
 
 		if egressErr != nil {
 			return egressErr
@@ -291,7 +347,7 @@ func (sess *EstablishedSession) CreateIngressRule(i *IngressEgressInput) error {
 			ToPort:     aws.Int64(i.Port),
 			CidrIp:     aws.String("0.0.0.0/0"),
 		}
-		_, ingressErr := (sess.Ec2).AuthorizeSecurityGroupIngress(securityIngressInput)
+		_, ingressErr := (sess.Ec2).AuthorizeSecurityGroupIngressWithContext(sess.Context(), securityIngressInput)
 
 		if ingressErr != nil {
 			return ingressErr
@@ -302,6 +358,136 @@ func (sess *EstablishedSession) CreateIngressRule(i *IngressEgressInput) error {
 
 }
 
+// DeleteIngressRule removes the ingress rule created by CreateIngressRule with the specified configurations.
+func (sess *EstablishedSession) DeleteIngressRule(i *IngressEgressInput) error {
+
+	if sess.Ec2 != nil {
+		securityIngressInput := &ec2.RevokeSecurityGroupIngressInput{
+			FromPort:   aws.Int64(i.Port),
+			IpProtocol: aws.String("tcp"),
+			GroupId:    aws.String(i.SecId),
+			ToPort:     aws.Int64(i.Port),
+			CidrIp:     aws.String("0.0.0.0/0"),
+		}
+		_, revokeErr := (sess.Ec2).RevokeSecurityGroupIngressWithContext(sess.Context(), securityIngressInput)
+
+		if revokeErr != nil {
+			return revokeErr
+		}
+		return nil
+	}
+	return err.InvalidSession()
+
+}
+
+// AuthorizeIngress adds the ingress rules passed in Permissions to the security group.
+func (sess *EstablishedSession) AuthorizeIngress(i *IngressEgressInput) error {
+
+	if sess.Ec2 != nil {
+		if (i.SecId != "") && (len(i.Permissions) != 0) {
+			input := &ec2.AuthorizeSecurityGroupIngressInput{
+				GroupId:       aws.String(i.SecId),
+				IpPermissions: i.Permissions,
+			}
+			_, ingressErr := (sess.Ec2).AuthorizeSecurityGroupIngressWithContext(sess.Context(), input)
+			if ingressErr != nil {
+				return ingressErr
+			}
+			return nil
+		}
+		return err.New(err.InvalidInput, "You provided empty struct to AuthorizeIngress, this is not acceptable")
+	}
+	return err.InvalidSession()
+}
+
+// RevokeIngress removes the ingress rules passed in Permissions from the security group.
+func (sess *EstablishedSession) RevokeIngress(i *IngressEgressInput) error {
+
+	if sess.Ec2 != nil {
+		if (i.SecId != "") && (len(i.Permissions) != 0) {
+			input := &ec2.RevokeSecurityGroupIngressInput{
+				GroupId:       aws.String(i.SecId),
+				IpPermissions: i.Permissions,
+			}
+			_, revokeErr := (sess.Ec2).RevokeSecurityGroupIngressWithContext(sess.Context(), input)
+			if revokeErr != nil {
+				return revokeErr
+			}
+			return nil
+		}
+		return err.New(err.InvalidInput, "You provided empty struct to RevokeIngress, this is not acceptable")
+	}
+	return err.InvalidSession()
+}
+
+// AuthorizeEgress adds the egress rules passed in Permissions to the security group.
+func (sess *EstablishedSession) AuthorizeEgress(i *IngressEgressInput) error {
+
+	if sess.Ec2 != nil {
+		if (i.SecId != "") && (len(i.Permissions) != 0) {
+			input := &ec2.AuthorizeSecurityGroupEgressInput{
+				GroupId:       aws.String(i.SecId),
+				IpPermissions: i.Permissions,
+			}
+			_, egressErr := (sess.Ec2).AuthorizeSecurityGroupEgressWithContext(sess.Context(), input)
+			if egressErr != nil {
+				return egressErr
+			}
+			return nil
+		}
+		return err.New(err.InvalidInput, "You provided empty struct to AuthorizeEgress, this is not acceptable")
+	}
+	return err.InvalidSession()
+}
+
+// RevokeEgress removes the egress rules passed in Permissions from the security group.
+func (sess *EstablishedSession) RevokeEgress(i *IngressEgressInput) error {
+
+	if sess.Ec2 != nil {
+		if (i.SecId != "") && (len(i.Permissions) != 0) {
+			input := &ec2.RevokeSecurityGroupEgressInput{
+				GroupId:       aws.String(i.SecId),
+				IpPermissions: i.Permissions,
+			}
+			_, revokeErr := (sess.Ec2).RevokeSecurityGroupEgressWithContext(sess.Context(), input)
+			if revokeErr != nil {
+				return revokeErr
+			}
+			return nil
+		}
+		return err.New(err.InvalidInput, "You provided empty struct to RevokeEgress, this is not acceptable")
+	}
+	return err.InvalidSession()
+}
+
+// UpdateRuleDescriptions replaces the descriptions of the rules of the security group with the ones passed in Permissions,
+// the rules are the egress ones if egress is set else the ingress ones.
+func (sess *EstablishedSession) UpdateRuleDescriptions(i *IngressEgressInput, egress bool) error {
+
+	if sess.Ec2 != nil {
+		if (i.SecId != "") && (len(i.Permissions) != 0) {
+			var updateErr error
+			if egress {
+				_, updateErr = (sess.Ec2).UpdateSecurityGroupRuleDescriptionsEgressWithContext(sess.Context(), &ec2.UpdateSecurityGroupRuleDescriptionsEgressInput{
+					GroupId:       aws.String(i.SecId),
+					IpPermissions: i.Permissions,
+				})
+			} else {
+				_, updateErr = (sess.Ec2).UpdateSecurityGroupRuleDescriptionsIngressWithContext(sess.Context(), &ec2.UpdateSecurityGroupRuleDescriptionsIngressInput{
+					GroupId:       aws.String(i.SecId),
+					IpPermissions: i.Permissions,
+				})
+			}
+			if updateErr != nil {
+				return updateErr
+			}
+			return nil
+		}
+		return err.New(err.InvalidInput, "You provided empty struct to UpdateRuleDescriptions, this is not acceptable")
+	}
+	return err.InvalidSession()
+}
+
 // DeleteIgw deletes the specified internetgateway selected.
 func (sess *EstablishedSession) DeleteIgw(i *DescribeNetworkInput) error {
 
@@ -311,13 +497,13 @@ func (sess *EstablishedSession) DeleteIgw(i *DescribeNetworkInput) error {
 			input := &ec2.DeleteInternetGatewayInput{
 				InternetGatewayId: aws.String(i.IgwIds[0]),
 			}
-			_, err := (sess.Ec2).DeleteInternetGateway(input)
+			_, err := (sess.Ec2).DeleteInternetGatewayWithContext(sess.Context(), input)
 			if err != nil {
 				return err
 			}
 			return nil
 		}
-		return fmt.Errorf(fmt.Sprintf("%v DeleteIgw", err.EmptyStructError()))
+		return err.New(err.InvalidInput, "You provided empty struct to DeleteIgw, this is not acceptable")
 	}
 	return err.InvalidSession()
 
@@ -333,13 +519,13 @@ func (sess *EstablishedSession) DetachIgw(i *DescribeNetworkInput) error {
 				InternetGatewayId: aws.String(i.IgwIds[0]),
 				VpcId:             aws.String(i.VpcIds[0]),
 			}
-			_, err := (sess.Ec2).DetachInternetGateway(input)
+			_, err := (sess.Ec2).DetachInternetGatewayWithContext(sess.Context(), input)
 			if err != nil {
 				return err
 			}
 			return nil
 		}
-		return fmt.Errorf(fmt.Sprintf("%v DetachIgw", err.EmptyStructError()))
+		return err.New(err.InvalidInput, "You provided empty struct to DetachIgw, this is not acceptable")
 	}
 	return err.InvalidSession()
 
@@ -354,7 +540,7 @@ func (sess *EstablishedSession) DescribeIgw(d *DescribeNetworkInput) (*ec2.Descr
 			input := &ec2.DescribeInternetGatewaysInput{
 				InternetGatewayIds: aws.StringSlice(d.IgwIds),
 			}
-			result, err := (sess.Ec2).DescribeInternetGateways(input)
+			result, err := (sess.Ec2).DescribeInternetGatewaysWithContext(sess.Context(), input)
 
 			if err != nil {
 				return nil, err
@@ -363,7 +549,7 @@ func (sess *EstablishedSession) DescribeIgw(d *DescribeNetworkInput) (*ec2.Descr
 		}
 
 		if reflect.DeepEqual(d.Filters, Filters{}) {
-			return nil, fmt.Errorf(fmt.Sprintf("%v DescribeIgw. You selected filters for this yet you passed empty", err.EmptyStructError()))
+			return nil, err.New(err.InvalidInput, "You selected filters for DescribeIgw and did not provide the values for them, this is not acceptable")
 		}
 		input := &ec2.DescribeInternetGatewaysInput{
 			Filters: []*ec2.Filter{
@@ -373,7 +559,7 @@ func (sess *EstablishedSession) DescribeIgw(d *DescribeNetworkInput) (*ec2.Descr
 				},
 			},
 		}
-		result, err := (sess.Ec2).DescribeInternetGateways(input)
+		result, err := (sess.Ec2).DescribeInternetGatewaysWithContext(sess.Context(), input)
 
 		if err != nil {
 			return nil, err
@@ -389,7 +575,7 @@ func (sess *EstablishedSession) DescribeAllIgw(d *DescribeNetworkInput) (*ec2.De
 
 	if sess.Ec2 != nil {
 		input := &ec2.DescribeInternetGatewaysInput{}
-		result, err := (sess.Ec2).DescribeInternetGateways(input)
+		result, err := (sess.Ec2).DescribeInternetGatewaysWithContext(sess.Context(), input)
 
 		if err != nil {
 			return nil, err
@@ -407,14 +593,14 @@ func (sess *EstablishedSession) DeleteRouteTable(r *DescribeNetworkInput) error
 			input := &ec2.DeleteRouteTableInput{
 				RouteTableId: aws.String(r.RouteTableIds[0]),
 			}
-			_, err := (sess.Ec2).DeleteRouteTable(input)
+			_, err := (sess.Ec2).DeleteRouteTableWithContext(sess.Context(), input)
 			if err != nil {
 				return err
 			}
 			return nil
 
 		}
-		return fmt.Errorf(fmt.Sprintf("%v DeleteRouteTable", err.EmptyStructError()))
+		return err.New(err.InvalidInput, "You provided empty struct to DeleteRouteTable, this is not acceptable")
 	}
 	return err.InvalidSession()
 }
@@ -427,7 +613,7 @@ func (sess *EstablishedSession) DescribeRouteTable(d *DescribeNetworkInput) (*ec
 			input := &ec2.DescribeRouteTablesInput{
 				RouteTableIds: aws.StringSlice(d.RouteTableIds),
 			}
-			result, err := (sess.Ec2).DescribeRouteTables(input)
+			result, err := (sess.Ec2).DescribeRouteTablesWithContext(sess.Context(), input)
 			if err != nil {
 				return nil, err
 			}
@@ -435,7 +621,7 @@ func (sess *EstablishedSession) DescribeRouteTable(d *DescribeNetworkInput) (*ec
 		}
 
 		if reflect.DeepEqual(d.Filters, Filters{}) {
-			return nil, fmt.Errorf(fmt.Sprintf("%v DescribeRouteTable. You selected filters for this yet you passed empty", err.EmptyStructError()))
+			return nil, err.New(err.InvalidInput, "You selected filters for DescribeRouteTable and did not provide the values for them, this is not acceptable")
 		}
 		input := &ec2.DescribeRouteTablesInput{
 			Filters: []*ec2.Filter{
@@ -445,7 +631,7 @@ func (sess *EstablishedSession) DescribeRouteTable(d *DescribeNetworkInput) (*ec
 				},
 			},
 		}
-		result, err := (sess.Ec2).DescribeRouteTables(input)
+		result, err := (sess.Ec2).DescribeRouteTablesWithContext(sess.Context(), input)
 
 		if err != nil {
 			return nil, err
@@ -464,15 +650,15 @@ func (sess *EstablishedSession) DeleteSecurityGroup(s *DescribeNetworkInput) err
 				input := &ec2.DeleteSecurityGroupInput{
 					GroupId: aws.String(sec),
 				}
-				_, err := (sess.Ec2).DeleteSecurityGroup(input)
+				_, err := (sess.Ec2).DeleteSecurityGroupWithContext(sess.Context(), input)
 				if err != nil {
 					return err
 				}
-				return nil
 			}
+			return nil
 		}
 
-		return fmt.Errorf(fmt.Sprintf("%v DeleteSecurityGroup", err.EmptyStructError()))
+		return err.New(err.InvalidInput, "You provided empty struct to DeleteSecurityGroup, this is not acceptable")
 	}
 	return err.InvalidSession()
 
@@ -486,7 +672,7 @@ func (sess *EstablishedSession) DescribeSecurityGroup(d *DescribeNetworkInput) (
 			input := &ec2.DescribeSecurityGroupsInput{
 				GroupIds: aws.StringSlice(d.SecIds),
 			}
-			result, err := (sess.Ec2).DescribeSecurityGroups(input)
+			result, err := (sess.Ec2).DescribeSecurityGroupsWithContext(sess.Context(), input)
 			if err != nil {
 				return nil, err
 			}
@@ -495,7 +681,7 @@ func (sess *EstablishedSession) DescribeSecurityGroup(d *DescribeNetworkInput) (
 		}
 
 		if reflect.DeepEqual(d.Filters, Filters{}) {
-			return nil, fmt.Errorf(fmt.Sprintf("%v DescribeSecurityGroup. You selected filters for this yet you passed empty", err.EmptyStructError()))
+			return nil, err.New(err.InvalidInput, "You selected filters for DescribeSecurityGroup and did not provide the values for them, this is not acceptable")
 		}
 		input := &ec2.DescribeSecurityGroupsInput{
 			Filters: []*ec2.Filter{
@@ -505,7 +691,7 @@ func (sess *EstablishedSession) DescribeSecurityGroup(d *DescribeNetworkInput) (
 				},
 			},
 		}
-		result, err := (sess.Ec2).DescribeSecurityGroups(input)
+		result, err := (sess.Ec2).DescribeSecurityGroupsWithContext(sess.Context(), input)
 		if err != nil {
 			return nil, err
 		}
@@ -516,31 +702,33 @@ func (sess *EstablishedSession) DescribeSecurityGroup(d *DescribeNetworkInput) (
 }
 
 // DescribeAllSecurityGroup fetches the information about all the security group present in the specified region.
+// All the pages are fetched, use EachSecurityGroup to visit them without holding all of them.
 func (sess *EstablishedSession) DescribeAllSecurityGroup(d *DescribeNetworkInput) (*ec2.DescribeSecurityGroupsOutput, error) {
 
-	if sess.Ec2 != nil {
-		input := &ec2.DescribeSecurityGroupsInput{}
-		result, err := (sess.Ec2).DescribeSecurityGroups(input)
-		if err != nil {
-			return nil, err
-		}
-		return result, nil
+	result := &ec2.DescribeSecurityGroupsOutput{SecurityGroups: make([]*ec2.SecurityGroup, 0)}
+	eacherr := sess.EachSecurityGroup(d, func(item *ec2.SecurityGroup) bool {
+		result.SecurityGroups = append(result.SecurityGroups, item)
+		return true
+	})
+	if eacherr != nil {
+		return nil, eacherr
 	}
-	return nil, err.InvalidSession()
+	return result, nil
 }
 
 // DescribeAllSubnet fetches the information about all the subnetnetworks present in the specified region.
+// All the pages are fetched, use EachSubnet to visit them without holding all of them.
 func (sess *EstablishedSession) DescribeAllSubnet(d *DescribeNetworkInput) (*ec2.DescribeSubnetsOutput, error) {
 
-	if sess.Ec2 != nil {
-		input := &ec2.DescribeSubnetsInput{}
-		result, err := (sess.Ec2).DescribeSubnets(input)
-		if err != nil {
-			return nil, err
-		}
-		return result, nil
+	result := &ec2.DescribeSubnetsOutput{Subnets: make([]*ec2.Subnet, 0)}
+	eacherr := sess.EachSubnet(d, func(item *ec2.Subnet) bool {
+		result.Subnets = append(result.Subnets, item)
+		return true
+	})
+	if eacherr != nil {
+		return nil, eacherr
 	}
-	return nil, err.InvalidSession()
+	return result, nil
 }
 
 // DescribeSubnet fetches the information about the selected subnetwork. This is achieved by describing it.
@@ -551,7 +739,7 @@ func (sess *EstablishedSession) DescribeSubnet(d *DescribeNetworkInput) (*ec2.De
 			input := &ec2.DescribeSubnetsInput{
 				SubnetIds: aws.StringSlice(d.SubnetIds),
 			}
-			result, err := (sess.Ec2).DescribeSubnets(input)
+			result, err := (sess.Ec2).DescribeSubnetsWithContext(sess.Context(), input)
 			if err != nil {
 				return nil, err
 			}
@@ -559,7 +747,7 @@ func (sess *EstablishedSession) DescribeSubnet(d *DescribeNetworkInput) (*ec2.De
 		}
 
 		if reflect.DeepEqual(d.Filters, Filters{}) {
-			return nil, fmt.Errorf(fmt.Sprintf("%v DescribeSubnet. You selected filters for this yet you passed empty", err.EmptyStructError()))
+			return nil, err.New(err.InvalidInput, "You selected filters for DescribeSubnet and did not provide the values for them, this is not acceptable")
 		}
 		input := &ec2.DescribeSubnetsInput{
 			Filters: []*ec2.Filter{
@@ -569,7 +757,7 @@ func (sess *EstablishedSession) DescribeSubnet(d *DescribeNetworkInput) (*ec2.De
 				},
 			},
 		}
-		result, err := (sess.Ec2).DescribeSubnets(input)
+		result, err := (sess.Ec2).DescribeSubnetsWithContext(sess.Context(), input)
 		if err != nil {
 			return nil, err
 		}
@@ -587,29 +775,30 @@ func (sess *EstablishedSession) DeleteSubnet(d *DescribeNetworkInput) error {
 			input := &ec2.DeleteSubnetInput{
 				SubnetId: aws.String(d.SubnetIds[0]),
 			}
-			_, err := (sess.Ec2).DeleteSubnet(input)
+			_, err := (sess.Ec2).DeleteSubnetWithContext(sess.Context(), input)
 			if err != nil {
 				return err
 			}
 			return nil
 		}
-		return fmt.Errorf(fmt.Sprintf("%v DeleteSubnet", err.EmptyStructError()))
+		return err.New(err.InvalidInput, "You provided empty struct to DeleteSubnet, this is not acceptable")
 	}
 	return err.InvalidSession()
 }
 
 // DescribeAllVpc fetches the required information of all network present in the selected region. This is achieved by describing it.
+// All the pages are fetched, use EachVpc to visit them without holding all of them.
 func (sess *EstablishedSession) DescribeAllVpc(d *DescribeNetworkInput) (*ec2.DescribeVpcsOutput, error) {
 
-	if sess.Ec2 != nil {
-		input := &ec2.DescribeVpcsInput{}
-		result, err := (sess.Ec2).DescribeVpcs(input)
-		if err != nil {
-			return nil, err
-		}
-		return result, nil
+	result := &ec2.DescribeVpcsOutput{Vpcs: make([]*ec2.Vpc, 0)}
+	eacherr := sess.EachVpc(d, func(item *ec2.Vpc) bool {
+		result.Vpcs = append(result.Vpcs, item)
+		return true
+	})
+	if eacherr != nil {
+		return nil, eacherr
 	}
-	return nil, err.InvalidSession()
+	return result, nil
 }
 
 // DescribeVpc fetch the information about the specified network.
@@ -620,7 +809,7 @@ func (sess *EstablishedSession) DescribeVpc(d *DescribeNetworkInput) (*ec2.Descr
 			input := &ec2.DescribeVpcsInput{
 				VpcIds: aws.StringSlice(d.VpcIds),
 			}
-			result, err := (sess.Ec2).DescribeVpcs(input)
+			result, err := (sess.Ec2).DescribeVpcsWithContext(sess.Context(), input)
 			if err != nil {
 				return nil, err
 			}
@@ -628,7 +817,7 @@ func (sess *EstablishedSession) DescribeVpc(d *DescribeNetworkInput) (*ec2.Descr
 		}
 
 		if reflect.DeepEqual(d.Filters, Filters{}) {
-			return nil, fmt.Errorf(fmt.Sprintf("%v DescribeVpc. You selected filters for this yet you passed empty", err.EmptyStructError()))
+			return nil, err.New(err.InvalidInput, "You selected filters for DescribeVpc and did not provide the values for them, this is not acceptable")
 		}
 		input := &ec2.DescribeVpcsInput{
 			Filters: []*ec2.Filter{
@@ -638,7 +827,7 @@ func (sess *EstablishedSession) DescribeVpc(d *DescribeNetworkInput) (*ec2.Descr
 				},
 			},
 		}
-		result, err := (sess.Ec2).DescribeVpcs(input)
+		result, err := (sess.Ec2).DescribeVpcsWithContext(sess.Context(), input)
 		if err != nil {
 			return nil, err
 		}
@@ -656,14 +845,53 @@ func (sess *EstablishedSession) DeleteVpc(d *DescribeNetworkInput) error {
 				input := &ec2.DeleteVpcInput{
 					VpcId: aws.String(vpc),
 				}
-				_, err := (sess.Ec2).DeleteVpc(input)
+				_, err := (sess.Ec2).DeleteVpcWithContext(sess.Context(), input)
 				if err != nil {
 					return err
 				}
-				return nil
 			}
+			return nil
+		}
+		return err.New(err.InvalidInput, "You provided empty struct to DeleteVpc, this is not acceptable")
+	}
+	return err.InvalidSession()
+}
+
+// AssociateVpcCidr associates the secondary CIDR block passed with the network.
+func (sess *EstablishedSession) AssociateVpcCidr(v *CreateNetworkInput) (*ec2.AssociateVpcCidrBlockOutput, error) {
+
+	if sess.Ec2 != nil {
+		if (v.VpcId != "") && (v.Cidr != "") {
+			input := &ec2.AssociateVpcCidrBlockInput{
+				CidrBlock: aws.String(v.Cidr),
+				VpcId:     aws.String(v.VpcId),
+			}
+			result, err := (sess.Ec2).AssociateVpcCidrBlockWithContext(sess.Context(), input)
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
 		}
-		return fmt.Errorf(fmt.Sprintf("%v DeleteVpc", err.EmptyStructError()))
+		return nil, err.New(err.InvalidInput, "You provided empty struct to AssociateVpcCidr, this is not acceptable")
+	}
+	return nil, err.InvalidSession()
+}
+
+// DisassociateVpcCidr removes the secondary CIDR block identified by the association ID from the network it is associated with.
+func (sess *EstablishedSession) DisassociateVpcCidr(d *DescribeNetworkInput) error {
+
+	if sess.Ec2 != nil {
+		if d.AssociationsId != "" {
+			input := &ec2.DisassociateVpcCidrBlockInput{
+				AssociationId: aws.String(d.AssociationsId),
+			}
+			_, err := (sess.Ec2).DisassociateVpcCidrBlockWithContext(sess.Context(), input)
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+		return err.New(err.InvalidInput, "You provided empty struct to DisassociateVpcCidr, this is not acceptable")
 	}
 	return err.InvalidSession()
 }
@@ -674,7 +902,7 @@ func (sess *EstablishedSession) WaitTillVpcAvailable(d *DescribeNetworkInput) er
 	if sess.Ec2 != nil {
 
 		if reflect.DeepEqual(d.Filters, Filters{}) {
-			return fmt.Errorf(fmt.Sprintf("%v WaitTillVpcAvailable", err.EmptyStructError()))
+			return err.New(err.InvalidInput, "You provided empty struct to WaitTillVpcAvailable, this is not acceptable")
 		}
 		input := &ec2.DescribeVpcsInput{
 			Filters: []*ec2.Filter{
@@ -684,7 +912,9 @@ func (sess *EstablishedSession) WaitTillVpcAvailable(d *DescribeNetworkInput) er
 				},
 			},
 		}
-		err := (sess.Ec2).WaitUntilVpcAvailable(input)
+		err := sess.wait.AWS(sess.Context(), fmt.Sprintf("vpcs with %s %v", d.Filters.Name, d.Filters.Value), func(ctx aws.Context, options ...request.WaiterOption) error {
+			return (sess.Ec2).WaitUntilVpcAvailableWithContext(ctx, input, options...)
+		})
 		if err != nil {
 			return err
 		}
@@ -698,7 +928,7 @@ func (sess *EstablishedSession) WaitTillSubnetAvailable(d *DescribeNetworkInput)
 
 	if sess.Ec2 != nil {
 		if reflect.DeepEqual(d.Filters, Filters{}) {
-			return fmt.Errorf(fmt.Sprintf("%v WaitTillSubnetAvailable", err.EmptyStructError()))
+			return err.New(err.InvalidInput, "You provided empty struct to WaitTillSubnetAvailable, this is not acceptable")
 		}
 		input := &ec2.DescribeSubnetsInput{
 			Filters: []*ec2.Filter{
@@ -708,7 +938,9 @@ func (sess *EstablishedSession) WaitTillSubnetAvailable(d *DescribeNetworkInput)
 				},
 			},
 		}
-		err := (sess.Ec2).WaitUntilSubnetAvailable(input)
+		err := sess.wait.AWS(sess.Context(), fmt.Sprintf("subnets with %s %v", d.Filters.Name, d.Filters.Value), func(ctx aws.Context, options ...request.WaiterOption) error {
+			return (sess.Ec2).WaitUntilSubnetAvailableWithContext(ctx, input, options...)
+		})
 		if err != nil {
 			return err
 		}
@@ -718,6 +950,7 @@ func (sess *EstablishedSession) WaitTillSubnetAvailable(d *DescribeNetworkInput)
 }
 
 // WaitUntilSubnetDeleted makes the method called this to wait till the subnet is successfully deleted.
+// The subnets are polled as per the poller of the session, till aws reports them missing or the poller gives up.
 func (sess *EstablishedSession) WaitUntilSubnetDeleted(d *DescribeNetworkInput) (bool, error) {
 
 	if sess.Ec2 != nil {
@@ -725,41 +958,21 @@ func (sess *EstablishedSession) WaitUntilSubnetDeleted(d *DescribeNetworkInput)
 			input := &ec2.DescribeSubnetsInput{
 				SubnetIds: aws.StringSlice(d.SubnetIds),
 			}
-
-			response, deserr := (sess.Ec2).DescribeSubnets(input)
-			if response.Subnets != nil {
-				start := time.Now()
-				for len(response.Subnets) > 0 {
-					response, deserr = (sess.Ec2).DescribeSubnets(input)
-					if deserr != nil {
-						switch deserr.(awserr.Error).Code() {
-						case "InvalidSubnetID.NotFound":
-							return true, nil
-						default:
-							return false, deserr
-						}
-					}
-					if time.Since(start) > time.Duration(10*time.Second) {
-						return false, fmt.Errorf("Time Out .Oops...!! it took annoyingly more than anticipated time while waiting for subnet to get deleted. Guess I was not called after delete subnet function")
-					}
-				}
-			}
-			if deserr != nil {
-				switch deserr.(awserr.Error).Code() {
-				case "InvalidSubnetID.NotFound":
-					return true, nil
-				default:
-					return false, deserr
+			return sess.waitUntilDeleted(fmt.Sprintf("subnets %v", d.SubnetIds), "InvalidSubnetID.NotFound", func(ctx context.Context) (int, error) {
+				response, deserr := (sess.Ec2).DescribeSubnetsWithContext(ctx, input)
+				if deserr != nil {
+					return 0, deserr
 				}
-			}
-			return false, fmt.Errorf("Error occurred while waiting for the subnet deletion")
+				return len(response.Subnets), nil
+			})
 		}
-		return false, fmt.Errorf(fmt.Sprintf("%v WaitUntilSubnetDeleted", err.EmptyStructError()))
+		return false, err.New(err.InvalidInput, "You provided empty struct to WaitUntilSubnetDeleted, this is not acceptable")
 	}
 	return false, err.InvalidSession()
 }
 
 // WaitUntilRoutTableDeleted makes the method called this to wait till the route table is successfully deleted.
+// The route tables are polled as per the poller of the session, till aws reports them missing or the poller gives up.
 func (sess *EstablishedSession) WaitUntilRoutTableDeleted(d *DescribeNetworkInput) (bool, error) {
 
 	if sess.Ec2 != nil {
@@ -767,41 +980,21 @@ func (sess *EstablishedSession) WaitUntilRoutTableDeleted(d *DescribeNetworkInpu
 			input := &ec2.DescribeRouteTablesInput{
 				RouteTableIds: aws.StringSlice(d.RouteTableIds),
 			}
-
-			response, deserr := (sess.Ec2).DescribeRouteTables(input)
-			if response.RouteTables != nil {
-				start := time.Now()
-				for len(response.RouteTables) > 0 {
-					response, deserr = (sess.Ec2).DescribeRouteTables(input)
-					if deserr != nil {
-						switch deserr.(awserr.Error).Code() {
-						case "InvalidRouteTableID.NotFound":
-							return true, nil
-						default:
-							return false, deserr
-						}
-					}
-					if time.Since(start) > time.Duration(10*time.Second) {
-						return false, fmt.Errorf("Time Out .Oops...!! it took annoyingly more than anticipated time while waiting for routetable to get deleted. Guess I was not called after delete routetable function")
-					}
+			return sess.waitUntilDeleted(fmt.Sprintf("routetables %v", d.RouteTableIds), "InvalidRouteTableID.NotFound", func(ctx context.Context) (int, error) {
+				response, deserr := (sess.Ec2).DescribeRouteTablesWithContext(ctx, input)
+				if deserr != nil {
+					return 0, deserr
 				}
-			}
-			if deserr != nil {
-				switch deserr.(awserr.Error).Code() {
-				case "InvalidRouteTableID.NotFound":
-					return true, nil
-				default:
-					return false, deserr
-				}
-			}
-			return false, fmt.Errorf("Error occurred while waiting for the routetable deletion")
+				return len(response.RouteTables), nil
+			})
 		}
-		return false, fmt.Errorf(fmt.Sprintf("%v WaitUntilRoutTableDeleted", err.EmptyStructError()))
+		return false, err.New(err.InvalidInput, "You provided empty struct to WaitUntilRoutTableDeleted, this is not acceptable")
 	}
 	return false, err.InvalidSession()
 }
 
 // WaitUntilIgwDeleted makes the method called this to wait until internetgateway deletion is successful.
+// The internetgateways are polled as per the poller of the session, till aws reports them missing or the poller gives up.
 func (sess *EstablishedSession) WaitUntilIgwDeleted(d *DescribeNetworkInput) (bool, error) {
 
 	if sess.Ec2 != nil {
@@ -809,36 +1002,173 @@ func (sess *EstablishedSession) WaitUntilIgwDeleted(d *DescribeNetworkInput) (bo
 			input := &ec2.DescribeInternetGatewaysInput{
 				InternetGatewayIds: aws.StringSlice(d.IgwIds),
 			}
+			return sess.waitUntilDeleted(fmt.Sprintf("internetgateways %v", d.IgwIds), "InvalidInternetGatewayID.NotFound", func(ctx context.Context) (int, error) {
+				response, deserr := (sess.Ec2).DescribeInternetGatewaysWithContext(ctx, input)
+				if deserr != nil {
+					return 0, deserr
+				}
+				return len(response.InternetGateways), nil
+			})
+		}
+		return false, err.New(err.InvalidInput, "You provided empty struct to WaitUntilIgwDeleted, this is not acceptable")
+	}
+	return false, err.InvalidSession()
+}
 
-			response, deserr := (sess.Ec2).DescribeInternetGateways(input)
-			if response.InternetGateways != nil {
-				start := time.Now()
-				for len(response.InternetGateways) > 0 {
-					response, deserr = (sess.Ec2).DescribeInternetGateways(input)
-					if deserr != nil {
-						switch deserr.(awserr.Error).Code() {
-						case "InvalidInternetGatewayID.NotFound":
-							return true, nil
-						default:
-							return false, deserr
-						}
-					}
-					if time.Since(start) > time.Duration(10*time.Second) {
-						return false, fmt.Errorf("Time Out .Oops...!! it took annoyingly more than anticipated time while waiting for igw to get deleted. Guess I was not called after delete igw function")
+// waitUntilDeleted polls the resources with describe till none of them is left, which is when aws either returns none or fails with the code notFound.
+func (sess *EstablishedSession) waitUntilDeleted(resource, notFound string, describe func(ctx context.Context) (int, error)) (bool, error) {
+	waiterr := sess.wait.Until(sess.Context(), resource, func(ctx context.Context) (bool, string, error) {
+		remaining, deserr := describe(ctx)
+		if deserr != nil {
+			if awsErr, ok := deserr.(awserr.Error); ok && awsErr.Code() == notFound {
+				return true, "deleted", nil
+			}
+			return false, "", deserr
+		}
+		if remaining == 0 {
+			return true, "deleted", nil
+		}
+		return false, fmt.Sprintf("with %d yet to be deleted", remaining), nil
+	})
+	if waiterr != nil {
+		return false, waiterr
+	}
+	return true, nil
+}
+
+// DescribeNatGateways fetches the information about the nat gateways selected either by its id's or the filters.
+// The nat gateways deleted are retained by aws for a while, they are described with the state deleted.
+func (sess *EstablishedSession) DescribeNatGateways(d *DescribeNetworkInput) (*ec2.DescribeNatGatewaysOutput, error) {
+
+	if sess.Ec2 != nil {
+		if d.NatGatewayIds != nil {
+			input := &ec2.DescribeNatGatewaysInput{
+				NatGatewayIds: aws.StringSlice(d.NatGatewayIds),
+			}
+			result, err := (sess.Ec2).DescribeNatGatewaysWithContext(sess.Context(), input)
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+
+		if reflect.DeepEqual(d.Filters, Filters{}) {
+			return nil, err.New(err.InvalidInput, "You selected filters for DescribeNatGateways and did not provide the values for them, this is not acceptable")
+		}
+		input := &ec2.DescribeNatGatewaysInput{
+			Filter: []*ec2.Filter{
+				{
+					Name:   aws.String(d.Filters.Name),
+					Values: aws.StringSlice(d.Filters.Value),
+				},
+			},
+		}
+		result, err := (sess.Ec2).DescribeNatGatewaysWithContext(sess.Context(), input)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return nil, err.InvalidSession()
+}
+
+// DeleteNatGateway deletes the nat gateway selected, the elastic ip of the gateway is disassociated but not released.
+func (sess *EstablishedSession) DeleteNatGateway(d *DescribeNetworkInput) error {
+
+	if sess.Ec2 != nil {
+		if d.NatGatewayIds != nil {
+			input := &ec2.DeleteNatGatewayInput{
+				NatGatewayId: aws.String(d.NatGatewayIds[0]),
+			}
+			_, err := (sess.Ec2).DeleteNatGatewayWithContext(sess.Context(), input)
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+		return err.New(err.InvalidInput, "You provided empty struct to DeleteNatGateway, this is not acceptable")
+	}
+	return err.InvalidSession()
+}
+
+// WaitUntilNatGatewayDeleted makes the method called this to wait till the nat gateways are successfully deleted.
+// The nat gateways are polled as per the poller of the session, till aws reports them deleted or the poller gives up.
+func (sess *EstablishedSession) WaitUntilNatGatewayDeleted(d *DescribeNetworkInput) (bool, error) {
+
+	if sess.Ec2 != nil {
+		if d.NatGatewayIds != nil {
+			input := &ec2.DescribeNatGatewaysInput{
+				NatGatewayIds: aws.StringSlice(d.NatGatewayIds),
+			}
+			return sess.waitUntilDeleted(fmt.Sprintf("natgateways %v", d.NatGatewayIds), "NatGatewayNotFound", func(ctx context.Context) (int, error) {
+				response, deserr := (sess.Ec2).DescribeNatGatewaysWithContext(ctx, input)
+				if deserr != nil {
+					return 0, deserr
+				}
+				remaining := 0
+				for _, nat := range response.NatGateways {
+					if aws.StringValue(nat.State) != ec2.NatGatewayStateDeleted {
+						remaining++
 					}
 				}
+				return remaining, nil
+			})
+		}
+		return false, err.New(err.InvalidInput, "You provided empty struct to WaitUntilNatGatewayDeleted, this is not acceptable")
+	}
+	return false, err.InvalidSession()
+}
+
+// DescribeNetworkInterfaces fetches the information about the network interfaces selected either by its id's or the filters.
+func (sess *EstablishedSession) DescribeNetworkInterfaces(d *DescribeNetworkInput) (*ec2.DescribeNetworkInterfacesOutput, error) {
+
+	if sess.Ec2 != nil {
+		if d.NetworkInterfaceIds != nil {
+			input := &ec2.DescribeNetworkInterfacesInput{
+				NetworkInterfaceIds: aws.StringSlice(d.NetworkInterfaceIds),
 			}
-			if deserr != nil {
-				switch deserr.(awserr.Error).Code() {
-				case "InvalidInternetGatewayID.NotFound":
-					return true, nil
-				default:
-					return false, deserr
-				}
+			result, err := (sess.Ec2).DescribeNetworkInterfacesWithContext(sess.Context(), input)
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+
+		if reflect.DeepEqual(d.Filters, Filters{}) {
+			return nil, err.New(err.InvalidInput, "You selected filters for DescribeNetworkInterfaces and did not provide the values for them, this is not acceptable")
+		}
+		input := &ec2.DescribeNetworkInterfacesInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String(d.Filters.Name),
+					Values: aws.StringSlice(d.Filters.Value),
+				},
+			},
+		}
+		result, err := (sess.Ec2).DescribeNetworkInterfacesWithContext(sess.Context(), input)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return nil, err.InvalidSession()
+}
+
+// DeleteNetworkInterface deletes the network interface selected, the interfaces which are in use (attached) cannot be deleted.
+func (sess *EstablishedSession) DeleteNetworkInterface(d *DescribeNetworkInput) error {
+
+	if sess.Ec2 != nil {
+		if d.NetworkInterfaceIds != nil {
+			input := &ec2.DeleteNetworkInterfaceInput{
+				NetworkInterfaceId: aws.String(d.NetworkInterfaceIds[0]),
+			}
+			_, err := (sess.Ec2).DeleteNetworkInterfaceWithContext(sess.Context(), input)
+			if err != nil {
+				return err
 			}
-			return false, fmt.Errorf("Error occurred while waiting for the InternetGateways deletion")
+			return nil
 		}
-		return false, fmt.Errorf(fmt.Sprintf("%v WaitUntilIgwDeleted", err.EmptyStructError()))
+		return err.New(err.InvalidInput, "You provided empty struct to DeleteNetworkInterface, this is not acceptable")
 	}
-	return false, err.InvalidSession()
+	return err.InvalidSession()
 }
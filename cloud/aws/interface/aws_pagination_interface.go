@@ -0,0 +1,252 @@
+package neuronaws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// Pagination holds the optional values which controls how the results of the Describe calls are fetched from aws.
+// The DescribeAll methods and its iterators (EachVpc, EachInstance etc.) follows NextToken/Marker till the last page,
+// hence all the results are returned unless a Limit is set.
+type Pagination struct {
+	// PageSize is the number of results fetched from aws per request, the default of aws is used if left 0.
+	// It is adjusted to the bounds aws accepts for the call (ex: 5 to 1000 for the calls of ec2, 1 to 400 for loadbalancers).
+	PageSize int64
+	// Limit is the maximum number of results returned, all the results are returned if left 0.
+	Limit int64
+}
+
+// pageSize returns the size of the page to be requested, adjusted to the bounds passed. nil is returned if PageSize is not set.
+func (p Pagination) pageSize(min, max int64) *int64 {
+	switch {
+	case p.PageSize <= 0:
+		return nil
+	case p.PageSize < min:
+		return aws.Int64(min)
+	case p.PageSize > max:
+		return aws.Int64(max)
+	}
+	return aws.Int64(p.PageSize)
+}
+
+// pager counts the results visited by an iterator, and decides whether the iteration has to be continued.
+type pager struct {
+	limit int64
+	seen  int64
+}
+
+func (p Pagination) pager() *pager {
+	return &pager{limit: p.Limit}
+}
+
+// next records a result visited and reports whether the next one has to be visited,
+// which is not the case once the callback asks to stop or Limit is reached.
+func (p *pager) next(proceed bool) bool {
+	p.seen++
+	return proceed && (p.limit <= 0 || p.seen < p.limit)
+}
+
+// EachInstance calls fn for every instance in the selected region along with the reservation it belongs to, page by page.
+// The iteration stops once fn returns false or Limit is reached.
+func (sess *EstablishedSession) EachInstance(des *DescribeComputeInput, fn func(*ec2.Reservation, *ec2.Instance) bool) error {
+
+	if sess.Ec2 == nil {
+		return cloudyerror.InvalidSession()
+	}
+	visit := des.pager()
+	input := &ec2.DescribeInstancesInput{MaxResults: des.pageSize(5, 1000)}
+	return (sess.Ec2).DescribeInstancesPagesWithContext(sess.Context(), input, func(page *ec2.DescribeInstancesOutput, _ bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				if !visit.next(fn(reservation, instance)) {
+					return false
+				}
+			}
+		}
+		return true
+	})
+}
+
+// EachImage calls fn for every image owned by the account in the selected region (the ones which are not public).
+// DescribeImages of aws is not paginated, hence the images are fetched in a single request and PageSize is ignored.
+// The iteration stops once fn returns false or Limit is reached.
+func (sess *EstablishedSession) EachImage(img *DescribeComputeInput, fn func(*ec2.Image) bool) error {
+
+	if sess.Ec2 == nil {
+		return cloudyerror.InvalidSession()
+	}
+	visit := img.pager()
+	input := &ec2.DescribeImagesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("is-public"),
+				Values: aws.StringSlice([]string{"false"}),
+			},
+		},
+	}
+	result, err := (sess.Ec2).DescribeImagesWithContext(sess.Context(), input)
+	if err != nil {
+		return err
+	}
+	for _, image := range result.Images {
+		if !visit.next(fn(image)) {
+			break
+		}
+	}
+	return nil
+}
+
+// EachVpc calls fn for every network in the selected region, page by page.
+// The iteration stops once fn returns false or Limit is reached.
+func (sess *EstablishedSession) EachVpc(d *DescribeNetworkInput, fn func(*ec2.Vpc) bool) error {
+
+	if sess.Ec2 == nil {
+		return cloudyerror.InvalidSession()
+	}
+	visit := d.pager()
+	input := &ec2.DescribeVpcsInput{MaxResults: d.pageSize(5, 1000)}
+	return (sess.Ec2).DescribeVpcsPagesWithContext(sess.Context(), input, func(page *ec2.DescribeVpcsOutput, _ bool) bool {
+		for _, vpc := range page.Vpcs {
+			if !visit.next(fn(vpc)) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// EachSubnet calls fn for every subnetwork in the selected region, page by page.
+// The iteration stops once fn returns false or Limit is reached.
+func (sess *EstablishedSession) EachSubnet(d *DescribeNetworkInput, fn func(*ec2.Subnet) bool) error {
+
+	if sess.Ec2 == nil {
+		return cloudyerror.InvalidSession()
+	}
+	visit := d.pager()
+	input := &ec2.DescribeSubnetsInput{MaxResults: d.pageSize(5, 1000)}
+	return (sess.Ec2).DescribeSubnetsPagesWithContext(sess.Context(), input, func(page *ec2.DescribeSubnetsOutput, _ bool) bool {
+		for _, subnet := range page.Subnets {
+			if !visit.next(fn(subnet)) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// EachSecurityGroup calls fn for every security group in the selected region, page by page.
+// The iteration stops once fn returns false or Limit is reached.
+func (sess *EstablishedSession) EachSecurityGroup(d *DescribeNetworkInput, fn func(*ec2.SecurityGroup) bool) error {
+
+	if sess.Ec2 == nil {
+		return cloudyerror.InvalidSession()
+	}
+	visit := d.pager()
+	input := &ec2.DescribeSecurityGroupsInput{MaxResults: d.pageSize(5, 1000)}
+	return (sess.Ec2).DescribeSecurityGroupsPagesWithContext(sess.Context(), input, func(page *ec2.DescribeSecurityGroupsOutput, _ bool) bool {
+		for _, group := range page.SecurityGroups {
+			if !visit.next(fn(group)) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// EachVolume calls fn for every ebs volume in the selected region, page by page.
+// The iteration stops once fn returns false or Limit is reached.
+func (sess *EstablishedSession) EachVolume(v *VolumeInput, fn func(*ec2.Volume) bool) error {
+
+	if sess.Ec2 == nil {
+		return cloudyerror.InvalidSession()
+	}
+	visit := v.pager()
+	input := &ec2.DescribeVolumesInput{MaxResults: v.pageSize(5, 500)}
+	return (sess.Ec2).DescribeVolumesPagesWithContext(sess.Context(), input, func(page *ec2.DescribeVolumesOutput, _ bool) bool {
+		for _, volume := range page.Volumes {
+			if !visit.next(fn(volume)) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// EachSnapshot calls fn for every snapshot owned by the account in the selected region, page by page.
+// The iteration stops once fn returns false or Limit is reached.
+func (sess *EstablishedSession) EachSnapshot(v *VolumeInput, fn func(*ec2.Snapshot) bool) error {
+
+	if sess.Ec2 == nil {
+		return cloudyerror.InvalidSession()
+	}
+	visit := v.pager()
+	input := &ec2.DescribeSnapshotsInput{OwnerIds: aws.StringSlice([]string{"self"}), MaxResults: v.pageSize(5, 1000)}
+	return (sess.Ec2).DescribeSnapshotsPagesWithContext(sess.Context(), input, func(page *ec2.DescribeSnapshotsOutput, _ bool) bool {
+		for _, snapshot := range page.Snapshots {
+			if !visit.next(fn(snapshot)) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// EachClassicLoadbalancer calls fn for every classic loadbalancer in the selected region, page by page.
+// The iteration stops once fn returns false or Limit is reached.
+func (sess *EstablishedSession) EachClassicLoadbalancer(lb *DescribeLoadbalancersInput, fn func(*elb.LoadBalancerDescription) bool) error {
+
+	if sess.Elb == nil {
+		return cloudyerror.InvalidSession()
+	}
+	visit := lb.pager()
+	input := &elb.DescribeLoadBalancersInput{PageSize: lb.pageSize(1, 400)}
+	return (sess.Elb).DescribeLoadBalancersPagesWithContext(sess.Context(), input, func(page *elb.DescribeLoadBalancersOutput, _ bool) bool {
+		for _, loadbalancer := range page.LoadBalancerDescriptions {
+			if !visit.next(fn(loadbalancer)) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// EachApplicationLoadbalancer calls fn for every application loadbalancer in the selected region, page by page.
+// The iteration stops once fn returns false or Limit is reached.
+func (sess *EstablishedSession) EachApplicationLoadbalancer(lb *DescribeLoadbalancersInput, fn func(*elbv2.LoadBalancer) bool) error {
+
+	if sess.Elb2 == nil {
+		return cloudyerror.InvalidSession()
+	}
+	visit := lb.pager()
+	input := &elbv2.DescribeLoadBalancersInput{PageSize: lb.pageSize(1, 400)}
+	return (sess.Elb2).DescribeLoadBalancersPagesWithContext(sess.Context(), input, func(page *elbv2.DescribeLoadBalancersOutput, _ bool) bool {
+		for _, loadbalancer := range page.LoadBalancers {
+			if !visit.next(fn(loadbalancer)) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// EachTargetgroup calls fn for every target group in the selected region, page by page.
+// The iteration stops once fn returns false or Limit is reached.
+func (sess *EstablishedSession) EachTargetgroup(lb *DescribeLoadbalancersInput, fn func(*elbv2.TargetGroup) bool) error {
+
+	if sess.Elb2 == nil {
+		return cloudyerror.InvalidSession()
+	}
+	visit := lb.pager()
+	input := &elbv2.DescribeTargetGroupsInput{PageSize: lb.pageSize(1, 400)}
+	return (sess.Elb2).DescribeTargetGroupsPagesWithContext(sess.Context(), input, func(page *elbv2.DescribeTargetGroupsOutput, _ bool) bool {
+		for _, group := range page.TargetGroups {
+			if !visit.next(fn(group)) {
+				return false
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,73 @@
+package neuronaws
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// SpotPriceInput holds the values to select the spot prices to be fetched, all of them are optional.
+type SpotPriceInput struct {
+	// InstanceTypes are the types of the instances of which the spot prices has to be fetched ex: t2.micro.
+	InstanceTypes []string
+	// Zones are the availability zones in which the spot prices has to be fetched ex: us-east-1a.
+	Zones []string
+	// ProductDescriptions are the platforms of which the spot prices has to be fetched ex: Linux/UNIX, Windows.
+	ProductDescriptions []string
+	// StartTime fetches the prices from the time passed, aws reports the current price of each selection if it is the present time.
+	StartTime time.Time
+	// Pagination controls the size of the pages and the number of prices fetched.
+	Pagination
+}
+
+// DescribeSpotPriceHistory fetches the history of the spot prices selected, all the pages are fetched.
+func (sess *EstablishedSession) DescribeSpotPriceHistory(s *SpotPriceInput) ([]*ec2.SpotPrice, error) {
+
+	if sess.Ec2 != nil {
+		visit := s.pager()
+		input := &ec2.DescribeSpotPriceHistoryInput{MaxResults: s.pageSize(1, 1000)}
+		if len(s.InstanceTypes) != 0 {
+			input.InstanceTypes = aws.StringSlice(s.InstanceTypes)
+		}
+		if len(s.Zones) != 0 {
+			input.Filters = []*ec2.Filter{{Name: aws.String("availability-zone"), Values: aws.StringSlice(s.Zones)}}
+		}
+		if len(s.ProductDescriptions) != 0 {
+			input.ProductDescriptions = aws.StringSlice(s.ProductDescriptions)
+		}
+		if !s.StartTime.IsZero() {
+			input.StartTime = aws.Time(s.StartTime)
+		}
+
+		prices := make([]*ec2.SpotPrice, 0)
+		err := (sess.Ec2).DescribeSpotPriceHistoryPagesWithContext(sess.Context(), input, func(page *ec2.DescribeSpotPriceHistoryOutput, _ bool) bool {
+			for _, price := range page.SpotPriceHistory {
+				prices = append(prices, price)
+				if !visit.next(true) {
+					return false
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		return prices, nil
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// CancelSpotInstanceRequests cancels the spot requests passed, the instances launched for them are not terminated.
+// The persistent requests have to be cancelled before terminating their instances, else aws launches new instances for them.
+func (sess *EstablishedSession) CancelSpotInstanceRequests(requestIds []string) (*ec2.CancelSpotInstanceRequestsOutput, error) {
+
+	if sess.Ec2 != nil {
+		if len(requestIds) == 0 {
+			return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty list of spot requests to CancelSpotInstanceRequests, this is not acceptable")
+		}
+		return (sess.Ec2).CancelSpotInstanceRequestsWithContext(sess.Context(), &ec2.CancelSpotInstanceRequestsInput{SpotInstanceRequestIds: aws.StringSlice(requestIds)})
+	}
+	return nil, cloudyerror.InvalidSession()
+}
@@ -0,0 +1,420 @@
+package neuronaws
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// VolumeInput holds the required values to create/attach/detach/modify/delete/describe the ebs volumes and their snapshots.
+type VolumeInput struct {
+	// VolumeIds are the IDs of the volumes on which the operation has to be performed, only the first one is considered by the operations on a single volume.
+	VolumeIds []string
+	// SnapshotIds are the IDs of the snapshots of whom the information has to be retrieved, or out of which the volume has to be created.
+	SnapshotIds []string
+	// AvailabilityZone is the zone in which the volume has to be created.
+	AvailabilityZone string
+	// Size of the volume in GiB.
+	Size int64
+	// Type of the volume ex: gp2, io1, st1.
+	Type string
+	// Iops to be provisioned for the volume, this is valid only for io1.
+	Iops int64
+	// Encrypted encrypts the volume with the key KmsKeyId, or with the default key of ebs if KmsKeyId is not set.
+	Encrypted bool
+	// KmsKeyId is the ID or ARN of the key of kms with which the volume has to be encrypted.
+	KmsKeyId string
+	// InstanceId is the ID of the instance to which the volume has to be attached, or from which it has to be detached.
+	InstanceId string
+	// Device is the name of the device at which the volume has to be attached ex: /dev/sdf.
+	Device string
+	// Force detaches the volume even if the instance did not unmount it, this might corrupt the data on the volume.
+	Force bool
+	// Description of the snapshot to be created.
+	Description string
+	// Filters can be applied over the volumes or snapshots to get more precise data about it ex: attachment.instance-id.
+	Filters Filters
+	// Pagination controls the size of the pages and the number of results fetched by DescribeAllVolumes and DescribeAllSnapshots.
+	Pagination
+}
+
+// CreateVolume creates the volume in the zone passed, either of the size passed or out of the first of the snapshots passed.
+func (sess *EstablishedSession) CreateVolume(v *VolumeInput) (*ec2.Volume, error) {
+
+	if sess.Ec2 != nil {
+		if v.AvailabilityZone != "" && (v.Size != 0 || len(v.SnapshotIds) != 0) {
+			result, err := (sess.Ec2).CreateVolumeWithContext(sess.Context(), v.createVolumeInput())
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty/wrong details to CreateVolume, the zone and either the size or the snapshot is required")
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// createVolumeInput translates the values passed to the request CreateVolume of aws.
+func (v *VolumeInput) createVolumeInput() *ec2.CreateVolumeInput {
+	input := &ec2.CreateVolumeInput{AvailabilityZone: aws.String(v.AvailabilityZone)}
+	if v.Size != 0 {
+		input.Size = aws.Int64(v.Size)
+	}
+	if len(v.SnapshotIds) != 0 {
+		input.SnapshotId = aws.String(v.SnapshotIds[0])
+	}
+	if v.Type != "" {
+		input.VolumeType = aws.String(v.Type)
+	}
+	if v.Iops != 0 {
+		input.Iops = aws.Int64(v.Iops)
+	}
+	if v.Encrypted {
+		input.Encrypted = aws.Bool(true)
+	}
+	if v.KmsKeyId != "" {
+		input.KmsKeyId = aws.String(v.KmsKeyId)
+	}
+	return input
+}
+
+// AttachVolume attaches the first of the volumes passed to the instance at the device passed.
+func (sess *EstablishedSession) AttachVolume(v *VolumeInput) (*ec2.VolumeAttachment, error) {
+
+	if sess.Ec2 != nil {
+		if len(v.VolumeIds) != 0 && v.InstanceId != "" && v.Device != "" {
+			result, err := (sess.Ec2).AttachVolumeWithContext(sess.Context(), v.attachVolumeInput())
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty/wrong details to AttachVolume, the volume, instance and device are required")
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+func (v *VolumeInput) attachVolumeInput() *ec2.AttachVolumeInput {
+	return &ec2.AttachVolumeInput{VolumeId: aws.String(v.VolumeIds[0]), InstanceId: aws.String(v.InstanceId), Device: aws.String(v.Device)}
+}
+
+// DetachVolume detaches the first of the volumes passed from the instance it is attached to.
+func (sess *EstablishedSession) DetachVolume(v *VolumeInput) (*ec2.VolumeAttachment, error) {
+
+	if sess.Ec2 != nil {
+		if len(v.VolumeIds) != 0 {
+			result, err := (sess.Ec2).DetachVolumeWithContext(sess.Context(), v.detachVolumeInput())
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to DetachVolume, this is not acceptable")
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+func (v *VolumeInput) detachVolumeInput() *ec2.DetachVolumeInput {
+	input := &ec2.DetachVolumeInput{VolumeId: aws.String(v.VolumeIds[0])}
+	if v.InstanceId != "" {
+		input.InstanceId = aws.String(v.InstanceId)
+	}
+	if v.Device != "" {
+		input.Device = aws.String(v.Device)
+	}
+	if v.Force {
+		input.Force = aws.Bool(true)
+	}
+	return input
+}
+
+// ModifyVolume changes the size, type or iops of the first of the volumes passed, the ones which are not set are left as is.
+func (sess *EstablishedSession) ModifyVolume(v *VolumeInput) (*ec2.VolumeModification, error) {
+
+	if sess.Ec2 != nil {
+		if len(v.VolumeIds) != 0 && (v.Size != 0 || v.Type != "" || v.Iops != 0) {
+			result, err := (sess.Ec2).ModifyVolumeWithContext(sess.Context(), v.modifyVolumeInput())
+			if err != nil {
+				return nil, err
+			}
+			return result.VolumeModification, nil
+		}
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty/wrong details to ModifyVolume, the volume and the size, type or iops are required")
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+func (v *VolumeInput) modifyVolumeInput() *ec2.ModifyVolumeInput {
+	input := &ec2.ModifyVolumeInput{VolumeId: aws.String(v.VolumeIds[0])}
+	if v.Size != 0 {
+		input.Size = aws.Int64(v.Size)
+	}
+	if v.Type != "" {
+		input.VolumeType = aws.String(v.Type)
+	}
+	if v.Iops != 0 {
+		input.Iops = aws.Int64(v.Iops)
+	}
+	return input
+}
+
+// DeleteVolume deletes the first of the volumes passed, the volume has to be detached before deleting it.
+func (sess *EstablishedSession) DeleteVolume(v *VolumeInput) error {
+
+	if sess.Ec2 != nil {
+		if len(v.VolumeIds) != 0 {
+			_, err := (sess.Ec2).DeleteVolumeWithContext(sess.Context(), &ec2.DeleteVolumeInput{VolumeId: aws.String(v.VolumeIds[0])})
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+		return cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to DeleteVolume, this is not acceptable")
+	}
+	return cloudyerror.InvalidSession()
+}
+
+// DescribeVolumes fetches the information about the volumes selected either by its IDs or the filters.
+func (sess *EstablishedSession) DescribeVolumes(v *VolumeInput) (*ec2.DescribeVolumesOutput, error) {
+
+	if sess.Ec2 != nil {
+		if v.VolumeIds != nil {
+			input := &ec2.DescribeVolumesInput{
+				VolumeIds: aws.StringSlice(v.VolumeIds),
+			}
+			result, err := (sess.Ec2).DescribeVolumesWithContext(sess.Context(), input)
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+
+		if reflect.DeepEqual(v.Filters, Filters{}) {
+			return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to DescribeVolumes, this is not acceptable")
+		}
+		input := &ec2.DescribeVolumesInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String(v.Filters.Name),
+					Values: aws.StringSlice(v.Filters.Value),
+				},
+			},
+		}
+		result, err := (sess.Ec2).DescribeVolumesWithContext(sess.Context(), input)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// DescribeAllVolumes describes all the volumes in the selected region, all the pages are fetched.
+// Use EachVolume to visit the volumes without holding all of them.
+func (sess *EstablishedSession) DescribeAllVolumes(v *VolumeInput) (*ec2.DescribeVolumesOutput, error) {
+
+	result := &ec2.DescribeVolumesOutput{Volumes: make([]*ec2.Volume, 0)}
+	err := sess.EachVolume(v, func(volume *ec2.Volume) bool {
+		result.Volumes = append(result.Volumes, volume)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateSnapshot captures the snapshot of the first of the volumes passed, aws creates the snapshot asynchronously.
+func (sess *EstablishedSession) CreateSnapshot(v *VolumeInput) (*ec2.Snapshot, error) {
+
+	if sess.Ec2 != nil {
+		if len(v.VolumeIds) != 0 {
+			result, err := (sess.Ec2).CreateSnapshotWithContext(sess.Context(), v.createSnapshotInput())
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to CreateSnapshot, this is not acceptable")
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+func (v *VolumeInput) createSnapshotInput() *ec2.CreateSnapshotInput {
+	input := &ec2.CreateSnapshotInput{VolumeId: aws.String(v.VolumeIds[0])}
+	if v.Description != "" {
+		input.Description = aws.String(v.Description)
+	}
+	return input
+}
+
+// DescribeSnapshots fetches the information about the snapshots owned by the account selected either by its IDs or the filters.
+func (sess *EstablishedSession) DescribeSnapshots(v *VolumeInput) (*ec2.DescribeSnapshotsOutput, error) {
+
+	if sess.Ec2 != nil {
+		if v.SnapshotIds != nil {
+			input := &ec2.DescribeSnapshotsInput{
+				SnapshotIds: aws.StringSlice(v.SnapshotIds),
+			}
+			result, err := (sess.Ec2).DescribeSnapshotsWithContext(sess.Context(), input)
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+
+		if reflect.DeepEqual(v.Filters, Filters{}) {
+			return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to DescribeSnapshots, this is not acceptable")
+		}
+		input := &ec2.DescribeSnapshotsInput{
+			OwnerIds: aws.StringSlice([]string{"self"}),
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String(v.Filters.Name),
+					Values: aws.StringSlice(v.Filters.Value),
+				},
+			},
+		}
+		result, err := (sess.Ec2).DescribeSnapshotsWithContext(sess.Context(), input)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// DescribeAllSnapshots describes all the snapshots owned by the account in the selected region, all the pages are fetched.
+// Use EachSnapshot to visit the snapshots without holding all of them.
+func (sess *EstablishedSession) DescribeAllSnapshots(v *VolumeInput) (*ec2.DescribeSnapshotsOutput, error) {
+
+	result := &ec2.DescribeSnapshotsOutput{Snapshots: make([]*ec2.Snapshot, 0)}
+	err := sess.EachSnapshot(v, func(snapshot *ec2.Snapshot) bool {
+		result.Snapshots = append(result.Snapshots, snapshot)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WaitTillVolumeAvailable makes the called method to wait till the volumes passed becomes available, which is when they are created or detached.
+func (sess *EstablishedSession) WaitTillVolumeAvailable(v *VolumeInput) error {
+
+	if sess.Ec2 != nil {
+		if v.VolumeIds != nil {
+			input := &ec2.DescribeVolumesInput{
+				VolumeIds: aws.StringSlice(v.VolumeIds),
+			}
+			return sess.wait.AWS(sess.Context(), fmt.Sprintf("volumes %v", v.VolumeIds), func(ctx aws.Context, options ...request.WaiterOption) error {
+				return (sess.Ec2).WaitUntilVolumeAvailableWithContext(ctx, input, options...)
+			})
+		}
+		return cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to WaitTillVolumeAvailable, this is not acceptable")
+	}
+	return cloudyerror.InvalidSession()
+}
+
+// WaitTillVolumeModified makes the called method to wait till the latest modification of the volumes passed reaches optimizing or completed,
+// the volumes can be used at their new size and type from optimizing on though aws continues to optimize them in the background.
+// It fails if the modification of any of the volumes failed.
+func (sess *EstablishedSession) WaitTillVolumeModified(v *VolumeInput) error {
+
+	if sess.Ec2 != nil {
+		if v.VolumeIds != nil {
+			input := &ec2.DescribeVolumesModificationsInput{
+				VolumeIds: aws.StringSlice(v.VolumeIds),
+			}
+			return sess.wait.Until(sess.Context(), fmt.Sprintf("modification of volumes %v", v.VolumeIds), func(ctx context.Context) (bool, string, error) {
+				response, deserr := (sess.Ec2).DescribeVolumesModificationsWithContext(ctx, input)
+				if deserr != nil {
+					return false, "", deserr
+				}
+				pending := 0
+				for _, modification := range response.VolumesModifications {
+					switch aws.StringValue(modification.ModificationState) {
+					case ec2.VolumeModificationStateOptimizing, ec2.VolumeModificationStateCompleted:
+					case ec2.VolumeModificationStateFailed:
+						return false, "", cloudyerror.Newf(cloudyerror.Conflict, "The modification of the volume %s failed: %s", aws.StringValue(modification.VolumeId), aws.StringValue(modification.StatusMessage))
+					default:
+						pending++
+					}
+				}
+				if pending == 0 {
+					return true, ec2.VolumeModificationStateOptimizing, nil
+				}
+				return false, fmt.Sprintf("with %d yet to be modified", pending), nil
+			})
+		}
+		return cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to WaitTillVolumeModified, this is not acceptable")
+	}
+	return cloudyerror.InvalidSession()
+}
+
+// WaitTillVolumeInUse makes the called method to wait till the volumes passed are attached to the instances.
+func (sess *EstablishedSession) WaitTillVolumeInUse(v *VolumeInput) error {
+
+	if sess.Ec2 != nil {
+		if v.VolumeIds != nil {
+			input := &ec2.DescribeVolumesInput{
+				VolumeIds: aws.StringSlice(v.VolumeIds),
+			}
+			return sess.wait.AWS(sess.Context(), fmt.Sprintf("volumes %v", v.VolumeIds), func(ctx aws.Context, options ...request.WaiterOption) error {
+				return (sess.Ec2).WaitUntilVolumeInUseWithContext(ctx, input, options...)
+			})
+		}
+		return cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to WaitTillVolumeInUse, this is not acceptable")
+	}
+	return cloudyerror.InvalidSession()
+}
+
+// WaitUntilVolumeDeleted makes the method called this to wait till the volumes are successfully deleted.
+// The volumes are polled as per the poller of the session, till aws reports them missing or the poller gives up.
+func (sess *EstablishedSession) WaitUntilVolumeDeleted(v *VolumeInput) (bool, error) {
+
+	if sess.Ec2 != nil {
+		if v.VolumeIds != nil {
+			input := &ec2.DescribeVolumesInput{
+				VolumeIds: aws.StringSlice(v.VolumeIds),
+			}
+			return sess.waitUntilDeleted(fmt.Sprintf("volumes %v", v.VolumeIds), "InvalidVolume.NotFound", func(ctx context.Context) (int, error) {
+				response, deserr := (sess.Ec2).DescribeVolumesWithContext(ctx, input)
+				if deserr != nil {
+					return 0, deserr
+				}
+				remaining := 0
+				for _, volume := range response.Volumes {
+					if aws.StringValue(volume.State) != ec2.VolumeStateDeleted {
+						remaining++
+					}
+				}
+				return remaining, nil
+			})
+		}
+		return false, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to WaitUntilVolumeDeleted, this is not acceptable")
+	}
+	return false, cloudyerror.InvalidSession()
+}
+
+// WaitTillSnapshotCompleted makes the called method to wait till the snapshots passed are completed.
+func (sess *EstablishedSession) WaitTillSnapshotCompleted(v *VolumeInput) error {
+
+	if sess.Ec2 != nil {
+		if v.SnapshotIds != nil {
+			input := &ec2.DescribeSnapshotsInput{
+				SnapshotIds: aws.StringSlice(v.SnapshotIds),
+			}
+			return sess.wait.AWS(sess.Context(), fmt.Sprintf("snapshots %v", v.SnapshotIds), func(ctx aws.Context, options ...request.WaiterOption) error {
+				return (sess.Ec2).WaitUntilSnapshotCompletedWithContext(ctx, input, options...)
+			})
+		}
+		return cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to WaitTillSnapshotCompleted, this is not acceptable")
+	}
+	return cloudyerror.InvalidSession()
+}
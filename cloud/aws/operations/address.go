@@ -0,0 +1,244 @@
+package aws
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// The methods below manage the elastic ips, unlike the public ip assigned on the launch of the instance
+// the elastic ip stays with the account until it is released, hence it survives the stop/start of the instance.
+
+const (
+	// AddressAssociate associates the elastic ip with the instance or the network interface.
+	AddressAssociate = "associate"
+	// AddressDisassociate disassociates the elastic ip from the instance or the network interface it is associated with.
+	AddressDisassociate = "disassociate"
+)
+
+// AddressInput holds the values required to allocate/fetch/update/release the elastic ips.
+type AddressInput struct {
+	// AllocationIds are the IDs of the elastic ips to be fetched/updated/released.
+	AllocationIds []string `json:"allocationids"`
+	// Name of the elastic ip to be allocated.
+	Name string `json:"name"`
+	// InstanceId is the ID of the instance with which the elastic ip has to be associated, or of which the elastic ips has to be fetched.
+	InstanceId string `json:"instanceid"`
+	// NetworkInterfaceId is the ID of the network interface with which the elastic ip has to be associated, or of which the elastic ips has to be fetched.
+	// It takes precedence over InstanceId.
+	NetworkInterfaceId string `json:"networkinterfaceid"`
+	// Reassociate moves the elastic ip which is already associated to the instance or network interface passed.
+	Reassociate bool `json:"reassociate"`
+	// Action to be performed by UpdateAddress, associate or disassociate.
+	Action string `json:"action"`
+	GetRaw bool   `json:"getraw"`
+}
+
+// AddressResponse holds the details of the elastic ip.
+type AddressResponse struct {
+	// Name of the elastic ip.
+	Name string `json:"Name,omitempty"`
+	// AllocationId is the ID of the elastic ip.
+	AllocationId string `json:"AllocationId,omitempty"`
+	// PublicIp is the address allocated.
+	PublicIp string `json:"PublicIp,omitempty"`
+	// AssociationId is the ID of the association of the elastic ip with the instance or network interface.
+	AssociationId string `json:"AssociationId,omitempty"`
+	// InstanceId is the ID of the instance with which the elastic ip is associated.
+	InstanceId string `json:"InstanceId,omitempty"`
+	// NetworkInterfaceId is the ID of the network interface with which the elastic ip is associated.
+	NetworkInterfaceId string `json:"NetworkInterfaceId,omitempty"`
+	// PrivateIp is the private address of the instance or network interface with which the elastic ip is associated.
+	PrivateIp string `json:"PrivateIp,omitempty"`
+	// State of the elastic ip, associated, unassociated or released.
+	State string `json:"State,omitempty"`
+	// AddressRaw holds the unfiltered response from aws for the elastic ip.
+	AddressRaw *ec2.Address `json:"AddressRaw,omitempty"`
+}
+
+// AllocateAddress allocates the elastic ip and associates it with the instance or network interface passed, if any.
+// The elastic ip is released back if it could not be named or associated.
+func (a *AddressInput) AllocateAddress(con aws.EstablishConnectionInput) (AddressResponse, error) {
+
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return AddressResponse{}, seserr
+	}
+	address, allocerr := allocateAddress(con, sess, a.Name)
+	if allocerr != nil {
+		return AddressResponse{}, allocerr
+	}
+	if (a.InstanceId != "") || (a.NetworkInterfaceId != "") {
+		input := &aws.AddressInput{AllocationIds: []string{address}, InstanceId: a.InstanceId, NetworkInterfaceId: a.NetworkInterfaceId, AllowReassociation: a.Reassociate}
+		if _, asserr := sess.AssociateAddress(input); asserr != nil {
+			sess.ReleaseAddress(&aws.AddressInput{AllocationIds: []string{address}})
+			return AddressResponse{}, asserr
+		}
+	}
+	return describeAddress(sess, address, a.GetRaw)
+}
+
+// GetAddresses fetches the elastic ips passed, the ones associated with the network interface or instance passed, or all the ones in the region.
+func (a *AddressInput) GetAddresses(con aws.EstablishConnectionInput) ([]AddressResponse, error) {
+
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return nil, seserr
+	}
+	var addresses *ec2.DescribeAddressesOutput
+	var err error
+	switch {
+	case len(a.AllocationIds) != 0:
+		addresses, err = sess.DescribeAddresses(&aws.AddressInput{AllocationIds: a.AllocationIds})
+	case a.NetworkInterfaceId != "":
+		addresses, err = sess.DescribeAddresses(&aws.AddressInput{Filters: aws.Filters{Name: "network-interface-id", Value: []string{a.NetworkInterfaceId}}})
+	case a.InstanceId != "":
+		addresses, err = sess.DescribeAddresses(&aws.AddressInput{Filters: aws.Filters{Name: "instance-id", Value: []string{a.InstanceId}}})
+	default:
+		addresses, err = sess.DescribeAllAddresses(&aws.AddressInput{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	response := make([]AddressResponse, 0, len(addresses.Addresses))
+	for _, address := range addresses.Addresses {
+		response = append(response, addressResponse(address, a.GetRaw))
+	}
+	return response, nil
+}
+
+// UpdateAddress performs the Action on the first of the elastic ips passed:
+// associate associates it with the network interface or instance passed and disassociate disassociates it from the one it is associated with.
+func (a *AddressInput) UpdateAddress(con aws.EstablishConnectionInput) (AddressResponse, error) {
+
+	if len(a.AllocationIds) == 0 {
+		return AddressResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the elastic ip to be updated")
+	}
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return AddressResponse{}, seserr
+	}
+	address, geterr := fetchAddress(sess, a.AllocationIds[0])
+	if geterr != nil {
+		return AddressResponse{}, geterr
+	}
+
+	switch a.Action {
+	case AddressAssociate:
+		if (a.InstanceId == "") && (a.NetworkInterfaceId == "") {
+			return AddressResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed either the instance or the network interface with which the elastic ip has to be associated")
+		}
+		input := &aws.AddressInput{AllocationIds: []string{*address.AllocationId}, InstanceId: a.InstanceId, NetworkInterfaceId: a.NetworkInterfaceId, AllowReassociation: a.Reassociate}
+		if _, asserr := sess.AssociateAddress(input); asserr != nil {
+			return AddressResponse{}, asserr
+		}
+	case AddressDisassociate:
+		if address.AssociationId == nil {
+			return AddressResponse{}, cloudyerror.Newf(cloudyerror.InvalidInput, "elastic ip %s is not associated with any instance or network interface", *address.AllocationId)
+		}
+		if disserr := sess.DisassociateAddress(&aws.AddressInput{AssociationId: *address.AssociationId}); disserr != nil {
+			return AddressResponse{}, disserr
+		}
+	default:
+		return AddressResponse{}, cloudyerror.Newf(cloudyerror.InvalidInput, "action %s is not supported on the elastic ips, it should be one of associate or disassociate", a.Action)
+	}
+	return describeAddress(sess, *address.AllocationId, a.GetRaw)
+}
+
+// ReleaseAddress releases the elastic ips passed, the ones released before the failure are returned along with the error.
+// The elastic ips associated are not released, they have to be disassociated first.
+func (a *AddressInput) ReleaseAddress(con aws.EstablishConnectionInput) ([]AddressResponse, error) {
+
+	if len(a.AllocationIds) == 0 {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the elastic ips to be released")
+	}
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return nil, seserr
+	}
+	addresses, geterr := sess.DescribeAddresses(&aws.AddressInput{AllocationIds: a.AllocationIds})
+	if geterr != nil {
+		return nil, geterr
+	}
+	if associated := associatedIds(addresses.Addresses); len(associated) != 0 {
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "elastic ips %v are associated, disassociate them before releasing", associated)
+	}
+
+	response := make([]AddressResponse, 0, len(addresses.Addresses))
+	for _, address := range addresses.Addresses {
+		if relerr := sess.ReleaseAddress(&aws.AddressInput{AllocationIds: []string{*address.AllocationId}}); relerr != nil {
+			return response, relerr
+		}
+		response = append(response, AddressResponse{Name: nameOf(address.Tags), AllocationId: *address.AllocationId, PublicIp: awssdk.StringValue(address.PublicIp), State: "released"})
+	}
+	return response, nil
+}
+
+// allocateAddress allocates the elastic ip and names it, the elastic ip is released back if it could not be named.
+func allocateAddress(con aws.EstablishConnectionInput, sess aws.EstablishedSession, name string) (string, error) {
+	address, allocerr := sess.AllocateAddress(&aws.AddressInput{})
+	if allocerr != nil {
+		return "", allocerr
+	}
+	if name != "" {
+		tags := Tag{Resource: *address.AllocationId, Name: "Name", Value: name}
+		if _, tagerr := tags.CreateTags(con); tagerr != nil {
+			sess.ReleaseAddress(&aws.AddressInput{AllocationIds: []string{*address.AllocationId}})
+			return "", tagerr
+		}
+	}
+	return *address.AllocationId, nil
+}
+
+// fetchAddress fetches the elastic ip passed.
+func fetchAddress(sess aws.EstablishedSession, id string) (*ec2.Address, error) {
+	addresses, err := sess.DescribeAddresses(&aws.AddressInput{AllocationIds: []string{id}})
+	if err != nil {
+		return nil, err
+	}
+	if len(addresses.Addresses) == 0 {
+		return nil, cloudyerror.Newf(cloudyerror.NotFound, "elastic ip %s is not found", id)
+	}
+	return addresses.Addresses[0], nil
+}
+
+func describeAddress(sess aws.EstablishedSession, id string, raw bool) (AddressResponse, error) {
+	address, err := fetchAddress(sess, id)
+	if err != nil {
+		return AddressResponse{}, err
+	}
+	return addressResponse(address, raw), nil
+}
+
+// associatedIds returns the IDs of the elastic ips which are associated with the instances or network interfaces.
+func associatedIds(addresses []*ec2.Address) []string {
+	ids := make([]string, 0)
+	for _, address := range addresses {
+		if address.AssociationId != nil {
+			ids = append(ids, *address.AllocationId)
+		}
+	}
+	return ids
+}
+
+// addressResponse returns the details of the elastic ip, the unfiltered response of aws is returned if raw is set.
+func addressResponse(address *ec2.Address, raw bool) AddressResponse {
+	if raw {
+		return AddressResponse{AddressRaw: address}
+	}
+	response := AddressResponse{
+		Name:               nameOf(address.Tags),
+		AllocationId:       awssdk.StringValue(address.AllocationId),
+		PublicIp:           awssdk.StringValue(address.PublicIp),
+		AssociationId:      awssdk.StringValue(address.AssociationId),
+		InstanceId:         awssdk.StringValue(address.InstanceId),
+		NetworkInterfaceId: awssdk.StringValue(address.NetworkInterfaceId),
+		PrivateIp:          awssdk.StringValue(address.PrivateIpAddress),
+		State:              "unassociated",
+	}
+	if address.AssociationId != nil {
+		response.State = "associated"
+	}
+	return response
+}
@@ -0,0 +1,192 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsfake "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/fake"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+func TestAddressLifecycle(t *testing.T) {
+	cloud := awsfake.New()
+	server, _ := createTestServer(t, cloud)
+
+	allocate := AddressInput{Name: "web", InstanceId: server.InstanceId}
+	address, err := allocate.AllocateAddress(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("allocating elastic ip: %v", err)
+	}
+	if address.Name != "web" || address.State != "associated" || address.InstanceId != server.InstanceId || address.PublicIp == "" {
+		t.Fatalf("elastic ip is not allocated as requested, got %+v", address)
+	}
+
+	get := AddressInput{InstanceId: server.InstanceId}
+	addresses, err := get.GetAddresses(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("fetching elastic ips: %v", err)
+	}
+	if len(addresses) != 1 || addresses[0].AllocationId != address.AllocationId {
+		t.Errorf("expected the elastic ip %s associated with the server, got %+v", address.AllocationId, addresses)
+	}
+
+	release := AddressInput{AllocationIds: []string{address.AllocationId}}
+	if _, err := release.ReleaseAddress(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+		t.Errorf("expected the associated elastic ip not to be released, got %v", err)
+	}
+
+	disassociate := AddressInput{AllocationIds: []string{address.AllocationId}, Action: AddressDisassociate}
+	disassociated, err := disassociate.UpdateAddress(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("disassociating elastic ip: %v", err)
+	}
+	if disassociated.State != "unassociated" || disassociated.InstanceId != "" || disassociated.PublicIp != address.PublicIp {
+		t.Errorf("expected the elastic ip to be disassociated retaining its address, got %+v", disassociated)
+	}
+	if _, err := disassociate.UpdateAddress(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+		t.Errorf("expected the elastic ip which is not associated to be rejected, got %v", err)
+	}
+
+	associate := AddressInput{AllocationIds: []string{address.AllocationId}, Action: AddressAssociate, InstanceId: server.InstanceId}
+	associated, err := associate.UpdateAddress(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("associating elastic ip: %v", err)
+	}
+	if associated.State != "associated" || associated.InstanceId != server.InstanceId {
+		t.Errorf("expected the elastic ip to be associated with %s, got %+v", server.InstanceId, associated)
+	}
+	if _, err := disassociate.UpdateAddress(fakeConnection(cloud, "ec2")); err != nil {
+		t.Fatalf("disassociating elastic ip: %v", err)
+	}
+
+	released, err := release.ReleaseAddress(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("releasing elastic ip: %v", err)
+	}
+	if len(released) != 1 || released[0].AllocationId != address.AllocationId || released[0].State != "released" {
+		t.Errorf("expected the elastic ip %s to be released, got %+v", address.AllocationId, released)
+	}
+	all := AddressInput{}
+	if addresses, err := all.GetAddresses(fakeConnection(cloud, "ec2")); err != nil || len(addresses) != 0 {
+		t.Errorf("expected no elastic ips left, got %+v, %v", addresses, err)
+	}
+}
+
+func TestUpdateAddressInvalid(t *testing.T) {
+	cloud := awsfake.New()
+	allocate := AddressInput{}
+	address, err := allocate.AllocateAddress(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("allocating elastic ip: %v", err)
+	}
+
+	for _, update := range []AddressInput{{Action: AddressAssociate}, {Action: "move"}, {}} {
+		update.AllocationIds = []string{address.AllocationId}
+		if _, err := update.UpdateAddress(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+			t.Errorf("expected %+v to be rejected as invalid input, got %v", update, err)
+		}
+	}
+	if calls := cloud.Calls("AssociateAddress") + cloud.Calls("DisassociateAddress"); calls != 0 {
+		t.Errorf("expected the invalid updates not to reach aws, got %d calls", calls)
+	}
+}
+
+func TestCreateServerStaticPublicIp(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	server := CreateServerInput{InstanceName: "neuron", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id,
+		MaxCount: 2, StaticPublicIp: true, ClientToken: "neuron-static-1"}
+	servers, err := server.CreateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating server: %v", err)
+	}
+	get := AddressInput{}
+	addresses, err := get.GetAddresses(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("fetching elastic ips: %v", err)
+	}
+	ips := make(map[string]AddressResponse)
+	for _, address := range addresses {
+		ips[address.AllocationId] = address
+	}
+	for _, created := range servers {
+		address, ok := ips[created.AllocationId]
+		if !ok || address.InstanceId != created.InstanceId || address.PublicIp != created.PublicIpAddress || address.Name != created.InstanceName {
+			t.Errorf("expected server %s to be reachable at its elastic ip, got %+v and %+v", created.InstanceId, created, address)
+		}
+	}
+
+	// the elastic ips already associated are picked up along with the instances.
+	if _, err := server.CreateServer(fakeConnection(cloud, "ec2")); err != nil {
+		t.Fatalf("retrying creation of server: %v", err)
+	}
+	if calls := cloud.Calls("AllocateAddress"); calls != 2 {
+		t.Errorf("expected an elastic ip to be allocated once for each server, got %d calls to AllocateAddress", calls)
+	}
+}
+
+func TestCreateServerStaticPublicIpFailure(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	// the elastic ip of the first server is associated, the one of the second is not.
+	cloud.FailNext("AssociateAddress", nil)
+	cloud.FailNext("AssociateAddress", awserr.New("InvalidInstanceID", "The instance is not in a valid state for this operation", nil))
+	server := CreateServerInput{InstanceName: "neuron", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id,
+		MaxCount: 2, StaticPublicIp: true}
+	servers, err := server.CreateServer(fakeConnection(cloud, "ec2"))
+	if err == nil {
+		t.Fatal("expected the creation to fail as the elastic ip could not be associated")
+	}
+	if len(servers) != 2 || servers[0].InstanceId == "" || servers[1].InstanceId == "" {
+		t.Fatalf("expected the servers launched to be returned along with the error, got %+v", servers)
+	}
+	get := AddressInput{}
+	addresses, err := get.GetAddresses(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("fetching elastic ips: %v", err)
+	}
+	if len(addresses) != 1 || servers[0].AllocationId != addresses[0].AllocationId || servers[1].AllocationId != "" {
+		t.Errorf("expected the elastic ip associated to be returned and the other one released, got %+v and %+v", servers, addresses)
+	}
+}
+
+func TestPlanAddress(t *testing.T) {
+	cloud := awsfake.New()
+	server, _ := createTestServer(t, cloud)
+
+	allocate := AddressInput{Name: "web", InstanceId: server.InstanceId}
+	plan, err := allocate.PlanAllocateAddress(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("planning elastic ip: %v", err)
+	}
+	if got := plan.Operations(); !reflect.DeepEqual(got, []string{"AllocateAddress", "AssociateAddress"}) || !plan.Actions[0].Verified || plan.Actions[1].Verified {
+		t.Fatalf("unexpected plan %+v", plan)
+	}
+	get := AddressInput{}
+	if addresses, err := get.GetAddresses(fakeConnection(cloud, "ec2")); err != nil || len(addresses) != 0 {
+		t.Fatalf("expected no elastic ip to be allocated while planning, got %+v, %v", addresses, err)
+	}
+
+	address, err := allocate.AllocateAddress(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("allocating elastic ip: %v", err)
+	}
+	release := AddressInput{AllocationIds: []string{address.AllocationId}}
+	if _, err := release.PlanReleaseAddress(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+		t.Errorf("expected the plan to fail as the elastic ip is associated, got %v", err)
+	}
+	disassociate := AddressInput{AllocationIds: []string{address.AllocationId}, Action: AddressDisassociate}
+	plan, err = disassociate.PlanUpdateAddress(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("planning disassociation: %v", err)
+	}
+	if got := plan.Operations(); !reflect.DeepEqual(got, []string{"DisassociateAddress"}) || plan.Actions[0].Details["server"] != server.InstanceId {
+		t.Errorf("unexpected plan %+v", plan)
+	}
+	if addresses, err := get.GetAddresses(fakeConnection(cloud, "ec2")); err != nil || addresses[0].State != "associated" {
+		t.Errorf("expected the elastic ip to stay associated while planning, got %+v, %v", addresses, err)
+	}
+}
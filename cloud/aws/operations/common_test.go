@@ -0,0 +1,35 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	awsfake "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/fake"
+	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	"github.com/nikhilsbhat/neuron-cloudy/wait"
+)
+
+const testRegion = "us-east-1"
+
+// fakeConnection returns the input to establish the connection of the resource passed with the fake.
+// The resources are polled without delay, as the fake changes their state only when they are described.
+func fakeConnection(cloud *awsfake.Cloud, resource string) aws.EstablishConnectionInput {
+	return aws.EstablishConnectionInput{Region: testRegion, Resource: resource, Clients: cloud, Wait: &wait.Poller{Interval: time.Millisecond, Backoff: 1}}
+}
+
+// createTestNetwork creates a public network with two subnets spread across the zones of the region.
+func createTestNetwork(t *testing.T, cloud *awsfake.Cloud) NetworkResponse {
+	t.Helper()
+	network := NetworkCreateInput{
+		Name:     "neuron",
+		VpcCidr:  "10.0.0.0/16",
+		SubCidrs: []string{"10.0.1.0/24", "10.0.2.0/24"},
+		Type:     "public",
+		Ports:    []string{"22", "80"},
+	}
+	response, err := network.CreateNetwork(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating network: %v", err)
+	}
+	return response
+}
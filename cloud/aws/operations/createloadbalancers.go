@@ -1,12 +1,14 @@
 package aws
 
 import (
-	"fmt"
+	"sort"
 	"strings"
 
+	awssdk "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
 )
 
 // LoadBalanceCreateInput implements CreateLoadBalancer to create loadbalancer.
@@ -57,6 +59,9 @@ type LoadBalanceCreateInput struct {
 	// IpAddressType refers to Ip address type ex: ipv4, ipv6. If nothing is passed ipv4 is considered by default.
 	// optional parameter;
 	IpAddressType string
+	// Targets are the IDs of the instances to be registered as the backends of the loadbalancer, once it is created.
+	// optional parameter;
+	Targets []string
 	// GetRaw returns unfiltered response from the cloud if it is set to true.
 	// optional parameter;
 	GetRaw bool
@@ -84,9 +89,11 @@ type LoadBalanceResponse struct {
 	// VpcId is the ID of the network of which the loadbalancer is part of.
 	VpcId string `json:"vpcid,omitempty"`
 	// Scheme is to select the catageory of loadbalancer ex: internal, internet-facing. If not mentioned internet-facing will be created by default.
-	Scheme          string      `json:"scheme,omitempty"`
-	DefaultResponse interface{} `json:"defaultresponse,omitempty"`
-	LbDeleteStatus  string      `json:"lbdeletestatus,omitempty"`
+	Scheme string `json:"scheme,omitempty"`
+	// Listeners are the ports and protocols on which the loadbalancer listens.
+	Listeners       []ListenerResponse `json:"listeners,omitempty"`
+	DefaultResponse interface{}        `json:"defaultresponse,omitempty"`
+	LbDeleteStatus  string             `json:"lbdeletestatus,omitempty"`
 	// ClassicLb has the responses of classic loadbalancer.
 	ClassicLb []LoadBalanceResponse `json:"classiclb,omitempty"`
 	// ApplicationLb has the responses of application loadbalancer.
@@ -98,6 +105,39 @@ type LoadBalanceResponse struct {
 	GetApplicationLbRaw    ApplicationLbRaw                 `json:"getapplicationlbraw,omitempty"`
 }
 
+// ListenerResponse holds the port and protocol on which the loadbalancer listens.
+type ListenerResponse struct {
+	// Port on which the loadbalancer listens ex: 80, 443.
+	Port int64 `json:"port,omitempty"`
+	// Protocol of the listener ex: HTTP, HTTPS.
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// ClassicListeners returns the listeners of the classic loadbalancer passed, sorted by port.
+func ClassicListeners(descriptions []*elb.ListenerDescription) []ListenerResponse {
+	listeners := make([]ListenerResponse, 0, len(descriptions))
+	for _, description := range descriptions {
+		if description.Listener != nil {
+			listeners = append(listeners, ListenerResponse{Port: awssdk.Int64Value(description.Listener.LoadBalancerPort), Protocol: awssdk.StringValue(description.Listener.Protocol)})
+		}
+	}
+	sort.Slice(listeners, func(i, j int) bool { return listeners[i].Port < listeners[j].Port })
+	return listeners
+}
+
+// ApplicationListeners returns the listeners of the application loadbalancer passed, sorted by port.
+func ApplicationListeners(described *elbv2.DescribeListenersOutput) []ListenerResponse {
+	listeners := make([]ListenerResponse, 0)
+	if described == nil {
+		return listeners
+	}
+	for _, listener := range described.Listeners {
+		listeners = append(listeners, ListenerResponse{Port: awssdk.Int64Value(listener.Port), Protocol: awssdk.StringValue(listener.Protocol)})
+	}
+	sort.Slice(listeners, func(i, j int) bool { return listeners[i].Port < listeners[j].Port })
+	return listeners
+}
+
 // ApplicationLbRaw is a not used alone and has to be used along with LoadBalanceResponse.
 // This means, no function will which is accessible to user will send this in response but is passed via LoadBalanceResponse.
 type ApplicationLbRaw struct {
@@ -179,6 +219,13 @@ func (load *LoadBalanceCreateInput) CreateLoadBalancer(con aws.EstablishConnecti
 			return LoadBalanceResponse{}, err
 		}
 
+		if len(load.Targets) != 0 {
+			lbin.Instances = load.Targets
+			if _, regerr := elb.RegisterInstancesToClassicLb(lbin); regerr != nil {
+				return LoadBalanceResponse{}, regerr
+			}
+		}
+
 		response := new(LoadBalanceResponse)
 		if load.GetRaw == true {
 			response.CreateClassicLbRaw = lbCreateResponse
@@ -188,6 +235,7 @@ func (load *LoadBalanceCreateInput) CreateLoadBalancer(con aws.EstablishConnecti
 		response.Name = load.Name
 		response.Type = load.Type
 		response.LbDns = *lbCreateResponse.DNSName
+		response.Listeners = []ListenerResponse{{Port: load.LbPort, Protocol: load.Lbproto}}
 		return *response, nil
 
 	case "application":
@@ -228,6 +276,13 @@ func (load *LoadBalanceCreateInput) CreateLoadBalancer(con aws.EstablishConnecti
 			return LoadBalanceResponse{}, liserr
 		}
 
+		if len(load.Targets) != 0 {
+			lbin.Instances = load.Targets
+			if _, regerr := elb.RegisterTargets(lbin); regerr != nil {
+				return LoadBalanceResponse{}, regerr
+			}
+		}
+
 		response := new(LoadBalanceResponse)
 
 		if load.GetRaw == true {
@@ -243,9 +298,10 @@ func (load *LoadBalanceCreateInput) CreateLoadBalancer(con aws.EstablishConnecti
 		response.LbArn = *lbCreateResponse.LoadBalancers[0].LoadBalancerArn
 		response.TargetArn = *targetGroupResponse.TargetGroups[0].TargetGroupArn
 		response.ListnerArn = *listnerCreateResponse.Listeners[0].ListenerArn
+		response.Listeners = ApplicationListeners(&elbv2.DescribeListenersOutput{Listeners: listnerCreateResponse.Listeners})
 		return *response, nil
 
 	default:
-		return LoadBalanceResponse{}, fmt.Errorf("You provided unknown loadbalancer type, enter a valid LB type")
+		return LoadBalanceResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You provided unknown loadbalancer type, enter a valid LB type")
 	}
 }
@@ -0,0 +1,172 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	awsfake "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/fake"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
+)
+
+func TestCreateClassicLoadBalancer(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	create := LoadBalanceCreateInput{
+		Name:      "neuron-classic",
+		VpcId:     network.VpcId,
+		Type:      "classic",
+		LbPort:    80,
+		Lbproto:   "HTTP",
+		InstPort:  8080,
+		Instproto: "HTTP",
+	}
+	lb, err := create.CreateLoadBalancer(fakeConnection(cloud, "elb"))
+	if err != nil {
+		t.Fatalf("creating loadbalancer: %v", err)
+	}
+	if lb.LbDns == "" {
+		t.Fatalf("expected DNS of the loadbalancer created, got %+v", lb)
+	}
+
+	if _, err := create.CreateLoadBalancer(fakeConnection(cloud, "elb")); err == nil {
+		t.Fatalf("expected creation of loadbalancer with duplicate name to fail")
+	} else if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "DuplicateLoadBalancerName" {
+		t.Fatalf("expected DuplicateLoadBalancerName, got %v", err)
+	}
+
+	del := DeleteLoadbalancerInput{LbNames: []string{"neuron-classic"}, Type: "classic"}
+	deleted, err := del.DeleteLoadbalancer(fakeConnection(cloud, "elb"))
+	if err != nil {
+		t.Fatalf("deleting loadbalancer: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].LbName != "neuron-classic" {
+		t.Errorf("unexpected response of deletion: %+v", deleted)
+	}
+}
+
+func TestCreateApplicationLoadBalancer(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	create := LoadBalanceCreateInput{
+		Name:       "neuron-app",
+		VpcId:      network.VpcId,
+		Type:       "application",
+		LbPort:     80,
+		Lbproto:    "HTTP",
+		InstPort:   8080,
+		Instproto:  "HTTP",
+		HttpCode:   "200",
+		HealthPath: "/",
+	}
+	lb, err := create.CreateLoadBalancer(fakeConnection(cloud, "elb2"))
+	if err != nil {
+		t.Fatalf("creating loadbalancer: %v", err)
+	}
+	if lb.LbArn == "" || lb.TargetArn == "" || lb.ListnerArn == "" {
+		t.Fatalf("expected loadbalancer to be created along with its target group and listener, got %+v", lb)
+	}
+
+	del := DeleteLoadbalancerInput{LbArns: []string{lb.LbArn}, Type: "application"}
+	if _, err := del.DeleteLoadbalancer(fakeConnection(cloud, "elb2")); err != nil {
+		t.Fatalf("deleting loadbalancer: %v", err)
+	}
+	if calls := cloud.Calls("DeleteTargetGroup"); calls != 1 {
+		t.Errorf("expected target group of the loadbalancer to be deleted, DeleteTargetGroup was called %d times", calls)
+	}
+
+	// the network can be deleted only when the loadbalancer and its target group are gone.
+	delnet := DeleteNetworkInput{VpcIds: []string{network.VpcId}}
+	if _, err := delnet.DeleteNetwork(fakeConnection(cloud, "ec2")); err != nil {
+		t.Fatalf("deleting network: %v", err)
+	}
+}
+
+func TestCreateApplicationLoadBalancerSingleZone(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	create := LoadBalanceCreateInput{
+		Name:      "neuron-app",
+		SubnetIds: []string{network.Subnets[0].Id},
+		VpcId:     network.VpcId,
+		Type:      "application",
+		LbPort:    80,
+		Lbproto:   "HTTP",
+	}
+	_, err := create.CreateLoadBalancer(fakeConnection(cloud, "elb2"))
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "ValidationError" {
+		t.Fatalf("expected ValidationError for loadbalancer in single zone, got %v", err)
+	}
+}
+
+func TestCreateApplicationLoadBalancerInvalidTarget(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	create := LoadBalanceCreateInput{
+		Name:      "neuron-app",
+		VpcId:     network.VpcId,
+		Type:      "application",
+		LbPort:    80,
+		Lbproto:   "HTTP",
+		InstPort:  8080,
+		Instproto: "HTTP",
+		Targets:   []string{"i-00000000000000099"},
+	}
+	_, err := create.CreateLoadBalancer(fakeConnection(cloud, "elb2"))
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != elbv2.ErrCodeInvalidTargetException {
+		t.Fatalf("expected InvalidTarget for the instance which does not exist, got %v", err)
+	}
+}
+
+func TestDeleteApplicationLoadBalancerRetriesTargetGroup(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	create := LoadBalanceCreateInput{Name: "neuron-app", VpcId: network.VpcId, Type: "application", LbPort: 80, Lbproto: "HTTP", InstPort: 8080, Instproto: "HTTP", HttpCode: "200", HealthPath: "/"}
+	if _, err := create.CreateLoadBalancer(fakeConnection(cloud, "elb2")); err != nil {
+		t.Fatalf("creating loadbalancer: %v", err)
+	}
+
+	// aws holds on to the target group for a while after deleting its loadbalancer.
+	inUse := awserr.New(elbv2.ErrCodeResourceInUseException, "Target group is currently in use by a listener or a rule", nil)
+	cloud.FailNext("DeleteTargetGroup", inUse)
+	cloud.FailNext("DeleteTargetGroup", inUse)
+
+	con := fakeConnection(cloud, "elb2")
+	con.Retry = &retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	del := DeleteLoadbalancerInput{LbNames: []string{"neuron-app"}, Type: "application"}
+	if _, err := del.DeleteLoadbalancer(con); err != nil {
+		t.Fatalf("deleting loadbalancer: %v", err)
+	}
+	if calls := cloud.Calls("DeleteTargetGroup"); calls != 3 {
+		t.Errorf("expected the deletion of target group to be retried till it succeeds, DeleteTargetGroup was called %d times", calls)
+	}
+}
+
+func TestDeleteApplicationLoadBalancerRetriesExhausted(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	create := LoadBalanceCreateInput{Name: "neuron-app", VpcId: network.VpcId, Type: "application", LbPort: 80, Lbproto: "HTTP", InstPort: 8080, Instproto: "HTTP", HttpCode: "200", HealthPath: "/"}
+	if _, err := create.CreateLoadBalancer(fakeConnection(cloud, "elb2")); err != nil {
+		t.Fatalf("creating loadbalancer: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		cloud.FailNext("DeleteTargetGroup", awserr.New(elbv2.ErrCodeResourceInUseException, "Target group is currently in use by a listener or a rule", nil))
+	}
+
+	con := fakeConnection(cloud, "elb2")
+	con.Retry = &retry.Policy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+	del := DeleteLoadbalancerInput{LbNames: []string{"neuron-app"}, Type: "application"}
+	if _, err := del.DeleteLoadbalancer(con); err == nil {
+		t.Fatalf("expected the deletion to fail once the attempts are exhausted")
+	}
+	if calls := cloud.Calls("DeleteTargetGroup"); calls != 2 {
+		t.Errorf("expected DeleteTargetGroup to be attempted 2 times, got %d", calls)
+	}
+}
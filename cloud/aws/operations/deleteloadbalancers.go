@@ -1,11 +1,11 @@
 package aws
 
 import (
-	"fmt"
 	"strings"
-	"time"
 
 	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
 )
 
 // DeleteLoadbalancerInput will values enough to delete a loadbalancer
@@ -48,7 +48,7 @@ func (d *DeleteLoadbalancerInput) DeleteLoadbalancer(con aws.EstablishConnection
 	case "application":
 
 		if (d.LbNames != nil) && (d.LbArns != nil) {
-			return nil, fmt.Errorf("You provided both LbNames and LbArns to fetch applicationlb data, has to provide either of them")
+			return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided both LbNames and LbArns to fetch applicationlb data, has to provide either of them")
 		}
 
 		lbin := new(GetLoadbalancerInput)
@@ -105,12 +105,14 @@ func (d *DeleteLoadbalancerInput) DeleteLoadbalancer(con aws.EstablishConnection
 				if waiterr != nil {
 					return nil, waiterr
 				}
+				//waiting till its network interfaces are released, the subnets and security groups are held by aws until then.
+				if _, enierr := elb.WaitUntilAppLbInterfacesDeleted(getlb); enierr != nil {
+					return nil, enierr
+				}
 
-				// making this to sleep is not good idea but this is temporary fix
-				time.Sleep(5 * time.Second)
 				//deletion of targetgroups
 				delb.TargetArn = *tararn.TargetGroups[0].TargetGroupArn
-				tarerr := elb.DeleteTargetGroup(delb)
+				tarerr := deleteTargetGroup(elb, delb)
 				if tarerr != nil {
 					return nil, tarerr
 				}
@@ -123,6 +125,14 @@ func (d *DeleteLoadbalancerInput) DeleteLoadbalancer(con aws.EstablishConnection
 		if d.LbArns != nil {
 
 			for _, lbarn := range d.LbArns {
+				deslb := new(aws.DescribeLoadbalancersInput)
+				deslb.LbArns = []string{lbarn}
+				//fetching arn of targetgroup, this has to be done before deleting the loadbalancer as the lookup is made through it.
+				tararn, tararnerr := elb.DescribeTargetgroups(deslb)
+				if tararnerr != nil {
+					return nil, tararnerr
+				}
+
 				//deleting loadbalancers
 				delb := new(aws.DeleteLoadbalancerInput)
 				delb.LbArn = lbarn
@@ -131,23 +141,19 @@ func (d *DeleteLoadbalancerInput) DeleteLoadbalancer(con aws.EstablishConnection
 					return nil, delerr
 				}
 
-				deslb := new(aws.DescribeLoadbalancersInput)
-				deslb.LbArns = []string{lbarn}
 				//waiting till the loadbalancer gets deleted completed
 				waiterr := elb.WaitTillLbDeletionSuccessfull(deslb)
 				if waiterr != nil {
 					return nil, waiterr
 				}
-
-				//fetching arn of targetgroup
-				tararn, tararnerr := elb.DescribeTargetgroups(deslb)
-				if tararnerr != nil {
-					return nil, tararnerr
+				//waiting till its network interfaces are released, the subnets and security groups are held by aws until then.
+				if _, enierr := elb.WaitUntilAppLbInterfacesDeleted(deslb); enierr != nil {
+					return nil, enierr
 				}
 
 				//deletion of targetgroups
 				delb.TargetArn = *tararn.TargetGroups[0].TargetGroupArn
-				tarerr := elb.DeleteTargetGroup(delb)
+				tarerr := deleteTargetGroup(elb, delb)
 				if tarerr != nil {
 					return nil, tarerr
 				}
@@ -156,7 +162,7 @@ func (d *DeleteLoadbalancerInput) DeleteLoadbalancer(con aws.EstablishConnection
 			}
 			return lbDeleteStatus, nil
 		}
-		return nil, fmt.Errorf("You selected application lb to fetch data, but I couldn't find any valid inputs. Its is empty input")
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You selected application lb to fetch data, but I couldn't find any valid inputs. Its is empty input")
 	case "classic":
 		if d.LbNames != nil {
 
@@ -179,8 +185,18 @@ func (d *DeleteLoadbalancerInput) DeleteLoadbalancer(con aws.EstablishConnection
 			}
 			return lbDeleteStatus, nil
 		}
-		return nil, fmt.Errorf("You selected classic lb to fetch data, but I couldn't find any valid inputs. Its is empty input")
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You selected classic lb to fetch data, but I couldn't find any valid inputs. Its is empty input")
 	default:
-		return nil, fmt.Errorf("You provided empty struct to delete loadbalancer, you have to pass either Names or Arns")
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to delete loadbalancer, you have to pass either Names or Arns")
 	}
 }
+
+// deleteTargetGroup deletes the target group of the loadbalancer deleted moments ago, aws holds on to the group (ResourceInUse)
+// for a while even after the deletion of the loadbalancer completes, hence the deletion is retried as per the policy of the session.
+func deleteTargetGroup(elb aws.EstablishedSession, delb *aws.DeleteLoadbalancerInput) error {
+	return elb.Retry(func() error {
+		return elb.DeleteTargetGroup(delb)
+	}, func(err error) bool {
+		return retry.AWS(err) || cloudyerror.IsConflict(cloudyerror.FromAWS(err, "target group", delb.TargetArn))
+	})
+}
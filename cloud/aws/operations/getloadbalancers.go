@@ -1,11 +1,10 @@
 package aws
 
 import (
-	"fmt"
 	"strings"
-	"time"
 
 	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
 )
 
 // GetLoadbalancerInput implements the method GetAllLoadbalancer, GetAllClassicLb, Getloadbalancers, GetAllApplicationLb to fetch the granular level details of loadbalancers.
@@ -28,53 +27,31 @@ type GetLoadbalancerInput struct {
 // GetAllLoadbalancer will help in fetching information about all loadbalancers this include both application and classic.
 func (lb *GetLoadbalancerInput) GetAllLoadbalancer(con aws.EstablishConnectionInput) ([]LoadBalanceResponse, error) {
 
-	lbChn := make(chan interface{}, 2)
-	defer close(lbChn)
+	// the loadbalancers of each type are fetched concurrently, each onto a channel of its own so that they are told apart.
+	classicChn, applicationChn := make(chan lbResult, 1), make(chan lbResult, 1)
 	go func() {
 		lbs, err := lb.GetAllClassicLb(con)
-		time.Sleep(time.Second * 1)
-		if err != nil {
-			lbChn <- err
-		} else {
-			lbChn <- lbs
-		}
+		classicChn <- lbResult{lbs: lbs, err: err}
 	}()
 	go func() {
 		lbs, err := lb.GetAllApplicationLb(con)
-		time.Sleep(time.Second * 2)
-		if err != nil {
-			lbChn <- err
-		} else {
-			lbChn <- lbs
-		}
+		applicationChn <- lbResult{lbs: lbs, err: err}
 	}()
 
-	// this is just a workaround and has to be fixed soon.
-	classiclb := <-lbChn
-	applicationlb := <-lbChn
-
-	response := new(LoadBalanceResponse)
-	switch calssic := classiclb.(type) {
-	case []LoadBalanceResponse:
-		response.ClassicLb = calssic
-	case error:
-		return nil, calssic
-	default:
-		return nil, fmt.Errorf("An unknown error occurred while returning classiclb data")
+	classiclb, applicationlb := <-classicChn, <-applicationChn
+	if classiclb.err != nil {
+		return nil, classiclb.err
 	}
-
-	switch app := applicationlb.(type) {
-	case []LoadBalanceResponse:
-		response.ApplicationLb = app
-	case error:
-		return nil, app
-	default:
-		return nil, fmt.Errorf("An unknown error occurred while returning classiclb data")
+	if applicationlb.err != nil {
+		return nil, applicationlb.err
 	}
+	return []LoadBalanceResponse{{ClassicLb: classiclb.lbs, ApplicationLb: applicationlb.lbs}}, nil
+}
 
-	resp := make([]LoadBalanceResponse, 0)
-	resp = append(resp, *response)
-	return resp, nil
+// lbResult carries the loadbalancers fetched by GetAllLoadbalancer of a type, or the error of fetching them.
+type lbResult struct {
+	lbs []LoadBalanceResponse
+	err error
 }
 
 // GetAllClassicLb will fetch information about all the classic loadbalancers.
@@ -101,7 +78,7 @@ func (lb *GetLoadbalancerInput) GetAllClassicLb(con aws.EstablishConnectionInput
 		return lbList, nil
 	}
 	for _, load := range searchLbResult.LoadBalancerDescriptions {
-		lbList = append(lbList, LoadBalanceResponse{Name: *load.LoadBalancerName, LbDns: *load.DNSName, Createdon: (*load.CreatedTime).String(), Type: "classic", Scheme: *load.Scheme, VpcId: *load.VPCId})
+		lbList = append(lbList, LoadBalanceResponse{Name: *load.LoadBalancerName, LbDns: *load.DNSName, Createdon: (*load.CreatedTime).String(), Type: "classic", Scheme: *load.Scheme, VpcId: *load.VPCId, Listeners: ClassicListeners(load.ListenerDescriptions)})
 	}
 	return lbList, nil
 }
@@ -173,6 +150,7 @@ func (lb *GetLoadbalancerInput) GetAllApplicationLb(con aws.EstablishConnectionI
 			response.VpcId = *load.VpcId
 			response.TargetArn = tarArn
 			response.ListnerArn = lisRrn
+			response.Listeners = ApplicationListeners(searchListeners)
 			lbList = append(lbList, *response)
 		}
 	}
@@ -196,7 +174,7 @@ func (lb *GetLoadbalancerInput) Getloadbalancers(con aws.EstablishConnectionInpu
 		}
 		return getlb, nil
 	default:
-		return nil, fmt.Errorf("You provided unknown loadbalancer type, enter a valid LB type")
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You provided unknown loadbalancer type, enter a valid LB type")
 	}
 }
 
@@ -231,6 +209,7 @@ func (lb *GetLoadbalancerInput) GetClassicloadbalancers(con aws.EstablishConnect
 		response.Type = "classic"
 		response.Scheme = *load.Scheme
 		response.VpcId = *load.VPCId
+		response.Listeners = ClassicListeners(load.ListenerDescriptions)
 		lbResponse = append(lbResponse, *response)
 	}
 
@@ -295,6 +274,7 @@ func (lb *GetLoadbalancerInput) GetApplicationloadbalancers(con aws.EstablishCon
 			response.VpcId = *load.VpcId
 			response.TargetArn = tarArn
 			response.ListnerArn = lisRrn
+			response.Listeners = ApplicationListeners(searchListeners)
 			lbResponse = append(lbResponse, *response)
 		}
 	}
@@ -321,7 +301,7 @@ func (lb *GetLoadbalancerInput) FindClassicLoadbalancer(con aws.EstablishConnect
 	if len(getLoadbalancer.LoadBalancerDescriptions) != 0 {
 		return true, nil
 	}
-	return false, fmt.Errorf("Could not find the entered loadbalancer, please enter valid/existing loadbalancer Name")
+	return false, &cloudyerror.Error{Code: cloudyerror.NotFound, Cloud: "aws", Kind: "loadbalancer", Message: "Could not find the entered loadbalancer, please enter valid/existing loadbalancer Name"}
 }
 
 // FindApplicationLoadbalancer will return ture if loadbalancer exists in the system.
@@ -345,7 +325,7 @@ func (lb *GetLoadbalancerInput) FindApplicationLoadbalancer(con aws.EstablishCon
 	if len(getLoadbalancer.LoadBalancers) != 0 {
 		return true, nil
 	}
-	return false, fmt.Errorf("Could not find the entered loadbalancer, please enter valid/existing loadbalancer ARN/Name")
+	return false, &cloudyerror.Error{Code: cloudyerror.NotFound, Cloud: "aws", Kind: "loadbalancer", Message: "Could not find the entered loadbalancer, please enter valid/existing loadbalancer ARN/Name"}
 }
 
 // GetArnFromLoadbalancer will help in fetching ARN from the selected loadbalancer.
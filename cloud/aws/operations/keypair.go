@@ -0,0 +1,132 @@
+package aws
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+	"github.com/nikhilsbhat/neuron-cloudy/sshkey"
+)
+
+// The methods below manage the key pairs with which the instances are logged into, aws keeps only the public key of the key pair
+// hence the private key of the one created is returned only once, by CreateKeyPair.
+
+// KeyPairInput holds the values required to create/import/fetch/delete the key pairs.
+type KeyPairInput struct {
+	// KeyNames are the names of the key pairs to be fetched/deleted, the first one is the name of the key pair to be created/imported.
+	KeyNames []string `json:"keynames"`
+	// PublicKey is the RSA public key in the OpenSSH format (ex: ssh-rsa AAAA... user@host) to be imported by ImportKeyPair.
+	PublicKey string `json:"publickey"`
+	GetRaw    bool   `json:"getraw"`
+}
+
+// KeyPairResponse holds the details of the key pair.
+type KeyPairResponse struct {
+	// Name of the key pair.
+	Name string `json:"Name,omitempty"`
+	// Fingerprint of the key pair, it is the SHA-1 digest of the private key for the ones created by aws and the MD5 digest of the public key for the ones imported.
+	Fingerprint string `json:"Fingerprint,omitempty"`
+	// PrivateKey is the PEM encoded private key of the key pair created, it is not retained by aws hence it cannot be fetched again.
+	PrivateKey string `json:"PrivateKey,omitempty"`
+	// KeyPairRaw holds the unfiltered response from aws for the key pair.
+	KeyPairRaw *ec2.KeyPairInfo `json:"KeyPairRaw,omitempty"`
+}
+
+// CreateKeyPair creates the key pair named as the first of KeyNames, the private key of the key pair is part of the response.
+func (k *KeyPairInput) CreateKeyPair(con aws.EstablishConnectionInput) (KeyPairResponse, error) {
+
+	if len(k.KeyNames) == 0 {
+		return KeyPairResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the name of the key pair to be created")
+	}
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return KeyPairResponse{}, seserr
+	}
+	return createKeyPair(sess, k.KeyNames[0], k.GetRaw)
+}
+
+// ImportKeyPair imports the PublicKey as the key pair named as the first of KeyNames, the private key stays with the caller.
+// The public key is validated before it is sent to aws.
+func (k *KeyPairInput) ImportKeyPair(con aws.EstablishConnectionInput) (KeyPairResponse, error) {
+
+	if len(k.KeyNames) == 0 {
+		return KeyPairResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the name of the key pair to be imported")
+	}
+	if _, keyerr := sshkey.Parse(k.PublicKey); keyerr != nil {
+		return KeyPairResponse{}, keyerr
+	}
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return KeyPairResponse{}, seserr
+	}
+	imported, imperr := sess.ImportKeyPair(&aws.KeyPairInput{KeyNames: []string{k.KeyNames[0]}, PublicKey: k.PublicKey})
+	if imperr != nil {
+		return KeyPairResponse{}, imperr
+	}
+	pair := &ec2.KeyPairInfo{KeyName: imported.KeyName, KeyFingerprint: imported.KeyFingerprint}
+	return keyPairResponse(pair, k.GetRaw), nil
+}
+
+// GetKeyPairs fetches the key pairs passed along with their fingerprints, all the key pairs of the region are fetched if none are passed.
+func (k *KeyPairInput) GetKeyPairs(con aws.EstablishConnectionInput) ([]KeyPairResponse, error) {
+
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return nil, seserr
+	}
+	pairs, err := sess.DescribeKeyPairs(&aws.KeyPairInput{KeyNames: k.KeyNames})
+	if err != nil {
+		return nil, err
+	}
+	response := make([]KeyPairResponse, 0, len(pairs.KeyPairs))
+	for _, pair := range pairs.KeyPairs {
+		response = append(response, keyPairResponse(pair, k.GetRaw))
+	}
+	return response, nil
+}
+
+// DeleteKeyPair deletes the key pairs passed, the ones deleted before the failure are returned along with the error.
+// The instances launched with the key pairs are left as is, but no longer can be launched with them.
+func (k *KeyPairInput) DeleteKeyPair(con aws.EstablishConnectionInput) ([]KeyPairResponse, error) {
+
+	if len(k.KeyNames) == 0 {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the key pairs to be deleted")
+	}
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return nil, seserr
+	}
+	// the key pairs are fetched first, as aws reports the deletion of the key pair which does not exist as success.
+	pairs, geterr := sess.DescribeKeyPairs(&aws.KeyPairInput{KeyNames: k.KeyNames})
+	if geterr != nil {
+		return nil, geterr
+	}
+
+	response := make([]KeyPairResponse, 0, len(pairs.KeyPairs))
+	for _, pair := range pairs.KeyPairs {
+		if delerr := sess.DeleteKeyPair(&aws.KeyPairInput{KeyNames: []string{*pair.KeyName}}); delerr != nil {
+			return response, delerr
+		}
+		response = append(response, keyPairResponse(pair, false))
+	}
+	return response, nil
+}
+
+// createKeyPair creates the key pair named, the private key is returned along with the details of the key pair even if raw is set.
+func createKeyPair(sess aws.EstablishedSession, name string, raw bool) (KeyPairResponse, error) {
+	created, err := sess.CreateKeyPair(&aws.KeyPairInput{KeyNames: []string{name}})
+	if err != nil {
+		return KeyPairResponse{}, err
+	}
+	response := keyPairResponse(&ec2.KeyPairInfo{KeyName: created.KeyName, KeyFingerprint: created.KeyFingerprint}, raw)
+	response.PrivateKey = awssdk.StringValue(created.KeyMaterial)
+	return response, nil
+}
+
+// keyPairResponse returns the details of the key pair, the unfiltered response of aws is returned if raw is set.
+func keyPairResponse(pair *ec2.KeyPairInfo, raw bool) KeyPairResponse {
+	if raw {
+		return KeyPairResponse{KeyPairRaw: pair}
+	}
+	return KeyPairResponse{Name: awssdk.StringValue(pair.KeyName), Fingerprint: awssdk.StringValue(pair.KeyFingerprint)}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/service/ec2"
 	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
 )
 
 // NetworkCreateInput will implement almost all the creation of network and its components under cloud/operations.
@@ -15,6 +16,8 @@ type NetworkCreateInput struct {
 	VpcCidr string `json:"vpccidr"`
 	// SubCidrs would be the list of CIDR bolcks for the subnetworks that would be created.
 	SubCidrs []string `json:"subcidrs"`
+	// Layout carves the CIDR blocks of the subnets out of that of the network, when SubCidrs are not passed.
+	Layout SubnetLayout `json:"layout"`
 	// SubCidr would be the CIDR bolck for the subnetwork that would be created.
 	SubCidr string `json:"subcidr"`
 	// Name of the network that would be created.
@@ -30,6 +33,8 @@ type NetworkCreateInput struct {
 	// IgwId refers to the ID of the internet gateway which should be updated/deleted.
 	IgwId  string `json:"igwid"`
 	GetRaw bool   `json:"getraw"`
+	// rollback if set, records the components created so that they can be removed on failure.
+	rollback *rollback
 }
 
 // NetworkResponse will be the response type of almost all the network related activities under cloud/operations.
@@ -52,8 +57,16 @@ type NetworkResponse struct {
 	IsDefault bool `json:"isdefault,omitempty"`
 	// SecGroupIds are the list of security groups IDs that is associated with the network/subnetwork.
 	SecGroupIds []string `json:"secgroupid,omitempty"`
+	// SecurityGroups holds the details of the security groups of the network, along with the ports opened on them.
+	SecurityGroups []SecurityGroupResponse `json:"securitygroups,omitempty"`
+	// Cidr is the block of IP addresses of the network.
+	Cidr string `json:"cidr,omitempty"`
+	// SecondaryCidrs are the blocks of IP addresses associated with the network other than its primary block.
+	SecondaryCidrs []string `json:"secondarycidrs,omitempty"`
 	// Region name in which the network/subnetwork or its component were created.
-	Region                string                              `json:"region,omitempty"`
+	Region string `json:"region,omitempty"`
+	// Rollback is set when the creation of network failed midway, it reports the components which were cleaned up and the ones left behind.
+	Rollback              *RollbackResponse                   `json:"rollback,omitempty"`
 	GetVpcsRaw            *ec2.DescribeVpcsOutput             `json:"getvpcsraw,omitempty"`
 	GetVpcRaw             *ec2.Vpc                            `json:"getvpcraw,omitempty"`
 	GetSubnetRaw          *ec2.DescribeSubnetsOutput          `json:"getsubnetraw,omitempty"`
@@ -78,7 +91,11 @@ type DeleteNetworkInput struct {
 	IgwIds []string `json:"igwid"`
 	// RouteTableIds are the list of routetable that are associated with subnetwork and has to be deleted.
 	RouteTableIds []string `json:"routetableids"`
-	GetRaw        bool     `json:"getraw"`
+	// Force deletes the resources which depends on the network before deleting it, which otherwise fails the deletion of network.
+	// The servers are terminated, the loadbalancers along with the target groups, nat gateways and network interfaces are deleted
+	// and the elastic ips associated with any of them are released.
+	Force  bool `json:"force"`
+	GetRaw bool `json:"getraw"`
 }
 
 // GetNetworksInput will implement almost all the methods of fetching network and its components under cloud/operations.
@@ -108,6 +125,18 @@ type DeleteNetworkResponse struct {
 	Vpcs            string `json:"vpcs,omitempty"`
 	DefaultResponse string `json:"defaultresponse,omitempty"`
 	Status          string `json:"status,omitempty"`
+	// Deleted is the log of the deletion, it holds every resource deleted in the order they were deleted.
+	Deleted []DeletedResource `json:"deleted,omitempty"`
+}
+
+// DeletedResource is an entry of the log of DeleteNetwork, one for every resource deleted while deleting the network.
+type DeletedResource struct {
+	// Kind of the resource deleted ex: server, loadbalancer, targetgroup, natgateway, networkinterface, elasticip, subnet, network.
+	Kind string `json:"kind"`
+	// Id of the resource deleted, the loadbalancers are identified by its name (classic) or arn (application).
+	Id string `json:"id"`
+	// Status of the resource once it is deleted ex: terminated, deleted, released.
+	Status string `json:"status"`
 }
 
 // UpdateNetworkInput will implement the methods that will update the network and its components under cloud/operations.
@@ -118,7 +147,9 @@ type UpdateNetworkInput struct {
 	Network NetworkCreateInput `json:"network"`
 	// Action to be performed on the resource selected.
 	Action string `json:"action"`
-	GetRaw bool   `json:"getRaw"`
+	// Subnets are the names or IDs of the subnetworks to be deleted.
+	Subnets []string `json:"subnets"`
+	GetRaw  bool     `json:"getRaw"`
 }
 
 // Filters will help one to have a hold on the call that they make, will help to filter the quiries.
@@ -129,10 +160,12 @@ type UpdateNetworkInput struct {
 // }
 
 // CreateNetwork is a customized method for network creation, if one needs to create the individual components of network then call the appropriate methods.
+// The creation is all or nothing, if it fails midway the components created so far are deleted in reverse order
+// and the error is returned along with the response holding the details of it in Rollback.
 func (net *NetworkCreateInput) CreateNetwork(con aws.EstablishConnectionInput) (NetworkResponse, error) {
 
 	if (net.VpcCidr == "") || (net.Name == "") {
-		return NetworkResponse{}, fmt.Errorf("You have not provided either CIDR or name for VPC, cannot proceed further")
+		return NetworkResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not provided either CIDR or name for VPC, cannot proceed further")
 	}
 
 	/*get the relative sessions before proceeding further
@@ -141,38 +174,39 @@ func (net *NetworkCreateInput) CreateNetwork(con aws.EstablishConnectionInput) (
 	          return NetworkResponse{}, seserr
 	  }*/
 
+	// the subnets are laid out ahead, so that nothing is created if their blocks does not fit in the network.
+	zonein := CommonInput{}
+	zones, zonerr := zonein.GetAvailabilityZones(con)
+	if zonerr != nil {
+		return NetworkResponse{}, zonerr
+	}
+	layout, layouterr := net.layoutSubnets(zones, []string{net.VpcCidr}, nil)
+	if layouterr != nil {
+		return NetworkResponse{}, layouterr
+	}
+
 	netin := new(NetworkCreateInput)
 	netin.VpcCidr = net.VpcCidr
 	netin.Name = net.Name
 	netin.Type = net.Type
 	netin.Ports = net.Ports
 	netin.GetRaw = net.GetRaw
+	netin.rollback = new(rollback)
 
 	vpc, err := netin.CreateVpc(con)
 	if err != nil {
-		return NetworkResponse{}, err
-	}
-
-	zonein := CommonInput{}
-	zones, zonerr := zonein.GetAvailabilityZones(con)
-	if zonerr != nil {
-		return NetworkResponse{}, zonerr
+		return NetworkResponse{Rollback: netin.rollback.run(con)}, err
 	}
 
 	// This takes care creation of required number of subnets.
 	subnets := make([]SubnetReponse, 0)
-
-	zonenum := len(zones) - 1
-	for i, sub := range net.SubCidrs {
-
-		if zonenum < 0 {
-			zonenum = len(zones) - 1
-		}
+	for i, sub := range layout {
 
 		// Creating subnet by calling appropriate object
-		netin.SubCidr = sub
+		netin.SubCidr = sub.cidr
 		netin.Name = net.Name + "_sub" + strconv.Itoa(i)
-		netin.Zone = zones[zonenum]
+		netin.Zone = sub.zone
+		netin.Type = sub.subnetType
 		if net.GetRaw == true {
 			netin.VpcId = *vpc.CreateVpcRaw.Vpc.VpcId
 			netin.IgwId = *vpc.CreateIgwRaw.InternetGateway.InternetGatewayId
@@ -183,20 +217,20 @@ func (net *NetworkCreateInput) CreateNetwork(con aws.EstablishConnectionInput) (
 
 		subnet, suberr := netin.CreateSubnet(con)
 		if suberr != nil {
-			return NetworkResponse{}, suberr
+			return NetworkResponse{Rollback: netin.rollback.run(con)}, suberr
 		}
 		subnets = append(subnets, subnet)
-
-		zonenum--
 	}
 	if net.GetRaw == true {
 		return NetworkResponse{CreateVpcRaw: vpc, CreateSubnetRaw: subnets}, nil
 	}
-	return NetworkResponse{Name: vpc.Name, VpcId: vpc.VpcId, Subnets: subnets, Type: vpc.Type, IgwId: vpc.IgwId, SecGroupIds: vpc.SecGroupIds}, nil
+	return NetworkResponse{Name: vpc.Name, VpcId: vpc.VpcId, Subnets: subnets, Type: vpc.Type, IgwId: vpc.IgwId, SecGroupIds: vpc.SecGroupIds, SecurityGroups: vpc.SecurityGroups, Cidr: net.VpcCidr}, nil
 
 }
 
 // DeleteNetwork is a customized method for deletion of network, if one needs to delete the individual components of network then call the appropriate methods.
+// The dependents of the network are deleted first if Force is set, every deletion is waited on before moving to the next.
+// The resources deleted are logged in the response, which is returned along with the error if the deletion fails midway.
 func (d *DeleteNetworkInput) DeleteNetwork(con aws.EstablishConnectionInput) (DeleteNetworkResponse, error) {
 
 	vpcin := GetNetworksInput{VpcIds: d.VpcIds}
@@ -206,17 +240,27 @@ func (d *DeleteNetworkInput) DeleteNetwork(con aws.EstablishConnectionInput) (De
 	}
 
 	if vpc != true {
-		return DeleteNetworkResponse{}, fmt.Errorf("Could not find the entered VPC, please enter valid/existing VPC id")
+		return DeleteNetworkResponse{}, &cloudyerror.Error{Code: cloudyerror.NotFound, Cloud: "aws", Kind: "network", Message: "Could not find the entered VPC, please enter valid/existing VPC id"}
+	}
+
+	deleted := make([]DeletedResource, 0)
+	if d.Force {
+		dependents, deperr := d.deleteNetworkDependents(con)
+		deleted = append(deleted, dependents...)
+		if deperr != nil {
+			return DeleteNetworkResponse{Deleted: deleted}, deperr
+		}
 	}
 
 	networkdel, neterr := d.getNetworkDeletables(con)
 	if neterr != nil {
-		return DeleteNetworkResponse{}, neterr
+		return DeleteNetworkResponse{Deleted: deleted}, neterr
 	}
 
 	deletestatus, netdelerr := networkdel.deleteNetworkDeletables(con)
+	deletestatus.Deleted = append(deleted, deletestatus.Deleted...)
 	if netdelerr != nil {
-		return DeleteNetworkResponse{}, netdelerr
+		return DeleteNetworkResponse{Deleted: deletestatus.Deleted}, netdelerr
 	}
 	return deletestatus, nil
 }
@@ -228,13 +272,15 @@ func (d *DeleteNetworkInput) deleteNetworkDeletables(con aws.EstablishConnection
 	          return DeleteNetworkResponse{}, seserr
 	  }*/
 
+	deleted := make([]DeletedResource, 0)
 	if len(d.SecIds) != 0 {
 		//Deletion of security groups
 		delsecin := NetworkComponentInput{SecGroupIds: d.SecIds}
 		secdelerr := delsecin.DeleteSecutiryGroup(con)
 		if secdelerr != nil {
-			return DeleteNetworkResponse{}, secdelerr
+			return DeleteNetworkResponse{Deleted: deleted}, secdelerr
 		}
+		deleted = logDeleted(deleted, "securitygroup", "deleted", d.SecIds...)
 	}
 
 	if len(d.RouteTableIds) != 0 {
@@ -242,17 +288,18 @@ func (d *DeleteNetworkInput) deleteNetworkDeletables(con aws.EstablishConnection
 		route := NetworkComponentInput{RouteTableIds: d.RouteTableIds}
 		dessroutetable, dessrouterr := route.DisassociateRouteTable(con)
 		if dessrouterr != nil {
-			return DeleteNetworkResponse{}, dessrouterr
+			return DeleteNetworkResponse{Deleted: deleted}, dessrouterr
 		}
 		if dessroutetable != true {
-			return DeleteNetworkResponse{}, fmt.Errorf("An error occurred while dettaching routetable from subnet")
+			return DeleteNetworkResponse{Deleted: deleted}, fmt.Errorf("An error occurred while dettaching routetable from subnet")
 		}
 
 		//deletion of routetable is handled by below loop.
 		delrouterr := route.DeleteRouteTable(con)
 		if delrouterr != nil {
-			return DeleteNetworkResponse{}, delrouterr
+			return DeleteNetworkResponse{Deleted: deleted}, delrouterr
 		}
+		deleted = logDeleted(deleted, "routetable", "deleted", d.RouteTableIds...)
 	}
 
 	if len(d.IgwIds) != 0 {
@@ -260,32 +307,35 @@ func (d *DeleteNetworkInput) deleteNetworkDeletables(con aws.EstablishConnection
 		dettachgateway := NetworkComponentInput{IgwIds: d.IgwIds, VpcIds: d.VpcIds}
 		detacherr := dettachgateway.DetachIgws(con)
 		if detacherr != nil {
-			return DeleteNetworkResponse{}, detacherr
+			return DeleteNetworkResponse{Deleted: deleted}, detacherr
 		}
 
 		//deletion of igw is been done by below snippet.
 		deletegateway := NetworkComponentInput{IgwIds: d.IgwIds}
 		deleteigwerr := deletegateway.DeleteIgws(con)
 		if deleteigwerr != nil {
-			return DeleteNetworkResponse{}, deleteigwerr
+			return DeleteNetworkResponse{Deleted: deleted}, deleteigwerr
 		}
+		deleted = logDeleted(deleted, "internetgateway", "deleted", d.IgwIds...)
 	}
 
 	if len(d.SubnetIds) != 0 {
 		subdelin := DeleteNetworkInput{SubnetIds: d.SubnetIds}
 		subdelerr := subdelin.DeleteSubnets(con)
 		if subdelerr != nil {
-			return DeleteNetworkResponse{}, subdelerr
+			return DeleteNetworkResponse{Deleted: deleted}, subdelerr
 		}
+		deleted = logDeleted(deleted, "subnet", "deleted", d.SubnetIds...)
 	}
 
 	//deletion of vpc is handled by below snippet
 	deletevpc := DeleteNetworkInput{VpcIds: d.VpcIds}
 	deletevpcerr := deletevpc.DeleteVpc(con)
 	if deletevpcerr != nil {
-		return DeleteNetworkResponse{}, deletevpcerr
+		return DeleteNetworkResponse{Deleted: deleted}, deletevpcerr
 	}
-	return DeleteNetworkResponse{Status: "Network and all its components has been deleted successfully"}, nil
+	deleted = logDeleted(deleted, "network", "deleted", d.VpcIds...)
+	return DeleteNetworkResponse{Status: "Network and all its components has been deleted successfully", Deleted: deleted}, nil
 }
 
 func (d *DeleteNetworkInput) getNetworkDeletables(con aws.EstablishConnectionInput) (DeleteNetworkInput, error) {
@@ -435,9 +485,9 @@ func (net *GetNetworksInput) GetNetwork(con aws.EstablishConnectionInput) ([]Net
 			networkresponse = append(networkresponse, subnets)
 		} else {
 			if vpc.Tags != nil {
-				networkresponse = append(networkresponse, NetworkResponse{Name: *vpc.Tags[0].Value, VpcId: *vpc.VpcId, Subnets: subnets.Subnets, State: *vpc.State, IgwId: igw.IgwIds[0], IsDefault: *vpc.IsDefault, SecGroupIds: sec.SecGroupIds})
+				networkresponse = append(networkresponse, NetworkResponse{Name: *vpc.Tags[0].Value, VpcId: *vpc.VpcId, Subnets: subnets.Subnets, State: *vpc.State, IgwId: igw.IgwIds[0], IsDefault: *vpc.IsDefault, SecGroupIds: sec.SecGroupIds, SecurityGroups: sec.SecurityGroups, Cidr: *vpc.CidrBlock})
 			} else {
-				networkresponse = append(networkresponse, NetworkResponse{VpcId: *vpc.VpcId, Subnets: subnets.Subnets, State: *vpc.State, IgwId: igw.IgwIds[0], IsDefault: *vpc.IsDefault, SecGroupIds: sec.SecGroupIds})
+				networkresponse = append(networkresponse, NetworkResponse{VpcId: *vpc.VpcId, Subnets: subnets.Subnets, State: *vpc.State, IgwId: igw.IgwIds[0], IsDefault: *vpc.IsDefault, SecGroupIds: sec.SecGroupIds, SecurityGroups: sec.SecurityGroups, Cidr: *vpc.CidrBlock})
 			}
 		}
 	}
@@ -490,9 +540,9 @@ func (net *GetNetworksInput) GetAllNetworks(con aws.EstablishConnectionInput) ([
 			networkresponse = append(networkresponse, *netres)
 		} else {
 			if vpc.Tags != nil {
-				networkresponse = append(networkresponse, NetworkResponse{Name: *vpc.Tags[0].Value, VpcId: *vpc.VpcId, Subnets: subnets.Subnets, State: *vpc.State, IgwId: igw.IgwIds[0], SecGroupIds: sec.SecGroupIds, IsDefault: *vpc.IsDefault, Region: con.Region})
+				networkresponse = append(networkresponse, NetworkResponse{Name: *vpc.Tags[0].Value, VpcId: *vpc.VpcId, Subnets: subnets.Subnets, State: *vpc.State, IgwId: igw.IgwIds[0], SecGroupIds: sec.SecGroupIds, SecurityGroups: sec.SecurityGroups, Cidr: *vpc.CidrBlock, IsDefault: *vpc.IsDefault, Region: con.Region})
 			} else {
-				networkresponse = append(networkresponse, NetworkResponse{VpcId: *vpc.VpcId, Subnets: subnets.Subnets, State: *vpc.State, IgwId: igw.IgwIds[0], SecGroupIds: sec.SecGroupIds, IsDefault: *vpc.IsDefault, Region: con.Region})
+				networkresponse = append(networkresponse, NetworkResponse{VpcId: *vpc.VpcId, Subnets: subnets.Subnets, State: *vpc.State, IgwId: igw.IgwIds[0], SecGroupIds: sec.SecGroupIds, SecurityGroups: sec.SecurityGroups, Cidr: *vpc.CidrBlock, IsDefault: *vpc.IsDefault, Region: con.Region})
 			}
 		}
 	}
@@ -500,58 +550,37 @@ func (net *GetNetworksInput) GetAllNetworks(con aws.EstablishConnectionInput) ([
 }
 
 // UpdateNetwork is a customized method for updating the network and its components, if one needs to update the individual components network then this method does just that. For more operations call GOD, interface which talks to cloud.
+// The updates supported are (resource/action): subnets/create creates the subnetworks of SubCidrs and subnets/delete deletes the ones named in Subnets along with their route tables,
+// vpc/addcidr and vpc/removecidr adds and removes the secondary CIDR block passed in VpcCidr,
+// vpc/public routes every subnetwork to the internet gateway (attached if missing) and vpc/private removes the internet gateway along with the routes to it,
+// igw/attach, igw/detach and igw/replace attaches, removes and replaces the internet gateway (the one passed in IgwId is used if any, else a new one is created),
+// securitygroup/add and securitygroup/remove opens and closes the Ports on the security groups of the network.
 func (net *UpdateNetworkInput) UpdateNetwork(con aws.EstablishConnectionInput) (NetworkResponse, error) {
 
+	if net.Network.VpcId == "" {
+		return NetworkResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the ID of the network to be updated")
+	}
+
 	switch strings.ToLower(net.Resource) {
 	case "subnets":
 
 		switch strings.ToLower(net.Action) {
 		case "create":
-			// Collects all the available availability zones
-			var zones []string
-			if net.Network.Zone == "" {
-				zonein := CommonInput{}
-				zone, zonerr := zonein.GetAvailabilityZones(con)
-				if zonerr != nil {
-					return NetworkResponse{}, zonerr
-				}
-				zones = zone
-			} else {
-				zones = []string{net.Network.Zone}
+			// the subnets are laid out in the blocks of the network around the ones present, before creating any of them.
+			layout, uqnchr, layouterr := net.layoutNetworkSubnets(con)
+			if layouterr != nil {
+				return NetworkResponse{}, layouterr
 			}
+
 			// I will be the spoc for subnets creation in the loop as per the request made
 			subnetresponse := make([]SubnetReponse, 0)
-			zonenum := len(zones) - 1
-
-			//Fetching unique number to give our subnet a unique name
-			subnets := make([]string, 0)
-			subnetin := GetNetworksInput{VpcIds: []string{net.Network.VpcId}}
-			subnetlist, suberr := subnetin.GetSubnetsFromVpc(con)
-			if suberr != nil {
-				return NetworkResponse{}, suberr
-			}
-			for _, subnet := range subnetlist.Subnets {
-				if subnet.Name != "" {
-					subnets = append(subnets, subnet.Name)
-				}
-			}
-
-			// Getting Unique digit to name subnet uniquly
-			uqnin := CommonInput{SortInput: subnets}
-			uqnchr, unerr := uqnin.GetUniqueNumberFromTags()
-			if unerr != nil {
-				return NetworkResponse{}, unerr
-			}
-			for _, sub := range net.Network.SubCidrs {
-				if zonenum < 0 {
-					zonenum = len(zones) - 1
-				}
-
+			for _, sub := range layout {
 				// Creating subnet by calling appropriate object
 				subin := NetworkCreateInput{
-					SubCidr: sub,
+					SubCidr: sub.cidr,
 					Name:    net.Network.Name + "_sub" + strconv.Itoa(uqnchr),
-					Zone:    zones[zonenum],
+					Zone:    sub.zone,
+					Type:    sub.subnetType,
 					VpcId:   net.Network.VpcId,
 					GetRaw:  net.GetRaw,
 				}
@@ -560,8 +589,6 @@ func (net *UpdateNetworkInput) UpdateNetwork(con aws.EstablishConnectionInput) (
 					return NetworkResponse{}, suberr
 				}
 				subnetresponse = append(subnetresponse, subnet)
-
-				zonenum--
 				uqnchr++
 			}
 			if net.GetRaw == true {
@@ -569,16 +596,51 @@ func (net *UpdateNetworkInput) UpdateNetwork(con aws.EstablishConnectionInput) (
 			}
 			return NetworkResponse{Subnets: subnetresponse}, nil
 		case "delete":
-			return NetworkResponse{}, fmt.Errorf(fmt.Sprintf("Either we are not supporting the action %s of the resource %s or you entered wrong name. The action you selected was: %s", net.Action, net.Resource, net.Action))
+			return net.deleteSubnets(con)
 		default:
-			return NetworkResponse{}, fmt.Errorf(fmt.Sprintf("Either we are not supporting the action %s of the resource %s or you entered wrong name. The action you selected was: %s", net.Action, net.Resource, net.Action))
+			return NetworkResponse{}, cloudyerror.Newf(cloudyerror.Unsupported, "Either we are not supporting the action %s of the resource %s or you entered wrong name", net.Action, net.Resource)
 		}
 
 	case "vpc":
-		return NetworkResponse{}, fmt.Errorf(fmt.Sprintf("Either we are not supporting updation of the resource you entered or you entered wrong name. The resource you enetered was: %s", net.Resource))
+
+		switch strings.ToLower(net.Action) {
+		case "addcidr":
+			return net.addVpcCidr(con)
+		case "removecidr":
+			return net.removeVpcCidr(con)
+		case "public":
+			return net.makePublic(con)
+		case "private":
+			return net.makePrivate(con)
+		default:
+			return NetworkResponse{}, cloudyerror.Newf(cloudyerror.Unsupported, "Either we are not supporting the action %s of the resource %s or you entered wrong name", net.Action, net.Resource)
+		}
+
 	case "igw":
-		return NetworkResponse{}, fmt.Errorf(fmt.Sprintf("Either we are not supporting updation of the resource you entered or you entered wrong name. The resource you enetered was: %s", net.Resource))
+
+		switch strings.ToLower(net.Action) {
+		case "attach":
+			return net.attachIgw(con)
+		case "detach":
+			return net.detachIgw(con)
+		case "replace":
+			return net.replaceIgw(con)
+		default:
+			return NetworkResponse{}, cloudyerror.Newf(cloudyerror.Unsupported, "Either we are not supporting the action %s of the resource %s or you entered wrong name", net.Action, net.Resource)
+		}
+
+	case "securitygroup":
+
+		switch strings.ToLower(net.Action) {
+		case "add":
+			return net.updatePorts(con, true)
+		case "remove":
+			return net.updatePorts(con, false)
+		default:
+			return NetworkResponse{}, cloudyerror.Newf(cloudyerror.Unsupported, "Either we are not supporting the action %s of the resource %s or you entered wrong name", net.Action, net.Resource)
+		}
+
 	default:
-		return NetworkResponse{}, fmt.Errorf(fmt.Sprintf("Either we are not supporting updation of the resource you entered or you entered wrong name. The resource you enetered was: %s", net.Resource))
+		return NetworkResponse{}, cloudyerror.Newf(cloudyerror.Unsupported, "Either we are not supporting updation of the resource %s or you entered wrong name", net.Resource)
 	}
 }
@@ -2,11 +2,14 @@ package aws
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
+	awssdk "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
 )
 
 // NetworkComponentInput  will implement the methods which deals with the creation/deletion of network components under cloud/operations.
@@ -34,6 +37,8 @@ type NetworkComponentInput struct {
 	// DestinationCidr is the CIDR block which has to opened for routetable.
 	DestinationCidr string `json:"destinationcidr"`
 	GetRaw          bool   `json:"getraw"`
+	// rollback if set, records the components created so that they can be removed on failure.
+	rollback *rollback
 }
 
 // NetworkComponentResponse will be the response type of almost all the network components related activities under cloud/operations.
@@ -43,7 +48,9 @@ type NetworkComponentResponse struct {
 	// SecGroupIds are the IDs of the security groups that was created/updated/retrieved.
 	SecGroupIds []string `json:"SecGroupIds,omitempty"`
 	// RouteTableIds are the IDs of the route table that was created/updated/retrieved.
-	RouteTableIds     []string                            `json:"RouteTableIds,omitempty"`
+	RouteTableIds []string `json:"RouteTableIds,omitempty"`
+	// SecurityGroups holds the details of the security groups that was created/updated/retrieved.
+	SecurityGroups    []SecurityGroupResponse             `json:"SecurityGroups,omitempty"`
 	CreateIgwRaw      *ec2.CreateInternetGatewayOutput    `json:"CreateIgwRaw,omitempty"`
 	GetIgwRaw         *ec2.DescribeInternetGatewaysOutput `json:"GetIgwRaw,omitempty"`
 	CreateSecurityRaw *ec2.CreateSecurityGroupOutput      `json:"CreateSecRaw,omitempty"`
@@ -51,6 +58,23 @@ type NetworkComponentResponse struct {
 	GetSecurityRaw    *ec2.DescribeSecurityGroupsOutput   `json:"DescribeSecurityRaw,omitempty"`
 }
 
+// SecurityGroupResponse holds the details of the security group, along with the ports opened on it.
+type SecurityGroupResponse struct {
+	// Id of the security group.
+	Id string `json:"Id,omitempty"`
+	// Name of the security group.
+	Name string `json:"Name,omitempty"`
+	// VpcId is the ID of the network the security group is part of.
+	VpcId string `json:"VpcId,omitempty"`
+	// Description of the security group.
+	Description string `json:"Description,omitempty"`
+	// Ports are the ports opened for the incoming traffic ex: 22, 8000-8080 for TCP and udp:53 for the rest, all is the rule opening every port.
+	Ports []string `json:"Ports,omitempty"`
+	// Rules are the ingress and egress rules of the security group, one per peer. These are filled only by the methods managing the security groups.
+	Rules            []SecurityGroupRule `json:"Rules,omitempty"`
+	SecurityGroupRaw *ec2.SecurityGroup  `json:"SecurityGroupRaw,omitempty"`
+}
+
 // CreateIgw is customized internet-gateway creation, if one needs plain internet-gateway creation he/she has call interface the GOD which talks to cloud.
 func (net *NetworkComponentInput) CreateIgw(con aws.EstablishConnectionInput) (NetworkComponentResponse, error) {
 
@@ -62,6 +86,11 @@ func (net *NetworkComponentInput) CreateIgw(con aws.EstablishConnectionInput) (N
 	if igerr != nil {
 		return NetworkComponentResponse{}, igerr
 	}
+	igwid := *ig.InternetGateway.InternetGatewayId
+	net.rollback.record("internet gateway", igwid, func(con aws.EstablishConnectionInput) error {
+		deletegateway := NetworkComponentInput{IgwIds: []string{igwid}}
+		return deletegateway.DeleteIgws(con)
+	})
 
 	if net.VpcIds != nil {
 		aterr := ec2.AttachIgw(
@@ -73,6 +102,11 @@ func (net *NetworkComponentInput) CreateIgw(con aws.EstablishConnectionInput) (N
 		if aterr != nil {
 			return NetworkComponentResponse{}, aterr
 		}
+		vpcids := net.VpcIds
+		net.rollback.record("internet gateway attachment", igwid, func(con aws.EstablishConnectionInput) error {
+			dettachgateway := NetworkComponentInput{IgwIds: []string{igwid}, VpcIds: vpcids}
+			return dettachgateway.DetachIgws(con)
+		})
 	}
 
 	igtags := new(Tag)
@@ -137,6 +171,11 @@ func (net *NetworkComponentInput) CreateSecurityGroup(con aws.EstablishConnectio
 	if secerr != nil {
 		return NetworkComponentResponse{}, secerr
 	}
+	secid := *security.GroupId
+	net.rollback.record("security group", secid, func(con aws.EstablishConnectionInput) error {
+		delsecin := NetworkComponentInput{SecGroupIds: []string{secid}}
+		return delsecin.DeleteSecutiryGroup(con)
+	})
 
 	sctags := new(Tag)
 	sctags.Resource = *security.GroupId
@@ -160,19 +199,26 @@ func (net *NetworkComponentInput) CreateSecurityGroup(con aws.EstablishConnectio
 			return NetworkComponentResponse{}, ingreserr
 		}
 	}
+	// aws opens all the outgoing traffic on the groups it creates, the rule is created only if it is missing.
 	egreserr := ec2.CreateEgressRule(
 		&aws.IngressEgressInput{
 			SecId: *security.GroupId,
 		},
 	)
-	if egreserr != nil {
+	if egreserr != nil && !cloudyerror.IsConflict(cloudyerror.FromAWS(egreserr, "securitygroup", *security.GroupId)) {
 		return NetworkComponentResponse{}, egreserr
 	}
 
 	if net.GetRaw == true {
 		return NetworkComponentResponse{CreateSecurityRaw: security}, nil
 	}
-	return NetworkComponentResponse{SecGroupIds: []string{*security.GroupId}}, nil
+	ports := make([]string, 0, len(net.Ports))
+	for _, port := range net.Ports {
+		intport, _ := strconv.ParseInt(port, 10, 64)
+		ports = append(ports, strconv.FormatInt(intport, 10))
+	}
+	sort.Strings(ports)
+	return NetworkComponentResponse{SecGroupIds: []string{*security.GroupId}, SecurityGroups: []SecurityGroupResponse{{Id: *security.GroupId, Name: net.Name + "_sec", Ports: ports}}}, nil
 }
 
 // GetSecFromVpc will help one in fetching security-group from the VPC which they specify.
@@ -197,10 +243,34 @@ func (net *NetworkComponentInput) GetSecFromVpc(con aws.EstablishConnectionInput
 	if net.GetRaw == true {
 		return NetworkComponentResponse{GetSecurityRaw: response}, nil
 	}
+	groups := make([]SecurityGroupResponse, 0)
 	for _, sec := range response.SecurityGroups {
 		secids = append(secids, *sec.GroupId)
+		groups = append(groups, SecurityGroupResponse{Id: *sec.GroupId, Name: awssdk.StringValue(sec.GroupName), Ports: SecurityGroupPorts(sec.IpPermissions)})
+	}
+	return NetworkComponentResponse{SecGroupIds: secids, SecurityGroups: groups}, nil
+}
+
+// SecurityGroupPorts returns the ports opened by the ingress rules passed in the form of SecurityGroupResponse.Ports, sorted.
+func SecurityGroupPorts(permissions []*ec2.IpPermission) []string {
+	ports := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		protocol := strings.ToLower(awssdk.StringValue(permission.IpProtocol))
+		if protocol == "-1" {
+			ports = append(ports, "all")
+			continue
+		}
+		port := strconv.FormatInt(awssdk.Int64Value(permission.FromPort), 10)
+		if awssdk.Int64Value(permission.ToPort) != awssdk.Int64Value(permission.FromPort) {
+			port += "-" + strconv.FormatInt(awssdk.Int64Value(permission.ToPort), 10)
+		}
+		if protocol != "tcp" && protocol != "6" {
+			port = protocol + ":" + port
+		}
+		ports = append(ports, port)
 	}
-	return NetworkComponentResponse{SecGroupIds: secids}, nil
+	sort.Strings(ports)
+	return ports
 }
 
 // DeleteSecutiryGroup will help one in deleting security-group which they specify, for deletion of other resources refer other methods.
@@ -237,6 +307,11 @@ func (net *NetworkComponentInput) CreateRouteTable(con aws.EstablishConnectionIn
 	if routetableerr != nil {
 		return routetableerr
 	}
+	routeid := *routetable.RouteTable.RouteTableId
+	net.rollback.record("route table", routeid, func(con aws.EstablishConnectionInput) error {
+		route := NetworkComponentInput{RouteTableIds: []string{routeid}}
+		return route.DeleteRouteTable(con)
+	})
 
 	if net.IgwId != "" {
 		if strings.ToLower(net.SubType) == "public" {
@@ -260,6 +335,7 @@ func (net *NetworkComponentInput) CreateRouteTable(con aws.EstablishConnectionIn
 			if routeattacherr != nil {
 				return routeattacherr
 			}
+			net.recordRouteTableAssociation(routeid)
 
 			return nil
 		} else {
@@ -296,6 +372,7 @@ func (net *NetworkComponentInput) CreateRouteTable(con aws.EstablishConnectionIn
 					if routeattacherr != nil {
 						return routeattacherr
 					}
+					net.recordRouteTableAssociation(routeid)
 
 					return nil
 				}
@@ -309,6 +386,21 @@ func (net *NetworkComponentInput) CreateRouteTable(con aws.EstablishConnectionIn
 	}
 }
 
+// recordRouteTableAssociation records the disassociation of the route-table passed, from the subnet it was attached to.
+func (net *NetworkComponentInput) recordRouteTableAssociation(routeid string) {
+	net.rollback.record("route table association", routeid, func(con aws.EstablishConnectionInput) error {
+		route := NetworkComponentInput{RouteTableIds: []string{routeid}}
+		dessroutetable, dessrouterr := route.DisassociateRouteTable(con)
+		if dessrouterr != nil {
+			return dessrouterr
+		}
+		if dessroutetable != true {
+			return fmt.Errorf("An error occurred while dettaching routetable from subnet")
+		}
+		return nil
+	})
+}
+
 // DisassociateRouteTable will help one in disassociating the route-table which you specify, from the subnet to which it is attached, for disassociating other resources refer other methods.
 func (net *NetworkComponentInput) DisassociateRouteTable(con aws.EstablishConnectionInput) (bool, error) {
 
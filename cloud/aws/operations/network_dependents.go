@@ -0,0 +1,297 @@
+package aws
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+)
+
+// deleteNetworkDependents deletes the resources which lives in the network but are not part of it, as aws refuses to delete the network holding them.
+// The elastic ips are collected while deleting the resources they are associated with and are released at last,
+// as aws disassociates them from the servers and nat gateways once they are gone.
+func (d *DeleteNetworkInput) deleteNetworkDependents(con aws.EstablishConnectionInput) ([]DeletedResource, error) {
+
+	// the loadbalancers are looked up with the clients of both kind of loadbalancers, along with ec2.
+	con.Resource = "elb12"
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return nil, seserr
+	}
+
+	deleted := make([]DeletedResource, 0)
+	for _, vpc := range d.VpcIds {
+		addresses, servdelerr := deleteServersOfVpc(con, sess, vpc, &deleted)
+		if servdelerr != nil {
+			return deleted, servdelerr
+		}
+		if lbdelerr := deleteLoadbalancersOfVpc(sess, vpc, &deleted); lbdelerr != nil {
+			return deleted, lbdelerr
+		}
+
+		nataddresses, natdelerr := deleteNatGatewaysOfVpc(sess, vpc, &deleted)
+		if natdelerr != nil {
+			return deleted, natdelerr
+		}
+		addresses = append(addresses, nataddresses...)
+
+		eniaddresses, enidelerr := deleteInterfacesOfVpc(sess, vpc, &deleted)
+		if enidelerr != nil {
+			return deleted, enidelerr
+		}
+		addresses = append(addresses, eniaddresses...)
+
+		if relerr := releaseAddresses(sess, addresses, &deleted); relerr != nil {
+			return deleted, relerr
+		}
+	}
+	return deleted, nil
+}
+
+// deleteServersOfVpc terminates the servers of the network and returns the elastic ips which were associated with them.
+// The servers protected from termination are unprotected first, as their termination is forced along with the network.
+func deleteServersOfVpc(con aws.EstablishConnectionInput, sess aws.EstablishedSession, vpc string, deleted *[]DeletedResource) ([]string, error) {
+
+	servers, serverr := serversOfVpc(sess, vpc)
+	if serverr != nil {
+		return nil, serverr
+	}
+	if len(servers) == 0 {
+		return nil, nil
+	}
+
+	addresses, adderr := sess.DescribeAddresses(&aws.AddressInput{Filters: aws.Filters{Name: "instance-id", Value: servers}})
+	if adderr != nil {
+		return nil, adderr
+	}
+	allocations := make([]string, 0)
+	for _, address := range addresses.Addresses {
+		allocations = append(allocations, *address.AllocationId)
+	}
+
+	if unprotecterr := unprotectServers(sess, servers); unprotecterr != nil {
+		return nil, unprotecterr
+	}
+
+	serverin := DeleteServerInput{VpcId: vpc}
+	terminated, termerr := serverin.DeleteServerFromVpc(con)
+	if termerr != nil {
+		return nil, termerr
+	}
+	for _, server := range terminated {
+		*deleted = logDeleted(*deleted, "server", server.CurrentState, server.InstanceId)
+	}
+	return allocations, nil
+}
+
+// unprotectServers removes the protection from termination of the servers passed, the ones which are not protected are left as is.
+func unprotectServers(sess aws.EstablishedSession, servers []string) error {
+
+	protected, proterr := protectedServers(sess, servers)
+	if proterr != nil {
+		return proterr
+	}
+	for _, server := range protected {
+		if moderr := sess.ModifyInstanceAttribute(&aws.InstanceAttributeInput{InstanceId: server, DisableApiTermination: awssdk.Bool(false)}); moderr != nil {
+			return moderr
+		}
+	}
+	return nil
+}
+
+// protectedServers returns the servers among the ones passed which are protected from termination.
+func protectedServers(sess aws.EstablishedSession, servers []string) ([]string, error) {
+
+	protected := make([]string, 0)
+	for _, server := range servers {
+		protection, descerr := sess.DescribeInstanceAttribute(&aws.InstanceAttributeInput{InstanceId: server, Attribute: ec2.InstanceAttributeNameDisableApiTermination})
+		if descerr != nil {
+			return nil, descerr
+		}
+		if (protection.DisableApiTermination != nil) && awssdk.BoolValue(protection.DisableApiTermination.Value) {
+			protected = append(protected, server)
+		}
+	}
+	return protected, nil
+}
+
+// deleteLoadbalancersOfVpc deletes the classic and application loadbalancers of the network, followed by the target groups of the network.
+func deleteLoadbalancersOfVpc(sess aws.EstablishedSession, vpc string, deleted *[]DeletedResource) error {
+
+	classic, classicerr := sess.DescribeAllClassicLoadbalancer(&aws.DescribeLoadbalancersInput{})
+	if classicerr != nil {
+		return classicerr
+	}
+	lbnames := make([]string, 0)
+	for _, lb := range classic.LoadBalancerDescriptions {
+		if awssdk.StringValue(lb.VPCId) != vpc {
+			continue
+		}
+		if delerr := sess.DeleteClassicLoadbalancer(&aws.DeleteLoadbalancerInput{LbName: *lb.LoadBalancerName}); delerr != nil {
+			return delerr
+		}
+		lbnames = append(lbnames, *lb.LoadBalancerName)
+	}
+	if len(lbnames) != 0 {
+		//waiting till the network interfaces of the loadbalancers are released, the subnets are held by aws until then.
+		if _, waiterr := sess.WaitUntilClassicLbInterfacesDeleted(&aws.DescribeLoadbalancersInput{LbNames: lbnames}); waiterr != nil {
+			return waiterr
+		}
+		*deleted = logDeleted(*deleted, "loadbalancer", "deleted", lbnames...)
+	}
+
+	application, apperr := sess.DescribeAllApplicationLoadbalancer(&aws.DescribeLoadbalancersInput{})
+	if apperr != nil {
+		return apperr
+	}
+	lbarns := make([]string, 0)
+	for _, lb := range application.LoadBalancers {
+		if awssdk.StringValue(lb.VpcId) != vpc {
+			continue
+		}
+		if delerr := sess.DeleteAppLoadbalancer(&aws.DeleteLoadbalancerInput{LbArn: *lb.LoadBalancerArn}); delerr != nil {
+			return delerr
+		}
+		lbarns = append(lbarns, *lb.LoadBalancerArn)
+	}
+	if len(lbarns) != 0 {
+		//waiting till the loadbalancers gets deleted, the target groups are held by aws until then.
+		if waiterr := sess.WaitTillLbDeletionSuccessfull(&aws.DescribeLoadbalancersInput{LbArns: lbarns}); waiterr != nil {
+			return waiterr
+		}
+		//waiting till the network interfaces of the loadbalancers are released as well, the subnets and security groups are held by aws until then.
+		if _, waiterr := sess.WaitUntilAppLbInterfacesDeleted(&aws.DescribeLoadbalancersInput{LbArns: lbarns}); waiterr != nil {
+			return waiterr
+		}
+		*deleted = logDeleted(*deleted, "loadbalancer", "deleted", lbarns...)
+	}
+
+	groups, grouperr := sess.DescribeAllTargetgroups(&aws.DescribeLoadbalancersInput{})
+	if grouperr != nil {
+		return grouperr
+	}
+	for _, group := range groups.TargetGroups {
+		if awssdk.StringValue(group.VpcId) != vpc {
+			continue
+		}
+		if tarerr := deleteTargetGroup(sess, &aws.DeleteLoadbalancerInput{TargetArn: *group.TargetGroupArn}); tarerr != nil {
+			return tarerr
+		}
+		*deleted = logDeleted(*deleted, "targetgroup", "deleted", *group.TargetGroupArn)
+	}
+	return nil
+}
+
+// deleteNatGatewaysOfVpc deletes the nat gateways of the network and returns the elastic ips of them.
+func deleteNatGatewaysOfVpc(sess aws.EstablishedSession, vpc string, deleted *[]DeletedResource) ([]string, error) {
+
+	nats, naterr := sess.DescribeNatGateways(&aws.DescribeNetworkInput{Filters: aws.Filters{Name: "vpc-id", Value: []string{vpc}}})
+	if naterr != nil {
+		return nil, naterr
+	}
+
+	natids, allocations := make([]string, 0), make([]string, 0)
+	for _, nat := range nats.NatGateways {
+		switch awssdk.StringValue(nat.State) {
+		case ec2.NatGatewayStateDeleted:
+			continue
+		case ec2.NatGatewayStateDeleting:
+		default:
+			if delerr := sess.DeleteNatGateway(&aws.DescribeNetworkInput{NatGatewayIds: []string{*nat.NatGatewayId}}); delerr != nil {
+				return nil, delerr
+			}
+		}
+		natids = append(natids, *nat.NatGatewayId)
+		for _, address := range nat.NatGatewayAddresses {
+			if address.AllocationId != nil {
+				allocations = append(allocations, *address.AllocationId)
+			}
+		}
+	}
+	if len(natids) == 0 {
+		return nil, nil
+	}
+
+	//waiting till the nat gateways gets deleted, as its network interfaces are held until then.
+	if _, waiterr := sess.WaitUntilNatGatewayDeleted(&aws.DescribeNetworkInput{NatGatewayIds: natids}); waiterr != nil {
+		return nil, waiterr
+	}
+	*deleted = logDeleted(*deleted, "natgateway", "deleted", natids...)
+	return allocations, nil
+}
+
+// deleteInterfacesOfVpc deletes the network interfaces of the network which are not in use and returns the elastic ips which were associated with them.
+// The interfaces in use are the ones managed by aws (ex: of loadbalancers), which are deleted by aws along with the resource managing them.
+func deleteInterfacesOfVpc(sess aws.EstablishedSession, vpc string, deleted *[]DeletedResource) ([]string, error) {
+
+	interfaces, enierr := sess.DescribeNetworkInterfaces(&aws.DescribeNetworkInput{Filters: aws.Filters{Name: "vpc-id", Value: []string{vpc}}})
+	if enierr != nil {
+		return nil, enierr
+	}
+
+	allocations := make([]string, 0)
+	for _, eni := range interfaces.NetworkInterfaces {
+		if awssdk.StringValue(eni.Status) != ec2.NetworkInterfaceStatusAvailable {
+			continue
+		}
+		if eni.Association != nil && eni.Association.AllocationId != nil {
+			allocations = append(allocations, *eni.Association.AllocationId)
+		}
+		if delerr := sess.DeleteNetworkInterface(&aws.DescribeNetworkInput{NetworkInterfaceIds: []string{*eni.NetworkInterfaceId}}); delerr != nil {
+			return nil, delerr
+		}
+		*deleted = logDeleted(*deleted, "networkinterface", "deleted", *eni.NetworkInterfaceId)
+	}
+	return allocations, nil
+}
+
+// releaseAddresses releases the elastic ips passed, the ones which are still associated are disassociated before releasing them.
+func releaseAddresses(sess aws.EstablishedSession, allocations []string, deleted *[]DeletedResource) error {
+
+	if len(allocations) == 0 {
+		return nil
+	}
+	addresses, adderr := sess.DescribeAddresses(&aws.AddressInput{AllocationIds: allocations})
+	if adderr != nil {
+		return adderr
+	}
+	for _, address := range addresses.Addresses {
+		if address.AssociationId != nil {
+			if disserr := sess.DisassociateAddress(&aws.AddressInput{AssociationId: *address.AssociationId}); disserr != nil {
+				return disserr
+			}
+		}
+		if relerr := sess.ReleaseAddress(&aws.AddressInput{AllocationIds: []string{*address.AllocationId}}); relerr != nil {
+			return relerr
+		}
+		*deleted = logDeleted(*deleted, "elasticip", "released", *address.AllocationId)
+	}
+	return nil
+}
+
+// serversOfVpc returns the IDs of the servers of the network which are not yet terminated, aws retains the terminated servers for a while.
+func serversOfVpc(sess aws.EstablishedSession, vpc string) ([]string, error) {
+
+	result, err := sess.DescribeInstance(&aws.DescribeComputeInput{Filters: aws.Filters{Name: "vpc-id", Value: []string{vpc}}})
+	if err != nil {
+		return nil, err
+	}
+	servers := make([]string, 0)
+	for _, reservation := range result.Reservations {
+		for _, instance := range reservation.Instances {
+			switch awssdk.StringValue(instance.State.Name) {
+			case ec2.InstanceStateNameTerminated, ec2.InstanceStateNameShuttingDown:
+			default:
+				servers = append(servers, *instance.InstanceId)
+			}
+		}
+	}
+	return servers, nil
+}
+
+// logDeleted appends the resources passed to the log of the deletion.
+func logDeleted(deleted []DeletedResource, kind, status string, ids ...string) []DeletedResource {
+	for _, id := range ids {
+		deleted = append(deleted, DeletedResource{Kind: kind, Id: id, Status: status})
+	}
+	return deleted
+}
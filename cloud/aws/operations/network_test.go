@@ -0,0 +1,343 @@
+package aws
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	awsfake "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/fake"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+func TestCreateNetwork(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	if network.VpcId == "" || network.IgwId == "" {
+		t.Fatalf("expected the network to be created with an internet gateway, got %+v", network)
+	}
+	if len(network.Subnets) != 2 {
+		t.Fatalf("expected 2 subnets, got %d", len(network.Subnets))
+	}
+	if len(network.SecGroupIds) == 0 {
+		t.Fatalf("expected the security group of the network to be created")
+	}
+
+	get := GetNetworksInput{VpcIds: []string{network.VpcId}}
+	networks, err := get.GetNetwork(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("fetching network: %v", err)
+	}
+	if len(networks) != 1 {
+		t.Fatalf("expected 1 network, got %d", len(networks))
+	}
+	got := networks[0]
+	if got.Name != "neuron" || got.IgwId != network.IgwId || len(got.Subnets) != 2 {
+		t.Errorf("network fetched does not match the one created, got %+v", got)
+	}
+	for i, subnet := range got.Subnets {
+		if name := "neuron_sub" + strconv.Itoa(i); subnet.Name != name {
+			t.Errorf("expected subnet %s to be named %s, got %s", subnet.Id, name, subnet.Name)
+		}
+	}
+}
+
+func TestCreateNetworkInvalidSubnetCidr(t *testing.T) {
+	cloud := awsfake.New()
+	network := NetworkCreateInput{
+		Name:     "neuron",
+		VpcCidr:  "10.0.0.0/16",
+		SubCidrs: []string{"192.168.1.0/24"},
+	}
+	_, err := network.CreateNetwork(fakeConnection(cloud, "ec2"))
+	if !cloudyerror.IsInvalidInput(err) {
+		t.Fatalf("expected invalid input, got %v", err)
+	}
+	// the blocks of the subnets are validated before creating anything.
+	if calls := cloud.Calls("CreateVpc"); calls != 0 {
+		t.Errorf("expected no network to be created, got %d calls", calls)
+	}
+}
+
+func TestCreateNetworkRollback(t *testing.T) {
+	cloud := awsfake.New()
+	network := NetworkCreateInput{
+		Name:     "neuron",
+		VpcCidr:  "10.0.0.0/16",
+		SubCidrs: []string{"10.0.1.0/24", "10.0.2.0/24", "10.0.3.0/24"},
+		Type:     "public",
+		Ports:    []string{"22"},
+	}
+	// the third subnet fails.
+	cloud.FailNext("CreateSubnet", nil)
+	cloud.FailNext("CreateSubnet", nil)
+	cloud.FailNext("CreateSubnet", awserr.New("InvalidSubnet.Range", "The CIDR '10.0.3.0/24' is invalid.", nil))
+	response, err := network.CreateNetwork(fakeConnection(cloud, "ec2"))
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "InvalidSubnet.Range" {
+		t.Fatalf("expected InvalidSubnet.Range, got %v", err)
+	}
+	if response.Rollback == nil || len(response.Rollback.Leftovers) != 0 {
+		t.Fatalf("expected the network to be rolled back completely, got %+v", response.Rollback)
+	}
+
+	// 2 subnets with their route tables and associations, security group, internet gateway with its attachment and the network.
+	rolledback := response.Rollback.RolledBack
+	if len(rolledback) != 10 {
+		t.Fatalf("expected 10 components to be rolled back, got %v", rolledback)
+	}
+	if !strings.HasPrefix(rolledback[0], "route table association ") || !strings.HasPrefix(rolledback[len(rolledback)-1], "network ") {
+		t.Errorf("expected components to be rolled back in the reverse order of creation, got %v", rolledback)
+	}
+
+	get := GetNetworksInput{}
+	networks, err := get.GetAllNetworks(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("fetching networks: %v", err)
+	}
+	if len(networks) != 0 {
+		t.Fatalf("expected no network to be left behind, got %+v", networks)
+	}
+}
+
+func TestCreateNetworkRollbackLeftovers(t *testing.T) {
+	cloud := awsfake.New()
+	cloud.FailNext("CreateSubnet", awserr.New("SubnetLimitExceeded", "The maximum number of subnets has been reached.", nil))
+	cloud.FailNext("DeleteVpc", awserr.New("UnauthorizedOperation", "You are not authorized to perform this operation.", nil))
+
+	network := NetworkCreateInput{Name: "neuron", VpcCidr: "10.0.0.0/16", SubCidrs: []string{"10.0.1.0/24"}}
+	response, err := network.CreateNetwork(fakeConnection(cloud, "ec2"))
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "SubnetLimitExceeded" {
+		t.Fatalf("expected the error of the failure to be returned, got %v", err)
+	}
+	if response.Rollback == nil || len(response.Rollback.RolledBack) != 3 {
+		t.Fatalf("expected security group and internet gateway to be rolled back, got %+v", response.Rollback)
+	}
+	if leftovers := response.Rollback.Leftovers; len(leftovers) != 1 || !strings.HasPrefix(leftovers[0], "network vpc-") {
+		t.Fatalf("expected the network to be left behind, got %v", leftovers)
+	}
+}
+
+func TestDeleteNetwork(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	del := DeleteNetworkInput{VpcIds: []string{network.VpcId}}
+	response, err := del.DeleteNetwork(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("deleting network: %v", err)
+	}
+	if response.Status == "" {
+		t.Errorf("expected the status of deletion, got %+v", response)
+	}
+
+	get := GetNetworksInput{VpcIds: []string{network.VpcId}}
+	_, err = get.FindVpcs(fakeConnection(cloud, "ec2"))
+	if !cloudyerror.IsNotFound(cloudyerror.FromAWS(err, "network", network.VpcId)) {
+		t.Fatalf("expected the network to be deleted, got %v", err)
+	}
+}
+
+func TestDeleteNetworkWithServers(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	server := CreateServerInput{InstanceName: "neuron", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id}
+	if _, err := server.CreateServer(fakeConnection(cloud, "ec2")); err != nil {
+		t.Fatalf("creating server: %v", err)
+	}
+
+	del := DeleteNetworkInput{VpcIds: []string{network.VpcId}}
+	_, err := del.DeleteNetwork(fakeConnection(cloud, "ec2"))
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "DependencyViolation" {
+		t.Fatalf("expected DependencyViolation while servers are running in network, got %v", err)
+	}
+}
+
+func TestDeleteNetworkForce(t *testing.T) {
+	cloud := awsfake.New()
+	ctx := context.Background()
+	ec2api := cloud.EC2(testRegion)
+	network := createTestNetwork(t, cloud)
+
+	server := CreateServerInput{InstanceName: "neuron", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id, AssignPubIp: true}
+	servers, err := server.CreateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating server: %v", err)
+	}
+	// the termination of the server protected is forced along with the network.
+	protect := UpdateServerInput{InstanceIds: []string{servers[0].InstanceId}, Action: "protect"}
+	if _, err := protect.UpdateServer(fakeConnection(cloud, "ec2")); err != nil {
+		t.Fatalf("protecting server: %v", err)
+	}
+	for _, lb := range []LoadBalanceCreateInput{
+		{Name: "neuron-classic", VpcId: network.VpcId, Type: "classic", LbPort: 80, Lbproto: "HTTP", InstPort: 8080, Instproto: "HTTP"},
+		{Name: "neuron-app", VpcId: network.VpcId, Type: "application", LbPort: 80, Lbproto: "HTTP", InstPort: 8080, Instproto: "HTTP", HttpCode: "200", HealthPath: "/"},
+	} {
+		if _, err := lb.CreateLoadBalancer(fakeConnection(cloud, "elb12")); err != nil {
+			t.Fatalf("creating loadbalancer %s: %v", lb.Name, err)
+		}
+	}
+
+	// the elastic ips of the server, nat gateway and network interface are expected to be released.
+	allocate := func() string {
+		address, err := ec2api.AllocateAddressWithContext(ctx, &ec2.AllocateAddressInput{Domain: awssdk.String("vpc")})
+		if err != nil {
+			t.Fatalf("allocating address: %v", err)
+		}
+		return *address.AllocationId
+	}
+	if _, err := ec2api.AssociateAddressWithContext(ctx, &ec2.AssociateAddressInput{AllocationId: awssdk.String(allocate()), InstanceId: awssdk.String(servers[0].InstanceId)}); err != nil {
+		t.Fatalf("associating address with server: %v", err)
+	}
+	nat, err := ec2api.CreateNatGatewayWithContext(ctx, &ec2.CreateNatGatewayInput{AllocationId: awssdk.String(allocate()), SubnetId: awssdk.String(network.Subnets[0].Id)})
+	if err != nil {
+		t.Fatalf("creating nat gateway: %v", err)
+	}
+	eni, err := ec2api.CreateNetworkInterfaceWithContext(ctx, &ec2.CreateNetworkInterfaceInput{SubnetId: awssdk.String(network.Subnets[1].Id)})
+	if err != nil {
+		t.Fatalf("creating network interface: %v", err)
+	}
+	if _, err := ec2api.AssociateAddressWithContext(ctx, &ec2.AssociateAddressInput{AllocationId: awssdk.String(allocate()), NetworkInterfaceId: eni.NetworkInterface.NetworkInterfaceId}); err != nil {
+		t.Fatalf("associating address with network interface: %v", err)
+	}
+
+	del := DeleteNetworkInput{VpcIds: []string{network.VpcId}, Force: true}
+	response, err := del.DeleteNetwork(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("force deleting network: %v", err)
+	}
+
+	kinds := make(map[string]int)
+	for _, deleted := range response.Deleted {
+		kinds[deleted.Kind]++
+	}
+	expected := map[string]int{"server": 1, "loadbalancer": 2, "targetgroup": 1, "natgateway": 1, "networkinterface": 1, "elasticip": 3, "network": 1}
+	for kind, count := range expected {
+		if kinds[kind] != count {
+			t.Errorf("expected %d %s to be deleted, got %d in %+v", count, kind, kinds[kind], response.Deleted)
+		}
+	}
+	if last := response.Deleted[len(response.Deleted)-1]; last.Kind != "network" || last.Id != network.VpcId {
+		t.Errorf("expected the network to be deleted at last, got %+v", last)
+	}
+	if response.Deleted[0].Kind != "server" || response.Deleted[0].Status != "terminated" {
+		t.Errorf("expected the server to be terminated first, got %+v", response.Deleted[0])
+	}
+
+	addresses, err := ec2api.DescribeAddressesWithContext(ctx, &ec2.DescribeAddressesInput{})
+	if err != nil || len(addresses.Addresses) != 0 {
+		t.Errorf("expected all the addresses to be released, got %v (%v)", addresses, err)
+	}
+	nats, err := ec2api.DescribeNatGatewaysWithContext(ctx, &ec2.DescribeNatGatewaysInput{NatGatewayIds: []*string{nat.NatGateway.NatGatewayId}})
+	if err != nil || *nats.NatGateways[0].State != ec2.NatGatewayStateDeleted {
+		t.Errorf("expected the nat gateway to be deleted, got %v (%v)", nats, err)
+	}
+}
+
+func TestDeleteNetworkForcePlan(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	server := CreateServerInput{InstanceName: "neuron", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id}
+	servers, err := server.CreateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating server: %v", err)
+	}
+	protect := UpdateServerInput{InstanceIds: []string{servers[0].InstanceId}, Action: "protect"}
+	if _, err := protect.UpdateServer(fakeConnection(cloud, "ec2")); err != nil {
+		t.Fatalf("protecting server: %v", err)
+	}
+
+	del := DeleteNetworkInput{VpcIds: []string{network.VpcId}, Force: true}
+	plan, err := del.PlanDeleteNetwork(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("planning force deletion of network: %v", err)
+	}
+	if first := plan.Actions[0]; first.Operation != "ModifyInstanceAttribute" || first.Target != servers[0].InstanceId || !first.Verified {
+		t.Errorf("expected the protection of server to be removed first, got %+v", first)
+	}
+	if second := plan.Actions[1]; second.Operation != "TerminateInstances" || second.Target != servers[0].InstanceId || !second.Verified {
+		t.Errorf("expected the termination of server to be planned next, got %+v", second)
+	}
+
+	// nothing is deleted while planning.
+	instances, err := cloud.EC2(testRegion).DescribeInstancesWithContext(context.Background(), &ec2.DescribeInstancesInput{InstanceIds: []*string{awssdk.String(servers[0].InstanceId)}})
+	if err != nil || *instances.Reservations[0].Instances[0].State.Name != ec2.InstanceStateNameRunning {
+		t.Errorf("expected the server to be left running while planning, got %v (%v)", instances, err)
+	}
+}
+
+func TestDeleteNetworkNotFound(t *testing.T) {
+	cloud := awsfake.New()
+	del := DeleteNetworkInput{VpcIds: []string{"vpc-00000000000000099"}}
+	_, err := del.DeleteNetwork(fakeConnection(cloud, "ec2"))
+	if !cloudyerror.IsNotFound(cloudyerror.FromAWS(err, "network", "vpc-00000000000000099")) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestCreateNetworkSubnetLayout(t *testing.T) {
+	cloud := awsfake.New()
+	network := NetworkCreateInput{
+		Name:    "neuron",
+		VpcCidr: "10.0.0.0/16",
+		Type:    "public",
+		Layout:  SubnetLayout{PublicPerZone: 1, PrivatePerZone: 1, Zones: 2, PrefixLength: 24},
+	}
+	plan, err := network.PlanCreateNetwork(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("planning network: %v", err)
+	}
+	planned := make([]string, 0)
+	for _, action := range plan.Actions {
+		if action.Operation == "CreateSubnet" {
+			planned = append(planned, action.Details["cidr"])
+		}
+	}
+
+	response, err := network.CreateNetwork(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating network: %v", err)
+	}
+	created := make([]string, 0)
+	for _, subnet := range response.Subnets {
+		created = append(created, subnet.Cidr)
+	}
+	expected := []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24", "10.0.3.0/24"}
+	if !reflect.DeepEqual(created, expected) || !reflect.DeepEqual(planned, expected) {
+		t.Fatalf("expected the subnets %v to be planned and created, got %v and %v", expected, planned, created)
+	}
+	// only the public subnet of each zone is routed to the internet gateway.
+	if routes := defaultRoutes(t, cloud, response.VpcId); len(routes) != 2 {
+		t.Errorf("expected the 2 public subnets to be routed to the internet gateway, got %v", routes)
+	}
+}
+
+func TestCreateNetworkSubnetLayoutInvalid(t *testing.T) {
+	cloud := awsfake.New()
+	invalid := []NetworkCreateInput{
+		{Layout: SubnetLayout{Count: 300, PrefixLength: 24}},
+		{Layout: SubnetLayout{Count: 2, PrefixLength: 24}, SubCidrs: []string{"10.0.1.0/24"}},
+		{Layout: SubnetLayout{Count: 2, PublicPerZone: 1, PrefixLength: 24}},
+		{Layout: SubnetLayout{PublicPerZone: 1, PrefixLength: 24}, Type: "private"},
+		{Layout: SubnetLayout{PrivatePerZone: 1, Zones: 100, PrefixLength: 24}},
+		{SubCidrs: []string{"10.0.1.0/24", "10.0.1.128/25"}},
+	}
+	for _, network := range invalid {
+		network.Name, network.VpcCidr = "neuron", "10.0.0.0/16"
+		if _, err := network.PlanCreateNetwork(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+			t.Errorf("expected invalid input while planning %+v, got %v", network, err)
+		}
+		if _, err := network.CreateNetwork(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+			t.Errorf("expected invalid input for %+v, got %v", network, err)
+		}
+	}
+	if calls := cloud.Calls("CreateVpc"); calls != 0 {
+		t.Errorf("expected no network to be created, got %d calls", calls)
+	}
+}
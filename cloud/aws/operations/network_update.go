@@ -0,0 +1,737 @@
+package aws
+
+import (
+	"fmt"
+	"strconv"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// The methods below carry out the updates of UpdateNetwork other than creation of subnets.
+// The work of each update is resolved ahead by describing the network, so that UpdateNetwork and PlanUpdateNetwork agree on it.
+
+// defaultRoute is the destination of the route which makes the subnetwork public, by routing its traffic to the internet gateway.
+const defaultRoute = "0.0.0.0/0"
+
+// subnetDeletion is the work of deleting the subnetworks selected in the network.
+type subnetDeletion struct {
+	subnets []SubnetReponse
+	// associations are the associations of the subnetworks with the route tables, which are removed before deleting the subnetworks.
+	associations []routeAssociation
+	// routeTables are the route tables which are left without associations once the subnetworks are disassociated, they are deleted along.
+	routeTables []string
+}
+
+// routeAssociation is the association of the route table with the subnetwork.
+type routeAssociation struct {
+	routeTable  string
+	association string
+	subnet      string
+}
+
+// gatewayRoute is the route of the route table to the internet gateway.
+type gatewayRoute struct {
+	routeTable  string
+	destination string
+}
+
+// subnetRouting is the change in the routing of the subnetwork which makes it public.
+type subnetRouting struct {
+	subnet SubnetReponse
+	// routeTable is the route table associated with the subnetwork, it is empty if the subnetwork uses the main route table
+	// in which case a route table is created for the subnetwork.
+	routeTable string
+	// route is the change to the default route of the route table ex: create, replace. It is empty if the route already leads to the internet gateway.
+	route string
+	// gateway is the gateway the default route leads to before it is replaced, it is empty if the route leads elsewhere (ex: NAT gateway).
+	gateway string
+}
+
+// deleteSubnets deletes the subnetworks selected either by name or ID, they are disassociated from the route tables before deleting them.
+func (net *UpdateNetworkInput) deleteSubnets(con aws.EstablishConnectionInput) (NetworkResponse, error) {
+
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return NetworkResponse{}, seserr
+	}
+	deletion, delerr := net.resolveSubnetDeletion(sess)
+	if delerr != nil {
+		return NetworkResponse{}, delerr
+	}
+
+	for _, association := range deletion.associations {
+		deterr := sess.DettachRouteTable(&aws.DescribeNetworkInput{AssociationsId: association.association})
+		if deterr != nil {
+			return NetworkResponse{}, deterr
+		}
+	}
+	if len(deletion.routeTables) != 0 {
+		route := NetworkComponentInput{RouteTableIds: deletion.routeTables}
+		if routerr := route.DeleteRouteTable(con); routerr != nil {
+			return NetworkResponse{}, routerr
+		}
+	}
+
+	subnetids := make([]string, 0, len(deletion.subnets))
+	for _, subnet := range deletion.subnets {
+		subnetids = append(subnetids, subnet.Id)
+	}
+	subdelin := DeleteNetworkInput{SubnetIds: subnetids}
+	if subdelerr := subdelin.DeleteSubnets(con); subdelerr != nil {
+		return NetworkResponse{}, subdelerr
+	}
+	return NetworkResponse{VpcId: net.Network.VpcId, Subnets: deletion.subnets}, nil
+}
+
+// resolveSubnetDeletion finds the subnetworks of the network passed in Subnets along with their associations with the route tables.
+func (net *UpdateNetworkInput) resolveSubnetDeletion(sess aws.EstablishedSession) (subnetDeletion, error) {
+
+	if len(net.Subnets) == 0 {
+		return subnetDeletion{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the names or IDs of the subnets to be deleted")
+	}
+	result, suberr := sess.DescribeSubnet(&aws.DescribeNetworkInput{Filters: aws.Filters{Name: "vpc-id", Value: []string{net.Network.VpcId}}})
+	if suberr != nil {
+		return subnetDeletion{}, suberr
+	}
+
+	deletion := subnetDeletion{}
+	selected := make(map[string]bool)
+	for _, ref := range net.Subnets {
+		var found *ec2.Subnet
+		for _, subnet := range result.Subnets {
+			if (*subnet.SubnetId == ref) || (nameOf(subnet.Tags) == ref) {
+				found = subnet
+				break
+			}
+		}
+		if found == nil {
+			return subnetDeletion{}, &cloudyerror.Error{Code: cloudyerror.NotFound, Cloud: "aws", Kind: "subnet", Message: fmt.Sprintf("Could not find the subnet %s in the network %s", ref, net.Network.VpcId)}
+		}
+		if selected[*found.SubnetId] {
+			continue
+		}
+		selected[*found.SubnetId] = true
+		deletion.subnets = append(deletion.subnets, SubnetReponse{Name: nameOf(found.Tags), Id: *found.SubnetId, State: "deleted", VpcId: *found.VpcId, Cidr: *found.CidrBlock})
+	}
+
+	subnetids := make([]string, 0, len(selected))
+	for _, subnet := range deletion.subnets {
+		subnetids = append(subnetids, subnet.Id)
+	}
+	tables, routerr := sess.DescribeRouteTable(&aws.DescribeNetworkInput{Filters: aws.Filters{Name: "association.subnet-id", Value: subnetids}})
+	if routerr != nil {
+		return subnetDeletion{}, routerr
+	}
+	for _, table := range tables.RouteTables {
+		remaining := 0
+		for _, association := range table.Associations {
+			if (association.SubnetId != nil) && selected[*association.SubnetId] {
+				deletion.associations = append(deletion.associations, routeAssociation{routeTable: *table.RouteTableId, association: *association.RouteTableAssociationId, subnet: *association.SubnetId})
+				continue
+			}
+			remaining++
+		}
+		if remaining == 0 {
+			deletion.routeTables = append(deletion.routeTables, *table.RouteTableId)
+		}
+	}
+	return deletion, nil
+}
+
+// layoutNetworkSubnets lays out the subnets to be created in the network, in its blocks around the subnets present in it.
+// The subnets are created in the Zone passed, else across all the zones. It returns the subnets along with the number
+// from which the names of the subnets continue, so that they are named uniquely.
+func (net *UpdateNetworkInput) layoutNetworkSubnets(con aws.EstablishConnectionInput) ([]subnetSpec, int, error) {
+
+	zones := []string{net.Network.Zone}
+	if net.Network.Zone == "" {
+		zonein := CommonInput{}
+		zone, zonerr := zonein.GetAvailabilityZones(con)
+		if zonerr != nil {
+			return nil, 0, zonerr
+		}
+		zones = zone
+	}
+
+	subnetin := GetNetworksInput{VpcIds: []string{net.Network.VpcId}}
+	subnetlist, suberr := subnetin.GetSubnetsFromVpc(con)
+	if suberr != nil {
+		return nil, 0, suberr
+	}
+	names := make([]string, 0)
+	taken := make([]string, 0)
+	for _, subnet := range subnetlist.Subnets {
+		if subnet.Name != "" {
+			names = append(names, subnet.Name)
+		}
+		taken = append(taken, subnet.Cidr)
+	}
+	uqnin := CommonInput{SortInput: names}
+	uqnchr, unerr := uqnin.GetUniqueNumberFromTags()
+	if unerr != nil {
+		return nil, 0, unerr
+	}
+
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return nil, 0, seserr
+	}
+	lookup := UpdateNetworkInput{Network: NetworkCreateInput{VpcId: net.Network.VpcId}}
+	blocks, blockerr := lookup.vpcCidrs(sess)
+	if blockerr != nil {
+		return nil, 0, blockerr
+	}
+	layout, layouterr := net.Network.layoutSubnets(zones, append([]string{blocks.Cidr}, blocks.SecondaryCidrs...), taken)
+	if layouterr != nil {
+		return nil, 0, layouterr
+	}
+	return layout, uqnchr, nil
+}
+
+// addVpcCidr associates the CIDR block passed in VpcCidr with the network as its secondary block.
+func (net *UpdateNetworkInput) addVpcCidr(con aws.EstablishConnectionInput) (NetworkResponse, error) {
+
+	if net.Network.VpcCidr == "" {
+		return NetworkResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the CIDR block to be added to the network")
+	}
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return NetworkResponse{}, seserr
+	}
+	_, assocerr := sess.AssociateVpcCidr(&aws.CreateNetworkInput{VpcId: net.Network.VpcId, Cidr: net.Network.VpcCidr})
+	if assocerr != nil {
+		return NetworkResponse{}, assocerr
+	}
+	return net.vpcCidrs(sess)
+}
+
+// removeVpcCidr removes the secondary CIDR block passed in VpcCidr from the network, the subnetworks in the block has to be deleted prior.
+func (net *UpdateNetworkInput) removeVpcCidr(con aws.EstablishConnectionInput) (NetworkResponse, error) {
+
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return NetworkResponse{}, seserr
+	}
+	association, assocerr := net.cidrAssociation(sess)
+	if assocerr != nil {
+		return NetworkResponse{}, assocerr
+	}
+	if disserr := sess.DisassociateVpcCidr(&aws.DescribeNetworkInput{AssociationsId: association}); disserr != nil {
+		return NetworkResponse{}, disserr
+	}
+	return net.vpcCidrs(sess)
+}
+
+// cidrAssociation returns the ID of the association of the secondary CIDR block passed in VpcCidr with the network.
+func (net *UpdateNetworkInput) cidrAssociation(sess aws.EstablishedSession) (string, error) {
+
+	if net.Network.VpcCidr == "" {
+		return "", cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the CIDR block to be removed from the network")
+	}
+	vpcs, vpcerr := sess.DescribeVpc(&aws.DescribeNetworkInput{VpcIds: []string{net.Network.VpcId}})
+	if vpcerr != nil {
+		return "", vpcerr
+	}
+	for _, vpc := range vpcs.Vpcs {
+		for _, association := range vpc.CidrBlockAssociationSet {
+			if awssdk.StringValue(association.CidrBlock) != net.Network.VpcCidr {
+				continue
+			}
+			if awssdk.StringValue(association.CidrBlock) == awssdk.StringValue(vpc.CidrBlock) {
+				return "", cloudyerror.Newf(cloudyerror.InvalidInput, "The CIDR block %s is the primary block of the network %s and cannot be removed", net.Network.VpcCidr, net.Network.VpcId)
+			}
+			return *association.AssociationId, nil
+		}
+	}
+	return "", &cloudyerror.Error{Code: cloudyerror.NotFound, Cloud: "aws", Kind: "network", Message: fmt.Sprintf("Could not find the CIDR block %s in the network %s", net.Network.VpcCidr, net.Network.VpcId)}
+}
+
+// vpcCidrs returns the CIDR blocks of the network, the primary one in Cidr and the others in SecondaryCidrs.
+func (net *UpdateNetworkInput) vpcCidrs(sess aws.EstablishedSession) (NetworkResponse, error) {
+
+	vpcs, vpcerr := sess.DescribeVpc(&aws.DescribeNetworkInput{VpcIds: []string{net.Network.VpcId}})
+	if vpcerr != nil {
+		return NetworkResponse{}, vpcerr
+	}
+	vpc := vpcs.Vpcs[0]
+	if net.GetRaw == true {
+		return NetworkResponse{GetVpcRaw: vpc}, nil
+	}
+	response := NetworkResponse{Name: nameOf(vpc.Tags), VpcId: *vpc.VpcId, State: *vpc.State, Cidr: *vpc.CidrBlock}
+	for _, association := range vpc.CidrBlockAssociationSet {
+		if *association.CidrBlock == *vpc.CidrBlock {
+			continue
+		}
+		switch awssdk.StringValue(association.CidrBlockState.State) {
+		case ec2.VpcCidrBlockStateCodeAssociating, ec2.VpcCidrBlockStateCodeAssociated:
+			response.SecondaryCidrs = append(response.SecondaryCidrs, *association.CidrBlock)
+		}
+	}
+	return response, nil
+}
+
+// attachIgw attaches the internet gateway passed in IgwId to the network, a new one is created if none was passed.
+func (net *UpdateNetworkInput) attachIgw(con aws.EstablishConnectionInput) (NetworkResponse, error) {
+
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return NetworkResponse{}, seserr
+	}
+	current, igwerr := attachedIgw(sess, net.Network.VpcId)
+	if igwerr != nil {
+		return NetworkResponse{}, igwerr
+	}
+	if current != "" {
+		return NetworkResponse{}, cloudyerror.Newf(cloudyerror.Conflict, "The network %s already has the internet gateway %s attached, replace it instead", net.Network.VpcId, current)
+	}
+	undo := new(rollback)
+	igw, newerr := net.newIgw(con, sess, undo)
+	if newerr != nil {
+		return NetworkResponse{Rollback: undo.run(con)}, newerr
+	}
+	return net.igwResponse(sess, igw, "")
+}
+
+// detachIgw removes the routes to the internet gateway of the network, detaches it and deletes it. This leaves the network private.
+func (net *UpdateNetworkInput) detachIgw(con aws.EstablishConnectionInput) (NetworkResponse, error) {
+
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return NetworkResponse{}, seserr
+	}
+	current, igwerr := attachedIgw(sess, net.Network.VpcId)
+	if igwerr != nil {
+		return NetworkResponse{}, igwerr
+	}
+	if current == "" {
+		return NetworkResponse{}, &cloudyerror.Error{Code: cloudyerror.NotFound, Cloud: "aws", Kind: "internetgateway", Message: fmt.Sprintf("The network %s has no internet gateway attached", net.Network.VpcId)}
+	}
+	if remerr := net.removeIgw(con, sess, current); remerr != nil {
+		return NetworkResponse{}, remerr
+	}
+	return NetworkResponse{VpcId: net.Network.VpcId, Type: "private"}, nil
+}
+
+// replaceIgw replaces the internet gateway of the network with the one passed in IgwId or a new one if none was passed,
+// the routes to the gateway replaced are pointed to the new one and the gateway replaced is deleted.
+// If the replacement fails midway, the new gateway is removed and the one replaced is attached back along with its routes.
+func (net *UpdateNetworkInput) replaceIgw(con aws.EstablishConnectionInput) (NetworkResponse, error) {
+
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return NetworkResponse{}, seserr
+	}
+	current, igwerr := attachedIgw(sess, net.Network.VpcId)
+	if igwerr != nil {
+		return NetworkResponse{}, igwerr
+	}
+	if current == "" {
+		return NetworkResponse{}, &cloudyerror.Error{Code: cloudyerror.NotFound, Cloud: "aws", Kind: "internetgateway", Message: fmt.Sprintf("The network %s has no internet gateway attached, attach one instead", net.Network.VpcId)}
+	}
+	routes, routerr := routesToIgw(sess, net.Network.VpcId, current)
+	if routerr != nil {
+		return NetworkResponse{}, routerr
+	}
+
+	// a network can have only one internet gateway attached, hence the one replaced is detached first.
+	undo := new(rollback)
+	dettachgateway := NetworkComponentInput{IgwIds: []string{current}, VpcIds: []string{net.Network.VpcId}}
+	if deterr := dettachgateway.DetachIgws(con); deterr != nil {
+		return NetworkResponse{}, deterr
+	}
+	vpc := net.Network.VpcId
+	undo.record("internet gateway detachment", current, func(con aws.EstablishConnectionInput) error {
+		// the new gateway is detached by then, the routes pointed to it are pointed back once the gateway replaced is attached.
+		sess, seserr := con.EstablishConnection()
+		if seserr != nil {
+			return seserr
+		}
+		if aterr := sess.AttachIgw(&aws.DescribeNetworkInput{IgwIds: []string{current}, VpcIds: []string{vpc}}); aterr != nil {
+			return aterr
+		}
+		for _, route := range routes {
+			if reperr := sess.ReplaceRoute(&aws.CreateNetworkInput{RouteTableId: route.routeTable, DestinationCidr: route.destination, IgwId: current}); reperr != nil {
+				return reperr
+			}
+		}
+		return nil
+	})
+
+	igw, newerr := net.newIgw(con, sess, undo)
+	if newerr != nil {
+		return NetworkResponse{Rollback: undo.run(con)}, newerr
+	}
+	for _, route := range routes {
+		reperr := sess.ReplaceRoute(&aws.CreateNetworkInput{RouteTableId: route.routeTable, DestinationCidr: route.destination, IgwId: igw})
+		if reperr != nil {
+			return NetworkResponse{Rollback: undo.run(con)}, reperr
+		}
+	}
+	deletegateway := NetworkComponentInput{IgwIds: []string{current}}
+	if delerr := deletegateway.DeleteIgws(con); delerr != nil {
+		return NetworkResponse{Rollback: undo.run(con)}, delerr
+	}
+	return net.igwResponse(sess, igw, "")
+}
+
+// makePublic makes every subnetwork of the network public, by routing its traffic to the internet gateway.
+// The internet gateway is attached if the network has none and the subnetworks using the main route table are given a route table of its own.
+// If it fails midway, the internet gateway, route tables and routes created are removed and the routes replaced are pointed back.
+func (net *UpdateNetworkInput) makePublic(con aws.EstablishConnectionInput) (NetworkResponse, error) {
+
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return NetworkResponse{}, seserr
+	}
+	igw, igwerr := attachedIgw(sess, net.Network.VpcId)
+	if igwerr != nil {
+		return NetworkResponse{}, igwerr
+	}
+	undo := new(rollback)
+	if igw == "" {
+		created, newerr := net.newIgw(con, sess, undo)
+		if newerr != nil {
+			return NetworkResponse{Rollback: undo.run(con)}, newerr
+		}
+		igw = created
+	}
+	routing, routingerr := publicRouting(sess, net.Network.VpcId, igw)
+	if routingerr != nil {
+		return NetworkResponse{Rollback: undo.run(con)}, routingerr
+	}
+
+	subnets := make([]SubnetReponse, 0, len(routing))
+	for _, subnet := range routing {
+		routetable := subnet.routeTable
+		if routetable == "" {
+			table, tableerr := sess.CreateRouteTable(&aws.CreateNetworkInput{VpcId: net.Network.VpcId})
+			if tableerr != nil {
+				return NetworkResponse{Rollback: undo.run(con)}, tableerr
+			}
+			routetable = *table.RouteTable.RouteTableId
+			created := routetable
+			undo.record("route table", created, func(con aws.EstablishConnectionInput) error {
+				route := NetworkComponentInput{RouteTableIds: []string{created}}
+				return route.DeleteRouteTable(con)
+			})
+		}
+		if routeerr := recordPublicRoute(sess, undo, subnet, routetable, igw); routeerr != nil {
+			return NetworkResponse{Rollback: undo.run(con)}, routeerr
+		}
+		if subnet.routeTable == "" {
+			attacherr := sess.AttachRouteTable(&aws.CreateNetworkInput{RouteTableId: routetable, SubId: subnet.subnet.Id})
+			if attacherr != nil {
+				return NetworkResponse{Rollback: undo.run(con)}, attacherr
+			}
+			association := NetworkComponentInput{rollback: undo}
+			association.recordRouteTableAssociation(routetable)
+		}
+		subnets = append(subnets, subnet.subnet)
+	}
+	return net.igwResponse(sess, igw, "public", subnets...)
+}
+
+// recordPublicRoute points the default route of the route table to the internet gateway as the routing of the subnetwork demands,
+// and records the action which undoes it: the route created is deleted and the one replaced is pointed back to the gateway it led to.
+func recordPublicRoute(sess aws.EstablishedSession, undo *rollback, subnet subnetRouting, routetable, igw string) error {
+
+	route := &aws.CreateNetworkInput{RouteTableId: routetable, DestinationCidr: defaultRoute, IgwId: igw}
+	switch subnet.route {
+	case "create":
+		if routeerr := sess.WriteRoute(route); routeerr != nil {
+			return routeerr
+		}
+		undo.record("route", routetable, func(con aws.EstablishConnectionInput) error {
+			sess, seserr := con.EstablishConnection()
+			if seserr != nil {
+				return seserr
+			}
+			return sess.DeleteRoute(&aws.CreateNetworkInput{RouteTableId: routetable, DestinationCidr: defaultRoute})
+		})
+	case "replace":
+		if routeerr := sess.ReplaceRoute(route); routeerr != nil {
+			return routeerr
+		}
+		previous := subnet.gateway
+		undo.record("route", routetable, func(con aws.EstablishConnectionInput) error {
+			if previous == "" {
+				return cloudyerror.Newf(cloudyerror.Conflict, "The default route of the route table %s did not lead to a gateway, it has to be pointed back by hand", routetable)
+			}
+			sess, seserr := con.EstablishConnection()
+			if seserr != nil {
+				return seserr
+			}
+			return sess.ReplaceRoute(&aws.CreateNetworkInput{RouteTableId: routetable, DestinationCidr: defaultRoute, IgwId: previous})
+		})
+	}
+	return nil
+}
+
+// makePrivate makes the network private by removing its internet gateway along with the routes to it, nothing is done if the network has none.
+func (net *UpdateNetworkInput) makePrivate(con aws.EstablishConnectionInput) (NetworkResponse, error) {
+
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return NetworkResponse{}, seserr
+	}
+	igw, igwerr := attachedIgw(sess, net.Network.VpcId)
+	if igwerr != nil {
+		return NetworkResponse{}, igwerr
+	}
+	if igw != "" {
+		if remerr := net.removeIgw(con, sess, igw); remerr != nil {
+			return NetworkResponse{}, remerr
+		}
+	}
+	return NetworkResponse{VpcId: net.Network.VpcId, Type: "private"}, nil
+}
+
+// updatePorts opens the ports passed in Ports on the security groups of the network, the ones opened already are left as is.
+// The ports are closed instead if open is false, the ones which are not opened are left as is.
+// The default security group of the network is not touched as the servers are not placed in it by this package.
+func (net *UpdateNetworkInput) updatePorts(con aws.EstablishConnectionInput, open bool) (NetworkResponse, error) {
+
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return NetworkResponse{}, seserr
+	}
+	changes, changerr := net.resolvePorts(sess, open)
+	if changerr != nil {
+		return NetworkResponse{}, changerr
+	}
+	for _, change := range changes {
+		var rulerr error
+		if open {
+			rulerr = sess.CreateIngressRule(&change)
+		} else {
+			rulerr = sess.DeleteIngressRule(&change)
+		}
+		if rulerr != nil {
+			return NetworkResponse{}, rulerr
+		}
+	}
+
+	secin := NetworkComponentInput{VpcIds: []string{net.Network.VpcId}, GetRaw: net.GetRaw}
+	sec, secerr := secin.GetSecFromVpc(con)
+	if secerr != nil {
+		return NetworkResponse{}, secerr
+	}
+	if net.GetRaw == true {
+		return NetworkResponse{DescribeSecurityRaw: sec.GetSecurityRaw}, nil
+	}
+	return NetworkResponse{VpcId: net.Network.VpcId, SecGroupIds: sec.SecGroupIds, SecurityGroups: sec.SecurityGroups}, nil
+}
+
+// resolvePorts returns the ingress rules to be added (open) or removed, one for every port and security group of the network.
+func (net *UpdateNetworkInput) resolvePorts(sess aws.EstablishedSession, open bool) ([]aws.IngressEgressInput, error) {
+
+	if len(net.Network.Ports) == 0 {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the ports to be updated on the network")
+	}
+	ports := make([]int64, 0, len(net.Network.Ports))
+	for _, port := range net.Network.Ports {
+		intport, porterr := strconv.ParseInt(port, 10, 64)
+		if (porterr != nil) || (intport < 0) || (intport > 65535) {
+			return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The port %s is not valid, ports has to be numbers between 0 and 65535", port)
+		}
+		ports = append(ports, intport)
+	}
+
+	groups, secerr := sess.DescribeSecurityGroup(&aws.DescribeNetworkInput{Filters: aws.Filters{Name: "vpc-id", Value: []string{net.Network.VpcId}}})
+	if secerr != nil {
+		return nil, secerr
+	}
+	changes := make([]aws.IngressEgressInput, 0)
+	found := false
+	for _, group := range groups.SecurityGroups {
+		if *group.GroupName == "default" {
+			continue
+		}
+		found = true
+		for _, port := range ports {
+			if opensPort(group, port) != open {
+				changes = append(changes, aws.IngressEgressInput{Port: port, SecId: *group.GroupId})
+			}
+		}
+	}
+	if found != true {
+		return nil, &cloudyerror.Error{Code: cloudyerror.NotFound, Cloud: "aws", Kind: "securitygroup", Message: fmt.Sprintf("Could not find the security groups of the network %s", net.Network.VpcId)}
+	}
+	return changes, nil
+}
+
+// newIgw attaches the internet gateway passed in IgwId to the network, a new one is created and attached if none was passed.
+// The attachment, and the creation of the gateway, are recorded on the rollback passed.
+func (net *UpdateNetworkInput) newIgw(con aws.EstablishConnectionInput, sess aws.EstablishedSession, undo *rollback) (string, error) {
+
+	if net.Network.IgwId != "" {
+		aterr := sess.AttachIgw(&aws.DescribeNetworkInput{IgwIds: []string{net.Network.IgwId}, VpcIds: []string{net.Network.VpcId}})
+		if aterr != nil {
+			return "", aterr
+		}
+		igw, vpcids := net.Network.IgwId, []string{net.Network.VpcId}
+		undo.record("internet gateway attachment", igw, func(con aws.EstablishConnectionInput) error {
+			dettachgateway := NetworkComponentInput{IgwIds: []string{igw}, VpcIds: vpcids}
+			return dettachgateway.DetachIgws(con)
+		})
+		return igw, nil
+	}
+
+	name, nameerr := net.networkName(sess)
+	if nameerr != nil {
+		return "", nameerr
+	}
+	igwin := NetworkComponentInput{Name: name, VpcIds: []string{net.Network.VpcId}, rollback: undo}
+	igw, igwerr := igwin.CreateIgw(con)
+	if igwerr != nil {
+		return "", igwerr
+	}
+	return igw.IgwIds[0], nil
+}
+
+// removeIgw removes the routes to the internet gateway from the route tables of the network, then detaches and deletes it.
+func (net *UpdateNetworkInput) removeIgw(con aws.EstablishConnectionInput, sess aws.EstablishedSession, igw string) error {
+
+	routes, routerr := routesToIgw(sess, net.Network.VpcId, igw)
+	if routerr != nil {
+		return routerr
+	}
+	for _, route := range routes {
+		if delerr := sess.DeleteRoute(&aws.CreateNetworkInput{RouteTableId: route.routeTable, DestinationCidr: route.destination}); delerr != nil {
+			return delerr
+		}
+	}
+	dettachgateway := NetworkComponentInput{IgwIds: []string{igw}, VpcIds: []string{net.Network.VpcId}}
+	if deterr := dettachgateway.DetachIgws(con); deterr != nil {
+		return deterr
+	}
+	deletegateway := NetworkComponentInput{IgwIds: []string{igw}}
+	return deletegateway.DeleteIgws(con)
+}
+
+// networkName returns the name of the network passed in Name, the name is looked up from the network if it was not passed.
+func (net *UpdateNetworkInput) networkName(sess aws.EstablishedSession) (string, error) {
+
+	if net.Network.Name != "" {
+		return net.Network.Name, nil
+	}
+	vpcs, vpcerr := sess.DescribeVpc(&aws.DescribeNetworkInput{VpcIds: []string{net.Network.VpcId}})
+	if vpcerr != nil {
+		return "", vpcerr
+	}
+	return nameOf(vpcs.Vpcs[0].Tags), nil
+}
+
+// igwResponse returns the response of the updates of the internet gateway of the network.
+func (net *UpdateNetworkInput) igwResponse(sess aws.EstablishedSession, igw, nettype string, subnets ...SubnetReponse) (NetworkResponse, error) {
+
+	if net.GetRaw == true {
+		response, igwerr := sess.DescribeIgw(&aws.DescribeNetworkInput{IgwIds: []string{igw}})
+		if igwerr != nil {
+			return NetworkResponse{}, igwerr
+		}
+		return NetworkResponse{DescribeIgwRaw: response}, nil
+	}
+	return NetworkResponse{VpcId: net.Network.VpcId, IgwId: igw, Type: nettype, Subnets: subnets}, nil
+}
+
+// attachedIgw returns the ID of the internet gateway attached to the network, it is empty if the network has none.
+func attachedIgw(sess aws.EstablishedSession, vpc string) (string, error) {
+
+	response, err := sess.DescribeIgw(&aws.DescribeNetworkInput{Filters: aws.Filters{Name: "attachment.vpc-id", Value: []string{vpc}}})
+	if err != nil {
+		return "", err
+	}
+	if len(response.InternetGateways) == 0 {
+		return "", nil
+	}
+	return *response.InternetGateways[0].InternetGatewayId, nil
+}
+
+// routesToIgw returns the routes of the route tables of the network which leads to the internet gateway passed.
+func routesToIgw(sess aws.EstablishedSession, vpc, igw string) ([]gatewayRoute, error) {
+
+	tables, err := sess.DescribeRouteTable(&aws.DescribeNetworkInput{Filters: aws.Filters{Name: "vpc-id", Value: []string{vpc}}})
+	if err != nil {
+		return nil, err
+	}
+	routes := make([]gatewayRoute, 0)
+	for _, table := range tables.RouteTables {
+		for _, route := range table.Routes {
+			if awssdk.StringValue(route.GatewayId) == igw {
+				routes = append(routes, gatewayRoute{routeTable: *table.RouteTableId, destination: awssdk.StringValue(route.DestinationCidrBlock)})
+			}
+		}
+	}
+	return routes, nil
+}
+
+// publicRouting returns the changes in the routing of the subnetworks of the network which makes them public.
+func publicRouting(sess aws.EstablishedSession, vpc, igw string) ([]subnetRouting, error) {
+
+	subnets, suberr := sess.DescribeSubnet(&aws.DescribeNetworkInput{Filters: aws.Filters{Name: "vpc-id", Value: []string{vpc}}})
+	if suberr != nil {
+		return nil, suberr
+	}
+	tables, routerr := sess.DescribeRouteTable(&aws.DescribeNetworkInput{Filters: aws.Filters{Name: "vpc-id", Value: []string{vpc}}})
+	if routerr != nil {
+		return nil, routerr
+	}
+
+	routing := make([]subnetRouting, 0, len(subnets.Subnets))
+	for _, subnet := range subnets.Subnets {
+		change := subnetRouting{subnet: SubnetReponse{Name: nameOf(subnet.Tags), Id: *subnet.SubnetId, VpcId: vpc, Cidr: *subnet.CidrBlock}, route: "create"}
+		for _, table := range tables.RouteTables {
+			associated := false
+			for _, association := range table.Associations {
+				if awssdk.StringValue(association.SubnetId) == *subnet.SubnetId {
+					associated = true
+				}
+			}
+			if associated != true {
+				continue
+			}
+			change.routeTable = *table.RouteTableId
+			for _, route := range table.Routes {
+				if awssdk.StringValue(route.DestinationCidrBlock) != defaultRoute {
+					continue
+				}
+				change.route = "replace"
+				change.gateway = awssdk.StringValue(route.GatewayId)
+				if (igw != "") && (awssdk.StringValue(route.GatewayId) == igw) {
+					change.route = ""
+				}
+			}
+		}
+		routing = append(routing, change)
+	}
+	return routing, nil
+}
+
+// opensPort reports whether the security group has the ingress rule created by CreateIngressRule for the port.
+func opensPort(group *ec2.SecurityGroup, port int64) bool {
+	for _, permission := range group.IpPermissions {
+		if (awssdk.StringValue(permission.IpProtocol) != "tcp") || (awssdk.Int64Value(permission.FromPort) != port) || (awssdk.Int64Value(permission.ToPort) != port) {
+			continue
+		}
+		for _, iprange := range permission.IpRanges {
+			if awssdk.StringValue(iprange.CidrIp) == "0.0.0.0/0" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nameOf returns the value of the tag Name from the tags passed.
+func nameOf(tags []*ec2.Tag) string {
+	for _, tag := range tags {
+		if awssdk.StringValue(tag.Key) == "Name" {
+			return awssdk.StringValue(tag.Value)
+		}
+	}
+	return ""
+}
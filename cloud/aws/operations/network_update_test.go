@@ -0,0 +1,353 @@
+package aws
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	awsfake "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/fake"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// defaultRoutes returns the gateways to which the default route of the route tables of the network leads, keyed by the route table.
+func defaultRoutes(t *testing.T, cloud *awsfake.Cloud, vpc string) map[string]string {
+	t.Helper()
+	tables, err := cloud.EC2(testRegion).DescribeRouteTablesWithContext(context.Background(), &ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{{Name: awssdk.String("vpc-id"), Values: []*string{awssdk.String(vpc)}}},
+	})
+	if err != nil {
+		t.Fatalf("describing route tables: %v", err)
+	}
+	routes := make(map[string]string)
+	for _, table := range tables.RouteTables {
+		for _, route := range table.Routes {
+			if awssdk.StringValue(route.DestinationCidrBlock) == defaultRoute {
+				routes[*table.RouteTableId] = awssdk.StringValue(route.GatewayId)
+			}
+		}
+	}
+	return routes
+}
+
+func TestUpdateNetworkDeleteSubnets(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	update := UpdateNetworkInput{Resource: "subnets", Action: "delete", Network: NetworkCreateInput{VpcId: network.VpcId}, Subnets: []string{"neuron_sub0"}}
+	response, err := update.UpdateNetwork(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("deleting subnet: %v", err)
+	}
+	if len(response.Subnets) != 1 || response.Subnets[0].Id != network.Subnets[0].Id || response.Subnets[0].State != "deleted" {
+		t.Fatalf("expected the subnet neuron_sub0 to be deleted, got %+v", response.Subnets)
+	}
+
+	get := GetNetworksInput{VpcIds: []string{network.VpcId}}
+	subnets, err := get.GetSubnetsFromVpc(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("fetching subnets: %v", err)
+	}
+	if len(subnets.Subnets) != 1 || subnets.Subnets[0].Id != network.Subnets[1].Id {
+		t.Errorf("expected only the subnet %s to be left, got %+v", network.Subnets[1].Id, subnets.Subnets)
+	}
+	// the route table of the subnet deleted goes along, the main one and the one of the other subnet are left.
+	if routes := defaultRoutes(t, cloud, network.VpcId); len(routes) != 1 {
+		t.Errorf("expected the route table of the subnet to be deleted, got %v", routes)
+	}
+
+	update.Subnets = []string{"neuron_sub7"}
+	if _, err := update.UpdateNetwork(fakeConnection(cloud, "ec2")); !cloudyerror.IsNotFound(err) {
+		t.Errorf("expected not found while deleting unknown subnet, got %v", err)
+	}
+}
+
+func TestUpdateNetworkVpcCidr(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+	con := fakeConnection(cloud, "ec2")
+
+	add := UpdateNetworkInput{Resource: "vpc", Action: "addcidr", Network: NetworkCreateInput{VpcId: network.VpcId, VpcCidr: "10.1.0.0/16"}}
+	response, err := add.UpdateNetwork(con)
+	if err != nil {
+		t.Fatalf("adding cidr: %v", err)
+	}
+	if response.Cidr != "10.0.0.0/16" || !reflect.DeepEqual(response.SecondaryCidrs, []string{"10.1.0.0/16"}) {
+		t.Fatalf("expected the cidr 10.1.0.0/16 to be added, got %+v", response)
+	}
+
+	// subnets can be created in the block added and the block cannot be removed while they exist.
+	create := UpdateNetworkInput{Resource: "subnets", Action: "create", Network: NetworkCreateInput{VpcId: network.VpcId, Name: "neuron", SubCidrs: []string{"10.1.1.0/24"}}}
+	subnets, err := create.UpdateNetwork(con)
+	if err != nil {
+		t.Fatalf("creating subnet in the cidr added: %v", err)
+	}
+	remove := UpdateNetworkInput{Resource: "vpc", Action: "removecidr", Network: NetworkCreateInput{VpcId: network.VpcId, VpcCidr: "10.1.0.0/16"}}
+	if _, err := remove.UpdateNetwork(con); err == nil {
+		t.Fatalf("expected the removal of cidr in use to fail")
+	} else if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "DependencyViolation" {
+		t.Fatalf("expected DependencyViolation, got %v", err)
+	}
+
+	del := UpdateNetworkInput{Resource: "subnets", Action: "delete", Network: NetworkCreateInput{VpcId: network.VpcId}, Subnets: []string{subnets.Subnets[0].Id}}
+	if _, err := del.UpdateNetwork(con); err != nil {
+		t.Fatalf("deleting subnet: %v", err)
+	}
+	response, err = remove.UpdateNetwork(con)
+	if err != nil {
+		t.Fatalf("removing cidr: %v", err)
+	}
+	if len(response.SecondaryCidrs) != 0 {
+		t.Errorf("expected the cidr to be removed, got %+v", response.SecondaryCidrs)
+	}
+
+	primary := UpdateNetworkInput{Resource: "vpc", Action: "removecidr", Network: NetworkCreateInput{VpcId: network.VpcId, VpcCidr: "10.0.0.0/16"}}
+	if _, err := primary.UpdateNetwork(con); !cloudyerror.IsInvalidInput(err) {
+		t.Errorf("expected the removal of primary cidr to be refused, got %v", err)
+	}
+}
+
+func TestUpdateNetworkIgw(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+	con := fakeConnection(cloud, "ec2")
+
+	replace := UpdateNetworkInput{Resource: "igw", Action: "replace", Network: NetworkCreateInput{VpcId: network.VpcId}}
+	replaced, err := replace.UpdateNetwork(con)
+	if err != nil {
+		t.Fatalf("replacing internet gateway: %v", err)
+	}
+	if replaced.IgwId == "" || replaced.IgwId == network.IgwId {
+		t.Fatalf("expected the internet gateway to be replaced, got %+v", replaced)
+	}
+	routes := defaultRoutes(t, cloud, network.VpcId)
+	if len(routes) != 2 {
+		t.Fatalf("expected the default routes of both subnets to be left, got %v", routes)
+	}
+	for table, gateway := range routes {
+		if gateway != replaced.IgwId {
+			t.Errorf("expected the default route of %s to lead to %s, got %s", table, replaced.IgwId, gateway)
+		}
+	}
+	if _, err := cloud.EC2(testRegion).DescribeInternetGatewaysWithContext(context.Background(), &ec2.DescribeInternetGatewaysInput{
+		InternetGatewayIds: []*string{awssdk.String(network.IgwId)},
+	}); err == nil {
+		t.Errorf("expected the internet gateway replaced to be deleted")
+	}
+
+	private := UpdateNetworkInput{Resource: "vpc", Action: "private", Network: NetworkCreateInput{VpcId: network.VpcId}}
+	if _, err := private.UpdateNetwork(con); err != nil {
+		t.Fatalf("making network private: %v", err)
+	}
+	if routes := defaultRoutes(t, cloud, network.VpcId); len(routes) != 0 {
+		t.Errorf("expected the default routes to be removed, got %v", routes)
+	}
+	detach := UpdateNetworkInput{Resource: "igw", Action: "detach", Network: NetworkCreateInput{VpcId: network.VpcId}}
+	if _, err := detach.UpdateNetwork(con); !cloudyerror.IsNotFound(err) {
+		t.Errorf("expected not found while detaching the internet gateway of private network, got %v", err)
+	}
+
+	public := UpdateNetworkInput{Resource: "vpc", Action: "public", Network: NetworkCreateInput{VpcId: network.VpcId}}
+	made, err := public.UpdateNetwork(con)
+	if err != nil {
+		t.Fatalf("making network public: %v", err)
+	}
+	if made.IgwId == "" || made.Type != "public" || len(made.Subnets) != 2 {
+		t.Fatalf("expected the network to be public, got %+v", made)
+	}
+	if routes := defaultRoutes(t, cloud, network.VpcId); len(routes) != 2 {
+		t.Errorf("expected the subnets to be routed to the internet gateway, got %v", routes)
+	}
+
+	attach := UpdateNetworkInput{Resource: "igw", Action: "attach", Network: NetworkCreateInput{VpcId: network.VpcId}}
+	if _, err := attach.UpdateNetwork(con); !cloudyerror.IsConflict(err) {
+		t.Errorf("expected conflict while attaching second internet gateway, got %v", err)
+	}
+}
+
+func TestUpdateNetworkPublicSubnetsOfMainRouteTable(t *testing.T) {
+	cloud := awsfake.New()
+	network := NetworkCreateInput{Name: "neuron", VpcCidr: "10.0.0.0/16", SubCidrs: []string{"10.0.1.0/24"}, Type: "private"}
+	created, err := network.CreateNetwork(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating network: %v", err)
+	}
+
+	public := UpdateNetworkInput{Resource: "vpc", Action: "public", Network: NetworkCreateInput{VpcId: created.VpcId}}
+	made, err := public.UpdateNetwork(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("making network public: %v", err)
+	}
+	tables, err := cloud.EC2(testRegion).DescribeRouteTablesWithContext(context.Background(), &ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{{Name: awssdk.String("association.subnet-id"), Values: []*string{awssdk.String(created.Subnets[0].Id)}}},
+	})
+	if err != nil || len(tables.RouteTables) != 1 {
+		t.Fatalf("expected the subnet to be associated with a route table, got %v (%v)", tables, err)
+	}
+	if gateway := defaultRoutes(t, cloud, created.VpcId)[*tables.RouteTables[0].RouteTableId]; gateway != made.IgwId {
+		t.Errorf("expected the subnet to be routed to %s, got %q", made.IgwId, gateway)
+	}
+}
+
+func TestUpdateNetworkReplaceIgwRollback(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	// the route of the second subnet fails to be pointed to the new internet gateway.
+	cloud.FailNext("ReplaceRoute", nil)
+	cloud.FailNext("ReplaceRoute", awserr.New("InvalidRoute.NotFound", "The route was not found.", nil))
+	replace := UpdateNetworkInput{Resource: "igw", Action: "replace", Network: NetworkCreateInput{VpcId: network.VpcId}}
+	response, err := replace.UpdateNetwork(fakeConnection(cloud, "ec2"))
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "InvalidRoute.NotFound" {
+		t.Fatalf("expected InvalidRoute.NotFound, got %v", err)
+	}
+	if response.Rollback == nil || len(response.Rollback.Leftovers) != 0 || len(response.Rollback.RolledBack) != 3 {
+		t.Fatalf("expected the replacement to be rolled back completely, got %+v", response.Rollback)
+	}
+
+	// the internet gateway replaced is attached back along with its routes, and the new one is deleted.
+	igws, err := cloud.EC2(testRegion).DescribeInternetGatewaysWithContext(context.Background(), &ec2.DescribeInternetGatewaysInput{})
+	if err != nil || len(igws.InternetGateways) != 1 || *igws.InternetGateways[0].InternetGatewayId != network.IgwId ||
+		len(igws.InternetGateways[0].Attachments) != 1 || *igws.InternetGateways[0].Attachments[0].VpcId != network.VpcId {
+		t.Fatalf("expected only the internet gateway replaced to be left attached, got %v (%v)", igws, err)
+	}
+	routes := defaultRoutes(t, cloud, network.VpcId)
+	if len(routes) != 2 {
+		t.Fatalf("expected the default routes of both subnets to be left, got %v", routes)
+	}
+	for table, gateway := range routes {
+		if gateway != network.IgwId {
+			t.Errorf("expected the default route of %s to lead back to %s, got %s", table, network.IgwId, gateway)
+		}
+	}
+}
+
+func TestUpdateNetworkPublicRollback(t *testing.T) {
+	cloud := awsfake.New()
+	network := NetworkCreateInput{Name: "neuron", VpcCidr: "10.0.0.0/16", SubCidrs: []string{"10.0.1.0/24"}, Type: "private"}
+	created, err := network.CreateNetwork(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating network: %v", err)
+	}
+	describeTables := &ec2.DescribeRouteTablesInput{Filters: []*ec2.Filter{{Name: awssdk.String("vpc-id"), Values: []*string{awssdk.String(created.VpcId)}}}}
+	before, err := cloud.EC2(testRegion).DescribeRouteTablesWithContext(context.Background(), describeTables)
+	if err != nil {
+		t.Fatalf("describing route tables: %v", err)
+	}
+
+	cloud.FailNext("AssociateRouteTable", awserr.New("Resource.AlreadyAssociated", "The subnet is already associated.", nil))
+	public := UpdateNetworkInput{Resource: "vpc", Action: "public", Network: NetworkCreateInput{VpcId: created.VpcId}}
+	response, err := public.UpdateNetwork(fakeConnection(cloud, "ec2"))
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "Resource.AlreadyAssociated" {
+		t.Fatalf("expected Resource.AlreadyAssociated, got %v", err)
+	}
+	if response.Rollback == nil || len(response.Rollback.Leftovers) != 0 {
+		t.Fatalf("expected the network to be rolled back completely, got %+v", response.Rollback)
+	}
+
+	// the internet gateway, route table and route created are removed.
+	igws, err := cloud.EC2(testRegion).DescribeInternetGatewaysWithContext(context.Background(), &ec2.DescribeInternetGatewaysInput{})
+	if err != nil || len(igws.InternetGateways) != 0 {
+		t.Errorf("expected the internet gateway created to be deleted, got %v (%v)", igws, err)
+	}
+	after, err := cloud.EC2(testRegion).DescribeRouteTablesWithContext(context.Background(), describeTables)
+	if err != nil || len(after.RouteTables) != len(before.RouteTables) {
+		t.Errorf("expected the route table created to be deleted, got %v (%v)", after, err)
+	}
+	if routes := defaultRoutes(t, cloud, created.VpcId); len(routes) != 0 {
+		t.Errorf("expected no default route to be left, got %v", routes)
+	}
+}
+
+func TestUpdateNetworkPorts(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+	con := fakeConnection(cloud, "ec2")
+
+	portsOf := func(response NetworkResponse) []string {
+		for _, group := range response.SecurityGroups {
+			if group.Name == "neuron_sec" {
+				return group.Ports
+			}
+		}
+		t.Fatalf("expected the security group of the network in %+v", response.SecurityGroups)
+		return nil
+	}
+
+	add := UpdateNetworkInput{Resource: "securitygroup", Action: "add", Network: NetworkCreateInput{VpcId: network.VpcId, Ports: []string{"22", "443"}}}
+	response, err := add.UpdateNetwork(con)
+	if err != nil {
+		t.Fatalf("opening ports: %v", err)
+	}
+	if ports := portsOf(response); !reflect.DeepEqual(ports, []string{"22", "443", "80"}) {
+		t.Errorf("expected the port 443 to be opened along with the ones opened, got %v", ports)
+	}
+
+	remove := UpdateNetworkInput{Resource: "securitygroup", Action: "remove", Network: NetworkCreateInput{VpcId: network.VpcId, Ports: []string{"80", "8080"}}}
+	response, err = remove.UpdateNetwork(con)
+	if err != nil {
+		t.Fatalf("closing ports: %v", err)
+	}
+	if ports := portsOf(response); !reflect.DeepEqual(ports, []string{"22", "443"}) {
+		t.Errorf("expected the port 80 to be closed, got %v", ports)
+	}
+
+	add.Network.Ports = []string{"ssh"}
+	if _, err := add.UpdateNetwork(con); !cloudyerror.IsInvalidInput(err) {
+		t.Errorf("expected invalid input for the port ssh, got %v", err)
+	}
+}
+
+func TestUpdateNetworkSubnetLayout(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	// the blocks taken by the subnets 10.0.1.0/24 and 10.0.2.0/24 of the network are skipped.
+	update := UpdateNetworkInput{Resource: "subnets", Action: "create", Network: NetworkCreateInput{Name: "neuron", VpcId: network.VpcId, Layout: SubnetLayout{Count: 2, PrefixLength: 24}}}
+	response, err := update.UpdateNetwork(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating subnets: %v", err)
+	}
+	created := make([]string, 0)
+	for _, subnet := range response.Subnets {
+		created = append(created, subnet.Cidr)
+	}
+	if expected := []string{"10.0.0.0/24", "10.0.3.0/24"}; !reflect.DeepEqual(created, expected) {
+		t.Errorf("expected the subnets %v to be created, got %v", expected, created)
+	}
+
+	subnets := cloud.Calls("CreateSubnet")
+	overlapping := UpdateNetworkInput{Resource: "subnets", Action: "create", Network: NetworkCreateInput{Name: "neuron", VpcId: network.VpcId, SubCidrs: []string{"10.0.4.0/24", "10.0.2.128/25"}}}
+	if _, err := overlapping.UpdateNetwork(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+		t.Fatalf("expected invalid input for the subnet overlapping the ones present, got %v", err)
+	}
+	if _, err := overlapping.PlanUpdateNetwork(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+		t.Fatalf("expected invalid input while planning the subnet overlapping the ones present, got %v", err)
+	}
+	if calls := cloud.Calls("CreateSubnet") - subnets; calls != 0 {
+		t.Errorf("expected none of the subnets to be created, got %d calls", calls)
+	}
+}
+
+func TestUpdateNetworkUnsupported(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	for _, update := range []UpdateNetworkInput{
+		{Resource: "subnets", Action: "rename"},
+		{Resource: "vpc", Action: "move"},
+		{Resource: "igw", Action: "rename"},
+		{Resource: "securitygroup", Action: "replace"},
+		{Resource: "natgateway", Action: "add"},
+	} {
+		update.Network = NetworkCreateInput{VpcId: network.VpcId}
+		if _, err := update.UpdateNetwork(fakeConnection(cloud, "ec2")); !cloudyerror.IsUnsupported(err) {
+			t.Errorf("expected the update of %s by %s to be unsupported, got %v", update.Resource, update.Action, err)
+		}
+		if _, err := update.PlanUpdateNetwork(fakeConnection(cloud, "ec2")); !cloudyerror.IsUnsupported(err) {
+			t.Errorf("expected the plan to update %s by %s to be unsupported, got %v", update.Resource, update.Action, err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	awsfake "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/fake"
+	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+)
+
+// createTestVpcs creates the number of vpcs passed and returns the session with which they were created.
+func createTestVpcs(t *testing.T, cloud *awsfake.Cloud, count int) aws.EstablishedSession {
+	t.Helper()
+	con := fakeConnection(cloud, "ec2")
+	sess, err := con.EstablishConnection()
+	if err != nil {
+		t.Fatalf("establishing connection: %v", err)
+	}
+	for i := 0; i < count; i++ {
+		if _, err := sess.CreateVpc(&aws.CreateNetworkInput{Cidr: fmt.Sprintf("10.%d.0.0/16", i), Tenancy: "default"}); err != nil {
+			t.Fatalf("creating vpc %d: %v", i, err)
+		}
+	}
+	return sess
+}
+
+func TestDescribeAllVpcPages(t *testing.T) {
+	cloud := awsfake.New()
+	sess := createTestVpcs(t, cloud, 12)
+
+	before := cloud.Calls("DescribeVpcs")
+	result, err := sess.DescribeAllVpc(&aws.DescribeNetworkInput{Pagination: aws.Pagination{PageSize: 5}})
+	if err != nil {
+		t.Fatalf("describing vpcs: %v", err)
+	}
+	if len(result.Vpcs) != 12 {
+		t.Fatalf("expected all the 12 vpcs across the pages, got %d", len(result.Vpcs))
+	}
+	if calls := cloud.Calls("DescribeVpcs") - before; calls != 3 {
+		t.Errorf("expected 3 pages to be fetched, got %d", calls)
+	}
+	seen := make(map[string]bool)
+	for _, vpc := range result.Vpcs {
+		if seen[*vpc.VpcId] {
+			t.Errorf("vpc %s is returned more than once", *vpc.VpcId)
+		}
+		seen[*vpc.VpcId] = true
+	}
+
+	// page size below the one aws accepts is raised to the minimum, rather than failing.
+	result, err = sess.DescribeAllVpc(&aws.DescribeNetworkInput{Pagination: aws.Pagination{PageSize: 1}})
+	if err != nil {
+		t.Fatalf("describing vpcs with a page size of 1: %v", err)
+	}
+	if len(result.Vpcs) != 12 {
+		t.Errorf("expected all the 12 vpcs, got %d", len(result.Vpcs))
+	}
+}
+
+func TestDescribeAllVpcLimit(t *testing.T) {
+	cloud := awsfake.New()
+	sess := createTestVpcs(t, cloud, 12)
+
+	before := cloud.Calls("DescribeVpcs")
+	result, err := sess.DescribeAllVpc(&aws.DescribeNetworkInput{Pagination: aws.Pagination{PageSize: 5, Limit: 7}})
+	if err != nil {
+		t.Fatalf("describing vpcs: %v", err)
+	}
+	if len(result.Vpcs) != 7 {
+		t.Fatalf("expected the vpcs to be limited to 7, got %d", len(result.Vpcs))
+	}
+	if calls := cloud.Calls("DescribeVpcs") - before; calls != 2 {
+		t.Errorf("expected the pages beyond the limit not to be fetched, got %d calls", calls)
+	}
+}
+
+func TestEachVpcStops(t *testing.T) {
+	cloud := awsfake.New()
+	sess := createTestVpcs(t, cloud, 12)
+
+	visited := make([]string, 0)
+	err := sess.EachVpc(&aws.DescribeNetworkInput{Pagination: aws.Pagination{PageSize: 5}}, func(vpc *ec2.Vpc) bool {
+		visited = append(visited, *vpc.VpcId)
+		return len(visited) < 6
+	})
+	if err != nil {
+		t.Fatalf("iterating vpcs: %v", err)
+	}
+	if len(visited) != 6 {
+		t.Errorf("expected the iteration to stop after 6 vpcs, got %d", len(visited))
+	}
+}
+
+func TestDescribeAllInstancesPages(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	for i := 0; i < 3; i++ {
+		server := CreateServerInput{InstanceName: "neuron", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id, MaxCount: 2}
+		if _, err := server.CreateServer(fakeConnection(cloud, "ec2")); err != nil {
+			t.Fatalf("creating servers: %v", err)
+		}
+	}
+
+	con := fakeConnection(cloud, "ec2")
+	sess, err := con.EstablishConnection()
+	if err != nil {
+		t.Fatalf("establishing connection: %v", err)
+	}
+	result, err := sess.DescribeAllInstances(&aws.DescribeComputeInput{Pagination: aws.Pagination{PageSize: 5, Limit: 5}})
+	if err != nil {
+		t.Fatalf("describing instances: %v", err)
+	}
+	instances := 0
+	for _, reservation := range result.Reservations {
+		instances += len(reservation.Instances)
+	}
+	if len(result.Reservations) != 3 || instances != 5 {
+		t.Errorf("expected 5 instances across 3 reservations, got %d across %d", instances, len(result.Reservations))
+	}
+}
+
+func TestDescribeAllTargetgroupsPages(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	con := fakeConnection(cloud, "elb2")
+	sess, err := con.EstablishConnection()
+	if err != nil {
+		t.Fatalf("establishing connection: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		group := aws.LoadBalanceCreateInput{Name: fmt.Sprintf("neuron-%d", i), VpcId: network.VpcId, Lbproto: "HTTP", LbPort: 80, Instproto: "HTTP", InstPort: 80, HealthPath: "/", HttpCode: "200"}
+		if _, err := sess.CreateTargetGroups(&group); err != nil {
+			t.Fatalf("creating target group %d: %v", i, err)
+		}
+	}
+
+	before := cloud.Calls("DescribeTargetGroups")
+	result, err := sess.DescribeAllTargetgroups(&aws.DescribeLoadbalancersInput{Pagination: aws.Pagination{PageSize: 1}})
+	if err != nil {
+		t.Fatalf("describing target groups: %v", err)
+	}
+	if len(result.TargetGroups) != 3 {
+		t.Errorf("expected all the 3 target groups, got %d", len(result.TargetGroups))
+	}
+	if calls := cloud.Calls("DescribeTargetGroups") - before; calls != 3 {
+		t.Errorf("expected a page per target group, got %d calls", calls)
+	}
+}
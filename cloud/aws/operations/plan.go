@@ -0,0 +1,1528 @@
+package aws
+
+import (
+	b64 "encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+	"github.com/nikhilsbhat/neuron-cloudy/sshkey"
+)
+
+// The methods below plan the operations of this package, they make only the calls which fetches the details from aws
+// and answers with the actions the operation would perform in the same order.
+// The actions whose request is completely known ahead are verified with aws using the DryRun of ec2,
+// the tags created along with the resources are part of the action creating the resource.
+
+// planner collects the actions of the plan.
+type planner struct {
+	sess aws.EstablishedSession
+	plan cmn.Plan
+}
+
+func newPlanner(con aws.EstablishConnectionInput) (*planner, error) {
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return nil, seserr
+	}
+	return &planner{sess: sess}, nil
+}
+
+// add appends the action to the plan, the details are passed as key value pairs.
+func (p *planner) add(operation, resource, target string, details ...string) {
+	p.plan.Actions = append(p.plan.Actions, cmn.Action{Operation: operation, Resource: resource, Target: target, Details: pairs(details)})
+}
+
+// verify appends the action to the plan once aws confirms that the request passed would be permitted.
+func (p *planner) verify(request interface{}, operation, resource, target string, details ...string) error {
+	if err := p.sess.DryRun(request); err != nil {
+		return err
+	}
+	p.plan.Actions = append(p.plan.Actions, cmn.Action{Operation: operation, Resource: resource, Target: target, Details: pairs(details), Verified: true})
+	return nil
+}
+
+func pairs(details []string) map[string]string {
+	if len(details) == 0 {
+		return nil
+	}
+	values := make(map[string]string)
+	for i := 0; i+1 < len(details); i += 2 {
+		values[details[i]] = details[i+1]
+	}
+	return values
+}
+
+// PlanCreateNetwork plans CreateNetwork, the network and its components are planned as CreateNetwork would create them.
+func (net *NetworkCreateInput) PlanCreateNetwork(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	if (net.VpcCidr == "") || (net.Name == "") {
+		return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not provided either CIDR or name for VPC, cannot proceed further")
+	}
+
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+
+	// the subnets are laid out the same way CreateNetwork does, the plan fails if their blocks does not fit in the network.
+	zonein := CommonInput{}
+	zones, zonerr := zonein.GetAvailabilityZones(con)
+	if zonerr != nil {
+		return cmn.Plan{}, zonerr
+	}
+	layout, layouterr := net.layoutSubnets(zones, []string{net.VpcCidr}, nil)
+	if layouterr != nil {
+		return cmn.Plan{}, layouterr
+	}
+
+	vpcerr := p.verify(&ec2.CreateVpcInput{CidrBlock: awssdk.String(net.VpcCidr), InstanceTenancy: awssdk.String("default")},
+		"CreateVpc", "network", net.Name, "cidr", net.VpcCidr)
+	if vpcerr != nil {
+		return cmn.Plan{}, vpcerr
+	}
+
+	switch strings.ToLower(net.Type) {
+	case "public", "":
+		if igwerr := p.verify(&ec2.CreateInternetGatewayInput{}, "CreateInternetGateway", "internetgateway", net.Name+"_igw"); igwerr != nil {
+			return cmn.Plan{}, igwerr
+		}
+		p.add("AttachInternetGateway", "internetgateway", net.Name+"_igw", "network", net.Name)
+	case "private":
+	default:
+		return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You provided unknown network type. There are two possibility, either we do not support this type else you would have misspelled")
+	}
+
+	p.add("CreateSecurityGroup", "securitygroup", net.Name+"_sec", "network", net.Name)
+	for _, port := range net.Ports {
+		p.add("AuthorizeSecurityGroupIngress", "securitygroup", net.Name+"_sec", "port", port)
+	}
+	p.add("AuthorizeSecurityGroupEgress", "securitygroup", net.Name+"_sec")
+
+	for i, sub := range layout {
+		p.planSubnet(net.Name+"_sub"+strconv.Itoa(i), sub.cidr, sub.zone, net.Name, strings.ToLower(sub.subnetType) == "public")
+	}
+	return p.plan, nil
+}
+
+// planSubnet adds the actions CreateSubnet would perform while creating the subnet in the network yet to be created.
+func (p *planner) planSubnet(name, cidr, zone, network string, public bool) {
+	p.add("CreateSubnet", "subnet", name, "cidr", cidr, "zone", zone, "network", network)
+	p.add("CreateRouteTable", "routetable", name+"_route", "network", network)
+	if public {
+		p.add("CreateRoute", "routetable", name+"_route", "destination", "0.0.0.0/0", "internetgateway", network+"_igw")
+		p.add("AssociateRouteTable", "routetable", name+"_route", "subnet", name)
+	}
+}
+
+// PlanDeleteNetwork plans DeleteNetwork, the components of the network are collected and planned in the order they would be deleted.
+// The dependents of the network are planned ahead of its components if Force is set.
+func (d *DeleteNetworkInput) PlanDeleteNetwork(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+
+	vpcin := GetNetworksInput{VpcIds: d.VpcIds}
+	vpc, vpcerr := vpcin.FindVpcs(con)
+	if vpcerr != nil {
+		return cmn.Plan{}, vpcerr
+	}
+	if vpc != true {
+		return cmn.Plan{}, &cloudyerror.Error{Code: cloudyerror.NotFound, Cloud: "aws", Kind: "network", Message: "Could not find the entered VPC, please enter valid/existing VPC id"}
+	}
+
+	if d.Force {
+		if deperr := p.planNetworkDependents(con, d.VpcIds); deperr != nil {
+			return cmn.Plan{}, deperr
+		}
+	}
+
+	deletables, delerr := d.getNetworkDeletables(con)
+	if delerr != nil {
+		return cmn.Plan{}, delerr
+	}
+
+	for _, sec := range deletables.SecIds {
+		if secerr := p.verify(&ec2.DeleteSecurityGroupInput{GroupId: awssdk.String(sec)}, "DeleteSecurityGroup", "securitygroup", sec); secerr != nil {
+			return cmn.Plan{}, secerr
+		}
+	}
+
+	if len(deletables.RouteTableIds) != 0 {
+		routes, routerr := p.sess.DescribeRouteTable(&aws.DescribeNetworkInput{RouteTableIds: deletables.RouteTableIds})
+		if routerr != nil {
+			return cmn.Plan{}, routerr
+		}
+		for _, route := range routes.RouteTables {
+			if route.Associations != nil {
+				association := *route.Associations[0].RouteTableAssociationId
+				disserr := p.verify(&ec2.DisassociateRouteTableInput{AssociationId: awssdk.String(association)},
+					"DisassociateRouteTable", "routetable", *route.RouteTableId, "association", association)
+				if disserr != nil {
+					return cmn.Plan{}, disserr
+				}
+			}
+		}
+		for _, route := range deletables.RouteTableIds {
+			if routerr := p.verify(&ec2.DeleteRouteTableInput{RouteTableId: awssdk.String(route)}, "DeleteRouteTable", "routetable", route); routerr != nil {
+				return cmn.Plan{}, routerr
+			}
+		}
+	}
+
+	for _, igw := range deletables.IgwIds {
+		deterr := p.verify(&ec2.DetachInternetGatewayInput{InternetGatewayId: awssdk.String(igw), VpcId: awssdk.String(d.VpcIds[0])},
+			"DetachInternetGateway", "internetgateway", igw, "network", d.VpcIds[0])
+		if deterr != nil {
+			return cmn.Plan{}, deterr
+		}
+	}
+	for _, igw := range deletables.IgwIds {
+		if igwerr := p.verify(&ec2.DeleteInternetGatewayInput{InternetGatewayId: awssdk.String(igw)}, "DeleteInternetGateway", "internetgateway", igw); igwerr != nil {
+			return cmn.Plan{}, igwerr
+		}
+	}
+
+	for _, subnet := range deletables.SubnetIds {
+		if suberr := p.verify(&ec2.DeleteSubnetInput{SubnetId: awssdk.String(subnet)}, "DeleteSubnet", "subnet", subnet); suberr != nil {
+			return cmn.Plan{}, suberr
+		}
+	}
+
+	for _, vpc := range d.VpcIds {
+		if vpcerr := p.verify(&ec2.DeleteVpcInput{VpcId: awssdk.String(vpc)}, "DeleteVpc", "network", vpc); vpcerr != nil {
+			return cmn.Plan{}, vpcerr
+		}
+	}
+	return p.plan, nil
+}
+
+// PlanUpdateNetwork plans UpdateNetwork, the updates supported are the same as UpdateNetwork.
+func (net *UpdateNetworkInput) PlanUpdateNetwork(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	if net.Network.VpcId == "" {
+		return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the ID of the network to be updated")
+	}
+
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+
+	var planerr error
+	switch strings.ToLower(net.Resource) + "/" + strings.ToLower(net.Action) {
+	case "subnets/create":
+		planerr = p.planCreateSubnets(con, net)
+	case "subnets/delete":
+		planerr = p.planDeleteSubnets(net)
+	case "vpc/addcidr":
+		if net.Network.VpcCidr == "" {
+			return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the CIDR block to be added to the network")
+		}
+		p.add("AssociateVpcCidrBlock", "network", net.Network.VpcId, "cidr", net.Network.VpcCidr)
+	case "vpc/removecidr":
+		association, assocerr := net.cidrAssociation(p.sess)
+		if assocerr != nil {
+			return cmn.Plan{}, assocerr
+		}
+		p.add("DisassociateVpcCidrBlock", "network", net.Network.VpcId, "cidr", net.Network.VpcCidr, "association", association)
+	case "vpc/public":
+		planerr = p.planPublic(net)
+	case "vpc/private":
+		igw, igwerr := attachedIgw(p.sess, net.Network.VpcId)
+		if igwerr != nil {
+			return cmn.Plan{}, igwerr
+		}
+		if igw != "" {
+			planerr = p.planRemoveIgw(net.Network.VpcId, igw)
+		}
+	case "igw/attach":
+		planerr = p.planAttachIgw(net)
+	case "igw/detach", "igw/replace":
+		planerr = p.planDetachIgw(net, strings.ToLower(net.Action) == "replace")
+	case "securitygroup/add", "securitygroup/remove":
+		planerr = p.planPorts(net, strings.ToLower(net.Action) == "add")
+	default:
+		return cmn.Plan{}, cloudyerror.Newf(cloudyerror.Unsupported, "Either we are not supporting the action %s of the resource %s or you entered wrong name", net.Action, net.Resource)
+	}
+	if planerr != nil {
+		return cmn.Plan{}, planerr
+	}
+	return p.plan, nil
+}
+
+// planCreateSubnets adds the actions UpdateNetwork would perform while creating the subnets in the existing network.
+func (p *planner) planCreateSubnets(con aws.EstablishConnectionInput, net *UpdateNetworkInput) error {
+
+	// the subnets are laid out and named the way UpdateNetwork does, continuing from the subnets which are already present.
+	layout, uqnchr, layouterr := net.layoutNetworkSubnets(con)
+	if layouterr != nil {
+		return layouterr
+	}
+	for _, sub := range layout {
+		name := net.Network.Name + "_sub" + strconv.Itoa(uqnchr)
+		suberr := p.verify(&ec2.CreateSubnetInput{CidrBlock: awssdk.String(sub.cidr), VpcId: awssdk.String(net.Network.VpcId), AvailabilityZone: awssdk.String(sub.zone)},
+			"CreateSubnet", "subnet", name, "cidr", sub.cidr, "zone", sub.zone, "network", net.Network.VpcId)
+		if suberr != nil {
+			return suberr
+		}
+		routerr := p.verify(&ec2.CreateRouteTableInput{VpcId: awssdk.String(net.Network.VpcId)},
+			"CreateRouteTable", "routetable", name+"_route", "network", net.Network.VpcId)
+		if routerr != nil {
+			return routerr
+		}
+		uqnchr++
+	}
+	return nil
+}
+
+// planDeleteSubnets adds the actions UpdateNetwork would perform while deleting the subnets of the network.
+func (p *planner) planDeleteSubnets(net *UpdateNetworkInput) error {
+
+	deletion, delerr := net.resolveSubnetDeletion(p.sess)
+	if delerr != nil {
+		return delerr
+	}
+	for _, association := range deletion.associations {
+		disserr := p.verify(&ec2.DisassociateRouteTableInput{AssociationId: awssdk.String(association.association)},
+			"DisassociateRouteTable", "routetable", association.routeTable, "association", association.association, "subnet", association.subnet)
+		if disserr != nil {
+			return disserr
+		}
+	}
+	for _, route := range deletion.routeTables {
+		if routerr := p.verify(&ec2.DeleteRouteTableInput{RouteTableId: awssdk.String(route)}, "DeleteRouteTable", "routetable", route); routerr != nil {
+			return routerr
+		}
+	}
+	for _, subnet := range deletion.subnets {
+		if suberr := p.verify(&ec2.DeleteSubnetInput{SubnetId: awssdk.String(subnet.Id)}, "DeleteSubnet", "subnet", subnet.Id, "name", subnet.Name); suberr != nil {
+			return suberr
+		}
+	}
+	return nil
+}
+
+// planPublic adds the actions UpdateNetwork would perform while making the subnets of the network public.
+func (p *planner) planPublic(net *UpdateNetworkInput) error {
+
+	igw, igwerr := attachedIgw(p.sess, net.Network.VpcId)
+	if igwerr != nil {
+		return igwerr
+	}
+	target := igw
+	if igw == "" {
+		if igwerr := p.planNewIgw(net); igwerr != nil {
+			return igwerr
+		}
+		target = net.Network.IgwId
+		if target == "" {
+			name, nameerr := net.networkName(p.sess)
+			if nameerr != nil {
+				return nameerr
+			}
+			target = name + "_igw"
+		}
+	}
+
+	routing, routingerr := publicRouting(p.sess, net.Network.VpcId, igw)
+	if routingerr != nil {
+		return routingerr
+	}
+	for _, subnet := range routing {
+		routetable := subnet.routeTable
+		if routetable == "" {
+			routetable = subnet.subnet.Id + "_route"
+			tableerr := p.verify(&ec2.CreateRouteTableInput{VpcId: awssdk.String(net.Network.VpcId)}, "CreateRouteTable", "routetable", routetable, "network", net.Network.VpcId)
+			if tableerr != nil {
+				return tableerr
+			}
+		}
+		switch {
+		case (subnet.route == "replace") && (igw != ""):
+			reperr := p.verify(&ec2.ReplaceRouteInput{RouteTableId: awssdk.String(routetable), DestinationCidrBlock: awssdk.String(defaultRoute), GatewayId: awssdk.String(igw)},
+				"ReplaceRoute", "routetable", routetable, "destination", defaultRoute, "internetgateway", igw)
+			if reperr != nil {
+				return reperr
+			}
+		case subnet.route == "replace":
+			p.add("ReplaceRoute", "routetable", routetable, "destination", defaultRoute, "internetgateway", target)
+		case subnet.route == "create":
+			p.add("CreateRoute", "routetable", routetable, "destination", defaultRoute, "internetgateway", target)
+		}
+		if subnet.routeTable == "" {
+			p.add("AssociateRouteTable", "routetable", routetable, "subnet", subnet.subnet.Id)
+		}
+	}
+	return nil
+}
+
+// planAttachIgw adds the actions UpdateNetwork would perform while attaching the internet gateway to the network.
+func (p *planner) planAttachIgw(net *UpdateNetworkInput) error {
+
+	current, igwerr := attachedIgw(p.sess, net.Network.VpcId)
+	if igwerr != nil {
+		return igwerr
+	}
+	if current != "" {
+		return cloudyerror.Newf(cloudyerror.Conflict, "The network %s already has the internet gateway %s attached, replace it instead", net.Network.VpcId, current)
+	}
+	return p.planNewIgw(net)
+}
+
+// planDetachIgw adds the actions UpdateNetwork would perform while removing the internet gateway of the network, or replacing it if replace is set.
+func (p *planner) planDetachIgw(net *UpdateNetworkInput, replace bool) error {
+
+	current, igwerr := attachedIgw(p.sess, net.Network.VpcId)
+	if igwerr != nil {
+		return igwerr
+	}
+	if current == "" {
+		return &cloudyerror.Error{Code: cloudyerror.NotFound, Cloud: "aws", Kind: "internetgateway", Message: fmt.Sprintf("The network %s has no internet gateway attached", net.Network.VpcId)}
+	}
+	if replace != true {
+		return p.planRemoveIgw(net.Network.VpcId, current)
+	}
+
+	routes, routerr := routesToIgw(p.sess, net.Network.VpcId, current)
+	if routerr != nil {
+		return routerr
+	}
+	deterr := p.verify(&ec2.DetachInternetGatewayInput{InternetGatewayId: awssdk.String(current), VpcId: awssdk.String(net.Network.VpcId)},
+		"DetachInternetGateway", "internetgateway", current, "network", net.Network.VpcId)
+	if deterr != nil {
+		return deterr
+	}
+	if newerr := p.planNewIgw(net); newerr != nil {
+		return newerr
+	}
+	target := net.Network.IgwId
+	if target == "" {
+		name, nameerr := net.networkName(p.sess)
+		if nameerr != nil {
+			return nameerr
+		}
+		target = name + "_igw"
+	}
+	for _, route := range routes {
+		p.add("ReplaceRoute", "routetable", route.routeTable, "destination", route.destination, "internetgateway", target)
+	}
+	return p.verify(&ec2.DeleteInternetGatewayInput{InternetGatewayId: awssdk.String(current)}, "DeleteInternetGateway", "internetgateway", current)
+}
+
+// planNewIgw adds the actions of attaching the internet gateway passed in IgwId to the network or creating a new one if none was passed.
+func (p *planner) planNewIgw(net *UpdateNetworkInput) error {
+
+	if net.Network.IgwId != "" {
+		return p.verify(&ec2.AttachInternetGatewayInput{InternetGatewayId: awssdk.String(net.Network.IgwId), VpcId: awssdk.String(net.Network.VpcId)},
+			"AttachInternetGateway", "internetgateway", net.Network.IgwId, "network", net.Network.VpcId)
+	}
+	name, nameerr := net.networkName(p.sess)
+	if nameerr != nil {
+		return nameerr
+	}
+	if igwerr := p.verify(&ec2.CreateInternetGatewayInput{}, "CreateInternetGateway", "internetgateway", name+"_igw"); igwerr != nil {
+		return igwerr
+	}
+	p.add("AttachInternetGateway", "internetgateway", name+"_igw", "network", net.Network.VpcId)
+	return nil
+}
+
+// planRemoveIgw adds the actions of removing the routes to the internet gateway followed by detaching and deleting it.
+func (p *planner) planRemoveIgw(vpc, igw string) error {
+
+	routes, routerr := routesToIgw(p.sess, vpc, igw)
+	if routerr != nil {
+		return routerr
+	}
+	for _, route := range routes {
+		delerr := p.verify(&ec2.DeleteRouteInput{RouteTableId: awssdk.String(route.routeTable), DestinationCidrBlock: awssdk.String(route.destination)},
+			"DeleteRoute", "routetable", route.routeTable, "destination", route.destination, "internetgateway", igw)
+		if delerr != nil {
+			return delerr
+		}
+	}
+	deterr := p.verify(&ec2.DetachInternetGatewayInput{InternetGatewayId: awssdk.String(igw), VpcId: awssdk.String(vpc)},
+		"DetachInternetGateway", "internetgateway", igw, "network", vpc)
+	if deterr != nil {
+		return deterr
+	}
+	return p.verify(&ec2.DeleteInternetGatewayInput{InternetGatewayId: awssdk.String(igw)}, "DeleteInternetGateway", "internetgateway", igw)
+}
+
+// planPorts adds the actions UpdateNetwork would perform while opening (open) or closing the ports on the security groups of the network.
+func (p *planner) planPorts(net *UpdateNetworkInput, open bool) error {
+
+	changes, changerr := net.resolvePorts(p.sess, open)
+	if changerr != nil {
+		return changerr
+	}
+	for _, change := range changes {
+		port := strconv.FormatInt(change.Port, 10)
+		var rulerr error
+		if open {
+			rulerr = p.verify(&ec2.AuthorizeSecurityGroupIngressInput{GroupId: awssdk.String(change.SecId), IpProtocol: awssdk.String("tcp"),
+				FromPort: awssdk.Int64(change.Port), ToPort: awssdk.Int64(change.Port), CidrIp: awssdk.String("0.0.0.0/0")},
+				"AuthorizeSecurityGroupIngress", "securitygroup", change.SecId, "port", port)
+		} else {
+			rulerr = p.verify(&ec2.RevokeSecurityGroupIngressInput{GroupId: awssdk.String(change.SecId), IpProtocol: awssdk.String("tcp"),
+				FromPort: awssdk.Int64(change.Port), ToPort: awssdk.Int64(change.Port), CidrIp: awssdk.String("0.0.0.0/0")},
+				"RevokeSecurityGroupIngress", "securitygroup", change.SecId, "port", port)
+		}
+		if rulerr != nil {
+			return rulerr
+		}
+	}
+	return nil
+}
+
+// PlanCreateServer plans CreateServer, the request to create the servers is verified with aws.
+func (csrv *CreateServerInput) PlanCreateServer(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	marketOptions, marketerr := csrv.marketOptions()
+	if marketerr != nil {
+		return cmn.Plan{}, marketerr
+	}
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+
+	subInput := GetNetworksInput{SubnetIds: []string{csrv.SubnetId}}
+	subResult, suberr := subInput.FindSubnet(con)
+	if suberr != nil {
+		return cmn.Plan{}, suberr
+	}
+	if subResult != true {
+		return cmn.Plan{}, &cloudyerror.Error{Code: cloudyerror.NotFound, Cloud: "aws", Kind: "subnet", Message: "Could not find the entered SUBNET, please enter valid/existing SUBNET id"}
+	}
+
+	mappings, mapperr := csrv.blockDeviceMappings(p.sess)
+	if mapperr != nil {
+		return cmn.Plan{}, mapperr
+	}
+
+	// the instances launched earlier with the same client token would be picked up, hence nothing is launched.
+	launched, tokenerr := csrv.launchedWithToken(p.sess)
+	if tokenerr != nil {
+		return cmn.Plan{}, tokenerr
+	}
+	if len(launched) != 0 {
+		p.add("DescribeInstances", "server", csrv.InstanceName, "clienttoken", csrv.ClientToken, "instances", strings.Join(launched, ","))
+		return p.plan, nil
+	}
+
+	inst := new(aws.CreateServerInput)
+	inst.SecurityGroups = []string{csrv.SecGroupId}
+	if csrv.SecGroupId == "" {
+		vpcRes, vpcerr := subInput.GetVpcFromSubnet(con)
+		if vpcerr != nil {
+			return cmn.Plan{}, vpcerr
+		}
+		secInput := NetworkComponentInput{VpcIds: []string{vpcRes.VpcId}}
+		secRes, secerr := secInput.GetSecFromVpc(con)
+		if secerr != nil {
+			return cmn.Plan{}, secerr
+		}
+		inst.SecurityGroups = secRes.SecGroupIds
+	}
+
+	inst.MinCount, inst.MaxCount = 1, 1
+	if csrv.MinCount != 0 {
+		inst.MinCount = csrv.MinCount
+	}
+	if csrv.MaxCount != 0 {
+		inst.MaxCount = csrv.MaxCount
+	}
+	inst.ImageId = csrv.ImageId
+	inst.InstanceType = csrv.InstanceType
+	inst.KeyName = csrv.KeyName
+	inst.AssignPubIp = csrv.AssignPubIp
+	inst.SubnetId = csrv.SubnetId
+	inst.ClientToken = csrv.ClientToken
+	inst.BlockDeviceMappings = mappings
+	inst.InstanceMarketOptions = marketOptions
+	// the key pair would be generated ahead of launching the instances, its creation is planned as an action of its own.
+	// The launch is verified without it as aws checks the key name ahead of the permissions, hence it would fail for the key pair not yet created.
+	// The name of the key pair is random unless the client token is set, hence the one planned differs from the one generated on launch.
+	keyName := csrv.KeyName
+	if csrv.generatesKeyPair() {
+		keyName = csrv.keyPairName()
+		if keyerr := p.verify(&ec2.CreateKeyPairInput{KeyName: awssdk.String(keyName)}, "CreateKeyPair", "keypair", keyName, "server", csrv.InstanceName); keyerr != nil {
+			return cmn.Plan{}, keyerr
+		}
+	}
+	// the userdata is encoded the same way CreateServer does, so that the request verified is the one which would be made.
+	inst.UserData = b64.StdEncoding.EncodeToString([]byte("echo 'nothing'"))
+	if csrv.UserData != "" {
+		inst.UserData = b64.StdEncoding.EncodeToString([]byte(csrv.UserData))
+	}
+
+	details := []string{"image", csrv.ImageId, "type", csrv.InstanceType, "subnet", csrv.SubnetId, "securitygroups", strings.Join(inst.SecurityGroups, ","),
+		"count", strconv.FormatInt(inst.MaxCount, 10), "keypair", keyName, "publicip", strconv.FormatBool(csrv.AssignPubIp), "staticpublicip", strconv.FormatBool(csrv.StaticPublicIp), "volumes", strings.Join(devices(mappings), ",")}
+	if marketOptions != nil {
+		details = append(details, "market", ec2.MarketTypeSpot, "maxprice", awssdk.StringValue(marketOptions.SpotOptions.MaxPrice),
+			"interruptionbehavior", awssdk.StringValue(marketOptions.SpotOptions.InstanceInterruptionBehavior))
+	}
+	runerr := p.verify(inst, "RunInstances", "server", csrv.InstanceName, details...)
+	if runerr != nil {
+		return cmn.Plan{}, runerr
+	}
+	// the instances are not yet launched, hence only the allocation of their elastic ips can be verified.
+	if csrv.StaticPublicIp {
+		for i := 0; i < int(inst.MaxCount); i++ {
+			name := csrv.InstanceName + "-" + strconv.Itoa(i)
+			if allocerr := p.verify(&ec2.AllocateAddressInput{Domain: awssdk.String(ec2.DomainTypeVpc)}, "AllocateAddress", "elasticip", name); allocerr != nil {
+				return cmn.Plan{}, allocerr
+			}
+			p.add("AssociateAddress", "elasticip", name, "server", name)
+		}
+	}
+	return p.plan, nil
+}
+
+// planNetworkDependents plans the deletion of the resources which lives in the networks passed, in the order DeleteNetwork deletes them when Force is set.
+// The deletion of loadbalancers, target groups and nat gateways are planned without verifying, as their apis do not support DryRun.
+func (p *planner) planNetworkDependents(con aws.EstablishConnectionInput, vpcs []string) error {
+
+	con.Resource = "elb12"
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return seserr
+	}
+
+	for _, vpc := range vpcs {
+		allocations := make([]string, 0)
+		servers, serverr := serversOfVpc(sess, vpc)
+		if serverr != nil {
+			return serverr
+		}
+		if len(servers) != 0 {
+			// the servers protected from termination are unprotected, as their termination is forced along with the network.
+			protected, proterr := protectedServers(sess, servers)
+			if proterr != nil {
+				return proterr
+			}
+			for _, server := range protected {
+				moderr := p.verify(&aws.InstanceAttributeInput{InstanceId: server, DisableApiTermination: awssdk.Bool(false)}, "ModifyInstanceAttribute", "server", server, "disableapitermination", "false")
+				if moderr != nil {
+					return moderr
+				}
+			}
+			termerr := p.verify(&ec2.TerminateInstancesInput{InstanceIds: awssdk.StringSlice(servers)}, "TerminateInstances", "server", strings.Join(servers, ","), "network", vpc)
+			if termerr != nil {
+				return termerr
+			}
+			addresses, adderr := sess.DescribeAddresses(&aws.AddressInput{Filters: aws.Filters{Name: "instance-id", Value: servers}})
+			if adderr != nil {
+				return adderr
+			}
+			for _, address := range addresses.Addresses {
+				allocations = append(allocations, *address.AllocationId)
+			}
+		}
+
+		classic, classicerr := sess.DescribeAllClassicLoadbalancer(&aws.DescribeLoadbalancersInput{})
+		if classicerr != nil {
+			return classicerr
+		}
+		for _, lb := range classic.LoadBalancerDescriptions {
+			if awssdk.StringValue(lb.VPCId) == vpc {
+				p.add("DeleteLoadBalancer", "loadbalancer", *lb.LoadBalancerName, "network", vpc)
+			}
+		}
+		application, apperr := sess.DescribeAllApplicationLoadbalancer(&aws.DescribeLoadbalancersInput{})
+		if apperr != nil {
+			return apperr
+		}
+		for _, lb := range application.LoadBalancers {
+			if awssdk.StringValue(lb.VpcId) == vpc {
+				p.add("DeleteLoadBalancer", "loadbalancer", *lb.LoadBalancerArn, "network", vpc)
+			}
+		}
+		groups, grouperr := sess.DescribeAllTargetgroups(&aws.DescribeLoadbalancersInput{})
+		if grouperr != nil {
+			return grouperr
+		}
+		for _, group := range groups.TargetGroups {
+			if awssdk.StringValue(group.VpcId) == vpc {
+				p.add("DeleteTargetGroup", "targetgroup", *group.TargetGroupArn, "network", vpc)
+			}
+		}
+
+		nats, naterr := sess.DescribeNatGateways(&aws.DescribeNetworkInput{Filters: aws.Filters{Name: "vpc-id", Value: []string{vpc}}})
+		if naterr != nil {
+			return naterr
+		}
+		for _, nat := range nats.NatGateways {
+			if awssdk.StringValue(nat.State) == ec2.NatGatewayStateDeleted {
+				continue
+			}
+			p.add("DeleteNatGateway", "natgateway", *nat.NatGatewayId, "network", vpc)
+			for _, address := range nat.NatGatewayAddresses {
+				if address.AllocationId != nil {
+					allocations = append(allocations, *address.AllocationId)
+				}
+			}
+		}
+
+		interfaces, enierr := sess.DescribeNetworkInterfaces(&aws.DescribeNetworkInput{Filters: aws.Filters{Name: "vpc-id", Value: []string{vpc}}})
+		if enierr != nil {
+			return enierr
+		}
+		for _, eni := range interfaces.NetworkInterfaces {
+			if awssdk.StringValue(eni.Status) != ec2.NetworkInterfaceStatusAvailable {
+				continue
+			}
+			if eni.Association != nil && eni.Association.AllocationId != nil {
+				allocations = append(allocations, *eni.Association.AllocationId)
+			}
+			enidelerr := p.verify(&ec2.DeleteNetworkInterfaceInput{NetworkInterfaceId: eni.NetworkInterfaceId}, "DeleteNetworkInterface", "networkinterface", *eni.NetworkInterfaceId, "network", vpc)
+			if enidelerr != nil {
+				return enidelerr
+			}
+		}
+
+		for _, allocation := range allocations {
+			if relerr := p.verify(&ec2.ReleaseAddressInput{AllocationId: awssdk.String(allocation)}, "ReleaseAddress", "elasticip", allocation); relerr != nil {
+				return relerr
+			}
+		}
+	}
+	return nil
+}
+
+// PlanDeleteServer plans DeleteServer, the request to terminate the servers is verified with aws.
+func (d *DeleteServerInput) PlanDeleteServer(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+	if searcherr := p.searchInstances(con, d.InstanceIds); searcherr != nil {
+		return cmn.Plan{}, searcherr
+	}
+	if spoterr := p.cancelSpotRequests(d.InstanceIds); spoterr != nil {
+		return cmn.Plan{}, spoterr
+	}
+
+	termerr := p.verify(&ec2.TerminateInstancesInput{InstanceIds: awssdk.StringSlice(d.InstanceIds)}, "TerminateInstances", "server", strings.Join(d.InstanceIds, ","))
+	if termerr != nil {
+		return cmn.Plan{}, termerr
+	}
+	return p.plan, nil
+}
+
+// PlanDeleteServerFromVpc plans DeleteServerFromVpc, the servers of the network are collected and the request to terminate them is verified with aws.
+func (d *DeleteServerInput) PlanDeleteServerFromVpc(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+
+	insatanceids, serverr := serversOfVpc(p.sess, d.VpcId)
+	if serverr != nil {
+		return cmn.Plan{}, serverr
+	}
+	if len(insatanceids) == 0 {
+		return p.plan, nil
+	}
+	if spoterr := p.cancelSpotRequests(insatanceids); spoterr != nil {
+		return cmn.Plan{}, spoterr
+	}
+
+	termerr := p.verify(&ec2.TerminateInstancesInput{InstanceIds: awssdk.StringSlice(insatanceids)}, "TerminateInstances", "server", strings.Join(insatanceids, ","), "network", d.VpcId)
+	if termerr != nil {
+		return cmn.Plan{}, termerr
+	}
+	return p.plan, nil
+}
+
+// cancelSpotRequests plans the cancellation of the spot requests of the instances passed, which precedes their termination.
+func (p *planner) cancelSpotRequests(instanceIds []string) error {
+	requests, err := spotRequests(p.sess, instanceIds)
+	if err != nil || len(requests) == 0 {
+		return err
+	}
+	return p.verify(&ec2.CancelSpotInstanceRequestsInput{SpotInstanceRequestIds: awssdk.StringSlice(requests)}, "CancelSpotInstanceRequests", "spotrequest", strings.Join(requests, ","),
+		"server", strings.Join(instanceIds, ","))
+}
+
+// PlanUpdateServer plans UpdateServer, the requests to update the servers are verified with aws.
+// The start of the instances stopped for the change of their type/user data is planned without verifying, as they would be running while planning.
+func (u *UpdateServerInput) PlanUpdateServer(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	action := strings.ToLower(u.Action)
+	if valerr := u.validate(action); valerr != nil {
+		return cmn.Plan{}, valerr
+	}
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+	if searcherr := p.searchInstances(con, u.InstanceIds); searcherr != nil {
+		return cmn.Plan{}, searcherr
+	}
+
+	var updateerr error
+	instances := strings.Join(u.InstanceIds, ",")
+	switch action {
+	case "start":
+		updateerr = p.verify(&ec2.StartInstancesInput{InstanceIds: awssdk.StringSlice(u.InstanceIds)}, "StartInstances", "server", instances)
+	case "stop":
+		updateerr = p.verify(&ec2.StopInstancesInput{InstanceIds: awssdk.StringSlice(u.InstanceIds)}, "StopInstances", "server", instances)
+	case "reboot":
+		updateerr = p.verify(&ec2.RebootInstancesInput{InstanceIds: awssdk.StringSlice(u.InstanceIds)}, "RebootInstances", "server", instances)
+	case "resize", "userdata":
+		updateerr = u.planModifyStopped(p)
+	case "protect", "unprotect", "securitygroups":
+		for _, id := range u.InstanceIds {
+			if updateerr = p.verify(u.attribute(id), "ModifyInstanceAttribute", "server", id, u.attributeDetails()...); updateerr != nil {
+				break
+			}
+		}
+	case "monitor":
+		updateerr = p.verify(&ec2.MonitorInstancesInput{InstanceIds: awssdk.StringSlice(u.InstanceIds)}, "MonitorInstances", "server", instances)
+	case "unmonitor":
+		updateerr = p.verify(&ec2.UnmonitorInstancesInput{InstanceIds: awssdk.StringSlice(u.InstanceIds)}, "UnmonitorInstances", "server", instances)
+	}
+	if updateerr != nil {
+		return cmn.Plan{}, updateerr
+	}
+	return p.plan, nil
+}
+
+// planModifyStopped plans the change of attribute which is made while the instances are stopped, in the order UpdateServer makes it.
+func (u *UpdateServerInput) planModifyStopped(p *planner) error {
+
+	before, beferr := serverAttributes(p.sess, u.InstanceIds, false)
+	if beferr != nil {
+		return beferr
+	}
+	running := make([]string, 0)
+	for index, id := range u.InstanceIds {
+		if before[index].State == ec2.InstanceStateNameRunning {
+			running = append(running, id)
+		}
+	}
+
+	if len(running) != 0 {
+		if stoperr := p.verify(&ec2.StopInstancesInput{InstanceIds: awssdk.StringSlice(running)}, "StopInstances", "server", strings.Join(running, ",")); stoperr != nil {
+			return stoperr
+		}
+	}
+	for _, id := range u.InstanceIds {
+		if moderr := p.verify(u.attribute(id), "ModifyInstanceAttribute", "server", id, u.attributeDetails()...); moderr != nil {
+			return moderr
+		}
+	}
+	if len(running) != 0 {
+		p.add("StartInstances", "server", strings.Join(running, ","))
+	}
+	return nil
+}
+
+// attributeDetails returns the details of the change of attribute planned, the user data is left out as it could be large.
+func (u *UpdateServerInput) attributeDetails() []string {
+	switch strings.ToLower(u.Action) {
+	case "resize":
+		return []string{"type", u.InstanceType}
+	case "securitygroups":
+		return []string{"securitygroups", strings.Join(u.SecurityGroupIds, ",")}
+	case "protect", "unprotect":
+		return []string{"disableapitermination", strconv.FormatBool(strings.ToLower(u.Action) == "protect")}
+	}
+	return nil
+}
+
+func (p *planner) searchInstances(con aws.EstablishConnectionInput, instanceIds []string) error {
+	searchInput := CommonComputeInput{InstanceIds: instanceIds}
+	search, serverr := searchInput.SearchInstance(con)
+	if serverr != nil {
+		return serverr
+	}
+	if search != true {
+		return &cloudyerror.Error{Code: cloudyerror.NotFound, Cloud: "aws", Kind: "server", Message: "Could not find the entered Instances, please enter valid/existing InstanceIds"}
+	}
+	return nil
+}
+
+// PlanCreateImage plans CreateImage, the image is named the way CreateImage names it and the request is verified with aws.
+func (img *ImageCreateInput) PlanCreateImage(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+	searchInstance := CommonComputeInput{InstanceIds: []string{img.InstanceId}}
+	instanceResult, insterr := searchInstance.SearchInstance(con)
+	if insterr != nil {
+		return cmn.Plan{}, insterr
+	}
+	if instanceResult == false {
+		return cmn.Plan{}, cloudyerror.ServerNotFound()
+	}
+
+	getInstname := DescribeInstanceInput{InstanceIds: []string{img.InstanceId}}
+	instanceName, instgeterr := getInstname.GetServersDetails(con)
+	if instgeterr != nil {
+		return cmn.Plan{}, instgeterr
+	}
+
+	result, deserr := p.sess.DescribeAllImages(&aws.DescribeComputeInput{})
+	if deserr != nil {
+		return cmn.Plan{}, deserr
+	}
+	imagenames := make([]string, 0)
+	for _, imgs := range result.Images {
+		imagenames = append(imagenames, *imgs.Name)
+	}
+	uqnin := CommonInput{SortInput: imagenames}
+	uqnchr, unerr := uqnin.GetUniqueNumberFromTags()
+	if unerr != nil {
+		return cmn.Plan{}, unerr
+	}
+
+	name := instanceName[0].InstanceName + "-snapshot-" + strconv.Itoa(uqnchr)
+	imgerr := p.verify(&aws.ImageCreateInput{InstanceId: img.InstanceId, ServerName: name, Description: "This image is captured by neuron api for " + instanceName[0].InstanceName},
+		"CreateImage", "image", name, "server", img.InstanceId)
+	if imgerr != nil {
+		return cmn.Plan{}, imgerr
+	}
+	return p.plan, nil
+}
+
+// PlanDeleteImage plans DeleteImage, the images along with their snapshots are planned in the order they would be deleted.
+func (img *DeleteImageInput) PlanDeleteImage(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+
+	searchImage := GetImageInput{ImageIds: img.ImageIds}
+	imagexists, desImgErr := searchImage.IsImageAvailable(con)
+	if desImgErr != nil {
+		return cmn.Plan{}, desImgErr
+	}
+	if imagexists != true {
+		return cmn.Plan{}, cloudyerror.ImageNotFound()
+	}
+
+	imageResult, imageErr := p.sess.DescribeImages(&aws.DescribeComputeInput{ImageIds: img.ImageIds})
+	if imageErr != nil {
+		return cmn.Plan{}, imageErr
+	}
+	for _, image := range imageResult.Images {
+		if derErr := p.verify(&ec2.DeregisterImageInput{ImageId: image.ImageId}, "DeregisterImage", "image", *image.ImageId); derErr != nil {
+			return cmn.Plan{}, derErr
+		}
+		snapshot := *image.BlockDeviceMappings[0].Ebs.SnapshotId
+		snapErr := p.verify(&ec2.DeleteSnapshotInput{SnapshotId: awssdk.String(snapshot)}, "DeleteSnapshot", "snapshot", snapshot, "image", *image.ImageId)
+		if snapErr != nil {
+			return cmn.Plan{}, snapErr
+		}
+	}
+	return p.plan, nil
+}
+
+// PlanCreateLoadBalancer plans CreateLoadBalancer, the loadbalancers does not support DryRun hence the actions are not verified.
+func (load *LoadBalanceCreateInput) PlanCreateLoadBalancer(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+
+	subnets := load.SubnetIds
+	if subnets == nil {
+		subnetsIn := GetNetworksInput{VpcIds: []string{load.VpcId}}
+		subnetsResult, suberr := subnetsIn.GetSubnetsFromVpc(con)
+		if suberr != nil {
+			return cmn.Plan{}, suberr
+		}
+		for _, subnet := range subnetsResult.Subnets {
+			subnets = append(subnets, subnet.Id)
+		}
+	}
+
+	securities := load.SecurityGroupIds
+	if securities == nil {
+		secInput := NetworkComponentInput{VpcIds: []string{load.VpcId}}
+		secResult, secerr := secInput.GetSecFromVpc(con)
+		if secerr != nil {
+			return cmn.Plan{}, secerr
+		}
+		securities = secResult.SecGroupIds
+	}
+
+	scheme := "internet-facing"
+	if load.Scheme == "internal" {
+		scheme = "internal"
+	}
+
+	switch strings.ToLower(load.Type) {
+	case "classic":
+		p.add("CreateLoadBalancer", "loadbalancer", load.Name, "type", "classic", "scheme", scheme,
+			"subnets", strings.Join(subnets, ","), "securitygroups", strings.Join(securities, ","),
+			"listener", fmt.Sprintf("%s:%d -> %s:%d", load.Lbproto, load.LbPort, load.Instproto, load.InstPort))
+		if len(load.Targets) != 0 {
+			p.add("RegisterInstancesWithLoadBalancer", "loadbalancer", load.Name, "instances", strings.Join(load.Targets, ","))
+		}
+	case "application":
+		p.add("CreateLoadBalancer", "loadbalancer", load.Name, "type", "application", "scheme", scheme,
+			"subnets", strings.Join(subnets, ","), "securitygroups", strings.Join(securities, ","))
+		p.add("CreateTargetGroup", "targetgroup", load.Name+"-target", "network", load.VpcId,
+			"protocol", load.Instproto, "port", strconv.FormatInt(load.InstPort, 10))
+		p.add("CreateListener", "listener", load.Name, "protocol", load.Lbproto, "port", strconv.FormatInt(load.LbPort, 10),
+			"targetgroup", load.Name+"-target")
+		if len(load.Targets) != 0 {
+			p.add("RegisterTargets", "targetgroup", load.Name+"-target", "instances", strings.Join(load.Targets, ","))
+		}
+	default:
+		return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You provided unknown loadbalancer type, enter a valid LB type")
+	}
+	return p.plan, nil
+}
+
+// PlanDeleteLoadbalancer plans DeleteLoadbalancer, the listeners and target groups of the application loadbalancers are planned along with it.
+func (d *DeleteLoadbalancerInput) PlanDeleteLoadbalancer(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+
+	switch strings.ToLower(d.Type) {
+	case "application":
+		if (d.LbNames != nil) && (d.LbArns != nil) {
+			return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You provided both LbNames and LbArns to fetch applicationlb data, has to provide either of them")
+		}
+		if (d.LbNames == nil) && (d.LbArns == nil) {
+			return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You selected application lb to fetch data, but I couldn't find any valid inputs. Its is empty input")
+		}
+
+		lbin := GetLoadbalancerInput{LbNames: d.LbNames, LbArns: d.LbArns}
+		if _, finderr := lbin.FindApplicationLoadbalancer(con); finderr != nil {
+			return cmn.Plan{}, finderr
+		}
+
+		lbarns := d.LbArns
+		for _, lb := range d.LbNames {
+			lbarnin := GetLoadbalancerInput{LbNames: []string{lb}}
+			lbarn, arnerr := lbarnin.GetArnFromLoadbalancer(con)
+			if arnerr != nil {
+				return cmn.Plan{}, arnerr
+			}
+			lbarns = append(lbarns, lbarn.LbArns[0])
+		}
+
+		for _, lbarn := range lbarns {
+			getlb := &aws.DescribeLoadbalancersInput{LbArns: []string{lbarn}}
+			// the listeners are deleted explicitly only when the loadbalancers are deleted by their name.
+			if d.LbNames != nil {
+				lisarn, lisarnerr := p.sess.DescribeListners(getlb)
+				if lisarnerr != nil {
+					return cmn.Plan{}, lisarnerr
+				}
+				if lisarn.Listeners != nil {
+					p.add("DeleteListener", "listener", *lisarn.Listeners[0].ListenerArn, "loadbalancer", lbarn)
+				}
+			}
+			tararn, tararnerr := p.sess.DescribeTargetgroups(getlb)
+			if tararnerr != nil {
+				return cmn.Plan{}, tararnerr
+			}
+			p.add("DeleteLoadBalancer", "loadbalancer", lbarn)
+			p.add("DeleteTargetGroup", "targetgroup", *tararn.TargetGroups[0].TargetGroupArn, "loadbalancer", lbarn)
+		}
+	case "classic":
+		if d.LbNames == nil {
+			return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You selected classic lb to fetch data, but I couldn't find any valid inputs. Its is empty input")
+		}
+		lbin := GetLoadbalancerInput{LbNames: d.LbNames}
+		if _, finderr := lbin.FindClassicLoadbalancer(con); finderr != nil {
+			return cmn.Plan{}, finderr
+		}
+		for _, lbname := range d.LbNames {
+			p.add("DeleteLoadBalancer", "loadbalancer", lbname)
+		}
+	default:
+		return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You provided empty struct to delete loadbalancer, you have to pass either Names or Arns")
+	}
+	return p.plan, nil
+}
+
+// PlanCreateSecurityGroup plans CreateSecurityGroup, the rules cannot be verified as the security group would not exist yet.
+func (sec *SecurityGroupInput) PlanCreateSecurityGroup(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	if (sec.Name == "") || (sec.VpcId == "") {
+		return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not provided either the name or the network of the security group, cannot proceed further")
+	}
+	grants, granterr := normalizeRules(sec.Rules)
+	if granterr != nil {
+		return cmn.Plan{}, granterr
+	}
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+
+	description := sec.Description
+	if description == "" {
+		description = "This security group is created by Neuron api"
+	}
+	secerr := p.verify(&ec2.CreateSecurityGroupInput{GroupName: awssdk.String(sec.Name), Description: awssdk.String(description), VpcId: awssdk.String(sec.VpcId)},
+		"CreateSecurityGroup", "securitygroup", sec.Name, "network", sec.VpcId)
+	if secerr != nil {
+		return cmn.Plan{}, secerr
+	}
+	ingress, egress := splitDirection(grants)
+	if len(ingress) != 0 {
+		p.add("AuthorizeSecurityGroupIngress", "securitygroup", sec.Name, "rules", rulesSummary(ingress))
+	}
+	if len(egress) != 0 {
+		p.add("AuthorizeSecurityGroupEgress", "securitygroup", sec.Name, "rules", rulesSummary(egress))
+	}
+	return p.plan, nil
+}
+
+// PlanUpdateSecurityGroup plans UpdateSecurityGroup, only the changes required on the rules are planned and each of them is verified with aws.
+func (sec *SecurityGroupInput) PlanUpdateSecurityGroup(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+	changes, changerr := sec.resolveRuleChanges(p.sess)
+	if changerr != nil {
+		return cmn.Plan{}, changerr
+	}
+	for _, change := range changes {
+		if planerr := p.planRuleChanges(change); planerr != nil {
+			return cmn.Plan{}, planerr
+		}
+	}
+	return p.plan, nil
+}
+
+// planRuleChanges adds the actions ruleChanges.apply would perform, in the same order.
+func (p *planner) planRuleChanges(change ruleChanges) error {
+
+	group := awssdk.String(change.group)
+	ingress, egress := splitDirection(change.authorize)
+	if len(ingress) != 0 {
+		if err := p.verify(&ec2.AuthorizeSecurityGroupIngressInput{GroupId: group, IpPermissions: grantPermissions(ingress)},
+			"AuthorizeSecurityGroupIngress", "securitygroup", change.group, "rules", rulesSummary(ingress)); err != nil {
+			return err
+		}
+	}
+	if len(egress) != 0 {
+		if err := p.verify(&ec2.AuthorizeSecurityGroupEgressInput{GroupId: group, IpPermissions: grantPermissions(egress)},
+			"AuthorizeSecurityGroupEgress", "securitygroup", change.group, "rules", rulesSummary(egress)); err != nil {
+			return err
+		}
+	}
+	ingress, egress = splitDirection(change.describe)
+	if len(ingress) != 0 {
+		if err := p.verify(&ec2.UpdateSecurityGroupRuleDescriptionsIngressInput{GroupId: group, IpPermissions: grantPermissions(ingress)},
+			"UpdateSecurityGroupRuleDescriptionsIngress", "securitygroup", change.group, "rules", rulesSummary(ingress)); err != nil {
+			return err
+		}
+	}
+	if len(egress) != 0 {
+		if err := p.verify(&ec2.UpdateSecurityGroupRuleDescriptionsEgressInput{GroupId: group, IpPermissions: grantPermissions(egress)},
+			"UpdateSecurityGroupRuleDescriptionsEgress", "securitygroup", change.group, "rules", rulesSummary(egress)); err != nil {
+			return err
+		}
+	}
+	ingress, egress = splitDirection(change.revoke)
+	if len(ingress) != 0 {
+		if err := p.verify(&ec2.RevokeSecurityGroupIngressInput{GroupId: group, IpPermissions: grantPermissions(ingress)},
+			"RevokeSecurityGroupIngress", "securitygroup", change.group, "rules", rulesSummary(ingress)); err != nil {
+			return err
+		}
+	}
+	if len(egress) != 0 {
+		if err := p.verify(&ec2.RevokeSecurityGroupEgressInput{GroupId: group, IpPermissions: grantPermissions(egress)},
+			"RevokeSecurityGroupEgress", "securitygroup", change.group, "rules", rulesSummary(egress)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PlanDeleteSecurityGroup plans DeleteSecurityGroup, the deletion of every security group is verified with aws.
+func (sec *SecurityGroupInput) PlanDeleteSecurityGroup(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	if len(sec.GroupIds) == 0 {
+		return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the security groups to be deleted")
+	}
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+	groups, geterr := p.sess.DescribeSecurityGroup(&aws.DescribeNetworkInput{SecIds: sec.GroupIds})
+	if geterr != nil {
+		return cmn.Plan{}, geterr
+	}
+	for _, group := range groups.SecurityGroups {
+		if delerr := p.verify(&ec2.DeleteSecurityGroupInput{GroupId: group.GroupId}, "DeleteSecurityGroup", "securitygroup", *group.GroupId,
+			"name", awssdk.StringValue(group.GroupName), "network", awssdk.StringValue(group.VpcId)); delerr != nil {
+			return cmn.Plan{}, delerr
+		}
+	}
+	return p.plan, nil
+}
+
+// PlanCreateVolume plans CreateVolume, the creation of the volume is verified with aws.
+func (v *VolumeInput) PlanCreateVolume(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	if verr := v.validateCreate(); verr != nil {
+		return cmn.Plan{}, verr
+	}
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+	if planerr := p.planCreateVolume(v); planerr != nil {
+		return cmn.Plan{}, planerr
+	}
+	return p.plan, nil
+}
+
+func (p *planner) planCreateVolume(v *VolumeInput) error {
+
+	input := &ec2.CreateVolumeInput{AvailabilityZone: awssdk.String(v.Zone)}
+	details := []string{"zone", v.Zone}
+	if v.Size != 0 {
+		input.Size = awssdk.Int64(v.Size)
+		details = append(details, "size", strconv.FormatInt(v.Size, 10))
+	}
+	if v.SnapshotId != "" {
+		input.SnapshotId = awssdk.String(v.SnapshotId)
+		details = append(details, "snapshot", v.SnapshotId)
+	}
+	if v.Type != "" {
+		input.VolumeType = awssdk.String(v.Type)
+		details = append(details, "type", v.Type)
+	}
+	if v.Iops != 0 {
+		input.Iops = awssdk.Int64(v.Iops)
+		details = append(details, "iops", strconv.FormatInt(v.Iops, 10))
+	}
+	if v.Encrypted {
+		input.Encrypted = awssdk.Bool(true)
+		details = append(details, "encrypted", "true")
+	}
+	if v.KmsKeyId != "" {
+		input.KmsKeyId = awssdk.String(v.KmsKeyId)
+	}
+	return p.verify(input, "CreateVolume", "volume", v.Name, details...)
+}
+
+// PlanUpdateVolume plans UpdateVolume, the Action on the volume is verified with aws.
+func (v *VolumeInput) PlanUpdateVolume(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	if len(v.VolumeIds) != 1 {
+		return cmn.Plan{}, cloudyerror.Newf(cloudyerror.InvalidInput, "You have to pass exactly one volume to be updated, got %d", len(v.VolumeIds))
+	}
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+	volume, geterr := fetchVolume(p.sess, v.VolumeIds[0])
+	if geterr != nil {
+		return cmn.Plan{}, geterr
+	}
+	id := volume.VolumeId
+
+	var updateerr error
+	switch v.Action {
+	case VolumeAttach:
+		if (v.InstanceId == "") || (v.DeviceName == "") {
+			return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not provided either the instance or the device to which the volume has to be attached")
+		}
+		updateerr = p.verify(&ec2.AttachVolumeInput{VolumeId: id, InstanceId: awssdk.String(v.InstanceId), Device: awssdk.String(v.DeviceName)},
+			"AttachVolume", "volume", *id, "server", v.InstanceId, "device", v.DeviceName)
+	case VolumeDetach:
+		if len(volume.Attachments) == 0 {
+			return cmn.Plan{}, cloudyerror.Newf(cloudyerror.InvalidInput, "volume %s is not attached to any instance", *id)
+		}
+		attachment := volume.Attachments[0]
+		updateerr = p.verify(&ec2.DetachVolumeInput{VolumeId: id, Force: awssdk.Bool(v.Force)},
+			"DetachVolume", "volume", *id, "server", awssdk.StringValue(attachment.InstanceId), "device", awssdk.StringValue(attachment.Device))
+	case VolumeResize:
+		if reserr := v.validateResize(volume); reserr != nil {
+			return cmn.Plan{}, reserr
+		}
+		input := &ec2.ModifyVolumeInput{VolumeId: id}
+		details := make([]string, 0)
+		if v.Size != 0 {
+			input.Size = awssdk.Int64(v.Size)
+			details = append(details, "size", strconv.FormatInt(v.Size, 10))
+		}
+		if v.Type != "" {
+			input.VolumeType = awssdk.String(v.Type)
+			details = append(details, "type", v.Type)
+		}
+		if v.Iops != 0 {
+			input.Iops = awssdk.Int64(v.Iops)
+			details = append(details, "iops", strconv.FormatInt(v.Iops, 10))
+		}
+		updateerr = p.verify(input, "ModifyVolume", "volume", *id, details...)
+	default:
+		return cmn.Plan{}, cloudyerror.Newf(cloudyerror.InvalidInput, "action %s is not supported on the volumes, it should be one of attach, detach or resize", v.Action)
+	}
+	if updateerr != nil {
+		return cmn.Plan{}, updateerr
+	}
+	return p.plan, nil
+}
+
+// PlanDeleteVolume plans DeleteVolume, the deletion of every volume is verified with aws.
+func (v *VolumeInput) PlanDeleteVolume(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	if len(v.VolumeIds) == 0 {
+		return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the volumes to be deleted")
+	}
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+	volumes, geterr := p.sess.DescribeVolumes(&aws.VolumeInput{VolumeIds: v.VolumeIds})
+	if geterr != nil {
+		return cmn.Plan{}, geterr
+	}
+	if attached := attachedIds(volumes.Volumes); len(attached) != 0 {
+		return cmn.Plan{}, cloudyerror.Newf(cloudyerror.InvalidInput, "volumes %v are attached to the instances, detach them before deleting", attached)
+	}
+	for _, volume := range volumes.Volumes {
+		if delerr := p.verify(&ec2.DeleteVolumeInput{VolumeId: volume.VolumeId}, "DeleteVolume", "volume", *volume.VolumeId, "name", nameOf(volume.Tags)); delerr != nil {
+			return cmn.Plan{}, delerr
+		}
+	}
+	return p.plan, nil
+}
+
+// PlanCreateSnapshot plans CreateSnapshot, the creation of the snapshot is verified with aws.
+func (s *SnapshotInput) PlanCreateSnapshot(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	if s.VolumeId == "" {
+		return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the volume of which the snapshot has to be created")
+	}
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+	input := &ec2.CreateSnapshotInput{VolumeId: awssdk.String(s.VolumeId)}
+	if s.Description != "" {
+		input.Description = awssdk.String(s.Description)
+	}
+	if snaperr := p.verify(input, "CreateSnapshot", "snapshot", s.Name, "volume", s.VolumeId); snaperr != nil {
+		return cmn.Plan{}, snaperr
+	}
+	return p.plan, nil
+}
+
+// PlanDeleteSnapshot plans DeleteSnapshot, the deletion of every snapshot is verified with aws.
+func (s *SnapshotInput) PlanDeleteSnapshot(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	if len(s.SnapshotIds) == 0 {
+		return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the snapshots to be deleted")
+	}
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+	snapshots, geterr := p.sess.DescribeSnapshots(&aws.VolumeInput{SnapshotIds: s.SnapshotIds})
+	if geterr != nil {
+		return cmn.Plan{}, geterr
+	}
+	for _, snapshot := range snapshots.Snapshots {
+		if delerr := p.verify(&ec2.DeleteSnapshotInput{SnapshotId: snapshot.SnapshotId}, "DeleteSnapshot", "snapshot", *snapshot.SnapshotId,
+			"volume", awssdk.StringValue(snapshot.VolumeId)); delerr != nil {
+			return cmn.Plan{}, delerr
+		}
+	}
+	return p.plan, nil
+}
+
+// PlanRestoreSnapshot plans RestoreSnapshot, the creation of the volume out of the snapshot is verified with aws.
+func (s *SnapshotInput) PlanRestoreSnapshot(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	if len(s.SnapshotIds) == 0 {
+		return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the snapshot to be restored")
+	}
+	volume := s.Volume
+	volume.SnapshotId = s.SnapshotIds[0]
+	return volume.PlanCreateVolume(con)
+}
+
+// PlanAllocateAddress plans AllocateAddress, the allocation and the association of the elastic ip are verified with aws.
+func (a *AddressInput) PlanAllocateAddress(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+	if allocerr := p.verify(&ec2.AllocateAddressInput{Domain: awssdk.String(ec2.DomainTypeVpc)}, "AllocateAddress", "elasticip", a.Name); allocerr != nil {
+		return cmn.Plan{}, allocerr
+	}
+	// the elastic ip is not yet allocated, hence the association cannot be verified.
+	if (a.InstanceId != "") || (a.NetworkInterfaceId != "") {
+		p.add("AssociateAddress", "elasticip", a.Name, associationDetails(a)...)
+	}
+	return p.plan, nil
+}
+
+// PlanUpdateAddress plans UpdateAddress, the Action on the elastic ip is verified with aws.
+func (a *AddressInput) PlanUpdateAddress(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	if len(a.AllocationIds) == 0 {
+		return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the elastic ip to be updated")
+	}
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+	address, geterr := fetchAddress(p.sess, a.AllocationIds[0])
+	if geterr != nil {
+		return cmn.Plan{}, geterr
+	}
+	id := address.AllocationId
+
+	var updateerr error
+	switch a.Action {
+	case AddressAssociate:
+		if (a.InstanceId == "") && (a.NetworkInterfaceId == "") {
+			return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed either the instance or the network interface with which the elastic ip has to be associated")
+		}
+		input := &ec2.AssociateAddressInput{AllocationId: id, AllowReassociation: awssdk.Bool(a.Reassociate)}
+		if a.NetworkInterfaceId != "" {
+			input.NetworkInterfaceId = awssdk.String(a.NetworkInterfaceId)
+		} else {
+			input.InstanceId = awssdk.String(a.InstanceId)
+		}
+		updateerr = p.verify(input, "AssociateAddress", "elasticip", *id, associationDetails(a)...)
+	case AddressDisassociate:
+		if address.AssociationId == nil {
+			return cmn.Plan{}, cloudyerror.Newf(cloudyerror.InvalidInput, "elastic ip %s is not associated with any instance or network interface", *id)
+		}
+		updateerr = p.verify(&ec2.DisassociateAddressInput{AssociationId: address.AssociationId}, "DisassociateAddress", "elasticip", *id,
+			"server", awssdk.StringValue(address.InstanceId), "networkinterface", awssdk.StringValue(address.NetworkInterfaceId))
+	default:
+		return cmn.Plan{}, cloudyerror.Newf(cloudyerror.InvalidInput, "action %s is not supported on the elastic ips, it should be one of associate or disassociate", a.Action)
+	}
+	if updateerr != nil {
+		return cmn.Plan{}, updateerr
+	}
+	return p.plan, nil
+}
+
+// PlanReleaseAddress plans ReleaseAddress, the release of every elastic ip is verified with aws.
+func (a *AddressInput) PlanReleaseAddress(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	if len(a.AllocationIds) == 0 {
+		return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the elastic ips to be released")
+	}
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+	addresses, geterr := p.sess.DescribeAddresses(&aws.AddressInput{AllocationIds: a.AllocationIds})
+	if geterr != nil {
+		return cmn.Plan{}, geterr
+	}
+	if associated := associatedIds(addresses.Addresses); len(associated) != 0 {
+		return cmn.Plan{}, cloudyerror.Newf(cloudyerror.InvalidInput, "elastic ips %v are associated, disassociate them before releasing", associated)
+	}
+	for _, address := range addresses.Addresses {
+		if relerr := p.verify(&ec2.ReleaseAddressInput{AllocationId: address.AllocationId}, "ReleaseAddress", "elasticip", *address.AllocationId,
+			"publicip", awssdk.StringValue(address.PublicIp)); relerr != nil {
+			return cmn.Plan{}, relerr
+		}
+	}
+	return p.plan, nil
+}
+
+// associationDetails returns the details of the instance or network interface with which the elastic ip is to be associated.
+func associationDetails(a *AddressInput) []string {
+	if a.NetworkInterfaceId != "" {
+		return []string{"networkinterface", a.NetworkInterfaceId}
+	}
+	return []string{"server", a.InstanceId}
+}
+
+// PlanCreateKeyPair plans CreateKeyPair, the creation of the key pair is verified with aws.
+func (k *KeyPairInput) PlanCreateKeyPair(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	if len(k.KeyNames) == 0 {
+		return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the name of the key pair to be created")
+	}
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+	if createrr := p.verify(&ec2.CreateKeyPairInput{KeyName: awssdk.String(k.KeyNames[0])}, "CreateKeyPair", "keypair", k.KeyNames[0]); createrr != nil {
+		return cmn.Plan{}, createrr
+	}
+	return p.plan, nil
+}
+
+// PlanImportKeyPair plans ImportKeyPair, the public key is validated and its import is verified with aws.
+func (k *KeyPairInput) PlanImportKeyPair(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	if len(k.KeyNames) == 0 {
+		return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the name of the key pair to be imported")
+	}
+	key, keyerr := sshkey.Parse(k.PublicKey)
+	if keyerr != nil {
+		return cmn.Plan{}, keyerr
+	}
+	fingerprint, fperr := sshkey.Fingerprint(key)
+	if fperr != nil {
+		return cmn.Plan{}, fperr
+	}
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+	imperr := p.verify(&ec2.ImportKeyPairInput{KeyName: awssdk.String(k.KeyNames[0]), PublicKeyMaterial: []byte(k.PublicKey)}, "ImportKeyPair", "keypair", k.KeyNames[0],
+		"fingerprint", fingerprint)
+	if imperr != nil {
+		return cmn.Plan{}, imperr
+	}
+	return p.plan, nil
+}
+
+// PlanDeleteKeyPair plans DeleteKeyPair, the deletion of every key pair is verified with aws.
+func (k *KeyPairInput) PlanDeleteKeyPair(con aws.EstablishConnectionInput) (cmn.Plan, error) {
+
+	if len(k.KeyNames) == 0 {
+		return cmn.Plan{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the key pairs to be deleted")
+	}
+	p, err := newPlanner(con)
+	if err != nil {
+		return cmn.Plan{}, err
+	}
+	pairs, geterr := p.sess.DescribeKeyPairs(&aws.KeyPairInput{KeyNames: k.KeyNames})
+	if geterr != nil {
+		return cmn.Plan{}, geterr
+	}
+	for _, pair := range pairs.KeyPairs {
+		if delerr := p.verify(&ec2.DeleteKeyPairInput{KeyName: pair.KeyName}, "DeleteKeyPair", "keypair", *pair.KeyName,
+			"fingerprint", awssdk.StringValue(pair.KeyFingerprint)); delerr != nil {
+			return cmn.Plan{}, delerr
+		}
+	}
+	return p.plan, nil
+}
@@ -0,0 +1,228 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsfake "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/fake"
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// assertPlanPerformed checks that the operations of the plan are the very calls the cloud received while performing it.
+func assertPlanPerformed(t *testing.T, plan cmn.Plan, cloud *awsfake.Cloud) {
+	t.Helper()
+	planned := make(map[string]int)
+	for _, operation := range plan.Operations() {
+		planned[operation]++
+	}
+	for operation, count := range planned {
+		if calls := cloud.Calls(operation); calls != count {
+			t.Errorf("%s is planned %d times, but was called %d times", operation, count, calls)
+		}
+	}
+}
+
+func TestPlanCreateNetwork(t *testing.T) {
+	cloud := awsfake.New()
+	network := NetworkCreateInput{
+		Name:     "neuron",
+		VpcCidr:  "10.0.0.0/16",
+		SubCidrs: []string{"10.0.1.0/24", "10.0.2.0/24"},
+		Type:     "public",
+		Ports:    []string{"22", "80"},
+	}
+	plan, err := network.PlanCreateNetwork(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("planning network: %v", err)
+	}
+
+	expected := []string{
+		"CreateVpc", "CreateInternetGateway", "AttachInternetGateway",
+		"CreateSecurityGroup", "AuthorizeSecurityGroupIngress", "AuthorizeSecurityGroupIngress", "AuthorizeSecurityGroupEgress",
+		"CreateSubnet", "CreateRouteTable", "CreateRoute", "AssociateRouteTable",
+		"CreateSubnet", "CreateRouteTable", "CreateRoute", "AssociateRouteTable",
+	}
+	if got := plan.Operations(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected plan\n got: %v\nwant: %v", got, expected)
+	}
+	if !plan.Actions[0].Verified || plan.Actions[0].Details["cidr"] != "10.0.0.0/16" {
+		t.Errorf("expected creation of network to be verified with its cidr, got %+v", plan.Actions[0])
+	}
+	if plan.Actions[7].Details["zone"] == plan.Actions[11].Details["zone"] {
+		t.Errorf("expected subnets to be spread across the zones, got %v and %v", plan.Actions[7].Details, plan.Actions[11].Details)
+	}
+
+	get := GetNetworksInput{}
+	networks, err := get.GetAllNetworks(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("fetching networks: %v", err)
+	}
+	if len(networks) != 0 {
+		t.Fatalf("expected nothing to be created while planning, got %+v", networks)
+	}
+
+	performed := awsfake.New()
+	if _, err := network.CreateNetwork(fakeConnection(performed, "ec2")); err != nil {
+		t.Fatalf("creating network: %v", err)
+	}
+	assertPlanPerformed(t, plan, performed)
+}
+
+func TestPlanCreateNetworkUnauthorized(t *testing.T) {
+	cloud := awsfake.New()
+	cloud.FailNext("CreateVpc", awserr.New("UnauthorizedOperation", "You are not authorized to perform this operation.", nil))
+
+	network := NetworkCreateInput{Name: "neuron", VpcCidr: "10.0.0.0/16"}
+	_, err := network.PlanCreateNetwork(fakeConnection(cloud, "ec2"))
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "UnauthorizedOperation" {
+		t.Fatalf("expected UnauthorizedOperation, got %v", err)
+	}
+}
+
+func TestPlanDeleteNetwork(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	del := DeleteNetworkInput{VpcIds: []string{network.VpcId}}
+	plan, err := del.PlanDeleteNetwork(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("planning deletion of network: %v", err)
+	}
+	if operations := plan.Operations(); operations[len(operations)-1] != "DeleteVpc" {
+		t.Fatalf("expected network to be deleted at last, got %v", operations)
+	}
+	for _, action := range plan.Actions {
+		if !action.Verified {
+			t.Errorf("expected %s of %s to be verified", action.Operation, action.Target)
+		}
+	}
+
+	// nothing is deleted while planning, hence the network can still be deleted as planned.
+	before := make(map[string]int)
+	for _, operation := range plan.Operations() {
+		before[operation] = cloud.Calls(operation)
+	}
+	if _, err := del.DeleteNetwork(fakeConnection(cloud, "ec2")); err != nil {
+		t.Fatalf("deleting network: %v", err)
+	}
+	planned := make(map[string]int)
+	for _, operation := range plan.Operations() {
+		planned[operation]++
+	}
+	for operation, count := range planned {
+		if calls := cloud.Calls(operation) - before[operation]; calls != count {
+			t.Errorf("%s is planned %d times, but was called %d times", operation, count, calls)
+		}
+	}
+}
+
+func TestPlanCreateAndDeleteServer(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	server := CreateServerInput{InstanceName: "neuron", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id, MaxCount: 2}
+	plan, err := server.PlanCreateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("planning server: %v", err)
+	}
+	if got := plan.Operations(); !reflect.DeepEqual(got, []string{"RunInstances"}) || !plan.Actions[0].Verified || plan.Actions[0].Details["count"] != "2" {
+		t.Fatalf("unexpected plan %+v", plan)
+	}
+
+	get := DescribeInstanceInput{VpcIds: []string{network.VpcId}}
+	servers, err := get.GetServersFromNetwork(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("fetching servers: %v", err)
+	}
+	if len(servers) != 0 {
+		t.Fatalf("expected nothing to be created while planning, got %+v", servers)
+	}
+
+	created, err := server.CreateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating server: %v", err)
+	}
+	del := DeleteServerInput{InstanceIds: []string{created[0].InstanceId}}
+	delplan, err := del.PlanDeleteServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("planning deletion of server: %v", err)
+	}
+	if got := delplan.Operations(); !reflect.DeepEqual(got, []string{"TerminateInstances"}) || delplan.Actions[0].Target != created[0].InstanceId {
+		t.Fatalf("unexpected plan %+v", delplan)
+	}
+}
+
+func TestPlanCreateServerClientToken(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	server := CreateServerInput{InstanceName: "neuron", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id, ClientToken: "neuron-create-1"}
+	created, err := server.CreateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating server: %v", err)
+	}
+
+	plan, err := server.PlanCreateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("planning server: %v", err)
+	}
+	if got := plan.Operations(); !reflect.DeepEqual(got, []string{"DescribeInstances"}) || plan.Actions[0].Details["instances"] != created[0].InstanceId {
+		t.Fatalf("expected the server created with the token to be picked up, got %+v", plan)
+	}
+}
+
+func TestPlanCreateApplicationLoadBalancer(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	create := LoadBalanceCreateInput{Name: "neuron-app", VpcId: network.VpcId, Type: "application", LbPort: 80, Lbproto: "HTTP", InstPort: 8080, Instproto: "HTTP"}
+	plan, err := create.PlanCreateLoadBalancer(fakeConnection(cloud, "elb2"))
+	if err != nil {
+		t.Fatalf("planning loadbalancer: %v", err)
+	}
+	if got := plan.Operations(); !reflect.DeepEqual(got, []string{"CreateLoadBalancer", "CreateTargetGroup", "CreateListener"}) {
+		t.Fatalf("unexpected plan %v", got)
+	}
+	if calls := cloud.Calls("CreateLoadBalancer"); calls != 0 {
+		t.Fatalf("expected nothing to be created while planning, CreateLoadBalancer was called %d times", calls)
+	}
+}
+
+func TestPlanUpdateNetwork(t *testing.T) {
+	updates := []UpdateNetworkInput{
+		{Resource: "subnets", Action: "delete", Subnets: []string{"neuron_sub1"}},
+		{Resource: "igw", Action: "replace"},
+		{Resource: "vpc", Action: "private"},
+		{Resource: "vpc", Action: "public"},
+		{Resource: "securitygroup", Action: "remove", Network: NetworkCreateInput{Ports: []string{"22", "443"}}},
+	}
+	for _, update := range updates {
+		cloud := awsfake.New()
+		network := createTestNetwork(t, cloud)
+		update.Network.VpcId = network.VpcId
+
+		plan, err := update.PlanUpdateNetwork(fakeConnection(cloud, "ec2"))
+		if err != nil {
+			t.Fatalf("planning %s/%s: %v", update.Resource, update.Action, err)
+		}
+
+		// nothing is changed while planning, hence the network can still be updated as planned.
+		before := make(map[string]int)
+		for _, operation := range plan.Operations() {
+			before[operation] = cloud.Calls(operation)
+		}
+		if _, err := update.UpdateNetwork(fakeConnection(cloud, "ec2")); err != nil {
+			t.Fatalf("updating %s/%s: %v", update.Resource, update.Action, err)
+		}
+		planned := make(map[string]int)
+		for _, operation := range plan.Operations() {
+			planned[operation]++
+		}
+		for operation, count := range planned {
+			if calls := cloud.Calls(operation) - before[operation]; calls != count {
+				t.Errorf("%s/%s: %s is planned %d times, but was called %d times", update.Resource, update.Action, operation, count, calls)
+			}
+		}
+	}
+}
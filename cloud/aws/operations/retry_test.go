@@ -0,0 +1,82 @@
+package aws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
+)
+
+const throttledResponse = `<Response><Errors><Error><Code>RequestLimitExceeded</Code><Message>Request limit exceeded.</Message></Error></Errors><RequestID>fake-request-id</RequestID></Response>`
+
+const vpcsResponse = `<DescribeVpcsResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><requestId>fake-request-id</requestId><vpcSet><item><vpcId>vpc-0123456789</vpcId><cidrBlock>10.0.0.0/16</cidrBlock></item></vpcSet></DescribeVpcsResponse>`
+
+// throttlingServer answers the requests of ec2 with RequestLimitExceeded for the number of times passed, and with a vpc later.
+func throttlingServer(throttles int, attempts *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*attempts++
+		if *attempts <= throttles {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(throttledResponse))
+			return
+		}
+		w.Write([]byte(vpcsResponse))
+	}))
+}
+
+func testSession(t *testing.T, endpoint string) *session.Session {
+	t.Helper()
+	sess, err := session.NewSession(&awssdk.Config{
+		Endpoint:    awssdk.String(endpoint),
+		Credentials: credentials.NewStaticCredentials("AKID", "SECRET", ""),
+		DisableSSL:  awssdk.Bool(true),
+	})
+	if err != nil {
+		t.Fatalf("creating session: %v", err)
+	}
+	return sess
+}
+
+func TestSessionRetriesThrottling(t *testing.T) {
+	attempts := 0
+	server := throttlingServer(2, &attempts)
+	defer server.Close()
+
+	con := aws.EstablishConnectionInput{Region: testRegion, Resource: "ec2", Session: testSession(t, server.URL), Retry: &retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond}}
+	sess, err := con.EstablishConnection()
+	if err != nil {
+		t.Fatalf("establishing connection: %v", err)
+	}
+	result, err := sess.DescribeAllVpc(&aws.DescribeNetworkInput{})
+	if err != nil {
+		t.Fatalf("expected the throttled requests to be retried, got %v", err)
+	}
+	if attempts != 3 || len(result.Vpcs) != 1 {
+		t.Errorf("expected the vpc on attempt 3, got %d vpcs after %d attempts", len(result.Vpcs), attempts)
+	}
+}
+
+func TestSessionRetryNever(t *testing.T) {
+	attempts := 0
+	server := throttlingServer(1, &attempts)
+	defer server.Close()
+
+	never := retry.Never()
+	con := aws.EstablishConnectionInput{Region: testRegion, Resource: "ec2", Session: testSession(t, server.URL), Retry: &never}
+	sess, err := con.EstablishConnection()
+	if err != nil {
+		t.Fatalf("establishing connection: %v", err)
+	}
+	if _, err := sess.DescribeAllVpc(&aws.DescribeNetworkInput{}); err == nil {
+		t.Fatalf("expected the throttling to be returned when the calls are not retried")
+	}
+	if attempts != 1 {
+		t.Errorf("expected a single attempt, got %d", attempts)
+	}
+}
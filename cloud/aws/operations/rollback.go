@@ -0,0 +1,61 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+)
+
+// RollbackResponse reports the clean up done after the creation of network failed midway.
+type RollbackResponse struct {
+	// RolledBack are the components (ex: "subnet subnet-0a1b2c") which were created and deleted back, in the order they were deleted.
+	RolledBack []string `json:"rolledback,omitempty"`
+	// Leftovers are the components which could not be deleted while rolling back along with the reason, these have to be cleaned by hand.
+	Leftovers []string `json:"leftovers,omitempty"`
+}
+
+// rollback records the compensating action of every component as it gets created,
+// so that the components created so far can be removed if the creation fails midway.
+type rollback struct {
+	steps []rollbackStep
+}
+
+type rollbackStep struct {
+	component string
+	undo      func(con aws.EstablishConnectionInput) error
+}
+
+// record adds the action which undoes the creation of the component passed, recording on nil rollback does nothing.
+// This lets the methods creating the components to be called on their own, without rolling back.
+func (r *rollback) record(resource, id string, undo func(con aws.EstablishConnectionInput) error) {
+	if r == nil {
+		return
+	}
+	r.steps = append(r.steps, rollbackStep{component: resource + " " + id, undo: undo})
+}
+
+// run undoes the recorded actions in reverse order. It does not stop at failure, instead tries the rest
+// and reports the components which could not be deleted.
+func (r *rollback) run(con aws.EstablishConnectionInput) *RollbackResponse {
+	if r == nil || len(r.steps) == 0 {
+		return nil
+	}
+
+	// the failure would have been the cancellation of the context itself, the cleanup should still be attempted.
+	if con.Context != nil && con.Context.Err() != nil {
+		con.Context = context.Background()
+	}
+
+	response := new(RollbackResponse)
+	for i := len(r.steps) - 1; i >= 0; i-- {
+		step := r.steps[i]
+		if err := step.undo(con); err != nil {
+			response.Leftovers = append(response.Leftovers, fmt.Sprintf("%s: %v", step.component, err))
+			continue
+		}
+		response.RolledBack = append(response.RolledBack, step.component)
+	}
+	r.steps = nil
+	return response
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
 )
 
 // SearchImage is tailored to find the Id's of the images, of whom's name is matched with the keyword entered.
@@ -33,7 +34,7 @@ func (i *GetImageInput) SearchImage(con aws.EstablishConnectionInput) (ImageResp
 	if imageId != nil {
 		return ImageResponse{ImageIds: imageId}, nil
 	}
-	return ImageResponse{}, fmt.Errorf("We were unable to find the image with the keyword you entered")
+	return ImageResponse{}, &cloudyerror.Error{Code: cloudyerror.NotFound, Cloud: "aws", Kind: "image", Message: "We were unable to find the image with the keyword you entered"}
 }
 
 // IsImageAvailable will check if the entered image exists in account for that particular region or not.
@@ -0,0 +1,529 @@
+package aws
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// The methods below manage the security groups on their own, along with their ingress and egress rules.
+// The rules are tracked per peer (a CIDR block or a security group) as aws does, so that the rules passed and the ones
+// present on the security groups can be compared irrespective of the way they are grouped.
+
+// SecurityGroupRule is the rule of the security group, which allows the traffic of the protocol on the ports
+// from (ingress) or to (egress) the peers: the CIDR blocks and the members of the security groups passed.
+type SecurityGroupRule struct {
+	// Direction of the traffic allowed by the rule, ingress or egress. Defaults to ingress.
+	Direction string `json:"direction,omitempty"`
+	// Protocol of the traffic allowed by the rule, tcp, udp, icmp, icmpv6, all or the number of the protocol.
+	Protocol string `json:"protocol,omitempty"`
+	// Ports is the port or the range of the ports (ex: 22, 8000-8080) of tcp/udp allowed by the rule, every port is allowed if not passed.
+	// For icmp it is the type or the type and code (ex: 8, 3-4) allowed, every type is allowed if not passed. It is not supported by the rest.
+	Ports string `json:"ports,omitempty"`
+	// Cidrs are the IPv4/IPv6 CIDR blocks from which (ingress) or to which (egress) the traffic is allowed.
+	Cidrs []string `json:"cidrs,omitempty"`
+	// SecurityGroups are the IDs of the security groups, from the members of which (ingress) or to the members of which (egress) the traffic is allowed.
+	SecurityGroups []string `json:"securitygroups,omitempty"`
+	// Description of the rule.
+	Description string `json:"description,omitempty"`
+}
+
+// SecurityGroupInput holds the values required to create/update/delete/fetch the security groups and their rules.
+type SecurityGroupInput struct {
+	// GroupIds are the IDs of the security groups to be updated/deleted/fetched.
+	GroupIds []string `json:"groupids"`
+	// VpcId is the ID of the network in which the security group has to be created, or of which the security groups has to be fetched.
+	VpcId string `json:"vpcid"`
+	// Name of the security group to be created.
+	Name string `json:"name"`
+	// Description of the security group to be created.
+	Description string `json:"description"`
+	// Rules to be added on the security group created, or the ones to be added/revoked/set on the security groups updated.
+	Rules []SecurityGroupRule `json:"rules"`
+	// Action to be performed by UpdateSecurityGroup, add or revoke the Rules or set them in place of the ones present.
+	Action string `json:"action"`
+	GetRaw bool   `json:"getraw"`
+}
+
+// grant is the rule to a single peer along with the permission of aws it is made of.
+type grant struct {
+	rule       SecurityGroupRule
+	permission *ec2.IpPermission
+}
+
+// ruleChanges are the changes made by UpdateSecurityGroup on the rules of a security group, the rules of which only the description changes are updated in place.
+type ruleChanges struct {
+	group     string
+	authorize []grant
+	describe  []grant
+	revoke    []grant
+}
+
+// CreateSecurityGroup creates the security group in the network along with the rules passed.
+// The egress rule opening all the outgoing traffic which aws adds to the security groups is left as is, the egress rules passed are added along.
+// The security group is deleted back if the rules could not be added.
+func (sec *SecurityGroupInput) CreateSecurityGroup(con aws.EstablishConnectionInput) (SecurityGroupResponse, error) {
+
+	if (sec.Name == "") || (sec.VpcId == "") {
+		return SecurityGroupResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not provided either the name or the network of the security group, cannot proceed further")
+	}
+	grants, granterr := normalizeRules(sec.Rules)
+	if granterr != nil {
+		return SecurityGroupResponse{}, granterr
+	}
+
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return SecurityGroupResponse{}, seserr
+	}
+	security, secerr := sess.CreateSecurityGroup(&aws.CreateNetworkInput{VpcId: sec.VpcId, Name: sec.Name, Description: sec.Description})
+	if secerr != nil {
+		return SecurityGroupResponse{}, secerr
+	}
+	groupID := *security.GroupId
+
+	tags := Tag{Resource: groupID, Name: "Name", Value: sec.Name}
+	if _, tagerr := tags.CreateTags(con); tagerr != nil {
+		sess.DeleteSecurityGroup(&aws.DescribeNetworkInput{SecIds: []string{groupID}})
+		return SecurityGroupResponse{}, tagerr
+	}
+	changes := ruleChanges{group: groupID, authorize: grants}
+	if ruleerr := changes.apply(sess); ruleerr != nil {
+		sess.DeleteSecurityGroup(&aws.DescribeNetworkInput{SecIds: []string{groupID}})
+		return SecurityGroupResponse{}, ruleerr
+	}
+
+	groups, geterr := sess.DescribeSecurityGroup(&aws.DescribeNetworkInput{SecIds: []string{groupID}})
+	if geterr != nil {
+		return SecurityGroupResponse{}, geterr
+	}
+	return securityGroupResponse(groups.SecurityGroups[0], sec.GetRaw), nil
+}
+
+// GetSecurityGroups fetches the security groups passed, the ones of the network passed or all the ones in the region, along with their rules.
+func (sec *SecurityGroupInput) GetSecurityGroups(con aws.EstablishConnectionInput) ([]SecurityGroupResponse, error) {
+
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return nil, seserr
+	}
+	groups, geterr := sec.describeGroups(sess)
+	if geterr != nil {
+		return nil, geterr
+	}
+	response := make([]SecurityGroupResponse, 0, len(groups))
+	for _, group := range groups {
+		response = append(response, securityGroupResponse(group, sec.GetRaw))
+	}
+	return response, nil
+}
+
+// UpdateSecurityGroup updates the rules of the security groups passed as per the Action:
+// add authorizes the Rules, revoke removes them and set replaces the rules present with the Rules.
+// As set replaces every rule, the egress rule opening all the outgoing traffic which aws adds is removed unless it is passed along.
+func (sec *SecurityGroupInput) UpdateSecurityGroup(con aws.EstablishConnectionInput) ([]SecurityGroupResponse, error) {
+
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return nil, seserr
+	}
+	changes, changerr := sec.resolveRuleChanges(sess)
+	if changerr != nil {
+		return nil, changerr
+	}
+	for _, change := range changes {
+		if applyerr := change.apply(sess); applyerr != nil {
+			return nil, applyerr
+		}
+	}
+
+	groups, geterr := sess.DescribeSecurityGroup(&aws.DescribeNetworkInput{SecIds: sec.GroupIds})
+	if geterr != nil {
+		return nil, geterr
+	}
+	response := make([]SecurityGroupResponse, 0, len(groups.SecurityGroups))
+	for _, group := range groups.SecurityGroups {
+		response = append(response, securityGroupResponse(group, sec.GetRaw))
+	}
+	return response, nil
+}
+
+// DeleteSecurityGroup deletes the security groups passed, the ones deleted before the failure are returned along with the error.
+func (sec *SecurityGroupInput) DeleteSecurityGroup(con aws.EstablishConnectionInput) ([]SecurityGroupResponse, error) {
+
+	if len(sec.GroupIds) == 0 {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the security groups to be deleted")
+	}
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return nil, seserr
+	}
+	groups, geterr := sess.DescribeSecurityGroup(&aws.DescribeNetworkInput{SecIds: sec.GroupIds})
+	if geterr != nil {
+		return nil, geterr
+	}
+
+	response := make([]SecurityGroupResponse, 0, len(groups.SecurityGroups))
+	for _, group := range groups.SecurityGroups {
+		if delerr := sess.DeleteSecurityGroup(&aws.DescribeNetworkInput{SecIds: []string{*group.GroupId}}); delerr != nil {
+			return response, delerr
+		}
+		response = append(response, SecurityGroupResponse{Id: *group.GroupId, Name: awssdk.StringValue(group.GroupName), VpcId: awssdk.StringValue(group.VpcId)})
+	}
+	return response, nil
+}
+
+// describeGroups fetches the security groups selected by GroupIds or VpcId, all the security groups are fetched if neither is passed.
+func (sec *SecurityGroupInput) describeGroups(sess aws.EstablishedSession) ([]*ec2.SecurityGroup, error) {
+	var groups *ec2.DescribeSecurityGroupsOutput
+	var err error
+	switch {
+	case len(sec.GroupIds) != 0:
+		groups, err = sess.DescribeSecurityGroup(&aws.DescribeNetworkInput{SecIds: sec.GroupIds})
+	case sec.VpcId != "":
+		groups, err = sess.DescribeSecurityGroup(&aws.DescribeNetworkInput{Filters: aws.Filters{Name: "vpc-id", Value: []string{sec.VpcId}}})
+	default:
+		groups, err = sess.DescribeAllSecurityGroup(&aws.DescribeNetworkInput{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return groups.SecurityGroups, nil
+}
+
+// resolveRuleChanges works out the changes to be made on the rules of every security group passed, as per the Action.
+// The rules which are already present are not authorized again and the ones which are missing are not revoked, so that the update can be repeated.
+func (sec *SecurityGroupInput) resolveRuleChanges(sess aws.EstablishedSession) ([]ruleChanges, error) {
+
+	if len(sec.GroupIds) == 0 {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the security groups to be updated")
+	}
+	action := strings.ToLower(sec.Action)
+	if action != "add" && action != "revoke" && action != "set" {
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The action %q is not supported on the rules of security group, it has to be one of add, revoke or set", sec.Action)
+	}
+	requested, granterr := normalizeRules(sec.Rules)
+	if granterr != nil {
+		return nil, granterr
+	}
+	groups, geterr := sess.DescribeSecurityGroup(&aws.DescribeNetworkInput{SecIds: sec.GroupIds})
+	if geterr != nil {
+		return nil, geterr
+	}
+
+	changes := make([]ruleChanges, 0, len(groups.SecurityGroups))
+	for _, group := range groups.SecurityGroups {
+		present := make(map[string]grant)
+		for _, existing := range groupGrants(group) {
+			present[existing.key()] = existing
+		}
+		change := ruleChanges{group: *group.GroupId}
+		wanted := make(map[string]bool)
+		for _, req := range requested {
+			wanted[req.key()] = true
+			existing, ok := present[req.key()]
+			switch {
+			case action == "revoke" && ok:
+				change.revoke = append(change.revoke, existing)
+			case action != "revoke" && !ok:
+				change.authorize = append(change.authorize, req)
+			case action != "revoke" && existing.rule.Description != req.rule.Description:
+				change.describe = append(change.describe, req)
+			}
+		}
+		if action == "set" {
+			for _, existing := range groupGrants(group) {
+				if !wanted[existing.key()] {
+					change.revoke = append(change.revoke, existing)
+				}
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// apply authorizes the new rules before revoking the ones replaced, so that the traffic allowed by both is not interrupted.
+func (c ruleChanges) apply(sess aws.EstablishedSession) error {
+
+	ingress, egress := splitDirection(c.authorize)
+	if len(ingress) != 0 {
+		if err := sess.AuthorizeIngress(&aws.IngressEgressInput{SecId: c.group, Permissions: grantPermissions(ingress)}); err != nil {
+			return err
+		}
+	}
+	if len(egress) != 0 {
+		if err := sess.AuthorizeEgress(&aws.IngressEgressInput{SecId: c.group, Permissions: grantPermissions(egress)}); err != nil {
+			return err
+		}
+	}
+	ingress, egress = splitDirection(c.describe)
+	if len(ingress) != 0 {
+		if err := sess.UpdateRuleDescriptions(&aws.IngressEgressInput{SecId: c.group, Permissions: grantPermissions(ingress)}, false); err != nil {
+			return err
+		}
+	}
+	if len(egress) != 0 {
+		if err := sess.UpdateRuleDescriptions(&aws.IngressEgressInput{SecId: c.group, Permissions: grantPermissions(egress)}, true); err != nil {
+			return err
+		}
+	}
+	ingress, egress = splitDirection(c.revoke)
+	if len(ingress) != 0 {
+		if err := sess.RevokeIngress(&aws.IngressEgressInput{SecId: c.group, Permissions: grantPermissions(ingress)}); err != nil {
+			return err
+		}
+	}
+	if len(egress) != 0 {
+		if err := sess.RevokeEgress(&aws.IngressEgressInput{SecId: c.group, Permissions: grantPermissions(egress)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// key identifies the rule irrespective of its description.
+func (g grant) key() string {
+	return strings.Join([]string{g.rule.Direction, g.rule.Protocol, g.rule.Ports, strings.Join(g.rule.Cidrs, ","), strings.Join(g.rule.SecurityGroups, ",")}, "|")
+}
+
+// splitDirection separates the grants passed by the direction of their rules.
+func splitDirection(grants []grant) (ingress []grant, egress []grant) {
+	for _, g := range grants {
+		if g.rule.Direction == "egress" {
+			egress = append(egress, g)
+			continue
+		}
+		ingress = append(ingress, g)
+	}
+	return ingress, egress
+}
+
+func grantPermissions(grants []grant) []*ec2.IpPermission {
+	permissions := make([]*ec2.IpPermission, 0, len(grants))
+	for _, g := range grants {
+		permissions = append(permissions, g.permission)
+	}
+	return permissions
+}
+
+// rulesSummary describes the rules of the grants in short ex: "tcp 22 from 0.0.0.0/0, all to sg-0a1b2c".
+func rulesSummary(grants []grant) string {
+	rules := make([]string, 0, len(grants))
+	for _, g := range grants {
+		peer := strings.Join(append(append([]string{}, g.rule.Cidrs...), g.rule.SecurityGroups...), ",")
+		way := "from"
+		if g.rule.Direction == "egress" {
+			way = "to"
+		}
+		rules = append(rules, strings.Join(strings.Fields(strings.Join([]string{g.rule.Protocol, g.rule.Ports, way, peer}, " ")), " "))
+	}
+	return strings.Join(rules, ", ")
+}
+
+// normalizeRules validates the rules and splits them into grants, the rules are represented the way groupGrants reports the rules of aws.
+func normalizeRules(rules []SecurityGroupRule) ([]grant, error) {
+	grants := make([]grant, 0)
+	for _, rule := range rules {
+		direction := strings.ToLower(rule.Direction)
+		if direction == "" {
+			direction = "ingress"
+		}
+		if direction != "ingress" && direction != "egress" {
+			return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The direction %q of the rule is not valid, it has to be either ingress or egress", rule.Direction)
+		}
+		protocol, from, to, porterr := protocolPorts(rule.Protocol, rule.Ports)
+		if porterr != nil {
+			return nil, porterr
+		}
+		if len(rule.Cidrs)+len(rule.SecurityGroups) == 0 {
+			return nil, cloudyerror.New(cloudyerror.InvalidInput, "The rule has neither the CIDR blocks nor the security groups to which the traffic is allowed")
+		}
+		permission := &ec2.IpPermission{IpProtocol: awssdk.String(protocol), FromPort: from, ToPort: to}
+		var description *string
+		if rule.Description != "" {
+			description = awssdk.String(rule.Description)
+		}
+		for _, cidr := range rule.Cidrs {
+			ip, block, cidrerr := net.ParseCIDR(cidr)
+			if cidrerr != nil {
+				return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The CIDR block %q of the rule is not valid", cidr)
+			}
+			if ip.To4() != nil {
+				permission.IpRanges = append(permission.IpRanges, &ec2.IpRange{CidrIp: awssdk.String(block.String()), Description: description})
+				continue
+			}
+			permission.Ipv6Ranges = append(permission.Ipv6Ranges, &ec2.Ipv6Range{CidrIpv6: awssdk.String(block.String()), Description: description})
+		}
+		for _, group := range rule.SecurityGroups {
+			permission.UserIdGroupPairs = append(permission.UserIdGroupPairs, &ec2.UserIdGroupPair{GroupId: awssdk.String(group), Description: description})
+		}
+		grants = append(grants, permissionGrants(direction, permission)...)
+	}
+	return grants, nil
+}
+
+// protocolPorts converts the protocol and the ports of the rule to the ones of the permission of aws.
+func protocolPorts(protocol, ports string) (string, *int64, *int64, error) {
+	protocol = strings.ToLower(protocol)
+	switch protocol {
+	case "all", "-1":
+		protocol = "-1"
+	case "6":
+		protocol = "tcp"
+	case "17":
+		protocol = "udp"
+	case "1":
+		protocol = "icmp"
+	case "58":
+		protocol = "icmpv6"
+	case "tcp", "udp", "icmp", "icmpv6":
+	default:
+		if number, err := strconv.Atoi(protocol); err != nil || number < 0 || number > 255 {
+			return "", nil, nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The protocol %q of the rule is not valid, it has to be one of tcp, udp, icmp, icmpv6, all or the number of the protocol", protocol)
+		}
+	}
+
+	switch protocol {
+	case "tcp", "udp":
+		if ports == "" {
+			return protocol, awssdk.Int64(0), awssdk.Int64(65535), nil
+		}
+		from, to, err := portRange(ports, 0, 65535)
+		if err != nil || from > to {
+			return "", nil, nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The ports %q of the rule are not valid, it has to be a port or a range of ports ex: 22, 8000-8080", ports)
+		}
+		return protocol, awssdk.Int64(from), awssdk.Int64(to), nil
+	case "icmp", "icmpv6":
+		if ports == "" {
+			return protocol, awssdk.Int64(-1), awssdk.Int64(-1), nil
+		}
+		icmpType, code, err := portRange(ports, 0, 255)
+		if err != nil {
+			return "", nil, nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The ports %q of the rule are not valid, for %s it has to be the type or the type and code ex: 8, 3-4", ports, protocol)
+		}
+		if !strings.Contains(ports, "-") {
+			code = -1
+		}
+		return protocol, awssdk.Int64(icmpType), awssdk.Int64(code), nil
+	}
+	if ports != "" {
+		return "", nil, nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The ports cannot be set on the rules of the protocol %q", protocol)
+	}
+	return protocol, nil, nil, nil
+}
+
+// portRange parses the port or the range of ports ex: 22, 8000-8080.
+func portRange(ports string, min, max int64) (int64, int64, error) {
+	bounds := strings.SplitN(ports, "-", 2)
+	from, err := strconv.ParseInt(bounds[0], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	to := from
+	if len(bounds) == 2 {
+		if to, err = strconv.ParseInt(bounds[1], 10, 64); err != nil {
+			return 0, 0, err
+		}
+	}
+	if from < min || from > max || to < min || to > max {
+		return 0, 0, fmt.Errorf("the ports %s are out of range", ports)
+	}
+	return from, to, nil
+}
+
+// groupGrants returns the grants of the ingress and egress rules of the security group.
+func groupGrants(group *ec2.SecurityGroup) []grant {
+	grants := make([]grant, 0)
+	for _, permission := range group.IpPermissions {
+		grants = append(grants, permissionGrants("ingress", permission)...)
+	}
+	for _, permission := range group.IpPermissionsEgress {
+		grants = append(grants, permissionGrants("egress", permission)...)
+	}
+	return grants
+}
+
+// permissionGrants splits the permission into grants, one per peer.
+func permissionGrants(direction string, permission *ec2.IpPermission) []grant {
+	protocol := strings.ToLower(awssdk.StringValue(permission.IpProtocol))
+	base := SecurityGroupRule{Direction: direction, Protocol: protocol, Ports: rulePorts(protocol, permission.FromPort, permission.ToPort)}
+	if protocol == "-1" {
+		base.Protocol = "all"
+	}
+	single := func() *ec2.IpPermission {
+		return &ec2.IpPermission{IpProtocol: permission.IpProtocol, FromPort: permission.FromPort, ToPort: permission.ToPort}
+	}
+
+	grants := make([]grant, 0)
+	for _, ipRange := range permission.IpRanges {
+		rule := base
+		rule.Cidrs, rule.Description = []string{awssdk.StringValue(ipRange.CidrIp)}, awssdk.StringValue(ipRange.Description)
+		peer := single()
+		peer.IpRanges = []*ec2.IpRange{ipRange}
+		grants = append(grants, grant{rule: rule, permission: peer})
+	}
+	for _, ipRange := range permission.Ipv6Ranges {
+		rule := base
+		rule.Cidrs, rule.Description = []string{awssdk.StringValue(ipRange.CidrIpv6)}, awssdk.StringValue(ipRange.Description)
+		peer := single()
+		peer.Ipv6Ranges = []*ec2.Ipv6Range{ipRange}
+		grants = append(grants, grant{rule: rule, permission: peer})
+	}
+	for _, pair := range permission.UserIdGroupPairs {
+		rule := base
+		rule.SecurityGroups, rule.Description = []string{awssdk.StringValue(pair.GroupId)}, awssdk.StringValue(pair.Description)
+		peer := single()
+		peer.UserIdGroupPairs = []*ec2.UserIdGroupPair{{GroupId: pair.GroupId, Description: pair.Description}}
+		grants = append(grants, grant{rule: rule, permission: peer})
+	}
+	return grants
+}
+
+// rulePorts returns the ports of the permission in the form of SecurityGroupRule.Ports.
+func rulePorts(protocol string, fromPort, toPort *int64) string {
+	from, to := awssdk.Int64Value(fromPort), awssdk.Int64Value(toPort)
+	switch protocol {
+	case "tcp", "udp", "6", "17":
+		if from == 0 && to == 65535 {
+			return ""
+		}
+	case "icmp", "icmpv6", "1", "58":
+		if from == -1 {
+			return ""
+		}
+		if to == -1 {
+			return strconv.FormatInt(from, 10)
+		}
+	default:
+		return ""
+	}
+	if from == to {
+		return strconv.FormatInt(from, 10)
+	}
+	return strconv.FormatInt(from, 10) + "-" + strconv.FormatInt(to, 10)
+}
+
+// securityGroupResponse returns the details of the security group along with its rules, the unfiltered response of aws is returned if raw is set.
+func securityGroupResponse(group *ec2.SecurityGroup, raw bool) SecurityGroupResponse {
+	if raw {
+		return SecurityGroupResponse{SecurityGroupRaw: group}
+	}
+	response := SecurityGroupResponse{
+		Id:          awssdk.StringValue(group.GroupId),
+		Name:        awssdk.StringValue(group.GroupName),
+		VpcId:       awssdk.StringValue(group.VpcId),
+		Description: awssdk.StringValue(group.Description),
+		Ports:       SecurityGroupPorts(group.IpPermissions),
+	}
+	for _, g := range groupGrants(group) {
+		response.Rules = append(response.Rules, g.rule)
+	}
+	return response
+}
@@ -0,0 +1,233 @@
+package aws
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsfake "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/fake"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// ruleSet returns the rules passed in the form "direction protocol ports peer description", sorted.
+func ruleSet(rules []SecurityGroupRule) []string {
+	set := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		set = append(set, rulesSummary([]grant{{rule: rule}})+" "+rule.Direction+" "+rule.Description)
+	}
+	sort.Strings(set)
+	return set
+}
+
+// createTestSecurityGroup creates the security group web in the network passed, which allows ssh from the security group of the network.
+func createTestSecurityGroup(t *testing.T, cloud *awsfake.Cloud, network NetworkResponse) SecurityGroupResponse {
+	t.Helper()
+	create := SecurityGroupInput{
+		VpcId: network.VpcId, Name: "web", Description: "web servers",
+		Rules: []SecurityGroupRule{
+			{Protocol: "tcp", Ports: "443", Cidrs: []string{"0.0.0.0/0", "::/0"}, Description: "https"},
+			{Protocol: "TCP", Ports: "22-22", SecurityGroups: []string{network.SecGroupIds[0]}},
+			{Protocol: "icmp", Cidrs: []string{"10.0.0.7/16"}},
+			{Direction: "egress", Protocol: "udp", Ports: "53", Cidrs: []string{"10.0.0.2/32"}},
+		},
+	}
+	group, err := create.CreateSecurityGroup(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating security group: %v", err)
+	}
+	return group
+}
+
+func TestCreateSecurityGroup(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+	group := createTestSecurityGroup(t, cloud, network)
+
+	if group.Id == "" || group.Name != "web" || group.VpcId != network.VpcId || group.Description != "web servers" {
+		t.Fatalf("expected the security group web in %s, got %+v", network.VpcId, group)
+	}
+	expected := []string{
+		"all to 0.0.0.0/0 egress ",
+		"icmp from 10.0.0.0/16 ingress ",
+		"tcp 22 from " + network.SecGroupIds[0] + " ingress ",
+		"tcp 443 from 0.0.0.0/0 ingress https",
+		"tcp 443 from ::/0 ingress https",
+		"udp 53 to 10.0.0.2/32 egress ",
+	}
+	if rules := ruleSet(group.Rules); !reflect.DeepEqual(rules, expected) {
+		t.Errorf("expected the rules %v, got %v", expected, rules)
+	}
+
+	get := SecurityGroupInput{VpcId: network.VpcId}
+	groups, err := get.GetSecurityGroups(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("fetching security groups: %v", err)
+	}
+	// the default security group and the one of the network are fetched along.
+	if len(groups) != 3 {
+		t.Errorf("expected 3 security groups in the network, got %+v", groups)
+	}
+}
+
+func TestCreateSecurityGroupInvalidRules(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	invalid := [][]SecurityGroupRule{
+		{{Protocol: "ssh", Cidrs: []string{"0.0.0.0/0"}}},
+		{{Protocol: "tcp", Ports: "8080-80", Cidrs: []string{"0.0.0.0/0"}}},
+		{{Protocol: "all", Ports: "22", Cidrs: []string{"0.0.0.0/0"}}},
+		{{Protocol: "tcp", Ports: "22", Cidrs: []string{"10.0.0.300/8"}}},
+		{{Protocol: "tcp", Ports: "22"}},
+		{{Direction: "inbound", Protocol: "tcp", Ports: "22", Cidrs: []string{"0.0.0.0/0"}}},
+	}
+	for _, rules := range invalid {
+		create := SecurityGroupInput{VpcId: network.VpcId, Name: "web", Rules: rules}
+		if _, err := create.CreateSecurityGroup(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+			t.Errorf("expected invalid input for the rules %+v, got %v", rules, err)
+		}
+	}
+	// the network creates one, the rules are validated before creating the security group.
+	if calls := cloud.Calls("CreateSecurityGroup"); calls != 1 {
+		t.Errorf("expected no security group to be created for the invalid rules, got %d calls", calls)
+	}
+}
+
+func TestCreateSecurityGroupDeletedOnFailure(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+	cloud.FailNext("AuthorizeSecurityGroupIngress", awserr.New("InvalidGroup.NotFound", "The security group does not exist", nil))
+
+	create := SecurityGroupInput{VpcId: network.VpcId, Name: "web", Rules: []SecurityGroupRule{{Protocol: "tcp", Ports: "80", Cidrs: []string{"0.0.0.0/0"}}}}
+	if _, err := create.CreateSecurityGroup(fakeConnection(cloud, "ec2")); err == nil {
+		t.Fatalf("expected the creation of security group to fail")
+	}
+	get := SecurityGroupInput{VpcId: network.VpcId}
+	groups, err := get.GetSecurityGroups(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("fetching security groups: %v", err)
+	}
+	for _, group := range groups {
+		if group.Name == "web" {
+			t.Errorf("expected the security group to be deleted back, got %+v", group)
+		}
+	}
+}
+
+func TestUpdateSecurityGroup(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+	group := createTestSecurityGroup(t, cloud, network)
+	con := fakeConnection(cloud, "ec2")
+
+	// the rules present are neither authorized again nor failed as duplicates, only their descriptions are updated.
+	add := SecurityGroupInput{GroupIds: []string{group.Id}, Action: "add", Rules: []SecurityGroupRule{
+		{Protocol: "tcp", Ports: "443", Cidrs: []string{"0.0.0.0/0"}, Description: "public https"},
+		{Protocol: "udp", Ports: "8000-8100", Cidrs: []string{"2001:db8::/32"}},
+	}}
+	authorized := cloud.Calls("AuthorizeSecurityGroupIngress")
+	updated, err := add.UpdateSecurityGroup(con)
+	if err != nil {
+		t.Fatalf("adding rules: %v", err)
+	}
+	if calls := cloud.Calls("AuthorizeSecurityGroupIngress") - authorized; calls != 1 {
+		t.Errorf("expected the rules to be authorized in a single call, got %d", calls)
+	}
+	rules := make(map[string]bool)
+	for _, rule := range ruleSet(updated[0].Rules) {
+		rules[rule] = true
+	}
+	for _, rule := range []string{"tcp 443 from 0.0.0.0/0 ingress public https", "tcp 443 from ::/0 ingress https", "udp 8000-8100 from 2001:db8::/32 ingress "} {
+		if !rules[rule] {
+			t.Errorf("expected the rule %q in %v", rule, ruleSet(updated[0].Rules))
+		}
+	}
+
+	revoke := SecurityGroupInput{GroupIds: []string{group.Id}, Action: "revoke", Rules: []SecurityGroupRule{
+		{Protocol: "tcp", Ports: "443", Cidrs: []string{"0.0.0.0/0", "::/0"}},
+		{Protocol: "tcp", Ports: "3306", Cidrs: []string{"0.0.0.0/0"}},
+	}}
+	updated, err = revoke.UpdateSecurityGroup(con)
+	if err != nil {
+		t.Fatalf("revoking rules: %v", err)
+	}
+	for _, rule := range updated[0].Rules {
+		if rule.Ports == "443" {
+			t.Errorf("expected the rules of port 443 to be revoked, got %+v", rule)
+		}
+	}
+
+	set := SecurityGroupInput{GroupIds: []string{group.Id}, Action: "set", Rules: []SecurityGroupRule{
+		{Protocol: "tcp", Ports: "22", SecurityGroups: []string{network.SecGroupIds[0]}, Description: "ssh"},
+		{Direction: "egress", Protocol: "all", Cidrs: []string{"10.0.0.0/16"}},
+	}}
+	updated, err = set.UpdateSecurityGroup(con)
+	if err != nil {
+		t.Fatalf("setting rules: %v", err)
+	}
+	expected := []string{"all to 10.0.0.0/16 egress ", "tcp 22 from " + network.SecGroupIds[0] + " ingress ssh"}
+	if rules := ruleSet(updated[0].Rules); !reflect.DeepEqual(rules, expected) {
+		t.Errorf("expected the rules to be replaced with %v, got %v", expected, rules)
+	}
+
+	set.Action = "replace"
+	if _, err := set.UpdateSecurityGroup(con); !cloudyerror.IsInvalidInput(err) {
+		t.Errorf("expected invalid input for the action replace, got %v", err)
+	}
+}
+
+func TestPlanUpdateSecurityGroup(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+	group := createTestSecurityGroup(t, cloud, network)
+
+	update := SecurityGroupInput{GroupIds: []string{group.Id}, Action: "set", Rules: []SecurityGroupRule{
+		{Protocol: "tcp", Ports: "443", Cidrs: []string{"0.0.0.0/0", "::/0"}, Description: "https only"},
+		{Protocol: "tcp", Ports: "80", Cidrs: []string{"0.0.0.0/0"}},
+	}}
+	plan, err := update.PlanUpdateSecurityGroup(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("planning update of security group: %v", err)
+	}
+	expected := []string{"AuthorizeSecurityGroupIngress", "UpdateSecurityGroupRuleDescriptionsIngress", "RevokeSecurityGroupIngress", "RevokeSecurityGroupEgress"}
+	if operations := plan.Operations(); !reflect.DeepEqual(operations, expected) {
+		t.Fatalf("expected the operations %v, got %v", expected, operations)
+	}
+
+	// nothing is changed while planning, hence the security group can still be updated as planned.
+	before := make(map[string]int)
+	for _, operation := range expected {
+		before[operation] = cloud.Calls(operation)
+	}
+	if _, err := update.UpdateSecurityGroup(fakeConnection(cloud, "ec2")); err != nil {
+		t.Fatalf("updating security group: %v", err)
+	}
+	for _, operation := range expected {
+		if calls := cloud.Calls(operation) - before[operation]; calls != 1 {
+			t.Errorf("%s is planned once, but was called %d times", operation, calls)
+		}
+	}
+}
+
+func TestDeleteSecurityGroup(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+	group := createTestSecurityGroup(t, cloud, network)
+	con := fakeConnection(cloud, "ec2")
+
+	// the security group of the network is referred by the rules of web.
+	referred := SecurityGroupInput{GroupIds: []string{network.SecGroupIds[0]}}
+	if _, err := referred.DeleteSecurityGroup(con); !cloudyerror.IsConflict(cloudyerror.FromAWS(err, "securitygroup", "")) {
+		t.Fatalf("expected conflict while deleting the security group referred by other, got %v", err)
+	}
+
+	del := SecurityGroupInput{GroupIds: []string{group.Id, network.SecGroupIds[0]}}
+	deleted, err := del.DeleteSecurityGroup(con)
+	if err != nil {
+		t.Fatalf("deleting security groups: %v", err)
+	}
+	if len(deleted) != 2 || deleted[0].Id != group.Id || deleted[0].Name != "web" {
+		t.Errorf("expected both the security groups to be deleted, got %+v", deleted)
+	}
+}
@@ -41,7 +41,12 @@ func (des *CommonComputeInput) SearchInstance(con aws.EstablishConnectionInput)
 		if err != nil {
 			return false, err
 		}
-		if len(result.Reservations) == len(des.InstanceIds) {
+		// instances launched together share the reservation, hence instances are counted instead of reservations.
+		instances := 0
+		for _, reservation := range result.Reservations {
+			instances = instances + len(reservation.Instances)
+		}
+		if instances == len(des.InstanceIds) {
 			return true, nil
 		}
 		return false, fmt.Errorf("Failed to fetch the data of all instances you entered, found multiple entries of same values")
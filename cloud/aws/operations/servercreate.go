@@ -1,12 +1,17 @@
 package aws
 
 import (
+	"crypto/rand"
 	b64 "encoding/base64"
-	"fmt"
+	"encoding/hex"
 	"strconv"
+	"strings"
+	"time"
 
+	awssdk "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
 )
 
 // CreateServerInput will implement the methods for creating instances and holds the value for the same.
@@ -19,6 +24,10 @@ type CreateServerInput struct {
 	InstanceType string
 	// KeyName refers to the name of the key value pair which has to be assossiated with the Instance provisioned.
 	KeyName string
+	// GenerateKeyPair creates a key pair named after the instance when KeyName is not passed, and launches the instances with it.
+	// The name is suffixed with the ClientToken, or a random one if the token is not set, so that it does not clash with the key pairs of other servers of the same name.
+	// The private key of the key pair is returned along with the servers, it is not retained by aws hence it cannot be fetched again.
+	GenerateKeyPair bool
 	// MaxCount defines the maximum number of instances that has to be provisioned.
 	MaxCount int64
 	// MinCount defines the minimum number of instances that has to be provisioned it defaults to 1.
@@ -31,7 +40,23 @@ type CreateServerInput struct {
 	UserData string
 	// AssignPubIp assignes public IP to VMs if it is set. This makes instance opened to world.
 	AssignPubIp bool
-	GetRaw      bool
+	// StaticPublicIp allocates an elastic ip for each of the instance and associates it with the instance once it is running.
+	// Unlike the one assigned by AssignPubIp, the elastic ip does not change on the stop/start of the instance and is not released along with the instance.
+	StaticPublicIp bool
+	// ClientToken makes the creation idempotent, the instances launched earlier with the same token are returned in place of launching new ones.
+	// This makes it safe to call CreateServer again with the same token when the earlier call failed midway (ex: network blip after RunInstances).
+	ClientToken string
+	// Volumes are the ebs volumes to be created and attached to each instance, the root volume of the image is overridden by the one marked Root.
+	// The instances are created with the volumes of the image if none are passed.
+	Volumes []ServerVolume
+	// MarketType is the market in which the instances are launched, on-demand (default) or spot.
+	MarketType string
+	// MaxPrice is the maximum hourly price in USD paid for the spot instances ex: 0.05, it defaults to the on-demand price of the InstanceType.
+	MaxPrice string
+	// InterruptionBehavior is what aws does with the spot instances when it takes back the capacity: terminate (default), stop or hibernate.
+	// The spot instances to be stopped/hibernated are requested persistently, hence they are started back once the capacity is available.
+	InterruptionBehavior string
+	GetRaw               bool
 }
 
 // ServerResponse holds the filtered/unfiltered output of CreateServer from aws.
@@ -46,8 +71,18 @@ type ServerResponse struct {
 	PrivateIpAddress string `json:"IpAddress,omitempty"`
 	// PublicIpAddress holds the public IP address assigned to the instance.
 	PublicIpAddress string `json:"PublicIpAddress,omitempty"`
+	// AllocationId is the ID of the elastic ip associated with the instance, it is set only when the instance is created with StaticPublicIp.
+	AllocationId string `json:"AllocationId,omitempty"`
 	// PrivateDnsName holds the private DNS assigned to the instance.
 	PrivateDnsName string `json:"PrivateDnsName,omitempty"`
+	// KeyName is the name of the key pair with which the instance is launched.
+	KeyName string `json:"KeyName,omitempty"`
+	// PrivateKey is the private key of the key pair generated for the instance, it is set only by the CreateServer which generated it.
+	PrivateKey string `json:"PrivateKey,omitempty"`
+	// Lifecycle is spot for the spot instances, it is empty for the ones on-demand.
+	Lifecycle string `json:"Lifecycle,omitempty"`
+	// SpotInstanceRequestId is the ID of the request with which the spot instance is launched.
+	SpotInstanceRequestId string `json:"SpotInstanceRequestId,omitempty"`
 	// CreatedOn holds the information on the time when the instance was created.
 	CreatedOn string `json:"CreatedOn,omitempty"`
 	// State of the instance created/deleted/updated/retrieved.
@@ -63,7 +98,15 @@ type ServerResponse struct {
 	// PreviousState defines the state of instance prior to which information is retrieved.
 	PreviousState string `json:"PreviousState,omitempty"`
 	// CurrentState of the instance of which information is retrieved.
-	CurrentState    string                        `json:"CurrentState,omitempty"`
+	CurrentState string `json:"CurrentState,omitempty"`
+	// Before holds the attributes of the instance prior to the update, it is set only by UpdateServer.
+	Before *ServerAttributes `json:"Before,omitempty"`
+	// After holds the attributes of the instance once the update is done (or rolled back), it is set only by UpdateServer.
+	After *ServerAttributes `json:"After,omitempty"`
+	// Rollback reports the steps undone when the update of the instances failed midway.
+	Rollback *RollbackResponse `json:"Rollback,omitempty"`
+	// Volumes are the ebs volumes attached to the instance.
+	Volumes         []AttachedVolume              `json:"Volumes,omitempty"`
 	DefaultResponse interface{}                   `json:"DefaultResponse,omitempty"`
 	Error           error                         `json:"Error,omitempty"`
 	CreateInstRaw   *ec2.DescribeInstancesOutput  `json:"CreateInstRaw,omitempty"`
@@ -76,8 +119,17 @@ type ServerResponse struct {
 }
 
 // CreateServer will help in creating instances/vms with the configuration passed.
+// On failure after the instances are launched, their IDs are returned along with the error with the elastic ips associated with them
+// and the key pair generated for them, so that neither the instances nor the private key are lost.
+// The key pair generated is deleted if the launch fails, unless aws launched the instances with the client token even so.
 func (csrv *CreateServerInput) CreateServer(con aws.EstablishConnectionInput) ([]ServerResponse, error) {
 
+	// the market options are validated upfront, as they are not known to aws until the instances are launched.
+	marketOptions, marketerr := csrv.marketOptions()
+	if marketerr != nil {
+		return nil, marketerr
+	}
+
 	//get the relative sessions before proceeding further
 	ec2, sesserr := con.EstablishConnection()
 	if sesserr != nil {
@@ -92,7 +144,7 @@ func (csrv *CreateServerInput) CreateServer(con aws.EstablishConnectionInput) ([
 	}
 
 	if subResult != true {
-		return nil, fmt.Errorf("Could not find the entered SUBNET, please enter valid/existing SUBNET id")
+		return nil, &cloudyerror.Error{Code: cloudyerror.NotFound, Cloud: "aws", Kind: "subnet", Message: "Could not find the entered SUBNET, please enter valid/existing SUBNET id"}
 	}
 
 	inst := new(aws.CreateServerInput)
@@ -107,7 +159,7 @@ func (csrv *CreateServerInput) CreateServer(con aws.EstablishConnectionInput) ([
 		secInput := NetworkComponentInput{VpcIds: []string{vpcRes.VpcId}}
 		secRes, secerr := secInput.GetSecFromVpc(con)
 		if secerr != nil {
-			return nil, nil
+			return nil, secerr
 		}
 		inst.SecurityGroups = secRes.SecGroupIds
 
@@ -142,16 +194,39 @@ func (csrv *CreateServerInput) CreateServer(con aws.EstablishConnectionInput) ([
 	inst.KeyName = csrv.KeyName
 	inst.AssignPubIp = csrv.AssignPubIp
 	inst.SubnetId = csrv.SubnetId
-	// support for custom ebs mapping will be rolled out soon
-	serverCreateResult, err := ec2.CreateInstance(inst)
+	inst.ClientToken = csrv.ClientToken
+	inst.InstanceMarketOptions = marketOptions
 
-	if err != nil {
-		return nil, err
+	// the volumes are validated upfront, so that an invalid one does not fail the creation after the instances are launched.
+	mappings, mapperr := csrv.blockDeviceMappings(ec2)
+	if mapperr != nil {
+		return nil, mapperr
 	}
+	inst.BlockDeviceMappings = mappings
 
-	instanceIds := make([]string, 0)
-	for _, instance := range serverCreateResult.Instances {
-		instanceIds = append(instanceIds, *instance.InstanceId)
+	// the instances launched earlier with the same client token are picked up in place of launching new ones.
+	instanceIds, tokenerr := csrv.launchedWithToken(ec2)
+	if tokenerr != nil {
+		return nil, tokenerr
+	}
+	privateKey := ""
+	if len(instanceIds) == 0 {
+		// the key pair is generated only when the instances are to be launched, the ones picked up by the client token
+		// were launched with the key pair generated by the earlier call.
+		if csrv.generatesKeyPair() {
+			pair, keyerr := csrv.generateKeyPair(ec2)
+			if keyerr != nil {
+				return nil, keyerr
+			}
+			inst.KeyName, privateKey = pair.Name, pair.PrivateKey
+		}
+		serverCreateResult, err := ec2.CreateInstance(inst)
+		if err != nil {
+			return csrv.launchFailed(ec2, inst.KeyName, privateKey, err)
+		}
+		for _, instance := range serverCreateResult.Instances {
+			instanceIds = append(instanceIds, *instance.InstanceId)
+		}
 	}
 
 	// I will make program wait until instance become running
@@ -161,7 +236,7 @@ func (csrv *CreateServerInput) CreateServer(con aws.EstablishConnectionInput) ([
 		},
 	)
 	if waitErr != nil {
-		return nil, waitErr
+		return csrv.launched(instanceIds, nil, inst.KeyName, privateKey), waitErr
 	}
 
 	// creating tags for the server
@@ -172,7 +247,16 @@ func (csrv *CreateServerInput) CreateServer(con aws.EstablishConnectionInput) ([
 		tags.Value = csrv.InstanceName + "-" + strconv.Itoa(i)
 		_, tagErr := tags.CreateTags(con)
 		if tagErr != nil {
-			return nil, tagErr
+			return csrv.launched(instanceIds, nil, inst.KeyName, privateKey), tagErr
+		}
+	}
+
+	// the elastic ips are associated only after the instances are running, as aws does not accept them for the instances which are pending.
+	allocations := make(map[string]string)
+	if csrv.StaticPublicIp {
+		var adderr error
+		if allocations, adderr = csrv.associateStaticIps(con, ec2, instanceIds); adderr != nil {
+			return csrv.launched(instanceIds, allocations, inst.KeyName, privateKey), adderr
 		}
 	}
 
@@ -183,7 +267,7 @@ func (csrv *CreateServerInput) CreateServer(con aws.EstablishConnectionInput) ([
 		},
 	)
 	if serverr != nil {
-		return nil, serverr
+		return csrv.launched(instanceIds, allocations, inst.KeyName, privateKey), serverr
 	}
 
 	type serverResponse struct {
@@ -192,31 +276,209 @@ func (csrv *CreateServerInput) CreateServer(con aws.EstablishConnectionInput) ([
 		ipaddress  string
 		privatedns string
 		publicIp   string
+		keyname    string
 		createdon  string
+		lifecycle  string
+		spotid     string
+	}
+
+	volumes, volerr := attachedVolumes(ec2, result.Reservations)
+	if volerr != nil {
+		return csrv.launched(instanceIds, allocations, inst.KeyName, privateKey), volerr
 	}
 
 	response := make([]serverResponse, 0)
 	createServerResponse := make([]ServerResponse, 0)
 
 	if csrv.GetRaw == true {
-		createServerResponse = append(createServerResponse, ServerResponse{CreateInstRaw: result, Cloud: "Amazon"})
+		createServerResponse = append(createServerResponse, ServerResponse{CreateInstRaw: result, KeyName: inst.KeyName, PrivateKey: privateKey, Cloud: "Amazon"})
 		return createServerResponse, nil
 	}
 
 	// fetching the instance details which is created in previous process
 	for _, reservation := range result.Reservations {
 		for _, instance := range reservation.Instances {
-			if csrv.AssignPubIp == true {
-				response = append(response, serverResponse{name: *instance.Tags[0].Value, instanceId: *instance.InstanceId, ipaddress: *instance.PrivateIpAddress, privatedns: *instance.PrivateDnsName, publicIp: *instance.PublicIpAddress, createdon: (*instance.LaunchTime).String()})
+			if (csrv.AssignPubIp == true) || (csrv.StaticPublicIp == true) {
+				response = append(response, serverResponse{name: *instance.Tags[0].Value, instanceId: *instance.InstanceId, ipaddress: *instance.PrivateIpAddress, privatedns: *instance.PrivateDnsName, publicIp: *instance.PublicIpAddress, keyname: awssdk.StringValue(instance.KeyName), createdon: (*instance.LaunchTime).String(),
+					lifecycle: awssdk.StringValue(instance.InstanceLifecycle), spotid: awssdk.StringValue(instance.SpotInstanceRequestId)})
 			} else {
-				response = append(response, serverResponse{name: *instance.Tags[0].Value, instanceId: *instance.InstanceId, ipaddress: *instance.PrivateIpAddress, privatedns: *instance.PrivateDnsName, createdon: (*instance.LaunchTime).String()})
+				response = append(response, serverResponse{name: *instance.Tags[0].Value, instanceId: *instance.InstanceId, ipaddress: *instance.PrivateIpAddress, privatedns: *instance.PrivateDnsName, keyname: awssdk.StringValue(instance.KeyName), createdon: (*instance.LaunchTime).String(),
+					lifecycle: awssdk.StringValue(instance.InstanceLifecycle), spotid: awssdk.StringValue(instance.SpotInstanceRequestId)})
 			}
 		}
 	}
 
 	for _, server := range response {
-		createServerResponse = append(createServerResponse, ServerResponse{InstanceName: server.name, InstanceId: server.instanceId, SubnetId: csrv.SubnetId, PrivateIpAddress: server.ipaddress, PublicIpAddress: server.publicIp, PrivateDnsName: server.privatedns, CreatedOn: server.createdon, Cloud: "Amazon"})
+		createServerResponse = append(createServerResponse, ServerResponse{InstanceName: server.name, InstanceId: server.instanceId, SubnetId: csrv.SubnetId, PrivateIpAddress: server.ipaddress, PublicIpAddress: server.publicIp, AllocationId: allocations[server.instanceId], PrivateDnsName: server.privatedns, KeyName: server.keyname, PrivateKey: privateKey, CreatedOn: server.createdon, Volumes: volumes[server.instanceId], Cloud: "Amazon",
+			Lifecycle: server.lifecycle, SpotInstanceRequestId: server.spotid})
 	}
 
 	return createServerResponse, nil
 }
+
+// marketOptions returns the market options of the instances to be launched, nil is returned for the ones on-demand.
+// The options of spot are rejected for the instances on-demand, rather than launching them on-demand silently.
+func (csrv *CreateServerInput) marketOptions() (*ec2.InstanceMarketOptionsRequest, error) {
+
+	switch strings.ToLower(csrv.MarketType) {
+	case "", "on-demand":
+		if csrv.MaxPrice != "" || csrv.InterruptionBehavior != "" {
+			return nil, cloudyerror.New(cloudyerror.InvalidInput, "MaxPrice and InterruptionBehavior are applicable only to the spot instances, set the MarketType to spot")
+		}
+		return nil, nil
+	case ec2.MarketTypeSpot:
+	default:
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The market type %s is not supported, the available ones are: on-demand/spot", csrv.MarketType)
+	}
+
+	spot := new(ec2.SpotMarketOptions)
+	if csrv.MaxPrice != "" {
+		if price, err := strconv.ParseFloat(csrv.MaxPrice, 64); err != nil || price <= 0 {
+			return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The max price %s of the spot instances is not valid, it has to be the hourly price in USD ex: 0.05", csrv.MaxPrice)
+		}
+		spot.MaxPrice = awssdk.String(csrv.MaxPrice)
+	}
+	switch behavior := strings.ToLower(csrv.InterruptionBehavior); behavior {
+	case "", ec2.InstanceInterruptionBehaviorTerminate:
+	case ec2.InstanceInterruptionBehaviorStop, ec2.InstanceInterruptionBehaviorHibernate:
+		// aws stops/hibernates only the instances of the persistent requests, the one-time requests are closed once interrupted.
+		spot.InstanceInterruptionBehavior = awssdk.String(behavior)
+		spot.SpotInstanceType = awssdk.String(ec2.SpotInstanceTypePersistent)
+	default:
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The interruption behavior %s is not supported, the available ones are: terminate/stop/hibernate", csrv.InterruptionBehavior)
+	}
+	return &ec2.InstanceMarketOptionsRequest{MarketType: awssdk.String(ec2.MarketTypeSpot), SpotOptions: spot}, nil
+}
+
+// generatesKeyPair reports whether a key pair has to be generated for the instances, it is generated only if none is passed.
+func (csrv *CreateServerInput) generatesKeyPair() bool {
+	return csrv.GenerateKeyPair && (csrv.KeyName == "")
+}
+
+// keyPairName returns the name of the key pair to be generated for the instances, the name of the instance suffixed with the client token.
+// A random suffix is used in place of the client token if it is not set, so that the servers of the same name get key pairs of their own.
+func (csrv *CreateServerInput) keyPairName() string {
+	if csrv.ClientToken != "" {
+		return csrv.InstanceName + "-" + csrv.ClientToken
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return csrv.InstanceName + "-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return csrv.InstanceName + "-" + hex.EncodeToString(suffix)
+}
+
+// generateKeyPair generates the key pair for the instances to be launched, named after the instance and the client token.
+// The key pair of the client token left behind by an earlier attempt which launched no instance (ex: it crashed before launching them)
+// is used by none, hence it is generated afresh as its private key cannot be fetched from aws.
+func (csrv *CreateServerInput) generateKeyPair(sess aws.EstablishedSession) (KeyPairResponse, error) {
+
+	name := csrv.keyPairName()
+	pair, err := createKeyPair(sess, name, false)
+	if (err == nil) || (csrv.ClientToken == "") || !cloudyerror.IsConflict(cloudyerror.FromAWS(err, "keypair", name)) {
+		return pair, err
+	}
+	if delerr := sess.DeleteKeyPair(&aws.KeyPairInput{KeyNames: []string{name}}); delerr != nil {
+		return KeyPairResponse{}, delerr
+	}
+	return createKeyPair(sess, name, false)
+}
+
+// associateStaticIps allocates an elastic ip for each of the instances passed and associates it, named after the instance.
+// The instances which already have one associated (ex: picked up by ClientToken) are left as is, their elastic ip is returned instead.
+// On failure the elastic ips associated so far are returned along with the error, the one which could not be associated is released.
+func (csrv *CreateServerInput) associateStaticIps(con aws.EstablishConnectionInput, sess aws.EstablishedSession, instanceIds []string) (map[string]string, error) {
+
+	allocations := make(map[string]string)
+	addresses, adderr := sess.DescribeAddresses(&aws.AddressInput{Filters: aws.Filters{Name: "instance-id", Value: instanceIds}})
+	if adderr != nil {
+		return nil, adderr
+	}
+	for _, address := range addresses.Addresses {
+		allocations[*address.InstanceId] = *address.AllocationId
+	}
+
+	for i, instance := range instanceIds {
+		if _, ok := allocations[instance]; ok {
+			continue
+		}
+		allocation, allocerr := allocateAddress(con, sess, csrv.InstanceName+"-"+strconv.Itoa(i))
+		if allocerr != nil {
+			return allocations, allocerr
+		}
+		if _, asserr := sess.AssociateAddress(&aws.AddressInput{AllocationIds: []string{allocation}, InstanceId: instance}); asserr != nil {
+			sess.ReleaseAddress(&aws.AddressInput{AllocationIds: []string{allocation}})
+			return allocations, asserr
+		}
+		allocations[instance] = allocation
+	}
+	return allocations, nil
+}
+
+// launched returns the response of the instances launched, it is returned along with the error failing the creation after the instances are launched
+// so that the instances, the elastic ips associated with them and the key pair generated are not lost to the caller.
+func (csrv *CreateServerInput) launched(instanceIds []string, allocations map[string]string, keyName, privateKey string) []ServerResponse {
+	response := make([]ServerResponse, 0, len(instanceIds))
+	for _, instance := range instanceIds {
+		response = append(response, ServerResponse{InstanceId: instance, SubnetId: csrv.SubnetId, AllocationId: allocations[instance], KeyName: keyName, PrivateKey: privateKey, Cloud: "Amazon"})
+	}
+	return response
+}
+
+// launchFailed returns the error of launching the instances, the key pair generated for them is deleted only if none of them was launched.
+// aws may have launched the instances with the client token even though the response was lost, those are returned along with the error
+// and keep the key pair, so that a retry with the same token picks them up. The key pair which could not be deleted is returned as well.
+func (csrv *CreateServerInput) launchFailed(sess aws.EstablishedSession, keyName, privateKey string, err error) ([]ServerResponse, error) {
+
+	if privateKey == "" {
+		return nil, err
+	}
+	generated := []ServerResponse{{KeyName: keyName, PrivateKey: privateKey, Cloud: "Amazon"}}
+	instanceIds, tokenerr := csrv.launchedWithToken(sess)
+	if tokenerr != nil {
+		return generated, err
+	}
+	if len(instanceIds) != 0 {
+		return csrv.launched(instanceIds, nil, keyName, privateKey), err
+	}
+	if delerr := sess.DeleteKeyPair(&aws.KeyPairInput{KeyNames: []string{keyName}}); delerr != nil {
+		generated[0].Error = delerr
+		return generated, err
+	}
+	return nil, err
+}
+
+// launchedWithToken returns the IDs of the instances launched earlier with the client token of the input, none if the token is not set.
+// The instances terminated since are left out, the token cannot be reused once all of them are terminated as aws answers it with the terminated ones.
+func (csrv *CreateServerInput) launchedWithToken(sess aws.EstablishedSession) ([]string, error) {
+
+	instanceIds := make([]string, 0)
+	if csrv.ClientToken == "" {
+		return instanceIds, nil
+	}
+
+	result, err := sess.DescribeInstance(
+		&aws.DescribeComputeInput{
+			Filters: aws.Filters{Name: "client-token", Value: []string{csrv.ClientToken}},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	terminated := 0
+	for _, reservation := range result.Reservations {
+		for _, instance := range reservation.Instances {
+			switch awssdk.StringValue(instance.State.Name) {
+			case ec2.InstanceStateNameTerminated, ec2.InstanceStateNameShuttingDown:
+				terminated++
+			default:
+				instanceIds = append(instanceIds, *instance.InstanceId)
+			}
+		}
+	}
+	if (len(instanceIds) == 0) && (terminated != 0) {
+		return nil, &cloudyerror.Error{Code: cloudyerror.Conflict, Cloud: "aws", Kind: "server", Message: "The instances launched with the client token " + csrv.ClientToken +
+			" are terminated, pass a new token to launch the instances again"}
+	}
+	return instanceIds, nil
+}
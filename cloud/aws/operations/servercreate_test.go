@@ -0,0 +1,241 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsfake "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/fake"
+	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+func TestCreateServer(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	server := CreateServerInput{
+		InstanceName: "neuron",
+		ImageId:      "ami-0123456789",
+		InstanceType: "t2.micro",
+		SubnetId:     network.Subnets[0].Id,
+		MaxCount:     2,
+		AssignPubIp:  true,
+	}
+	servers, err := server.CreateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating server: %v", err)
+	}
+	if len(servers) != 2 {
+		t.Fatalf("expected 2 servers, got %d", len(servers))
+	}
+	for i, created := range servers {
+		if created.InstanceName != "neuron-"+string(rune('0'+i)) {
+			t.Errorf("unexpected name of server %d: %s", i, created.InstanceName)
+		}
+		if created.PrivateIpAddress == "" || created.PublicIpAddress == "" || created.SubnetId != network.Subnets[0].Id {
+			t.Errorf("server is not created as requested, got %+v", created)
+		}
+	}
+
+	del := DeleteServerInput{InstanceIds: []string{servers[0].InstanceId, servers[1].InstanceId}}
+	deleted, err := del.DeleteServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("deleting server: %v", err)
+	}
+	for _, server := range deleted {
+		if server.CurrentState != "terminated" {
+			t.Errorf("expected server %s to be terminated, got %s", server.InstanceId, server.CurrentState)
+		}
+	}
+}
+
+func TestCreateServerSubnetNotFound(t *testing.T) {
+	cloud := awsfake.New()
+	server := CreateServerInput{InstanceName: "neuron", ImageId: "ami-0123456789", SubnetId: "subnet-00000000000000099"}
+	_, err := server.CreateServer(fakeConnection(cloud, "ec2"))
+	if !cloudyerror.IsNotFound(cloudyerror.FromAWS(err, "subnet", server.SubnetId)) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestCreateServerClientToken(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	server := CreateServerInput{InstanceName: "neuron", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id, MaxCount: 2, ClientToken: "neuron-create-1"}
+	// the first attempt fails after the instances are launched, as if the connection dropped midway.
+	cloud.FailNext("CreateTags", awserr.New("InternalError", "An internal error has occurred", nil))
+	if _, err := server.CreateServer(fakeConnection(cloud, "ec2")); err == nil {
+		t.Fatalf("expected the first attempt to fail")
+	}
+
+	servers, err := server.CreateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("retrying creation of server: %v", err)
+	}
+	if len(servers) != 2 || servers[0].InstanceName != "neuron-0" {
+		t.Fatalf("expected the 2 servers launched by the first attempt, got %+v", servers)
+	}
+	if calls := cloud.Calls("RunInstances"); calls != 1 {
+		t.Errorf("expected the instances to be launched once, got %d calls to RunInstances", calls)
+	}
+
+	get := DescribeInstanceInput{}
+	all, err := get.GetAllServers(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("fetching servers: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("expected no duplicate servers, got %d servers", len(all))
+	}
+}
+
+func TestCreateServerClientTokenTerminated(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	server := CreateServerInput{InstanceName: "neuron", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id, ClientToken: "neuron-create-1"}
+	servers, err := server.CreateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating server: %v", err)
+	}
+	del := DeleteServerInput{InstanceIds: []string{servers[0].InstanceId}}
+	if _, err := del.DeleteServer(fakeConnection(cloud, "ec2")); err != nil {
+		t.Fatalf("deleting server: %v", err)
+	}
+
+	// the token belongs to the instance terminated, hence it is neither picked up nor waited on.
+	if _, err := server.CreateServer(fakeConnection(cloud, "ec2")); !cloudyerror.IsConflict(err) {
+		t.Errorf("expected the token of the terminated instances to conflict, got %v", err)
+	}
+	if calls := cloud.Calls("RunInstances"); calls != 1 {
+		t.Errorf("expected no instance to be launched with the token, got %d calls to RunInstances", calls)
+	}
+}
+
+func TestCreateServerClientTokenMismatch(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	server := CreateServerInput{InstanceName: "neuron", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id, ClientToken: "neuron-create-1"}
+	con := fakeConnection(cloud, "ec2")
+	sess, err := con.EstablishConnection()
+	if err != nil {
+		t.Fatalf("establishing connection: %v", err)
+	}
+	if _, err := sess.CreateInstance(&aws.CreateServerInput{ImageId: server.ImageId, InstanceType: "t2.micro", SubnetId: server.SubnetId, MinCount: 1, MaxCount: 1, ClientToken: server.ClientToken}); err != nil {
+		t.Fatalf("launching instance: %v", err)
+	}
+	_, err = sess.CreateInstance(&aws.CreateServerInput{ImageId: server.ImageId, InstanceType: "t2.large", SubnetId: server.SubnetId, MinCount: 1, MaxCount: 1, ClientToken: server.ClientToken})
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "IdempotentParameterMismatch" {
+		t.Errorf("expected the token reused with other arguments to be rejected, got %v", err)
+	}
+}
+
+func TestCreateAndDeleteImage(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	server := CreateServerInput{InstanceName: "neuron", ImageId: "ami-0123456789", SubnetId: network.Subnets[0].Id, AssignPubIp: true}
+	servers, err := server.CreateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating server: %v", err)
+	}
+
+	create := ImageCreateInput{InstanceId: servers[0].InstanceId}
+	image, err := create.CreateImage(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating image: %v", err)
+	}
+	if image.ImageId == "" {
+		t.Fatalf("expected the ID of image created, got %+v", image)
+	}
+
+	del := DeleteImageInput{ImageIds: []string{image.ImageId}}
+	if _, err := del.DeleteImage(fakeConnection(cloud, "ec2")); err != nil {
+		t.Fatalf("deleting image: %v", err)
+	}
+	if calls := cloud.Calls("DeleteSnapshot"); calls != 1 {
+		t.Errorf("expected snapshot of the image to be deleted, DeleteSnapshot was called %d times", calls)
+	}
+}
+
+func TestCreateServerVolumes(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	retain := false
+	server := CreateServerInput{
+		InstanceName: "neuron",
+		ImageId:      "ami-0123456789",
+		InstanceType: "t2.micro",
+		SubnetId:     network.Subnets[0].Id,
+		Volumes: []ServerVolume{
+			{Root: true, Size: 30},
+			{Size: 100, Type: "io1", Iops: 1000, Encrypted: true, KmsKeyId: "alias/neuron"},
+			{Size: 500, Type: "st1", DeleteOnTermination: &retain},
+		},
+	}
+	servers, err := server.CreateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating server: %v", err)
+	}
+	volumes := servers[0].Volumes
+	if len(volumes) != 3 {
+		t.Fatalf("expected 3 volumes attached to the server, got %+v", volumes)
+	}
+	if !volumes[0].Root || volumes[0].DeviceName != "/dev/xvda" || volumes[0].Size != 30 || volumes[0].Type != "gp2" {
+		t.Errorf("root volume is not created as requested, got %+v", volumes[0])
+	}
+	if volumes[1].DeviceName != "/dev/sdf" || volumes[1].Type != "io1" || volumes[1].Iops != 1000 || !volumes[1].Encrypted || volumes[1].KmsKeyId != "alias/neuron" {
+		t.Errorf("io1 volume is not created as requested, got %+v", volumes[1])
+	}
+	if volumes[2].DeviceName != "/dev/sdg" || volumes[2].Type != "st1" || volumes[2].DeleteOnTermination {
+		t.Errorf("st1 volume is not created as requested, got %+v", volumes[2])
+	}
+
+	del := DeleteServerInput{InstanceIds: []string{servers[0].InstanceId}}
+	if _, err := del.DeleteServer(fakeConnection(cloud, "ec2")); err != nil {
+		t.Fatalf("deleting server: %v", err)
+	}
+	con := fakeConnection(cloud, "ec2")
+	sess, err := con.EstablishConnection()
+	if err != nil {
+		t.Fatalf("establishing connection: %v", err)
+	}
+	left, err := sess.DescribeVolumes(&aws.VolumeInput{Filters: aws.Filters{Name: "status", Value: []string{"available", "in-use"}}})
+	if err != nil {
+		t.Fatalf("fetching volumes: %v", err)
+	}
+	if len(left.Volumes) != 1 || *left.Volumes[0].VolumeId != volumes[2].VolumeId {
+		t.Errorf("expected only the volume retained on termination to be left, got %v", left.Volumes)
+	}
+}
+
+func TestCreateServerVolumesInvalid(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	cases := map[string][]ServerVolume{
+		"unsupported type":       {{Size: 10, Type: "sc1"}},
+		"missing size":           {{Type: "gp2"}},
+		"st1 too small":          {{Size: 100, Type: "st1"}},
+		"io1 without iops":       {{Size: 100, Type: "io1"}},
+		"iops on gp2":            {{Size: 100, Iops: 300}},
+		"iops ratio":             {{Size: 10, Type: "io1", Iops: 1000}},
+		"key without encryption": {{Size: 10, KmsKeyId: "alias/neuron"}},
+		"st1 root":               {{Root: true, Size: 500, Type: "st1"}},
+		"two roots":              {{Root: true}, {Root: true, Size: 20}},
+		"duplicate device":       {{Size: 10, DeviceName: "/dev/sdf"}, {Size: 20, DeviceName: "/dev/sdf"}},
+		"root device":            {{Size: 10, DeviceName: "/dev/xvda"}},
+	}
+	for name, volumes := range cases {
+		server := CreateServerInput{InstanceName: "neuron", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id, Volumes: volumes}
+		if _, err := server.CreateServer(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+			t.Errorf("%s: expected invalid input, got %v", name, err)
+		}
+	}
+	if calls := cloud.Calls("RunInstances"); calls != 0 {
+		t.Errorf("expected no instance to be launched with invalid volumes, got %d calls to RunInstances", calls)
+	}
+}
@@ -1,9 +1,9 @@
 package aws
 
 import (
-	"fmt"
-
+	awssdk "github.com/aws/aws-sdk-go/aws"
 	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
 )
 
 // DeleteServerInput will implement the methods to delete servers/vm and other related activities.
@@ -16,6 +16,7 @@ type DeleteServerInput struct {
 }
 
 // DeleteServer will delete the server who's Id was selected.
+// The spot requests of the spot instances are cancelled before terminating them, so that aws does not launch them again.
 func (d *DeleteServerInput) DeleteServer(con aws.EstablishConnectionInput) ([]ServerResponse, error) {
 
 	//get the relative sessions before proceeding further
@@ -32,7 +33,18 @@ func (d *DeleteServerInput) DeleteServer(con aws.EstablishConnectionInput) ([]Se
 	}
 
 	if searchInstance != true {
-		return nil, fmt.Errorf("Could not find the entered Instances, please enter valid/existing InstanceIds")
+		return nil, &cloudyerror.Error{Code: cloudyerror.NotFound, Cloud: "aws", Kind: "server", Message: "Could not find the entered Instances, please enter valid/existing InstanceIds"}
+	}
+
+	// the spot requests are cancelled ahead of terminating the instances, else aws launches new instances for the persistent ones.
+	requests, spoterr := spotRequests(ec2, d.InstanceIds)
+	if spoterr != nil {
+		return nil, spoterr
+	}
+	if len(requests) != 0 {
+		if _, cancelerr := ec2.CancelSpotInstanceRequests(requests); cancelerr != nil {
+			return nil, cancelerr
+		}
 	}
 	deleteResult, insTermErr := ec2.DeleteInstance(
 		&aws.DeleteComputeInput{
@@ -76,7 +88,8 @@ func (d *DeleteServerInput) DeleteServer(con aws.EstablishConnectionInput) ([]Se
 	return deleteResponse, nil
 }
 
-// DeleteServerFromVpc deletes every single instances from the vpc selected.
+// DeleteServerFromVpc deletes every single instances from the vpc selected, and waits till they are terminated.
+// The instances which are already terminated (or are terminating) are left out, nothing is deleted if the vpc has none of them.
 func (d *DeleteServerInput) DeleteServerFromVpc(con aws.EstablishConnectionInput) ([]ServerResponse, error) {
 
 	//get the relative sessions before proceeding further
@@ -85,38 +98,31 @@ func (d *DeleteServerInput) DeleteServerFromVpc(con aws.EstablishConnectionInput
 		return nil, sesserr
 	}
 
-	instanceSearchInput := DescribeInstanceInput{
-		VpcIds: []string{d.VpcId},
-	}
-	searchInstance, serverr := instanceSearchInput.GetServersFromNetwork(con)
+	insatanceids, serverr := serversOfVpc(ec2, d.VpcId)
 	if serverr != nil {
 		return nil, serverr
 	}
-
-	insatanceids := make([]string, 0)
-	for _, instanceid := range searchInstance {
-		insatanceids = append(insatanceids, instanceid.InstanceId)
-	}
-
-	result, servDelErr := ec2.DeleteInstance(
-		&aws.DeleteComputeInput{
-			InstanceIds: insatanceids,
-		},
-	)
-	if servDelErr != nil {
-		return nil, servDelErr
+	if len(insatanceids) == 0 {
+		return make([]ServerResponse, 0), nil
 	}
 
-	deleteResponse := make([]ServerResponse, 0)
+	deletein := DeleteServerInput{InstanceIds: insatanceids, GetRaw: d.GetRaw}
+	return deletein.DeleteServer(con)
+}
 
-	if d.GetRaw == true {
-		deleteResponse = append(deleteResponse, ServerResponse{DeleteInstRaw: result, Cloud: "Amazon"})
-		return deleteResponse, nil
+// spotRequests returns the IDs of the spot requests for which the instances passed were launched, the instances on-demand are left out.
+func spotRequests(sess aws.EstablishedSession, instanceIds []string) ([]string, error) {
+	described, err := sess.DescribeInstance(&aws.DescribeComputeInput{InstanceIds: instanceIds})
+	if err != nil {
+		return nil, err
 	}
-
-	for _, instance := range result.TerminatingInstances {
-		deleteResponse = append(deleteResponse, ServerResponse{InstanceId: *instance.InstanceId, CurrentState: *instance.CurrentState.Name})
+	requests := make([]string, 0)
+	for _, reservation := range described.Reservations {
+		for _, instance := range reservation.Instances {
+			if request := awssdk.StringValue(instance.SpotInstanceRequestId); request != "" {
+				requests = append(requests, request)
+			}
+		}
 	}
-
-	return deleteResponse, nil
+	return requests, nil
 }
@@ -1,24 +1,59 @@
 package aws
 
 import (
+	b64 "encoding/base64"
 	"fmt"
 	"strings"
 
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
 )
 
 // UpdateServerInput implements UpdateServer and other related activities, it holds all necessary details to update server.
 type UpdateServerInput struct {
 	// InstanceIds are the list of instances which has to updated.
 	InstanceIds []string
-	// Action to be performned on instances as part of updation.
+	// Action to be performned on instances as part of updation, the available actions are: start, stop, reboot,
+	// resize (changes the type to InstanceType), protect/unprotect (from termination), userdata (replaces it with UserData),
+	// securitygroups (replaces them with SecurityGroupIds) and monitor/unmonitor (the detailed monitoring).
 	Action string
-	GetRaw bool
+	// InstanceType is the type to which the instances are changed by resize ex: t2.medium.
+	InstanceType string
+	// UserData is the command/script which replaces the user data of the instances by userdata.
+	UserData string
+	// SecurityGroupIds are the IDs of the security groups which replaces the ones of the instances by securitygroups.
+	SecurityGroupIds []string
+	GetRaw           bool
 }
 
-// UpdateServer updates the server (start/stop and other operations).
+// ServerAttributes are the attributes of the instance which could be changed by UpdateServer, they are captured before and after the update.
+type ServerAttributes struct {
+	// State of the instance ex: running, stopped.
+	State string `json:"State,omitempty"`
+	// InstanceType of the instance ex: t2.micro.
+	InstanceType string `json:"InstanceType,omitempty"`
+	// DisableApiTermination states whether the instance is protected from termination.
+	DisableApiTermination bool `json:"DisableApiTermination"`
+	// Monitoring is the state of the detailed monitoring of the instance ex: enabled, disabled.
+	Monitoring string `json:"Monitoring,omitempty"`
+	// SecurityGroupIds are the IDs of the security groups associated with the instance.
+	SecurityGroupIds []string `json:"SecurityGroupIds,omitempty"`
+	// UserData is the user data of the instance, it is captured only by the action userdata.
+	UserData string `json:"UserData,omitempty"`
+}
+
+// UpdateServer updates the server (start/stop and other operations), the attributes of the instances before and after the update are part of the response.
+// The instance type and the user data can be changed only while the instances are stopped, hence the running instances are stopped before and started back after the change.
+// If they fail to start with the change, it is reverted and the instances are started back; the response then reports the rollback along with the error.
 func (u *UpdateServerInput) UpdateServer(con aws.EstablishConnectionInput) ([]ServerResponse, error) {
 
+	action := strings.ToLower(u.Action)
+	if valerr := u.validate(action); valerr != nil {
+		return nil, valerr
+	}
+
 	//get the relative sessions before proceeding further
 	ec2, sesserr := con.EstablishConnection()
 	if sesserr != nil {
@@ -32,72 +67,247 @@ func (u *UpdateServerInput) UpdateServer(con aws.EstablishConnectionInput) ([]Se
 	}
 
 	if search != true {
-		return nil, fmt.Errorf("Could not find the entered Instances, please enter valid/existing InstanceIds")
+		return nil, &cloudyerror.Error{Code: cloudyerror.NotFound, Cloud: "aws", Kind: "server", Message: "Could not find the entered Instances, please enter valid/existing InstanceIds"}
 	}
-	serverResponse := make([]ServerResponse, 0)
 
-	switch strings.ToLower(u.Action) {
+	before, beferr := serverAttributes(ec2, u.InstanceIds, action == "userdata")
+	if beferr != nil {
+		return nil, beferr
+	}
+
+	raw := ServerResponse{Cloud: "Amazon"}
+	var rolledback *RollbackResponse
+	var updateerr error
+	switch action {
 	case "start":
-		result, startErr := ec2.StartInstances(
-			&aws.UpdateComputeInput{
-				InstanceIds: u.InstanceIds,
-			},
-		)
+		raw.StartInstRaw, updateerr = startInstances(ec2, u.InstanceIds)
+	case "stop":
+		raw.StopInstRaw, updateerr = stopInstances(ec2, u.InstanceIds)
+	case "reboot":
+		updateerr = ec2.RebootInstances(&aws.UpdateComputeInput{InstanceIds: u.InstanceIds})
+	case "resize", "userdata":
+		rolledback, updateerr = u.modifyStopped(con, ec2, before)
+	case "protect", "unprotect", "securitygroups":
+		for _, id := range u.InstanceIds {
+			if updateerr = ec2.ModifyInstanceAttribute(u.attribute(id)); updateerr != nil {
+				break
+			}
+		}
+	case "monitor":
+		_, updateerr = ec2.MonitorInstances(&aws.UpdateComputeInput{InstanceIds: u.InstanceIds})
+	case "unmonitor":
+		_, updateerr = ec2.UnmonitorInstances(&aws.UpdateComputeInput{InstanceIds: u.InstanceIds})
+	}
+	if updateerr != nil && rolledback == nil {
+		return nil, updateerr
+	}
+
+	after, afterr := serverAttributes(ec2, u.InstanceIds, action == "userdata")
+	if afterr != nil {
+		return nil, afterr
+	}
 
-		if startErr != nil {
-			return nil, startErr
+	if u.GetRaw == true {
+		if raw.StartInstRaw == nil && raw.StopInstRaw == nil {
+			described, deserr := ec2.DescribeInstance(&aws.DescribeComputeInput{InstanceIds: u.InstanceIds})
+			if deserr != nil {
+				return nil, deserr
+			}
+			raw.GetInstRaw = described
 		}
+		raw.Rollback = rolledback
+		return []ServerResponse{raw}, updateerr
+	}
 
-		waitErr := ec2.WaitTillInstanceRunning(
-			&aws.DescribeComputeInput{
-				InstanceIds: u.InstanceIds,
-			},
-		)
-		if waitErr != nil {
-			return nil, waitErr
+	serverResponse := make([]ServerResponse, 0)
+	for index, id := range u.InstanceIds {
+		serverResponse = append(serverResponse, ServerResponse{InstanceId: id, InstanceType: after[index].InstanceType, CurrentState: after[index].State,
+			PreviousState: before[index].State, Before: &before[index], After: &after[index], Rollback: rolledback})
+	}
+	return serverResponse, updateerr
+}
+
+// validate validates the action along with the values it requires, before anything is asked to aws.
+func (u *UpdateServerInput) validate(action string) error {
+	switch action {
+	case "start", "stop", "reboot", "protect", "unprotect", "monitor", "unmonitor":
+	case "resize":
+		if u.InstanceType == "" {
+			return cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the InstanceType to which the instances has to be resized")
+		}
+	case "userdata":
+		if u.UserData == "" {
+			return cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the UserData which has to replace the user data of the instances")
+		}
+	case "securitygroups":
+		if len(u.SecurityGroupIds) == 0 {
+			return cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the SecurityGroupIds which has to be associated with the instances")
 		}
+	default:
+		return cloudyerror.New(cloudyerror.InvalidInput, "Sorry...!!!!. I am not aware of the action you asked me to perform, please enter the action which we support. The available actions are: start/stop/reboot/resize/protect/unprotect/userdata/securitygroups/monitor/unmonitor")
+	}
+	return nil
+}
+
+// attribute returns the change of attribute of the instance asked for by the action.
+func (u *UpdateServerInput) attribute(id string) *aws.InstanceAttributeInput {
+	switch strings.ToLower(u.Action) {
+	case "resize":
+		return &aws.InstanceAttributeInput{InstanceId: id, InstanceType: u.InstanceType}
+	case "userdata":
+		return &aws.InstanceAttributeInput{InstanceId: id, UserData: u.UserData}
+	case "securitygroups":
+		return &aws.InstanceAttributeInput{InstanceId: id, SecurityGroups: u.SecurityGroupIds}
+	default:
+		return &aws.InstanceAttributeInput{InstanceId: id, DisableApiTermination: awssdk.Bool(strings.ToLower(u.Action) == "protect")}
+	}
+}
+
+// modifyStopped applies the change which aws allows only while the instances are stopped, the running instances are stopped before and started back after it.
+// Every step is recorded along with the one undoing it, hence if the stop, the change or the start of instances fails the instances are brought back to the attributes
+// and the state they had before; the rollback is returned along with the error in that case.
+func (u *UpdateServerInput) modifyStopped(con aws.EstablishConnectionInput, sess aws.EstablishedSession, before []ServerAttributes) (*RollbackResponse, error) {
 
-		if u.GetRaw == true {
-			serverResponse = append(serverResponse, ServerResponse{StartInstRaw: result, Cloud: "Amazon"})
-			return serverResponse, nil
+	running := make([]string, 0)
+	for index, id := range u.InstanceIds {
+		if before[index].State == ec2.InstanceStateNameRunning {
+			running = append(running, id)
 		}
+	}
 
-		for _, inst := range result.StartingInstances {
-			serverResponse = append(serverResponse, ServerResponse{InstanceId: *inst.InstanceId, CurrentState: "running", PreviousState: *inst.PreviousState.Name})
+	undo := new(rollback)
+	if len(running) != 0 {
+		// the start is recorded ahead of stopping, so that the instances which did stop are started back even if the stop fails partway (ex: waiter timeout).
+		undo.record("server stop", strings.Join(running, ","), func(con aws.EstablishConnectionInput) error {
+			sess, seserr := con.EstablishConnection()
+			if seserr != nil {
+				return seserr
+			}
+			_, starterr := startInstances(sess, running)
+			return starterr
+		})
+		if _, stoperr := stopInstances(sess, running); stoperr != nil {
+			return undo.run(con), stoperr
 		}
-		return serverResponse, nil
+	}
 
-	case "stop":
-		result, stopErr := ec2.StopInstances(
-			&aws.UpdateComputeInput{
-				InstanceIds: u.InstanceIds,
-			},
-		)
+	for index, id := range u.InstanceIds {
+		if moderr := sess.ModifyInstanceAttribute(u.attribute(id)); moderr != nil {
+			return undo.run(con), moderr
+		}
+		previous := &aws.InstanceAttributeInput{InstanceId: id, InstanceType: before[index].InstanceType}
+		if strings.ToLower(u.Action) == "userdata" {
+			previous = &aws.InstanceAttributeInput{InstanceId: id, UserData: before[index].UserData}
+		}
+		undo.record("server "+strings.ToLower(u.Action), id, func(con aws.EstablishConnectionInput) error {
+			// aws does not take empty user data, hence the one of the instance launched without it cannot be restored.
+			if previous.InstanceType == "" && previous.UserData == "" {
+				return fmt.Errorf("the instance was launched without user data, it has to be removed by hand")
+			}
+			sess, seserr := con.EstablishConnection()
+			if seserr != nil {
+				return seserr
+			}
+			return sess.ModifyInstanceAttribute(previous)
+		})
+	}
 
-		if stopErr != nil {
-			return nil, stopErr
+	if len(running) == 0 {
+		return nil, nil
+	}
+	// the instances which failed to start are stopped again, as the change can be reverted only while they are stopped.
+	undo.record("server start", strings.Join(running, ","), func(con aws.EstablishConnectionInput) error {
+		sess, seserr := con.EstablishConnection()
+		if seserr != nil {
+			return seserr
 		}
-		waitErr := ec2.WaitTillInstanceStopped(
-			&aws.DescribeComputeInput{
-				InstanceIds: u.InstanceIds,
-			},
-		)
+		_, stoperr := stopInstances(sess, running)
+		return stoperr
+	})
+	if _, starterr := startInstances(sess, running); starterr != nil {
+		return undo.run(con), starterr
+	}
+	return nil, nil
+}
+
+// startInstances starts the instances and waits till they are running.
+func startInstances(sess aws.EstablishedSession, ids []string) (*ec2.StartInstancesOutput, error) {
+	result, startErr := sess.StartInstances(&aws.UpdateComputeInput{InstanceIds: ids})
+	if startErr != nil {
+		return nil, startErr
+	}
+	if waitErr := sess.WaitTillInstanceRunning(&aws.DescribeComputeInput{InstanceIds: ids}); waitErr != nil {
+		return nil, waitErr
+	}
+	return result, nil
+}
 
-		if waitErr != nil {
-			return nil, waitErr
+// stopInstances stops the instances and waits till they are stopped.
+func stopInstances(sess aws.EstablishedSession, ids []string) (*ec2.StopInstancesOutput, error) {
+	result, stopErr := sess.StopInstances(&aws.UpdateComputeInput{InstanceIds: ids})
+	if stopErr != nil {
+		return nil, stopErr
+	}
+	if waitErr := sess.WaitTillInstanceStopped(&aws.DescribeComputeInput{InstanceIds: ids}); waitErr != nil {
+		return nil, waitErr
+	}
+	return result, nil
+}
+
+// serverAttributes captures the attributes of the instances in the order they are passed, the user data is fetched only if asked for
+// as it is an additional call to aws per instance.
+func serverAttributes(sess aws.EstablishedSession, ids []string, userData bool) ([]ServerAttributes, error) {
+
+	described, deserr := sess.DescribeInstance(&aws.DescribeComputeInput{InstanceIds: ids})
+	if deserr != nil {
+		return nil, deserr
+	}
+	instances := make(map[string]*ec2.Instance)
+	for _, reservation := range described.Reservations {
+		for _, instance := range reservation.Instances {
+			instances[*instance.InstanceId] = instance
 		}
+	}
 
-		if u.GetRaw == true {
-			serverResponse = append(serverResponse, ServerResponse{StopInstRaw: result, Cloud: "Amazon"})
-			return serverResponse, nil
+	attributes := make([]ServerAttributes, 0, len(ids))
+	for _, id := range ids {
+		instance, ok := instances[id]
+		if !ok {
+			return nil, cloudyerror.ServerNotFound()
+		}
+		attrs := ServerAttributes{InstanceType: awssdk.StringValue(instance.InstanceType), SecurityGroupIds: make([]string, 0)}
+		if instance.State != nil {
+			attrs.State = awssdk.StringValue(instance.State.Name)
+		}
+		if instance.Monitoring != nil {
+			attrs.Monitoring = awssdk.StringValue(instance.Monitoring.State)
+		}
+		for _, group := range instance.SecurityGroups {
+			attrs.SecurityGroupIds = append(attrs.SecurityGroupIds, awssdk.StringValue(group.GroupId))
 		}
 
-		for _, inst := range result.StoppingInstances {
-			serverResponse = append(serverResponse, ServerResponse{InstanceId: *inst.InstanceId, CurrentState: "stopped", PreviousState: *inst.PreviousState.Name})
+		termination, termerr := sess.DescribeInstanceAttribute(&aws.InstanceAttributeInput{InstanceId: id, Attribute: ec2.InstanceAttributeNameDisableApiTermination})
+		if termerr != nil {
+			return nil, termerr
+		}
+		if termination.DisableApiTermination != nil {
+			attrs.DisableApiTermination = awssdk.BoolValue(termination.DisableApiTermination.Value)
 		}
-		return serverResponse, nil
 
-	default:
-		return nil, fmt.Errorf("Sorry...!!!!. I am not aware of the action you asked me to perform, please enter the action which we support. The available actions are: start/stop")
+		if userData {
+			data, dataerr := sess.DescribeInstanceAttribute(&aws.InstanceAttributeInput{InstanceId: id, Attribute: ec2.InstanceAttributeNameUserData})
+			if dataerr != nil {
+				return nil, dataerr
+			}
+			if data.UserData != nil && data.UserData.Value != nil {
+				decoded, decerr := b64.StdEncoding.DecodeString(*data.UserData.Value)
+				if decerr != nil {
+					return nil, decerr
+				}
+				attrs.UserData = string(decoded)
+			}
+		}
+		attributes = append(attributes, attrs)
 	}
+	return attributes, nil
 }
@@ -0,0 +1,200 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awsfake "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/fake"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+func TestUpdateServerActions(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+	group := createTestSecurityGroup(t, cloud, network)
+	server := CreateServerInput{InstanceName: "neuron", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id}
+	servers, err := server.CreateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating server: %v", err)
+	}
+	id := servers[0].InstanceId
+
+	update := func(in UpdateServerInput) ServerResponse {
+		t.Helper()
+		in.InstanceIds = []string{id}
+		updated, err := in.UpdateServer(fakeConnection(cloud, "ec2"))
+		if err != nil {
+			t.Fatalf("%s of server: %v", in.Action, err)
+		}
+		return updated[0]
+	}
+
+	stopped := update(UpdateServerInput{Action: "stop"})
+	if stopped.PreviousState != "running" || stopped.CurrentState != "stopped" || stopped.Before.State != "running" || stopped.After.State != "stopped" {
+		t.Errorf("expected the server to be stopped, got %+v", stopped)
+	}
+	started := update(UpdateServerInput{Action: "Start"})
+	if started.PreviousState != "stopped" || started.CurrentState != "running" {
+		t.Errorf("expected the server to be started, got %+v", started)
+	}
+
+	rebooted := update(UpdateServerInput{Action: "reboot"})
+	if rebooted.After.State != "running" || cloud.Calls("RebootInstances") != 1 {
+		t.Errorf("expected the server to be rebooted, got %+v", rebooted)
+	}
+
+	protected := update(UpdateServerInput{Action: "protect"})
+	if protected.Before.DisableApiTermination || !protected.After.DisableApiTermination {
+		t.Errorf("expected the server to be protected from termination, got before %+v after %+v", protected.Before, protected.After)
+	}
+	del := DeleteServerInput{InstanceIds: []string{id}}
+	if _, err := del.DeleteServer(fakeConnection(cloud, "ec2")); err == nil {
+		t.Errorf("expected the server protected not to be terminated")
+	}
+	if unprotected := update(UpdateServerInput{Action: "unprotect"}); unprotected.After.DisableApiTermination {
+		t.Errorf("expected the protection from termination to be removed, got %+v", unprotected.After)
+	}
+
+	monitored := update(UpdateServerInput{Action: "monitor"})
+	if monitored.Before.Monitoring != "disabled" || monitored.After.Monitoring != "enabled" {
+		t.Errorf("expected the detailed monitoring to be enabled, got before %+v after %+v", monitored.Before, monitored.After)
+	}
+	if unmonitored := update(UpdateServerInput{Action: "unmonitor"}); unmonitored.After.Monitoring != "disabled" {
+		t.Errorf("expected the detailed monitoring to be disabled, got %+v", unmonitored.After)
+	}
+
+	regrouped := update(UpdateServerInput{Action: "securitygroups", SecurityGroupIds: []string{group.Id}})
+	if reflect.DeepEqual(regrouped.Before.SecurityGroupIds, regrouped.After.SecurityGroupIds) || !reflect.DeepEqual(regrouped.After.SecurityGroupIds, []string{group.Id}) {
+		t.Errorf("expected the security groups to be replaced, got before %+v after %+v", regrouped.Before, regrouped.After)
+	}
+
+	// the user data can be replaced only while the server is stopped, hence it is stopped and started back.
+	scripted := update(UpdateServerInput{Action: "userdata", UserData: "#!/bin/bash\necho neuron"})
+	if scripted.Before.UserData != "echo 'nothing'" || scripted.After.UserData != "#!/bin/bash\necho neuron" || scripted.After.State != "running" {
+		t.Errorf("expected the user data to be replaced, got before %+v after %+v", scripted.Before, scripted.After)
+	}
+
+	if _, err := del.DeleteServer(fakeConnection(cloud, "ec2")); err != nil {
+		t.Errorf("expected the server to be terminated once unprotected, got %v", err)
+	}
+}
+
+func TestUpdateServerResize(t *testing.T) {
+	cloud := awsfake.New()
+	server, _ := createTestServer(t, cloud)
+
+	resize := UpdateServerInput{InstanceIds: []string{server.InstanceId}, Action: "resize", InstanceType: "t2.medium"}
+	resized, err := resize.UpdateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("resizing server: %v", err)
+	}
+	if resized[0].Before.InstanceType != "t2.micro" || resized[0].After.InstanceType != "t2.medium" || resized[0].After.State != "running" || resized[0].Rollback != nil {
+		t.Errorf("expected the server to be resized and running, got before %+v after %+v", resized[0].Before, resized[0].After)
+	}
+
+	// the server does not start with the new type, hence it is brought back to the type it had.
+	resize.InstanceType = "t2.large"
+	cloud.FailNext("StartInstances", awserr.New("InsufficientInstanceCapacity", "There is no capacity available", nil))
+	rolledback, err := resize.UpdateServer(fakeConnection(cloud, "ec2"))
+	if !cloudyerror.IsQuotaExceeded(cloudyerror.FromAWS(err, "server", server.InstanceId)) {
+		t.Fatalf("expected the start of the resized server to fail, got %v", err)
+	}
+	if len(rolledback) != 1 || rolledback[0].After.InstanceType != "t2.medium" || rolledback[0].After.State != "running" {
+		t.Fatalf("expected the server to be running with its previous type, got %+v", rolledback)
+	}
+	want := []string{"server start " + server.InstanceId, "server resize " + server.InstanceId, "server stop " + server.InstanceId}
+	if rollback := rolledback[0].Rollback; rollback == nil || !reflect.DeepEqual(rollback.RolledBack, want) || len(rollback.Leftovers) != 0 {
+		t.Errorf("expected the rollback %v, got %+v", want, rollback)
+	}
+
+	// the server which does not stop in time is started back, as it is left stopping otherwise.
+	resize.InstanceType = "t2.large"
+	cloud.FailNext("WaitUntilInstanceStopped", awserr.New(request.WaiterResourceNotReadyErrorCode, "exceeded wait attempts", nil))
+	rolledback, err = resize.UpdateServer(fakeConnection(cloud, "ec2"))
+	if err == nil {
+		t.Fatalf("expected the stop of the server to fail")
+	}
+	if len(rolledback) != 1 || rolledback[0].After.InstanceType != "t2.medium" || rolledback[0].After.State != "running" {
+		t.Fatalf("expected the server to be started back with its previous type, got %+v", rolledback)
+	}
+	want = []string{"server stop " + server.InstanceId}
+	if rollback := rolledback[0].Rollback; rollback == nil || !reflect.DeepEqual(rollback.RolledBack, want) || len(rollback.Leftovers) != 0 {
+		t.Errorf("expected the rollback %v, got %+v", want, rollback)
+	}
+
+	// the stopped servers are resized and left stopped.
+	stop := UpdateServerInput{InstanceIds: []string{server.InstanceId}, Action: "stop"}
+	if _, err := stop.UpdateServer(fakeConnection(cloud, "ec2")); err != nil {
+		t.Fatalf("stopping server: %v", err)
+	}
+	resize.InstanceType = "t2.small"
+	resized, err = resize.UpdateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("resizing stopped server: %v", err)
+	}
+	if resized[0].After.InstanceType != "t2.small" || resized[0].After.State != "stopped" {
+		t.Errorf("expected the server to be resized and left stopped, got %+v", resized[0].After)
+	}
+}
+
+func TestUpdateServerInvalid(t *testing.T) {
+	cloud := awsfake.New()
+	server, _ := createTestServer(t, cloud)
+
+	calls := cloud.Calls("DescribeInstances")
+	for _, update := range []UpdateServerInput{
+		{InstanceIds: []string{server.InstanceId}, Action: "resize"},
+		{InstanceIds: []string{server.InstanceId}, Action: "userdata"},
+		{InstanceIds: []string{server.InstanceId}, Action: "securitygroups"},
+	} {
+		if _, err := update.UpdateServer(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+			t.Errorf("expected %+v to be rejected as invalid input, got %v", update, err)
+		}
+	}
+	unknown := UpdateServerInput{InstanceIds: []string{server.InstanceId}, Action: "hibernate"}
+	if _, err := unknown.UpdateServer(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+		t.Errorf("expected the unknown action to be rejected as invalid input, got %v", err)
+	}
+	if got := cloud.Calls("DescribeInstances"); got != calls {
+		t.Errorf("expected the invalid updates not to reach aws, got %d calls to DescribeInstances", got-calls)
+	}
+}
+
+func TestPlanUpdateServer(t *testing.T) {
+	cloud := awsfake.New()
+	server, _ := createTestServer(t, cloud)
+
+	resize := UpdateServerInput{InstanceIds: []string{server.InstanceId}, Action: "resize", InstanceType: "t2.medium"}
+	plan, err := resize.PlanUpdateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("planning resize of server: %v", err)
+	}
+	if got := plan.Operations(); !reflect.DeepEqual(got, []string{"StopInstances", "ModifyInstanceAttribute", "StartInstances"}) ||
+		plan.Actions[1].Details["type"] != "t2.medium" || plan.Actions[2].Verified {
+		t.Errorf("unexpected plan %+v", plan)
+	}
+
+	protect := UpdateServerInput{InstanceIds: []string{server.InstanceId}, Action: "protect"}
+	plan, err = protect.PlanUpdateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("planning protection of server: %v", err)
+	}
+	if got := plan.Operations(); !reflect.DeepEqual(got, []string{"ModifyInstanceAttribute"}) || !plan.Actions[0].Verified {
+		t.Errorf("unexpected plan %+v", plan)
+	}
+
+	con := fakeConnection(cloud, "ec2")
+	sess, err := con.EstablishConnection()
+	if err != nil {
+		t.Fatalf("establishing connection: %v", err)
+	}
+	attributes, err := serverAttributes(sess, []string{server.InstanceId}, false)
+	if err != nil {
+		t.Fatalf("fetching server: %v", err)
+	}
+	if attributes[0].InstanceType != "t2.micro" || attributes[0].State != "running" || attributes[0].DisableApiTermination {
+		t.Errorf("expected the server to be left as is while planning, got %+v", attributes[0])
+	}
+}
@@ -0,0 +1,272 @@
+package aws
+
+import (
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// ServerVolume is an ebs volume to be created and attached to the instance while it is created.
+type ServerVolume struct {
+	// DeviceName is the name of the device at which the volume is attached ex: /dev/sdf.
+	// It defaults to the root device of the image for the root volume, and to the next free one from /dev/sdf for the others.
+	DeviceName string `json:"devicename"`
+	// Root marks the volume as the root volume of the instance, the values which are not set are taken from the image.
+	Root bool `json:"root"`
+	// Size of the volume in GiB, it is required for the volumes other than the root one.
+	Size int64 `json:"size"`
+	// Type of the volume gp2, io1 or st1, defaults to gp2.
+	Type string `json:"type"`
+	// Iops to be provisioned for the volume, it is required for io1 and not accepted for the other types.
+	Iops int64 `json:"iops"`
+	// Encrypted encrypts the volume with the key KmsKeyId, or with the default key of ebs if KmsKeyId is not set.
+	Encrypted bool `json:"encrypted"`
+	// KmsKeyId is the ID or ARN of the key of kms with which the volume is encrypted.
+	KmsKeyId string `json:"kmskeyid"`
+	// DeleteOnTermination decides whether the volume is deleted along with the instance, defaults to true.
+	DeleteOnTermination *bool `json:"deleteontermination,omitempty"`
+}
+
+// AttachedVolume holds the details of the volume attached to an instance.
+type AttachedVolume struct {
+	// VolumeId is the ID of the volume.
+	VolumeId string `json:"VolumeId,omitempty"`
+	// DeviceName is the name of the device at which the volume is attached.
+	DeviceName string `json:"DeviceName,omitempty"`
+	// Size of the volume in GiB.
+	Size int64 `json:"Size,omitempty"`
+	// Type of the volume ex: gp2, io1.
+	Type string `json:"Type,omitempty"`
+	// Iops provisioned for the volume.
+	Iops int64 `json:"Iops,omitempty"`
+	// Encrypted states whether the volume is encrypted.
+	Encrypted bool `json:"Encrypted"`
+	// KmsKeyId is the ARN of the key of kms with which the volume is encrypted.
+	KmsKeyId string `json:"KmsKeyId,omitempty"`
+	// DeleteOnTermination states whether the volume is deleted along with the instance.
+	DeleteOnTermination bool `json:"DeleteOnTermination"`
+	// Root states whether the volume is the root volume of the instance.
+	Root bool `json:"Root,omitempty"`
+}
+
+// the range of size in GiB accepted for the volumes of each type.
+var volumeSizes = map[string][2]int64{
+	ec2.VolumeTypeGp2: {1, 16384},
+	ec2.VolumeTypeIo1: {4, 16384},
+	ec2.VolumeTypeSt1: {500, 16384},
+}
+
+const (
+	defaultRootDevice = "/dev/xvda"
+	// the devices to which the volumes other than the root one are attached, when the device is not passed.
+	firstDevice = 'f'
+	lastDevice  = 'p'
+)
+
+// validate validates the volume the way aws does, so that the creation of the instance does not fail midway because of it.
+func (v ServerVolume) validate() error {
+	return v.check(!v.Root)
+}
+
+// check validates the values of the volume, the size is validated only if it is set when sizeRequired is false.
+func (v ServerVolume) check(sizeRequired bool) error {
+
+	volumeType := v.Type
+	if volumeType == "" {
+		volumeType = ec2.VolumeTypeGp2
+	}
+	sizes, ok := volumeSizes[volumeType]
+	if !ok {
+		return cloudyerror.Newf(cloudyerror.InvalidInput, "type %s of the volume is not supported, the volume should be one of gp2, io1 or st1", v.Type)
+	}
+	if v.Root && volumeType == ec2.VolumeTypeSt1 {
+		return cloudyerror.New(cloudyerror.InvalidInput, "st1 volume cannot be the root volume of the instance")
+	}
+	if (v.Size != 0 || sizeRequired) && (v.Size < sizes[0] || v.Size > sizes[1]) {
+		return cloudyerror.Newf(cloudyerror.InvalidInput, "size of the %s volume should be between %d and %d GiB, got %d", volumeType, sizes[0], sizes[1], v.Size)
+	}
+	switch {
+	case volumeType != ec2.VolumeTypeIo1 && v.Iops != 0:
+		return cloudyerror.Newf(cloudyerror.InvalidInput, "iops can be provisioned only for io1 volumes, not for %s", volumeType)
+	case volumeType == ec2.VolumeTypeIo1 && (v.Iops < 100 || v.Iops > 64000):
+		return cloudyerror.Newf(cloudyerror.InvalidInput, "iops of the io1 volume should be between 100 and 64000, got %d", v.Iops)
+	case volumeType == ec2.VolumeTypeIo1 && v.Size != 0 && v.Iops > 50*v.Size:
+		return cloudyerror.Newf(cloudyerror.InvalidInput, "iops of the io1 volume can be at most 50 per GiB, %d is too high for %d GiB", v.Iops, v.Size)
+	}
+	if v.KmsKeyId != "" && !v.Encrypted {
+		return cloudyerror.New(cloudyerror.InvalidInput, "KmsKeyId is valid only for the volumes which are encrypted")
+	}
+	return nil
+}
+
+// mapping translates the volume to the block device mapping of aws, the root volume leaves the values which are not set to the image.
+func (v ServerVolume) mapping() *ec2.BlockDeviceMapping {
+
+	ebs := &ec2.EbsBlockDevice{DeleteOnTermination: v.DeleteOnTermination}
+	if v.Size != 0 {
+		ebs.VolumeSize = awssdk.Int64(v.Size)
+	}
+	if v.Type != "" || !v.Root {
+		ebs.VolumeType = awssdk.String(ec2.VolumeTypeGp2)
+		if v.Type != "" {
+			ebs.VolumeType = awssdk.String(v.Type)
+		}
+	}
+	if v.Iops != 0 {
+		ebs.Iops = awssdk.Int64(v.Iops)
+	}
+	if v.Encrypted {
+		ebs.Encrypted = awssdk.Bool(true)
+	}
+	if v.KmsKeyId != "" {
+		ebs.KmsKeyId = awssdk.String(v.KmsKeyId)
+	}
+	return &ec2.BlockDeviceMapping{DeviceName: awssdk.String(v.DeviceName), Ebs: ebs}
+}
+
+// blockDeviceMappings validates the volumes of the input and translates them to the block device mappings of aws,
+// none are returned if no volumes are passed so that the instance is created with the volumes of the image.
+func (csrv *CreateServerInput) blockDeviceMappings(sess aws.EstablishedSession) ([]*ec2.BlockDeviceMapping, error) {
+
+	if len(csrv.Volumes) == 0 {
+		return nil, nil
+	}
+
+	roots := 0
+	for _, volume := range csrv.Volumes {
+		if err := volume.validate(); err != nil {
+			return nil, err
+		}
+		if volume.Root {
+			roots++
+		}
+	}
+	if roots > 1 {
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "an instance can have only one root volume, %d were passed", roots)
+	}
+
+	rootDevice, rooterr := csrv.rootDevice(sess)
+	if rooterr != nil {
+		return nil, rooterr
+	}
+
+	used := map[string]bool{rootDevice: true}
+	for _, volume := range csrv.Volumes {
+		if volume.DeviceName == "" || volume.Root {
+			continue
+		}
+		if used[volume.DeviceName] {
+			if volume.DeviceName == rootDevice {
+				return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "%s is the root device of the image, mark the volume as Root to change the root volume", rootDevice)
+			}
+			return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "device %s is used by more than one volume", volume.DeviceName)
+		}
+		used[volume.DeviceName] = true
+	}
+
+	mappings := make([]*ec2.BlockDeviceMapping, 0, len(csrv.Volumes))
+	next := firstDevice
+	for _, volume := range csrv.Volumes {
+		switch {
+		case volume.Root:
+			if volume.DeviceName != "" && volume.DeviceName != rootDevice {
+				return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "root device of the image is %s, the root volume cannot be attached at %s", rootDevice, volume.DeviceName)
+			}
+			volume.DeviceName = rootDevice
+		case volume.DeviceName == "":
+			for next <= lastDevice && used[fmt.Sprintf("/dev/sd%c", next)] {
+				next++
+			}
+			if next > lastDevice {
+				return nil, cloudyerror.New(cloudyerror.InvalidInput, "no free device is left to attach the volume, pass the DeviceName of the volumes")
+			}
+			volume.DeviceName = fmt.Sprintf("/dev/sd%c", next)
+			used[volume.DeviceName] = true
+		}
+		mappings = append(mappings, volume.mapping())
+	}
+	return mappings, nil
+}
+
+// devices returns the names of the devices of the mappings passed.
+func devices(mappings []*ec2.BlockDeviceMapping) []string {
+	names := make([]string, 0, len(mappings))
+	for _, mapping := range mappings {
+		names = append(names, awssdk.StringValue(mapping.DeviceName))
+	}
+	return names
+}
+
+// rootDevice returns the name of the root device of the image of the input, /dev/xvda is assumed for the images which are not visible to the account.
+func (csrv *CreateServerInput) rootDevice(sess aws.EstablishedSession) (string, error) {
+
+	images, imgerr := sess.DescribeImages(&aws.DescribeComputeInput{ImageIds: []string{csrv.ImageId}})
+	if imgerr != nil {
+		if aerr, ok := imgerr.(awserr.Error); ok && (aerr.Code() == "InvalidAMIID.NotFound" || aerr.Code() == "InvalidAMIID.Unavailable") {
+			return defaultRootDevice, nil
+		}
+		return "", imgerr
+	}
+	for _, image := range images.Images {
+		if name := awssdk.StringValue(image.RootDeviceName); name != "" {
+			return name, nil
+		}
+	}
+	return defaultRootDevice, nil
+}
+
+// attachedVolumes fetches the volumes attached to the instances of the reservations passed, keyed by the ID of the instance.
+func attachedVolumes(sess aws.EstablishedSession, reservations []*ec2.Reservation) (map[string][]AttachedVolume, error) {
+
+	instances := make([]*ec2.Instance, 0)
+	for _, reservation := range reservations {
+		instances = append(instances, reservation.Instances...)
+	}
+	attached := make(map[string][]AttachedVolume)
+	ids := make([]string, 0)
+	for _, instance := range instances {
+		for _, mapping := range instance.BlockDeviceMappings {
+			if mapping.Ebs != nil {
+				ids = append(ids, awssdk.StringValue(mapping.Ebs.VolumeId))
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return attached, nil
+	}
+
+	result, err := sess.DescribeVolumes(&aws.VolumeInput{VolumeIds: ids})
+	if err != nil {
+		return nil, err
+	}
+	volumes := make(map[string]*ec2.Volume)
+	for _, volume := range result.Volumes {
+		volumes[awssdk.StringValue(volume.VolumeId)] = volume
+	}
+	for _, instance := range instances {
+		for _, mapping := range instance.BlockDeviceMappings {
+			if mapping.Ebs == nil {
+				continue
+			}
+			device := AttachedVolume{
+				VolumeId:            awssdk.StringValue(mapping.Ebs.VolumeId),
+				DeviceName:          awssdk.StringValue(mapping.DeviceName),
+				DeleteOnTermination: awssdk.BoolValue(mapping.Ebs.DeleteOnTermination),
+				Root:                awssdk.StringValue(mapping.DeviceName) == awssdk.StringValue(instance.RootDeviceName),
+			}
+			if volume, ok := volumes[device.VolumeId]; ok {
+				device.Size = awssdk.Int64Value(volume.Size)
+				device.Type = awssdk.StringValue(volume.VolumeType)
+				device.Iops = awssdk.Int64Value(volume.Iops)
+				device.Encrypted = awssdk.BoolValue(volume.Encrypted)
+				device.KmsKeyId = awssdk.StringValue(volume.KmsKeyId)
+			}
+			attached[awssdk.StringValue(instance.InstanceId)] = append(attached[awssdk.StringValue(instance.InstanceId)], device)
+		}
+	}
+	return attached, nil
+}
@@ -0,0 +1,171 @@
+package aws
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// SnapshotInput holds the values required to create/fetch/delete the snapshots of the ebs volumes and to restore them as volumes.
+type SnapshotInput struct {
+	// SnapshotIds are the IDs of the snapshots to be fetched/deleted, only the first one is restored.
+	SnapshotIds []string `json:"snapshotids"`
+	// VolumeId is the ID of the volume of which the snapshot has to be created, or of which the snapshots has to be fetched.
+	VolumeId string `json:"volumeid"`
+	// Name of the snapshot to be created.
+	Name string `json:"name"`
+	// Description of the snapshot to be created.
+	Description string `json:"description"`
+	// Volume holds the details of the volume to be restored out of the snapshot, the size defaults to the one of the snapshot.
+	Volume VolumeInput `json:"volume"`
+	GetRaw bool        `json:"getraw"`
+}
+
+// SnapshotResponse holds the details of the snapshot of the ebs volume.
+type SnapshotResponse struct {
+	// Name of the snapshot.
+	Name string `json:"Name,omitempty"`
+	// SnapshotId is the ID of the snapshot.
+	SnapshotId string `json:"SnapshotId,omitempty"`
+	// VolumeId is the ID of the volume of which the snapshot was created.
+	VolumeId string `json:"VolumeId,omitempty"`
+	// Size of the volume captured in GiB.
+	Size int64 `json:"Size,omitempty"`
+	// State of the snapshot ex: pending, completed, deleted.
+	State string `json:"State,omitempty"`
+	// Progress of the creation of the snapshot ex: 100%.
+	Progress string `json:"Progress,omitempty"`
+	// Encrypted states whether the snapshot is encrypted.
+	Encrypted bool `json:"Encrypted"`
+	// Description of the snapshot.
+	Description string `json:"Description,omitempty"`
+	// StartTime is the time at which the creation of the snapshot started.
+	StartTime string `json:"StartTime,omitempty"`
+	// SnapshotRaw holds the unfiltered response from aws for the snapshot.
+	SnapshotRaw *ec2.Snapshot `json:"SnapshotRaw,omitempty"`
+}
+
+// CreateSnapshot captures the snapshot of the volume passed and waits till it is completed.
+// The snapshot is deleted back if it could not be named.
+func (s *SnapshotInput) CreateSnapshot(con aws.EstablishConnectionInput) (SnapshotResponse, error) {
+
+	if s.VolumeId == "" {
+		return SnapshotResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the volume of which the snapshot has to be created")
+	}
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return SnapshotResponse{}, seserr
+	}
+	snapshot, snaperr := sess.CreateSnapshot(&aws.VolumeInput{VolumeIds: []string{s.VolumeId}, Description: s.Description})
+	if snaperr != nil {
+		return SnapshotResponse{}, snaperr
+	}
+	snapshotID := *snapshot.SnapshotId
+
+	if s.Name != "" {
+		tags := Tag{Resource: snapshotID, Name: "Name", Value: s.Name}
+		if _, tagerr := tags.CreateTags(con); tagerr != nil {
+			sess.DeleteSnapshot(&aws.DeleteComputeInput{SnapshotId: snapshotID})
+			return SnapshotResponse{}, tagerr
+		}
+	}
+	input := &aws.VolumeInput{SnapshotIds: []string{snapshotID}}
+	if waiterr := sess.WaitTillSnapshotCompleted(input); waiterr != nil {
+		return SnapshotResponse{}, waiterr
+	}
+	snapshots, geterr := sess.DescribeSnapshots(input)
+	if geterr != nil {
+		return SnapshotResponse{}, geterr
+	}
+	if len(snapshots.Snapshots) == 0 {
+		return SnapshotResponse{}, cloudyerror.Newf(cloudyerror.NotFound, "snapshot %s is not found", snapshotID)
+	}
+	return snapshotResponse(snapshots.Snapshots[0], s.GetRaw), nil
+}
+
+// GetSnapshots fetches the snapshots passed, the ones of the volume passed or all the ones owned by the account in the region.
+func (s *SnapshotInput) GetSnapshots(con aws.EstablishConnectionInput) ([]SnapshotResponse, error) {
+
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return nil, seserr
+	}
+	var snapshots *ec2.DescribeSnapshotsOutput
+	var err error
+	switch {
+	case len(s.SnapshotIds) != 0:
+		snapshots, err = sess.DescribeSnapshots(&aws.VolumeInput{SnapshotIds: s.SnapshotIds})
+	case s.VolumeId != "":
+		snapshots, err = sess.DescribeSnapshots(&aws.VolumeInput{Filters: aws.Filters{Name: "volume-id", Value: []string{s.VolumeId}}})
+	default:
+		snapshots, err = sess.DescribeAllSnapshots(&aws.VolumeInput{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	response := make([]SnapshotResponse, 0, len(snapshots.Snapshots))
+	for _, snapshot := range snapshots.Snapshots {
+		response = append(response, snapshotResponse(snapshot, s.GetRaw))
+	}
+	return response, nil
+}
+
+// DeleteSnapshot deletes the snapshots passed, the ones deleted before the failure are returned along with the error.
+// The snapshots used by the images cannot be deleted, the images have to be deleted instead.
+func (s *SnapshotInput) DeleteSnapshot(con aws.EstablishConnectionInput) ([]SnapshotResponse, error) {
+
+	if len(s.SnapshotIds) == 0 {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the snapshots to be deleted")
+	}
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return nil, seserr
+	}
+	snapshots, geterr := sess.DescribeSnapshots(&aws.VolumeInput{SnapshotIds: s.SnapshotIds})
+	if geterr != nil {
+		return nil, geterr
+	}
+
+	response := make([]SnapshotResponse, 0, len(snapshots.Snapshots))
+	for _, snapshot := range snapshots.Snapshots {
+		if delerr := sess.DeleteSnapshot(&aws.DeleteComputeInput{SnapshotId: *snapshot.SnapshotId}); delerr != nil {
+			return response, delerr
+		}
+		response = append(response, SnapshotResponse{Name: nameOf(snapshot.Tags), SnapshotId: *snapshot.SnapshotId, VolumeId: awssdk.StringValue(snapshot.VolumeId), State: "deleted"})
+	}
+	return response, nil
+}
+
+// RestoreSnapshot creates the volume described by Volume out of the first of the snapshots passed, and waits till it is available.
+func (s *SnapshotInput) RestoreSnapshot(con aws.EstablishConnectionInput) (VolumeResponse, error) {
+
+	if len(s.SnapshotIds) == 0 {
+		return VolumeResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the snapshot to be restored")
+	}
+	volume := s.Volume
+	volume.SnapshotId = s.SnapshotIds[0]
+	volume.GetRaw = s.GetRaw
+	return volume.CreateVolume(con)
+}
+
+// snapshotResponse returns the details of the snapshot, the unfiltered response of aws is returned if raw is set.
+func snapshotResponse(snapshot *ec2.Snapshot, raw bool) SnapshotResponse {
+	if raw {
+		return SnapshotResponse{SnapshotRaw: snapshot}
+	}
+	response := SnapshotResponse{
+		Name:        nameOf(snapshot.Tags),
+		SnapshotId:  awssdk.StringValue(snapshot.SnapshotId),
+		VolumeId:    awssdk.StringValue(snapshot.VolumeId),
+		Size:        awssdk.Int64Value(snapshot.VolumeSize),
+		State:       awssdk.StringValue(snapshot.State),
+		Progress:    awssdk.StringValue(snapshot.Progress),
+		Encrypted:   awssdk.BoolValue(snapshot.Encrypted),
+		Description: awssdk.StringValue(snapshot.Description),
+	}
+	if snapshot.StartTime != nil {
+		response.StartTime = snapshot.StartTime.String()
+	}
+	return response
+}
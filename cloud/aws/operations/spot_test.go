@@ -0,0 +1,175 @@
+package aws
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	awsfake "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/fake"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+func TestCreateSpotServer(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	server := CreateServerInput{InstanceName: "worker", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id,
+		MaxCount: 2, MarketType: "spot", MaxPrice: "0.01"}
+	servers, err := server.CreateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating spot server: %v", err)
+	}
+	for _, created := range servers {
+		if created.Lifecycle != "spot" || created.SpotInstanceRequestId == "" {
+			t.Errorf("expected server %s to be launched as spot instance, got %+v", created.InstanceId, created)
+		}
+	}
+
+	onDemand := CreateServerInput{InstanceName: "web", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id}
+	servers, err = onDemand.CreateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating server: %v", err)
+	}
+	if servers[0].Lifecycle != "" || servers[0].SpotInstanceRequestId != "" {
+		t.Errorf("expected the server to be launched on-demand, got %+v", servers[0])
+	}
+
+	// the spot price of the zone is above the max price, hence no instance is launched.
+	cloud.SetSpotPrice("t2.micro", "us-east-1a", 0.02)
+	cloud.SetSpotPrice("t2.micro", "us-east-1b", 0.02)
+	if _, err := server.CreateServer(fakeConnection(cloud, "ec2")); err == nil {
+		t.Errorf("expected the spot server not to be launched below the spot price")
+	}
+
+	stop := CreateServerInput{InstanceName: "stoppable", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id,
+		MarketType: "Spot", InterruptionBehavior: "stop"}
+	if _, err := stop.CreateServer(fakeConnection(cloud, "ec2")); err != nil {
+		t.Errorf("expected the spot server to be stopped on interruption to be launched, got %v", err)
+	}
+}
+
+func TestCreateSpotServerInvalid(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	for _, server := range []CreateServerInput{
+		{MarketType: "reserved"},
+		{MaxPrice: "0.01"},
+		{MarketType: "on-demand", InterruptionBehavior: "stop"},
+		{MarketType: "spot", MaxPrice: "cheap"},
+		{MarketType: "spot", MaxPrice: "-1"},
+		{MarketType: "spot", InterruptionBehavior: "pause"},
+	} {
+		server.InstanceName, server.ImageId, server.InstanceType, server.SubnetId = "worker", "ami-0123456789", "t2.micro", network.Subnets[0].Id
+		if _, err := server.CreateServer(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+			t.Errorf("expected %+v to be rejected as invalid input, got %v", server, err)
+		}
+		if _, err := server.PlanCreateServer(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+			t.Errorf("expected the plan of %+v to be rejected as invalid input, got %v", server, err)
+		}
+	}
+	if calls := cloud.Calls("RunInstances"); calls != 0 {
+		t.Errorf("expected the invalid servers not to reach aws, got %d calls to RunInstances", calls)
+	}
+}
+
+func TestGetSpotPrices(t *testing.T) {
+	cloud := awsfake.New()
+	cloud.SetSpotPrice("t2.medium", "us-east-1a", 0.001)
+
+	spot := SpotPriceInput{InstanceTypes: []string{"t2.micro", "t2.medium"}}
+	prices, err := spot.GetSpotPrices(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("fetching spot prices: %v", err)
+	}
+	got := make([]string, 0)
+	for _, price := range prices {
+		if price.ProductDescription != "Linux/UNIX" {
+			t.Errorf("expected the prices of Linux/UNIX, got %+v", price)
+		}
+		got = append(got, price.InstanceType+" "+price.Zone+" "+price.Price)
+	}
+	want := []string{"t2.medium us-east-1a 0.001000", "t2.micro us-east-1b 0.003150", "t2.micro us-east-1a 0.003500", "t2.medium us-east-1b 0.012510"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the prices cheapest first %v, got %v", want, got)
+	}
+
+	zonal := SpotPriceInput{InstanceTypes: []string{"t2.micro"}, Zones: []string{"us-east-1a"}, ProductDescription: "Windows", GetRaw: true}
+	prices, err = zonal.GetSpotPrices(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("fetching spot prices: %v", err)
+	}
+	if len(prices) != 1 || *prices[0].SpotPriceRaw.AvailabilityZone != "us-east-1a" || *prices[0].SpotPriceRaw.SpotPrice != "0.007000" {
+		t.Errorf("expected the raw price of the zone selected, got %+v", prices)
+	}
+
+	empty := SpotPriceInput{}
+	if _, err := empty.GetSpotPrices(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+		t.Errorf("expected the instance types to be required, got %v", err)
+	}
+}
+
+func TestPlanCreateSpotServer(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	server := CreateServerInput{InstanceName: "worker", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id,
+		MarketType: "spot", MaxPrice: "0.01", InterruptionBehavior: "hibernate"}
+	plan, err := server.PlanCreateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("planning spot server: %v", err)
+	}
+	details := plan.Actions[0].Details
+	if details["market"] != "spot" || details["maxprice"] != "0.01" || details["interruptionbehavior"] != "hibernate" || !plan.Actions[0].Verified {
+		t.Errorf("unexpected plan %+v", plan)
+	}
+}
+
+func TestDeleteSpotServer(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	// the persistent spot request would launch the server again, unless it is cancelled before terminating the server.
+	server := CreateServerInput{InstanceName: "worker", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id,
+		MarketType: "spot", InterruptionBehavior: "stop"}
+	servers, err := server.CreateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating spot server: %v", err)
+	}
+	onDemand := CreateServerInput{InstanceName: "web", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id}
+	if _, err := onDemand.CreateServer(fakeConnection(cloud, "ec2")); err != nil {
+		t.Fatalf("creating server: %v", err)
+	}
+
+	del := DeleteServerInput{InstanceIds: []string{servers[0].InstanceId}}
+	plan, err := del.PlanDeleteServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("planning deletion of spot server: %v", err)
+	}
+	if got := plan.Operations(); !reflect.DeepEqual(got, []string{"CancelSpotInstanceRequests", "TerminateInstances"}) || plan.Actions[0].Target != servers[0].SpotInstanceRequestId {
+		t.Fatalf("unexpected plan %+v", plan)
+	}
+	if _, err := del.DeleteServer(fakeConnection(cloud, "ec2")); err != nil {
+		t.Fatalf("deleting spot server: %v", err)
+	}
+	requests, err := cloud.EC2(testRegion).DescribeSpotInstanceRequestsWithContext(context.Background(), &ec2.DescribeSpotInstanceRequestsInput{
+		SpotInstanceRequestIds: awssdk.StringSlice([]string{servers[0].SpotInstanceRequestId}),
+	})
+	if err != nil {
+		t.Fatalf("describing the spot request: %v", err)
+	}
+	if state := awssdk.StringValue(requests.SpotInstanceRequests[0].State); state != ec2.SpotInstanceStateCancelled {
+		t.Errorf("expected the spot request to be cancelled before terminating the server, got %s", state)
+	}
+
+	// the servers on-demand have no spot request to be cancelled.
+	vpc := DeleteServerInput{VpcId: network.VpcId}
+	if _, err := vpc.DeleteServerFromVpc(fakeConnection(cloud, "ec2")); err != nil {
+		t.Fatalf("deleting servers of network: %v", err)
+	}
+	if calls := cloud.Calls("CancelSpotInstanceRequests"); calls != 2 {
+		t.Errorf("expected the spot request to be cancelled once while planning and once while deleting, got %d calls", calls)
+	}
+}
@@ -0,0 +1,100 @@
+package aws
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// SpotPriceInput holds the values required to fetch the current spot prices, which helps in picking the zone in which the spot instances are the cheapest.
+type SpotPriceInput struct {
+	// InstanceTypes are the types of the instances of which the spot prices has to be fetched ex: t2.micro.
+	InstanceTypes []string `json:"instancetypes"`
+	// Zones are the availability zones in which the spot prices has to be fetched, all the zones of the region are considered if none are passed.
+	Zones []string `json:"zones"`
+	// ProductDescription is the platform of the instances ex: Linux/UNIX (default), Windows.
+	ProductDescription string `json:"productdescription"`
+	GetRaw             bool   `json:"getraw"`
+}
+
+// SpotPriceResponse holds the current spot price of the instance type in the zone.
+type SpotPriceResponse struct {
+	// InstanceType of which the price is.
+	InstanceType string `json:"InstanceType,omitempty"`
+	// Zone in which the price is applicable.
+	Zone string `json:"Zone,omitempty"`
+	// ProductDescription is the platform of the instances of which the price is.
+	ProductDescription string `json:"ProductDescription,omitempty"`
+	// Price is the hourly price in USD.
+	Price string `json:"Price,omitempty"`
+	// Timestamp is the time from which the price is effective.
+	Timestamp string `json:"Timestamp,omitempty"`
+	// SpotPriceRaw holds the unfiltered response from aws for the price.
+	SpotPriceRaw *ec2.SpotPrice `json:"SpotPriceRaw,omitempty"`
+}
+
+// GetSpotPrices fetches the current spot price of the instance types passed in each of the zones, the cheapest are first.
+func (s *SpotPriceInput) GetSpotPrices(con aws.EstablishConnectionInput) ([]SpotPriceResponse, error) {
+
+	if len(s.InstanceTypes) == 0 {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the instance types of which the spot prices has to be fetched")
+	}
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return nil, seserr
+	}
+	product := s.ProductDescription
+	if product == "" {
+		product = "Linux/UNIX"
+	}
+
+	// aws reports the current price of each instance type in each zone when the history is asked from now.
+	history, err := sess.DescribeSpotPriceHistory(&aws.SpotPriceInput{InstanceTypes: s.InstanceTypes, Zones: s.Zones, ProductDescriptions: []string{product}, StartTime: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[string]*ec2.SpotPrice)
+	for _, price := range history {
+		key := awssdk.StringValue(price.InstanceType) + "/" + awssdk.StringValue(price.AvailabilityZone)
+		if current, ok := latest[key]; !ok || awssdk.TimeValue(price.Timestamp).After(awssdk.TimeValue(current.Timestamp)) {
+			latest[key] = price
+		}
+	}
+
+	prices := make([]*ec2.SpotPrice, 0, len(latest))
+	for _, price := range latest {
+		prices = append(prices, price)
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		left, _ := strconv.ParseFloat(awssdk.StringValue(prices[i].SpotPrice), 64)
+		right, _ := strconv.ParseFloat(awssdk.StringValue(prices[j].SpotPrice), 64)
+		if left != right {
+			return left < right
+		}
+		if awssdk.StringValue(prices[i].InstanceType) != awssdk.StringValue(prices[j].InstanceType) {
+			return awssdk.StringValue(prices[i].InstanceType) < awssdk.StringValue(prices[j].InstanceType)
+		}
+		return awssdk.StringValue(prices[i].AvailabilityZone) < awssdk.StringValue(prices[j].AvailabilityZone)
+	})
+
+	response := make([]SpotPriceResponse, 0, len(prices))
+	for _, price := range prices {
+		if s.GetRaw {
+			response = append(response, SpotPriceResponse{SpotPriceRaw: price})
+			continue
+		}
+		response = append(response, SpotPriceResponse{
+			InstanceType:       awssdk.StringValue(price.InstanceType),
+			Zone:               awssdk.StringValue(price.AvailabilityZone),
+			ProductDescription: awssdk.StringValue(price.ProductDescription),
+			Price:              awssdk.StringValue(price.SpotPrice),
+			Timestamp:          awssdk.TimeValue(price.Timestamp).String(),
+		})
+	}
+	return response, nil
+}
@@ -2,11 +2,40 @@ package aws
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/nikhilsbhat/neuron-cloudy/cidr"
 	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
 )
 
+// SubnetLayout lays out the subnets of the network when their CIDR blocks are not passed, the blocks of the prefix length
+// passed are carved in order out of the block of the network, skipping the ones taken by the subnets present in it.
+// Either Count or the counts per zone has to be set.
+type SubnetLayout struct {
+	// Count is the number of subnets to be created, spread across the zones. The subnets are of the type of the network.
+	Count int `json:"count"`
+	// PublicPerZone is the number of public subnets (routed to the internet gateway) to be created in each zone.
+	PublicPerZone int `json:"publicperzone"`
+	// PrivatePerZone is the number of private subnets to be created in each zone.
+	PrivatePerZone int `json:"privateperzone"`
+	// Zones is the number of zones in which the subnets are created per zone, all the zones of the region are used if not set.
+	Zones int `json:"zones"`
+	// PrefixLength of the blocks of the subnets ex: 24 for /24.
+	PrefixLength int `json:"prefixlength"`
+}
+
+// subnetSpec is the subnet to be created, as laid out by layoutSubnets.
+type subnetSpec struct {
+	cidr, zone, subnetType string
+}
+
+// isSet reports whether the layout asks for any subnet.
+func (l SubnetLayout) isSet() bool {
+	return l.Count != 0 || l.PublicPerZone != 0 || l.PrivatePerZone != 0
+}
+
 // SubnetReponse is a struct that will be the response type of almost all the subnet related activities under cloud/operations.
 type SubnetReponse struct {
 	// Name of the subnetwork created/updated/retrieved.
@@ -16,7 +45,9 @@ type SubnetReponse struct {
 	// State of the subnetwork ex: pending,deleted and etc.
 	State string `json:"State,omitempty"`
 	// VpcId refers to an Id of network of which subnetwork is part of.
-	VpcId           string                     `json:"VpcId,omitempty"`
+	VpcId string `json:"VpcId,omitempty"`
+	// Cidr is the block of IP addresses of the subnetwork.
+	Cidr            string                     `json:"Cidr,omitempty"`
 	CreateSubnetRaw *ec2.CreateSubnetOutput    `json:"CreateSubnetRaw,omitempty"`
 	GetSubnetRaw    *ec2.DescribeSubnetsOutput `json:"GetSubnetRaw,omitempty"`
 }
@@ -41,6 +72,11 @@ func (subin *NetworkCreateInput) CreateSubnet(con aws.EstablishConnectionInput)
 	if suberr != nil {
 		return SubnetReponse{}, suberr
 	}
+	subid := *sub.Subnet.SubnetId
+	subin.rollback.record("subnet", subid, func(con aws.EstablishConnectionInput) error {
+		deletesubnet := DeleteNetworkInput{SubnetIds: []string{subid}}
+		return deletesubnet.DeleteSubnets(con)
+	})
 
 	// I will make program wait until subnet become available
 	waiterr := ec2.WaitTillSubnetAvailable(
@@ -59,7 +95,7 @@ func (subin *NetworkCreateInput) CreateSubnet(con aws.EstablishConnectionInput)
 	// I will be the spock for tags creation.
 	tags := new(Tag)
 	tags.Resource = *sub.Subnet.SubnetId
-	tags.Resource = "Name"
+	tags.Name = "Name"
 	tags.Value = subin.Name
 	subtag, tagerr := tags.CreateTags(con)
 	if tagerr != nil {
@@ -72,6 +108,7 @@ func (subin *NetworkCreateInput) CreateSubnet(con aws.EstablishConnectionInput)
 	routes.SubId = *sub.Subnet.SubnetId
 	routes.IgwId = subin.IgwId
 	routes.SubType = subin.Type
+	routes.rollback = subin.rollback
 
 	routeerr := routes.CreateRouteTable(con)
 
@@ -83,7 +120,7 @@ func (subin *NetworkCreateInput) CreateSubnet(con aws.EstablishConnectionInput)
 		return SubnetReponse{CreateSubnetRaw: sub}, nil
 	}
 
-	return SubnetReponse{Name: subtag, Id: *sub.Subnet.SubnetId}, nil
+	return SubnetReponse{Name: subtag, Id: *sub.Subnet.SubnetId, Cidr: subin.SubCidr}, nil
 }
 
 // GetAllSubnets is a customized method for fetching details of all subnets for a given region, if one needs plain get subnet then he/she has to call the GOD, interface which talks to cloud.
@@ -107,7 +144,7 @@ func (net *GetNetworksInput) GetAllSubnets(con aws.EstablishConnectionInput) (Ne
 
 	subnets := make([]SubnetReponse, 0)
 	for _, subnet := range result.Subnets {
-		subnets = append(subnets, SubnetReponse{Name: *subnet.Tags[0].Value, Id: *subnet.SubnetId, State: *subnet.State, VpcId: *subnet.VpcId})
+		subnets = append(subnets, SubnetReponse{Name: *subnet.Tags[0].Value, Id: *subnet.SubnetId, State: *subnet.State, VpcId: *subnet.VpcId, Cidr: *subnet.CidrBlock})
 	}
 	return NetworkResponse{Subnets: subnets}, nil
 
@@ -136,9 +173,9 @@ func (net *GetNetworksInput) GetSubnets(con aws.EstablishConnectionInput) (Netwo
 	subnets := make([]SubnetReponse, 0)
 	for _, subnet := range result.Subnets {
 		if subnet.Tags[0] != nil {
-			subnets = append(subnets, SubnetReponse{Name: *subnet.Tags[0].Value, Id: *subnet.SubnetId, State: *subnet.State, VpcId: *subnet.VpcId})
+			subnets = append(subnets, SubnetReponse{Name: *subnet.Tags[0].Value, Id: *subnet.SubnetId, State: *subnet.State, VpcId: *subnet.VpcId, Cidr: *subnet.CidrBlock})
 		} else {
-			subnets = append(subnets, SubnetReponse{Id: *subnet.SubnetId, State: *subnet.State, VpcId: *subnet.VpcId})
+			subnets = append(subnets, SubnetReponse{Id: *subnet.SubnetId, State: *subnet.State, VpcId: *subnet.VpcId, Cidr: *subnet.CidrBlock})
 		}
 	}
 	return NetworkResponse{Subnets: subnets}, nil
@@ -172,9 +209,9 @@ func (net *GetNetworksInput) GetSubnetsFromVpc(con aws.EstablishConnectionInput)
 	subnets := make([]SubnetReponse, 0)
 	for _, subnet := range result.Subnets {
 		if subnet.Tags != nil {
-			subnets = append(subnets, SubnetReponse{Name: *subnet.Tags[0].Value, Id: *subnet.SubnetId, State: *subnet.State})
+			subnets = append(subnets, SubnetReponse{Name: *subnet.Tags[0].Value, Id: *subnet.SubnetId, State: *subnet.State, Cidr: *subnet.CidrBlock})
 		} else {
-			subnets = append(subnets, SubnetReponse{Id: *subnet.SubnetId, State: *subnet.State})
+			subnets = append(subnets, SubnetReponse{Id: *subnet.SubnetId, State: *subnet.State, Cidr: *subnet.CidrBlock})
 		}
 	}
 	return NetworkResponse{VpcId: net.VpcIds[0], Subnets: subnets}, nil
@@ -254,3 +291,82 @@ func (net *GetNetworksInput) GetVpcFromSubnet(con aws.EstablishConnectionInput)
 	}
 	return SubnetReponse{VpcId: *result.Subnets[0].VpcId}, nil
 }
+
+// layoutSubnets returns the subnets to be created in the network of the blocks passed (parents), around the subnets taken.
+// The CIDR blocks passed in SubCidrs are validated for containment and overlap, else they are carved as per Layout.
+// The subnets are spread across the zones starting from the last one, except the ones laid out per zone.
+func (net *NetworkCreateInput) layoutSubnets(zones, parents, taken []string) ([]subnetSpec, error) {
+
+	layout := net.Layout
+	if len(net.SubCidrs) != 0 && layout.isSet() {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "Pass either the CIDR blocks of the subnets or the layout to carve them, not both")
+	}
+	if layout.Count < 0 || layout.PublicPerZone < 0 || layout.PrivatePerZone < 0 || layout.Zones < 0 {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "The counts of the subnet layout cannot be negative")
+	}
+	if len(zones) == 0 {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "There are no availability zones to create the subnets in")
+	}
+
+	blocks := net.SubCidrs
+	switch {
+	case len(net.SubCidrs) != 0:
+		if err := cidr.Validate(parents, net.SubCidrs, taken); err != nil {
+			return nil, err
+		}
+	case layout.Count != 0:
+		if layout.PublicPerZone+layout.PrivatePerZone != 0 {
+			return nil, cloudyerror.New(cloudyerror.InvalidInput, "Pass either the number of subnets or the number of subnets per zone, not both")
+		}
+		carved, err := cidr.Carve(parents, layout.PrefixLength, layout.Count, taken)
+		if err != nil {
+			return nil, err
+		}
+		blocks = carved
+	case layout.PublicPerZone+layout.PrivatePerZone != 0:
+		return net.layoutSubnetsPerZone(zones, parents, taken)
+	}
+
+	subnets := make([]subnetSpec, 0, len(blocks))
+	zonenum := len(zones) - 1
+	for _, block := range blocks {
+		if zonenum < 0 {
+			zonenum = len(zones) - 1
+		}
+		subnets = append(subnets, subnetSpec{cidr: block, zone: zones[zonenum], subnetType: net.Type})
+		zonenum--
+	}
+	return subnets, nil
+}
+
+// layoutSubnetsPerZone carves the public and private subnets of each zone, the public ones first.
+func (net *NetworkCreateInput) layoutSubnetsPerZone(zones, parents, taken []string) ([]subnetSpec, error) {
+
+	layout := net.Layout
+	if layout.PublicPerZone != 0 && strings.ToLower(net.Type) == "private" {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "The public subnets cannot be laid out in the private network, as it has no internet gateway")
+	}
+	if layout.Zones > len(zones) {
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The subnets cannot be laid out in %d zones, there are only %d zones %v", layout.Zones, len(zones), zones)
+	}
+	if layout.Zones != 0 {
+		zones = zones[:layout.Zones]
+	}
+	perzone := layout.PublicPerZone + layout.PrivatePerZone
+	blocks, err := cidr.Carve(parents, layout.PrefixLength, perzone*len(zones), taken)
+	if err != nil {
+		return nil, err
+	}
+
+	subnets := make([]subnetSpec, 0, len(blocks))
+	for i, zone := range zones {
+		for j := 0; j < perzone; j++ {
+			subnetType := "public"
+			if j >= layout.PublicPerZone {
+				subnetType = "private"
+			}
+			subnets = append(subnets, subnetSpec{cidr: blocks[i*perzone+j], zone: zone, subnetType: subnetType})
+		}
+	}
+	return subnets, nil
+}
@@ -0,0 +1,354 @@
+package aws
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// The methods below manage the ebs volumes on their own, the volumes created along with the instances are managed by the methods of the servers.
+// Every change made on the volume is waited till aws completes it, so that the volume returned is in the state it was asked to be.
+// The resize is waited till the modification reaches optimizing, as the volume is usable at its new size from then on while aws optimizes it for hours.
+
+const (
+	// VolumeAttach attaches the volume to the instance.
+	VolumeAttach = "attach"
+	// VolumeDetach detaches the volume from the instance it is attached to.
+	VolumeDetach = "detach"
+	// VolumeResize changes the size, type or iops of the volume.
+	VolumeResize = "resize"
+)
+
+// VolumeInput holds the values required to create/fetch/update/delete the ebs volumes.
+type VolumeInput struct {
+	// VolumeIds are the IDs of the volumes to be fetched/updated/deleted.
+	VolumeIds []string `json:"volumeids"`
+	// Name of the volume to be created.
+	Name string `json:"name"`
+	// Zone is the availability zone in which the volume has to be created, the volume can be attached only to the instances in the same zone.
+	Zone string `json:"zone"`
+	// Size of the volume in GiB, it is required unless the volume is restored from the snapshot.
+	// The volume can only grow on resize.
+	Size int64 `json:"size"`
+	// Type of the volume gp2, io1 or st1, defaults to gp2 on creation.
+	Type string `json:"type"`
+	// Iops to be provisioned for the volume, it is required for io1 and not accepted for the other types.
+	Iops int64 `json:"iops"`
+	// Encrypted encrypts the volume with the key KmsKeyId, or with the default key of ebs if KmsKeyId is not set.
+	Encrypted bool `json:"encrypted"`
+	// KmsKeyId is the ID or ARN of the key of kms with which the volume is encrypted.
+	KmsKeyId string `json:"kmskeyid"`
+	// SnapshotId is the ID of the snapshot out of which the volume has to be created.
+	SnapshotId string `json:"snapshotid"`
+	// InstanceId is the ID of the instance to which the volume has to be attached, or of which the volumes has to be fetched.
+	InstanceId string `json:"instanceid"`
+	// DeviceName is the name of the device at which the volume has to be attached ex: /dev/sdf.
+	DeviceName string `json:"devicename"`
+	// Force detaches the volume even if the instance did not unmount it, the data which is not yet written to the volume is lost.
+	Force bool `json:"force"`
+	// Action to be performed by UpdateVolume, attach, detach or resize.
+	Action string `json:"action"`
+	GetRaw bool   `json:"getraw"`
+}
+
+// VolumeResponse holds the details of the ebs volume.
+type VolumeResponse struct {
+	// Name of the volume.
+	Name string `json:"Name,omitempty"`
+	// VolumeId is the ID of the volume.
+	VolumeId string `json:"VolumeId,omitempty"`
+	// Zone is the availability zone of the volume.
+	Zone string `json:"Zone,omitempty"`
+	// Size of the volume in GiB.
+	Size int64 `json:"Size,omitempty"`
+	// Type of the volume ex: gp2, io1.
+	Type string `json:"Type,omitempty"`
+	// Iops provisioned for the volume.
+	Iops int64 `json:"Iops,omitempty"`
+	// Encrypted states whether the volume is encrypted.
+	Encrypted bool `json:"Encrypted"`
+	// KmsKeyId is the ARN of the key of kms with which the volume is encrypted.
+	KmsKeyId string `json:"KmsKeyId,omitempty"`
+	// SnapshotId is the ID of the snapshot out of which the volume was created.
+	SnapshotId string `json:"SnapshotId,omitempty"`
+	// State of the volume ex: available, in-use, deleted.
+	State string `json:"State,omitempty"`
+	// InstanceId is the ID of the instance to which the volume is attached.
+	InstanceId string `json:"InstanceId,omitempty"`
+	// DeviceName is the name of the device at which the volume is attached.
+	DeviceName string `json:"DeviceName,omitempty"`
+	// DeleteOnTermination states whether the volume is deleted along with the instance it is attached to.
+	DeleteOnTermination bool `json:"DeleteOnTermination,omitempty"`
+	// CreateTime is the time at which the volume was created.
+	CreateTime string `json:"CreateTime,omitempty"`
+	// VolumeRaw holds the unfiltered response from aws for the volume.
+	VolumeRaw *ec2.Volume `json:"VolumeRaw,omitempty"`
+}
+
+// CreateVolume creates the volume in the zone passed, either of the size passed or out of the snapshot passed, and waits till it is available.
+// The volume is deleted back if it does not become available or could not be named.
+func (v *VolumeInput) CreateVolume(con aws.EstablishConnectionInput) (VolumeResponse, error) {
+
+	if verr := v.validateCreate(); verr != nil {
+		return VolumeResponse{}, verr
+	}
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return VolumeResponse{}, seserr
+	}
+	volume, createrr := sess.CreateVolume(v.createInput())
+	if createrr != nil {
+		return VolumeResponse{}, createrr
+	}
+	volumeID := *volume.VolumeId
+
+	if waiterr := sess.WaitTillVolumeAvailable(&aws.VolumeInput{VolumeIds: []string{volumeID}}); waiterr != nil {
+		sess.DeleteVolume(&aws.VolumeInput{VolumeIds: []string{volumeID}})
+		return VolumeResponse{}, waiterr
+	}
+	if v.Name != "" {
+		tags := Tag{Resource: volumeID, Name: "Name", Value: v.Name}
+		if _, tagerr := tags.CreateTags(con); tagerr != nil {
+			sess.DeleteVolume(&aws.VolumeInput{VolumeIds: []string{volumeID}})
+			return VolumeResponse{}, tagerr
+		}
+	}
+	return describeVolume(sess, volumeID, v.GetRaw)
+}
+
+// GetVolumes fetches the volumes passed, the ones attached to the instance passed or all the ones in the region.
+func (v *VolumeInput) GetVolumes(con aws.EstablishConnectionInput) ([]VolumeResponse, error) {
+
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return nil, seserr
+	}
+	volumes, geterr := v.describeVolumes(sess)
+	if geterr != nil {
+		return nil, geterr
+	}
+	response := make([]VolumeResponse, 0, len(volumes))
+	for _, volume := range volumes {
+		response = append(response, volumeResponse(volume, v.GetRaw))
+	}
+	return response, nil
+}
+
+// UpdateVolume performs the Action on the volume passed, exactly one has to be passed:
+// attach attaches it to the instance at the device passed, detach detaches it from the instance it is attached to
+// and resize changes its size, type or iops, the ones which are not passed are left as is, and waits till the modification reaches optimizing.
+func (v *VolumeInput) UpdateVolume(con aws.EstablishConnectionInput) (VolumeResponse, error) {
+
+	if len(v.VolumeIds) != 1 {
+		return VolumeResponse{}, cloudyerror.Newf(cloudyerror.InvalidInput, "You have to pass exactly one volume to be updated, got %d", len(v.VolumeIds))
+	}
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return VolumeResponse{}, seserr
+	}
+	volume, geterr := fetchVolume(sess, v.VolumeIds[0])
+	if geterr != nil {
+		return VolumeResponse{}, geterr
+	}
+	input := &aws.VolumeInput{VolumeIds: []string{*volume.VolumeId}}
+
+	switch v.Action {
+	case VolumeAttach:
+		if (v.InstanceId == "") || (v.DeviceName == "") {
+			return VolumeResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not provided either the instance or the device to which the volume has to be attached")
+		}
+		input.InstanceId, input.Device = v.InstanceId, v.DeviceName
+		if _, atterr := sess.AttachVolume(input); atterr != nil {
+			return VolumeResponse{}, atterr
+		}
+		if waiterr := sess.WaitTillVolumeInUse(input); waiterr != nil {
+			return VolumeResponse{}, waiterr
+		}
+	case VolumeDetach:
+		if len(volume.Attachments) == 0 {
+			return VolumeResponse{}, cloudyerror.Newf(cloudyerror.InvalidInput, "volume %s is not attached to any instance", *volume.VolumeId)
+		}
+		input.InstanceId, input.Device, input.Force = v.InstanceId, v.DeviceName, v.Force
+		if _, deterr := sess.DetachVolume(input); deterr != nil {
+			return VolumeResponse{}, deterr
+		}
+		if waiterr := sess.WaitTillVolumeAvailable(input); waiterr != nil {
+			return VolumeResponse{}, waiterr
+		}
+	case VolumeResize:
+		if reserr := v.validateResize(volume); reserr != nil {
+			return VolumeResponse{}, reserr
+		}
+		input.Size, input.Type, input.Iops = v.Size, v.Type, v.Iops
+		if _, moderr := sess.ModifyVolume(input); moderr != nil {
+			return VolumeResponse{}, moderr
+		}
+		if waiterr := sess.WaitTillVolumeModified(input); waiterr != nil {
+			return VolumeResponse{}, waiterr
+		}
+	default:
+		return VolumeResponse{}, cloudyerror.Newf(cloudyerror.InvalidInput, "action %s is not supported on the volumes, it should be one of attach, detach or resize", v.Action)
+	}
+	return describeVolume(sess, *volume.VolumeId, v.GetRaw)
+}
+
+// DeleteVolume deletes the volumes passed and waits till they are gone, the ones deleted before the failure are returned along with the error.
+// The volumes attached to the instances are not deleted, they have to be detached first.
+func (v *VolumeInput) DeleteVolume(con aws.EstablishConnectionInput) ([]VolumeResponse, error) {
+
+	if len(v.VolumeIds) == 0 {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the volumes to be deleted")
+	}
+	sess, seserr := con.EstablishConnection()
+	if seserr != nil {
+		return nil, seserr
+	}
+	volumes, geterr := sess.DescribeVolumes(&aws.VolumeInput{VolumeIds: v.VolumeIds})
+	if geterr != nil {
+		return nil, geterr
+	}
+	if attached := attachedIds(volumes.Volumes); len(attached) != 0 {
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "volumes %v are attached to the instances, detach them before deleting", attached)
+	}
+
+	response := make([]VolumeResponse, 0, len(volumes.Volumes))
+	for _, volume := range volumes.Volumes {
+		input := &aws.VolumeInput{VolumeIds: []string{*volume.VolumeId}}
+		if delerr := sess.DeleteVolume(input); delerr != nil {
+			return response, delerr
+		}
+		if _, waiterr := sess.WaitUntilVolumeDeleted(input); waiterr != nil {
+			return response, waiterr
+		}
+		response = append(response, VolumeResponse{Name: nameOf(volume.Tags), VolumeId: *volume.VolumeId, State: ec2.VolumeStateDeleted})
+	}
+	return response, nil
+}
+
+// spec returns the volume as the one of the instance, so that it is validated the same way.
+func (v *VolumeInput) spec() ServerVolume {
+	return ServerVolume{Size: v.Size, Type: v.Type, Iops: v.Iops, Encrypted: v.Encrypted, KmsKeyId: v.KmsKeyId}
+}
+
+// validateCreate validates the volume to be created, the size is taken from the snapshot if it is not passed.
+func (v *VolumeInput) validateCreate() error {
+	if v.Zone == "" {
+		return cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the zone in which the volume has to be created")
+	}
+	return v.spec().check(v.SnapshotId == "")
+}
+
+// validateResize validates the volume as it would be after the resize, aws allows the volume only to grow.
+func (v *VolumeInput) validateResize(volume *ec2.Volume) error {
+
+	if (v.Size == 0) && (v.Type == "") && (v.Iops == 0) {
+		return cloudyerror.New(cloudyerror.InvalidInput, "You have not passed either the size, type or iops to which the volume has to be resized")
+	}
+	resized := ServerVolume{Size: awssdk.Int64Value(volume.Size), Type: awssdk.StringValue(volume.VolumeType), Encrypted: awssdk.BoolValue(volume.Encrypted)}
+	if resized.Type == ec2.VolumeTypeIo1 {
+		resized.Iops = awssdk.Int64Value(volume.Iops)
+	}
+	if v.Size != 0 {
+		if v.Size < resized.Size {
+			return cloudyerror.Newf(cloudyerror.InvalidInput, "volume %s cannot shrink from %d to %d GiB", *volume.VolumeId, resized.Size, v.Size)
+		}
+		resized.Size = v.Size
+	}
+	if v.Type != "" {
+		resized.Type = v.Type
+		if v.Type != ec2.VolumeTypeIo1 {
+			resized.Iops = 0
+		}
+	}
+	if v.Iops != 0 {
+		resized.Iops = v.Iops
+	}
+	return resized.check(true)
+}
+
+func (v *VolumeInput) createInput() *aws.VolumeInput {
+	input := &aws.VolumeInput{AvailabilityZone: v.Zone, Size: v.Size, Type: v.Type, Iops: v.Iops, Encrypted: v.Encrypted, KmsKeyId: v.KmsKeyId}
+	if v.SnapshotId != "" {
+		input.SnapshotIds = []string{v.SnapshotId}
+	}
+	return input
+}
+
+// describeVolumes fetches the volumes selected by VolumeIds or InstanceId, all the volumes are fetched if neither is passed.
+func (v *VolumeInput) describeVolumes(sess aws.EstablishedSession) ([]*ec2.Volume, error) {
+	var volumes *ec2.DescribeVolumesOutput
+	var err error
+	switch {
+	case len(v.VolumeIds) != 0:
+		volumes, err = sess.DescribeVolumes(&aws.VolumeInput{VolumeIds: v.VolumeIds})
+	case v.InstanceId != "":
+		volumes, err = sess.DescribeVolumes(&aws.VolumeInput{Filters: aws.Filters{Name: "attachment.instance-id", Value: []string{v.InstanceId}}})
+	default:
+		volumes, err = sess.DescribeAllVolumes(&aws.VolumeInput{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return volumes.Volumes, nil
+}
+
+// fetchVolume fetches the volume passed.
+func fetchVolume(sess aws.EstablishedSession, id string) (*ec2.Volume, error) {
+	volumes, err := sess.DescribeVolumes(&aws.VolumeInput{VolumeIds: []string{id}})
+	if err != nil {
+		return nil, err
+	}
+	if len(volumes.Volumes) == 0 {
+		return nil, cloudyerror.Newf(cloudyerror.NotFound, "volume %s is not found", id)
+	}
+	return volumes.Volumes[0], nil
+}
+
+func describeVolume(sess aws.EstablishedSession, id string, raw bool) (VolumeResponse, error) {
+	volume, err := fetchVolume(sess, id)
+	if err != nil {
+		return VolumeResponse{}, err
+	}
+	return volumeResponse(volume, raw), nil
+}
+
+// attachedIds returns the IDs of the volumes which are attached to the instances.
+func attachedIds(volumes []*ec2.Volume) []string {
+	ids := make([]string, 0)
+	for _, volume := range volumes {
+		if len(volume.Attachments) != 0 {
+			ids = append(ids, *volume.VolumeId)
+		}
+	}
+	return ids
+}
+
+// volumeResponse returns the details of the volume, the unfiltered response of aws is returned if raw is set.
+func volumeResponse(volume *ec2.Volume, raw bool) VolumeResponse {
+	if raw {
+		return VolumeResponse{VolumeRaw: volume}
+	}
+	response := VolumeResponse{
+		Name:       nameOf(volume.Tags),
+		VolumeId:   awssdk.StringValue(volume.VolumeId),
+		Zone:       awssdk.StringValue(volume.AvailabilityZone),
+		Size:       awssdk.Int64Value(volume.Size),
+		Type:       awssdk.StringValue(volume.VolumeType),
+		Iops:       awssdk.Int64Value(volume.Iops),
+		Encrypted:  awssdk.BoolValue(volume.Encrypted),
+		KmsKeyId:   awssdk.StringValue(volume.KmsKeyId),
+		SnapshotId: awssdk.StringValue(volume.SnapshotId),
+		State:      awssdk.StringValue(volume.State),
+	}
+	if volume.CreateTime != nil {
+		response.CreateTime = volume.CreateTime.String()
+	}
+	if len(volume.Attachments) != 0 {
+		attachment := volume.Attachments[0]
+		response.InstanceId = awssdk.StringValue(attachment.InstanceId)
+		response.DeviceName = awssdk.StringValue(attachment.Device)
+		response.DeleteOnTermination = awssdk.BoolValue(attachment.DeleteOnTermination)
+	}
+	return response
+}
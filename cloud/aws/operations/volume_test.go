@@ -0,0 +1,260 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsfake "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/fake"
+	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// createTestServer creates a server in the first subnet of a new network and returns it along with the zone it is placed in.
+func createTestServer(t *testing.T, cloud *awsfake.Cloud) (ServerResponse, string) {
+	t.Helper()
+	network := createTestNetwork(t, cloud)
+	server := CreateServerInput{InstanceName: "neuron", ImageId: "ami-0123456789", InstanceType: "t2.micro", SubnetId: network.Subnets[0].Id}
+	servers, err := server.CreateServer(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating server: %v", err)
+	}
+	con := fakeConnection(cloud, "ec2")
+	sess, err := con.EstablishConnection()
+	if err != nil {
+		t.Fatalf("establishing connection: %v", err)
+	}
+	instances, err := sess.DescribeInstance(&aws.DescribeComputeInput{InstanceIds: []string{servers[0].InstanceId}})
+	if err != nil {
+		t.Fatalf("fetching server: %v", err)
+	}
+	return servers[0], *instances.Reservations[0].Instances[0].Placement.AvailabilityZone
+}
+
+func TestVolumeLifecycle(t *testing.T) {
+	cloud := awsfake.New()
+	server, zone := createTestServer(t, cloud)
+
+	create := VolumeInput{Name: "data", Zone: zone, Size: 100, Type: "io1", Iops: 1000}
+	volume, err := create.CreateVolume(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating volume: %v", err)
+	}
+	if volume.Name != "data" || volume.State != "available" || volume.Size != 100 || volume.Iops != 1000 || volume.Zone != zone {
+		t.Fatalf("volume is not created as requested, got %+v", volume)
+	}
+
+	attach := VolumeInput{VolumeIds: []string{volume.VolumeId}, Action: VolumeAttach, InstanceId: server.InstanceId, DeviceName: "/dev/sdf"}
+	attached, err := attach.UpdateVolume(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("attaching volume: %v", err)
+	}
+	if attached.State != "in-use" || attached.InstanceId != server.InstanceId || attached.DeviceName != "/dev/sdf" {
+		t.Errorf("expected the volume to be attached to %s at /dev/sdf, got %+v", server.InstanceId, attached)
+	}
+
+	get := VolumeInput{InstanceId: server.InstanceId}
+	volumes, err := get.GetVolumes(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("fetching volumes: %v", err)
+	}
+	// the root volume of the server is fetched along.
+	if len(volumes) != 2 {
+		t.Errorf("expected 2 volumes attached to the server, got %+v", volumes)
+	}
+
+	del := VolumeInput{VolumeIds: []string{volume.VolumeId}}
+	if _, err := del.DeleteVolume(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+		t.Errorf("expected the attached volume not to be deleted, got %v", err)
+	}
+
+	resize := VolumeInput{VolumeIds: []string{volume.VolumeId}, Action: VolumeResize, Size: 200, Type: "gp2"}
+	resized, err := resize.UpdateVolume(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("resizing volume: %v", err)
+	}
+	if resized.Size != 200 || resized.Type != "gp2" {
+		t.Errorf("expected the volume to be resized to 200 GiB gp2, got %+v", resized)
+	}
+	if calls := cloud.Calls("DescribeVolumesModifications"); calls == 0 {
+		t.Errorf("expected the resize to be waited on till the modification is done")
+	}
+
+	detach := VolumeInput{VolumeIds: []string{volume.VolumeId}, Action: VolumeDetach}
+	detached, err := detach.UpdateVolume(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("detaching volume: %v", err)
+	}
+	if detached.State != "available" || detached.InstanceId != "" {
+		t.Errorf("expected the volume to be detached, got %+v", detached)
+	}
+
+	deleted, err := del.DeleteVolume(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("deleting volume: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].VolumeId != volume.VolumeId || deleted[0].State != "deleted" {
+		t.Errorf("expected the volume %s to be deleted, got %+v", volume.VolumeId, deleted)
+	}
+}
+
+func TestCreateVolumeNotAvailable(t *testing.T) {
+	cloud := awsfake.New()
+	cloud.FailNext("WaitUntilVolumeAvailable", awserr.New("ResourceNotReady", "exceeded wait attempts", nil))
+	create := VolumeInput{Name: "data", Zone: testRegion + "a", Size: 100}
+	if _, err := create.CreateVolume(fakeConnection(cloud, "ec2")); err == nil {
+		t.Fatalf("expected the volume which did not become available to fail the creation")
+	}
+
+	// the volume created is not left behind.
+	get := VolumeInput{}
+	volumes, err := get.GetVolumes(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("fetching volumes: %v", err)
+	}
+	if len(volumes) != 0 {
+		t.Errorf("expected the volume to be deleted back, got %+v", volumes)
+	}
+}
+
+func TestUpdateVolumeInvalid(t *testing.T) {
+	cloud := awsfake.New()
+	_, zone := createTestServer(t, cloud)
+
+	create := VolumeInput{Zone: zone, Size: 100}
+	volume, err := create.CreateVolume(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating volume: %v", err)
+	}
+
+	invalid := []VolumeInput{
+		{Action: VolumeResize, Size: 50},
+		{Action: VolumeResize},
+		{Action: VolumeResize, Iops: 500},
+		{Action: VolumeAttach, DeviceName: "/dev/sdf"},
+		{Action: VolumeDetach},
+		{Action: "snapshot"},
+	}
+	for _, update := range invalid {
+		update.VolumeIds = []string{volume.VolumeId}
+		if _, err := update.UpdateVolume(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+			t.Errorf("expected %+v to be rejected as invalid input, got %v", update, err)
+		}
+	}
+	for _, volumes := range [][]string{nil, {volume.VolumeId, volume.VolumeId}} {
+		update := VolumeInput{VolumeIds: volumes, Action: VolumeResize, Size: 200}
+		if _, err := update.UpdateVolume(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+			t.Errorf("expected the update of %d volumes to be rejected as invalid input, got %v", len(volumes), err)
+		}
+		if _, err := update.PlanUpdateVolume(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+			t.Errorf("expected the plan to update %d volumes to be rejected as invalid input, got %v", len(volumes), err)
+		}
+	}
+	if calls := cloud.Calls("ModifyVolume") + cloud.Calls("AttachVolume") + cloud.Calls("DetachVolume"); calls != 0 {
+		t.Errorf("expected the invalid updates not to reach aws, got %d calls", calls)
+	}
+
+	for _, create := range []VolumeInput{{Size: 100}, {Zone: zone}, {Zone: zone, Size: 100, Type: "st1"}, {Zone: zone, Size: 10, Type: "io1"}} {
+		if _, err := create.CreateVolume(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+			t.Errorf("expected %+v to be rejected as invalid input, got %v", create, err)
+		}
+	}
+}
+
+func TestSnapshotLifecycle(t *testing.T) {
+	cloud := awsfake.New()
+	_, zone := createTestServer(t, cloud)
+
+	create := VolumeInput{Name: "data", Zone: zone, Size: 100, Encrypted: true}
+	volume, err := create.CreateVolume(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating volume: %v", err)
+	}
+
+	capture := SnapshotInput{VolumeId: volume.VolumeId, Name: "data-backup", Description: "nightly backup"}
+	snapshot, err := capture.CreateSnapshot(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating snapshot: %v", err)
+	}
+	if snapshot.Name != "data-backup" || snapshot.State != "completed" || snapshot.Size != 100 || !snapshot.Encrypted || snapshot.VolumeId != volume.VolumeId {
+		t.Fatalf("snapshot is not created as requested, got %+v", snapshot)
+	}
+
+	list := SnapshotInput{VolumeId: volume.VolumeId}
+	snapshots, err := list.GetSnapshots(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("fetching snapshots: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].SnapshotId != snapshot.SnapshotId {
+		t.Errorf("expected the snapshot %s of the volume, got %+v", snapshot.SnapshotId, snapshots)
+	}
+
+	restore := SnapshotInput{SnapshotIds: []string{snapshot.SnapshotId}, Volume: VolumeInput{Name: "data-restored", Zone: zone}}
+	restored, err := restore.RestoreSnapshot(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("restoring snapshot: %v", err)
+	}
+	if restored.Name != "data-restored" || restored.SnapshotId != snapshot.SnapshotId || restored.Size != 100 || !restored.Encrypted {
+		t.Errorf("expected the volume to be restored with the size and encryption of the snapshot, got %+v", restored)
+	}
+
+	del := SnapshotInput{SnapshotIds: []string{snapshot.SnapshotId}}
+	deleted, err := del.DeleteSnapshot(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("deleting snapshot: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].SnapshotId != snapshot.SnapshotId {
+		t.Errorf("expected the snapshot %s to be deleted, got %+v", snapshot.SnapshotId, deleted)
+	}
+	if snapshots, err := list.GetSnapshots(fakeConnection(cloud, "ec2")); err != nil || len(snapshots) != 0 {
+		t.Errorf("expected no snapshots left of the volume, got %+v, %v", snapshots, err)
+	}
+}
+
+func TestPlanVolume(t *testing.T) {
+	cloud := awsfake.New()
+	server, zone := createTestServer(t, cloud)
+
+	create := VolumeInput{Name: "data", Zone: zone, Size: 100}
+	plan, err := create.PlanCreateVolume(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("planning volume: %v", err)
+	}
+	if got := plan.Operations(); !reflect.DeepEqual(got, []string{"CreateVolume"}) || !plan.Actions[0].Verified || plan.Actions[0].Details["size"] != "100" {
+		t.Fatalf("unexpected plan %+v", plan)
+	}
+	get := VolumeInput{}
+	if volumes, err := get.GetVolumes(fakeConnection(cloud, "ec2")); err != nil || len(volumes) != 1 {
+		t.Fatalf("expected only the root volume of the server while planning, got %+v, %v", volumes, err)
+	}
+
+	volume, err := create.CreateVolume(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("creating volume: %v", err)
+	}
+	attach := VolumeInput{VolumeIds: []string{volume.VolumeId}, Action: VolumeAttach, InstanceId: server.InstanceId, DeviceName: "/dev/sdf"}
+	plan, err = attach.PlanUpdateVolume(fakeConnection(cloud, "ec2"))
+	if err != nil {
+		t.Fatalf("planning attachment: %v", err)
+	}
+	if got := plan.Operations(); !reflect.DeepEqual(got, []string{"AttachVolume"}) || plan.Actions[0].Details["server"] != server.InstanceId {
+		t.Errorf("unexpected plan %+v", plan)
+	}
+	if volumes, err := get.GetVolumes(fakeConnection(cloud, "ec2")); err != nil || volumes[1].State != "available" {
+		t.Errorf("expected the volume not to be attached while planning, got %+v, %v", volumes, err)
+	}
+
+	// the attached volume cannot be deleted, which is reported before aws is asked.
+	if _, err := attach.UpdateVolume(fakeConnection(cloud, "ec2")); err != nil {
+		t.Fatalf("attaching volume: %v", err)
+	}
+	del := VolumeInput{VolumeIds: []string{volume.VolumeId}}
+	if _, err := del.PlanDeleteVolume(fakeConnection(cloud, "ec2")); !cloudyerror.IsInvalidInput(err) {
+		t.Errorf("expected the plan to fail as the volume is attached, got %v", err)
+	}
+	cloud.FailNext("DetachVolume", awserr.New("UnauthorizedOperation", "You are not authorized to perform this operation.", nil))
+	detach := VolumeInput{VolumeIds: []string{volume.VolumeId}, Action: VolumeDetach}
+	if _, err := detach.PlanUpdateVolume(fakeConnection(cloud, "ec2")); err == nil || err.(awserr.Error).Code() != "UnauthorizedOperation" {
+		t.Errorf("expected the plan to fail as the detachment is not permitted, got %v", err)
+	}
+}
@@ -1,11 +1,11 @@
 package aws
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
 	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
 )
 
 // VpcResponse is a struct that will be the response type of almost all the VPC related activities under cloud/operations.
@@ -20,6 +20,8 @@ type VpcResponse struct {
 	IgwId string `json:"IgwId,omitempty"`
 	// SecGroupIds is the list of security groups IDs which are part of network created/updated/retrieved.
 	SecGroupIds []string `json:"SecGroupId,omitempty"`
+	// SecurityGroups holds the details of the security groups which are part of network created/updated/retrieved.
+	SecurityGroups []SecurityGroupResponse `json:"SecurityGroups,omitempty"`
 	// IsDefault is set true if the network was pre-created by AWS.
 	IsDefault bool `json:"IsDefault,omitempty"`
 	// State of the network ex: pending,deleted and etc.
@@ -48,6 +50,11 @@ func (vpc *NetworkCreateInput) CreateVpc(con aws.EstablishConnectionInput) (VpcR
 	if vpcErr != nil {
 		return VpcResponse{}, vpcErr
 	}
+	vpcid := *vpcResult.Vpc.VpcId
+	vpc.rollback.record("network", vpcid, func(con aws.EstablishConnectionInput) error {
+		deletevpc := DeleteNetworkInput{VpcIds: []string{vpcid}}
+		return deletevpc.DeleteVpc(con)
+	})
 
 	// I will program wait until vpc become available
 	waitErr := ec2.WaitTillVpcAvailable(
@@ -76,6 +83,7 @@ func (vpc *NetworkCreateInput) CreateVpc(con aws.EstablishConnectionInput) (VpcR
 	netcomp.Name = vpc.Name
 	netcomp.VpcIds = []string{*vpcResult.Vpc.VpcId}
 	netcomp.GetRaw = vpc.GetRaw
+	netcomp.rollback = vpc.rollback
 	vpcresponse := new(VpcResponse)
 
 	if (strings.ToLower(vpc.Type) == "public") || (strings.ToLower(vpc.Type) == "") {
@@ -91,7 +99,7 @@ func (vpc *NetworkCreateInput) CreateVpc(con aws.EstablishConnectionInput) (VpcR
 	} else if strings.ToLower(vpc.Type) == "private" {
 		vpcresponse.IgwId = ""
 	} else {
-		return VpcResponse{}, fmt.Errorf("You provided unknown network type. There are two possibility, either we do not support this type else you would have misspelled")
+		return VpcResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You provided unknown network type. There are two possibility, either we do not support this type else you would have misspelled")
 	}
 
 	// I will initialize data required to create security group and pass it to respective person to create one
@@ -108,6 +116,7 @@ func (vpc *NetworkCreateInput) CreateVpc(con aws.EstablishConnectionInput) (VpcR
 	}
 
 	vpcresponse.SecGroupIds = sec.SecGroupIds
+	vpcresponse.SecurityGroups = sec.SecurityGroups
 	vpcresponse.Name = vpctag
 	vpcresponse.VpcId = *vpcResult.Vpc.VpcId
 	vpcresponse.Type = vpc.Type
@@ -178,5 +187,5 @@ func (v *GetNetworksInput) FindVpcs(con aws.EstablishConnectionInput) (bool, err
 	if len(response.Vpcs) != 0 {
 		return true, nil
 	}
-	return false, fmt.Errorf("Could not find the VPC's you asked for")
+	return false, &cloudyerror.Error{Code: cloudyerror.NotFound, Cloud: "aws", Kind: "network", Message: "Could not find the VPC's you asked for"}
 }
@@ -0,0 +1,57 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	awsfake "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/fake"
+	aws "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+	"github.com/nikhilsbhat/neuron-cloudy/wait"
+)
+
+func TestWaitUntilSubnetDeletedTimesOut(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	polls := 0
+	con := fakeConnection(cloud, "ec2")
+	con.Wait = &wait.Poller{Interval: time.Millisecond, Backoff: 1, Timeout: 50 * time.Millisecond, Progress: func(wait.Progress) { polls++ }}
+	sess, err := con.EstablishConnection()
+	if err != nil {
+		t.Fatalf("establishing connection: %v", err)
+	}
+
+	deleted, err := sess.WaitUntilSubnetDeleted(&aws.DescribeNetworkInput{SubnetIds: []string{network.Subnets[0].Id}})
+	if deleted || !cloudyerror.IsTimeout(err) {
+		t.Fatalf("expected the waiting on the subnet which is not deleted to time out, got %v", err)
+	}
+	if polls < 2 {
+		t.Errorf("expected the subnet to be polled till the timeout, got %d polls", polls)
+	}
+}
+
+func TestWaitUntilSubnetDeleted(t *testing.T) {
+	cloud := awsfake.New()
+	network := createTestNetwork(t, cloud)
+
+	con := fakeConnection(cloud, "ec2")
+	con.Wait = &wait.Poller{Interval: time.Millisecond, Timeout: time.Second}
+	sess, err := con.EstablishConnection()
+	if err != nil {
+		t.Fatalf("establishing connection: %v", err)
+	}
+	subnet := &aws.DescribeNetworkInput{SubnetIds: []string{network.Subnets[0].Id}}
+	if err := sess.DeleteSubnet(subnet); err != nil {
+		t.Fatalf("deleting subnet: %v", err)
+	}
+
+	before := cloud.Calls("DescribeSubnets")
+	deleted, err := sess.WaitUntilSubnetDeleted(subnet)
+	if !deleted || err != nil {
+		t.Errorf("expected the deleted subnet to be reported deleted, got %v", err)
+	}
+	if calls := cloud.Calls("DescribeSubnets") - before; calls != 1 {
+		t.Errorf("expected a single poll of the deleted subnet, got %d", calls)
+	}
+}
@@ -1,14 +1,19 @@
 package azurecompute
 
 import (
+	"context"
 	"fmt"
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2018-04-01/compute"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
+	"github.com/nikhilsbhat/neuron-cloudy/wait"
 )
 
-func getDisksClient() compute.DisksClient {
+func getDisksClient(policy *retry.Policy, poller *wait.Poller) compute.DisksClient {
 	disksClient := compute.NewDisksClient(subscription)
 	disksClient.Authorizer = autorest.NewBearerAuthorizer(token)
+	withRetry(&disksClient.Client, policy)
+	withPolling(&disksClient.Client, poller)
 	return disksClient
 }
 
@@ -16,11 +21,18 @@ type DisksIn struct {
 	ResourceGroup string
 	DiskName      string `json:"snapshotname,omitempty"`
 	Location      string `json:"location,omitempty"`
+	// Context controls the cancellation and deadline of the calls made to azure, defaults to context.Background().
+	Context context.Context `json:"-"`
+	// Retry is the policy with which the failed calls to azure are retried, retry.Default() is used if not passed.
+	Retry *retry.Policy `json:"-"`
+	// Wait is the poller with which the long running operations of azure are waited till they are done, wait.Default() is used if not passed.
+	Wait *wait.Poller `json:"-"`
 }
 
 func (d DisksIn) DeleteDisk() (ar autorest.Response, err error) {
+	ctx := getContext(d.Context)
 
-	disksClient := getDisksClient()
+	disksClient := getDisksClient(d.Retry, d.Wait)
 	future, err := disksClient.Delete(
 		ctx,
 		d.ResourceGroup,
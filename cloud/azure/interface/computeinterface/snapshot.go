@@ -1,15 +1,20 @@
 package azurecompute
 
 import (
+	"context"
 	"fmt"
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2018-04-01/compute"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
+	"github.com/nikhilsbhat/neuron-cloudy/wait"
 )
 
-func getSnapshotsClient() compute.SnapshotsClient {
+func getSnapshotsClient(policy *retry.Policy, poller *wait.Poller) compute.SnapshotsClient {
 	snapshotsClient := compute.NewSnapshotsClient(subscription)
 	snapshotsClient.Authorizer = autorest.NewBearerAuthorizer(token)
+	withRetry(&snapshotsClient.Client, policy)
+	withPolling(&snapshotsClient.Client, poller)
 	return snapshotsClient
 }
 
@@ -18,11 +23,18 @@ type SnapshotIn struct {
 	SnapshotName  string `json:"snapshotname,omitempty"`
 	SourceImageID string `json:"sourceimageid,omitempty"`
 	Location      string `json:"location,omitempty"`
+	// Context controls the cancellation and deadline of the calls made to azure, defaults to context.Background().
+	Context context.Context `json:"-"`
+	// Retry is the policy with which the failed calls to azure are retried, retry.Default() is used if not passed.
+	Retry *retry.Policy `json:"-"`
+	// Wait is the poller with which the long running operations of azure are waited till they are done, wait.Default() is used if not passed.
+	Wait *wait.Poller `json:"-"`
 }
 
 func (s SnapshotIn) CreateSnapshot() (ss compute.Snapshot, err error) {
+	ctx := getContext(s.Context)
 
-	snapshotsClient := getSnapshotsClient()
+	snapshotsClient := getSnapshotsClient(s.Retry, s.Wait)
 	future, err := snapshotsClient.CreateOrUpdate(
 		ctx,
 		s.ResourceGroup,
@@ -52,8 +64,9 @@ func (s SnapshotIn) CreateSnapshot() (ss compute.Snapshot, err error) {
 }
 
 func (s SnapshotIn) DeleteSnapshot() (ar autorest.Response, err error) {
+	ctx := getContext(s.Context)
 
-	snapshotsClient := getSnapshotsClient()
+	snapshotsClient := getSnapshotsClient(s.Retry, s.Wait)
 	future, err := snapshotsClient.Delete(
 		ctx,
 		s.ResourceGroup,
@@ -73,8 +86,9 @@ func (s SnapshotIn) DeleteSnapshot() (ar autorest.Response, err error) {
 }
 
 func (s SnapshotIn) GetSnapshot() (ss compute.Snapshot, err error) {
+	ctx := getContext(s.Context)
 
-	snapshotsClient := getSnapshotsClient()
+	snapshotsClient := getSnapshotsClient(s.Retry, s.Wait)
 	future, err := snapshotsClient.Get(
 		ctx,
 		s.ResourceGroup,
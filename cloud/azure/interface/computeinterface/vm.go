@@ -6,21 +6,23 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2018-04-01/compute"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
+	"github.com/nikhilsbhat/neuron-cloudy/sshkey"
+	"github.com/nikhilsbhat/neuron-cloudy/wait"
 	"io/ioutil"
-	"log"
 	"neuron/cloud/azure/access"
-	"os"
 )
 
 var (
 	token, _, subscription = auth.GetServicePrincipalToken()
-	fakepubkey             = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQCjCX8wh0lnk2KUvoCulBER4TQ+4+repQF5vvQeCVc5eWHNQKIPuSxy4fGcEbar15U4wjEJYDsXUGhW0JIh4peIKFf+dXUtZlMQEo7QvPGGORjVm8Zf+je/cVqGQJOvUP4s1/J8EQ+/n6gidtByBL+4lN/vDp/lgPSZzRgb08zVuW40z6jFrxfwalru10FHzzPmkCEtW54YkdJ2yEnLzk+xZDJXmG7JE4c2yRl+Y35HCzHfeRsUqcF1ErV2KYHcRWqwzD9oDZ5V2uTC4ERHkF102Ve7LOSyYK3cvJ8QSWMoOCOPA/UpdrkJRq9e2eVdpIqvnbu2vp6xazU080ZNu/BB"
-	ctx                    = context.Background()
 )
 
-func getVMClient() compute.VirtualMachinesClient {
+func getVMClient(policy *retry.Policy, poller *wait.Poller) compute.VirtualMachinesClient {
 	vmClient := compute.NewVirtualMachinesClient(subscription)
 	vmClient.Authorizer = autorest.NewBearerAuthorizer(token)
+	withRetry(&vmClient.Client, policy)
+	withPolling(&vmClient.Client, poller)
 	return vmClient
 }
 
@@ -32,28 +34,34 @@ type VMIn struct {
 	Password         string `json:"password,omitempty"`
 	Flavour          string `json:"os,omitempty"`
 	SSHPublicKeyPath string `json:"sshkeypath,omitempty"`
-	Location         string `json:"location,omitempty"`
+	// SSHPublicKey is the public key in the OpenSSH format to be authorized on the vm, it takes precedence over SSHPublicKeyPath.
+	// A key pair is generated for the vm if neither is passed.
+	SSHPublicKey string `json:"sshkey,omitempty"`
+	Location     string `json:"location,omitempty"`
+	// Context controls the cancellation and deadline of the calls made to azure, defaults to context.Background().
+	Context context.Context `json:"-"`
+	// Retry is the policy with which the failed calls to azure are retried, retry.Default() is used if not passed.
+	Retry *retry.Policy `json:"-"`
+	// Wait is the poller with which the long running operations of azure are waited till they are done, wait.Default() is used if not passed.
+	Wait *wait.Poller `json:"-"`
 }
 
 // CreateVM creates a new virtual machine with the specified name using the specified NIC.
-// Username, password, and sshPublicKeyPath determine logon credentials.
-func (v VMIn) CreateVM() (vm compute.VirtualMachine, err error) {
+// Username, password, and the ssh public key (SSHPublicKey or the one at SSHPublicKeyPath) determine logon credentials.
+// If neither of the ssh keys is passed a key pair is generated for the vm and returned, its private key is not kept anywhere else
+// hence it has to be saved by the caller. The key pair returned is nil when the key passed is used.
+func (v VMIn) CreateVM() (vm compute.VirtualMachine, key *sshkey.Pair, err error) {
+	ctx := getContext(v.Context)
 	// see the network samples for how to create and get a NIC resource
 
 	osconfig := Image(v.Flavour)
 
-	var sshKeyData string
-	if _, err = os.Stat(v.SSHPublicKeyPath); err == nil {
-		sshBytes, err := ioutil.ReadFile(v.SSHPublicKeyPath)
-		if err != nil {
-			log.Fatalf("failed to read SSH key data: %v", err)
-		}
-		sshKeyData = string(sshBytes)
-	} else {
-		sshKeyData = fakepubkey
+	sshKeyData, key, err := v.sshKey()
+	if err != nil {
+		return vm, nil, err
 	}
 
-	vmClient := getVMClient()
+	vmClient := getVMClient(v.Retry, v.Wait)
 	future, err := vmClient.CreateOrUpdate(
 		ctx,
 		v.ResourceGroup,
@@ -103,40 +111,65 @@ func (v VMIn) CreateVM() (vm compute.VirtualMachine, err error) {
 		},
 	)
 	if err != nil {
-		return vm, fmt.Errorf("cannot create vm: %v", err)
+		return vm, nil, classify(err, v.VmName, "cannot create vm")
 	}
 
 	err = future.WaitForCompletion(ctx, vmClient.Client)
 	if err != nil {
-		return vm, fmt.Errorf("cannot get the vm create or update future response: %v", err)
+		return vm, nil, classify(err, v.VmName, "cannot get the vm create or update future response")
 	}
 
-	return future.Result(vmClient)
+	vm, err = future.Result(vmClient)
+	return vm, key, err
+}
+
+// sshKey returns the public key to be authorized on the vm, along with the key pair if it had to be generated.
+func (v VMIn) sshKey() (string, *sshkey.Pair, error) {
+	if v.SSHPublicKey != "" {
+		if _, err := sshkey.Parse(v.SSHPublicKey); err != nil {
+			return "", nil, err
+		}
+		return v.SSHPublicKey, nil, nil
+	}
+	if v.SSHPublicKeyPath != "" {
+		sshBytes, err := ioutil.ReadFile(v.SSHPublicKeyPath)
+		if err != nil {
+			return "", nil, cloudyerror.Newf(cloudyerror.InvalidInput, "failed to read SSH key data: %v", err)
+		}
+		return string(sshBytes), nil, nil
+	}
+	key, err := sshkey.Generate(sshkey.DefaultBits, v.VmName)
+	if err != nil {
+		return "", nil, err
+	}
+	return key.PublicKey, key, nil
 }
 
 func (v VMIn) DeleteVM() (ar autorest.Response, err error) {
+	ctx := getContext(v.Context)
 
-	vmClient := getVMClient()
+	vmClient := getVMClient(v.Retry, v.Wait)
 	future, err := vmClient.Delete(
 		ctx,
 		v.ResourceGroup,
 		v.VmName,
 	)
 	if err != nil {
-		return ar, fmt.Errorf("cannot delete VM: %v", err)
+		return ar, classify(err, v.VmName, "cannot delete VM")
 	}
 
 	err = future.WaitForCompletion(ctx, vmClient.Client)
 	if err != nil {
-		return ar, fmt.Errorf("cannot get the VM delete future response: %v", err)
+		return ar, classify(err, v.VmName, "cannot get the VM delete future response")
 	}
 
 	return future.Result(vmClient)
 }
 
 func (v VMIn) GetVM() (vm compute.VirtualMachine, err error) {
+	ctx := getContext(v.Context)
 
-	vmClient := getVMClient()
+	vmClient := getVMClient(v.Retry, v.Wait)
 	future, err := vmClient.Get(
 		ctx,
 		v.ResourceGroup,
@@ -144,37 +177,83 @@ func (v VMIn) GetVM() (vm compute.VirtualMachine, err error) {
 		"")
 
 	if err != nil {
-		return vm, fmt.Errorf("cannot get virtual VM: %v", err)
+		return vm, classify(err, v.VmName, "cannot get virtual VM")
 	}
 
 	return future, err
 }
 
 func (v VMIn) ListVM() (vm []compute.VirtualMachine, err error) {
+	ctx := getContext(v.Context)
 
-	vmClient := getVMClient()
+	vmClient := getVMClient(v.Retry, v.Wait)
 	future, err := vmClient.List(
 		ctx,
 		v.ResourceGroup,
 	)
 
 	if err != nil {
-		return vm, fmt.Errorf("cannot list the VMs in a resourcegroup: %v", err)
+		return vm, classify(err, "", "cannot list the VMs in a resourcegroup")
 	}
 
 	return future.Values(), err
 }
 
+// ListAllVM is same as ListAllVMWithContext, but uses context.Background().
 func ListAllVM() (vm []compute.VirtualMachine, err error) {
+	return ListAllVMWithContext(context.Background())
+}
+
+// ListAllVMWithContext lists the resources across the subscription, calls made to azure are bound to the context passed and retried with retry.Default().
+func ListAllVMWithContext(ctx context.Context) (vm []compute.VirtualMachine, err error) {
 
-	vmClient := getVMClient()
+	vmClient := getVMClient(nil, nil)
 	future, err := vmClient.ListAll(
 		ctx,
 	)
 
 	if err != nil {
-		return vm, fmt.Errorf("cannot list the VMs: %v", err)
+		return vm, classify(err, "", "cannot list the VMs")
 	}
 
 	return future.Values(), err
 }
+
+// getContext returns the context passed to the call, falls back to context.Background() if none was passed.
+func getContext(ctx context.Context) context.Context {
+	if ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// withRetry applies the retry policy passed to the client of azure, retry.Default() if none is passed,
+// autorest retries the requests failing with the status codes it deems transient (429 and 5xx) with the attempts and delay of the policy.
+func withRetry(client *autorest.Client, policy *retry.Policy) {
+	applied := retry.OrDefault(policy)
+	client.RetryAttempts = applied.Retries()
+	client.RetryDuration = applied.BaseDelay
+}
+
+// classify wraps the error returned by azure into cloudyerror.Error, so that the callers can look for its code.
+func classify(err error, id, message string) error {
+	status := 0
+	if detailed, ok := err.(autorest.DetailedError); ok {
+		if code, ok := detailed.StatusCode.(int); ok {
+			status = code
+		}
+	}
+	cloudyerr := cloudyerror.FromStatus("azure", "vm", id, status, err)
+	if typed, ok := cloudyerr.(*cloudyerror.Error); ok && typed.Err == err {
+		typed.Message = fmt.Sprintf("%s: %v", message, err)
+	}
+	return cloudyerr
+}
+
+// withPolling makes the client of azure poll the long running operations as per the poller passed, wait.Default() if none is passed,
+// autorest polls at a constant delay (or as asked by azure) hence the backoff of the poller is not applied.
+func withPolling(client *autorest.Client, poller *wait.Poller) {
+	applied := wait.OrDefault(poller)
+	client.PollingDelay = applied.Interval
+	client.PollingDuration = applied.Timeout
+}
@@ -1,15 +1,20 @@
 package azurenetwork
 
 import (
+	"context"
 	"fmt"
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2017-09-01/network"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
+	"github.com/nikhilsbhat/neuron-cloudy/wait"
 )
 
-func getIPClient() network.PublicIPAddressesClient {
+func getIPClient(policy *retry.Policy, poller *wait.Poller) network.PublicIPAddressesClient {
 	ipClient := network.NewPublicIPAddressesClient(subscription)
 	ipClient.Authorizer = autorest.NewBearerAuthorizer(token)
+	withRetry(&ipClient.Client, policy)
+	withPolling(&ipClient.Client, poller)
 
 	return ipClient
 }
@@ -18,12 +23,21 @@ type IpIn struct {
 	ResourceGroup string
 	IpName        string `json:"ipname,omitempty"`
 	Location      string `json:"location,omitempty"`
+	// Tags are set on the public IP created, the static public IPs are named through them the way the other clouds do.
+	Tags map[string]*string `json:"tags,omitempty"`
+	// Context controls the cancellation and deadline of the calls made to azure, defaults to context.Background().
+	Context context.Context `json:"-"`
+	// Retry is the policy with which the failed calls to azure are retried, retry.Default() is used if not passed.
+	Retry *retry.Policy `json:"-"`
+	// Wait is the poller with which the long running operations of azure are waited till they are done, wait.Default() is used if not passed.
+	Wait *wait.Poller `json:"-"`
 }
 
 // CreatePublicIP creates a new public IP
 
 func (pubip IpIn) CreatePublicIP() (ip network.PublicIPAddress, err error) {
-	ipClient := getIPClient()
+	ctx := getContext(pubip.Context)
+	ipClient := getIPClient(pubip.Retry, pubip.Wait)
 	future, err := ipClient.CreateOrUpdate(
 		ctx,
 		pubip.ResourceGroup,
@@ -31,6 +45,7 @@ func (pubip IpIn) CreatePublicIP() (ip network.PublicIPAddress, err error) {
 		network.PublicIPAddress{
 			Name:     to.StringPtr(pubip.IpName),
 			Location: to.StringPtr(pubip.Location),
+			Tags:     pubip.Tags,
 			PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
 				PublicIPAddressVersion:   network.IPv4,
 				PublicIPAllocationMethod: network.Static,
@@ -54,7 +69,8 @@ func (pubip IpIn) CreatePublicIP() (ip network.PublicIPAddress, err error) {
 }
 
 func (pubip IpIn) DeletePublicIP() (ar autorest.Response, err error) {
-	ipClient := getIPClient()
+	ctx := getContext(pubip.Context)
+	ipClient := getIPClient(pubip.Retry, pubip.Wait)
 	future, err := ipClient.Delete(
 		ctx,
 		pubip.ResourceGroup,
@@ -73,7 +89,8 @@ func (pubip IpIn) DeletePublicIP() (ar autorest.Response, err error) {
 }
 
 func (pubip IpIn) GetPublicIP() (ip network.PublicIPAddress, err error) {
-	ipClient := getIPClient()
+	ctx := getContext(pubip.Context)
+	ipClient := getIPClient(pubip.Retry, pubip.Wait)
 	future, err := ipClient.Get(
 		ctx,
 		pubip.ResourceGroup,
@@ -88,7 +105,8 @@ func (pubip IpIn) GetPublicIP() (ip network.PublicIPAddress, err error) {
 }
 
 func (pubip IpIn) ListPublicIP() (ip []network.PublicIPAddress, err error) {
-	ipClient := getIPClient()
+	ctx := getContext(pubip.Context)
+	ipClient := getIPClient(pubip.Retry, pubip.Wait)
 	future, err := ipClient.List(
 		ctx,
 		pubip.ResourceGroup,
@@ -102,7 +120,8 @@ func (pubip IpIn) ListPublicIP() (ip []network.PublicIPAddress, err error) {
 }
 
 func (pubip IpIn) ListAllPublicIP() (ip []network.PublicIPAddress, err error) {
-	ipClient := getIPClient()
+	ctx := getContext(pubip.Context)
+	ipClient := getIPClient(pubip.Retry, pubip.Wait)
 	future, err := ipClient.ListAll(
 		ctx,
 	)
@@ -1,15 +1,20 @@
 package azurenetwork
 
 import (
+	"context"
 	"fmt"
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2017-09-01/network"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
+	"github.com/nikhilsbhat/neuron-cloudy/wait"
 )
 
-func getNicClient() network.InterfacesClient {
+func getNicClient(policy *retry.Policy, poller *wait.Poller) network.InterfacesClient {
 	nicClient := network.NewInterfacesClient(subscription)
 	nicClient.Authorizer = autorest.NewBearerAuthorizer(token)
+	withRetry(&nicClient.Client, policy)
+	withPolling(&nicClient.Client, poller)
 	return nicClient
 }
 
@@ -20,11 +25,18 @@ type NicIn struct {
 	SubnetID      string `json:"subnetid,omitempty"`
 	IpID          string `json:"ipid,omitempty"`
 	Location      string `json:"location,omitempty"`
+	// Context controls the cancellation and deadline of the calls made to azure, defaults to context.Background().
+	Context context.Context `json:"-"`
+	// Retry is the policy with which the failed calls to azure are retried, retry.Default() is used if not passed.
+	Retry *retry.Policy `json:"-"`
+	// Wait is the poller with which the long running operations of azure are waited till they are done, wait.Default() is used if not passed.
+	Wait *wait.Poller `json:"-"`
 }
 
 // CreateNIC creates a new network interface.
 
 func (n NicIn) CreateNIC() (nic network.Interface, err error) {
+	ctx := getContext(n.Context)
 
 	nicParams := network.Interface{
 		Name:     to.StringPtr(n.NicName),
@@ -53,7 +65,7 @@ func (n NicIn) CreateNIC() (nic network.Interface, err error) {
 		}
 	}
 
-	nicClient := getNicClient()
+	nicClient := getNicClient(n.Retry, n.Wait)
 	future, err := nicClient.CreateOrUpdate(
 		ctx,
 		n.ResourceGroup,
@@ -73,8 +85,50 @@ func (n NicIn) CreateNIC() (nic network.Interface, err error) {
 	return future.Result(nicClient)
 }
 
+// AssociatePublicIP associates the public IP IpID with the first ip configuration of the network interface,
+// replacing the one it had. The static public IP stays with the interface across the restarts of the vm.
+func (n NicIn) AssociatePublicIP() (nic network.Interface, err error) {
+	return n.setPublicIP(&network.PublicIPAddress{ID: to.StringPtr(n.IpID)})
+}
+
+// DisassociatePublicIP removes the public IP from the first ip configuration of the network interface, the public IP itself is retained.
+func (n NicIn) DisassociatePublicIP() (nic network.Interface, err error) {
+	return n.setPublicIP(nil)
+}
+
+func (n NicIn) setPublicIP(ip *network.PublicIPAddress) (nic network.Interface, err error) {
+	ctx := getContext(n.Context)
+	existing, err := n.GetNIC()
+	if err != nil {
+		return nic, err
+	}
+	if existing.InterfacePropertiesFormat == nil || existing.IPConfigurations == nil || len(*existing.IPConfigurations) == 0 {
+		return nic, fmt.Errorf("nic %s has no ip configuration to which the public ip can be associated", n.NicName)
+	}
+	(*existing.IPConfigurations)[0].PublicIPAddress = ip
+
+	nicClient := getNicClient(n.Retry, n.Wait)
+	future, err := nicClient.CreateOrUpdate(
+		ctx,
+		n.ResourceGroup,
+		n.NicName,
+		existing,
+	)
+	if err != nil {
+		return nic, fmt.Errorf("cannot update public ip of nic: %v", err)
+	}
+
+	err = future.WaitForCompletion(ctx, nicClient.Client)
+	if err != nil {
+		return nic, fmt.Errorf("cannot get nic create or update future response: %v", err)
+	}
+
+	return future.Result(nicClient)
+}
+
 func (n NicIn) DeleteNIC() (ar autorest.Response, err error) {
-	nicClient := getNicClient()
+	ctx := getContext(n.Context)
+	nicClient := getNicClient(n.Retry, n.Wait)
 	future, err := nicClient.Delete(
 		ctx,
 		n.ResourceGroup,
@@ -93,7 +147,8 @@ func (n NicIn) DeleteNIC() (ar autorest.Response, err error) {
 }
 
 func (n NicIn) GetNIC() (nic network.Interface, err error) {
-	nicClient := getNicClient()
+	ctx := getContext(n.Context)
+	nicClient := getNicClient(n.Retry, n.Wait)
 	future, err := nicClient.Get(
 		ctx,
 		n.ResourceGroup,
@@ -107,7 +162,8 @@ func (n NicIn) GetNIC() (nic network.Interface, err error) {
 }
 
 func (n NicIn) ListNIC() (nic []network.Interface, err error) {
-	nicClient := getNicClient()
+	ctx := getContext(n.Context)
+	nicClient := getNicClient(n.Retry, n.Wait)
 	future, err := nicClient.List(
 		ctx,
 		n.ResourceGroup,
@@ -120,8 +176,14 @@ func (n NicIn) ListNIC() (nic []network.Interface, err error) {
 	return future.Values(), err
 }
 
+// ListAllNIC is same as ListAllNICWithContext, but uses context.Background().
 func ListAllNIC() (nic []network.Interface, err error) {
-	nicClient := getNicClient()
+	return ListAllNICWithContext(context.Background())
+}
+
+// ListAllNICWithContext lists the resources across the subscription, calls made to azure are bound to the context passed and retried with retry.Default().
+func ListAllNICWithContext(ctx context.Context) (nic []network.Interface, err error) {
+	nicClient := getNicClient(nil, nil)
 	future, err := nicClient.ListAll(
 		ctx,
 	)
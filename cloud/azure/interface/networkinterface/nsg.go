@@ -1,15 +1,20 @@
 package azurenetwork
 
 import (
+	"context"
 	"fmt"
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2017-09-01/network"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
+	"github.com/nikhilsbhat/neuron-cloudy/wait"
 )
 
-func getNsgClient() network.SecurityGroupsClient {
+func getNsgClient(policy *retry.Policy, poller *wait.Poller) network.SecurityGroupsClient {
 	nsgClient := network.NewSecurityGroupsClient(subscription)
 	nsgClient.Authorizer = autorest.NewBearerAuthorizer(token)
+	withRetry(&nsgClient.Client, policy)
+	withPolling(&nsgClient.Client, poller)
 
 	return nsgClient
 }
@@ -19,18 +24,25 @@ type NsgIn struct {
 	NsgName       string `json:"nsgname,omitempty"`
 	//        SubnetID  string      `json:"subnet,omitempty"`
 	Location string `json:"location,omitempty"`
+	// Context controls the cancellation and deadline of the calls made to azure, defaults to context.Background().
+	Context context.Context `json:"-"`
+	// Retry is the policy with which the failed calls to azure are retried, retry.Default() is used if not passed.
+	Retry *retry.Policy `json:"-"`
+	// Wait is the poller with which the long running operations of azure are waited till they are done, wait.Default() is used if not passed.
+	Wait *wait.Poller `json:"-"`
 }
 
 // CreateNetworkSecurityGroup creates a new network security group.
 
 func (ns NsgIn) CreateNetworkSecurityGroup() (nsg network.SecurityGroup, err error) {
+	ctx := getContext(ns.Context)
 
 	nsgParams := network.SecurityGroup{
 		Name:     to.StringPtr(ns.NsgName),
 		Location: to.StringPtr(ns.Location),
 	}
 
-	nsgClient := getNsgClient()
+	nsgClient := getNsgClient(ns.Retry, ns.Wait)
 	future, err := nsgClient.CreateOrUpdate(
 		ctx,
 		ns.ResourceGroup,
@@ -51,7 +63,8 @@ func (ns NsgIn) CreateNetworkSecurityGroup() (nsg network.SecurityGroup, err err
 }
 
 func (ns NsgIn) DeleteNetworkSecurityGroup() (ar autorest.Response, err error) {
-	nsgClient := getNsgClient()
+	ctx := getContext(ns.Context)
+	nsgClient := getNsgClient(ns.Retry, ns.Wait)
 	future, err := nsgClient.Delete(
 		ctx,
 		ns.ResourceGroup,
@@ -71,7 +84,8 @@ func (ns NsgIn) DeleteNetworkSecurityGroup() (ar autorest.Response, err error) {
 }
 
 func (ns NsgIn) GetNetworkSecurityGroup() (nsg network.SecurityGroup, err error) {
-	nsgClient := getNsgClient()
+	ctx := getContext(ns.Context)
+	nsgClient := getNsgClient(ns.Retry, ns.Wait)
 	future, err := nsgClient.Get(
 		ctx,
 		ns.ResourceGroup,
@@ -86,7 +100,8 @@ func (ns NsgIn) GetNetworkSecurityGroup() (nsg network.SecurityGroup, err error)
 }
 
 func (ns NsgIn) ListNetworkSecurityGroup() (nsg []network.SecurityGroup, err error) {
-	nsgClient := getNsgClient()
+	ctx := getContext(ns.Context)
+	nsgClient := getNsgClient(ns.Retry, ns.Wait)
 	future, err := nsgClient.List(
 		ctx,
 		ns.ResourceGroup,
@@ -99,8 +114,14 @@ func (ns NsgIn) ListNetworkSecurityGroup() (nsg []network.SecurityGroup, err err
 	return future.Values(), err
 }
 
+// ListAllNetworkSecurityGroup is same as ListAllNetworkSecurityGroupWithContext, but uses context.Background().
 func ListAllNetworkSecurityGroup() (nsg []network.SecurityGroup, err error) {
-	nsgClient := getNsgClient()
+	return ListAllNetworkSecurityGroupWithContext(context.Background())
+}
+
+// ListAllNetworkSecurityGroupWithContext lists the resources across the subscription, calls made to azure are bound to the context passed and retried with retry.Default().
+func ListAllNetworkSecurityGroupWithContext(ctx context.Context) (nsg []network.SecurityGroup, err error) {
+	nsgClient := getNsgClient(nil, nil)
 	future, err := nsgClient.ListAll(
 		ctx,
 	)
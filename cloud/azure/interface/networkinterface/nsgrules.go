@@ -1,48 +1,66 @@
 package azurenetwork
 
 import (
+	"context"
 	"fmt"
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2017-09-01/network"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
+	"github.com/nikhilsbhat/neuron-cloudy/wait"
 )
 
-func getNsgRuleClient() network.SecurityRulesClient {
+func getNsgRuleClient(policy *retry.Policy, poller *wait.Poller) network.SecurityRulesClient {
 	nsgRuleClient := network.NewSecurityRulesClient(subscription)
 	nsgRuleClient.Authorizer = autorest.NewBearerAuthorizer(token)
+	withRetry(&nsgRuleClient.Client, policy)
+	withPolling(&nsgRuleClient.Client, poller)
 
 	return nsgRuleClient
 }
 
+// SecurityRuleIn holds the values required to create/delete the rule of the network security group.
+// The values which are not set defaults to the rule allowing inbound tcp traffic on Port from anywhere.
+// The rule matches the traffic only by the address prefixes, the application security groups are not supported yet hence it cannot
+// reference other security groups. It is not wired to the security groups of cloudoperations, azure being a placeholder provider there.
 type SecurityRuleIn struct {
 	ResourceGroup string
 	NsgName       string `json:"nsgname,omitempty"`
 	RuleName      string `json:"rulename,omitempty"`
 	Port          string `json:"port,omitempty"`
 	Priority      int32  `json:"priority,omitempty"`
+	// Protocol of the traffic the rule applies to, one of Tcp, Udp or * (all). Defaults to Tcp.
+	Protocol string `json:"protocol,omitempty"`
+	// Direction of the traffic the rule applies to, Inbound or Outbound. Defaults to Inbound.
+	Direction string `json:"direction,omitempty"`
+	// Access is either Allow or Deny the traffic matching the rule. Defaults to Allow.
+	Access string `json:"access,omitempty"`
+	// SourcePrefixes are the CIDR blocks from which the traffic is matched, defaults to 0.0.0.0/0.
+	SourcePrefixes []string `json:"sourceprefixes,omitempty"`
+	// DestinationPrefixes are the CIDR blocks to which the traffic is matched, defaults to 0.0.0.0/0.
+	DestinationPrefixes []string `json:"destinationprefixes,omitempty"`
+	// Description of the rule.
+	Description string `json:"description,omitempty"`
+	// Context controls the cancellation and deadline of the calls made to azure, defaults to context.Background().
+	Context context.Context `json:"-"`
+	// Retry is the policy with which the failed calls to azure are retried, retry.Default() is used if not passed.
+	Retry *retry.Policy `json:"-"`
+	// Wait is the poller with which the long running operations of azure are waited till they are done, wait.Default() is used if not passed.
+	Wait *wait.Poller `json:"-"`
 }
 
-// CreateNetworkSecurityGroup creates a new network security group.
-
+// CreateNetworkSecurityRule creates or updates the rule of the network security group.
 func (rule SecurityRuleIn) CreateNetworkSecurityRule() (nsgrule network.SecurityRule, err error) {
-	nsgRuleClient := getNsgRuleClient()
+	ctx := getContext(rule.Context)
+	nsgRuleClient := getNsgRuleClient(rule.Retry, rule.Wait)
 	future, err := nsgRuleClient.CreateOrUpdate(
 		ctx,
 		rule.ResourceGroup,
 		rule.NsgName,
 		rule.RuleName,
 		network.SecurityRule{
-			Name: to.StringPtr(rule.RuleName),
-			SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
-				Protocol:                 network.SecurityRuleProtocolTCP,
-				SourceAddressPrefix:      to.StringPtr("0.0.0.0/0"),
-				SourcePortRange:          to.StringPtr("1-65535"),
-				DestinationAddressPrefix: to.StringPtr("0.0.0.0/0"),
-				DestinationPortRange:     to.StringPtr(rule.Port),
-				Access:                   network.SecurityRuleAccessAllow,
-				Direction:                network.SecurityRuleDirectionInbound,
-				Priority:                 to.Int32Ptr(rule.Priority),
-			},
+			Name:                         to.StringPtr(rule.RuleName),
+			SecurityRulePropertiesFormat: rule.properties(),
 		},
 	)
 
@@ -57,3 +75,58 @@ func (rule SecurityRuleIn) CreateNetworkSecurityRule() (nsgrule network.Security
 
 	return future.Result(nsgRuleClient)
 }
+
+// DeleteNetworkSecurityRule deletes the rule of the network security group.
+func (rule SecurityRuleIn) DeleteNetworkSecurityRule() error {
+	ctx := getContext(rule.Context)
+	nsgRuleClient := getNsgRuleClient(rule.Retry, rule.Wait)
+	future, err := nsgRuleClient.Delete(ctx, rule.ResourceGroup, rule.NsgName, rule.RuleName)
+	if err != nil {
+		return fmt.Errorf("cannot delete nsgRule: %v", err)
+	}
+
+	err = future.WaitForCompletion(ctx, nsgRuleClient.Client)
+	if err != nil {
+		return fmt.Errorf("cannot get nsgRule delete future response: %v", err)
+	}
+	return nil
+}
+
+// properties returns the properties of the rule, the ones which are not set are defaulted.
+// The address prefix and the prefixes are exclusive in azure, hence only one of them is set.
+func (rule SecurityRuleIn) properties() *network.SecurityRulePropertiesFormat {
+	properties := &network.SecurityRulePropertiesFormat{
+		Protocol:             network.SecurityRuleProtocolTCP,
+		SourcePortRange:      to.StringPtr("1-65535"),
+		DestinationPortRange: to.StringPtr(rule.Port),
+		Access:               network.SecurityRuleAccessAllow,
+		Direction:            network.SecurityRuleDirectionInbound,
+		Priority:             to.Int32Ptr(rule.Priority),
+	}
+	if rule.Protocol != "" {
+		properties.Protocol = network.SecurityRuleProtocol(rule.Protocol)
+	}
+	if rule.Protocol == string(network.SecurityRuleProtocolAsterisk) || rule.Port == "" {
+		properties.DestinationPortRange = to.StringPtr("*")
+	}
+	if rule.Access != "" {
+		properties.Access = network.SecurityRuleAccess(rule.Access)
+	}
+	if rule.Direction != "" {
+		properties.Direction = network.SecurityRuleDirection(rule.Direction)
+	}
+	if rule.Description != "" {
+		properties.Description = to.StringPtr(rule.Description)
+	}
+	if len(rule.SourcePrefixes) != 0 {
+		properties.SourceAddressPrefixes = &rule.SourcePrefixes
+	} else {
+		properties.SourceAddressPrefix = to.StringPtr("0.0.0.0/0")
+	}
+	if len(rule.DestinationPrefixes) != 0 {
+		properties.DestinationAddressPrefixes = &rule.DestinationPrefixes
+	} else {
+		properties.DestinationAddressPrefix = to.StringPtr("0.0.0.0/0")
+	}
+	return properties
+}
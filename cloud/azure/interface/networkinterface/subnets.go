@@ -1,15 +1,20 @@
 package azurenetwork
 
 import (
+	"context"
 	"fmt"
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2017-09-01/network"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
+	"github.com/nikhilsbhat/neuron-cloudy/wait"
 )
 
-func getSubnetsClient() network.SubnetsClient {
+func getSubnetsClient(policy *retry.Policy, poller *wait.Poller) network.SubnetsClient {
 	subnetsClient := network.NewSubnetsClient(subscription)
 	subnetsClient.Authorizer = autorest.NewBearerAuthorizer(token)
+	withRetry(&subnetsClient.Client, policy)
+	withPolling(&subnetsClient.Client, poller)
 	return subnetsClient
 }
 
@@ -19,11 +24,18 @@ type SubnetIn struct {
 	SubnetName    string `json:"subnetname,omitempty"`
 	SubnetCidr    string `json:"cidr,omitempty"`
 	NsgID         string `json:nsg,omitempty`
+	// Context controls the cancellation and deadline of the calls made to azure, defaults to context.Background().
+	Context context.Context `json:"-"`
+	// Retry is the policy with which the failed calls to azure are retried, retry.Default() is used if not passed.
+	Retry *retry.Policy `json:"-"`
+	// Wait is the poller with which the long running operations of azure are waited till they are done, wait.Default() is used if not passed.
+	Wait *wait.Poller `json:"-"`
 }
 
 // CreateVirtualNetworkSubnet creates a subnet in an existing vnet
 
 func (sub SubnetIn) CreateVirtualNetworkSubnet() (subnet network.Subnet, err error) {
+	ctx := getContext(sub.Context)
 
 	subnetParams := network.Subnet{
 		Name: to.StringPtr(sub.SubnetName),
@@ -38,7 +50,7 @@ func (sub SubnetIn) CreateVirtualNetworkSubnet() (subnet network.Subnet, err err
 			ID: to.StringPtr(sub.NsgID),
 		}
 	}
-	subnetsClient := getSubnetsClient()
+	subnetsClient := getSubnetsClient(sub.Retry, sub.Wait)
 
 	future, err := subnetsClient.CreateOrUpdate(
 		ctx,
@@ -60,7 +72,8 @@ func (sub SubnetIn) CreateVirtualNetworkSubnet() (subnet network.Subnet, err err
 }
 
 func (sub SubnetIn) DeleteVirtualNetworkSubnet() (ar autorest.Response, err error) {
-	subnetsClient := getSubnetsClient()
+	ctx := getContext(sub.Context)
+	subnetsClient := getSubnetsClient(sub.Retry, sub.Wait)
 
 	future, err := subnetsClient.Delete(
 		ctx,
@@ -82,7 +95,8 @@ func (sub SubnetIn) DeleteVirtualNetworkSubnet() (ar autorest.Response, err erro
 }
 
 func (sub SubnetIn) GetVirtualNetworkSubnet() (subnet network.Subnet, err error) {
-	subnetsClient := getSubnetsClient()
+	ctx := getContext(sub.Context)
+	subnetsClient := getSubnetsClient(sub.Retry, sub.Wait)
 
 	future, err := subnetsClient.Get(
 		ctx,
@@ -98,7 +112,8 @@ func (sub SubnetIn) GetVirtualNetworkSubnet() (subnet network.Subnet, err error)
 }
 
 func (sub SubnetIn) ListVirtualNetworkSubnet() (subnet []network.Subnet, err error) {
-	subnetsClient := getSubnetsClient()
+	ctx := getContext(sub.Context)
+	subnetsClient := getSubnetsClient(sub.Retry, sub.Wait)
 
 	future, err := subnetsClient.List(
 		ctx,
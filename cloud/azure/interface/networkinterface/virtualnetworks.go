@@ -6,12 +6,13 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2017-09-01/network"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
+	"github.com/nikhilsbhat/neuron-cloudy/wait"
 	"neuron/cloud/azure/access"
 )
 
 var (
 	token, _, subscription = auth.GetServicePrincipalToken()
-	ctx                    = context.Background()
 )
 
 type VnetIn struct {
@@ -19,11 +20,19 @@ type VnetIn struct {
 	VnetName      string `json:"vnetname,omitempty"`
 	Cidr          string `json:"cidr,omitempty"`
 	Location      string `json:"location,omitempty"`
+	// Context controls the cancellation and deadline of the calls made to azure, defaults to context.Background().
+	Context context.Context `json:"-"`
+	// Retry is the policy with which the failed calls to azure are retried, retry.Default() is used if not passed.
+	Retry *retry.Policy `json:"-"`
+	// Wait is the poller with which the long running operations of azure are waited till they are done, wait.Default() is used if not passed.
+	Wait *wait.Poller `json:"-"`
 }
 
-func getVnetClient() network.VirtualNetworksClient {
+func getVnetClient(policy *retry.Policy, poller *wait.Poller) network.VirtualNetworksClient {
 	vnetClient := network.NewVirtualNetworksClient(subscription)
 	vnetClient.Authorizer = autorest.NewBearerAuthorizer(token)
+	withRetry(&vnetClient.Client, policy)
+	withPolling(&vnetClient.Client, poller)
 
 	return vnetClient
 }
@@ -31,7 +40,8 @@ func getVnetClient() network.VirtualNetworksClient {
 // CreateVirtualNetwork creates a virtual network
 // func CreateVirtualNetwork(resourceGroup string, vnetName string, cidr string, location string) (vnet network.VirtualNetwork, err error) {
 func (net VnetIn) CreateVirtualNetwork() (vnet network.VirtualNetwork, err error) {
-	vnetClient := getVnetClient()
+	ctx := getContext(net.Context)
+	vnetClient := getVnetClient(net.Retry, net.Wait)
 	future, err := vnetClient.CreateOrUpdate(
 		ctx,
 		net.ResourceGroup,
@@ -59,7 +69,8 @@ func (net VnetIn) CreateVirtualNetwork() (vnet network.VirtualNetwork, err error
 
 // CreateVirtualNetwork gets a virtual network
 func (net VnetIn) GetVirtualNetwork() (vnet network.VirtualNetwork, err error) {
-	vnetClient := getVnetClient()
+	ctx := getContext(net.Context)
+	vnetClient := getVnetClient(net.Retry, net.Wait)
 	future, err := vnetClient.Get(
 		ctx,
 		net.ResourceGroup,
@@ -75,7 +86,8 @@ func (net VnetIn) GetVirtualNetwork() (vnet network.VirtualNetwork, err error) {
 
 // CreateVirtualNetwork deletes a virtual network
 func (net VnetIn) DeleteVirtualNetwork() (ar autorest.Response, err error) {
-	vnetClient := getVnetClient()
+	ctx := getContext(net.Context)
+	vnetClient := getVnetClient(net.Retry, net.Wait)
 	future, err := vnetClient.Delete(
 		ctx,
 		net.ResourceGroup,
@@ -96,7 +108,8 @@ func (net VnetIn) DeleteVirtualNetwork() (ar autorest.Response, err error) {
 
 // CreateVirtualNetwork lists a virtual network
 func (net VnetIn) ListVirtualNetwork() (vnet []network.VirtualNetwork, err error) {
-	vnetClient := getVnetClient()
+	ctx := getContext(net.Context)
+	vnetClient := getVnetClient(net.Retry, net.Wait)
 	future, err := vnetClient.List(
 		ctx,
 		net.ResourceGroup)
@@ -109,8 +122,14 @@ func (net VnetIn) ListVirtualNetwork() (vnet []network.VirtualNetwork, err error
 }
 
 // // CreateVirtualNetwork lists all virtual networks
+// ListAllVirtualNetwork is same as ListAllVirtualNetworkWithContext, but uses context.Background().
 func ListAllVirtualNetwork() (vnet []network.VirtualNetwork, err error) {
-	vnetClient := getVnetClient()
+	return ListAllVirtualNetworkWithContext(context.Background())
+}
+
+// ListAllVirtualNetworkWithContext lists the resources across the subscription, calls made to azure are bound to the context passed and retried with retry.Default().
+func ListAllVirtualNetworkWithContext(ctx context.Context) (vnet []network.VirtualNetwork, err error) {
+	vnetClient := getVnetClient(nil, nil)
 	future, err := vnetClient.ListAll(
 		ctx)
 
@@ -120,3 +139,27 @@ func ListAllVirtualNetwork() (vnet []network.VirtualNetwork, err error) {
 
 	return future.Values(), err
 }
+
+// getContext returns the context passed to the call, falls back to context.Background() if none was passed.
+func getContext(ctx context.Context) context.Context {
+	if ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// withRetry applies the retry policy passed to the client of azure, retry.Default() if none is passed,
+// autorest retries the requests failing with the status codes it deems transient (429 and 5xx) with the attempts and delay of the policy.
+func withRetry(client *autorest.Client, policy *retry.Policy) {
+	applied := retry.OrDefault(policy)
+	client.RetryAttempts = applied.Retries()
+	client.RetryDuration = applied.BaseDelay
+}
+
+// withPolling makes the client of azure poll the long running operations as per the poller passed, wait.Default() if none is passed,
+// autorest polls at a constant delay (or as asked by azure) hence the backoff of the poller is not applied.
+func withPolling(client *autorest.Client, poller *wait.Poller) {
+	applied := wait.OrDefault(poller)
+	client.PollingDelay = applied.Interval
+	client.PollingDuration = applied.Timeout
+}
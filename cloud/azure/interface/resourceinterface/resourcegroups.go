@@ -6,22 +6,27 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
 	"neuron/cloud/azure/access"
 )
 
 var (
 	token, _, subscription = auth.GetServicePrincipalToken()
-	ctx                    = context.Background()
 )
 
 type GroupsIn struct {
 	ResourceGroup string
 	Location      string `json:"location,omitempty"`
+	// Context controls the cancellation and deadline of the calls made to azure, defaults to context.Background().
+	Context context.Context `json:"-"`
+	// Retry is the policy with which the failed calls to azure are retried, retry.Default() is used if not passed.
+	Retry *retry.Policy `json:"-"`
 }
 
-func getGroupsClient() resources.GroupsClient {
+func getGroupsClient(policy *retry.Policy) resources.GroupsClient {
 	groupsClient := resources.NewGroupsClient(subscription)
 	groupsClient.Authorizer = autorest.NewBearerAuthorizer(token)
+	withRetry(&groupsClient.Client, policy)
 
 	return groupsClient
 }
@@ -29,7 +34,8 @@ func getGroupsClient() resources.GroupsClient {
 // Creates a new resource group
 
 func (g GroupsIn) CreateResourceGroup() (resources.Group, error) {
-	groupsClient := getGroupsClient()
+	ctx := getContext(g.Context)
+	groupsClient := getGroupsClient(g.Retry)
 	fmt.Printf("\n creating resource group '%s' on location: %v", g.ResourceGroup, g.Location)
 	return groupsClient.CreateOrUpdate(
 		ctx,
@@ -38,3 +44,19 @@ func (g GroupsIn) CreateResourceGroup() (resources.Group, error) {
 			Location: to.StringPtr(g.Location),
 		})
 }
+
+// getContext returns the context passed to the call, falls back to context.Background() if none was passed.
+func getContext(ctx context.Context) context.Context {
+	if ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// withRetry applies the retry policy passed to the client of azure, retry.Default() if none is passed,
+// autorest retries the requests failing with the status codes it deems transient (429 and 5xx) with the attempts and delay of the policy.
+func withRetry(client *autorest.Client, policy *retry.Policy) {
+	applied := retry.OrDefault(policy)
+	client.RetryAttempts = applied.Retries()
+	client.RetryDuration = applied.BaseDelay
+}
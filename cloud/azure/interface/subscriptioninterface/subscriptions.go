@@ -5,21 +5,26 @@ import (
 	"fmt"
 	"github.com/Azure/azure-sdk-for-go/services/preview/subscription/mgmt/2018-03-01-preview/subscription"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
 	"neuron/cloud/azure/access"
 )
 
 var (
 	token, _, _ = auth.GetServicePrincipalToken()
-	ctx         = context.Background()
 )
 
 type SubcriptionIn struct {
 	Subscription string
+	// Context controls the cancellation and deadline of the calls made to azure, defaults to context.Background().
+	Context context.Context `json:"-"`
+	// Retry is the policy with which the failed calls to azure are retried, retry.Default() is used if not passed.
+	Retry *retry.Policy `json:"-"`
 }
 
-func getSubscriptionClient() subscription.SubscriptionsClient {
+func getSubscriptionClient(policy *retry.Policy) subscription.SubscriptionsClient {
 	subscriptionClient := subscription.NewSubscriptionsClient()
 	subscriptionClient.Authorizer = autorest.NewBearerAuthorizer(token)
+	withRetry(&subscriptionClient.Client, policy)
 
 	return subscriptionClient
 }
@@ -27,7 +32,8 @@ func getSubscriptionClient() subscription.SubscriptionsClient {
 // This function will get the subcription from subscriptions that are associated with a tenant in azure account.
 
 func (s SubcriptionIn) GetSubscription() (sub subscription.Model, err error) {
-	subscriptionClient := getSubscriptionClient()
+	ctx := getContext(s.Context)
+	subscriptionClient := getSubscriptionClient(s.Retry)
 	future, err := subscriptionClient.Get(
 		ctx,
 		s.Subscription,
@@ -42,8 +48,14 @@ func (s SubcriptionIn) GetSubscription() (sub subscription.Model, err error) {
 
 // This function will list the subcriptions that are associated with a tenant in azure account.
 
+// ListSubscription is same as ListSubscriptionWithContext, but uses context.Background().
 func ListSubscription() (sub []subscription.Model, err error) {
-	subscriptionClient := getSubscriptionClient()
+	return ListSubscriptionWithContext(context.Background())
+}
+
+// ListSubscriptionWithContext lists the resources across the subscription, calls made to azure are bound to the context passed and retried with retry.Default().
+func ListSubscriptionWithContext(ctx context.Context) (sub []subscription.Model, err error) {
+	subscriptionClient := getSubscriptionClient(nil)
 	future, err := subscriptionClient.List(
 		ctx,
 	)
@@ -54,3 +66,19 @@ func ListSubscription() (sub []subscription.Model, err error) {
 
 	return future.Values(), err
 }
+
+// getContext returns the context passed to the call, falls back to context.Background() if none was passed.
+func getContext(ctx context.Context) context.Context {
+	if ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// withRetry applies the retry policy passed to the client of azure, retry.Default() if none is passed,
+// autorest retries the requests failing with the status codes it deems transient (429 and 5xx) with the attempts and delay of the policy.
+func withRetry(client *autorest.Client, policy *retry.Policy) {
+	applied := retry.OrDefault(policy)
+	client.RetryAttempts = applied.Retries()
+	client.RetryDuration = applied.BaseDelay
+}
@@ -34,9 +34,12 @@ func main() {
 	nic, _ := ic.CreateNIC()
 	jsonMarshall(nic)
 
-	m := azurecompute.VMIn{"test", "test-vm", *nic.ID, "ubuntu", "ubuntu@12345", "ubuntu", "", "CentralIndia"}
-	vm, _ := m.CreateVM()
+	m := azurecompute.VMIn{ResourceGroup: "test", VmName: "test-vm", NicID: *nic.ID, UserName: "ubuntu", Password: "ubuntu@12345", Flavour: "ubuntu", Location: "CentralIndia"}
+	vm, key, _ := m.CreateVM()
 	jsonMarshall(vm)
+	if key != nil {
+		fmt.Println(key.PrivateKey)
+	}
 
 }
 
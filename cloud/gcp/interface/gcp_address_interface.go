@@ -0,0 +1,194 @@
+package neurongcp
+
+import (
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+	"google.golang.org/api/compute/v1"
+)
+
+// AddressInput holds the required values to reserve/fetch/release the static external addresses of the region,
+// and to attach them to the network interfaces of the instances as their access configs.
+type AddressInput struct {
+	// ProjectID refers to the ID of the GCP project in which the addresses exists.
+	ProjectID string
+	// Region in which the addresses are reserved.
+	Region string
+	// Zone of the instance to which the address is attached.
+	Zone string
+	// Name of the address or of the instance to be fetched/released/updated.
+	Name string
+	// Address is the address to be reserved.
+	Address *compute.Address
+	// NetworkInterface is the name of the network interface of the instance ex: nic0.
+	NetworkInterface string
+	// AccessConfig is the access config to be added to the network interface, or the name of it to be deleted.
+	AccessConfig *compute.AccessConfig
+	// Operation is the name of the operation to be fetched.
+	Operation string
+	GcpClient
+}
+
+// CreateAddress reserves the address passed, the operation reserving it is returned as gcp reserves the address asynchronously.
+func (a *AddressInput) CreateAddress() (*compute.Operation, error) {
+
+	if a.Client != nil {
+		ctx := a.getContext()
+		computeService, err := compute.New(a.httpClient())
+		if err != nil {
+			return nil, err
+		}
+		resp, err := computeService.Addresses.Insert(a.ProjectID, a.Region, a.Address).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// GetAddress fetches the address named.
+func (a *AddressInput) GetAddress() (*compute.Address, error) {
+
+	if a.Client != nil {
+		ctx := a.getContext()
+		computeService, err := compute.New(a.httpClient())
+		if err != nil {
+			return nil, err
+		}
+		resp, err := computeService.Addresses.Get(a.ProjectID, a.Region, a.Name).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// GetAddresses helps in retriving the information of all the addresses reserved in the selected region.
+func (a *AddressInput) GetAddresses() ([]*compute.Address, error) {
+
+	if a.Client != nil {
+		ctx := a.getContext()
+		computeService, err := compute.New(a.httpClient())
+		if err != nil {
+			return nil, err
+		}
+		req := computeService.Addresses.List(a.ProjectID, a.Region)
+		addresses := make([]*compute.Address, 0)
+		if err := req.Pages(ctx, func(page *compute.AddressList) error {
+			addresses = append(addresses, page.Items...)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		return addresses, nil
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// DeleteAddress releases the address named, the operation releasing it is returned as gcp releases the address asynchronously.
+func (a *AddressInput) DeleteAddress() (*compute.Operation, error) {
+
+	if a.Client != nil {
+		ctx := a.getContext()
+		computeService, err := compute.New(a.httpClient())
+		if err != nil {
+			return nil, err
+		}
+		resp, err := computeService.Addresses.Delete(a.ProjectID, a.Region, a.Name).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// GetInstance fetches the instance named from the zone.
+func (a *AddressInput) GetInstance() (*compute.Instance, error) {
+
+	if a.Client != nil {
+		ctx := a.getContext()
+		computeService, err := compute.New(a.httpClient())
+		if err != nil {
+			return nil, err
+		}
+		resp, err := computeService.Instances.Get(a.ProjectID, a.Zone, a.Name).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// AddAccessConfig adds the access config passed to the network interface of the instance named.
+func (a *AddressInput) AddAccessConfig() (*compute.Operation, error) {
+
+	if a.Client != nil {
+		ctx := a.getContext()
+		computeService, err := compute.New(a.httpClient())
+		if err != nil {
+			return nil, err
+		}
+		resp, err := computeService.Instances.AddAccessConfig(a.ProjectID, a.Zone, a.Name, a.NetworkInterface, a.AccessConfig).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// DeleteAccessConfig deletes the access config, named as the one passed, from the network interface of the instance named.
+func (a *AddressInput) DeleteAccessConfig() (*compute.Operation, error) {
+
+	if a.Client != nil {
+		ctx := a.getContext()
+		computeService, err := compute.New(a.httpClient())
+		if err != nil {
+			return nil, err
+		}
+		resp, err := computeService.Instances.DeleteAccessConfig(a.ProjectID, a.Zone, a.Name, a.AccessConfig.Name, a.NetworkInterface).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// GetRegionOperation fetches the operation named from the region, to know whether it is done.
+func (a *AddressInput) GetRegionOperation() (*compute.Operation, error) {
+
+	if a.Client != nil {
+		ctx := a.getContext()
+		computeService, err := compute.New(a.httpClient())
+		if err != nil {
+			return nil, err
+		}
+		resp, err := computeService.RegionOperations.Get(a.ProjectID, a.Region, a.Operation).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// GetZoneOperation fetches the operation named from the zone, to know whether it is done.
+func (a *AddressInput) GetZoneOperation() (*compute.Operation, error) {
+
+	if a.Client != nil {
+		ctx := a.getContext()
+		computeService, err := compute.New(a.httpClient())
+		if err != nil {
+			return nil, err
+		}
+		resp, err := computeService.ZoneOperations.Get(a.ProjectID, a.Zone, a.Operation).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+	return nil, cloudyerror.InvalidSession()
+}
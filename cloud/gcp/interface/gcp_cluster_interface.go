@@ -5,9 +5,10 @@ package neurongcp
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
 	"google.golang.org/api/container/v1"
 )
 
@@ -22,14 +23,31 @@ type GetClusterInput struct {
 type GcpClient struct {
 	// Client holds the session of GCP which has access.
 	Client *http.Client
+	// Context controls the cancellation and deadline of the calls made to GCP, if not passed context.Background() is used.
+	Context context.Context
+	// Retry is the policy with which the failed calls to GCP are retried, retry.Default() is used if not passed.
+	Retry *retry.Policy
+}
+
+// getContext returns the context set on the client, falls back to context.Background() if none was set.
+func (c *GcpClient) getContext() context.Context {
+	if c.Context != nil {
+		return c.Context
+	}
+	return context.Background()
+}
+
+// httpClient returns the client with which the calls are made to GCP, the calls made through it are retried as per the policy set.
+func (c *GcpClient) httpClient() *http.Client {
+	return retry.HTTPClient(c.Client, retry.OrDefault(c.Retry))
 }
 
 // ListClusters lists all GKE cluster and its details across the regions specified.
 func (c *GetClusterInput) ListClusters() ([]*container.Cluster, error) {
 
 	if c.Client != nil {
-		ctx := context.Background()
-		containerService, err := container.New(c.Client)
+		ctx := c.getContext()
+		containerService, err := container.New(c.httpClient())
 		if err != nil {
 			return nil, err
 		}
@@ -39,7 +57,7 @@ func (c *GetClusterInput) ListClusters() ([]*container.Cluster, error) {
 		}
 		return resp.Clusters, nil
 	}
-	return nil, fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return nil, cloudyerror.InvalidSession()
 
 }
 
@@ -47,8 +65,8 @@ func (c *GetClusterInput) ListClusters() ([]*container.Cluster, error) {
 func (c *GetClusterInput) GetCluster() (*container.Cluster, error) {
 
 	if c.Client != nil {
-		ctx := context.Background()
-		containerService, err := container.New(c.Client)
+		ctx := c.getContext()
+		containerService, err := container.New(c.httpClient())
 		if err != nil {
 			return nil, err
 		}
@@ -58,6 +76,6 @@ func (c *GetClusterInput) GetCluster() (*container.Cluster, error) {
 		}
 		return resp, nil
 	}
-	return nil, fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return nil, cloudyerror.InvalidSession()
 
 }
@@ -0,0 +1,93 @@
+package neurongcp
+
+import (
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+	"google.golang.org/api/compute/v1"
+)
+
+// FirewallInput holds the required values to create/delete/fetch the firewall rules of the project.
+type FirewallInput struct {
+	// ProjectID refers to the ID of the GCP project in which the firewall rules exists.
+	ProjectID string
+	// Name of the firewall rule to be deleted.
+	Name string
+	// Firewall is the firewall rule to be created.
+	Firewall *compute.Firewall
+	GcpClient
+}
+
+// CreateFirewall creates the firewall rule passed, the operation creating it is returned as gcp creates the rule asynchronously.
+func (f *FirewallInput) CreateFirewall() (*compute.Operation, error) {
+
+	if f.Client != nil {
+		ctx := f.getContext()
+		computeService, err := compute.New(f.httpClient())
+		if err != nil {
+			return nil, err
+		}
+		resp, err := computeService.Firewalls.Insert(f.ProjectID, f.Firewall).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// PatchFirewall updates the firewall rule named with the fields set on the Firewall passed.
+func (f *FirewallInput) PatchFirewall() (*compute.Operation, error) {
+
+	if f.Client != nil {
+		ctx := f.getContext()
+		computeService, err := compute.New(f.httpClient())
+		if err != nil {
+			return nil, err
+		}
+		resp, err := computeService.Firewalls.Patch(f.ProjectID, f.Name, f.Firewall).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// DeleteFirewall deletes the firewall rule named, the operation deleting it is returned as gcp deletes the rule asynchronously.
+func (f *FirewallInput) DeleteFirewall() (*compute.Operation, error) {
+
+	if f.Client != nil {
+		ctx := f.getContext()
+		computeService, err := compute.New(f.httpClient())
+		if err != nil {
+			return nil, err
+		}
+		resp, err := computeService.Firewalls.Delete(f.ProjectID, f.Name).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+	return nil, cloudyerror.InvalidSession()
+}
+
+// GetFirewalls helps in retriving the information of all the firewall rules in the selected project.
+func (f *FirewallInput) GetFirewalls() ([]*compute.Firewall, error) {
+
+	if f.Client != nil {
+		ctx := f.getContext()
+		computeService, err := compute.New(f.httpClient())
+		if err != nil {
+			return nil, err
+		}
+		req := computeService.Firewalls.List(f.ProjectID)
+		firewalls := make([]*compute.Firewall, 0)
+		if err := req.Pages(ctx, func(page *compute.FirewallList) error {
+			firewalls = append(firewalls, page.Items...)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		return firewalls, nil
+	}
+	return nil, cloudyerror.InvalidSession()
+}
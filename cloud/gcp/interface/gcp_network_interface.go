@@ -1,9 +1,7 @@
 package neurongcp
 
 import (
-	"context"
-	"fmt"
-
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
 	"google.golang.org/api/compute/v1"
 )
 
@@ -20,8 +18,8 @@ type GetNetworkInput struct {
 func (net *GetNetworkInput) GetNetwork() (*compute.Network, error) {
 
 	if net.Client != nil {
-		ctx := context.Background()
-		computeService, err := compute.New(net.Client)
+		ctx := net.getContext()
+		computeService, err := compute.New(net.httpClient())
 		if err != nil {
 			return nil, err
 		}
@@ -31,7 +29,7 @@ func (net *GetNetworkInput) GetNetwork() (*compute.Network, error) {
 		}
 		return resp, nil
 	}
-	return nil, fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return nil, cloudyerror.InvalidSession()
 
 }
 
@@ -39,8 +37,8 @@ func (net *GetNetworkInput) GetNetwork() (*compute.Network, error) {
 func (net *GetNetworkInput) GetNetworks() ([]*compute.Network, error) {
 
 	if net.Client != nil {
-		ctx := context.Background()
-		computeService, err := compute.New(net.Client)
+		ctx := net.getContext()
+		computeService, err := compute.New(net.httpClient())
 		if err != nil {
 			return nil, err
 		}
@@ -57,6 +55,6 @@ func (net *GetNetworkInput) GetNetworks() ([]*compute.Network, error) {
 
 		return networks, nil
 	}
-	return nil, fmt.Errorf("Did not get session to perform action, cannot proceed further")
+	return nil, cloudyerror.InvalidSession()
 
 }
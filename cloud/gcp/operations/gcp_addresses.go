@@ -0,0 +1,389 @@
+package gcp
+
+import (
+	"context"
+	"strings"
+
+	neurongcp "github.com/nikhilsbhat/neuron-cloudy/cloud/gcp/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
+	"github.com/nikhilsbhat/neuron-cloudy/wait"
+	"google.golang.org/api/compute/v1"
+)
+
+const (
+	// AddressAssociate attaches the address to the network interface of the instance.
+	AddressAssociate = "associate"
+	// AddressDisassociate detaches the address from the instance it is attached to.
+	AddressDisassociate = "disassociate"
+	// defaultInterface is the network interface to which the address is attached when none is passed.
+	defaultInterface = "nic0"
+	// accessConfigName is the name of the access config through which the address is attached, the one gcp gives by default.
+	accessConfigName = "External NAT"
+)
+
+// AddressInput holds the values required to reserve/fetch/update/release the static external addresses of gcp.
+// The address is attached to the instance as the access config of its network interface, which replaces the ephemeral one it had.
+type AddressInput struct {
+	// ProjectID refers to the ID of the GCP project in which the addresses exists.
+	ProjectID string
+	// Region in which the addresses are reserved, the address can be attached only to the instances of the same region.
+	Region string
+	// Name of the address to be reserved.
+	Name string
+	// Names of the addresses to be fetched/updated/released, only the first one is updated.
+	Names []string
+	// Instance is the name of the instance to which the address has to be attached, or of which the addresses has to be fetched.
+	Instance string
+	// Zone of the instance to which the address has to be attached.
+	Zone string
+	// NetworkInterface is the name of the network interface of the instance to which the address has to be attached, defaults to nic0.
+	NetworkInterface string
+	// Action to be performed by UpdateAddress, associate or disassociate.
+	Action string
+	// GetRaw makes sure that function returns unfiltered response if it is set.
+	GetRaw bool
+	// Context controls the cancellation and deadline of the calls made to GCP, if not passed context.Background() is used.
+	Context context.Context
+	// Retry is the policy with which the failed calls to GCP are retried, retry.Default() is used if not passed.
+	Retry *retry.Policy
+	// Wait is the poller with which the operations of GCP are waited till they are done, wait.Default() is used if not passed.
+	Wait *wait.Poller
+	CredMode
+}
+
+// AddressResponse contains filtered/unfiltered response from GCP on the static external address.
+type AddressResponse struct {
+	// Name of the address.
+	Name string
+	// ID of the address.
+	ID uint64
+	// Address is the external IP address reserved.
+	Address string
+	// Region of the address.
+	Region string
+	// Status of the address ex: RESERVED, IN_USE, RELEASED.
+	Status string
+	// Users are the links of the instances using the address.
+	Users []string
+	// CreationTimestamp is the time at which the address was reserved.
+	CreationTimestamp string
+	// AddressRaw contains unfiltered response from GCP on the address.
+	AddressRaw *compute.Address
+}
+
+// AllocateAddress reserves the static external address and attaches it to the instance passed, if any.
+// The address is released back if it could not be attached.
+func (addr *AddressInput) AllocateAddress(client interface{}) (AddressResponse, error) {
+
+	if err := addr.validate(); err != nil {
+		return AddressResponse{}, err
+	}
+	if len(addr.Name) == 0 {
+		return AddressResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the name of the address to be reserved")
+	}
+	if (len(addr.Instance) != 0) && (len(addr.Zone) == 0) {
+		return AddressResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the zone of the instance to which the address has to be attached")
+	}
+	input := addr.interfaceInput(client)
+	input.Address = &compute.Address{Name: addr.Name, AddressType: "EXTERNAL"}
+	operation, err := input.CreateAddress()
+	if err != nil {
+		return AddressResponse{}, err
+	}
+	if err := addr.waitFor(input, operation); err != nil {
+		return AddressResponse{}, err
+	}
+
+	input.Name = addr.Name
+	address, err := input.GetAddress()
+	if err != nil {
+		return AddressResponse{}, err
+	}
+	if len(addr.Instance) != 0 {
+		if err := addr.attach(client, address); err != nil {
+			input.DeleteAddress()
+			return AddressResponse{}, err
+		}
+		if address, err = input.GetAddress(); err != nil {
+			return AddressResponse{}, err
+		}
+	}
+	return addressResponse(address, addr.GetRaw), nil
+}
+
+// GetAddresses fetches the addresses selected by names, the ones used by the instance passed or all the ones reserved in the region.
+func (addr *AddressInput) GetAddresses(client interface{}) ([]AddressResponse, error) {
+
+	if err := addr.validate(); err != nil {
+		return nil, err
+	}
+	input := addr.interfaceInput(client)
+	addresses := make([]*compute.Address, 0)
+	if len(addr.Names) != 0 {
+		for _, name := range addr.Names {
+			input.Name = name
+			address, err := input.GetAddress()
+			if err != nil {
+				return nil, err
+			}
+			addresses = append(addresses, address)
+		}
+	} else {
+		all, err := input.GetAddresses()
+		if err != nil {
+			return nil, err
+		}
+		for _, address := range all {
+			if len(addr.Instance) == 0 || usedBy(address, addr.Instance) {
+				addresses = append(addresses, address)
+			}
+		}
+	}
+
+	response := make([]AddressResponse, 0, len(addresses))
+	for _, address := range addresses {
+		response = append(response, addressResponse(address, addr.GetRaw))
+	}
+	return response, nil
+}
+
+// UpdateAddress performs the Action on the first of the addresses passed:
+// associate attaches it to the network interface of the instance passed, replacing the external address the interface had,
+// and disassociate detaches it from the instance using it.
+func (addr *AddressInput) UpdateAddress(client interface{}) (AddressResponse, error) {
+
+	if err := addr.validate(); err != nil {
+		return AddressResponse{}, err
+	}
+	if len(addr.Names) == 0 {
+		return AddressResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the address to be updated")
+	}
+	input := addr.interfaceInput(client)
+	input.Name = addr.Names[0]
+	address, err := input.GetAddress()
+	if err != nil {
+		return AddressResponse{}, err
+	}
+
+	switch strings.ToLower(addr.Action) {
+	case AddressAssociate:
+		if (len(addr.Instance) == 0) || (len(addr.Zone) == 0) {
+			return AddressResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed either the instance or its zone to which the address has to be attached")
+		}
+		if err := addr.attach(client, address); err != nil {
+			return AddressResponse{}, err
+		}
+	case AddressDisassociate:
+		if err := addr.detach(client, address); err != nil {
+			return AddressResponse{}, err
+		}
+	default:
+		return AddressResponse{}, cloudyerror.Newf(cloudyerror.InvalidInput, "action %s is not supported on the addresses, it should be one of associate or disassociate", addr.Action)
+	}
+
+	input.Name = address.Name
+	if address, err = input.GetAddress(); err != nil {
+		return AddressResponse{}, err
+	}
+	return addressResponse(address, addr.GetRaw), nil
+}
+
+// ReleaseAddress releases the addresses passed, the ones released before the failure are returned along with the error.
+// The addresses used by the instances are not released, they have to be disassociated first.
+func (addr *AddressInput) ReleaseAddress(client interface{}) ([]AddressResponse, error) {
+
+	if err := addr.validate(); err != nil {
+		return nil, err
+	}
+	if len(addr.Names) == 0 {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the addresses to be released")
+	}
+	input := addr.interfaceInput(client)
+	addresses := make([]*compute.Address, 0, len(addr.Names))
+	for _, name := range addr.Names {
+		input.Name = name
+		address, err := input.GetAddress()
+		if err != nil {
+			return nil, err
+		}
+		if len(address.Users) != 0 {
+			return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "address %s is in use by %v, disassociate it before releasing", name, address.Users)
+		}
+		addresses = append(addresses, address)
+	}
+
+	response := make([]AddressResponse, 0, len(addresses))
+	for _, address := range addresses {
+		input.Name = address.Name
+		operation, err := input.DeleteAddress()
+		if err != nil {
+			return response, err
+		}
+		if err := addr.waitFor(input, operation); err != nil {
+			return response, err
+		}
+		response = append(response, AddressResponse{Name: address.Name, ID: address.Id, Address: address.Address, Region: addr.Region, Status: "RELEASED"})
+	}
+	return response, nil
+}
+
+func (addr *AddressInput) validate() error {
+	if len(addr.ProjectID) == 0 {
+		return cloudyerror.New(cloudyerror.InvalidInput, "Project ID cannot be empty")
+	}
+	if len(addr.Region) == 0 {
+		return cloudyerror.New(cloudyerror.InvalidInput, "Region cannot be empty, the addresses are regional")
+	}
+	return nil
+}
+
+func (addr *AddressInput) interfaceInput(client interface{}) *neurongcp.AddressInput {
+	input := new(neurongcp.AddressInput)
+	input.ProjectID = addr.ProjectID
+	input.Region = addr.Region
+	input.Client = getClientFromBase(client, []string{compute.CloudPlatformScope})
+	input.Context = addr.Context
+	input.Retry = addr.Retry
+	return input
+}
+
+// attach attaches the address to the network interface of the instance passed, the external address the interface had is detached first
+// as gcp allows only one access config on the interface.
+func (addr *AddressInput) attach(client interface{}, address *compute.Address) error {
+
+	if len(address.Users) != 0 {
+		return cloudyerror.Newf(cloudyerror.InvalidInput, "address %s is already in use by %v", address.Name, address.Users)
+	}
+	input := addr.interfaceInput(client)
+	input.Zone = addr.Zone
+	input.Name = addr.Instance
+	input.NetworkInterface = addr.NetworkInterface
+	if len(input.NetworkInterface) == 0 {
+		input.NetworkInterface = defaultInterface
+	}
+	instance, err := input.GetInstance()
+	if err != nil {
+		return err
+	}
+	nic := networkInterface(instance, input.NetworkInterface)
+	if nic == nil {
+		return cloudyerror.Newf(cloudyerror.NotFound, "network interface %s is not found on the instance %s", input.NetworkInterface, addr.Instance)
+	}
+	for _, config := range nic.AccessConfigs {
+		input.AccessConfig = config
+		operation, err := input.DeleteAccessConfig()
+		if err != nil {
+			return err
+		}
+		if err := addr.waitFor(input, operation); err != nil {
+			return err
+		}
+	}
+	input.AccessConfig = &compute.AccessConfig{Name: accessConfigName, NatIP: address.Address, Type: "ONE_TO_ONE_NAT"}
+	operation, err := input.AddAccessConfig()
+	if err != nil {
+		return err
+	}
+	return addr.waitFor(input, operation)
+}
+
+// detach removes the access config through which the address is attached to the instance using it.
+func (addr *AddressInput) detach(client interface{}, address *compute.Address) error {
+
+	if len(address.Users) == 0 {
+		return cloudyerror.Newf(cloudyerror.InvalidInput, "address %s is not in use by any instance", address.Name)
+	}
+	// the user is the link of the instance ex: .../projects/<project>/zones/<zone>/instances/<name>.
+	parts := strings.Split(address.Users[0], "/")
+	if (len(parts) < 4) || (parts[len(parts)-2] != "instances") || (parts[len(parts)-4] != "zones") {
+		return cloudyerror.Newf(cloudyerror.Unsupported, "address %s is in use by %s which is not an instance", address.Name, address.Users[0])
+	}
+	input := addr.interfaceInput(client)
+	input.Zone = parts[len(parts)-3]
+	input.Name = parts[len(parts)-1]
+	instance, err := input.GetInstance()
+	if err != nil {
+		return err
+	}
+	for _, nic := range instance.NetworkInterfaces {
+		for _, config := range nic.AccessConfigs {
+			if config.NatIP != address.Address {
+				continue
+			}
+			input.NetworkInterface = nic.Name
+			input.AccessConfig = config
+			operation, err := input.DeleteAccessConfig()
+			if err != nil {
+				return err
+			}
+			return addr.waitFor(input, operation)
+		}
+	}
+	return cloudyerror.Newf(cloudyerror.NotFound, "address %s is not attached to any network interface of the instance %s", address.Name, input.Name)
+}
+
+// waitFor waits till the operation passed is done, the error of the operation is returned if it failed.
+func (addr *AddressInput) waitFor(input *neurongcp.AddressInput, operation *compute.Operation) error {
+
+	ctx := addr.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	input.Operation = operation.Name
+	return wait.OrDefault(addr.Wait).Until(ctx, "operation "+operation.Name, func(ctx context.Context) (bool, string, error) {
+		var current *compute.Operation
+		var err error
+		if len(operation.Zone) != 0 {
+			current, err = input.GetZoneOperation()
+		} else {
+			current, err = input.GetRegionOperation()
+		}
+		if err != nil {
+			return false, "", err
+		}
+		if current.Status != "DONE" {
+			return false, current.Status, nil
+		}
+		if (current.Error != nil) && (len(current.Error.Errors) != 0) {
+			failure := current.Error.Errors[0]
+			return true, current.Status, cloudyerror.Newf(cloudyerror.Unknown, "operation %s failed with %s: %s", operation.Name, failure.Code, failure.Message)
+		}
+		return true, current.Status, nil
+	})
+}
+
+// networkInterface returns the network interface of the instance named, nil if the instance has none by the name.
+func networkInterface(instance *compute.Instance, name string) *compute.NetworkInterface {
+	for _, nic := range instance.NetworkInterfaces {
+		if nic.Name == name {
+			return nic
+		}
+	}
+	return nil
+}
+
+// usedBy reports whether the address is in use by the instance named.
+func usedBy(address *compute.Address, instance string) bool {
+	for _, user := range address.Users {
+		if strings.HasSuffix(user, "/instances/"+instance) {
+			return true
+		}
+	}
+	return false
+}
+
+func addressResponse(address *compute.Address, raw bool) AddressResponse {
+	if raw {
+		return AddressResponse{AddressRaw: address}
+	}
+	return AddressResponse{
+		Name:              address.Name,
+		ID:                address.Id,
+		Address:           address.Address,
+		Region:            address.Region[strings.LastIndex(address.Region, "/")+1:],
+		Status:            address.Status,
+		Users:             address.Users,
+		CreationTimestamp: address.CreationTimestamp,
+	}
+}
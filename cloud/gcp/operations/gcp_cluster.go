@@ -6,9 +6,11 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
 
 	neurongcp "github.com/nikhilsbhat/neuron-cloudy/cloud/gcp/interface"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
 	"google.golang.org/api/compute/v1"
 	"google.golang.org/api/container/v1"
 )
@@ -23,6 +25,10 @@ type GetClusterInput struct {
 	Regions []string
 	// GetRaw makes sure that function returns unfiltered response if it is set.
 	GetRaw bool
+	// Context controls the cancellation and deadline of the calls made to GCP, if not passed context.Background() is used.
+	Context context.Context
+	// Retry is the policy with which the failed calls to GCP are retried, retry.Default() is used if not passed.
+	Retry *retry.Policy
 	CredMode
 }
 
@@ -86,6 +92,8 @@ func (clust GetClusterInput) GetClusters(client interface{}) ([]ClusterResponse,
 		input := new(neurongcp.GetClusterInput)
 		input.ResourceURL = fmt.Sprintf("projects/%s/locations/%s", clust.ProjectID, r)
 		input.Client = sess
+		input.Context = clust.Context
+		input.Retry = clust.Retry
 		resp, err := input.ListClusters()
 		if err != nil {
 			return nil, err
@@ -137,6 +145,8 @@ func (clust GetClusterInput) GetCluster(client interface{}) ([]ClusterResponse,
 	input := new(neurongcp.GetClusterInput)
 	input.ResourceURL = fmt.Sprintf("projects/%s/locations/%s/clusters/%s", clust.ProjectID, clust.Regions[0], clust.ClusterName)
 	input.Client = sess
+	input.Context = clust.Context
+	input.Retry = clust.Retry
 	cluster, err := input.GetCluster()
 	if err != nil {
 		return nil, err
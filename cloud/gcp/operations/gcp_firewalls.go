@@ -0,0 +1,471 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	neurongcp "github.com/nikhilsbhat/neuron-cloudy/cloud/gcp/interface"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
+	"google.golang.org/api/compute/v1"
+)
+
+// firewallName matches the names of the firewall rules which make up a security group, <group>-in|eg-<hash of the rule>.
+var firewallName = regexp.MustCompile(`^([a-z](?:[-a-z0-9]*[a-z0-9])?)-(in|eg)-([0-9a-f]{8})$`)
+
+// FirewallRule is a rule of the security group made of the firewall rules of gcp.
+type FirewallRule struct {
+	// Direction of the traffic the rule allows, either ingress or egress. Defaults to ingress.
+	Direction string
+	// Protocol of the traffic the rule allows ex: tcp, udp, icmp or all.
+	Protocol string
+	// Ports allowed by the rule ex: 22, 8000-8080. All the ports are allowed if not set.
+	Ports string
+	// Cidrs are the ranges of IP address from/to which the traffic is allowed.
+	Cidrs []string
+	// SecurityGroups are the names of the security groups from which the traffic is allowed, gcp supports it only on ingress.
+	SecurityGroups []string
+	// Description of the rule.
+	Description string
+}
+
+// FirewallInput holds the required values to create/update/delete/fetch the security groups made of the firewall rules of gcp.
+// A security group is the set of firewall rules of the network which targets the instances tagged with the name of the group.
+type FirewallInput struct {
+	// ProjectID refers to the ID of the GCP project in which the security groups exists.
+	ProjectID string
+	// Network is the name or the link of the network of the security group.
+	Network string
+	// Name of the security group, this is also the network tag of the instances to which the rules are applied.
+	Name string
+	// Names of the security groups to be fetched/updated/deleted.
+	Names []string
+	// Description of the security group, this is set on each of its firewall rules.
+	Description string
+	// Rules of the security group.
+	Rules []FirewallRule
+	// Action to be performed on the rules of the security groups while updating, one of add, revoke or set.
+	Action string
+	// GetRaw makes sure that function returns unfiltered response if it is set.
+	GetRaw bool
+	// Context controls the cancellation and deadline of the calls made to GCP, if not passed context.Background() is used.
+	Context context.Context
+	// Retry is the policy with which the failed calls to GCP are retried, retry.Default() is used if not passed.
+	Retry *retry.Policy
+	CredMode
+}
+
+// FirewallResponse contains filtered/unfiltered response from GCP on the security group.
+type FirewallResponse struct {
+	// Name of the security group.
+	Name string
+	// Network of the security group.
+	Network string
+	// Description of the security group.
+	Description string
+	// Rules of the security group.
+	Rules []FirewallRule
+	// Firewalls are the names of the firewall rules which make up the security group.
+	Firewalls []string
+	// FirewallsRaw contains unfiltered response from GCP on the firewall rules of the security group.
+	FirewallsRaw []*compute.Firewall
+}
+
+// CreateSecurityGroup creates the firewall rules of the security group, one for each of the rules and the peers of it.
+// The firewall rules are created asynchronously by gcp, the ones created are returned.
+func (fire *FirewallInput) CreateSecurityGroup(client interface{}) (FirewallResponse, error) {
+
+	if len(fire.ProjectID) == 0 {
+		return FirewallResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "Project ID cannot be empty")
+	}
+	if err := validGroupName(fire.Name); err != nil {
+		return FirewallResponse{}, err
+	}
+	if len(fire.Network) == 0 {
+		return FirewallResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not provided the network of the security group, cannot proceed further")
+	}
+	if len(fire.Rules) == 0 {
+		return FirewallResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "The security group of gcp is made of its firewall rules, atleast one rule has to be passed")
+	}
+	firewalls, err := fire.firewalls(fire.Name, fire.Rules)
+	if err != nil {
+		return FirewallResponse{}, err
+	}
+
+	input := fire.interfaceInput(client)
+	for _, firewall := range firewalls {
+		input.Firewall = firewall
+		if _, err := input.CreateFirewall(); err != nil {
+			return FirewallResponse{}, err
+		}
+	}
+	return firewallResponse(fire.Name, firewalls, fire.GetRaw), nil
+}
+
+// GetSecurityGroups fetches the security groups selected by names, the ones of the network are fetched if names are not passed.
+// All the security groups of the project are fetched when neither of them are passed.
+func (fire *FirewallInput) GetSecurityGroups(client interface{}) ([]FirewallResponse, error) {
+
+	if len(fire.ProjectID) == 0 {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "Project ID cannot be empty")
+	}
+	groups, err := fire.groups(client)
+	if err != nil {
+		return nil, err
+	}
+	response := make([]FirewallResponse, 0)
+	for _, name := range sortedGroupNames(groups) {
+		response = append(response, firewallResponse(name, groups[name], fire.GetRaw))
+	}
+	return response, nil
+}
+
+// UpdateSecurityGroup adds, revokes or sets the rules of the security groups selected by names.
+// The rules already present are not created again, only their description is updated if it differs.
+func (fire *FirewallInput) UpdateSecurityGroup(client interface{}) ([]FirewallResponse, error) {
+
+	if len(fire.ProjectID) == 0 {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "Project ID cannot be empty")
+	}
+	if len(fire.Names) == 0 {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the security groups to be updated")
+	}
+	action := strings.ToLower(fire.Action)
+	if action != "add" && action != "revoke" && action != "set" {
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The action %q is not supported on the rules of security group, it has to be one of add, revoke or set", fire.Action)
+	}
+	groups, err := fire.groups(client)
+	if err != nil {
+		return nil, err
+	}
+
+	input := fire.interfaceInput(client)
+	response := make([]FirewallResponse, 0)
+	for _, name := range fire.Names {
+		existing, ok := groups[name]
+		if !ok {
+			return response, cloudyerror.Newf(cloudyerror.NotFound, "The security group %s does not exists", name)
+		}
+		update := *fire
+		update.Network = existing[0].Network
+		update.Description = groupDescription(existing[0])
+		wanted, err := update.firewalls(name, fire.Rules)
+		if err != nil {
+			return response, err
+		}
+		present := make(map[string]*compute.Firewall)
+		for _, firewall := range existing {
+			present[firewall.Name] = firewall
+		}
+
+		desired := make(map[string]bool)
+		for _, firewall := range wanted {
+			desired[firewall.Name] = true
+			current, ok := present[firewall.Name]
+			switch {
+			case action == "revoke":
+				if ok {
+					input.Name = firewall.Name
+					if _, err := input.DeleteFirewall(); err != nil {
+						return response, err
+					}
+					delete(present, firewall.Name)
+				}
+			case !ok:
+				input.Firewall = firewall
+				if _, err := input.CreateFirewall(); err != nil {
+					return response, err
+				}
+				present[firewall.Name] = firewall
+			case ruleDescription(current) != ruleDescription(firewall):
+				input.Name = firewall.Name
+				input.Firewall = &compute.Firewall{Description: firewall.Description}
+				if _, err := input.PatchFirewall(); err != nil {
+					return response, err
+				}
+				current.Description = firewall.Description
+			}
+		}
+		if action == "set" {
+			for firewallName := range present {
+				if desired[firewallName] {
+					continue
+				}
+				input.Name = firewallName
+				if _, err := input.DeleteFirewall(); err != nil {
+					return response, err
+				}
+				delete(present, firewallName)
+			}
+		}
+
+		firewalls := make([]*compute.Firewall, 0, len(present))
+		for _, firewall := range present {
+			firewalls = append(firewalls, firewall)
+		}
+		response = append(response, firewallResponse(name, firewalls, fire.GetRaw))
+	}
+	return response, nil
+}
+
+// DeleteSecurityGroup deletes all the firewall rules of the security groups selected by names.
+// The security groups deleted before the failure are returned along with the error.
+func (fire *FirewallInput) DeleteSecurityGroup(client interface{}) ([]FirewallResponse, error) {
+
+	if len(fire.ProjectID) == 0 {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "Project ID cannot be empty")
+	}
+	if len(fire.Names) == 0 {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the security groups to be deleted")
+	}
+	groups, err := fire.groups(client)
+	if err != nil {
+		return nil, err
+	}
+
+	input := fire.interfaceInput(client)
+	response := make([]FirewallResponse, 0)
+	for _, name := range fire.Names {
+		firewalls, ok := groups[name]
+		if !ok {
+			return response, cloudyerror.Newf(cloudyerror.NotFound, "The security group %s does not exists", name)
+		}
+		for _, firewall := range firewalls {
+			input.Name = firewall.Name
+			if _, err := input.DeleteFirewall(); err != nil {
+				return response, err
+			}
+		}
+		response = append(response, firewallResponse(name, firewalls, fire.GetRaw))
+	}
+	return response, nil
+}
+
+func (fire *FirewallInput) interfaceInput(client interface{}) *neurongcp.FirewallInput {
+	input := new(neurongcp.FirewallInput)
+	input.ProjectID = fire.ProjectID
+	input.Client = getClientFromBase(client, []string{compute.CloudPlatformScope})
+	input.Context = fire.Context
+	input.Retry = fire.Retry
+	return input
+}
+
+// groups fetches the firewall rules of the project and groups them by the security group they make up.
+// Only the groups selected by the names or the network of the input are returned.
+func (fire *FirewallInput) groups(client interface{}) (map[string][]*compute.Firewall, error) {
+	firewalls, err := fire.interfaceInput(client).GetFirewalls()
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[string]bool)
+	for _, name := range fire.Names {
+		selected[name] = true
+	}
+
+	groups := make(map[string][]*compute.Firewall)
+	for _, firewall := range firewalls {
+		name := groupOf(firewall)
+		if name == "" {
+			continue
+		}
+		if len(selected) != 0 && !selected[name] {
+			continue
+		}
+		if len(selected) == 0 && fire.Network != "" && !sameNetwork(firewall.Network, fire.Network) {
+			continue
+		}
+		groups[name] = append(groups[name], firewall)
+	}
+	return groups, nil
+}
+
+// firewalls converts the rules of the security group to the firewall rules of gcp, one for each of the rules and the peers of it.
+func (fire *FirewallInput) firewalls(group string, rules []FirewallRule) ([]*compute.Firewall, error) {
+	firewalls := make([]*compute.Firewall, 0)
+	names := make(map[string]bool)
+	for _, rule := range rules {
+		direction := strings.ToLower(rule.Direction)
+		if direction == "" {
+			direction = "ingress"
+		}
+		if direction != "ingress" && direction != "egress" {
+			return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The direction %q of the rule is not valid, it has to be either ingress or egress", rule.Direction)
+		}
+		if direction == "egress" && len(rule.SecurityGroups) != 0 {
+			return nil, cloudyerror.New(cloudyerror.InvalidInput, "The egress rules of gcp cannot allow the traffic to security groups, use the CIDR blocks instead")
+		}
+		if len(rule.Cidrs)+len(rule.SecurityGroups) == 0 {
+			return nil, cloudyerror.New(cloudyerror.InvalidInput, "The rule has neither the CIDR blocks nor the security groups to which the traffic is allowed")
+		}
+		allowed, err := firewallAllowed(rule.Protocol, rule.Ports)
+		if err != nil {
+			return nil, err
+		}
+
+		peers := make([]string, 0)
+		for _, cidr := range rule.Cidrs {
+			_, block, cidrerr := net.ParseCIDR(cidr)
+			if cidrerr != nil {
+				return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The CIDR block %q of the rule is not valid", cidr)
+			}
+			peers = append(peers, block.String())
+		}
+		peers = append(peers, rule.SecurityGroups...)
+
+		for i, peer := range peers {
+			firewall := &compute.Firewall{
+				Network:     networkLink(fire.Network),
+				Direction:   strings.ToUpper(direction),
+				Allowed:     []*compute.FirewallAllowed{allowed},
+				TargetTags:  []string{group},
+				Description: firewallDescription(fire.Description, rule.Description),
+			}
+			switch {
+			case i >= len(rule.Cidrs):
+				firewall.SourceTags = []string{peer}
+			case direction == "egress":
+				firewall.DestinationRanges = []string{peer}
+			default:
+				firewall.SourceRanges = []string{peer}
+			}
+			key := strings.Join([]string{direction, allowed.IPProtocol, strings.Join(allowed.Ports, ","), peer}, "|")
+			firewall.Name = fmt.Sprintf("%s-%s-%08x", group, direction[:2], crc32.ChecksumIEEE([]byte(key)))
+			if names[firewall.Name] {
+				continue
+			}
+			names[firewall.Name] = true
+			firewalls = append(firewalls, firewall)
+		}
+	}
+	return firewalls, nil
+}
+
+// firewallAllowed converts the protocol and the ports of the rule to the ones of the firewall rule of gcp.
+func firewallAllowed(protocol, ports string) (*compute.FirewallAllowed, error) {
+	protocol = strings.ToLower(protocol)
+	switch protocol {
+	case "all", "-1":
+		protocol = "all"
+	case "tcp", "udp", "icmp", "esp", "ah", "sctp", "ipip":
+	default:
+		if number, err := strconv.Atoi(protocol); err != nil || number < 0 || number > 255 {
+			return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The protocol %q of the rule is not valid, it has to be one of tcp, udp, icmp, all or the number of the protocol", protocol)
+		}
+	}
+	allowed := &compute.FirewallAllowed{IPProtocol: protocol}
+	if ports == "" {
+		return allowed, nil
+	}
+	if protocol != "tcp" && protocol != "udp" && protocol != "sctp" {
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The ports cannot be set on the rules of the protocol %q in gcp", protocol)
+	}
+	bounds := strings.SplitN(ports, "-", 2)
+	from, fromerr := strconv.Atoi(bounds[0])
+	to, toerr := from, error(nil)
+	if len(bounds) == 2 {
+		to, toerr = strconv.Atoi(bounds[1])
+	}
+	if fromerr != nil || toerr != nil || from < 0 || to > 65535 || from > to {
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The ports %q of the rule are not valid, it has to be a port or a range of ports ex: 22, 8000-8080", ports)
+	}
+	if from == to {
+		allowed.Ports = []string{strconv.Itoa(from)}
+	} else {
+		allowed.Ports = []string{fmt.Sprintf("%d-%d", from, to)}
+	}
+	return allowed, nil
+}
+
+// validGroupName makes sure the firewall rules named after the security group are valid names in gcp.
+func validGroupName(name string) error {
+	if len(name) == 0 {
+		return cloudyerror.New(cloudyerror.InvalidInput, "You have not provided the name of the security group, cannot proceed further")
+	}
+	if len(name) > 51 || !firewallName.MatchString(name+"-in-00000000") {
+		return cloudyerror.Newf(cloudyerror.InvalidInput, "The name %q of the security group is not valid, it has to be of lowercase letters, digits and hyphens and not more than 51 characters", name)
+	}
+	return nil
+}
+
+// groupOf returns the security group the firewall rule is part of, empty if it is not part of any.
+func groupOf(firewall *compute.Firewall) string {
+	match := firewallName.FindStringSubmatch(firewall.Name)
+	if match == nil || len(firewall.TargetTags) != 1 || firewall.TargetTags[0] != match[1] {
+		return ""
+	}
+	return match[1]
+}
+
+// firewallDescription joins the description of the security group with the one of the rule, separated by " | ".
+func firewallDescription(group, rule string) string {
+	if rule == "" {
+		return group
+	}
+	return group + " | " + rule
+}
+
+// ruleDescription returns the description of the rule from the one of the firewall rule.
+func ruleDescription(firewall *compute.Firewall) string {
+	if index := strings.Index(firewall.Description, " | "); index >= 0 {
+		return firewall.Description[index+3:]
+	}
+	return ""
+}
+
+// groupDescription returns the description of the security group from the one of the firewall rule.
+func groupDescription(firewall *compute.Firewall) string {
+	if index := strings.Index(firewall.Description, " | "); index >= 0 {
+		return firewall.Description[:index]
+	}
+	return firewall.Description
+}
+
+// networkLink returns the partial link of the network, the links passed are returned as is.
+func networkLink(network string) string {
+	if strings.Contains(network, "/") {
+		return network
+	}
+	return "global/networks/" + network
+}
+
+func sameNetwork(link, network string) bool {
+	return link == network || strings.HasSuffix(link, "/"+networkLink(network))
+}
+
+func sortedGroupNames(groups map[string][]*compute.Firewall) []string {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// firewallResponse converts the firewall rules of the security group to its response.
+func firewallResponse(name string, firewalls []*compute.Firewall, raw bool) FirewallResponse {
+	sort.Slice(firewalls, func(i, j int) bool { return firewalls[i].Name < firewalls[j].Name })
+	if raw {
+		return FirewallResponse{Name: name, FirewallsRaw: firewalls}
+	}
+	response := FirewallResponse{Name: name}
+	for _, firewall := range firewalls {
+		response.Network = firewall.Network
+		response.Description = groupDescription(firewall)
+		response.Firewalls = append(response.Firewalls, firewall.Name)
+		rule := FirewallRule{Direction: strings.ToLower(firewall.Direction), Description: ruleDescription(firewall)}
+		for _, allowed := range firewall.Allowed {
+			rule.Protocol = allowed.IPProtocol
+			rule.Ports = strings.Join(allowed.Ports, ",")
+		}
+		rule.Cidrs = append(append(rule.Cidrs, firewall.SourceRanges...), firewall.DestinationRanges...)
+		rule.SecurityGroups = firewall.SourceTags
+		response.Rules = append(response.Rules, rule)
+	}
+	return response
+}
@@ -53,6 +53,8 @@ func (net *GetNetworkInput) GetNetworks(client interface{}) ([]NetworkResponse,
 	input := new(neuron.GetNetworkInput)
 	input.ProjectID = net.ProjectID
 	input.Client = sess
+	input.Context = net.Context
+	input.Retry = net.Retry
 	networks, err := input.GetNetworks()
 	if err != nil {
 		return nil, err
@@ -82,6 +84,7 @@ func (net *GetNetworkInput) GetNetworks(client interface{}) ([]NetworkResponse,
 		// 	return response, err
 		// }
 		// netw.Duration = duration
+		response = append(response, *netw)
 	}
 	return response, nil
 }
@@ -110,6 +113,8 @@ func (net GetNetworkInput) GetNetwork(client interface{}) ([]NetworkResponse, er
 	input.ProjectID = net.ProjectID
 	input.NetworkID = net.NetworkID
 	input.Client = sess
+	input.Context = net.Context
+	input.Retry = net.Retry
 	network, err := input.GetNetwork()
 	if err != nil {
 		return nil, err
@@ -140,6 +145,7 @@ func (net GetNetworkInput) GetNetwork(client interface{}) ([]NetworkResponse, er
 		// 	return response, err
 		// }
 		// netw.Duration = duration
+		response = append(response, *netw)
 	}
 
 	return response, nil
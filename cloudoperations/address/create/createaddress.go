@@ -0,0 +1,48 @@
+package addresscreate
+
+import (
+	"context"
+
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
+)
+
+// AddressResponse will return the filtered/unfiltered responses of variuos clouds on the static public IP addresses.
+type AddressResponse = support.AddressResponse
+
+// AllocateAddress reserves the static public IP address in the cloud, and associates it with the server or network interface passed if any.
+func (addr *AllocateAddressInput) AllocateAddress() (AddressResponse, error) {
+	return addr.AllocateAddressWithContext(context.Background())
+}
+
+// AllocateAddressWithContext is same as AllocateAddress, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (addr *AllocateAddressInput) AllocateAddressWithContext(ctx context.Context) (AddressResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetAddressProvider(addr.Cloud.Name)
+	if err != nil {
+		return AddressResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, addr.Cloud.DryRun); err != nil {
+		return AddressResponse{}, err
+	}
+	addressin := support.AllocateAddressInput(*addr)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response AddressResponse
+	err = support.Track(ctx, addr.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.AllocateAddress(ctx, &addressin)
+		return support.AddressesCreated(addr.Cloud, response.Addresses), opErr
+	})
+	return response, err
+}
+
+// New returns the new AllocateAddressInput instance with empty values.
+func New() *AllocateAddressInput {
+	addr := &AllocateAddressInput{}
+	return addr
+}
@@ -0,0 +1,25 @@
+// Package addresscreate makes the tool cloud agnostic with respect to reserving the static public IP addresses.
+// The decision will be made here to route the request to respective package based on input.
+package addresscreate
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// AllocateAddressInput implements method AllocateAddress and holds parameter for reserving the address.
+type AllocateAddressInput struct {
+	// Name of the address that has to be reserved, this is required in gcp.
+	Name string `json:"name"`
+	// ServerID is the ID of the server with which the address has to be associated, the name of the instance in gcp.
+	ServerID string `json:"serverid"`
+	// NetworkInterfaceID is the ID of the network interface with which the address has to be associated, it takes precedence over ServerID in aws.
+	// In gcp this is the name of the network interface of the server ex: nic0, which is the default.
+	NetworkInterfaceID string `json:"networkinterfaceid"`
+	// Zone of the server with which the address has to be associated, this is required only in gcp.
+	Zone string `json:"zone"`
+	// ProjectID refers to the ID of the GCP project in which the address has to be reserved.
+	ProjectID string
+	Cloud     cmn.Cloud
+}
+
+//Nothing much from this file. This file contains only the structs for address/create
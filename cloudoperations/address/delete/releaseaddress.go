@@ -0,0 +1,48 @@
+package addressdelete
+
+import (
+	"context"
+
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
+)
+
+// AddressResponse will return the filtered/unfiltered responses of variuos clouds on the static public IP addresses.
+type AddressResponse = support.AddressResponse
+
+// ReleaseAddress releases the addresses selected back to the cloud, the addresses associated have to be disassociated first.
+func (addr *ReleaseAddressInput) ReleaseAddress() (AddressResponse, error) {
+	return addr.ReleaseAddressWithContext(context.Background())
+}
+
+// ReleaseAddressWithContext is same as ReleaseAddress, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (addr *ReleaseAddressInput) ReleaseAddressWithContext(ctx context.Context) (AddressResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetAddressProvider(addr.Cloud.Name)
+	if err != nil {
+		return AddressResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, addr.Cloud.DryRun); err != nil {
+		return AddressResponse{}, err
+	}
+	addressin := support.ReleaseAddressInput(*addr)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response AddressResponse
+	err = support.Track(ctx, addr.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.ReleaseAddress(ctx, &addressin)
+		return support.AddressesDeleted(response.Addresses), opErr
+	})
+	return response, err
+}
+
+// New returns the new ReleaseAddressInput instance with empty values.
+func New() *ReleaseAddressInput {
+	addr := &ReleaseAddressInput{}
+	return addr
+}
@@ -0,0 +1,18 @@
+// Package addressdelete makes the tool cloud agnostic with respect to releasing the static public IP addresses.
+// The decision will be made here to route the request to respective package based on input.
+package addressdelete
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// ReleaseAddressInput implements method ReleaseAddress and holds parameter for releasing addresses.
+type ReleaseAddressInput struct {
+	// AddressIDs are the IDs of the addresses to be released, the allocation IDs in aws and the names in gcp.
+	AddressIDs []string `json:"addressids"`
+	// ProjectID refers to the ID of the GCP project in which the addresses are reserved.
+	ProjectID string
+	Cloud     cmn.Cloud
+}
+
+//Nothing much from this file. This file contains only the structs for address/delete
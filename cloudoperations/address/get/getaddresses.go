@@ -0,0 +1,36 @@
+package addressget
+
+import (
+	"context"
+
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+)
+
+// AddressResponse will return the filtered/unfiltered responses of variuos clouds on the static public IP addresses.
+type AddressResponse = support.AddressResponse
+
+// GetAddresses fetches the addresses selected, the ones associated with the network interface or server, or all the ones in the region are fetched if the addresses are not passed.
+func (addr *GetAddressesInput) GetAddresses() (AddressResponse, error) {
+	return addr.GetAddressesWithContext(context.Background())
+}
+
+// GetAddressesWithContext is same as GetAddresses, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (addr *GetAddressesInput) GetAddressesWithContext(ctx context.Context) (AddressResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetAddressProvider(addr.Cloud.Name)
+	if err != nil {
+		return AddressResponse{}, err
+	}
+	addressin := support.GetAddressesInput(*addr)
+	return provider.GetAddresses(ctx, &addressin)
+}
+
+// New returns the new GetAddressesInput instance with empty values.
+func New() *GetAddressesInput {
+	addr := &GetAddressesInput{}
+	return addr
+}
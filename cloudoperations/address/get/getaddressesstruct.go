@@ -0,0 +1,22 @@
+// Package addressget makes the tool cloud agnostic in fetching the details of the static public IP addresses.
+// The decision will be made here to route the request to respective package based on input.
+package addressget
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// GetAddressesInput implements method GetAddresses and holds parameter for fetching addresses.
+type GetAddressesInput struct {
+	// AddressIDs are the IDs of the addresses of which information has to be retrieved, the allocation IDs in aws and the names in gcp.
+	AddressIDs []string `json:"addressids"`
+	// ServerID is the ID of the server of which the addresses are retrieved if AddressIDs are not passed.
+	ServerID string `json:"serverid"`
+	// NetworkInterfaceID is the ID of the network interface of which the addresses are retrieved if AddressIDs are not passed, this is supported only in aws.
+	NetworkInterfaceID string `json:"networkinterfaceid"`
+	// ProjectID refers to the ID of the GCP project of which the addresses are retrieved.
+	ProjectID string
+	Cloud     cmn.Cloud
+}
+
+//Nothing much from this file. This file contains only the structs for address/get
@@ -0,0 +1,39 @@
+package addressupdate
+
+import (
+	"context"
+
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+)
+
+// AddressResponse will return the filtered/unfiltered responses of variuos clouds on the static public IP addresses.
+type AddressResponse = support.AddressResponse
+
+// UpdateAddress associates the address with the server or network interface passed, or disassociates it from the one it is associated with, as per the action passed.
+func (addr *UpdateAddressInput) UpdateAddress() (AddressResponse, error) {
+	return addr.UpdateAddressWithContext(context.Background())
+}
+
+// UpdateAddressWithContext is same as UpdateAddress, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (addr *UpdateAddressInput) UpdateAddressWithContext(ctx context.Context) (AddressResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetAddressProvider(addr.Cloud.Name)
+	if err != nil {
+		return AddressResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, addr.Cloud.DryRun); err != nil {
+		return AddressResponse{}, err
+	}
+	addressin := support.UpdateAddressInput(*addr)
+	return provider.UpdateAddress(ctx, &addressin)
+}
+
+// New returns the new UpdateAddressInput instance with empty values.
+func New() *UpdateAddressInput {
+	addr := &UpdateAddressInput{}
+	return addr
+}
@@ -0,0 +1,30 @@
+// Package addressupdate makes the tool cloud agnostic for associating and disassociating the static public IP addresses.
+// The decision will be made here to route the request to respective package based on input.
+package addressupdate
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// UpdateAddressInput implements method UpdateAddress and holds parameter for updating address.
+type UpdateAddressInput struct {
+	// AddressID is the ID of the address to be updated, the allocation ID in aws and the name in gcp.
+	AddressID string `json:"addressid"`
+	// Action to be performed on the address, associate associates it with the server or network interface passed
+	// and disassociate disassociates it from the one it is associated with.
+	Action string `json:"action"`
+	// ServerID is the ID of the server with which the address has to be associated, the name of the instance in gcp.
+	ServerID string `json:"serverid"`
+	// NetworkInterfaceID is the ID of the network interface with which the address has to be associated, it takes precedence over ServerID in aws.
+	// In gcp this is the name of the network interface of the server ex: nic0, which is the default.
+	NetworkInterfaceID string `json:"networkinterfaceid"`
+	// Reassociate moves the address which is already associated to the server or network interface passed, this is supported only in aws.
+	Reassociate bool `json:"reassociate"`
+	// Zone of the server with which the address has to be associated, this is required only in gcp.
+	Zone string `json:"zone"`
+	// ProjectID refers to the ID of the GCP project in which the address is reserved.
+	ProjectID string
+	Cloud     cmn.Cloud
+}
+
+//Nothing much from this file. This file contains only the structs for address/update
@@ -1,5 +1,11 @@
 package cloudoperations
 
+import (
+	"github.com/nikhilsbhat/neuron-cloudy/retry"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
+	"github.com/nikhilsbhat/neuron-cloudy/wait"
+)
+
 // Cloud is the common structure which is called in all cloudoperations.
 type Cloud struct {
 	// Pass the cloud in which the resource has to be created. usage: "aws","azure" etc.
@@ -15,4 +21,21 @@ type Cloud struct {
 	CredPath string `json:"credpath"`
 	// Client for the appropriate cloud, without this one cannot interact with the various resource of neuron-cloudy.
 	Client interface{}
+	// Set DryRun to get the plan of the actions the operation would perform in place of performing them,
+	// this is honored only by the operations which create/update/delete the resources.
+	DryRun bool `json:"dryrun"`
+	// Retry is the policy with which the calls failing with transient errors (throttling, eventual consistency etc.) are retried,
+	// retry.Default() is used if not set. Use retry.Never() to not retry the calls.
+	Retry *retry.Policy `json:"retry,omitempty"`
+	// Wait is the poller with which the operation waits on the resources to reach the state expected (ex: instance to be running),
+	// wait.Default() is used if not set. Set it on the input of the operation to override the interval or the timeout of that call alone.
+	Wait *wait.Poller `json:"wait,omitempty"`
+	// Concurrency is the number of regions queried at once by the operations spanning all the regions (ex: GetAllServers),
+	// fanout.DefaultConcurrency is used if not set.
+	Concurrency int `json:"concurrency,omitempty"`
+	// State is the backend in which the resources created/deleted by the operations are recorded under Stack (ex: state.NewLocal(".cloudy")),
+	// the operation holds the lock of the stack while it runs. Nothing is recorded if either State or Stack is not set.
+	State state.Backend `json:"-"`
+	// Stack is the name of the stack (environment) to which the resources created/deleted by the operation belong to.
+	Stack string `json:"stack,omitempty"`
 }
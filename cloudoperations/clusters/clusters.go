@@ -3,8 +3,8 @@
 package clusters
 
 import (
-	gcp "github.com/nikhilsbhat/neuron-cloudy/cloud/gcp/operations"
 	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
 )
 
 // GetClusterInput is the way in to fetch the details of the cluster.
@@ -21,15 +21,6 @@ type GetClusterInput struct {
 }
 
 // ClusterResponse returns the filtered/unfiltered responses of variuos clouds.
-type ClusterResponse struct {
-	// Contains filtered/unfiltered response from AWS.
-	AwsResponse string `json:"AwsResponse,omitempty"`
-	// Contains filtered/unfiltered response from Azure.
-	AzureResponse string `json:"AzureResponse,omitempty"`
-	// Contains filtered/unfiltered response from GCP.
-	GCPResponse []gcp.ClusterResponse `json:"GcpResponse,omitempty"`
-	// Default response if no inputs or matching the values required.
-	DefaultResponse string `json:"DefaultResponse,omitempty"`
-}
+type ClusterResponse = support.ClusterResponse
 
 //Nothing much from this file. This file contains only the structs for network/create
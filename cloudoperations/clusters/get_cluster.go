@@ -1,74 +1,39 @@
 package clusters
 
 import (
-	"fmt"
-	"strings"
+	"context"
 
-	//"github.com/aws/aws-sdk-go/aws/session"
-
-	gcp "github.com/nikhilsbhat/neuron-cloudy/cloud/gcp/operations"
-	common "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/common"
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
 	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
 )
 
 // GetClusters will collect all the required information of the images specified to it and send back the response.
 func (clust *GetClusterInput) GetClusters() (ClusterResponse, error) {
+	return clust.GetClustersWithContext(context.Background())
+}
 
-	if status := support.DoesCloudSupports(strings.ToLower(clust.Cloud.Name)); status != true {
-		return ClusterResponse{}, fmt.Errorf(common.DefaultCloudResponse + "GetCluster")
-	}
-
-	switch strings.ToLower(clust.Cloud.Name) {
-	case "aws":
-		return ClusterResponse{}, fmt.Errorf(common.DefaultAwsResponse)
-	case "azure":
-		return ClusterResponse{}, fmt.Errorf(common.DefaultAzResponse)
-	case "gcp":
-
-		getCluster := new(gcp.GetClusterInput)
-		getCluster.ClusterName = clust.ClusterName
-		getCluster.ProjectID = clust.ProjectID
-		getCluster.Regions = clust.Regions
-		getCluster.GetRaw = clust.Cloud.GetRaw
-		resp, err := getCluster.GetClusters(clust.Cloud.Client)
-		if err != nil {
-			return ClusterResponse{}, err
-		}
-		return ClusterResponse{GCPResponse: resp}, nil
+// GetClustersWithContext is same as GetClusters, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (clust *GetClusterInput) GetClustersWithContext(ctx context.Context) (ClusterResponse, error) {
 
-	case "openstack":
-		return ClusterResponse{}, fmt.Errorf(common.DefaultOpResponse)
-	default:
-		return ClusterResponse{}, fmt.Errorf(common.DefaultCloudResponse + "GetCluster")
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetClusterProvider(clust.Cloud.Name)
+	if err != nil {
+		return ClusterResponse{}, err
 	}
+	clusterin := support.GetClusterInput(*clust)
+	return provider.GetClusters(ctx, &clusterin)
 }
 
 // GetCluster will fetch the details of all the kubernetes cluster in the specified account of region.
 func (clust *GetClusterInput) GetCluster() (ClusterResponse, error) {
+	return clust.GetClustersWithContext(context.Background())
+}
 
-	switch strings.ToLower(clust.Cloud.Name) {
-	case "aws":
-		return ClusterResponse{}, fmt.Errorf(common.DefaultAwsResponse)
-	case "azure":
-		return ClusterResponse{}, fmt.Errorf(common.DefaultAzResponse)
-	case "gcp":
-
-		getCluster := new(gcp.GetClusterInput)
-		getCluster.ClusterName = clust.ClusterName
-		getCluster.ProjectID = clust.ProjectID
-		getCluster.Regions = clust.Regions
-		getCluster.GetRaw = clust.Cloud.GetRaw
-		resp, err := getCluster.GetClusters(clust.Cloud.Client)
-		if err != nil {
-			return ClusterResponse{}, err
-		}
-		return ClusterResponse{GCPResponse: resp}, nil
-
-	case "openstack":
-		return ClusterResponse{}, fmt.Errorf(common.DefaultOpResponse)
-	default:
-		return ClusterResponse{}, fmt.Errorf(common.DefaultCloudResponse + "GetAllImage")
-	}
+// GetClusterWithContext is same as GetCluster, but the calls made to the cloud are bound to the context passed.
+func (clust *GetClusterInput) GetClusterWithContext(ctx context.Context) (ClusterResponse, error) {
+	return clust.GetClustersWithContext(ctx)
 }
 
 // New returns the new instance of GetImagesInput with empty values.
@@ -15,4 +15,8 @@ const (
 	BetaResponse = "%s is in Beta and supports very minimal support."
 	// AlphaResponse helps in constructing response for Alpha resources.
 	AlphaResponse = "%s is in Alpha and supports very minimal support."
+	// CapabilityNotImplemented helps in constructing response when the cloud choosed has not implemented the capability asked for.
+	CapabilityNotImplemented = "capability not implemented: %s is not yet implemented for the cloud %s"
+	// DryRunNotImplemented helps in constructing response when the cloud choosed cannot plan the actions of the operation.
+	DryRunNotImplemented = "dry run not implemented: the cloud %s cannot plan the actions, hence nothing was performed"
 )
@@ -0,0 +1,368 @@
+package drift
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	imageget "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/image/get"
+	getloadbalancer "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/loadbalancer/get"
+	networkget "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/network/get"
+	getservers "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/server/get"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
+)
+
+// loadbalancerTypes are the types of loadbalancers looked up in the networks expected, for the ones which were not expected.
+var loadbalancerTypes = []string{"classic", "application"}
+
+// InSync reports whether the cloud is in sync with the resources expected.
+func (d DriftResponse) InSync() bool {
+	return len(d.Missing) == 0 && len(d.Extra) == 0 && len(d.Changed) == 0
+}
+
+// DetectDrift compares the resources expected with the ones in the cloud, and reports the ones missing, extra and the ones changed.
+func (d *DriftInput) DetectDrift() (DriftResponse, error) {
+	return d.DetectDriftWithContext(context.Background())
+}
+
+// DetectDriftWithContext is same as DetectDrift, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (d *DriftInput) DetectDriftWithContext(ctx context.Context) (DriftResponse, error) {
+
+	if _, err := support.GetProvider(d.Cloud.Name); err != nil {
+		return DriftResponse{}, err
+	}
+
+	resources := d.Resources
+	if resources == nil {
+		if d.Cloud.State == nil || d.Cloud.Stack == "" {
+			return DriftResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "either the resources expected or both State and Stack of the cloud has to be passed to detect the drift")
+		}
+		current, err := d.Cloud.State.Read(ctx, d.Cloud.Stack)
+		if err != nil {
+			return DriftResponse{}, err
+		}
+		resources = current.Resources
+	}
+
+	// the details compared are filled in the common view of the resources only with the raw responses of some of the clouds.
+	cloud := d.Cloud
+	cloud.GetRaw = true
+	cloud.DryRun = false
+
+	response := DriftResponse{}
+	regions, expected := byRegion(resources, d.Cloud.Region)
+	for _, region := range regions {
+		cloud.Region = region
+		detect := detector{cloud: cloud, expected: expected[region], response: &response}
+		if err := detect.run(ctx); err != nil {
+			return response, err
+		}
+	}
+	return response, nil
+}
+
+// detector detects the drift of the resources of a region.
+type detector struct {
+	cloud    cmn.Cloud
+	expected []state.Resource
+	response *DriftResponse
+	// networks are the IDs of the networks expected which exist.
+	networks []string
+}
+
+func (d *detector) run(ctx context.Context) error {
+	if err := d.detectNetworks(ctx); err != nil {
+		return err
+	}
+	if err := d.detectServers(ctx); err != nil {
+		return err
+	}
+	if err := d.detectImages(ctx); err != nil {
+		return err
+	}
+	return d.detectLoadbalancers(ctx)
+}
+
+func (d *detector) detectNetworks(ctx context.Context) error {
+	for _, expected := range d.of(state.KindNetwork, "") {
+		netin := networkget.GetNetworksInput{NetworkID: []string{expected.ID}, Cloud: d.cloud}
+		response, err := netin.GetNetworksWithContext(ctx)
+		if err != nil && !cloudyerror.IsNotFound(err) {
+			return err
+		}
+		if err != nil || len(response.Networks) == 0 {
+			d.missing(expected)
+			continue
+		}
+		network := response.Networks[0]
+		d.networks = append(d.networks, network.ID)
+		d.compare(expected, state.AttributeCidr, join(network.CIDRs))
+
+		live := make(map[string]state.Resource)
+		for _, subnet := range network.Subnets {
+			live[subnet.ID] = found(state.KindSubnet, subnet.Resource, network.ID, state.AttributeCidr, join(subnet.CIDRs))
+		}
+		d.components(d.of(state.KindSubnet, network.ID), live, state.AttributeCidr)
+
+		live = make(map[string]state.Resource)
+		if network.SecurityGroups != nil {
+			for _, group := range network.SecurityGroups {
+				// the default security group is created by the cloud along with the network.
+				if group.Name != "default" {
+					live[group.ID] = found(state.KindSecurityGroup, group.Resource, network.ID, state.AttributePorts, join(group.Ports))
+				}
+			}
+		} else {
+			for _, group := range network.SecurityGroupIDs {
+				live[group] = found(state.KindSecurityGroup, cmn.Resource{ID: group, Region: network.Region}, network.ID, "", "")
+			}
+		}
+		d.components(d.of(state.KindSecurityGroup, network.ID), live, state.AttributePorts)
+
+		live = make(map[string]state.Resource)
+		if igw, ok := network.Extras["igwid"].(string); ok && igw != "" {
+			live[igw] = found(state.KindInternetGateway, cmn.Resource{ID: igw, Region: network.Region}, network.ID, "", "")
+		}
+		d.components(d.of(state.KindInternetGateway, network.ID), live, "")
+	}
+	return nil
+}
+
+func (d *detector) detectServers(ctx context.Context) error {
+	expected := d.of(state.KindServer, "")
+	ids := make([]string, 0, len(expected))
+	for _, server := range expected {
+		ids = append(ids, server.ID)
+	}
+	live, err := d.servers(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, server := range expected {
+		actual, ok := live[server.ID]
+		if !ok || gone(actual.State) {
+			d.missing(server)
+			continue
+		}
+		d.compare(server, state.AttributeFlavor, actual.Flavor)
+	}
+
+	if len(d.networks) == 0 {
+		return nil
+	}
+	serverin := getservers.GetServersInput{VpcIds: d.networks, Cloud: d.cloud}
+	response, err := serverin.GetServersDetailsWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	for _, server := range response.Servers {
+		if _, ok := live[server.ID]; !ok && !gone(server.State) {
+			d.extra(found(state.KindServer, server.Resource, server.NetworkID, state.AttributeFlavor, server.Flavor))
+		}
+	}
+	return nil
+}
+
+// servers fetches the servers passed, the ones which no longer exist are left out.
+func (d *detector) servers(ctx context.Context, ids []string) (map[string]cmn.Server, error) {
+	live := make(map[string]cmn.Server)
+	if len(ids) == 0 {
+		return live, nil
+	}
+	serverin := getservers.GetServersInput{InstanceIds: ids, Cloud: d.cloud}
+	response, err := serverin.GetServersDetailsWithContext(ctx)
+	if cloudyerror.IsNotFound(err) && len(ids) > 1 {
+		// a server which does not exist fails the whole lookup, hence the servers are looked up one after the other.
+		for _, id := range ids {
+			server, err := d.servers(ctx, []string{id})
+			if err != nil {
+				return nil, err
+			}
+			for id, actual := range server {
+				live[id] = actual
+			}
+		}
+		return live, nil
+	}
+	if cloudyerror.IsNotFound(err) {
+		return live, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, server := range response.Servers {
+		live[server.ID] = server
+	}
+	return live, nil
+}
+
+func (d *detector) detectImages(ctx context.Context) error {
+	for _, expected := range d.of(state.KindImage, "") {
+		imgin := imageget.GetImagesInput{ImageIds: []string{expected.ID}, Cloud: d.cloud}
+		response, err := imgin.GetImageWithContext(ctx)
+		if err != nil && !cloudyerror.IsNotFound(err) {
+			return err
+		}
+		if err != nil || len(response.Images) == 0 || gone(response.Images[0].State) {
+			d.missing(expected)
+		}
+	}
+	return nil
+}
+
+func (d *detector) detectLoadbalancers(ctx context.Context) error {
+	expected := d.of(state.KindLoadbalancer, "")
+	if len(expected) == 0 && len(d.networks) == 0 {
+		return nil
+	}
+
+	live := make(map[string]cmn.LoadBalancer)
+	for _, lbType := range loadbalancerTypes {
+		lbin := getloadbalancer.GetLoadbalancerInput{Type: lbType, Cloud: d.cloud}
+		// the loadbalancers are fetched from the region being compared only, no names are passed to fetch all of them.
+		response, err := lbin.GetLoadbalancersWithContext(ctx)
+		if err != nil {
+			if cloudyerror.IsUnsupported(err) && len(expected) == 0 {
+				// the cloud does not support loadbalancers, and none were expected.
+				return nil
+			}
+			return err
+		}
+		for _, lb := range response.LoadBalancers {
+			live[lb.ID] = lb
+		}
+	}
+
+	for _, lb := range expected {
+		actual, ok := live[lb.ID]
+		if !ok || gone(actual.State) {
+			d.missing(lb)
+			continue
+		}
+		d.compare(lb, state.AttributePorts, join(support.ListenerPorts(actual.Listeners)))
+		delete(live, lb.ID)
+	}
+	for _, lb := range sortedLoadbalancers(live) {
+		if contains(d.networks, lb.NetworkID) {
+			extra := found(state.KindLoadbalancer, lb.Resource, lb.NetworkID, state.AttributePorts, join(support.ListenerPorts(lb.Listeners)))
+			extra.Attributes[state.AttributeType] = lb.Type
+			d.extra(extra)
+		}
+	}
+	return nil
+}
+
+// components compares the components expected of a network with the ones found in it, the attribute passed is compared for the ones which exist.
+func (d *detector) components(expected []state.Resource, live map[string]state.Resource, attribute string) {
+	for _, component := range expected {
+		actual, ok := live[component.ID]
+		if !ok {
+			d.missing(component)
+			continue
+		}
+		if attribute != "" {
+			d.compare(component, attribute, actual.Attributes[attribute])
+		}
+		delete(live, component.ID)
+	}
+	ids := make([]string, 0, len(live))
+	for id := range live {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		d.extra(live[id])
+	}
+}
+
+// compare reports the change of the attribute, only if the resource was expected to have the attribute.
+func (d *detector) compare(expected state.Resource, attribute, actual string) {
+	value, ok := expected.Attributes[attribute]
+	if !ok || value == actual {
+		return
+	}
+	d.response.Changed = append(d.response.Changed, Change{Resource: expected, Attribute: attribute, Expected: value, Actual: actual})
+}
+
+func (d *detector) missing(resource state.Resource) {
+	d.response.Missing = append(d.response.Missing, resource)
+}
+
+func (d *detector) extra(resource state.Resource) {
+	d.response.Extra = append(d.response.Extra, resource)
+}
+
+// of returns the resources expected of the kind passed, which are part of the parent passed.
+func (d *detector) of(kind, parent string) []state.Resource {
+	resources := make([]state.Resource, 0)
+	for _, resource := range d.expected {
+		if resource.Kind == kind && resource.Parent == parent {
+			resources = append(resources, resource)
+		}
+	}
+	return resources
+}
+
+// byRegion groups the resources by the region they reside in, the resources without one belong to the region passed.
+func byRegion(resources []state.Resource, fallback string) ([]string, map[string][]state.Resource) {
+	regions := make([]string, 0)
+	grouped := make(map[string][]state.Resource)
+	for _, resource := range resources {
+		region := resource.Region
+		if region == "" {
+			region = fallback
+		}
+		if _, ok := grouped[region]; !ok {
+			regions = append(regions, region)
+		}
+		grouped[region] = append(grouped[region], resource)
+	}
+	return regions, grouped
+}
+
+// found returns the resource found in the cloud in the form of the resources of state, along with the attribute passed.
+func found(kind string, resource cmn.Resource, parent, attribute, value string) state.Resource {
+	recorded := state.Resource{Kind: kind, ID: resource.ID, Name: resource.Name, Cloud: resource.Cloud, Region: resource.Region, Parent: parent, Attributes: make(map[string]string)}
+	if attribute != "" {
+		recorded.Attributes[attribute] = value
+	}
+	return recorded
+}
+
+// gone reports whether the state of the resource says that it no longer exists.
+func gone(state string) bool {
+	switch strings.ToLower(state) {
+	case "terminated", "shutting-down", "deleted", "deleting", "deregistered":
+		return true
+	}
+	return false
+}
+
+// join returns the values passed in the form they are recorded in the attributes of state, the order of the values is ignored.
+func join(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+func sortedLoadbalancers(loadbalancers map[string]cmn.LoadBalancer) []cmn.LoadBalancer {
+	sorted := make([]cmn.LoadBalancer, 0, len(loadbalancers))
+	for _, lb := range loadbalancers {
+		sorted = append(sorted, lb)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
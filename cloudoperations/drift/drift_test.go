@@ -0,0 +1,311 @@
+package drift
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	awsfake "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/fake"
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	networkupdate "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/network/update"
+	securitygroupcreate "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/securitygroup/create"
+	securitygroupdelete "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/securitygroup/delete"
+	securitygroupupdate "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/securitygroup/update"
+	updateservers "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/server/update"
+	"github.com/nikhilsbhat/neuron-cloudy/cloudoperations/stack"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
+)
+
+const spec = `
+name: shop
+cloud:
+  name: aws
+  region: us-east-1
+networks:
+  - name: web
+    vpccidr: 10.0.0.0/16
+    subcidr: [10.0.1.0/24, 10.0.2.0/24]
+    type: public
+    ports: ["22", "80"]
+servers:
+  - name: app
+    count: 2
+    imageid: ami-0123456789
+    network: web
+    flavor: t2.micro
+    assignpubip: true
+images:
+  - name: golden
+    server: app
+loadbalancers:
+  - name: front
+    type: application
+    network: web
+    targets: [app]
+    lbport: 80
+    instport: 8080
+    lbproto: HTTP
+    instproto: HTTP
+`
+
+func applyStack(t *testing.T) (*awsfake.Cloud, *stack.Spec, stack.Outputs, func()) {
+	dir, err := ioutil.TempDir("", "cloudy-drift")
+	if err != nil {
+		t.Fatalf("creating the directory of state: %v", err)
+	}
+	cloud := awsfake.New()
+	parsed, err := stack.Parse([]byte(spec))
+	if err != nil {
+		t.Fatalf("parsing the spec: %v", err)
+	}
+	parsed.Cloud.Client = cloud
+	parsed.Cloud.State = state.NewLocal(dir)
+	parsed.Cloud.Stack = parsed.Name
+	outputs, err := parsed.Apply()
+	if err != nil {
+		t.Fatalf("applying the stack: %v", err)
+	}
+	return cloud, parsed, outputs, func() { os.RemoveAll(dir) }
+}
+
+func TestDetectDriftInSync(t *testing.T) {
+	_, parsed, _, cleanup := applyStack(t)
+	defer cleanup()
+
+	driftin := DriftInput{Cloud: parsed.Cloud}
+	response, err := driftin.DetectDrift()
+	if err != nil {
+		t.Fatalf("detecting the drift: %v", err)
+	}
+	if !response.InSync() {
+		t.Errorf("expected the stack just applied to be in sync, got %+v", response)
+	}
+}
+
+func TestDetectDrift(t *testing.T) {
+	cloud, parsed, outputs, cleanup := applyStack(t)
+	defer cleanup()
+	ctx := context.Background()
+	ec2api := cloud.EC2("us-east-1")
+	network := outputs.Networks["web"]
+
+	// the changes made from the console.
+	terminated := outputs.Servers["app"][1].ID
+	if _, err := ec2api.TerminateInstancesWithContext(ctx, &ec2.TerminateInstancesInput{InstanceIds: []*string{aws.String(terminated)}}); err != nil {
+		t.Fatalf("terminating the server: %v", err)
+	}
+	if _, err := ec2api.AuthorizeSecurityGroupIngressWithContext(ctx, &ec2.AuthorizeSecurityGroupIngressInput{
+		GroupId: aws.String(network.SecurityGroupIDs[0]), IpProtocol: aws.String("tcp"), FromPort: aws.Int64(3306), ToPort: aws.Int64(3306), CidrIp: aws.String("0.0.0.0/0"),
+	}); err != nil {
+		t.Fatalf("opening the port: %v", err)
+	}
+	subnet, err := ec2api.CreateSubnetWithContext(ctx, &ec2.CreateSubnetInput{VpcId: aws.String(network.ID), CidrBlock: aws.String("10.0.3.0/24")})
+	if err != nil {
+		t.Fatalf("creating the subnet: %v", err)
+	}
+	image := outputs.Images["golden"][0].ID
+	if _, err := ec2api.DeregisterImageWithContext(ctx, &ec2.DeregisterImageInput{ImageId: aws.String(image)}); err != nil {
+		t.Fatalf("deregistering the image: %v", err)
+	}
+	lb := outputs.Loadbalancers["front"].ID
+	elbv2api := cloud.ELBV2("us-east-1")
+	groups, err := elbv2api.DescribeTargetGroupsWithContext(ctx, &elbv2.DescribeTargetGroupsInput{LoadBalancerArn: aws.String(lb)})
+	if err != nil || len(groups.TargetGroups) == 0 {
+		t.Fatalf("describing the target group of loadbalancer: %v", err)
+	}
+	if _, err := elbv2api.CreateListenerWithContext(ctx, &elbv2.CreateListenerInput{
+		LoadBalancerArn: aws.String(lb), Port: aws.Int64(8443), Protocol: aws.String("HTTP"),
+		DefaultActions: []*elbv2.Action{{Type: aws.String("forward"), TargetGroupArn: groups.TargetGroups[0].TargetGroupArn}},
+	}); err != nil {
+		t.Fatalf("adding the listener: %v", err)
+	}
+
+	driftin := DriftInput{Cloud: parsed.Cloud}
+	response, err := driftin.DetectDrift()
+	if err != nil {
+		t.Fatalf("detecting the drift: %v", err)
+	}
+
+	missing := make([]string, 0)
+	for _, resource := range response.Missing {
+		missing = append(missing, resource.Kind+" "+resource.ID)
+	}
+	sort.Strings(missing)
+	expected := []string{"image " + image, "server " + terminated}
+	sort.Strings(expected)
+	if len(missing) != 2 || missing[0] != expected[0] || missing[1] != expected[1] {
+		t.Errorf("expected %v to be missing, got %v", expected, missing)
+	}
+
+	if len(response.Extra) != 1 || response.Extra[0].ID != *subnet.Subnet.SubnetId || response.Extra[0].Parent != network.ID ||
+		response.Extra[0].Attributes[state.AttributeCidr] != "10.0.3.0/24" {
+		t.Errorf("expected the subnet created from the console to be extra, got %+v", response.Extra)
+	}
+
+	changed := make(map[string]Change)
+	for _, change := range response.Changed {
+		changed[change.Resource.Kind] = change
+	}
+	if change := changed[state.KindSecurityGroup]; len(response.Changed) != 2 || change.Expected != "22,80" || change.Actual != "22,3306,80" {
+		t.Errorf("expected the ports of the security group to be changed, got %+v", response.Changed)
+	}
+	if change := changed[state.KindLoadbalancer]; change.Expected != "80" || change.Actual != "80,8443" {
+		t.Errorf("expected the ports of the loadbalancer to be changed, got %+v", response.Changed)
+	}
+}
+
+func TestDetectDriftMissingNetwork(t *testing.T) {
+	driftin := DriftInput{
+		Resources: []state.Resource{
+			{Kind: state.KindNetwork, ID: "vpc-00000000"},
+			{Kind: state.KindSubnet, ID: "subnet-00000000", Parent: "vpc-00000000"},
+		},
+	}
+	driftin.Cloud.Name = "aws"
+	driftin.Cloud.Region = "us-east-1"
+	driftin.Cloud.Client = awsfake.New()
+	response, err := driftin.DetectDrift()
+	if err != nil {
+		t.Fatalf("detecting the drift: %v", err)
+	}
+	if len(response.Missing) != 1 || response.Missing[0].ID != "vpc-00000000" || len(response.Extra) != 0 {
+		t.Errorf("expected only the network to be reported missing, got %+v", response)
+	}
+
+	if _, err := (&DriftInput{Cloud: driftin.Cloud}).DetectDrift(); !cloudyerror.IsInvalidInput(err) {
+		t.Errorf("expected the drift without resources and state to fail with InvalidInput, got %v", err)
+	}
+}
+
+func TestDetectDriftNetworkUpdated(t *testing.T) {
+	_, parsed, outputs, cleanup := applyStack(t)
+	defer cleanup()
+	network := outputs.Networks["web"]
+
+	// the updates made through the stack are recorded in its state.
+	update := func(category networkupdate.Catageory) networkupdate.UpdateNetworkResponse {
+		category.VpcId = network.ID
+		netin := networkupdate.NetworkUpdateInput{Catageory: category, Cloud: parsed.Cloud}
+		response, err := netin.UpdateNetwork()
+		if err != nil {
+			t.Fatalf("updating the %s of network: %v", category.Resource, err)
+		}
+		return response
+	}
+	created := update(networkupdate.Catageory{Resource: "subnets", Action: "create", Name: "web", SubCidrs: []string{"10.0.3.0/24", "10.0.4.0/24"}})
+	if len(created.Networks) != 1 || len(created.Networks[0].Subnets) != 2 {
+		t.Fatalf("expected the subnets to be created, got %+v", created.Networks)
+	}
+	update(networkupdate.Catageory{Resource: "subnets", Action: "delete", Subnets: []string{created.Networks[0].Subnets[0].ID}})
+	update(networkupdate.Catageory{Resource: "securitygroup", Action: "add", Ports: []string{"3306"}})
+	update(networkupdate.Catageory{Resource: "securitygroup", Action: "remove", Ports: []string{"22"}})
+
+	driftin := DriftInput{Cloud: parsed.Cloud}
+	response, err := driftin.DetectDrift()
+	if err != nil {
+		t.Fatalf("detecting the drift: %v", err)
+	}
+	if !response.InSync() {
+		t.Errorf("expected the stack updated through it to be in sync, got %+v", response)
+	}
+}
+
+func TestDetectDriftSecurityGroups(t *testing.T) {
+	_, parsed, outputs, cleanup := applyStack(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// the security groups created and updated through the stack are recorded in its state.
+	create := securitygroupcreate.CreateSecurityGroupInput{
+		Name: "db", NetworkID: outputs.Networks["web"].ID, Description: "database",
+		Rules: []cmn.SecurityRule{{Protocol: "tcp", Ports: "5432", CIDRs: []string{"10.0.0.0/16"}}}, Cloud: parsed.Cloud,
+	}
+	created, err := create.CreateSecurityGroup()
+	if err != nil {
+		t.Fatalf("creating the security group: %v", err)
+	}
+	group := created.SecurityGroups[0].ID
+	update := securitygroupupdate.UpdateSecurityGroupInput{
+		SecurityGroupIDs: []string{group}, Action: "add", Rules: []cmn.SecurityRule{{Protocol: "tcp", Ports: "6432", CIDRs: []string{"10.0.0.0/16"}}}, Cloud: parsed.Cloud,
+	}
+	if _, err := update.UpdateSecurityGroup(); err != nil {
+		t.Fatalf("updating the security group: %v", err)
+	}
+	recorded, err := parsed.Cloud.State.Read(ctx, parsed.Name)
+	if err != nil {
+		t.Fatalf("reading the state: %v", err)
+	}
+	found := false
+	for _, resource := range recorded.Of(state.KindSecurityGroup) {
+		if resource.ID == group {
+			found = resource.Parent == outputs.Networks["web"].ID && resource.Attributes[state.AttributePorts] == "5432,6432"
+		}
+	}
+	if !found {
+		t.Fatalf("expected the security group to be recorded with the ports opened, got %+v", recorded.Resources)
+	}
+	driftin := DriftInput{Cloud: parsed.Cloud}
+	response, err := driftin.DetectDrift()
+	if err != nil {
+		t.Fatalf("detecting the drift: %v", err)
+	}
+	if !response.InSync() {
+		t.Errorf("expected the stack to be in sync, got %+v", response)
+	}
+
+	del := securitygroupdelete.DeleteSecurityGroupInput{SecurityGroupIDs: []string{group}, Cloud: parsed.Cloud}
+	if _, err := del.DeleteSecurityGroup(); err != nil {
+		t.Fatalf("deleting the security group: %v", err)
+	}
+	recorded, err = parsed.Cloud.State.Read(ctx, parsed.Name)
+	if err != nil {
+		t.Fatalf("reading the state: %v", err)
+	}
+	for _, resource := range recorded.Of(state.KindSecurityGroup) {
+		if resource.ID == group {
+			t.Errorf("expected the security group deleted to be removed, got %+v", resource)
+		}
+	}
+}
+
+func TestDetectDriftServersUpdated(t *testing.T) {
+	_, parsed, outputs, cleanup := applyStack(t)
+	defer cleanup()
+	ctx := context.Background()
+	server := outputs.Servers["app"][0].ID
+
+	// the servers updated through the stack are recorded afresh in its state.
+	updates := []updateservers.UpdateServersInput{
+		{InstanceIds: []string{server}, Action: "resize", Flavor: "t2.medium", Cloud: parsed.Cloud},
+		{InstanceIds: []string{server}, Action: "securitygroups", SecurityGroupIds: outputs.Networks["web"].SecurityGroupIDs, Cloud: parsed.Cloud},
+	}
+	for _, update := range updates {
+		if _, err := update.UpdateServers(); err != nil {
+			t.Fatalf("updating the %s of server: %v", update.Action, err)
+		}
+	}
+	recorded, err := parsed.Cloud.State.Read(ctx, parsed.Name)
+	if err != nil {
+		t.Fatalf("reading the state: %v", err)
+	}
+	for _, resource := range recorded.Of(state.KindServer) {
+		if resource.ID == server && (resource.Name == "" || resource.Attributes[state.AttributeFlavor] != "t2.medium") {
+			t.Fatalf("expected the server to be recorded with the flavor it was resized to, got %+v", resource)
+		}
+	}
+
+	driftin := DriftInput{Cloud: parsed.Cloud}
+	response, err := driftin.DetectDrift()
+	if err != nil {
+		t.Fatalf("detecting the drift: %v", err)
+	}
+	if !response.InSync() {
+		t.Errorf("expected the stack updated through it to be in sync, got %+v", response)
+	}
+}
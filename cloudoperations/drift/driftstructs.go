@@ -0,0 +1,42 @@
+// Package drift makes the tool cloud agnostic in detecting the drift between the resources expected and the ones which exist in the cloud.
+// The resources expected are the ones recorded in the state of the stack (see package state), the resources created by stack carry the attributes of its spec.
+// The cloud is looked up with the getters of cloudoperations, which means the drift of the changes made out of band (ex: from the console) is reported as well.
+package drift
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
+)
+
+// DriftInput holds the resources expected to exist, against which the cloud is compared.
+type DriftInput struct {
+	// Resources are the resources expected to exist along with the attributes they are expected to have.
+	// optional parameter; The resources recorded in the state of the Stack of the cloud are taken if not passed.
+	Resources []state.Resource `json:"resources"`
+	Cloud     cmn.Cloud
+}
+
+// DriftResponse is the drift between the resources expected and the ones in the cloud, the cloud is in sync with the resources expected if it is empty.
+type DriftResponse struct {
+	// Missing are the resources expected which does not exist in the cloud, the components (ex: subnets) of the networks missing are not reported separately.
+	Missing []state.Resource `json:"missing,omitempty"`
+	// Extra are the resources which exist in the networks expected but were not expected ex: the subnet created from the console.
+	// Parent of the resource is the ID of the network it was found in, and Attributes are the ones it has in the cloud.
+	Extra []state.Resource `json:"extra,omitempty"`
+	// Changed are the attributes of the resources which differ from the ones expected.
+	Changed []Change `json:"changed,omitempty"`
+}
+
+// Change is an attribute of a resource which differs from the one expected.
+type Change struct {
+	// Resource is the resource expected.
+	Resource state.Resource `json:"resource"`
+	// Attribute which has changed ex: flavor, cidr, ports (see the attributes of package state).
+	Attribute string `json:"attribute"`
+	// Expected is the value the attribute was expected to have.
+	Expected string `json:"expected"`
+	// Actual is the value of the attribute in the cloud.
+	Actual string `json:"actual"`
+}
+
+//Nothing much from this file. This file contains only the structs for drift
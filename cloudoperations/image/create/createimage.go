@@ -1,66 +1,45 @@
 package imagecreate
 
 import (
-	"fmt"
-	"strings"
+	"context"
 
-	"github.com/aws/aws-sdk-go/aws/session"
-	auth "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
-	awsimage "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
-	common "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/common"
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
 	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
 )
 
 // CreateImageResponse contains the details of the images captured by CreateImage.
 // This also can contain the response from various cloud, but will deliver what was passed to it.
-type CreateImageResponse struct {
-	// Contains filtered/unfiltered response of AWS.
-	AwsResponse []awsimage.ImageResponse `json:"AwsResponse,omitempty"`
-	// Contains filtered/unfiltered response of Azure.
-	AzureResponse string `json:"AzureResponse,omitempty"`
-	// Default response if no inputs or matching the values required.
-	DefaultResponse string `json:"DefaultResponse,omitempty"`
-}
+type CreateImageResponse = support.CreateImageResponse
 
 // CreateImage will capture image of the server specified, this gives back the response who called.
 func (img *CreateImageInput) CreateImage() (CreateImageResponse, error) {
+	return img.CreateImageWithContext(context.Background())
+}
 
-	if status := support.DoesCloudSupports(strings.ToLower(img.Cloud.Name)); status != true {
-		return CreateImageResponse{}, fmt.Errorf(common.DefaultCloudResponse + "CreateImage")
-	}
-
-	switch strings.ToLower(img.Cloud.Name) {
-	case "aws":
-
-		// gets the established session so that we can carry out the process in cloud.
-		sess := (img.Cloud.Client).(*session.Session)
-
-		// authorizing further request
-		authinpt := auth.EstablishConnectionInput{Region: img.Cloud.Region, Resource: "ec2", Session: sess}
-
-		responseImage := make([]awsimage.ImageResponse, 0)
-
-		for _, id := range img.InstanceIds {
-			imgcreate := new(awsimage.ImageCreateInput)
-			imgcreate.InstanceId = id
-			imgcreate.GetRaw = img.Cloud.GetRaw
-			response, imgerr := imgcreate.CreateImage(authinpt)
-			if imgerr != nil {
-				return CreateImageResponse{}, imgerr
-			}
-			responseImage = append(responseImage, response)
-		}
-		return CreateImageResponse{AwsResponse: responseImage}, nil
+// CreateImageWithContext is same as CreateImage, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (img *CreateImageInput) CreateImageWithContext(ctx context.Context) (CreateImageResponse, error) {
 
-	case "azure":
-		return CreateImageResponse{}, fmt.Errorf(common.DefaultAzResponse)
-	case "gcp":
-		return CreateImageResponse{}, fmt.Errorf(common.DefaultGcpResponse)
-	case "openstack":
-		return CreateImageResponse{}, fmt.Errorf(common.DefaultOpResponse)
-	default:
-		return CreateImageResponse{}, fmt.Errorf(common.DefaultCloudResponse + "CreateImage")
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetImageProvider(img.Cloud.Name)
+	if err != nil {
+		return CreateImageResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, img.Cloud.DryRun); err != nil {
+		return CreateImageResponse{}, err
 	}
+	imagein := support.CreateImageInput(*img)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response CreateImageResponse
+	err = support.Track(ctx, img.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.CreateImage(ctx, &imagein)
+		return support.ImagesCreated(img.Cloud, response.Images), opErr
+	})
+	return response, err
 }
 
 // New returns the new instance of CreateImageInput with empty values.
@@ -1,60 +1,43 @@
 package imagedelete
 
 import (
-	"fmt"
-	"strings"
+	"context"
 
-	"github.com/aws/aws-sdk-go/aws/session"
-	auth "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
-	awsimage "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
-	common "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/common"
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
 	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
 )
 
 // DeleteImageResponse contains the details of the images deleted by DeleteImage.
 // This also can contain the response from various cloud, but will deliver what was passed to it.
-type DeleteImageResponse struct {
-	// Contains filtered/unfiltered response of AWS.
-	AwsResponse []awsimage.ImageResponse `json:"AwsResponse,omitempty"`
-	// Contains filtered/unfiltered response of Azure.
-	AzureResponse string `json:"AzureResponse,omitempty"`
-	// Default response if no inputs or matching the values required.
-	DefaultResponse string `json:"Response,omitempty"`
-}
+type DeleteImageResponse = support.DeleteImageResponse
 
 // DeleteImage deletes the images based on the inputu passed via DeleteImageInput struct.
 func (img *DeleteImageInput) DeleteImage() (DeleteImageResponse, error) {
+	return img.DeleteImageWithContext(context.Background())
+}
 
-	if status := support.DoesCloudSupports(strings.ToLower(img.Cloud.Name)); status != true {
-		return DeleteImageResponse{}, fmt.Errorf(common.DefaultCloudResponse + "DeleteImage")
-	}
-
-	switch strings.ToLower(img.Cloud.Name) {
-	case "aws":
-
-		// gets the established session so that we can carry out the process in cloud.
-		sess := (img.Cloud.Client).(*session.Session)
-
-		// authorizing further request
-		authinpt := auth.EstablishConnectionInput{Region: img.Cloud.Region, Resource: "ec2", Session: sess}
-
-		delimages := new(awsimage.DeleteImageInput)
-		delimages.ImageIds = img.ImageIds
-		result, err := delimages.DeleteImage(authinpt)
-		if err != nil {
-			return DeleteImageResponse{}, err
-		}
-		response := make([]awsimage.ImageResponse, 0)
-		response = append(response, result)
-		return DeleteImageResponse{AwsResponse: response}, nil
+// DeleteImageWithContext is same as DeleteImage, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (img *DeleteImageInput) DeleteImageWithContext(ctx context.Context) (DeleteImageResponse, error) {
 
-	case "azure":
-		return DeleteImageResponse{}, fmt.Errorf(common.DefaultAzResponse)
-	case "gcp":
-		return DeleteImageResponse{}, fmt.Errorf(common.DefaultGcpResponse)
-	case "openstack":
-		return DeleteImageResponse{}, fmt.Errorf(common.DefaultOpResponse)
-	default:
-		return DeleteImageResponse{}, fmt.Errorf(common.DefaultCloudResponse + "GetImage")
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetImageProvider(img.Cloud.Name)
+	if err != nil {
+		return DeleteImageResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, img.Cloud.DryRun); err != nil {
+		return DeleteImageResponse{}, err
 	}
+	imagein := support.DeleteImageInput(*img)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response DeleteImageResponse
+	err = support.Track(ctx, img.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.DeleteImage(ctx, &imagein)
+		return support.ImagesDeleted(response.Images), opErr
+	})
+	return response, err
 }
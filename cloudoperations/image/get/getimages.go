@@ -1,92 +1,52 @@
 package imagesget
 
 import (
-	"fmt"
-	"strings"
+	"context"
 
-	"github.com/aws/aws-sdk-go/aws/session"
-	auth "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
-	awsimage "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
-	common "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/common"
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
 	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
 )
 
 // GetImagesResponse contains the details of the images collected by GetImage.
 // This also can contain the response from various cloud, but will deliver what was passed to it.
-type GetImagesResponse struct {
-	// Contains filtered/unfiltered response of AWS.
-	AwsResponse []awsimage.ImageResponse `json:"AwsResponse,omitempty"`
-	// Contains filtered/unfiltered response of Azure.
-	AzureResponse string `json:"AzureResponse,omitempty"`
-	// Default response if no inputs or matching the values required.
-	DefaultResponse string `json:"DefaultResponse,omitempty"`
-}
+type GetImagesResponse = support.GetImagesResponse
 
 // GetImage will collect all the required information of the images specified to it and send back the response.
 func (img *GetImagesInput) GetImage() (GetImagesResponse, error) {
+	return img.GetImageWithContext(context.Background())
+}
 
-	if status := support.DoesCloudSupports(strings.ToLower(img.Cloud.Name)); status != true {
-		return GetImagesResponse{}, fmt.Errorf(common.DefaultCloudResponse + "GetImages")
-	}
-
-	switch strings.ToLower(img.Cloud.Name) {
-	case "aws":
-
-		// gets the established session so that we can carry out the process in cloud.
-		sess := (img.Cloud.Client).(*session.Session)
-
-		// authorizing further request
-		authinpt := auth.EstablishConnectionInput{Region: img.Cloud.Region, Resource: "ec2", Session: sess}
-
-		getimage := new(awsimage.GetImageInput)
-		getimage.ImageIds = img.ImageIds
-		getimage.GetRaw = img.Cloud.GetRaw
-		result, err := getimage.GetImage(authinpt)
-		if err != nil {
-			return GetImagesResponse{}, err
-		}
-		return GetImagesResponse{AwsResponse: result}, nil
+// GetImageWithContext is same as GetImage, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (img *GetImagesInput) GetImageWithContext(ctx context.Context) (GetImagesResponse, error) {
 
-	case "azure":
-		return GetImagesResponse{}, fmt.Errorf(common.DefaultAzResponse)
-	case "gcp":
-		return GetImagesResponse{}, fmt.Errorf(common.DefaultGcpResponse)
-	case "openstack":
-		return GetImagesResponse{}, fmt.Errorf(common.DefaultOpResponse)
-	default:
-		return GetImagesResponse{}, fmt.Errorf(common.DefaultCloudResponse + "GetImage")
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetImageProvider(img.Cloud.Name)
+	if err != nil {
+		return GetImagesResponse{}, err
 	}
+	imagein := support.GetImagesInput(*img)
+	return provider.GetImages(ctx, &imagein)
 }
 
-// GetAllImage will fetch the details of all the images in the specified acoount ot region.
-func (img *GetImagesInput) GetAllImage() (GetImagesResponse, error) {
-
-	switch strings.ToLower(img.Cloud.Name) {
-	case "aws":
-
-		// gets the established session so that we can carry out the process in cloud.
-		sess := (img.Cloud.Client).(*session.Session)
-
-		// authorizing further request
-		authinpt := auth.EstablishConnectionInput{Region: img.Cloud.Region, Resource: "ec2", Session: sess}
+// GetAllImage will fetch the details of all the images owned across all the regions of the cloud specified.
+// The regions in which the images could not be fetched carry the error in their response (Error), the error is returned only if every region failed.
+func (img *GetImagesInput) GetAllImage() ([]GetImagesResponse, error) {
+	return img.GetAllImageWithContext(context.Background())
+}
 
-		getimages := new(awsimage.GetImageInput)
-		getimages.GetRaw = img.Cloud.GetRaw
-		result, err := getimages.GetAllImage(authinpt)
-		if err != nil {
-			return GetImagesResponse{}, err
-		}
-		return GetImagesResponse{AwsResponse: result}, nil
+// GetAllImageWithContext is same as GetAllImage, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (img *GetImagesInput) GetAllImageWithContext(ctx context.Context) ([]GetImagesResponse, error) {
 
-	case "azure":
-		return GetImagesResponse{}, fmt.Errorf(common.DefaultAzResponse)
-	case "gcp":
-		return GetImagesResponse{}, fmt.Errorf(common.DefaultGcpResponse)
-	case "openstack":
-		return GetImagesResponse{}, fmt.Errorf(common.DefaultOpResponse)
-	default:
-		return GetImagesResponse{}, fmt.Errorf(common.DefaultCloudResponse + "GetAllImage")
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetImageProvider(img.Cloud.Name)
+	if err != nil {
+		return nil, err
 	}
+	imagein := support.GetImagesInput(*img)
+	return provider.GetAllImages(ctx, &imagein)
 }
 
 // New returns the new instance of GetImagesInput with empty values.
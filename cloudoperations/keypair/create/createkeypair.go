@@ -0,0 +1,49 @@
+package keypaircreate
+
+import (
+	"context"
+
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
+)
+
+// KeyPairResponse will return the filtered/unfiltered responses of variuos clouds on the ssh key pairs.
+type KeyPairResponse = support.KeyPairResponse
+
+// CreateKeyPair creates the ssh key pair in the cloud, the private key of the key pair is part of the response.
+// The cloud does not retain the private key, hence it is handed out only once and has to be saved by the caller.
+func (key *CreateKeyPairInput) CreateKeyPair() (KeyPairResponse, error) {
+	return key.CreateKeyPairWithContext(context.Background())
+}
+
+// CreateKeyPairWithContext is same as CreateKeyPair, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (key *CreateKeyPairInput) CreateKeyPairWithContext(ctx context.Context) (KeyPairResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetKeyPairProvider(key.Cloud.Name)
+	if err != nil {
+		return KeyPairResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, key.Cloud.DryRun); err != nil {
+		return KeyPairResponse{}, err
+	}
+	keyin := support.CreateKeyPairInput(*key)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response KeyPairResponse
+	err = support.Track(ctx, key.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.CreateKeyPair(ctx, &keyin)
+		return support.KeyPairsCreated(key.Cloud, response.KeyPairs), opErr
+	})
+	return response, err
+}
+
+// New returns the new CreateKeyPairInput instance with empty values.
+func New() *CreateKeyPairInput {
+	key := &CreateKeyPairInput{}
+	return key
+}
@@ -0,0 +1,16 @@
+// Package keypaircreate makes the tool cloud agnostic with respect to creation of the ssh key pairs.
+// The decision will be made here to route the request to respective package based on input.
+package keypaircreate
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// CreateKeyPairInput implements method CreateKeyPair and holds parameter for creating the key pair.
+type CreateKeyPairInput struct {
+	// Name of the key pair that has to be created, it has to be unique within the region.
+	Name  string `json:"name"`
+	Cloud cmn.Cloud
+}
+
+//Nothing much from this file. This file contains only the structs for keypair/create
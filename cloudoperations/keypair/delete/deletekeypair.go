@@ -0,0 +1,48 @@
+package keypairdelete
+
+import (
+	"context"
+
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
+)
+
+// KeyPairResponse will return the filtered/unfiltered responses of variuos clouds on the ssh key pairs.
+type KeyPairResponse = support.KeyPairResponse
+
+// DeleteKeyPair deletes the key pairs selected, the servers created with them are left as is.
+func (key *DeleteKeyPairInput) DeleteKeyPair() (KeyPairResponse, error) {
+	return key.DeleteKeyPairWithContext(context.Background())
+}
+
+// DeleteKeyPairWithContext is same as DeleteKeyPair, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (key *DeleteKeyPairInput) DeleteKeyPairWithContext(ctx context.Context) (KeyPairResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetKeyPairProvider(key.Cloud.Name)
+	if err != nil {
+		return KeyPairResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, key.Cloud.DryRun); err != nil {
+		return KeyPairResponse{}, err
+	}
+	keyin := support.DeleteKeyPairInput(*key)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response KeyPairResponse
+	err = support.Track(ctx, key.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.DeleteKeyPair(ctx, &keyin)
+		return support.KeyPairsDeleted(response.KeyPairs), opErr
+	})
+	return response, err
+}
+
+// New returns the new DeleteKeyPairInput instance with empty values.
+func New() *DeleteKeyPairInput {
+	key := &DeleteKeyPairInput{}
+	return key
+}
@@ -0,0 +1,16 @@
+// Package keypairdelete makes the tool cloud agnostic with respect to deletion of the ssh key pairs.
+// The decision will be made here to route the request to respective package based on input.
+package keypairdelete
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// DeleteKeyPairInput implements method DeleteKeyPair and holds parameter for deleting the key pairs.
+type DeleteKeyPairInput struct {
+	// Names of the key pairs that has to be deleted.
+	Names []string `json:"names"`
+	Cloud cmn.Cloud
+}
+
+//Nothing much from this file. This file contains only the structs for keypair/delete
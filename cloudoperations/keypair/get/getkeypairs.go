@@ -0,0 +1,36 @@
+package keypairget
+
+import (
+	"context"
+
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+)
+
+// KeyPairResponse will return the filtered/unfiltered responses of variuos clouds on the ssh key pairs.
+type KeyPairResponse = support.KeyPairResponse
+
+// GetKeyPairs fetches the key pairs selected along with their fingerprints, all the key pairs of the region are fetched if none are passed.
+func (key *GetKeyPairsInput) GetKeyPairs() (KeyPairResponse, error) {
+	return key.GetKeyPairsWithContext(context.Background())
+}
+
+// GetKeyPairsWithContext is same as GetKeyPairs, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (key *GetKeyPairsInput) GetKeyPairsWithContext(ctx context.Context) (KeyPairResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetKeyPairProvider(key.Cloud.Name)
+	if err != nil {
+		return KeyPairResponse{}, err
+	}
+	keyin := support.GetKeyPairsInput(*key)
+	return provider.GetKeyPairs(ctx, &keyin)
+}
+
+// New returns the new GetKeyPairsInput instance with empty values.
+func New() *GetKeyPairsInput {
+	key := &GetKeyPairsInput{}
+	return key
+}
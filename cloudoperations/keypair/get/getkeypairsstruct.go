@@ -0,0 +1,16 @@
+// Package keypairget makes the tool cloud agnostic with respect to fetching the ssh key pairs.
+// The decision will be made here to route the request to respective package based on input.
+package keypairget
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// GetKeyPairsInput implements method GetKeyPairs and holds parameter for fetching the key pairs.
+type GetKeyPairsInput struct {
+	// Names of the key pairs that has to be fetched.
+	Names []string `json:"names"`
+	Cloud cmn.Cloud
+}
+
+//Nothing much from this file. This file contains only the structs for keypair/get
@@ -0,0 +1,48 @@
+package keypairimport
+
+import (
+	"context"
+
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
+)
+
+// KeyPairResponse will return the filtered/unfiltered responses of variuos clouds on the ssh key pairs.
+type KeyPairResponse = support.KeyPairResponse
+
+// ImportKeyPair imports the public key of the ssh key pair generated elsewhere, the private key stays with the caller.
+func (key *ImportKeyPairInput) ImportKeyPair() (KeyPairResponse, error) {
+	return key.ImportKeyPairWithContext(context.Background())
+}
+
+// ImportKeyPairWithContext is same as ImportKeyPair, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (key *ImportKeyPairInput) ImportKeyPairWithContext(ctx context.Context) (KeyPairResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetKeyPairProvider(key.Cloud.Name)
+	if err != nil {
+		return KeyPairResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, key.Cloud.DryRun); err != nil {
+		return KeyPairResponse{}, err
+	}
+	keyin := support.ImportKeyPairInput(*key)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response KeyPairResponse
+	err = support.Track(ctx, key.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.ImportKeyPair(ctx, &keyin)
+		return support.KeyPairsCreated(key.Cloud, response.KeyPairs), opErr
+	})
+	return response, err
+}
+
+// New returns the new ImportKeyPairInput instance with empty values.
+func New() *ImportKeyPairInput {
+	key := &ImportKeyPairInput{}
+	return key
+}
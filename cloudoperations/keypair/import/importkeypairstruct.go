@@ -0,0 +1,18 @@
+// Package keypairimport makes the tool cloud agnostic with respect to importing the ssh key pairs generated elsewhere.
+// The decision will be made here to route the request to respective package based on input.
+package keypairimport
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// ImportKeyPairInput implements method ImportKeyPair and holds parameter for importing the key pair.
+type ImportKeyPairInput struct {
+	// Name of the key pair that has to be imported, it has to be unique within the region.
+	Name string `json:"name"`
+	// PublicKey is the RSA public key in the OpenSSH format ex: ssh-rsa AAAA... user@host, the content of ~/.ssh/id_rsa.pub.
+	PublicKey string `json:"publickey"`
+	Cloud     cmn.Cloud
+}
+
+//Nothing much from this file. This file contains only the structs for keypair/import
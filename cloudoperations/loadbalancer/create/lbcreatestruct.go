@@ -25,7 +25,9 @@ type LbCreateInput struct {
 	HttpCode          string   `json:"httpcode"`
 	HealthPath        string   `json:"healthpath"`
 	IpAddressType     string   `json:"ipaddresstype"`
-	Cloud             cmn.Cloud
+	// Targets are the IDs of the servers to be registered as the backends of the loadbalancer, once it is created.
+	Targets []string `json:"targets"`
+	Cloud   cmn.Cloud
 }
 
 //Nothing much from this file. This file contains only the structs for loadbalance/create
@@ -1,71 +1,45 @@
 package deleteloadbalancer
 
 import (
-	"fmt"
-	"strings"
+	"context"
 
-	"github.com/aws/aws-sdk-go/aws/session"
-	auth "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
-	awslb "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
-	common "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/common"
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
 	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
 )
 
 // LoadBalancerDeleteResponse will return the filtered/unfiltered responses of variuos clouds.
-type LoadBalancerDeleteResponse struct {
-	// Contains filtered/unfiltered response of AWS.
-	AwsResponse []awslb.LoadBalanceDeleteResponse `json:"AwsResponse,omitempty"`
-	// Contains filtered/unfiltered response of Azure.
-	AzureResponse string `json:"AzureResponse,omitempty"`
-	// Default response if no inputs or matching the values required.
-	DefaultResponse string `json:"DefaultResponse,omitempty"`
-}
+type LoadBalancerDeleteResponse = support.LoadBalancerDeleteResponse
 
 // DeleteLoadBalancer will help in deleting the loadbalancer created by CreateLoadBalancer
 // Appropriate user and his cloud profile details has to be passed while calling it.
 func (lb *LbDeleteInput) DeleteLoadBalancer() (LoadBalancerDeleteResponse, error) {
+	return lb.DeleteLoadBalancerWithContext(context.Background())
+}
 
-	if status := support.DoesCloudSupports(strings.ToLower(lb.Cloud.Name)); status != true {
-		return LoadBalancerDeleteResponse{}, fmt.Errorf(common.DefaultCloudResponse + "GetNetworks")
-	}
-
-	switch strings.ToLower(lb.Cloud.Name) {
-	case "aws":
-
-		// Gets the established session so that it can carry out the process in cloud
-		sess := (lb.Cloud.Client).(*session.Session)
-
-		//authorizing to request further
-		authinpt := new(auth.EstablishConnectionInput)
-		authinpt.Region = lb.Cloud.Region
-		authinpt.Session = sess
-		switch strings.ToLower(lb.Type) {
-		case "classic":
-			authinpt.Resource = "elb"
-		case "application":
-			authinpt.Resource = "elb2"
-		}
-
-		lbin := new(awslb.DeleteLoadbalancerInput)
-		lbin.LbNames = lb.LbNames
-		lbin.LbArns = lb.LbArns
-		lbin.Type = lb.Type
-		lbin.GetRaw = lb.Cloud.GetRaw
-		response, lberr := lbin.DeleteLoadbalancer(*authinpt)
-		if lberr != nil {
-			return LoadBalancerDeleteResponse{}, lberr
-		}
-		return LoadBalancerDeleteResponse{AwsResponse: response}, nil
+// DeleteLoadBalancerWithContext is same as DeleteLoadBalancer, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (lb *LbDeleteInput) DeleteLoadBalancerWithContext(ctx context.Context) (LoadBalancerDeleteResponse, error) {
 
-	case "azure":
-		return LoadBalancerDeleteResponse{}, fmt.Errorf(common.DefaultAzResponse)
-	case "gcp":
-		return LoadBalancerDeleteResponse{}, fmt.Errorf(common.DefaultGcpResponse)
-	case "openstack":
-		return LoadBalancerDeleteResponse{}, fmt.Errorf(common.DefaultOpResponse)
-	default:
-		return LoadBalancerDeleteResponse{}, fmt.Errorf(common.DefaultCloudResponse + "DeleteLoadBalancer")
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetLoadbalancerProvider(lb.Cloud.Name)
+	if err != nil {
+		return LoadBalancerDeleteResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, lb.Cloud.DryRun); err != nil {
+		return LoadBalancerDeleteResponse{}, err
 	}
+	lbin := support.LbDeleteInput(*lb)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response LoadBalancerDeleteResponse
+	err = support.Track(ctx, lb.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.DeleteLoadBalancer(ctx, &lbin)
+		return support.LoadbalancersDeleted(response.LoadBalancers), opErr
+	})
+	return response, err
 }
 
 // New returns the new instance of LbDeleteInput with the empty default values.
@@ -1,67 +1,45 @@
 package networkcreate
 
 import (
-	"fmt"
+	"context"
 
-	"strings"
-
-	"github.com/aws/aws-sdk-go/aws/session"
-	auth "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
-	awsnetwork "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
-	common "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/common"
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
 	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
 )
 
 // CreateNetworkResponse is a struct that will return the filtered/unfiltered responses of variuos clouds.
-type CreateNetworkResponse struct {
-	// Contains filtered/unfiltered response of AWS.
-	AwsResponse awsnetwork.NetworkResponse `json:"AwsResponse,omitempty"`
-	// Contains filtered/unfiltered response of Azure.
-	AzureResponse string `json:"AzureResponse,omitempty"`
-	// Default response if no inputs or matching the values required.
-	DefaultResponse string `json:"DefaultResponse,omitempty"`
-}
+type CreateNetworkResponse = support.CreateNetworkResponse
 
 // CreateNetwork is responsible for creating network and send back the response to the called source.
 // appropriate user and his cloud profile details which was passed while calling it.
 func (net *NetworkCreateInput) CreateNetwork() (CreateNetworkResponse, error) {
+	return net.CreateNetworkWithContext(context.Background())
+}
 
-	if status := support.DoesCloudSupports(strings.ToLower(net.Cloud.Name)); status != true {
-		return CreateNetworkResponse{}, fmt.Errorf(common.DefaultCloudResponse + "CreateNetwork")
-	}
-
-	switch strings.ToLower(net.Cloud.Name) {
-	case "aws":
-
-		// Gets the establish session so that it can carry out the process in cloud
-		sess := (net.Cloud.Client).(*session.Session)
-
-		//authorizing to request further
-		authinpt := auth.EstablishConnectionInput{Region: net.Cloud.Region, Resource: "ec2", Session: sess}
-
-		// Fetching all the networks across cloud aws
-		networkin := new(awsnetwork.NetworkCreateInput)
-		networkin.Name = net.Name
-		networkin.VpcCidr = net.VpcCidr
-		networkin.SubCidrs = net.SubCidr
-		networkin.Type = net.Type
-		networkin.Ports = net.Ports
-		networkin.GetRaw = net.Cloud.GetRaw
-		response, netErr := networkin.CreateNetwork(authinpt)
-		if netErr != nil {
-			return CreateNetworkResponse{}, netErr
-		}
-		return CreateNetworkResponse{AwsResponse: response}, nil
+// CreateNetworkWithContext is same as CreateNetwork, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (net *NetworkCreateInput) CreateNetworkWithContext(ctx context.Context) (CreateNetworkResponse, error) {
 
-	case "azure":
-		return CreateNetworkResponse{}, fmt.Errorf(common.DefaultAzResponse)
-	case "gcp":
-		return CreateNetworkResponse{}, fmt.Errorf(common.DefaultGcpResponse)
-	case "openstack":
-		return CreateNetworkResponse{}, fmt.Errorf(common.DefaultOpResponse)
-	default:
-		return CreateNetworkResponse{}, fmt.Errorf(common.DefaultCloudResponse + "CreateNetwork")
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetNetworkProvider(net.Cloud.Name)
+	if err != nil {
+		return CreateNetworkResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, net.Cloud.DryRun); err != nil {
+		return CreateNetworkResponse{}, err
 	}
+	networkin := support.CreateNetworkInput(*net)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response CreateNetworkResponse
+	err = support.Track(ctx, net.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.CreateNetwork(ctx, &networkin)
+		return support.NetworksCreated(net.Cloud, response.Networks), opErr
+	})
+	return response, err
 }
 
 // New returns the new NetworkCreateInput instance with empty values
@@ -4,6 +4,7 @@ package networkcreate
 
 import (
 	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
 )
 
 // NetworkCreateInput implements method CreateNetwork and holds parameter for creating network.
@@ -25,7 +26,14 @@ type NetworkCreateInput struct {
 	// if not passed, by default 22 will be made open so that
 	// one can access machines that will be created inside the created network.
 	Ports []string `json:"ports"`
-	Cloud cmn.Cloud
+	// Layout carves the CIDR blocks of the subnets out of VpcCidr when SubCidr is not passed,
+	// so that one can ask for the number of subnets (or public/private subnets per zone) and their prefix length instead.
+	// The blocks passed in SubCidr are validated to lie within VpcCidr without overlapping each other.
+	Layout SubnetLayout `json:"layout"`
+	Cloud  cmn.Cloud
 }
 
+// SubnetLayout lays out the subnets of the network when their CIDR blocks are not passed.
+type SubnetLayout = support.SubnetLayout
+
 //Nothing much from this file. This file contains only the structs for network/create
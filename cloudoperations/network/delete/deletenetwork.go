@@ -1,63 +1,45 @@
 package networkdelete
 
 import (
-	"fmt"
+	"context"
 
-	"strings"
-
-	"github.com/aws/aws-sdk-go/aws/session"
-	auth "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
-	awsnetwork "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
-	common "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/common"
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
 	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
 )
 
 // DeleteNetworkResponse returns the filtered/unfiltered responses of variuos clouds.
-type DeleteNetworkResponse struct {
-	// Contains filtered/unfiltered response of AWS.
-	AwsResponse awsnetwork.DeleteNetworkResponse `json:"AwsResponse,omitempty"`
-	// Contains filtered/unfiltered response of Azure.
-	AzureResponse string `json:"AzureResponse,omitempty"`
-	// Default response if no inputs or matching the values required.
-	DefaultResponse string `json:"DefaultResponse,omitempty"`
-}
+type DeleteNetworkResponse = support.DeleteNetworkResponse
 
 // DeleteNetwork will help in deleting network and its components.
 // Appropriate user and his cloud profile details which was passed while calling it.
 func (net *DeleteNetworkInput) DeleteNetwork() (DeleteNetworkResponse, error) {
+	return net.DeleteNetworkWithContext(context.Background())
+}
 
-	if status := support.DoesCloudSupports(strings.ToLower(net.Cloud.Name)); status != true {
-		return DeleteNetworkResponse{}, fmt.Errorf(common.DefaultCloudResponse + "UpdateNetwork")
-	}
-
-	switch strings.ToLower(net.Cloud.Name) {
-	case "aws":
-
-		// Gets the established session so that we can carry out the process in cloud.
-		sess := (net.Cloud.Client).(*session.Session)
-
-		//authorizing to request further
-		authinpt := auth.EstablishConnectionInput{Region: net.Cloud.Region, Resource: "ec2", Session: sess}
-
-		// deletes network from aws
-		networkin := new(awsnetwork.DeleteNetworkInput)
-		networkin.VpcIds = net.VpcIds
-		networkin.GetRaw = net.Cloud.GetRaw
-		response, netErr := networkin.DeleteNetwork(authinpt)
-		if netErr != nil {
-			return DeleteNetworkResponse{}, netErr
-		}
-		return DeleteNetworkResponse{AwsResponse: response}, nil
+// DeleteNetworkWithContext is same as DeleteNetwork, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (net *DeleteNetworkInput) DeleteNetworkWithContext(ctx context.Context) (DeleteNetworkResponse, error) {
 
-	case "azure":
-		return DeleteNetworkResponse{}, fmt.Errorf(common.DefaultAzResponse)
-	case "gcp":
-		return DeleteNetworkResponse{}, fmt.Errorf(common.DefaultGcpResponse)
-	case "openstack":
-		return DeleteNetworkResponse{}, fmt.Errorf(common.DefaultOpResponse)
-	default:
-		return DeleteNetworkResponse{}, fmt.Errorf(common.DefaultCloudResponse + "DeleteNetwork")
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetNetworkProvider(net.Cloud.Name)
+	if err != nil {
+		return DeleteNetworkResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, net.Cloud.DryRun); err != nil {
+		return DeleteNetworkResponse{}, err
 	}
+	networkin := support.DeleteNetworkInput(*net)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response DeleteNetworkResponse
+	err = support.Track(ctx, net.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.DeleteNetwork(ctx, &networkin)
+		return support.NetworksDeleted(response.Networks, response.Deleted...), opErr
+	})
+	return response, err
 }
 
 // New returns the new instance of DeleteNetworkInput with empty values
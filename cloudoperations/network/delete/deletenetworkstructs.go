@@ -16,7 +16,11 @@ type DeleteNetworkInput struct {
 	IgwIds []string `json:"igwids"`
 	// SecurityIds are the Ids or name of Security Groups which has to be deletd.
 	SecurityIds []string `json:"securityids"`
-	Cloud       cmn.Cloud
+	// Force deletes everything in the network before deleting it ex: servers, loadbalancers, nat gateways, elastic ips.
+	// The resources deleted are logged in Deleted of the response, which is returned along with the error if the deletion fails midway.
+	// optional parameter;
+	Force bool `json:"force"`
+	Cloud cmn.Cloud
 }
 
 //Nothing much from this file. This file contains only the structs for network/create
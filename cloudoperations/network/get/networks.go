@@ -3,9 +3,8 @@
 package networkget
 
 import (
-	awsnetwork "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
-	gcp "github.com/nikhilsbhat/neuron-cloudy/cloud/gcp/operations"
 	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
 )
 
 // GetNetworksInput implements method GetNetworks, GetSubnets.
@@ -20,24 +19,9 @@ type GetNetworksInput struct {
 }
 
 // GetNetworksResponse will return the filtered/unfiltered responses of variuos clouds.
-type GetNetworksResponse struct {
-	// Contains filtered/unfiltered response of AWS.
-	AwsResponse []awsnetwork.NetworkResponse `json:"AwsResponse,omitempty"`
-	// Contains filtered/unfiltered response of Azure.
-	AzureResponse string                `json:"AzureResponse,omitempty"`
-	GCPResponse   []gcp.NetworkResponse `json:"GcpResponse,omitempty"`
-	// Default response if no inputs or matching the values required.
-	DefaultResponse string `json:"DefaultResponse,omitempty"`
-}
-
-//Nothing much from this file. This file contains only the structs for network/get.
+type GetNetworksResponse = support.GetNetworksResponse
 
 // GetSubnetsResponse will return the filtered/unfiltered responses of variuos clouds.
-type GetSubnetsResponse struct {
-	// Contains filtered/unfiltered response of AWS.
-	AwsResponse awsnetwork.NetworkResponse `json:"AwsResponse,omitempty"`
-	// Contains filtered/unfiltered response of Azure.
-	AzureResponse string `json:"AzureResponse,omitempty"`
-	// Default response if no inputs or matching the values required.
-	DefaultResponse string `json:"DefaultResponse,omitempty"`
-}
+type GetSubnetsResponse = support.GetSubnetsResponse
+
+//Nothing much from this file. This file contains only the structs for network/get.
@@ -1,71 +1,45 @@
 package networkupdate
 
 import (
-	"fmt"
-	"strings"
+	"context"
 
-	"github.com/aws/aws-sdk-go/aws/session"
-	auth "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
-	awsnetwork "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
-	common "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/common"
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
 	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
 )
 
 // UpdateNetworkResponse will return the filtered/unfiltered responses of variuos clouds.
-type UpdateNetworkResponse struct {
-	// Contains filtered/unfiltered response of AWS.
-	AwsResponse awsnetwork.NetworkResponse `json:"AwsResponse,omitempty"`
-	// Contains filtered/unfiltered response of Azure.
-	AzureResponse string `json:"AzureResponse,omitempty"`
-	// Default response if no inputs or matching the values required.
-	DefaultResponse string `json:"DefaultResponse,omitempty"`
-}
+type UpdateNetworkResponse = support.UpdateNetworkResponse
 
 // UpdateNetwork will update network and its components
 // appropriate user and his cloud profile details which was passed while calling it.
 func (net *NetworkUpdateInput) UpdateNetwork() (UpdateNetworkResponse, error) {
+	return net.UpdateNetworkWithContext(context.Background())
+}
 
-	if status := support.DoesCloudSupports(strings.ToLower(net.Cloud.Name)); status != true {
-		return UpdateNetworkResponse{}, fmt.Errorf(common.DefaultCloudResponse + "UpdateNetwork")
-	}
-
-	switch strings.ToLower(net.Cloud.Name) {
-	case "aws":
-
-		// Gets the established session so that it can carry out the process in cloud.
-		sess := (net.Cloud.Client).(*session.Session)
-
-		//authorizing to request further
-		authinpt := auth.EstablishConnectionInput{Region: net.Cloud.Region, Resource: "ec2", Session: sess}
-
-		// I will call UpdateNetwork of interface and get the things done
-		serverin := awsnetwork.UpdateNetworkInput{}
-		serverin.Resource = net.Catageory.Resource
-		serverin.Action = net.Catageory.Action
-		serverin.GetRaw = net.Cloud.GetRaw
-		serverin.Network.Name = net.Catageory.Name
-		serverin.Network.VpcCidr = net.Catageory.VpcCidr
-		serverin.Network.VpcId = net.Catageory.VpcId
-		serverin.Network.SubCidrs = net.Catageory.SubCidrs
-		serverin.Network.Type = net.Catageory.Type
-		serverin.Network.Ports = net.Catageory.Ports
-		serverin.Network.Zone = net.Catageory.Zone
-
-		response, err := serverin.UpdateNetwork(authinpt)
-		if err != nil {
-			return UpdateNetworkResponse{}, err
-		}
-		return UpdateNetworkResponse{AwsResponse: response}, nil
+// UpdateNetworkWithContext is same as UpdateNetwork, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (net *NetworkUpdateInput) UpdateNetworkWithContext(ctx context.Context) (UpdateNetworkResponse, error) {
 
-	case "azure":
-		return UpdateNetworkResponse{}, fmt.Errorf(common.DefaultAzResponse)
-	case "gcp":
-		return UpdateNetworkResponse{}, fmt.Errorf(common.DefaultGcpResponse)
-	case "openstack":
-		return UpdateNetworkResponse{}, fmt.Errorf(common.DefaultOpResponse)
-	default:
-		return UpdateNetworkResponse{}, fmt.Errorf(common.DefaultCloudResponse + "NetworkUpdate")
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetNetworkProvider(net.Cloud.Name)
+	if err != nil {
+		return UpdateNetworkResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, net.Cloud.DryRun); err != nil {
+		return UpdateNetworkResponse{}, err
 	}
+	networkin := support.UpdateNetworkInput(*net)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response UpdateNetworkResponse
+	err = support.Track(ctx, net.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.UpdateNetwork(ctx, &networkin)
+		return support.NetworkUpdated(net.Cloud, net.Catageory, response.Networks), opErr
+	})
+	return response, err
 }
 
 // New returns the new NetworkUpdateInput instance with empty values
@@ -0,0 +1,30 @@
+package cloudoperations
+
+// Action is a single call which would be made to the cloud as part of an operation.
+type Action struct {
+	// Operation is the name of the API of cloud which would be called, ex: CreateVpc, RunInstances.
+	Operation string `json:"operation"`
+	// Resource is the kind of the resource acted upon, ex: network, subnet, server.
+	Resource string `json:"resource"`
+	// Target identifies the resource acted upon, this would be the name of the resource if it is yet to be created.
+	Target string `json:"target,omitempty"`
+	// Details holds the values the API would be called with.
+	Details map[string]string `json:"details,omitempty"`
+	// Verified is set if the cloud confirmed that the caller is permitted to perform the action.
+	Verified bool `json:"verified,omitempty"`
+}
+
+// Plan is the ordered list of actions an operation would perform, this is returned in place of
+// performing the operation when DryRun of the Cloud is set.
+type Plan struct {
+	Actions []Action `json:"actions"`
+}
+
+// Operations returns the names of the APIs which would be called, in the order of the plan.
+func (p Plan) Operations() []string {
+	operations := make([]string, 0, len(p.Actions))
+	for _, action := range p.Actions {
+		operations = append(operations, action.Operation)
+	}
+	return operations
+}
@@ -0,0 +1,114 @@
+package awsprovider
+
+import (
+	"context"
+
+	awsops "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// AllocateAddress allocates the elastic ip and associates it with the server or network interface passed, if any.
+func (p Provider) AllocateAddress(ctx context.Context, addr *support.AllocateAddressInput) (support.AddressResponse, error) {
+
+	authinpt := p.connection(ctx, addr.Cloud, "ec2")
+
+	addressin := awsops.AddressInput{}
+	addressin.Name = addr.Name
+	addressin.InstanceId = addr.ServerID
+	addressin.NetworkInterfaceId = addr.NetworkInterfaceID
+	addressin.GetRaw = addr.Cloud.GetRaw
+
+	if addr.Cloud.DryRun {
+		plan, planErr := addressin.PlanAllocateAddress(authinpt)
+		if planErr != nil {
+			return support.AddressResponse{}, cloudyerror.FromAWS(planErr, "address", "")
+		}
+		return support.AddressResponse{Plan: &plan}, nil
+	}
+	response, err := addressin.AllocateAddress(authinpt)
+	if err != nil {
+		return support.AddressResponse{}, cloudyerror.FromAWS(err, "address", "")
+	}
+	return addressResponse(authinpt.Region, response), nil
+}
+
+// GetAddresses fetches the elastic ips selected, the ones associated with the network interface or server, or all the ones in the region
+// are fetched if the elastic ips are not passed.
+func (p Provider) GetAddresses(ctx context.Context, addr *support.GetAddressesInput) (support.AddressResponse, error) {
+
+	authinpt := p.connection(ctx, addr.Cloud, "ec2")
+
+	addressin := awsops.AddressInput{}
+	addressin.AllocationIds = addr.AddressIDs
+	addressin.InstanceId = addr.ServerID
+	addressin.NetworkInterfaceId = addr.NetworkInterfaceID
+	addressin.GetRaw = addr.Cloud.GetRaw
+
+	response, err := addressin.GetAddresses(authinpt)
+	if err != nil {
+		return support.AddressResponse{}, cloudyerror.FromAWS(err, "address", "")
+	}
+	return addressResponse(authinpt.Region, response...), nil
+}
+
+// UpdateAddress associates or disassociates the elastic ip selected as per the action passed.
+func (p Provider) UpdateAddress(ctx context.Context, addr *support.UpdateAddressInput) (support.AddressResponse, error) {
+
+	authinpt := p.connection(ctx, addr.Cloud, "ec2")
+
+	addressin := awsops.AddressInput{}
+	if addr.AddressID != "" {
+		addressin.AllocationIds = []string{addr.AddressID}
+	}
+	addressin.Action = addr.Action
+	addressin.InstanceId = addr.ServerID
+	addressin.NetworkInterfaceId = addr.NetworkInterfaceID
+	addressin.Reassociate = addr.Reassociate
+	addressin.GetRaw = addr.Cloud.GetRaw
+
+	if addr.Cloud.DryRun {
+		plan, planErr := addressin.PlanUpdateAddress(authinpt)
+		if planErr != nil {
+			return support.AddressResponse{}, cloudyerror.FromAWS(planErr, "address", addr.AddressID)
+		}
+		return support.AddressResponse{Plan: &plan}, nil
+	}
+	response, err := addressin.UpdateAddress(authinpt)
+	if err != nil {
+		return support.AddressResponse{}, cloudyerror.FromAWS(err, "address", addr.AddressID)
+	}
+	return addressResponse(authinpt.Region, response), nil
+}
+
+// ReleaseAddress releases the elastic ips selected, the ones released before the failure are returned along with the error.
+func (p Provider) ReleaseAddress(ctx context.Context, addr *support.ReleaseAddressInput) (support.AddressResponse, error) {
+
+	authinpt := p.connection(ctx, addr.Cloud, "ec2")
+
+	addressin := awsops.AddressInput{}
+	addressin.AllocationIds = addr.AddressIDs
+
+	if addr.Cloud.DryRun {
+		plan, planErr := addressin.PlanReleaseAddress(authinpt)
+		if planErr != nil {
+			return support.AddressResponse{}, cloudyerror.FromAWS(planErr, "address", "")
+		}
+		return support.AddressResponse{Plan: &plan}, nil
+	}
+	response, err := addressin.ReleaseAddress(authinpt)
+	released := addressResponse(authinpt.Region, response...)
+	if err != nil {
+		return released, cloudyerror.FromAWS(err, "address", "")
+	}
+	return released, nil
+}
+
+// addressResponse converts the elastic ips of aws to the response.
+func addressResponse(region string, responses ...awsops.AddressResponse) support.AddressResponse {
+	response := support.AddressResponse{AwsResponse: responses}
+	for _, address := range responses {
+		response.Addresses = append(response.Addresses, addressFromResponse(region, address))
+	}
+	return response
+}
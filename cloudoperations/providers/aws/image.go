@@ -0,0 +1,109 @@
+package awsprovider
+
+import (
+	"context"
+
+	auth "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	awsimage "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// CreateImage captures the image of the instances passed in aws.
+func (p Provider) CreateImage(ctx context.Context, img *support.CreateImageInput) (support.CreateImageResponse, error) {
+
+	authinpt := p.connection(ctx, img.Cloud, "ec2")
+
+	if img.Cloud.DryRun {
+		plan := cmn.Plan{}
+		for _, id := range img.InstanceIds {
+			imgcreate := awsimage.ImageCreateInput{InstanceId: id}
+			imgplan, planErr := imgcreate.PlanCreateImage(authinpt)
+			if planErr != nil {
+				return support.CreateImageResponse{}, cloudyerror.FromAWS(planErr, "image", id)
+			}
+			plan.Actions = append(plan.Actions, imgplan.Actions...)
+		}
+		return support.CreateImageResponse{Plan: &plan}, nil
+	}
+
+	responseImage := make([]awsimage.ImageResponse, 0)
+	for _, id := range img.InstanceIds {
+		imgcreate := new(awsimage.ImageCreateInput)
+		imgcreate.InstanceId = id
+		imgcreate.GetRaw = img.Cloud.GetRaw
+		response, imgerr := imgcreate.CreateImage(authinpt)
+		if imgerr != nil {
+			return support.CreateImageResponse{}, cloudyerror.FromAWS(imgerr, "image", "")
+		}
+		responseImage = append(responseImage, response)
+	}
+	return support.CreateImageResponse{Images: images(authinpt.Region, responseImage...), AwsResponse: responseImage}, nil
+}
+
+// DeleteImage deletes the images passed from aws.
+func (p Provider) DeleteImage(ctx context.Context, img *support.DeleteImageInput) (support.DeleteImageResponse, error) {
+
+	authinpt := p.connection(ctx, img.Cloud, "ec2")
+
+	delimages := new(awsimage.DeleteImageInput)
+	delimages.ImageIds = img.ImageIds
+	if img.Cloud.DryRun {
+		plan, planErr := delimages.PlanDeleteImage(authinpt)
+		if planErr != nil {
+			return support.DeleteImageResponse{}, cloudyerror.FromAWS(planErr, "image", "")
+		}
+		return support.DeleteImageResponse{Plan: &plan}, nil
+	}
+	result, err := delimages.DeleteImage(authinpt)
+	if err != nil {
+		return support.DeleteImageResponse{}, cloudyerror.FromAWS(err, "image", "")
+	}
+	response := make([]awsimage.ImageResponse, 0)
+	response = append(response, result)
+	return support.DeleteImageResponse{Images: deletedImages(authinpt.Region, img.ImageIds), AwsResponse: response}, nil
+}
+
+// GetImages fetches the details of the images passed from aws.
+func (p Provider) GetImages(ctx context.Context, img *support.GetImagesInput) (support.GetImagesResponse, error) {
+
+	authinpt := p.connection(ctx, img.Cloud, "ec2")
+
+	getimage := new(awsimage.GetImageInput)
+	getimage.ImageIds = img.ImageIds
+	getimage.GetRaw = img.Cloud.GetRaw
+	result, err := getimage.GetImage(authinpt)
+	if err != nil {
+		return support.GetImagesResponse{}, cloudyerror.FromAWS(err, "image", "")
+	}
+	return support.GetImagesResponse{Images: images(authinpt.Region, result...), AwsResponse: result}, nil
+}
+
+// GetAllImages fetches the details of all the images owned across all the regions of aws, the regions without images are left out.
+// The regions are queried concurrently, the regions which fail (ex: not opted in) carry their error in place of failing the rest.
+func (p Provider) GetAllImages(ctx context.Context, img *support.GetImagesInput) ([]support.GetImagesResponse, error) {
+
+	regions, regerr := p.enabledRegions(ctx, img.Cloud, "image")
+	if regerr != nil {
+		return nil, regerr
+	}
+
+	regionResponses := make([][]awsimage.ImageResponse, len(regions))
+	errs := p.eachRegion(ctx, img.Cloud, regions, "image", func(index int, authinpt auth.EstablishConnectionInput) error {
+		getimages := awsimage.GetImageInput{GetRaw: img.Cloud.GetRaw}
+		result, err := getimages.GetAllImage(authinpt)
+		regionResponses[index] = result
+		return err
+	})
+
+	imageResponse := make([]support.GetImagesResponse, 0)
+	for index, region := range regions {
+		regionErr := regionError(errs, region)
+		regionImages := images(region, regionResponses[index]...)
+		if len(regionImages) != 0 || regionErr != nil {
+			imageResponse = append(imageResponse, support.GetImagesResponse{Region: region, Images: regionImages, AwsResponse: regionResponses[index], Error: regionErr})
+		}
+	}
+	return imageResponse, allFailed(regions, errs)
+}
@@ -0,0 +1,111 @@
+package awsprovider
+
+import (
+	"context"
+
+	awsops "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// CreateKeyPair creates the key pair, the private key of which is part of the response as aws does not retain it.
+func (p Provider) CreateKeyPair(ctx context.Context, key *support.CreateKeyPairInput) (support.KeyPairResponse, error) {
+
+	authinpt := p.connection(ctx, key.Cloud, "ec2")
+
+	keyin := awsops.KeyPairInput{}
+	if key.Name != "" {
+		keyin.KeyNames = []string{key.Name}
+	}
+	keyin.GetRaw = key.Cloud.GetRaw
+
+	if key.Cloud.DryRun {
+		plan, planErr := keyin.PlanCreateKeyPair(authinpt)
+		if planErr != nil {
+			return support.KeyPairResponse{}, cloudyerror.FromAWS(planErr, "keypair", key.Name)
+		}
+		return support.KeyPairResponse{Plan: &plan}, nil
+	}
+	response, err := keyin.CreateKeyPair(authinpt)
+	if err != nil {
+		return support.KeyPairResponse{}, cloudyerror.FromAWS(err, "keypair", key.Name)
+	}
+	return keyPairResponse(authinpt.Region, response), nil
+}
+
+// ImportKeyPair imports the public key passed as the key pair.
+func (p Provider) ImportKeyPair(ctx context.Context, key *support.ImportKeyPairInput) (support.KeyPairResponse, error) {
+
+	authinpt := p.connection(ctx, key.Cloud, "ec2")
+
+	keyin := awsops.KeyPairInput{}
+	if key.Name != "" {
+		keyin.KeyNames = []string{key.Name}
+	}
+	keyin.PublicKey = key.PublicKey
+	keyin.GetRaw = key.Cloud.GetRaw
+
+	if key.Cloud.DryRun {
+		plan, planErr := keyin.PlanImportKeyPair(authinpt)
+		if planErr != nil {
+			return support.KeyPairResponse{}, cloudyerror.FromAWS(planErr, "keypair", key.Name)
+		}
+		return support.KeyPairResponse{Plan: &plan}, nil
+	}
+	response, err := keyin.ImportKeyPair(authinpt)
+	if err != nil {
+		return support.KeyPairResponse{}, cloudyerror.FromAWS(err, "keypair", key.Name)
+	}
+	return keyPairResponse(authinpt.Region, response), nil
+}
+
+// GetKeyPairs fetches the key pairs selected along with their fingerprints, all the key pairs of the region are fetched if none are passed.
+func (p Provider) GetKeyPairs(ctx context.Context, key *support.GetKeyPairsInput) (support.KeyPairResponse, error) {
+
+	authinpt := p.connection(ctx, key.Cloud, "ec2")
+
+	keyin := awsops.KeyPairInput{}
+	keyin.KeyNames = key.Names
+	keyin.GetRaw = key.Cloud.GetRaw
+
+	response, err := keyin.GetKeyPairs(authinpt)
+	if err != nil {
+		return support.KeyPairResponse{}, cloudyerror.FromAWS(err, "keypair", "")
+	}
+	return keyPairResponse(authinpt.Region, response...), nil
+}
+
+// DeleteKeyPair deletes the key pairs selected, the ones deleted before the failure are returned along with the error.
+func (p Provider) DeleteKeyPair(ctx context.Context, key *support.DeleteKeyPairInput) (support.KeyPairResponse, error) {
+
+	authinpt := p.connection(ctx, key.Cloud, "ec2")
+
+	keyin := awsops.KeyPairInput{}
+	keyin.KeyNames = key.Names
+
+	if key.Cloud.DryRun {
+		plan, planErr := keyin.PlanDeleteKeyPair(authinpt)
+		if planErr != nil {
+			return support.KeyPairResponse{}, cloudyerror.FromAWS(planErr, "keypair", "")
+		}
+		return support.KeyPairResponse{Plan: &plan}, nil
+	}
+	response, err := keyin.DeleteKeyPair(authinpt)
+	deleted := keyPairResponse(authinpt.Region, response...)
+	for i := range deleted.KeyPairs {
+		deleted.KeyPairs[i].State = deletedState
+	}
+	if err != nil {
+		return deleted, cloudyerror.FromAWS(err, "keypair", "")
+	}
+	return deleted, nil
+}
+
+// keyPairResponse converts the key pairs of aws to the response.
+func keyPairResponse(region string, responses ...awsops.KeyPairResponse) support.KeyPairResponse {
+	response := support.KeyPairResponse{AwsResponse: responses}
+	for _, pair := range responses {
+		response.KeyPairs = append(response.KeyPairs, keyPairFromResponse(region, pair))
+	}
+	return response
+}
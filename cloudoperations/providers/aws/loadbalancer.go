@@ -0,0 +1,148 @@
+package awsprovider
+
+import (
+	"context"
+	"strings"
+
+	auth "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	awslb "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// CreateLoadBalancer creates the loadbalancer of the type passed in aws.
+func (p Provider) CreateLoadBalancer(ctx context.Context, lb *support.LbCreateInput) (support.LoadBalanceResponse, error) {
+
+	authinpt := p.connection(ctx, lb.Cloud, lbResource(lb.Type))
+
+	lbin := new(awslb.LoadBalanceCreateInput)
+	lbin.GetRaw = lb.Cloud.GetRaw
+	lbin.Name = lb.Name
+	lbin.VpcId = lb.VpcId
+	lbin.SubnetIds = lb.SubnetIds
+	lbin.AvailabilityZones = lb.AvailabilityZones
+	lbin.SecurityGroupIds = lb.SecurityGroupIds
+	lbin.Scheme = lb.Scheme
+	lbin.Type = lb.Type
+	lbin.LbPort = lb.LbPort
+	lbin.InstPort = lb.InstPort
+	lbin.Lbproto = lb.Lbproto
+	lbin.Instproto = lb.Instproto
+	lbin.HttpCode = lb.HttpCode
+	lbin.HealthPath = lb.HealthPath
+	lbin.SslCert = lb.SslCert
+	lbin.SslPolicy = lb.SslPolicy
+	lbin.IpAddressType = lb.IpAddressType
+	lbin.Targets = lb.Targets
+	if lb.Cloud.DryRun {
+		plan, planErr := lbin.PlanCreateLoadBalancer(authinpt)
+		if planErr != nil {
+			return support.LoadBalanceResponse{}, cloudyerror.FromAWS(planErr, "loadbalancer", "")
+		}
+		return support.LoadBalanceResponse{Plan: &plan}, nil
+	}
+	response, lberr := lbin.CreateLoadBalancer(authinpt)
+	if lberr != nil {
+		return support.LoadBalanceResponse{}, cloudyerror.FromAWS(lberr, "loadbalancer", "")
+	}
+	return support.LoadBalanceResponse{LoadBalancers: loadbalancers(authinpt.Region, response), AwsResponse: response}, nil
+}
+
+// DeleteLoadBalancer deletes the loadbalancers passed from aws.
+func (p Provider) DeleteLoadBalancer(ctx context.Context, lb *support.LbDeleteInput) (support.LoadBalancerDeleteResponse, error) {
+
+	authinpt := p.connection(ctx, lb.Cloud, lbResource(lb.Type))
+
+	lbin := new(awslb.DeleteLoadbalancerInput)
+	lbin.LbNames = lb.LbNames
+	lbin.LbArns = lb.LbArns
+	lbin.Type = lb.Type
+	lbin.GetRaw = lb.Cloud.GetRaw
+	if lb.Cloud.DryRun {
+		plan, planErr := lbin.PlanDeleteLoadbalancer(authinpt)
+		if planErr != nil {
+			return support.LoadBalancerDeleteResponse{}, cloudyerror.FromAWS(planErr, "loadbalancer", "")
+		}
+		return support.LoadBalancerDeleteResponse{Plan: &plan}, nil
+	}
+	response, lberr := lbin.DeleteLoadbalancer(authinpt)
+	if lberr != nil {
+		return support.LoadBalancerDeleteResponse{}, cloudyerror.FromAWS(lberr, "loadbalancer", "")
+	}
+	return support.LoadBalancerDeleteResponse{LoadBalancers: deletedLoadbalancers(authinpt.Region, lb.Type, response...), AwsResponse: response}, nil
+}
+
+// GetLoadbalancers fetches the details of the loadbalancers passed from aws.
+// All the loadbalancers of the type passed in the region are fetched when neither names nor arns are passed.
+func (p Provider) GetLoadbalancers(ctx context.Context, lb *support.GetLoadbalancerInput) (support.GetLoadbalancerResponse, error) {
+
+	authinpt := p.connection(ctx, lb.Cloud, lbResource(lb.Type))
+
+	lbin := new(awslb.GetLoadbalancerInput)
+	lbin.GetRaw = lb.Cloud.GetRaw
+	lbin.LbNames = lb.LbNames
+	lbin.LbArns = lb.LbArns
+	lbin.Type = lb.Type
+	var response []awslb.LoadBalanceResponse
+	var lberr error
+	switch {
+	case len(lb.LbNames) != 0 || len(lb.LbArns) != 0:
+		response, lberr = lbin.Getloadbalancers(authinpt)
+	case strings.ToLower(lb.Type) == "classic":
+		response, lberr = lbin.GetAllClassicLb(authinpt)
+	case strings.ToLower(lb.Type) == "application":
+		response, lberr = lbin.GetAllApplicationLb(authinpt)
+	default:
+		return support.GetLoadbalancerResponse{}, cloudyerror.Newf(cloudyerror.InvalidInput, "The loadbalancer type %s you entered is unknown to me, the known ones are: classic/application", lb.Type)
+	}
+	if lberr != nil {
+		return support.GetLoadbalancerResponse{}, cloudyerror.FromAWS(lberr, "loadbalancer", "")
+	}
+	return support.GetLoadbalancerResponse{LoadBalancers: loadbalancers(authinpt.Region, response...), AwsResponse: response}, nil
+}
+
+// GetAllLoadbalancers fetches the details of all the loadbalancers of the type passed across all the regions of aws, the regions without loadbalancers are left out.
+// The regions are queried concurrently, the regions which fail (ex: not opted in) carry their error in place of failing the rest.
+func (p Provider) GetAllLoadbalancers(ctx context.Context, lb *support.GetLoadbalancerInput) ([]support.GetLoadbalancerResponse, error) {
+
+	lbType := strings.ToLower(lb.Type)
+	switch lbType {
+	case "classic", "application", "":
+	default:
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The loadbalancer type %s you entered is unknown to me, the known ones are: classic/application", lb.Type)
+	}
+
+	regions, regerr := p.enabledRegions(ctx, lb.Cloud, "loadbalancer")
+	if regerr != nil {
+		return nil, regerr
+	}
+
+	regionResponses := make([][]awslb.LoadBalanceResponse, len(regions))
+	errs := p.eachRegion(ctx, lb.Cloud, regions, "loadbalancer", func(index int, authinpt auth.EstablishConnectionInput) error {
+		// both the classic and the application loadbalancers are looked up with the same connection.
+		authinpt.Resource = "elb12"
+		lbin := awslb.GetLoadbalancerInput{GetRaw: lb.Cloud.GetRaw}
+		var response []awslb.LoadBalanceResponse
+		var lberr error
+		switch lbType {
+		case "classic":
+			response, lberr = lbin.GetAllClassicLb(authinpt)
+		case "application":
+			response, lberr = lbin.GetAllApplicationLb(authinpt)
+		default:
+			response, lberr = lbin.GetAllLoadbalancer(authinpt)
+		}
+		regionResponses[index] = response
+		return lberr
+	})
+
+	lbResponse := make([]support.GetLoadbalancerResponse, 0)
+	for index, region := range regions {
+		regionErr := regionError(errs, region)
+		regionLbs := loadbalancers(region, regionResponses[index]...)
+		if len(regionLbs) != 0 || regionErr != nil {
+			lbResponse = append(lbResponse, support.GetLoadbalancerResponse{Region: region, LoadBalancers: regionLbs, AwsResponse: regionResponses[index], Error: regionErr})
+		}
+	}
+	return lbResponse, allFailed(regions, errs)
+}
@@ -0,0 +1,162 @@
+package awsprovider
+
+import (
+	"context"
+
+	auth "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	awsnetwork "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// CreateNetwork creates the network and its components in aws.
+func (p Provider) CreateNetwork(ctx context.Context, net *support.CreateNetworkInput) (support.CreateNetworkResponse, error) {
+
+	authinpt := p.connection(ctx, net.Cloud, "ec2")
+
+	networkin := new(awsnetwork.NetworkCreateInput)
+	networkin.Name = net.Name
+	networkin.VpcCidr = net.VpcCidr
+	networkin.SubCidrs = net.SubCidr
+	networkin.Type = net.Type
+	networkin.Ports = net.Ports
+	networkin.Layout = awsnetwork.SubnetLayout(net.Layout)
+	networkin.GetRaw = net.Cloud.GetRaw
+	if net.Cloud.DryRun {
+		plan, planErr := networkin.PlanCreateNetwork(authinpt)
+		if planErr != nil {
+			return support.CreateNetworkResponse{}, cloudyerror.FromAWS(planErr, "network", "")
+		}
+		return support.CreateNetworkResponse{Plan: &plan}, nil
+	}
+	response, netErr := networkin.CreateNetwork(authinpt)
+	if netErr != nil {
+		// the response carries the details of the rollback of the components created before the failure.
+		return support.CreateNetworkResponse{AwsResponse: response}, cloudyerror.FromAWS(netErr, "network", "")
+	}
+	return support.CreateNetworkResponse{Networks: networks(authinpt.Region, response), AwsResponse: response}, nil
+}
+
+// DeleteNetwork deletes the network and its components from aws.
+func (p Provider) DeleteNetwork(ctx context.Context, net *support.DeleteNetworkInput) (support.DeleteNetworkResponse, error) {
+
+	authinpt := p.connection(ctx, net.Cloud, "ec2")
+
+	networkin := new(awsnetwork.DeleteNetworkInput)
+	networkin.VpcIds = net.VpcIds
+	networkin.Force = net.Force
+	networkin.GetRaw = net.Cloud.GetRaw
+	if net.Cloud.DryRun {
+		plan, planErr := networkin.PlanDeleteNetwork(authinpt)
+		if planErr != nil {
+			return support.DeleteNetworkResponse{}, cloudyerror.FromAWS(planErr, "network", "")
+		}
+		return support.DeleteNetworkResponse{Plan: &plan}, nil
+	}
+	response, netErr := networkin.DeleteNetwork(authinpt)
+	if netErr != nil {
+		return support.DeleteNetworkResponse{Deleted: deletedResources(response.Deleted), AwsResponse: response}, cloudyerror.FromAWS(netErr, "network", "")
+	}
+	return support.DeleteNetworkResponse{Networks: deletedNetworks(authinpt.Region, net.VpcIds), Deleted: deletedResources(response.Deleted), AwsResponse: response}, nil
+}
+
+// GetNetworks fetches the details of the networks passed from aws.
+func (p Provider) GetNetworks(ctx context.Context, net *support.GetNetworksInput) (support.GetNetworksResponse, error) {
+
+	authinpt := p.connection(ctx, net.Cloud, "ec2")
+
+	networkin := awsnetwork.GetNetworksInput{}
+	networkin.VpcIds = net.NetworkID
+	networkin.GetRaw = net.Cloud.GetRaw
+	response, netErr := networkin.GetNetwork(authinpt)
+	if netErr != nil {
+		return support.GetNetworksResponse{}, cloudyerror.FromAWS(netErr, "network", "")
+	}
+	return support.GetNetworksResponse{Networks: networks(authinpt.Region, response...), AwsResponse: response}, nil
+}
+
+// GetAllNetworks fetches the details of all the networks across all regions of aws, a response is returned for every region.
+// The regions are queried concurrently, the regions which fail (ex: not opted in) carry their error in place of failing the rest.
+func (p Provider) GetAllNetworks(ctx context.Context, net *support.GetNetworksInput) ([]support.GetNetworksResponse, error) {
+
+	regions, regerr := p.enabledRegions(ctx, net.Cloud, "network")
+	if regerr != nil {
+		return nil, regerr
+	}
+
+	responses := make([]support.GetNetworksResponse, len(regions))
+	errs := p.eachRegion(ctx, net.Cloud, regions, "network", func(index int, authinpt auth.EstablishConnectionInput) error {
+		networkin := awsnetwork.GetNetworksInput{GetRaw: net.Cloud.GetRaw}
+		response, netErr := networkin.GetAllNetworks(authinpt)
+		if netErr != nil {
+			return netErr
+		}
+		responses[index] = support.GetNetworksResponse{Networks: networks(authinpt.Region, response...), AwsResponse: response}
+		return nil
+	})
+
+	for index, region := range regions {
+		responses[index].Region = region
+		responses[index].Error = regionError(errs, region)
+	}
+	return responses, allFailed(regions, errs)
+}
+
+// GetSubnets fetches the details of the subnets passed, or the subnets of the networks passed from aws.
+func (p Provider) GetSubnets(ctx context.Context, sub *support.GetNetworksInput) (support.GetSubnetsResponse, error) {
+
+	authInpt := p.connection(ctx, sub.Cloud, "ec2")
+
+	networkin := new(awsnetwork.GetNetworksInput)
+	networkin.GetRaw = sub.Cloud.GetRaw
+	if sub.SubnetIds != nil {
+		networkin.SubnetIds = sub.SubnetIds
+		response, getSubErr := networkin.GetSubnets(authInpt)
+		if getSubErr != nil {
+			return support.GetSubnetsResponse{}, cloudyerror.FromAWS(getSubErr, "network", "")
+		}
+		return support.GetSubnetsResponse{Subnets: subnetsFromNetwork(authInpt.Region, response), AwsResponse: response}, nil
+	} else if sub.NetworkID != nil {
+		networkin.VpcIds = sub.NetworkID
+		response, getSubErr := networkin.GetSubnetsFromVpc(authInpt)
+		if getSubErr != nil {
+			return support.GetSubnetsResponse{}, cloudyerror.FromAWS(getSubErr, "network", "")
+		}
+		return support.GetSubnetsResponse{Subnets: subnetsFromNetwork(authInpt.Region, response), AwsResponse: response}, nil
+	}
+	return support.GetSubnetsResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed valid input to get details of server, the input struct looks like empty")
+}
+
+// UpdateNetwork updates the network and its components in aws.
+func (p Provider) UpdateNetwork(ctx context.Context, net *support.UpdateNetworkInput) (support.UpdateNetworkResponse, error) {
+
+	authinpt := p.connection(ctx, net.Cloud, "ec2")
+
+	serverin := awsnetwork.UpdateNetworkInput{}
+	serverin.Resource = net.Catageory.Resource
+	serverin.Action = net.Catageory.Action
+	serverin.GetRaw = net.Cloud.GetRaw
+	serverin.Network.Name = net.Catageory.Name
+	serverin.Network.VpcCidr = net.Catageory.VpcCidr
+	serverin.Network.VpcId = net.Catageory.VpcId
+	serverin.Network.SubCidrs = net.Catageory.SubCidrs
+	serverin.Network.Layout = awsnetwork.SubnetLayout(net.Catageory.Layout)
+	serverin.Network.Type = net.Catageory.Type
+	serverin.Network.Ports = net.Catageory.Ports
+	serverin.Network.Zone = net.Catageory.Zone
+	serverin.Network.IgwId = net.Catageory.IgwId
+	serverin.Subnets = net.Catageory.Subnets
+
+	if net.Cloud.DryRun {
+		plan, planErr := serverin.PlanUpdateNetwork(authinpt)
+		if planErr != nil {
+			return support.UpdateNetworkResponse{}, cloudyerror.FromAWS(planErr, "network", "")
+		}
+		return support.UpdateNetworkResponse{Plan: &plan}, nil
+	}
+	response, err := serverin.UpdateNetwork(authinpt)
+	if err != nil {
+		return support.UpdateNetworkResponse{}, cloudyerror.FromAWS(err, "network", "")
+	}
+	return support.UpdateNetworkResponse{Networks: networks(authinpt.Region, response), AwsResponse: response}, nil
+}
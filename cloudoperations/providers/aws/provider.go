@@ -0,0 +1,56 @@
+// Package awsprovider is the built-in provider of neuron-cloudy for the cloud aws.
+// It registers itself with the support package and routes the requests of cloudoperations to cloud/aws/operations.
+package awsprovider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	auth "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+)
+
+// Provider implements the capabilities network, server, image, loadbalancer, region, securitygroup, volume, address, keypair and spotprice for aws, and plans the operations when asked for DryRun.
+type Provider struct{}
+
+func init() {
+	support.Register(Provider{})
+}
+
+// Name returns the name with which the provider is registered.
+func (p Provider) Name() string {
+	return "aws"
+}
+
+// Plans reports that the provider honors DryRun, the plans are verified with aws wherever possible.
+func (p Provider) Plans() bool {
+	return true
+}
+
+// connection authorizes the further requests to the resource passed, with the session held by the cloud.
+// The requests are retried as per the retry policy of the cloud, and the resources are waited on with its poller.
+// The client held by the cloud can either be the session of aws or an implementation of auth.Clients.
+func (p Provider) connection(ctx context.Context, cloud cmn.Cloud, resource string) auth.EstablishConnectionInput {
+	conn := auth.EstablishConnectionInput{Region: cloud.Region, Resource: resource, Context: ctx, Retry: cloud.Retry, Wait: cloud.Wait}
+	// Gets the established session so that it can carry out the process in cloud.
+	switch client := (cloud.Client).(type) {
+	case *session.Session:
+		conn.Session = client
+	case auth.Clients:
+		conn.Clients = client
+	}
+	return conn
+}
+
+// lbResource returns the resource to be authorized for the type of loadbalancer passed.
+func lbResource(lbType string) string {
+	switch strings.ToLower(lbType) {
+	case "classic":
+		return "elb"
+	case "application":
+		return "elb2"
+	}
+	return ""
+}
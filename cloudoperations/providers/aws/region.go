@@ -0,0 +1,70 @@
+package awsprovider
+
+import (
+	"context"
+
+	auth "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	awscommon "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+	"github.com/nikhilsbhat/neuron-cloudy/fanout"
+)
+
+// GetRegions fetches the list of regions available in aws.
+func (p Provider) GetRegions(ctx context.Context, reg *support.GetRegionInput) (support.GetRegionsResponse, error) {
+
+	authinpt := p.connection(ctx, reg.Cloud, "ec2")
+
+	regionin := awscommon.CommonInput{}
+	regionin.GetRaw = reg.Cloud.GetRaw
+	response, regErr := regionin.GetRegions(authinpt)
+	if regErr != nil {
+		return support.GetRegionsResponse{}, cloudyerror.FromAWS(regErr, "region", "")
+	}
+	return support.GetRegionsResponse{AwsResponse: response}, nil
+}
+
+// enabledRegions fetches the regions of aws enabled for the account, for the operations spanning all the regions.
+func (p Provider) enabledRegions(ctx context.Context, cloud cmn.Cloud, kind string) ([]string, error) {
+
+	authinpt := p.connection(ctx, cloud, "ec2")
+
+	regionin := awscommon.CommonInput{}
+	regions, regErr := regionin.GetRegions(authinpt)
+	if regErr != nil {
+		return nil, cloudyerror.FromAWS(regErr, kind, "")
+	}
+	return regions.Regions, nil
+}
+
+// eachRegion calls fn with the connection of every region passed, querying Concurrency regions of the cloud at once.
+// fn is passed the index of the region so that it can place its result, the errors of the regions which failed are returned.
+func (p Provider) eachRegion(ctx context.Context, cloud cmn.Cloud, regions []string, kind string, fn func(index int, authinpt auth.EstablishConnectionInput) error) fanout.Errors {
+	return fanout.Regions(ctx, regions, cloud.Concurrency, func(ctx context.Context, index int, region string) error {
+		authinpt := p.connection(ctx, cloud, "ec2")
+		authinpt.Region = region
+		return cloudyerror.FromAWS(fn(index, authinpt), kind, "")
+	})
+}
+
+// regionError returns the error of the region in the form carried by the responses, nil if the region did not fail.
+func regionError(errs fanout.Errors, region string) *cloudyerror.Error {
+	err := errs.Of(region)
+	if err == nil {
+		return nil
+	}
+	if cloudyerr, ok := cloudyerror.As(err); ok {
+		return cloudyerr
+	}
+	return cloudyerror.Wrap(cloudyerror.Unknown, err)
+}
+
+// allFailed returns the errors of the regions as the error of the operation if the operation failed in every region,
+// as there are no results left to be hidden by it.
+func allFailed(regions []string, errs fanout.Errors) error {
+	if len(regions) != 0 && len(errs) == len(regions) {
+		return errs
+	}
+	return nil
+}
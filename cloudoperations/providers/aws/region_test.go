@@ -0,0 +1,132 @@
+package awsprovider
+
+import (
+	"context"
+	"testing"
+
+	awsfake "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/fake"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+func TestGetAllNetworksDisabledRegion(t *testing.T) {
+	cloud := awsfake.New("us-east-1", "eu-west-1", "ap-south-1")
+	cloud.DisableRegion("ap-south-1")
+	ctx := context.Background()
+
+	for _, region := range []string{"us-east-1", "eu-west-1"} {
+		create := support.CreateNetworkInput{Name: "neuron", VpcCidr: "10.0.0.0/16", SubCidr: []string{"10.0.1.0/24"}, Type: "public", Cloud: fakeCloud(cloud, false)}
+		create.Cloud.Region = region
+		if _, err := (Provider{}).CreateNetwork(ctx, &create); err != nil {
+			t.Fatalf("creating network in %s: %v", region, err)
+		}
+	}
+
+	get := support.GetNetworksInput{Cloud: fakeCloud(cloud, false)}
+	get.Cloud.Concurrency = 2
+	responses, err := Provider{}.GetAllNetworks(ctx, &get)
+	if err != nil {
+		t.Fatalf("expected the disabled region not to fail the rest, got %v", err)
+	}
+	if len(responses) != 3 {
+		t.Fatalf("expected a response for every region, got %+v", responses)
+	}
+	for _, response := range responses {
+		switch response.Region {
+		case "ap-south-1":
+			if !cloudyerror.IsUnauthorized(response.Error) {
+				t.Errorf("expected the disabled region to carry AuthFailure, got %v", response.Error)
+			}
+		default:
+			if response.Error != nil || len(response.Networks) != 1 || response.Networks[0].Region != response.Region {
+				t.Errorf("expected the network of %s, got %+v", response.Region, response)
+			}
+		}
+	}
+}
+
+func TestGetAllServersDisabledRegion(t *testing.T) {
+	cloud := awsfake.New("us-east-1", "eu-west-1", "ap-south-1")
+	ctx := context.Background()
+
+	create := support.CreateNetworkInput{Name: "neuron", VpcCidr: "10.0.0.0/16", SubCidr: []string{"10.0.1.0/24"}, Type: "public", Cloud: fakeCloud(cloud, false)}
+	network, err := Provider{}.CreateNetwork(ctx, &create)
+	if err != nil {
+		t.Fatalf("creating network: %v", err)
+	}
+	server := support.CreateServerInput{InstanceName: "neuron", Count: 1, ImageId: "ami-0123456789", SubnetId: network.Networks[0].Subnets[0].ID, Flavor: "t2.micro", Cloud: fakeCloud(cloud, false)}
+	if _, err := (Provider{}).CreateServer(ctx, &server); err != nil {
+		t.Fatalf("creating server: %v", err)
+	}
+
+	cloud.DisableRegion("ap-south-1")
+	get := support.GetServersInput{Cloud: fakeCloud(cloud, false)}
+	responses, err := Provider{}.GetAllServers(ctx, &get)
+	if err != nil {
+		t.Fatalf("expected the disabled region not to fail the rest, got %v", err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("expected the region with servers and the region which failed, got %+v", responses)
+	}
+	if responses[0].Region != "us-east-1" || len(responses[0].Servers) != 1 || responses[0].Error != nil {
+		t.Errorf("expected the server of us-east-1, got %+v", responses[0])
+	}
+	if responses[1].Region != "ap-south-1" || !cloudyerror.IsUnauthorized(responses[1].Error) {
+		t.Errorf("expected ap-south-1 to carry AuthFailure, got %+v", responses[1])
+	}
+}
+
+func TestGetAllServersAllRegionsFailed(t *testing.T) {
+	cloud := awsfake.New("us-east-1", "eu-west-1")
+	ctx := context.Background()
+
+	cloud.FailNext("DescribeInstances", cloudyerror.New(cloudyerror.Timeout, "timed out"))
+	cloud.FailNext("DescribeInstances", cloudyerror.New(cloudyerror.Timeout, "timed out"))
+	get := support.GetServersInput{Cloud: fakeCloud(cloud, false)}
+	responses, err := Provider{}.GetAllServers(ctx, &get)
+	if err == nil {
+		t.Fatalf("expected the error when every region failed")
+	}
+	if len(responses) != 2 {
+		t.Errorf("expected the regions to carry their errors, got %+v", responses)
+	}
+}
+
+func TestGetAllImagesAndLoadbalancersDisabledRegion(t *testing.T) {
+	cloud := awsfake.New("us-east-1", "eu-west-1", "ap-south-1")
+	ctx := context.Background()
+
+	create := support.CreateNetworkInput{Name: "neuron", VpcCidr: "10.0.0.0/16", SubCidr: []string{"10.0.1.0/24"}, Type: "public", Cloud: fakeCloud(cloud, false)}
+	network, err := Provider{}.CreateNetwork(ctx, &create)
+	if err != nil {
+		t.Fatalf("creating network: %v", err)
+	}
+	server := support.CreateServerInput{InstanceName: "neuron", Count: 1, ImageId: "ami-0123456789", SubnetId: network.Networks[0].Subnets[0].ID, Flavor: "t2.micro", AssignPubIp: true, Cloud: fakeCloud(cloud, false)}
+	servers, err := Provider{}.CreateServer(ctx, &server)
+	if err != nil {
+		t.Fatalf("creating server: %v", err)
+	}
+	if _, err := (Provider{}).CreateImage(ctx, &support.CreateImageInput{InstanceIds: []string{servers.Servers[0].ID}, Cloud: fakeCloud(cloud, false)}); err != nil {
+		t.Fatalf("capturing image: %v", err)
+	}
+
+	cloud.DisableRegion("ap-south-1")
+	images, err := Provider{}.GetAllImages(ctx, &support.GetImagesInput{Cloud: fakeCloud(cloud, false)})
+	if err != nil {
+		t.Fatalf("expected the disabled region not to fail the rest, got %v", err)
+	}
+	if len(images) != 2 || images[0].Region != "us-east-1" || len(images[0].Images) != 1 || images[0].Error != nil {
+		t.Fatalf("expected the image of us-east-1 and the region which failed, got %+v", images)
+	}
+	if images[1].Region != "ap-south-1" || !cloudyerror.IsUnauthorized(images[1].Error) {
+		t.Errorf("expected ap-south-1 to carry AuthFailure, got %+v", images[1])
+	}
+
+	lbs, err := Provider{}.GetAllLoadbalancers(ctx, &support.GetLoadbalancerInput{Type: "classic", Cloud: fakeCloud(cloud, false)})
+	if err != nil {
+		t.Fatalf("expected the disabled region not to fail the rest, got %v", err)
+	}
+	if len(lbs) != 1 || lbs[0].Region != "ap-south-1" || !cloudyerror.IsUnauthorized(lbs[0].Error) {
+		t.Errorf("expected only ap-south-1 to be reported with AuthFailure, got %+v", lbs)
+	}
+}
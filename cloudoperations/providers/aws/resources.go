@@ -0,0 +1,638 @@
+package awsprovider
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	awsops "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// The functions below convert the responses of cloud/aws/operations to the resources common to all the clouds.
+// The responses holding the unfiltered output of aws are converted from the resources of aws they carry, and the same is set in Raw.
+
+// deletedState is the state of the resources which were deleted by the operation.
+const deletedState = "deleted"
+
+func resource(id, name, region, state string) cmn.Resource {
+	return cmn.Resource{ID: id, Name: name, Cloud: "aws", Region: region, State: state}
+}
+
+// parseTime parses the time as represented by aws or as it is formatted by cloud/aws/operations, nil is returned for the rest.
+func parseTime(value string) *time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999 -0700 MST"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return &parsed
+		}
+	}
+	return nil
+}
+
+func tags(ec2tags []*ec2.Tag) (string, map[string]string) {
+	if len(ec2tags) == 0 {
+		return "", nil
+	}
+	tagset := make(map[string]string, len(ec2tags))
+	for _, tag := range ec2tags {
+		tagset[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tagset["Name"], tagset
+}
+
+func networks(region string, responses ...awsops.NetworkResponse) []cmn.Network {
+	converted := make([]cmn.Network, 0, len(responses))
+	for _, response := range responses {
+		if response.GetVpcRaw != nil {
+			network := networkFromVpc(region, response.GetVpcRaw, response.GetSubnetRaw)
+			if response.DescribeSecurityRaw != nil {
+				for _, group := range response.DescribeSecurityRaw.SecurityGroups {
+					network.SecurityGroupIDs = append(network.SecurityGroupIDs, aws.StringValue(group.GroupId))
+					network.SecurityGroups = append(network.SecurityGroups, securityGroup(region, network.ID, awsops.SecurityGroupResponse{
+						Id: aws.StringValue(group.GroupId), Name: aws.StringValue(group.GroupName), Ports: awsops.SecurityGroupPorts(group.IpPermissions),
+					}))
+				}
+			}
+			if response.DescribeIgwRaw != nil && len(response.DescribeIgwRaw.InternetGateways) != 0 {
+				network.Extras = map[string]interface{}{"igwid": aws.StringValue(response.DescribeIgwRaw.InternetGateways[0].InternetGatewayId)}
+			}
+			converted = append(converted, network)
+			continue
+		}
+		if response.GetVpcsRaw != nil {
+			for _, vpc := range response.GetVpcsRaw.Vpcs {
+				converted = append(converted, networkFromVpc(region, vpc, nil))
+			}
+			continue
+		}
+
+		if response.Region != "" {
+			region = response.Region
+		}
+		network := cmn.Network{Resource: resource(response.VpcId, response.Name, region, response.State), SecurityGroupIDs: response.SecGroupIds}
+		if response.Cidr != "" {
+			network.CIDRs = append([]string{response.Cidr}, response.SecondaryCidrs...)
+		}
+		network.Subnets = subnets(region, response.VpcId, response.Subnets...)
+		for _, group := range response.SecurityGroups {
+			network.SecurityGroups = append(network.SecurityGroups, securityGroup(region, response.VpcId, group))
+		}
+		network.Extras = make(map[string]interface{})
+		if response.Type != "" {
+			network.Extras["type"] = response.Type
+		}
+		if response.IgwId != "" {
+			network.Extras["igwid"] = response.IgwId
+		}
+		if response.IsDefault {
+			network.Extras["isdefault"] = true
+		}
+		if response.Rollback != nil {
+			network.Extras["rollback"] = response.Rollback
+		}
+		converted = append(converted, network)
+	}
+	return converted
+}
+
+func networkFromVpc(region string, vpc *ec2.Vpc, subnetsRaw *ec2.DescribeSubnetsOutput) cmn.Network {
+	name, tagset := tags(vpc.Tags)
+	network := cmn.Network{Resource: resource(aws.StringValue(vpc.VpcId), name, region, aws.StringValue(vpc.State))}
+	network.Tags = tagset
+	network.Raw = vpc
+	for _, cidr := range vpc.CidrBlockAssociationSet {
+		network.CIDRs = append(network.CIDRs, aws.StringValue(cidr.CidrBlock))
+	}
+	if len(network.CIDRs) == 0 && vpc.CidrBlock != nil {
+		network.CIDRs = []string{aws.StringValue(vpc.CidrBlock)}
+	}
+	if subnetsRaw != nil {
+		for _, subnet := range subnetsRaw.Subnets {
+			network.Subnets = append(network.Subnets, subnetFromEc2(region, subnet))
+		}
+	}
+	return network
+}
+
+func subnets(region, vpcid string, responses ...awsops.SubnetReponse) []cmn.Subnet {
+	converted := make([]cmn.Subnet, 0, len(responses))
+	for _, response := range responses {
+		if response.GetSubnetRaw != nil {
+			for _, subnet := range response.GetSubnetRaw.Subnets {
+				converted = append(converted, subnetFromEc2(region, subnet))
+			}
+			continue
+		}
+		if response.CreateSubnetRaw != nil && response.CreateSubnetRaw.Subnet != nil {
+			converted = append(converted, subnetFromEc2(region, response.CreateSubnetRaw.Subnet))
+			continue
+		}
+		subnet := cmn.Subnet{Resource: resource(response.Id, response.Name, region, response.State), NetworkID: response.VpcId}
+		if subnet.NetworkID == "" {
+			subnet.NetworkID = vpcid
+		}
+		if response.Cidr != "" {
+			subnet.CIDRs = []string{response.Cidr}
+		}
+		converted = append(converted, subnet)
+	}
+	return converted
+}
+
+func subnetFromEc2(region string, sub *ec2.Subnet) cmn.Subnet {
+	name, tagset := tags(sub.Tags)
+	subnet := cmn.Subnet{Resource: resource(aws.StringValue(sub.SubnetId), name, region, aws.StringValue(sub.State)), NetworkID: aws.StringValue(sub.VpcId)}
+	subnet.Zone = aws.StringValue(sub.AvailabilityZone)
+	subnet.Tags = tagset
+	subnet.Raw = sub
+	if sub.CidrBlock != nil {
+		subnet.CIDRs = []string{aws.StringValue(sub.CidrBlock)}
+	}
+	return subnet
+}
+
+func securityGroup(region, vpcid string, response awsops.SecurityGroupResponse) cmn.SecurityGroup {
+	if raw := response.SecurityGroupRaw; raw != nil {
+		group := cmn.SecurityGroup{Resource: resource(aws.StringValue(raw.GroupId), aws.StringValue(raw.GroupName), region, ""), NetworkID: aws.StringValue(raw.VpcId)}
+		group.Ports = awsops.SecurityGroupPorts(raw.IpPermissions)
+		group.Description = aws.StringValue(raw.Description)
+		_, group.Tags = tags(raw.Tags)
+		group.Raw = raw
+		return group
+	}
+	if response.VpcId != "" {
+		vpcid = response.VpcId
+	}
+	group := cmn.SecurityGroup{Resource: resource(response.Id, response.Name, region, ""), NetworkID: vpcid, Ports: response.Ports, Description: response.Description}
+	for _, rule := range response.Rules {
+		group.Rules = append(group.Rules, cmn.SecurityRule{
+			Direction: rule.Direction, Protocol: rule.Protocol, Ports: rule.Ports, CIDRs: rule.Cidrs, SecurityGroupIDs: rule.SecurityGroups, Description: rule.Description,
+		})
+	}
+	return group
+}
+
+// subnetsFromNetwork returns the subnets held by the response passed, as the subnets are given out in the form of network by cloud/aws/operations.
+func subnetsFromNetwork(region string, response awsops.NetworkResponse) []cmn.Subnet {
+	if response.GetSubnetRaw != nil {
+		return subnets(region, response.VpcId, awsops.SubnetReponse{GetSubnetRaw: response.GetSubnetRaw})
+	}
+	return subnets(region, response.VpcId, response.Subnets...)
+}
+
+func servers(region string, responses ...awsops.ServerResponse) []cmn.Server {
+	converted := make([]cmn.Server, 0, len(responses))
+	for _, response := range responses {
+		switch {
+		case response.CreateInstRaw != nil:
+			converted = append(converted, serversFromReservations(region, response.CreateInstRaw.Reservations)...)
+		case response.GetInstRaw != nil:
+			converted = append(converted, serversFromReservations(region, response.GetInstRaw.Reservations)...)
+		case response.DeleteInstRaw != nil:
+			converted = append(converted, serversFromStateChanges(region, response.DeleteInstRaw.TerminatingInstances)...)
+		case response.StartInstRaw != nil:
+			converted = append(converted, serversFromStateChanges(region, response.StartInstRaw.StartingInstances)...)
+		case response.StopInstRaw != nil:
+			converted = append(converted, serversFromStateChanges(region, response.StopInstRaw.StoppingInstances)...)
+		default:
+			// the response carrying only the key pair generated (ex: the creation failed before launching) is no server.
+			if response.InstanceId == "" {
+				continue
+			}
+			if response.Region != "" {
+				region = response.Region
+			}
+			state := response.State
+			if state == "" {
+				state = response.CurrentState
+			}
+			server := cmn.Server{Resource: resource(response.InstanceId, response.InstanceName, region, state), Flavor: response.InstanceType, SubnetID: response.SubnetId}
+			server.CreatedAt = parseTime(response.CreatedOn)
+			if response.PrivateIpAddress != "" {
+				server.PrivateIPs = []string{response.PrivateIpAddress}
+			}
+			if response.PublicIpAddress != "" {
+				server.PublicIPs = []string{response.PublicIpAddress}
+			}
+			for _, volume := range response.Volumes {
+				server.Volumes = append(server.Volumes, cmn.Volume{
+					Resource:         resource(volume.VolumeId, "", region, ""),
+					Size:             volume.Size,
+					Type:             volume.Type,
+					Iops:             volume.Iops,
+					Encrypted:        volume.Encrypted,
+					ServerID:         response.InstanceId,
+					DeviceName:       volume.DeviceName,
+					Root:             volume.Root,
+					DeleteWithServer: volume.DeleteOnTermination,
+				})
+			}
+			server.Extras = make(map[string]interface{})
+			if response.PrivateDnsName != "" {
+				server.Extras["privatednsname"] = response.PrivateDnsName
+			}
+			if response.PreviousState != "" {
+				server.Extras["previousstate"] = response.PreviousState
+			}
+			if response.InstanceDeleteState != "" {
+				server.Extras["deletestate"] = response.InstanceDeleteState
+			}
+			if response.AllocationId != "" {
+				server.Extras["allocationid"] = response.AllocationId
+			}
+			if response.KeyName != "" {
+				server.Extras["keyname"] = response.KeyName
+			}
+			if response.Lifecycle != "" {
+				server.Extras["lifecycle"] = response.Lifecycle
+			}
+			if response.SpotInstanceRequestId != "" {
+				server.Extras["spotinstancerequestid"] = response.SpotInstanceRequestId
+			}
+			if response.Rollback != nil {
+				server.Extras["rollback"] = response.Rollback
+			}
+			converted = append(converted, server)
+		}
+	}
+	return converted
+}
+
+// serverChanges returns the settings of the servers before and after they were updated, the responses which are not of UpdateServer are skipped.
+func serverChanges(responses []awsops.ServerResponse) []cmn.ServerChange {
+	changes := make([]cmn.ServerChange, 0)
+	for _, response := range responses {
+		if response.Before == nil || response.After == nil {
+			continue
+		}
+		changes = append(changes, cmn.ServerChange{ServerID: response.InstanceId, Before: serverSettings(response.Before), After: serverSettings(response.After)})
+	}
+	return changes
+}
+
+func serverSettings(attributes *awsops.ServerAttributes) cmn.ServerSettings {
+	return cmn.ServerSettings{
+		State:                 attributes.State,
+		Flavor:                attributes.InstanceType,
+		TerminationProtection: attributes.DisableApiTermination,
+		Monitoring:            attributes.Monitoring,
+		SecurityGroupIDs:      attributes.SecurityGroupIds,
+		UserData:              attributes.UserData,
+	}
+}
+
+func serversFromReservations(region string, reservations []*ec2.Reservation) []cmn.Server {
+	converted := make([]cmn.Server, 0)
+	for _, reservation := range reservations {
+		for _, instance := range reservation.Instances {
+			name, tagset := tags(instance.Tags)
+			server := cmn.Server{Resource: resource(aws.StringValue(instance.InstanceId), name, region, "")}
+			if instance.State != nil {
+				server.State = aws.StringValue(instance.State.Name)
+			}
+			if instance.Placement != nil {
+				server.Zone = aws.StringValue(instance.Placement.AvailabilityZone)
+			}
+			server.Tags = tagset
+			server.CreatedAt = instance.LaunchTime
+			server.Flavor = aws.StringValue(instance.InstanceType)
+			server.ImageID = aws.StringValue(instance.ImageId)
+			server.NetworkID = aws.StringValue(instance.VpcId)
+			server.SubnetID = aws.StringValue(instance.SubnetId)
+			if instance.PrivateIpAddress != nil {
+				server.PrivateIPs = []string{aws.StringValue(instance.PrivateIpAddress)}
+			}
+			if instance.PublicIpAddress != nil {
+				server.PublicIPs = []string{aws.StringValue(instance.PublicIpAddress)}
+			}
+			// the details of the volumes are not part of the instance, only the attachments are reported.
+			for _, mapping := range instance.BlockDeviceMappings {
+				if mapping.Ebs == nil {
+					continue
+				}
+				server.Volumes = append(server.Volumes, cmn.Volume{
+					Resource:         resource(aws.StringValue(mapping.Ebs.VolumeId), "", region, ""),
+					ServerID:         server.ID,
+					DeviceName:       aws.StringValue(mapping.DeviceName),
+					Root:             aws.StringValue(mapping.DeviceName) == aws.StringValue(instance.RootDeviceName),
+					DeleteWithServer: aws.BoolValue(mapping.Ebs.DeleteOnTermination),
+				})
+			}
+			server.Raw = instance
+			converted = append(converted, server)
+		}
+	}
+	return converted
+}
+
+func serversFromStateChanges(region string, changes []*ec2.InstanceStateChange) []cmn.Server {
+	converted := make([]cmn.Server, 0, len(changes))
+	for _, change := range changes {
+		server := cmn.Server{Resource: resource(aws.StringValue(change.InstanceId), "", region, "")}
+		if change.CurrentState != nil {
+			server.State = aws.StringValue(change.CurrentState.Name)
+		}
+		server.Raw = change
+		converted = append(converted, server)
+	}
+	return converted
+}
+
+func volumeFromResponse(region string, response awsops.VolumeResponse) cmn.Volume {
+	if raw := response.VolumeRaw; raw != nil {
+		name, tagset := tags(raw.Tags)
+		volume := cmn.Volume{Resource: resource(aws.StringValue(raw.VolumeId), name, region, aws.StringValue(raw.State))}
+		volume.Zone = aws.StringValue(raw.AvailabilityZone)
+		volume.Tags = tagset
+		volume.CreatedAt = raw.CreateTime
+		volume.Size = aws.Int64Value(raw.Size)
+		volume.Type = aws.StringValue(raw.VolumeType)
+		volume.Iops = aws.Int64Value(raw.Iops)
+		volume.Encrypted = aws.BoolValue(raw.Encrypted)
+		volume.SnapshotID = aws.StringValue(raw.SnapshotId)
+		if len(raw.Attachments) != 0 {
+			volume.ServerID = aws.StringValue(raw.Attachments[0].InstanceId)
+			volume.DeviceName = aws.StringValue(raw.Attachments[0].Device)
+			volume.DeleteWithServer = aws.BoolValue(raw.Attachments[0].DeleteOnTermination)
+		}
+		volume.Raw = raw
+		return volume
+	}
+	volume := cmn.Volume{
+		Resource:         resource(response.VolumeId, response.Name, region, response.State),
+		Size:             response.Size,
+		Type:             response.Type,
+		Iops:             response.Iops,
+		Encrypted:        response.Encrypted,
+		ServerID:         response.InstanceId,
+		DeviceName:       response.DeviceName,
+		DeleteWithServer: response.DeleteOnTermination,
+		SnapshotID:       response.SnapshotId,
+	}
+	volume.Zone = response.Zone
+	volume.CreatedAt = parseTime(response.CreateTime)
+	return volume
+}
+
+func snapshotFromResponse(region string, response awsops.SnapshotResponse) cmn.Snapshot {
+	if raw := response.SnapshotRaw; raw != nil {
+		name, tagset := tags(raw.Tags)
+		snapshot := cmn.Snapshot{Resource: resource(aws.StringValue(raw.SnapshotId), name, region, aws.StringValue(raw.State))}
+		snapshot.Tags = tagset
+		snapshot.CreatedAt = raw.StartTime
+		snapshot.VolumeID = aws.StringValue(raw.VolumeId)
+		snapshot.Size = aws.Int64Value(raw.VolumeSize)
+		snapshot.Encrypted = aws.BoolValue(raw.Encrypted)
+		snapshot.Description = aws.StringValue(raw.Description)
+		snapshot.Raw = raw
+		return snapshot
+	}
+	snapshot := cmn.Snapshot{
+		Resource:    resource(response.SnapshotId, response.Name, region, response.State),
+		VolumeID:    response.VolumeId,
+		Size:        response.Size,
+		Encrypted:   response.Encrypted,
+		Description: response.Description,
+	}
+	snapshot.CreatedAt = parseTime(response.StartTime)
+	return snapshot
+}
+
+func images(region string, responses ...awsops.ImageResponse) []cmn.Image {
+	converted := make([]cmn.Image, 0, len(responses))
+	for _, response := range responses {
+		switch {
+		case response.GetImageRaw != nil:
+			converted = append(converted, imageFromEc2(region, response.GetImageRaw))
+		case response.GetImagesRaw != nil:
+			for _, img := range response.GetImagesRaw.Images {
+				converted = append(converted, imageFromEc2(region, img))
+			}
+		case response.CreateImageRaw != nil:
+			image := cmn.Image{Resource: resource(aws.StringValue(response.CreateImageRaw.ImageId), "", region, "")}
+			image.Raw = response.CreateImageRaw
+			converted = append(converted, image)
+		default:
+			image := cmn.Image{Resource: resource(response.ImageId, response.Name, region, response.State), Description: response.Description, Public: response.IsPublic}
+			image.CreatedAt = parseTime(response.CreationDate)
+			if response.SnapShot.SnapshotId != "" {
+				image.Extras = map[string]interface{}{"snapshot": response.SnapShot}
+			}
+			converted = append(converted, image)
+		}
+	}
+	return converted
+}
+
+func imageFromEc2(region string, img *ec2.Image) cmn.Image {
+	name, tagset := tags(img.Tags)
+	if img.Name != nil {
+		name = aws.StringValue(img.Name)
+	}
+	image := cmn.Image{Resource: resource(aws.StringValue(img.ImageId), name, region, aws.StringValue(img.State))}
+	image.Tags = tagset
+	image.CreatedAt = parseTime(aws.StringValue(img.CreationDate))
+	image.Description = aws.StringValue(img.Description)
+	image.Public = aws.BoolValue(img.Public)
+	image.Raw = img
+	return image
+}
+
+// deletedImages returns the images passed as deleted, since the deletion of images in aws does not report the images.
+func deletedImages(region string, ids []string) []cmn.Image {
+	converted := make([]cmn.Image, 0, len(ids))
+	for _, id := range ids {
+		converted = append(converted, cmn.Image{Resource: resource(id, "", region, deletedState)})
+	}
+	return converted
+}
+
+// deletedNetworks returns the networks passed as deleted, since the deletion of networks in aws does not report the networks.
+func deletedNetworks(region string, ids []string) []cmn.Network {
+	converted := make([]cmn.Network, 0, len(ids))
+	for _, id := range ids {
+		converted = append(converted, cmn.Network{Resource: resource(id, "", region, deletedState)})
+	}
+	return converted
+}
+
+// deletedResources converts the log of the deletion of network, the networks deleted are left out as they are part of the response already.
+func deletedResources(deleted []awsops.DeletedResource) []cmn.DeletedResource {
+	converted := make([]cmn.DeletedResource, 0, len(deleted))
+	for _, resource := range deleted {
+		if resource.Kind != "network" {
+			converted = append(converted, cmn.DeletedResource{Kind: resource.Kind, ID: resource.Id, Status: resource.Status})
+		}
+	}
+	return converted
+}
+
+func loadbalancers(region string, responses ...awsops.LoadBalanceResponse) []cmn.LoadBalancer {
+	converted := make([]cmn.LoadBalancer, 0, len(responses))
+	for _, response := range responses {
+		if len(response.ClassicLb) != 0 || len(response.ApplicationLb) != 0 {
+			converted = append(converted, loadbalancers(region, response.ClassicLb...)...)
+			converted = append(converted, loadbalancers(region, response.ApplicationLb...)...)
+			continue
+		}
+		switch {
+		case response.GetClassicLbRaw != nil:
+			converted = append(converted, loadbalancerFromClassic(region, response.GetClassicLbRaw))
+		case response.GetClassicLbsRaw != nil:
+			for _, load := range response.GetClassicLbsRaw.LoadBalancerDescriptions {
+				converted = append(converted, loadbalancerFromClassic(region, load))
+			}
+		case response.CreateClassicLbRaw != nil:
+			load := cmn.LoadBalancer{Resource: resource("", "", region, ""), Type: "classic", DNSName: aws.StringValue(response.CreateClassicLbRaw.DNSName)}
+			load.Raw = response.CreateClassicLbRaw
+			converted = append(converted, load)
+		case response.GetApplicationLbRaw.GetApplicationLbRaw != nil:
+			load := loadbalancerFromApplication(region, response.GetApplicationLbRaw.GetApplicationLbRaw)
+			load.Listeners = listeners(awsops.ApplicationListeners(response.GetApplicationLbRaw.GetListnersRaw))
+			load.Raw = response.GetApplicationLbRaw
+			converted = append(converted, load)
+		case response.CreateApplicationLbRaw.CreateApplicationLbRaw != nil:
+			for _, created := range response.CreateApplicationLbRaw.CreateApplicationLbRaw.LoadBalancers {
+				load := loadbalancerFromApplication(region, created)
+				load.Raw = response.CreateApplicationLbRaw
+				converted = append(converted, load)
+			}
+		default:
+			load := cmn.LoadBalancer{Resource: resource(response.LbArn, response.Name, region, ""), Type: response.Type, DNSName: response.LbDns, Scheme: response.Scheme, NetworkID: response.VpcId}
+			if load.ID == "" {
+				// classic loadbalancers are identified by their names.
+				load.ID = response.Name
+			}
+			load.CreatedAt = parseTime(response.Createdon)
+			load.Listeners = listeners(response.Listeners)
+			load.Extras = make(map[string]interface{})
+			if response.TargetArn != nil {
+				load.Extras["targetarn"] = response.TargetArn
+			}
+			if response.ListnerArn != nil {
+				load.Extras["listnerarn"] = response.ListnerArn
+			}
+			converted = append(converted, load)
+		}
+	}
+	return converted
+}
+
+func loadbalancerFromClassic(region string, load *elb.LoadBalancerDescription) cmn.LoadBalancer {
+	name := aws.StringValue(load.LoadBalancerName)
+	converted := cmn.LoadBalancer{Resource: resource(name, name, region, ""), Type: "classic"}
+	converted.CreatedAt = load.CreatedTime
+	converted.DNSName = aws.StringValue(load.DNSName)
+	converted.Scheme = aws.StringValue(load.Scheme)
+	converted.NetworkID = aws.StringValue(load.VPCId)
+	converted.Listeners = listeners(awsops.ClassicListeners(load.ListenerDescriptions))
+	converted.Raw = load
+	return converted
+}
+
+func listeners(responses []awsops.ListenerResponse) []cmn.Listener {
+	if len(responses) == 0 {
+		return nil
+	}
+	converted := make([]cmn.Listener, 0, len(responses))
+	for _, response := range responses {
+		converted = append(converted, cmn.Listener{Port: response.Port, Protocol: response.Protocol})
+	}
+	return converted
+}
+
+func loadbalancerFromApplication(region string, load *elbv2.LoadBalancer) cmn.LoadBalancer {
+	converted := cmn.LoadBalancer{Resource: resource(aws.StringValue(load.LoadBalancerArn), aws.StringValue(load.LoadBalancerName), region, "")}
+	if load.State != nil {
+		converted.State = aws.StringValue(load.State.Code)
+	}
+	converted.Type = strings.ToLower(aws.StringValue(load.Type))
+	converted.CreatedAt = load.CreatedTime
+	converted.DNSName = aws.StringValue(load.DNSName)
+	converted.Scheme = aws.StringValue(load.Scheme)
+	converted.NetworkID = aws.StringValue(load.VpcId)
+	converted.Raw = load
+	return converted
+}
+
+func deletedLoadbalancers(region, lbType string, responses ...awsops.LoadBalanceDeleteResponse) []cmn.LoadBalancer {
+	converted := make([]cmn.LoadBalancer, 0, len(responses))
+	for _, response := range responses {
+		id := response.LbArn
+		if id == "" {
+			id = response.LbName
+		}
+		load := cmn.LoadBalancer{Resource: resource(id, response.LbName, region, deletedState), Type: strings.ToLower(lbType)}
+		converted = append(converted, load)
+	}
+	return converted
+}
+
+func addressFromResponse(region string, response awsops.AddressResponse) cmn.Address {
+	if raw := response.AddressRaw; raw != nil {
+		name, tagset := tags(raw.Tags)
+		state := "unassociated"
+		if raw.AssociationId != nil {
+			state = "associated"
+		}
+		address := cmn.Address{Resource: resource(aws.StringValue(raw.AllocationId), name, region, state)}
+		address.Tags = tagset
+		address.PublicIP = aws.StringValue(raw.PublicIp)
+		address.ServerID = aws.StringValue(raw.InstanceId)
+		address.NetworkInterfaceID = aws.StringValue(raw.NetworkInterfaceId)
+		address.PrivateIP = aws.StringValue(raw.PrivateIpAddress)
+		address.Raw = raw
+		return address
+	}
+	return cmn.Address{
+		Resource:           resource(response.AllocationId, response.Name, region, response.State),
+		PublicIP:           response.PublicIp,
+		ServerID:           response.InstanceId,
+		NetworkInterfaceID: response.NetworkInterfaceId,
+		PrivateIP:          response.PrivateIp,
+	}
+}
+
+// keyPairFromResponse converts the key pair, aws identifies the key pairs by their names hence the name is the ID as well.
+func keyPairFromResponse(region string, response awsops.KeyPairResponse) cmn.KeyPair {
+	if raw := response.KeyPairRaw; raw != nil {
+		name := aws.StringValue(raw.KeyName)
+		pair := cmn.KeyPair{Resource: resource(name, name, region, "available"), Fingerprint: aws.StringValue(raw.KeyFingerprint), PrivateKey: response.PrivateKey}
+		pair.Raw = raw
+		return pair
+	}
+	return cmn.KeyPair{
+		Resource:    resource(response.Name, response.Name, region, "available"),
+		Fingerprint: response.Fingerprint,
+		PrivateKey:  response.PrivateKey,
+	}
+}
+
+// spotPriceFromResponse converts the spot price, the prices which are not parsable are reported as 0.
+func spotPriceFromResponse(region string, response awsops.SpotPriceResponse) cmn.SpotPrice {
+	if raw := response.SpotPriceRaw; raw != nil {
+		price, _ := strconv.ParseFloat(aws.StringValue(raw.SpotPrice), 64)
+		return cmn.SpotPrice{
+			Flavor:    aws.StringValue(raw.InstanceType),
+			Region:    region,
+			Zone:      aws.StringValue(raw.AvailabilityZone),
+			Product:   aws.StringValue(raw.ProductDescription),
+			Price:     price,
+			Timestamp: raw.Timestamp,
+			Raw:       raw,
+		}
+	}
+	price, _ := strconv.ParseFloat(response.Price, 64)
+	return cmn.SpotPrice{
+		Flavor:    response.InstanceType,
+		Region:    region,
+		Zone:      response.Zone,
+		Product:   response.ProductDescription,
+		Price:     price,
+		Timestamp: parseTime(response.Timestamp),
+	}
+}
@@ -0,0 +1,123 @@
+package awsprovider
+
+import (
+	"context"
+
+	awsops "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// CreateSecurityGroup creates the security group along with its rules in the network passed.
+func (p Provider) CreateSecurityGroup(ctx context.Context, sec *support.CreateSecurityGroupInput) (support.SecurityGroupResponse, error) {
+
+	authinpt := p.connection(ctx, sec.Cloud, "ec2")
+
+	securityin := awsops.SecurityGroupInput{}
+	securityin.Name = sec.Name
+	securityin.VpcId = sec.NetworkID
+	securityin.Description = sec.Description
+	securityin.Rules = securityGroupRules(sec.Rules)
+	securityin.GetRaw = sec.Cloud.GetRaw
+
+	if sec.Cloud.DryRun {
+		plan, planErr := securityin.PlanCreateSecurityGroup(authinpt)
+		if planErr != nil {
+			return support.SecurityGroupResponse{}, cloudyerror.FromAWS(planErr, "securitygroup", "")
+		}
+		return support.SecurityGroupResponse{Plan: &plan}, nil
+	}
+	response, err := securityin.CreateSecurityGroup(authinpt)
+	if err != nil {
+		return support.SecurityGroupResponse{}, cloudyerror.FromAWS(err, "securitygroup", "")
+	}
+	return securityGroupResponse(authinpt.Region, "", response), nil
+}
+
+// GetSecurityGroups fetches the security groups selected, the ones of the network are fetched if the security groups are not passed.
+func (p Provider) GetSecurityGroups(ctx context.Context, sec *support.GetSecurityGroupsInput) (support.SecurityGroupResponse, error) {
+
+	authinpt := p.connection(ctx, sec.Cloud, "ec2")
+
+	securityin := awsops.SecurityGroupInput{}
+	securityin.GroupIds = sec.SecurityGroupIDs
+	securityin.VpcId = sec.NetworkID
+	securityin.GetRaw = sec.Cloud.GetRaw
+
+	response, err := securityin.GetSecurityGroups(authinpt)
+	if err != nil {
+		return support.SecurityGroupResponse{}, cloudyerror.FromAWS(err, "securitygroup", "")
+	}
+	return securityGroupResponse(authinpt.Region, "", response...), nil
+}
+
+// UpdateSecurityGroup adds, revokes or sets the rules of the security groups selected.
+func (p Provider) UpdateSecurityGroup(ctx context.Context, sec *support.UpdateSecurityGroupInput) (support.SecurityGroupResponse, error) {
+
+	authinpt := p.connection(ctx, sec.Cloud, "ec2")
+
+	securityin := awsops.SecurityGroupInput{}
+	securityin.GroupIds = sec.SecurityGroupIDs
+	securityin.Action = sec.Action
+	securityin.Rules = securityGroupRules(sec.Rules)
+	securityin.GetRaw = sec.Cloud.GetRaw
+
+	if sec.Cloud.DryRun {
+		plan, planErr := securityin.PlanUpdateSecurityGroup(authinpt)
+		if planErr != nil {
+			return support.SecurityGroupResponse{}, cloudyerror.FromAWS(planErr, "securitygroup", "")
+		}
+		return support.SecurityGroupResponse{Plan: &plan}, nil
+	}
+	response, err := securityin.UpdateSecurityGroup(authinpt)
+	if err != nil {
+		return support.SecurityGroupResponse{}, cloudyerror.FromAWS(err, "securitygroup", "")
+	}
+	return securityGroupResponse(authinpt.Region, "", response...), nil
+}
+
+// DeleteSecurityGroup deletes the security groups selected, the ones deleted before the failure are returned along with the error.
+func (p Provider) DeleteSecurityGroup(ctx context.Context, sec *support.DeleteSecurityGroupInput) (support.SecurityGroupResponse, error) {
+
+	authinpt := p.connection(ctx, sec.Cloud, "ec2")
+
+	securityin := awsops.SecurityGroupInput{}
+	securityin.GroupIds = sec.SecurityGroupIDs
+	securityin.GetRaw = sec.Cloud.GetRaw
+
+	if sec.Cloud.DryRun {
+		plan, planErr := securityin.PlanDeleteSecurityGroup(authinpt)
+		if planErr != nil {
+			return support.SecurityGroupResponse{}, cloudyerror.FromAWS(planErr, "securitygroup", "")
+		}
+		return support.SecurityGroupResponse{Plan: &plan}, nil
+	}
+	response, err := securityin.DeleteSecurityGroup(authinpt)
+	deleted := securityGroupResponse(authinpt.Region, deletedState, response...)
+	if err != nil {
+		return deleted, cloudyerror.FromAWS(err, "securitygroup", "")
+	}
+	return deleted, nil
+}
+
+func securityGroupRules(rules []cmn.SecurityRule) []awsops.SecurityGroupRule {
+	converted := make([]awsops.SecurityGroupRule, 0, len(rules))
+	for _, rule := range rules {
+		converted = append(converted, awsops.SecurityGroupRule{
+			Direction: rule.Direction, Protocol: rule.Protocol, Ports: rule.Ports, Cidrs: rule.CIDRs, SecurityGroups: rule.SecurityGroupIDs, Description: rule.Description,
+		})
+	}
+	return converted
+}
+
+// securityGroupResponse converts the security groups of aws to the response, the state passed is set on each of them.
+func securityGroupResponse(region, state string, responses ...awsops.SecurityGroupResponse) support.SecurityGroupResponse {
+	response := support.SecurityGroupResponse{AwsResponse: responses}
+	for _, group := range responses {
+		converted := securityGroup(region, "", group)
+		converted.State = state
+		response.SecurityGroups = append(response.SecurityGroups, converted)
+	}
+	return response
+}
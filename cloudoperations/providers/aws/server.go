@@ -0,0 +1,176 @@
+package awsprovider
+
+import (
+	"context"
+
+	auth "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
+	awsserver "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// CreateServer creates the servers in aws with the requirement passed.
+func (p Provider) CreateServer(ctx context.Context, serv *support.CreateServerInput) (support.ServerCreateResponse, error) {
+
+	authInpt := p.connection(ctx, serv.Cloud, "ec2")
+
+	serverin := awsserver.CreateServerInput{}
+	serverin.InstanceName = serv.InstanceName
+	serverin.ImageId = serv.ImageId
+	serverin.InstanceType = serv.Flavor
+	serverin.KeyName = serv.KeyName
+	serverin.GenerateKeyPair = serv.GenerateKeyPair
+	serverin.MaxCount = serv.Count
+	serverin.SubnetId = serv.SubnetId
+	serverin.UserData = serv.UserData
+	serverin.AssignPubIp = serv.AssignPubIp
+	serverin.StaticPublicIp = serv.StaticPublicIp
+	serverin.ClientToken = serv.IdempotencyKey
+	for _, volume := range serv.Volumes {
+		serverin.Volumes = append(serverin.Volumes, awsserver.ServerVolume(volume))
+	}
+	serverin.MarketType = serv.MarketType
+	serverin.MaxPrice = serv.MaxPrice
+	serverin.InterruptionBehavior = serv.InterruptionBehavior
+	serverin.GetRaw = serv.Cloud.GetRaw
+	if serv.Cloud.DryRun {
+		plan, planErr := serverin.PlanCreateServer(authInpt)
+		if planErr != nil {
+			return support.ServerCreateResponse{}, cloudyerror.FromAWS(planErr, "server", "")
+		}
+		return support.ServerCreateResponse{Plan: &plan}, nil
+	}
+	// the servers launched before the failure are returned along with the error, so that they are not lost.
+	response, err := serverin.CreateServer(authInpt)
+	created := support.ServerCreateResponse{Servers: servers(authInpt.Region, response...), AwsResponse: response, KeyPair: generatedKeyPair(authInpt.Region, response)}
+	if err != nil {
+		return created, cloudyerror.FromAWS(err, "server", "")
+	}
+	return created, nil
+}
+
+// generatedKeyPair returns the key pair generated for the servers along with its private key, nil if none was generated.
+func generatedKeyPair(region string, response []awsserver.ServerResponse) *cmn.KeyPair {
+	if (len(response) == 0) || (response[0].PrivateKey == "") {
+		return nil
+	}
+	pair := keyPairFromResponse(region, awsserver.KeyPairResponse{Name: response[0].KeyName, PrivateKey: response[0].PrivateKey})
+	return &pair
+}
+
+// DeleteServer deletes the servers passed or all the servers in the network passed from aws.
+func (p Provider) DeleteServer(ctx context.Context, serv *support.DeleteServersInput) (support.DeleteServerResponse, error) {
+
+	authInpt := p.connection(ctx, serv.Cloud, "ec2")
+
+	serverin := awsserver.DeleteServerInput{GetRaw: serv.Cloud.GetRaw}
+	if serv.InstanceIds != nil {
+		serverin.InstanceIds = serv.InstanceIds
+		if serv.Cloud.DryRun {
+			plan, planErr := serverin.PlanDeleteServer(authInpt)
+			if planErr != nil {
+				return support.DeleteServerResponse{}, cloudyerror.FromAWS(planErr, "server", "")
+			}
+			return support.DeleteServerResponse{Plan: &plan}, nil
+		}
+		serverResponse, serverr := serverin.DeleteServer(authInpt)
+		if serverr != nil {
+			return support.DeleteServerResponse{}, cloudyerror.FromAWS(serverr, "server", "")
+		}
+		return support.DeleteServerResponse{Servers: servers(authInpt.Region, serverResponse...), AwsResponse: serverResponse}, nil
+	} else if serv.VpcId != "" {
+		serverin.VpcId = serv.VpcId
+		if serv.Cloud.DryRun {
+			plan, planErr := serverin.PlanDeleteServerFromVpc(authInpt)
+			if planErr != nil {
+				return support.DeleteServerResponse{}, cloudyerror.FromAWS(planErr, "server", "")
+			}
+			return support.DeleteServerResponse{Plan: &plan}, nil
+		}
+		serverResponse, serverr := serverin.DeleteServerFromVpc(authInpt)
+		if serverr != nil {
+			return support.DeleteServerResponse{}, cloudyerror.FromAWS(serverr, "server", "")
+		}
+		return support.DeleteServerResponse{Servers: servers(authInpt.Region, serverResponse...), AwsResponse: serverResponse}, nil
+	}
+	return support.DeleteServerResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed valid input to get details of server, the input looks like empty")
+}
+
+// GetServers fetches the details of the servers passed, or the servers in the subnets/networks passed from aws.
+// If nothing is passed it fetches the details of all the servers in the region.
+func (p Provider) GetServers(ctx context.Context, serv *support.GetServersInput) (support.GetServerResponse, error) {
+
+	authinpt := p.connection(ctx, serv.Cloud, "ec2")
+
+	serverin := awsserver.DescribeInstanceInput{GetRaw: serv.Cloud.GetRaw}
+	var serverResponse []awsserver.ServerResponse
+	var serverr error
+	if serv.InstanceIds != nil {
+		serverin.InstanceIds = serv.InstanceIds
+		serverResponse, serverr = serverin.GetServersDetails(authinpt)
+	} else if serv.SubnetIds != nil {
+		serverin.SubnetIds = serv.SubnetIds
+		serverResponse, serverr = serverin.GetServersFromSubnet(authinpt)
+	} else if serv.VpcIds != nil {
+		serverin.VpcIds = serv.VpcIds
+		serverResponse, serverr = serverin.GetServersFromNetwork(authinpt)
+	} else {
+		serverResponse, serverr = serverin.GetAllServers(authinpt)
+	}
+	if serverr != nil {
+		return support.GetServerResponse{}, cloudyerror.FromAWS(serverr, "server", "")
+	}
+	return support.GetServerResponse{Servers: servers(authinpt.Region, serverResponse...), AwsResponse: serverResponse}, nil
+}
+
+// GetAllServers fetches the details of all the servers across all the regions of aws, the regions without servers are left out.
+// The regions are queried concurrently, the regions which fail (ex: not opted in) carry their error in place of failing the rest.
+func (p Provider) GetAllServers(ctx context.Context, serv *support.GetServersInput) ([]support.GetServerResponse, error) {
+
+	// Fetching list of regions to get details  of server across the account
+	regions, regerr := p.enabledRegions(ctx, serv.Cloud, "server")
+	if regerr != nil {
+		return nil, regerr
+	}
+
+	regionResponses := make([][]awsserver.ServerResponse, len(regions))
+	errs := p.eachRegion(ctx, serv.Cloud, regions, "server", func(index int, authinpt auth.EstablishConnectionInput) error {
+		serverin := awsserver.DescribeInstanceInput{GetRaw: serv.Cloud.GetRaw}
+		serverResponse, serverr := serverin.GetAllServers(authinpt)
+		regionResponses[index] = serverResponse
+		return serverr
+	})
+
+	serverResponse := make([]support.GetServerResponse, 0)
+	for index, region := range regions {
+		regionErr := regionError(errs, region)
+		if len(regionResponses[index]) != 0 || regionErr != nil {
+			serverResponse = append(serverResponse, support.GetServerResponse{Region: region, Servers: servers(region, regionResponses[index]...), AwsResponse: regionResponses[index], Error: regionErr})
+		}
+	}
+	return serverResponse, allFailed(regions, errs)
+}
+
+// UpdateServers updates the servers (start/stop, resize etc.) in aws, the settings of the servers before and after the update are part of the response.
+func (p Provider) UpdateServers(ctx context.Context, serv *support.UpdateServersInput) (support.UpdateServersResponse, error) {
+
+	authinpt := p.connection(ctx, serv.Cloud, "ec2")
+
+	serverin := awsserver.UpdateServerInput{InstanceIds: serv.InstanceIds, Action: serv.Action, InstanceType: serv.Flavor, UserData: serv.UserData,
+		SecurityGroupIds: serv.SecurityGroupIds, GetRaw: serv.Cloud.GetRaw}
+	if serv.Cloud.DryRun {
+		plan, planErr := serverin.PlanUpdateServer(authinpt)
+		if planErr != nil {
+			return support.UpdateServersResponse{}, cloudyerror.FromAWS(planErr, "server", "")
+		}
+		return support.UpdateServersResponse{Plan: &plan}, nil
+	}
+	response, err := serverin.UpdateServer(authinpt)
+	if err != nil && len(response) == 0 {
+		return support.UpdateServersResponse{}, cloudyerror.FromAWS(err, "server", "")
+	}
+	// the update rolled back is reported along with the error, the response carries the details of the rollback.
+	return support.UpdateServersResponse{Servers: servers(authinpt.Region, response...), Changes: serverChanges(response), AwsResponse: response},
+		cloudyerror.FromAWS(err, "server", "")
+}
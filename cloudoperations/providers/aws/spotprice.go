@@ -0,0 +1,31 @@
+package awsprovider
+
+import (
+	"context"
+
+	awsops "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// GetSpotPrices fetches the current spot prices of the flavors passed in each of the zones selected, the cheapest are first.
+func (p Provider) GetSpotPrices(ctx context.Context, spot *support.GetSpotPricesInput) (support.SpotPriceResponse, error) {
+
+	authinpt := p.connection(ctx, spot.Cloud, "ec2")
+
+	spotin := awsops.SpotPriceInput{}
+	spotin.InstanceTypes = spot.Flavors
+	spotin.Zones = spot.Zones
+	spotin.ProductDescription = spot.Product
+	spotin.GetRaw = spot.Cloud.GetRaw
+
+	response, err := spotin.GetSpotPrices(authinpt)
+	if err != nil {
+		return support.SpotPriceResponse{}, cloudyerror.FromAWS(err, "spotprice", "")
+	}
+	prices := support.SpotPriceResponse{AwsResponse: response}
+	for _, price := range response {
+		prices.SpotPrices = append(prices.SpotPrices, spotPriceFromResponse(authinpt.Region, price))
+	}
+	return prices, nil
+}
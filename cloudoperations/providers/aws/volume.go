@@ -0,0 +1,228 @@
+package awsprovider
+
+import (
+	"context"
+
+	awsops "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// CreateVolume creates the ebs volume in the zone passed, either of the size passed or out of the snapshot passed.
+func (p Provider) CreateVolume(ctx context.Context, vol *support.CreateVolumeInput) (support.VolumeResponse, error) {
+
+	authinpt := p.connection(ctx, vol.Cloud, "ec2")
+
+	volumein := awsops.VolumeInput{}
+	volumein.Name = vol.Name
+	volumein.Zone = vol.Zone
+	volumein.Size = vol.Size
+	volumein.Type = vol.Type
+	volumein.Iops = vol.Iops
+	volumein.Encrypted = vol.Encrypted
+	volumein.KmsKeyId = vol.KmsKeyID
+	volumein.SnapshotId = vol.SnapshotID
+	volumein.GetRaw = vol.Cloud.GetRaw
+
+	if vol.Cloud.DryRun {
+		plan, planErr := volumein.PlanCreateVolume(authinpt)
+		if planErr != nil {
+			return support.VolumeResponse{}, cloudyerror.FromAWS(planErr, "volume", "")
+		}
+		return support.VolumeResponse{Plan: &plan}, nil
+	}
+	response, err := volumein.CreateVolume(authinpt)
+	if err != nil {
+		return support.VolumeResponse{}, cloudyerror.FromAWS(err, "volume", "")
+	}
+	return volumeResponse(authinpt.Region, "", response), nil
+}
+
+// GetVolumes fetches the volumes selected, the ones attached to the server or all the ones in the region are fetched if the volumes are not passed.
+func (p Provider) GetVolumes(ctx context.Context, vol *support.GetVolumesInput) (support.VolumeResponse, error) {
+
+	authinpt := p.connection(ctx, vol.Cloud, "ec2")
+
+	volumein := awsops.VolumeInput{}
+	volumein.VolumeIds = vol.VolumeIDs
+	volumein.InstanceId = vol.ServerID
+	volumein.GetRaw = vol.Cloud.GetRaw
+
+	response, err := volumein.GetVolumes(authinpt)
+	if err != nil {
+		return support.VolumeResponse{}, cloudyerror.FromAWS(err, "volume", "")
+	}
+	return volumeResponse(authinpt.Region, "", response...), nil
+}
+
+// UpdateVolume attaches, detaches or resizes the volume selected as per the action passed.
+func (p Provider) UpdateVolume(ctx context.Context, vol *support.UpdateVolumeInput) (support.VolumeResponse, error) {
+
+	authinpt := p.connection(ctx, vol.Cloud, "ec2")
+
+	volumein := awsops.VolumeInput{}
+	if vol.VolumeID != "" {
+		volumein.VolumeIds = []string{vol.VolumeID}
+	}
+	volumein.Action = vol.Action
+	volumein.InstanceId = vol.ServerID
+	volumein.DeviceName = vol.DeviceName
+	volumein.Force = vol.Force
+	volumein.Size = vol.Size
+	volumein.Type = vol.Type
+	volumein.Iops = vol.Iops
+	volumein.GetRaw = vol.Cloud.GetRaw
+
+	if vol.Cloud.DryRun {
+		plan, planErr := volumein.PlanUpdateVolume(authinpt)
+		if planErr != nil {
+			return support.VolumeResponse{}, cloudyerror.FromAWS(planErr, "volume", vol.VolumeID)
+		}
+		return support.VolumeResponse{Plan: &plan}, nil
+	}
+	response, err := volumein.UpdateVolume(authinpt)
+	if err != nil {
+		return support.VolumeResponse{}, cloudyerror.FromAWS(err, "volume", vol.VolumeID)
+	}
+	return volumeResponse(authinpt.Region, "", response), nil
+}
+
+// DeleteVolume deletes the volumes selected, the ones deleted before the failure are returned along with the error.
+func (p Provider) DeleteVolume(ctx context.Context, vol *support.DeleteVolumeInput) (support.VolumeResponse, error) {
+
+	authinpt := p.connection(ctx, vol.Cloud, "ec2")
+
+	volumein := awsops.VolumeInput{}
+	volumein.VolumeIds = vol.VolumeIDs
+
+	if vol.Cloud.DryRun {
+		plan, planErr := volumein.PlanDeleteVolume(authinpt)
+		if planErr != nil {
+			return support.VolumeResponse{}, cloudyerror.FromAWS(planErr, "volume", "")
+		}
+		return support.VolumeResponse{Plan: &plan}, nil
+	}
+	response, err := volumein.DeleteVolume(authinpt)
+	deleted := volumeResponse(authinpt.Region, deletedState, response...)
+	if err != nil {
+		return deleted, cloudyerror.FromAWS(err, "volume", "")
+	}
+	return deleted, nil
+}
+
+// CreateSnapshot captures the snapshot of the volume passed.
+func (p Provider) CreateSnapshot(ctx context.Context, snap *support.CreateSnapshotInput) (support.SnapshotResponse, error) {
+
+	authinpt := p.connection(ctx, snap.Cloud, "ec2")
+
+	snapshotin := awsops.SnapshotInput{}
+	snapshotin.VolumeId = snap.VolumeID
+	snapshotin.Name = snap.Name
+	snapshotin.Description = snap.Description
+	snapshotin.GetRaw = snap.Cloud.GetRaw
+
+	if snap.Cloud.DryRun {
+		plan, planErr := snapshotin.PlanCreateSnapshot(authinpt)
+		if planErr != nil {
+			return support.SnapshotResponse{}, cloudyerror.FromAWS(planErr, "snapshot", "")
+		}
+		return support.SnapshotResponse{Plan: &plan}, nil
+	}
+	response, err := snapshotin.CreateSnapshot(authinpt)
+	if err != nil {
+		return support.SnapshotResponse{}, cloudyerror.FromAWS(err, "snapshot", "")
+	}
+	return snapshotResponse(authinpt.Region, "", response), nil
+}
+
+// GetSnapshots fetches the snapshots selected, the ones of the volume or all the ones in the region are fetched if the snapshots are not passed.
+func (p Provider) GetSnapshots(ctx context.Context, snap *support.GetSnapshotsInput) (support.SnapshotResponse, error) {
+
+	authinpt := p.connection(ctx, snap.Cloud, "ec2")
+
+	snapshotin := awsops.SnapshotInput{}
+	snapshotin.SnapshotIds = snap.SnapshotIDs
+	snapshotin.VolumeId = snap.VolumeID
+	snapshotin.GetRaw = snap.Cloud.GetRaw
+
+	response, err := snapshotin.GetSnapshots(authinpt)
+	if err != nil {
+		return support.SnapshotResponse{}, cloudyerror.FromAWS(err, "snapshot", "")
+	}
+	return snapshotResponse(authinpt.Region, "", response...), nil
+}
+
+// DeleteSnapshot deletes the snapshots selected, the ones deleted before the failure are returned along with the error.
+func (p Provider) DeleteSnapshot(ctx context.Context, snap *support.DeleteSnapshotInput) (support.SnapshotResponse, error) {
+
+	authinpt := p.connection(ctx, snap.Cloud, "ec2")
+
+	snapshotin := awsops.SnapshotInput{}
+	snapshotin.SnapshotIds = snap.SnapshotIDs
+
+	if snap.Cloud.DryRun {
+		plan, planErr := snapshotin.PlanDeleteSnapshot(authinpt)
+		if planErr != nil {
+			return support.SnapshotResponse{}, cloudyerror.FromAWS(planErr, "snapshot", "")
+		}
+		return support.SnapshotResponse{Plan: &plan}, nil
+	}
+	response, err := snapshotin.DeleteSnapshot(authinpt)
+	deleted := snapshotResponse(authinpt.Region, deletedState, response...)
+	if err != nil {
+		return deleted, cloudyerror.FromAWS(err, "snapshot", "")
+	}
+	return deleted, nil
+}
+
+// RestoreSnapshot creates the volume out of the snapshot passed.
+func (p Provider) RestoreSnapshot(ctx context.Context, snap *support.RestoreSnapshotInput) (support.VolumeResponse, error) {
+
+	authinpt := p.connection(ctx, snap.Cloud, "ec2")
+
+	snapshotin := awsops.SnapshotInput{}
+	if snap.SnapshotID != "" {
+		snapshotin.SnapshotIds = []string{snap.SnapshotID}
+	}
+	snapshotin.Volume = awsops.VolumeInput{Name: snap.Name, Zone: snap.Zone, Size: snap.Size, Type: snap.Type, Iops: snap.Iops}
+	snapshotin.GetRaw = snap.Cloud.GetRaw
+
+	if snap.Cloud.DryRun {
+		plan, planErr := snapshotin.PlanRestoreSnapshot(authinpt)
+		if planErr != nil {
+			return support.VolumeResponse{}, cloudyerror.FromAWS(planErr, "snapshot", snap.SnapshotID)
+		}
+		return support.VolumeResponse{Plan: &plan}, nil
+	}
+	response, err := snapshotin.RestoreSnapshot(authinpt)
+	if err != nil {
+		return support.VolumeResponse{}, cloudyerror.FromAWS(err, "snapshot", snap.SnapshotID)
+	}
+	return volumeResponse(authinpt.Region, "", response), nil
+}
+
+// volumeResponse converts the volumes of aws to the response, the state passed is set on each of them if it is not empty.
+func volumeResponse(region, state string, responses ...awsops.VolumeResponse) support.VolumeResponse {
+	response := support.VolumeResponse{AwsResponse: responses}
+	for _, volume := range responses {
+		converted := volumeFromResponse(region, volume)
+		if state != "" {
+			converted.State = state
+		}
+		response.Volumes = append(response.Volumes, converted)
+	}
+	return response
+}
+
+// snapshotResponse converts the snapshots of aws to the response, the state passed is set on each of them if it is not empty.
+func snapshotResponse(region, state string, responses ...awsops.SnapshotResponse) support.SnapshotResponse {
+	response := support.SnapshotResponse{AwsResponse: responses}
+	for _, snapshot := range responses {
+		converted := snapshotFromResponse(region, snapshot)
+		if state != "" {
+			converted.State = state
+		}
+		response.Snapshots = append(response.Snapshots, converted)
+	}
+	return response
+}
@@ -0,0 +1,87 @@
+package gcpprovider
+
+import (
+	"context"
+
+	gcp "github.com/nikhilsbhat/neuron-cloudy/cloud/gcp/operations"
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// The addresses of gcp are the static external addresses of the region set on the cloud, they are identified by their names
+// and attached to the servers as the access config of their network interfaces.
+
+// AllocateAddress reserves the static external address and attaches it to the server passed, if any.
+func (p Provider) AllocateAddress(ctx context.Context, addr *support.AllocateAddressInput) (support.AddressResponse, error) {
+
+	address := p.addressInput(ctx, addr.ProjectID, addr.Cloud)
+	address.Name = addr.Name
+	address.Instance = addr.ServerID
+	address.Zone = addr.Zone
+	address.NetworkInterface = addr.NetworkInterfaceID
+	resp, err := address.AllocateAddress(addr.Cloud.Client)
+	if err != nil {
+		return support.AddressResponse{}, cloudyerror.FromGCP(err, "address", addr.Name)
+	}
+	return addressResponse(resp), nil
+}
+
+// GetAddresses fetches the addresses selected, the ones used by the server or all the ones of the region are fetched if the addresses are not passed.
+func (p Provider) GetAddresses(ctx context.Context, addr *support.GetAddressesInput) (support.AddressResponse, error) {
+
+	address := p.addressInput(ctx, addr.ProjectID, addr.Cloud)
+	address.Names = addr.AddressIDs
+	address.Instance = addr.ServerID
+	resp, err := address.GetAddresses(addr.Cloud.Client)
+	if err != nil {
+		return support.AddressResponse{}, cloudyerror.FromGCP(err, "address", "")
+	}
+	return addressResponse(resp...), nil
+}
+
+// UpdateAddress attaches or detaches the address selected as per the action passed.
+func (p Provider) UpdateAddress(ctx context.Context, addr *support.UpdateAddressInput) (support.AddressResponse, error) {
+
+	address := p.addressInput(ctx, addr.ProjectID, addr.Cloud)
+	if addr.AddressID != "" {
+		address.Names = []string{addr.AddressID}
+	}
+	address.Action = addr.Action
+	address.Instance = addr.ServerID
+	address.Zone = addr.Zone
+	address.NetworkInterface = addr.NetworkInterfaceID
+	resp, err := address.UpdateAddress(addr.Cloud.Client)
+	if err != nil {
+		return support.AddressResponse{}, cloudyerror.FromGCP(err, "address", addr.AddressID)
+	}
+	return addressResponse(resp), nil
+}
+
+// ReleaseAddress releases the addresses selected, the ones released before the failure are returned along with the error.
+func (p Provider) ReleaseAddress(ctx context.Context, addr *support.ReleaseAddressInput) (support.AddressResponse, error) {
+
+	address := p.addressInput(ctx, addr.ProjectID, addr.Cloud)
+	address.Names = addr.AddressIDs
+	resp, err := address.ReleaseAddress(addr.Cloud.Client)
+	if err != nil {
+		return addressResponse(resp...), cloudyerror.FromGCP(err, "address", "")
+	}
+	return addressResponse(resp...), nil
+}
+
+func (p Provider) addressInput(ctx context.Context, project string, cloud cmn.Cloud) *gcp.AddressInput {
+	address := new(gcp.AddressInput)
+	address.ProjectID = project
+	address.Region = cloud.Region
+	address.GetRaw = cloud.GetRaw
+	address.Context = ctx
+	address.Retry = cloud.Retry
+	address.Wait = cloud.Wait
+	return address
+}
+
+// addressResponse converts the addresses of gcp to the response.
+func addressResponse(responses ...gcp.AddressResponse) support.AddressResponse {
+	return support.AddressResponse{Addresses: addresses(responses...), GCPResponse: responses}
+}
@@ -0,0 +1,26 @@
+package gcpprovider
+
+import (
+	"context"
+
+	gcp "github.com/nikhilsbhat/neuron-cloudy/cloud/gcp/operations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// GetClusters fetches the details of the kubernetes clusters passed, or all the clusters in the regions passed from gcp.
+func (p Provider) GetClusters(ctx context.Context, clust *support.GetClusterInput) (support.ClusterResponse, error) {
+
+	getCluster := new(gcp.GetClusterInput)
+	getCluster.ClusterName = clust.ClusterName
+	getCluster.ProjectID = clust.ProjectID
+	getCluster.Regions = clust.Regions
+	getCluster.GetRaw = clust.Cloud.GetRaw
+	getCluster.Context = ctx
+	getCluster.Retry = clust.Cloud.Retry
+	resp, err := getCluster.GetClusters(clust.Cloud.Client)
+	if err != nil {
+		return support.ClusterResponse{}, cloudyerror.FromGCP(err, "cluster", "")
+	}
+	return support.ClusterResponse{Clusters: clusters(resp...), GCPResponse: resp}, nil
+}
@@ -0,0 +1,66 @@
+package gcpprovider
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	gcp "github.com/nikhilsbhat/neuron-cloudy/cloud/gcp/operations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// CreateNetwork is not yet implemented for gcp.
+func (p Provider) CreateNetwork(ctx context.Context, net *support.CreateNetworkInput) (support.CreateNetworkResponse, error) {
+	return support.CreateNetworkResponse{}, support.NotImplemented(p.Name(), "CreateNetwork")
+}
+
+// DeleteNetwork is not yet implemented for gcp.
+func (p Provider) DeleteNetwork(ctx context.Context, net *support.DeleteNetworkInput) (support.DeleteNetworkResponse, error) {
+	return support.DeleteNetworkResponse{}, support.NotImplemented(p.Name(), "DeleteNetwork")
+}
+
+// GetNetworks fetches the details of the network passed from gcp.
+func (p Provider) GetNetworks(ctx context.Context, net *support.GetNetworksInput) (support.GetNetworksResponse, error) {
+
+	fmt.Fprintf(os.Stdout, "%v\n", "This is an alpha resource of Google Cloud so do the support, watchout for the output")
+	if len(net.NetworkID) == 0 {
+		return support.GetNetworksResponse{}, cloudyerror.New(cloudyerror.InvalidInput, "You have not passed the network of which the details has to be fetched")
+	}
+	getNetwork := new(gcp.GetNetworkInput)
+	getNetwork.ProjectID = net.ProjectID
+	getNetwork.Context = ctx
+	getNetwork.Retry = net.Cloud.Retry
+	getNetwork.NetworkID = net.NetworkID[0]
+	resp, err := getNetwork.GetNetwork(net.Cloud.Client)
+	if err != nil {
+		return support.GetNetworksResponse{}, cloudyerror.FromGCP(err, "network", "")
+	}
+	return support.GetNetworksResponse{Networks: networks(resp...), GCPResponse: resp}, nil
+}
+
+// GetAllNetworks fetches the details of all the networks in the project of gcp.
+func (p Provider) GetAllNetworks(ctx context.Context, net *support.GetNetworksInput) ([]support.GetNetworksResponse, error) {
+
+	fmt.Fprintf(os.Stdout, "%v\n", "This is an alpha resource of Google Cloud so do the support, watchout for the output")
+	getNetwork := new(gcp.GetNetworkInput)
+	getNetwork.ProjectID = net.ProjectID
+	getNetwork.Context = ctx
+	getNetwork.Retry = net.Cloud.Retry
+	resp, err := getNetwork.GetNetworks(net.Cloud.Client)
+	if err != nil {
+		return nil, cloudyerror.FromGCP(err, "network", "")
+	}
+	networkResponse := make([]support.GetNetworksResponse, 0)
+	return append(networkResponse, support.GetNetworksResponse{Networks: networks(resp...), GCPResponse: resp}), nil
+}
+
+// GetSubnets is not yet implemented for gcp.
+func (p Provider) GetSubnets(ctx context.Context, sub *support.GetNetworksInput) (support.GetSubnetsResponse, error) {
+	return support.GetSubnetsResponse{}, support.NotImplemented(p.Name(), "GetSubnets")
+}
+
+// UpdateNetwork is not yet implemented for gcp.
+func (p Provider) UpdateNetwork(ctx context.Context, net *support.UpdateNetworkInput) (support.UpdateNetworkResponse, error) {
+	return support.UpdateNetworkResponse{}, support.NotImplemented(p.Name(), "UpdateNetwork")
+}
@@ -0,0 +1,19 @@
+// Package gcpprovider is the built-in provider of neuron-cloudy for the cloud gcp.
+// It registers itself with the support package and routes the requests of cloudoperations to cloud/gcp/operations.
+package gcpprovider
+
+import (
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+)
+
+// Provider implements the capabilities network (only fetching), cluster, securitygroup and address for gcp.
+type Provider struct{}
+
+func init() {
+	support.Register(Provider{})
+}
+
+// Name returns the name with which the provider is registered.
+func (p Provider) Name() string {
+	return "gcp"
+}
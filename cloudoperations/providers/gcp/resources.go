@@ -0,0 +1,154 @@
+package gcpprovider
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	gcp "github.com/nikhilsbhat/neuron-cloudy/cloud/gcp/operations"
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/container/v1"
+)
+
+// The functions below convert the responses of cloud/gcp/operations to the resources common to all the clouds.
+// The responses holding the unfiltered output of gcp are converted from the resources of gcp they carry, and the same is set in Raw.
+
+// parseTime parses the time in the format gcp represents it (RFC3339), nil is returned if it is not.
+func parseTime(value string) *time.Time {
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
+
+func networks(responses ...gcp.NetworkResponse) []cmn.Network {
+	converted := make([]cmn.Network, 0, len(responses))
+	for _, response := range responses {
+		if response.GetNetworkRaw != nil {
+			for _, network := range response.GetNetworkRaw {
+				converted = append(converted, networkFromCompute(network))
+			}
+			continue
+		}
+		network := cmn.Network{Resource: cmn.Resource{ID: strconv.FormatUint(response.ID, 10), Name: response.Name, Cloud: "gcp"}}
+		if response.IPv4Range != "" {
+			network.CIDRs = []string{response.IPv4Range}
+		}
+		network.CreatedAt = parseTime(response.Duration)
+		network.Extras = map[string]interface{}{
+			"selflink":              response.SelfLink,
+			"autocreatesubnetworks": response.AutoCreateSubnetworks,
+			"subnetworks":           response.Subnetworks,
+			"peerings":              response.Peerings,
+			"routingconfig":         response.RoutingConfig,
+		}
+		converted = append(converted, network)
+	}
+	return converted
+}
+
+func networkFromCompute(net *compute.Network) cmn.Network {
+	network := cmn.Network{Resource: cmn.Resource{ID: strconv.FormatUint(net.Id, 10), Name: net.Name, Cloud: "gcp"}}
+	if net.IPv4Range != "" {
+		network.CIDRs = []string{net.IPv4Range}
+	}
+	network.CreatedAt = parseTime(net.CreationTimestamp)
+	network.Raw = net
+	return network
+}
+
+func clusters(responses ...gcp.ClusterResponse) []cmn.Cluster {
+	converted := make([]cmn.Cluster, 0, len(responses))
+	for _, response := range responses {
+		if response.GetClustersRaw != nil {
+			for _, cluster := range response.GetClustersRaw {
+				converted = append(converted, clusterFromContainer(cluster))
+			}
+			continue
+		}
+		cluster := cmn.Cluster{Resource: cmn.Resource{ID: response.ClusterName, Name: response.ClusterName, Cloud: "gcp"}}
+		cluster.CreatedAt = parseTime(response.CreateTime)
+		cluster.NetworkID = response.ClusterNetwork
+		cluster.Version = response.CurrentMasterVersion
+		cluster.Endpoint = response.KubeEndpoint
+		cluster.Locations = response.ClusterLocation
+		cluster.Extras = map[string]interface{}{
+			"nodeversion":       response.CurrentNodeVersion,
+			"nodepools":         response.ClusterNodePool,
+			"instancegroupurls": response.ClusterInstanceGroupUrls,
+			"masterauth":        response.ClusterAuth,
+		}
+		converted = append(converted, cluster)
+	}
+	return converted
+}
+
+func clusterFromContainer(clust *container.Cluster) cmn.Cluster {
+	cluster := cmn.Cluster{Resource: cmn.Resource{ID: clust.Name, Name: clust.Name, Cloud: "gcp", Region: clust.Location, State: clust.Status}}
+	cluster.Tags = clust.ResourceLabels
+	cluster.CreatedAt = parseTime(clust.CreateTime)
+	cluster.NetworkID = clust.Network
+	cluster.Version = clust.CurrentMasterVersion
+	cluster.Endpoint = clust.Endpoint
+	cluster.Locations = clust.Locations
+	cluster.Raw = clust
+	return cluster
+}
+
+func securityGroups(responses ...gcp.FirewallResponse) []cmn.SecurityGroup {
+	converted := make([]cmn.SecurityGroup, 0, len(responses))
+	for _, response := range responses {
+		group := cmn.SecurityGroup{Resource: cmn.Resource{ID: response.Name, Name: response.Name, Cloud: "gcp"}}
+		if response.FirewallsRaw != nil {
+			for _, firewall := range response.FirewallsRaw {
+				group.NetworkID = firewall.Network
+			}
+			group.Raw = response.FirewallsRaw
+			converted = append(converted, group)
+			continue
+		}
+		group.NetworkID = response.Network
+		group.Description = response.Description
+		for _, rule := range response.Rules {
+			group.Rules = append(group.Rules, cmn.SecurityRule{
+				Direction: rule.Direction, Protocol: rule.Protocol, Ports: rule.Ports, CIDRs: rule.Cidrs, SecurityGroupIDs: rule.SecurityGroups, Description: rule.Description,
+			})
+		}
+		group.Extras = map[string]interface{}{"firewalls": response.Firewalls}
+		converted = append(converted, group)
+	}
+	return converted
+}
+
+func addresses(responses ...gcp.AddressResponse) []cmn.Address {
+	converted := make([]cmn.Address, 0, len(responses))
+	for _, response := range responses {
+		if raw := response.AddressRaw; raw != nil {
+			address := cmn.Address{Resource: cmn.Resource{ID: raw.Name, Name: raw.Name, Cloud: "gcp", Region: lastSegment(raw.Region), State: raw.Status}}
+			address.PublicIP = raw.Address
+			if len(raw.Users) != 0 {
+				address.ServerID = lastSegment(raw.Users[0])
+			}
+			address.CreatedAt = parseTime(raw.CreationTimestamp)
+			address.Raw = raw
+			converted = append(converted, address)
+			continue
+		}
+		address := cmn.Address{Resource: cmn.Resource{ID: response.Name, Name: response.Name, Cloud: "gcp", Region: response.Region, State: response.Status}}
+		address.PublicIP = response.Address
+		if len(response.Users) != 0 {
+			address.ServerID = lastSegment(response.Users[0])
+		}
+		address.CreatedAt = parseTime(response.CreationTimestamp)
+		address.Extras = map[string]interface{}{"id": strconv.FormatUint(response.ID, 10)}
+		converted = append(converted, address)
+	}
+	return converted
+}
+
+// lastSegment returns the last segment of the link of the resource of gcp, which is its name ex: .../regions/us-central1 gives us-central1.
+func lastSegment(link string) string {
+	return link[strings.LastIndex(link, "/")+1:]
+}
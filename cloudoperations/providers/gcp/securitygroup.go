@@ -0,0 +1,95 @@
+package gcpprovider
+
+import (
+	"context"
+
+	gcp "github.com/nikhilsbhat/neuron-cloudy/cloud/gcp/operations"
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// The security groups of gcp are made of its firewall rules, a security group is the set of firewall rules
+// of the network which target the instances tagged with the name of the group. Hence the groups are identified by their names.
+
+// CreateSecurityGroup creates the firewall rules of the security group in the network passed.
+func (p Provider) CreateSecurityGroup(ctx context.Context, sec *support.CreateSecurityGroupInput) (support.SecurityGroupResponse, error) {
+
+	firewall := p.firewallInput(ctx, sec.ProjectID, sec.Cloud)
+	firewall.Name = sec.Name
+	firewall.Network = sec.NetworkID
+	firewall.Description = sec.Description
+	firewall.Rules = firewallRules(sec.Rules)
+	resp, err := firewall.CreateSecurityGroup(sec.Cloud.Client)
+	if err != nil {
+		return support.SecurityGroupResponse{}, cloudyerror.FromGCP(err, "securitygroup", sec.Name)
+	}
+	return securityGroupResponse("", resp), nil
+}
+
+// GetSecurityGroups fetches the security groups selected, the ones of the network are fetched if the security groups are not passed.
+func (p Provider) GetSecurityGroups(ctx context.Context, sec *support.GetSecurityGroupsInput) (support.SecurityGroupResponse, error) {
+
+	firewall := p.firewallInput(ctx, sec.ProjectID, sec.Cloud)
+	firewall.Names = sec.SecurityGroupIDs
+	firewall.Network = sec.NetworkID
+	resp, err := firewall.GetSecurityGroups(sec.Cloud.Client)
+	if err != nil {
+		return support.SecurityGroupResponse{}, cloudyerror.FromGCP(err, "securitygroup", "")
+	}
+	return securityGroupResponse("", resp...), nil
+}
+
+// UpdateSecurityGroup adds, revokes or sets the firewall rules of the security groups selected.
+func (p Provider) UpdateSecurityGroup(ctx context.Context, sec *support.UpdateSecurityGroupInput) (support.SecurityGroupResponse, error) {
+
+	firewall := p.firewallInput(ctx, sec.ProjectID, sec.Cloud)
+	firewall.Names = sec.SecurityGroupIDs
+	firewall.Action = sec.Action
+	firewall.Rules = firewallRules(sec.Rules)
+	resp, err := firewall.UpdateSecurityGroup(sec.Cloud.Client)
+	if err != nil {
+		return securityGroupResponse("", resp...), cloudyerror.FromGCP(err, "securitygroup", "")
+	}
+	return securityGroupResponse("", resp...), nil
+}
+
+// DeleteSecurityGroup deletes the firewall rules of the security groups selected, the ones deleted before the failure are returned along with the error.
+func (p Provider) DeleteSecurityGroup(ctx context.Context, sec *support.DeleteSecurityGroupInput) (support.SecurityGroupResponse, error) {
+
+	firewall := p.firewallInput(ctx, sec.ProjectID, sec.Cloud)
+	firewall.Names = sec.SecurityGroupIDs
+	resp, err := firewall.DeleteSecurityGroup(sec.Cloud.Client)
+	if err != nil {
+		return securityGroupResponse("deleted", resp...), cloudyerror.FromGCP(err, "securitygroup", "")
+	}
+	return securityGroupResponse("deleted", resp...), nil
+}
+
+func (p Provider) firewallInput(ctx context.Context, project string, cloud cmn.Cloud) *gcp.FirewallInput {
+	firewall := new(gcp.FirewallInput)
+	firewall.ProjectID = project
+	firewall.GetRaw = cloud.GetRaw
+	firewall.Context = ctx
+	firewall.Retry = cloud.Retry
+	return firewall
+}
+
+func firewallRules(rules []cmn.SecurityRule) []gcp.FirewallRule {
+	converted := make([]gcp.FirewallRule, 0, len(rules))
+	for _, rule := range rules {
+		converted = append(converted, gcp.FirewallRule{
+			Direction: rule.Direction, Protocol: rule.Protocol, Ports: rule.Ports, Cidrs: rule.CIDRs, SecurityGroups: rule.SecurityGroupIDs, Description: rule.Description,
+		})
+	}
+	return converted
+}
+
+// securityGroupResponse converts the security groups of gcp to the response, the state passed is set on each of them.
+func securityGroupResponse(state string, responses ...gcp.FirewallResponse) support.SecurityGroupResponse {
+	response := support.SecurityGroupResponse{SecurityGroups: securityGroups(responses...), GCPResponse: responses}
+	for i := range response.SecurityGroups {
+		response.SecurityGroups[i].State = state
+	}
+	return response
+}
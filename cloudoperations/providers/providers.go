@@ -0,0 +1,30 @@
+// Package providers registers the built-in providers of neuron-cloudy with the support package.
+// Importing this package makes the clouds aws, azure, gcp and openstack available to cloudoperations,
+// in-house providers can be added along with these by calling support.Register.
+package providers
+
+import (
+	// registers the provider for aws.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers/aws"
+	// registers the provider for gcp.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers/gcp"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+)
+
+// Placeholder is the provider of the clouds which are known to neuron-cloudy but have not implemented any capability yet.
+// Every request routed to it is reported as capability not implemented.
+// Ex: the security groups of azure are not reachable through cloudoperations, the rules of its network security groups
+// are available only with cloud/azure/interface/networkinterface and cannot reference other security groups.
+type Placeholder struct {
+	CloudName string
+}
+
+func init() {
+	support.Register(Placeholder{CloudName: "azure"})
+	support.Register(Placeholder{CloudName: "openstack"})
+}
+
+// Name returns the name with which the provider is registered.
+func (p Placeholder) Name() string {
+	return p.CloudName
+}
@@ -0,0 +1,258 @@
+package cloudoperations
+
+import "time"
+
+// The types below are the cloud neutral view of the resources, every provider fills these in its responses
+// so that the callers need not branch on the cloud to read them.
+
+// Resource holds the details common to the resources of all the clouds.
+type Resource struct {
+	// ID is the unique ID given by the cloud to the resource ex: vpc-0a1b2c (aws), 12345678 (gcp).
+	ID string `json:"id,omitempty"`
+	// Name of the resource.
+	Name string `json:"name,omitempty"`
+	// Cloud to which the resource belongs to.
+	Cloud string `json:"cloud,omitempty"`
+	// Region in which the resource resides, this is empty for the resources which are global.
+	Region string `json:"region,omitempty"`
+	// Zone in which the resource resides, this is empty for the resources which are not zonal.
+	Zone string `json:"zone,omitempty"`
+	// State of the resource as reported by the cloud ex: available, running, deleted.
+	State string `json:"state,omitempty"`
+	// Tags are the labels attached to the resource.
+	Tags map[string]string `json:"tags,omitempty"`
+	// CreatedAt is the time at which the resource was created, nil if the cloud did not report it.
+	CreatedAt *time.Time `json:"createdat,omitempty"`
+	// Extras holds the details which are specific to the cloud and has no place in the common fields.
+	Extras map[string]interface{} `json:"extras,omitempty"`
+	// Raw holds the unfiltered payload of the cloud for the resource, this is set only if GetRaw of the cloud is set.
+	// With GetRaw set the clouds may not fill the rest of the fields.
+	Raw interface{} `json:"raw,omitempty"`
+}
+
+// Network is the network (VPC, VNet etc.) of the cloud.
+type Network struct {
+	Resource
+	// CIDRs are the blocks of IP addresses of the network.
+	CIDRs []string `json:"cidrs,omitempty"`
+	// Subnets of the network.
+	Subnets []Subnet `json:"subnets,omitempty"`
+	// SecurityGroupIDs are the IDs of the security groups/firewalls of the network.
+	SecurityGroupIDs []string `json:"securitygroupids,omitempty"`
+	// SecurityGroups are the security groups/firewalls of the network, this is filled only by the clouds reporting their rules.
+	SecurityGroups []SecurityGroup `json:"securitygroups,omitempty"`
+}
+
+// SecurityGroup is the security group/firewall of the network.
+type SecurityGroup struct {
+	Resource
+	// NetworkID is the ID of the network the security group is part of.
+	NetworkID string `json:"networkid,omitempty"`
+	// Ports are the ports opened for the incoming traffic ex: 22, 8000-8080 for TCP and udp:53 for the rest, all is the rule opening every port.
+	Ports []string `json:"ports,omitempty"`
+	// Description of the security group.
+	Description string `json:"description,omitempty"`
+	// Rules are the ingress and egress rules of the security group, this is filled only by the clouds reporting their rules.
+	Rules []SecurityRule `json:"rules,omitempty"`
+}
+
+// SecurityRule is the rule of the security group/firewall allowing the traffic from/to its peers.
+type SecurityRule struct {
+	// Direction of the traffic the rule allows, either ingress or egress. Defaults to ingress.
+	Direction string `json:"direction,omitempty"`
+	// Protocol of the traffic the rule allows ex: tcp, udp, icmp or all.
+	Protocol string `json:"protocol,omitempty"`
+	// Ports allowed by the rule ex: 22, 8000-8080, the type and code for icmp ex: 3-4. All the ports are allowed if not set.
+	Ports string `json:"ports,omitempty"`
+	// CIDRs are the blocks of IP addresses from/to which the traffic is allowed.
+	CIDRs []string `json:"cidrs,omitempty"`
+	// SecurityGroupIDs are the security groups from/to which the traffic is allowed, these are the names of the groups in gcp.
+	SecurityGroupIDs []string `json:"securitygroupids,omitempty"`
+	// Description of the rule.
+	Description string `json:"description,omitempty"`
+}
+
+// Subnet is the subnetwork of the network.
+type Subnet struct {
+	Resource
+	// NetworkID is the ID of the network the subnet is part of.
+	NetworkID string `json:"networkid,omitempty"`
+	// CIDRs are the blocks of IP addresses of the subnet.
+	CIDRs []string `json:"cidrs,omitempty"`
+}
+
+// Server is the virtual machine of the cloud.
+type Server struct {
+	Resource
+	// Flavor is the type/size of the server ex: t2.micro.
+	Flavor string `json:"flavor,omitempty"`
+	// ImageID is the ID of the image from which the server was created.
+	ImageID string `json:"imageid,omitempty"`
+	// NetworkID is the ID of the network in which the server resides.
+	NetworkID string `json:"networkid,omitempty"`
+	// SubnetID is the ID of the subnet in which the server resides.
+	SubnetID string `json:"subnetid,omitempty"`
+	// PrivateIPs are the private IP addresses assigned to the server.
+	PrivateIPs []string `json:"privateips,omitempty"`
+	// PublicIPs are the public IP addresses assigned to the server.
+	PublicIPs []string `json:"publicips,omitempty"`
+	// Volumes are the volumes attached to the server.
+	Volumes []Volume `json:"volumes,omitempty"`
+}
+
+// ServerSettings are the settings of the server which could be changed by updating it.
+type ServerSettings struct {
+	// State of the server ex: running, stopped.
+	State string `json:"state,omitempty"`
+	// Flavor is the type/size of the server ex: t2.micro.
+	Flavor string `json:"flavor,omitempty"`
+	// TerminationProtection is set if the server is protected from being deleted.
+	TerminationProtection bool `json:"terminationprotection"`
+	// Monitoring is the state of the detailed monitoring of the server ex: enabled, disabled.
+	Monitoring string `json:"monitoring,omitempty"`
+	// SecurityGroupIDs are the IDs of the security groups/firewalls of the server.
+	SecurityGroupIDs []string `json:"securitygroupids,omitempty"`
+	// UserData is the script with which the server boots, it is reported only when it is changed.
+	UserData string `json:"userdata,omitempty"`
+}
+
+// ServerChange holds the settings of the server before and after it was updated.
+type ServerChange struct {
+	// ServerID is the ID of the server updated.
+	ServerID string `json:"serverid"`
+	// Before holds the settings the server had prior to the update.
+	Before ServerSettings `json:"before"`
+	// After holds the settings the server has once updated, they are same as Before if the update was rolled back.
+	After ServerSettings `json:"after"`
+}
+
+// Volume is the block storage volume of the cloud.
+type Volume struct {
+	Resource
+	// Size of the volume in GiB.
+	Size int64 `json:"size,omitempty"`
+	// Type of the volume ex: gp2, io1 (aws).
+	Type string `json:"type,omitempty"`
+	// Iops provisioned for the volume.
+	Iops int64 `json:"iops,omitempty"`
+	// Encrypted is set if the volume is encrypted.
+	Encrypted bool `json:"encrypted,omitempty"`
+	// ServerID is the ID of the server to which the volume is attached.
+	ServerID string `json:"serverid,omitempty"`
+	// DeviceName is the name of the device at which the volume is attached to the server.
+	DeviceName string `json:"devicename,omitempty"`
+	// Root is set if the volume is the root volume of the server.
+	Root bool `json:"root,omitempty"`
+	// DeleteWithServer is set if the volume is deleted along with the server it is attached to.
+	DeleteWithServer bool `json:"deletewithserver,omitempty"`
+	// SnapshotID is the ID of the snapshot out of which the volume was created.
+	SnapshotID string `json:"snapshotid,omitempty"`
+}
+
+// Snapshot is the point in time copy of the volume.
+type Snapshot struct {
+	Resource
+	// VolumeID is the ID of the volume of which the snapshot was created.
+	VolumeID string `json:"volumeid,omitempty"`
+	// Size of the volume captured in GiB.
+	Size int64 `json:"size,omitempty"`
+	// Encrypted is set if the snapshot is encrypted.
+	Encrypted bool `json:"encrypted,omitempty"`
+	// Description of the snapshot.
+	Description string `json:"description,omitempty"`
+}
+
+// Address is the static public IP address reserved with the cloud, it stays with the account until it is released ex: elastic ip (aws), static external address (gcp).
+type Address struct {
+	Resource
+	// PublicIP is the address reserved.
+	PublicIP string `json:"publicip,omitempty"`
+	// ServerID is the ID of the server with which the address is associated.
+	ServerID string `json:"serverid,omitempty"`
+	// NetworkInterfaceID is the ID of the network interface with which the address is associated.
+	NetworkInterfaceID string `json:"networkinterfaceid,omitempty"`
+	// PrivateIP is the private address of the server or network interface with which the address is associated.
+	PrivateIP string `json:"privateip,omitempty"`
+}
+
+// KeyPair is the ssh key pair with which the servers are logged into, the cloud keeps only its public key.
+type KeyPair struct {
+	Resource
+	// Fingerprint is the digest of the key as reported by the cloud.
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// PrivateKey is the PEM encoded private key of the key pair, it is set only in the response of the operation which generated the key pair
+	// as the cloud does not retain it, hence it has to be saved by the caller.
+	PrivateKey string `json:"privatekey,omitempty"`
+}
+
+// SpotPrice is the current hourly price of the spot servers of the flavor in the zone, the price changes with the spare capacity of the cloud.
+type SpotPrice struct {
+	// Flavor of the servers of which the price is ex: t2.micro(aws).
+	Flavor string `json:"flavor,omitempty"`
+	// Region and Zone in which the price is applicable.
+	Region string `json:"region,omitempty"`
+	Zone   string `json:"zone,omitempty"`
+	// Product is the platform of the servers of which the price is ex: Linux/UNIX, Windows.
+	Product string `json:"product,omitempty"`
+	// Price is the hourly price in USD.
+	Price float64 `json:"price"`
+	// Timestamp is the time from which the price is effective.
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	// Raw holds the unfiltered price as returned by the cloud, it is set only when GetRaw is set.
+	Raw interface{} `json:"raw,omitempty"`
+}
+
+// Image is the image of the server.
+type Image struct {
+	Resource
+	// Description of the image.
+	Description string `json:"description,omitempty"`
+	// Public is set if the image is publicly available.
+	Public bool `json:"public,omitempty"`
+}
+
+// LoadBalancer is the loadbalancer of the cloud.
+type LoadBalancer struct {
+	Resource
+	// Type of the loadbalancer ex: classic, application.
+	Type string `json:"type,omitempty"`
+	// DNSName is the DNS with which the loadbalancer is reached.
+	DNSName string `json:"dnsname,omitempty"`
+	// Scheme is either internal or internet-facing.
+	Scheme string `json:"scheme,omitempty"`
+	// NetworkID is the ID of the network in which the loadbalancer resides.
+	NetworkID string `json:"networkid,omitempty"`
+	// Listeners are the ports and protocols on which the loadbalancer listens.
+	Listeners []Listener `json:"listeners,omitempty"`
+}
+
+// Listener is the port and protocol on which the loadbalancer listens.
+type Listener struct {
+	// Port on which the loadbalancer listens ex: 80, 443.
+	Port int64 `json:"port,omitempty"`
+	// Protocol of the listener ex: HTTP, HTTPS.
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// Cluster is the managed kubernetes cluster of the cloud.
+type Cluster struct {
+	Resource
+	// NetworkID is the ID of the network in which the cluster resides.
+	NetworkID string `json:"networkid,omitempty"`
+	// Version is the version of kubernetes the master of cluster runs.
+	Version string `json:"version,omitempty"`
+	// Endpoint is where the API of the cluster is reachable.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Locations are the zones the nodes of the cluster are spread across.
+	Locations []string `json:"locations,omitempty"`
+}
+
+// DeletedResource is an entry of the log of the deletion, which records every resource deleted along with the one requested ex: the servers of the network force deleted.
+type DeletedResource struct {
+	// Kind of the resource deleted ex: server, loadbalancer, targetgroup, natgateway, networkinterface, elasticip, subnet, network.
+	Kind string `json:"kind"`
+	// ID of the resource deleted.
+	ID string `json:"id"`
+	// Status of the resource once it is deleted ex: terminated, deleted, released.
+	Status string `json:"status,omitempty"`
+}
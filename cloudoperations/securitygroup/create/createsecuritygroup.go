@@ -0,0 +1,48 @@
+package securitygroupcreate
+
+import (
+	"context"
+
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
+)
+
+// SecurityGroupResponse will return the filtered/unfiltered responses of variuos clouds on the security groups.
+type SecurityGroupResponse = support.SecurityGroupResponse
+
+// CreateSecurityGroup creates the security group along with its rules in the network and cloud passed.
+func (sec *CreateSecurityGroupInput) CreateSecurityGroup() (SecurityGroupResponse, error) {
+	return sec.CreateSecurityGroupWithContext(context.Background())
+}
+
+// CreateSecurityGroupWithContext is same as CreateSecurityGroup, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (sec *CreateSecurityGroupInput) CreateSecurityGroupWithContext(ctx context.Context) (SecurityGroupResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetSecurityGroupProvider(sec.Cloud.Name)
+	if err != nil {
+		return SecurityGroupResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, sec.Cloud.DryRun); err != nil {
+		return SecurityGroupResponse{}, err
+	}
+	securityin := support.CreateSecurityGroupInput(*sec)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response SecurityGroupResponse
+	err = support.Track(ctx, sec.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.CreateSecurityGroup(ctx, &securityin)
+		return support.SecurityGroupsCreated(sec.Cloud, response.SecurityGroups), opErr
+	})
+	return response, err
+}
+
+// New returns the new CreateSecurityGroupInput instance with empty values.
+func New() *CreateSecurityGroupInput {
+	sec := &CreateSecurityGroupInput{}
+	return sec
+}
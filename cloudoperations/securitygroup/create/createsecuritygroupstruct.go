@@ -0,0 +1,24 @@
+// Package securitygroupcreate makes the tool cloud agnostic with respect to creation of security groups.
+// The decision will be made here to route the request to respective package based on input.
+package securitygroupcreate
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// CreateSecurityGroupInput implements method CreateSecurityGroup and holds parameter for creating security group.
+type CreateSecurityGroupInput struct {
+	// Name of the security group that has to be created, in gcp this is also the network tag of the instances the rules apply to.
+	Name string `json:"name"`
+	// NetworkID is the ID of the network in which the security group has to be created, the name of the network in gcp.
+	NetworkID string `json:"networkid"`
+	// Description of the security group.
+	Description string `json:"description"`
+	// Rules are the ingress and egress rules of the security group.
+	Rules []cmn.SecurityRule `json:"rules"`
+	// ProjectID refers to the ID of the GCP project in which the security group has to be created.
+	ProjectID string
+	Cloud     cmn.Cloud
+}
+
+//Nothing much from this file. This file contains only the structs for securitygroup/create
@@ -0,0 +1,48 @@
+package securitygroupdelete
+
+import (
+	"context"
+
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
+)
+
+// SecurityGroupResponse will return the filtered/unfiltered responses of variuos clouds on the security groups.
+type SecurityGroupResponse = support.SecurityGroupResponse
+
+// DeleteSecurityGroup deletes the security groups selected along with their rules.
+func (sec *DeleteSecurityGroupInput) DeleteSecurityGroup() (SecurityGroupResponse, error) {
+	return sec.DeleteSecurityGroupWithContext(context.Background())
+}
+
+// DeleteSecurityGroupWithContext is same as DeleteSecurityGroup, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (sec *DeleteSecurityGroupInput) DeleteSecurityGroupWithContext(ctx context.Context) (SecurityGroupResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetSecurityGroupProvider(sec.Cloud.Name)
+	if err != nil {
+		return SecurityGroupResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, sec.Cloud.DryRun); err != nil {
+		return SecurityGroupResponse{}, err
+	}
+	securityin := support.DeleteSecurityGroupInput(*sec)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response SecurityGroupResponse
+	err = support.Track(ctx, sec.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.DeleteSecurityGroup(ctx, &securityin)
+		return support.SecurityGroupsDeleted(response.SecurityGroups), opErr
+	})
+	return response, err
+}
+
+// New returns the new DeleteSecurityGroupInput instance with empty values.
+func New() *DeleteSecurityGroupInput {
+	sec := &DeleteSecurityGroupInput{}
+	return sec
+}
@@ -0,0 +1,18 @@
+// Package securitygroupdelete makes the tool cloud agnostic with respect to deletion of security groups.
+// The decision will be made here to route the request to respective package based on input.
+package securitygroupdelete
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// DeleteSecurityGroupInput implements method DeleteSecurityGroup and holds parameter for deleting security groups.
+type DeleteSecurityGroupInput struct {
+	// SecurityGroupIDs are the IDs of the security groups to be deleted, the names of the groups in gcp.
+	SecurityGroupIDs []string `json:"securitygroupids"`
+	// ProjectID refers to the ID of the GCP project in which the security groups exists.
+	ProjectID string
+	Cloud     cmn.Cloud
+}
+
+//Nothing much from this file. This file contains only the structs for securitygroup/delete
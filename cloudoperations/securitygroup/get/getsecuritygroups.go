@@ -0,0 +1,36 @@
+package securitygroupget
+
+import (
+	"context"
+
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+)
+
+// SecurityGroupResponse will return the filtered/unfiltered responses of variuos clouds on the security groups.
+type SecurityGroupResponse = support.SecurityGroupResponse
+
+// GetSecurityGroups fetches the security groups selected, the ones of the network are fetched if the security groups are not passed.
+func (sec *GetSecurityGroupsInput) GetSecurityGroups() (SecurityGroupResponse, error) {
+	return sec.GetSecurityGroupsWithContext(context.Background())
+}
+
+// GetSecurityGroupsWithContext is same as GetSecurityGroups, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (sec *GetSecurityGroupsInput) GetSecurityGroupsWithContext(ctx context.Context) (SecurityGroupResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetSecurityGroupProvider(sec.Cloud.Name)
+	if err != nil {
+		return SecurityGroupResponse{}, err
+	}
+	securityin := support.GetSecurityGroupsInput(*sec)
+	return provider.GetSecurityGroups(ctx, &securityin)
+}
+
+// New returns the new GetSecurityGroupsInput instance with empty values.
+func New() *GetSecurityGroupsInput {
+	sec := &GetSecurityGroupsInput{}
+	return sec
+}
@@ -0,0 +1,20 @@
+// Package securitygroupget makes the tool cloud agnostic in fetching the details of security groups.
+// The decision will be made here to route the request to respective package based on input.
+package securitygroupget
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// GetSecurityGroupsInput implements method GetSecurityGroups and holds parameter for fetching security groups.
+type GetSecurityGroupsInput struct {
+	// SecurityGroupIDs are the IDs of the security groups of which information has to be retrieved, the names of the groups in gcp.
+	SecurityGroupIDs []string `json:"securitygroupids"`
+	// NetworkID is the ID of the network of which the security groups are retrieved if SecurityGroupIDs are not passed.
+	NetworkID string `json:"networkid"`
+	// ProjectID refers to the ID of the GCP project of which the security groups are retrieved.
+	ProjectID string
+	Cloud     cmn.Cloud
+}
+
+//Nothing much from this file. This file contains only the structs for securitygroup/get
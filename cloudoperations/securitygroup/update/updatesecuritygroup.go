@@ -0,0 +1,48 @@
+package securitygroupupdate
+
+import (
+	"context"
+
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
+)
+
+// SecurityGroupResponse will return the filtered/unfiltered responses of variuos clouds on the security groups.
+type SecurityGroupResponse = support.SecurityGroupResponse
+
+// UpdateSecurityGroup adds, revokes or sets the rules of the security groups selected.
+func (sec *UpdateSecurityGroupInput) UpdateSecurityGroup() (SecurityGroupResponse, error) {
+	return sec.UpdateSecurityGroupWithContext(context.Background())
+}
+
+// UpdateSecurityGroupWithContext is same as UpdateSecurityGroup, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (sec *UpdateSecurityGroupInput) UpdateSecurityGroupWithContext(ctx context.Context) (SecurityGroupResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetSecurityGroupProvider(sec.Cloud.Name)
+	if err != nil {
+		return SecurityGroupResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, sec.Cloud.DryRun); err != nil {
+		return SecurityGroupResponse{}, err
+	}
+	securityin := support.UpdateSecurityGroupInput(*sec)
+
+	// the ports opened by the security groups updated are recorded in the state of the stack, if the cloud has one.
+	var response SecurityGroupResponse
+	err = support.Track(ctx, sec.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.UpdateSecurityGroup(ctx, &securityin)
+		return support.SecurityGroupsCreated(sec.Cloud, response.SecurityGroups), opErr
+	})
+	return response, err
+}
+
+// New returns the new UpdateSecurityGroupInput instance with empty values.
+func New() *UpdateSecurityGroupInput {
+	sec := &UpdateSecurityGroupInput{}
+	return sec
+}
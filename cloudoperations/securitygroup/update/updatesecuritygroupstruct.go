@@ -0,0 +1,23 @@
+// Package securitygroupupdate makes the tool cloud agnostic for updating the rules of security groups.
+// The decision will be made here to route the request to respective package based on input.
+package securitygroupupdate
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// UpdateSecurityGroupInput implements method UpdateSecurityGroup and holds parameter for updating security groups.
+type UpdateSecurityGroupInput struct {
+	// SecurityGroupIDs are the IDs of the security groups to be updated, the names of the groups in gcp.
+	SecurityGroupIDs []string `json:"securitygroupids"`
+	// Action to be performed on the rules, add authorizes the rules, revoke removes them
+	// and set makes the rules passed the only rules of the security groups.
+	Action string `json:"action"`
+	// Rules on which the action has to be performed.
+	Rules []cmn.SecurityRule `json:"rules"`
+	// ProjectID refers to the ID of the GCP project in which the security groups exists.
+	ProjectID string
+	Cloud     cmn.Cloud
+}
+
+//Nothing much from this file. This file contains only the structs for securitygroup/update
@@ -1,70 +1,51 @@
 package servercreate
 
 import (
-	"fmt"
+	"context"
 	"reflect"
-	"strings"
 
-	"github.com/aws/aws-sdk-go/aws/session"
-	auth "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
-	awsserver "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
-	common "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/common"
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
 	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
 )
 
 // ServerCreateResponse will return the filtered/unfiltered responses of variuos clouds.
-type ServerCreateResponse struct {
-	// Contains filtered/unfiltered response of AWS.
-	AwsResponse []awsserver.ServerResponse `json:"AwsResponse,omitempty"`
-	// Contains filtered/unfiltered response of Azure.
-	AzureResponse string `json:"AzureResponse,omitempty"`
-	// Default response if no inputs or matching the values required.
-	DefaultResponse interface{} `json:"DefaultResponse,omitempty"`
-}
+type ServerCreateResponse = support.ServerCreateResponse
 
 // CreateServer will create the server with the requirement passed to him
 // appropriate user and his cloud profile details which was passed while calling it.
 func (serv ServerCreateInput) CreateServer() (ServerCreateResponse, error) {
+	return serv.CreateServerWithContext(context.Background())
+}
 
-	if status := support.DoesCloudSupports(strings.ToLower(serv.Cloud.Name)); status != true {
-		return ServerCreateResponse{}, fmt.Errorf(common.DefaultCloudResponse + "GetNetworks")
-	}
-	switch strings.ToLower(serv.Cloud.Name) {
-	case "aws":
-
-		sess := (serv.Cloud.Client).(*session.Session)
-
-		//authorizing to request further
-		authInpt := auth.EstablishConnectionInput{Region: serv.Cloud.Region, Resource: "ec2", Session: sess}
-
-		// I will call CreateServer of interface and get the things done
-
-		serverin := awsserver.CreateServerInput{}
-		serverin.InstanceName = serv.InstanceName
-		serverin.ImageId = serv.ImageId
-		serverin.InstanceType = serv.Flavor
-		serverin.KeyName = serv.KeyName
-		serverin.MaxCount = serv.Count
-		serverin.SubnetId = serv.SubnetId
-		serverin.UserData = serv.UserData
-		serverin.AssignPubIp = serv.AssignPubIp
-		serverin.GetRaw = serv.Cloud.GetRaw
-		response, err := serverin.CreateServer(authInpt)
-		if err != nil {
-			return ServerCreateResponse{}, err
-		}
-		return ServerCreateResponse{AwsResponse: response}, nil
-
-	case "azure":
-		return ServerCreateResponse{}, fmt.Errorf(common.DefaultAzResponse)
-	case "gcp":
-		return ServerCreateResponse{}, fmt.Errorf(common.DefaultGcpResponse)
-	case "openstack":
-		return ServerCreateResponse{}, fmt.Errorf(common.DefaultOpResponse)
-	default:
+// CreateServerWithContext is same as CreateServer, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (serv ServerCreateInput) CreateServerWithContext(ctx context.Context) (ServerCreateResponse, error) {
 
-		return ServerCreateResponse{}, fmt.Errorf(common.DefaultCloudResponse + "CreateServer")
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetServerProvider(serv.Cloud.Name)
+	if err != nil {
+		return ServerCreateResponse{}, err
 	}
+	if err := support.CheckDryRun(provider, serv.Cloud.DryRun); err != nil {
+		return ServerCreateResponse{}, err
+	}
+	serverin := support.CreateServerInput(serv)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response ServerCreateResponse
+	err = support.Track(ctx, serv.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.CreateServer(ctx, &serverin)
+		change := support.ServersCreated(serv.Cloud, response.Servers)
+		if response.KeyPair != nil {
+			change.Created = append(change.Created, support.KeyPairsCreated(serv.Cloud, []cmn.KeyPair{*response.KeyPair}).Created...)
+		}
+		return change, opErr
+	})
+	return response, err
 }
 
 // CreateServerMock will help the user to know what all parameter CreateServer takes as part of ServerCreateInput
@@ -4,6 +4,7 @@ package servercreate
 
 import (
 	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
 )
 
 // ServerCreateInput actually implements CreateServer and holds the data in creating server.
@@ -18,14 +19,39 @@ type ServerCreateInput struct {
 	SubnetId string `json:"subnetid"`
 	// KeyName of the ssh keypair that has to used for creation of vm's, and this key will be used to log into the servers.
 	KeyName string `json:"keyname"`
+	// GenerateKeyPair generates a key pair named after the servers when KeyName is not passed, and creates the servers with it.
+	// The name is suffixed with the IdempotencyKey or a random one, the name given is reported in the KeyPair of the response.
+	// The private key of the key pair is returned once in the KeyPair of the response, it has to be saved as it cannot be fetched again.
+	GenerateKeyPair bool `json:"generatekeypair"`
 	// Flavor defines the hardware configurations of the vm that has to be created [ex: t2.micro(aws),n1-standard-2(gcp) etc.]
 	Flavor string `json:"flavor"`
 	// UserData refers to the raw codes that has to be executed immediately after server boots up goes here.
 	UserData string `json:"userdata"`
 	// AssignPubIp defines whether a public IP has to be assigned to VM or not
 	AssignPubIp bool `json:"assignpubip"`
+	// StaticPublicIp reserves a static public IP for each of the servers and associates it with the server, it stays unchanged across stop/start.
+	// The address is released along with the server once it is deleted if the cloud records the state of a stack, else it outlives the server
+	// and has to be released by the address API. Only aws supports it yet.
+	StaticPublicIp bool `json:"staticpublicip"`
+	// IdempotencyKey if set, makes the creation safe to retry: the servers created earlier with the same key are returned in place of creating new ones.
+	// This maps to ClientToken of aws and can be up to 64 ASCII characters, the other clouds do not create servers yet.
+	IdempotencyKey string `json:"idempotencykey,omitempty"`
+	// Volumes are the volumes to be created and attached to each server, the root volume of the image is overridden by the one marked Root.
+	// The servers are created with the volumes of the image if none are passed, only aws supports it yet.
+	Volumes []ServerVolume `json:"volumes,omitempty"`
+	// MarketType is the market in which the servers are created, on-demand (default) or spot. The spot servers are cheaper,
+	// but the cloud can take them back when it needs the capacity, only aws supports it yet.
+	MarketType string `json:"markettype,omitempty"`
+	// MaxPrice is the maximum hourly price in USD paid for the spot servers ex: 0.05, it defaults to the on-demand price of the Flavor.
+	// The creation fails if the current spot price is above it, spotprice/get helps in picking the zone in which the price is the lowest.
+	MaxPrice string `json:"maxprice,omitempty"`
+	// InterruptionBehavior is what is done with the spot servers when the cloud takes back the capacity: terminate (default), stop or hibernate.
+	InterruptionBehavior string `json:"interruptionbehavior,omitempty"`
 	// All cloud info goes here
 	Cloud cmn.Cloud
 }
 
+// ServerVolume is a volume to be created and attached to the server while it is created.
+type ServerVolume = support.ServerVolume
+
 //Nothing much from this file. This file contains only the structs for server/create
@@ -1,75 +1,75 @@
 package deleteserver
 
 import (
-	"fmt"
-	"strings"
+	"context"
 
-	"github.com/aws/aws-sdk-go/aws/session"
-	auth "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
-	awsserver "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
-	common "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/common"
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
 	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
 )
 
 // DeleteServerResponse will return the filtered/unfiltered responses of variuos clouds.
-type DeleteServerResponse struct {
-	// Contains filtered/unfiltered response of AWS.
-	AwsResponse []awsserver.ServerResponse `json:"AwsResponse,omitempty"`
-	// Contains filtered/unfiltered response of Azure.
-	AzureResponse string `json:"AzureResponse,omitempty"`
-	// Default response if no inputs or matching the values required.
-	DefaultResponse string `json:"Response,omitempty"`
-}
+type DeleteServerResponse = support.DeleteServerResponse
 
 // DeleteServer will delete servers as per the parameter passed to it
 // appropriate user and his cloud profile details which was passed while calling it.
 func (serv *DeleteServersInput) DeleteServer() (DeleteServerResponse, error) {
+	return serv.DeleteServerWithContext(context.Background())
+}
 
-	if status := support.DoesCloudSupports(strings.ToLower(serv.Cloud.Name)); status != true {
-		return DeleteServerResponse{}, fmt.Errorf(common.DefaultCloudResponse + "GetNetworks")
-	}
-
-	switch strings.ToLower(serv.Cloud.Name) {
-	case "aws":
-
-		// I will establish session so that we can carry out the process in cloud
-		sess := (serv.Cloud.Client).(*session.Session)
+// DeleteServerWithContext is same as DeleteServer, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+// The static public IPs recorded in the state of the stack as part of the servers are released once the servers are deleted.
+func (serv *DeleteServersInput) DeleteServerWithContext(ctx context.Context) (DeleteServerResponse, error) {
 
-		//authorize
-		authInpt := auth.EstablishConnectionInput{Region: serv.Cloud.Region, Resource: "ec2", Session: sess}
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetServerProvider(serv.Cloud.Name)
+	if err != nil {
+		return DeleteServerResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, serv.Cloud.DryRun); err != nil {
+		return DeleteServerResponse{}, err
+	}
+	serverin := support.DeleteServersInput(*serv)
 
-		// I will call DeleteServer of interface and get the things done
-		if serv.InstanceIds != nil {
-			serverin := awsserver.DeleteServerInput{}
-			serverin.InstanceIds = serv.InstanceIds
-			serverin.GetRaw = serv.Cloud.GetRaw
-			serverResponse, serverr := serverin.DeleteServer(authInpt)
-			if serverr != nil {
-				return DeleteServerResponse{}, serverr
-			}
-			return DeleteServerResponse{AwsResponse: serverResponse}, nil
-		} else if serv.VpcId != "" {
-			serverin := awsserver.DeleteServerInput{}
-			serverin.VpcId = serv.VpcId
-			serverin.GetRaw = serv.Cloud.GetRaw
-			serverResponse, serverr := serverin.DeleteServerFromVpc(authInpt)
-			if serverr != nil {
-				return DeleteServerResponse{}, serverr
-			}
-			return DeleteServerResponse{AwsResponse: serverResponse}, nil
-		} else {
-			return DeleteServerResponse{}, fmt.Errorf("You have not passed valid input to get details of server, the input looks like empty")
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response DeleteServerResponse
+	err = support.Track(ctx, serv.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.DeleteServer(ctx, &serverin)
+		if opErr != nil {
+			return support.ServersDeleted(response.Servers), opErr
 		}
+		return releaseAddresses(ctx, serv.Cloud, response.Servers)
+	})
+	return response, err
+}
+
+// releaseAddresses releases the static public IPs allocated along with the servers deleted, as they are billed even when not associated.
+// The ones which could not be released are kept in the state on their own (see support.AddressesNotReleased).
+func releaseAddresses(ctx context.Context, cloud cmn.Cloud, servers []cmn.Server) (state.Change, error) {
+	change := support.ServersDeleted(servers)
+	addresses, err := support.AddressesOf(ctx, cloud, servers)
+	if err != nil || len(addresses) == 0 {
+		return change, err
+	}
 
-	case "azure":
-		return DeleteServerResponse{}, fmt.Errorf(common.DefaultAzResponse)
-	case "gcp":
-		return DeleteServerResponse{}, fmt.Errorf(common.DefaultGcpResponse)
-	case "openstack":
-		return DeleteServerResponse{}, fmt.Errorf(common.DefaultOpResponse)
-	default:
-		return DeleteServerResponse{}, fmt.Errorf(common.DefaultCloudResponse + "DeleteServer")
+	ids := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		ids = append(ids, address.ID)
+	}
+	provider, err := support.GetAddressProvider(cloud.Name)
+	if err != nil {
+		return change, err
+	}
+	released, err := provider.ReleaseAddress(ctx, &support.ReleaseAddressInput{AddressIDs: ids, Cloud: cloud})
+	if err == nil {
+		return change, nil
 	}
+	change.Created = support.AddressesNotReleased(addresses, released.Addresses).Created
+	return change, err
 }
 
 // New returns the new DeleteServersInput instance with empty values
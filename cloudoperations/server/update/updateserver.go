@@ -1,62 +1,51 @@
 package updateservers
 
 import (
-	"fmt"
-	"strings"
+	"context"
 
-	"github.com/aws/aws-sdk-go/aws/session"
-	auth "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/interface"
-	awsserver "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
-	common "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/common"
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
 	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
 )
 
 // UpdateServersResponse will return the filtered/unfiltered responses of variuos clouds.
-type UpdateServersResponse struct {
-	// Contains filtered/unfiltered response of AWS.
-	AwsResponse []awsserver.ServerResponse `json:"AwsResponse,omitempty"`
-	// Contains filtered/unfiltered response of Azure.
-	AzureResponse string `json:"AzureResponse,omitempty"`
-	// Default response if no inputs or matching the values required.
-	DefaultResponse string `json:"Response,omitempty"`
-}
+type UpdateServersResponse = support.UpdateServersResponse
 
-// UpdateServers will update servers (start/stop, change ebs etc)
+// UpdateServers will update servers (start/stop, resize, termination protection etc)
 //  with the instructions passed to him and give back the response who called this.
 // Below method will take care of fetching details of
 // appropriate user and his cloud profile details which was passed while calling it.
 func (serv *UpdateServersInput) UpdateServers() (UpdateServersResponse, error) {
+	return serv.UpdateServersWithContext(context.Background())
+}
 
-	if status := support.DoesCloudSupports(strings.ToLower(serv.Cloud.Name)); status != true {
-		return UpdateServersResponse{}, fmt.Errorf(common.DefaultCloudResponse + "GetNetworks")
-	}
-
-	switch strings.ToLower(serv.Cloud.Name) {
-	case "aws":
-
-		// Gets the established session so that it can carry out the process in cloud.
-		sess := (serv.Cloud.Client).(*session.Session)
-
-		//authorizing to request further
-		authinpt := auth.EstablishConnectionInput{Region: serv.Cloud.Region, Resource: "ec2", Session: sess}
-
-		// I will call UpdateServer of interface and get the things done
-		serverin := awsserver.UpdateServerInput{InstanceIds: serv.InstanceIds, Action: serv.Action, GetRaw: serv.Cloud.GetRaw}
-		response, err := serverin.UpdateServer(authinpt)
-		if err != nil {
-			return UpdateServersResponse{}, err
-		}
-		return UpdateServersResponse{AwsResponse: response}, nil
+// UpdateServersWithContext is same as UpdateServers, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (serv *UpdateServersInput) UpdateServersWithContext(ctx context.Context) (UpdateServersResponse, error) {
 
-	case "azure":
-		return UpdateServersResponse{DefaultResponse: common.DefaultAzResponse}, nil
-	case "gcp":
-		return UpdateServersResponse{DefaultResponse: common.DefaultGcpResponse}, nil
-	case "openstack":
-		return UpdateServersResponse{DefaultResponse: common.DefaultOpResponse}, nil
-	default:
-		return UpdateServersResponse{}, fmt.Errorf(common.DefaultCloudResponse + "UpdateServers")
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetServerProvider(serv.Cloud.Name)
+	if err != nil {
+		return UpdateServersResponse{}, err
 	}
+	if err := support.CheckDryRun(provider, serv.Cloud.DryRun); err != nil {
+		return UpdateServersResponse{}, err
+	}
+	serverin := support.UpdateServersInput(*serv)
+
+	// the servers of the stack are recorded afresh in its state with the flavor they have once updated, if the cloud has one.
+	var response UpdateServersResponse
+	err = support.Track(ctx, serv.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.UpdateServers(ctx, &serverin)
+		change, stateErr := support.ServersUpdated(ctx, serv.Cloud, response.Changes)
+		if opErr != nil {
+			return change, opErr
+		}
+		return change, stateErr
+	})
+	return response, err
 }
 
 // New returns the new UpdateServersInput instance with empty values
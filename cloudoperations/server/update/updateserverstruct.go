@@ -10,9 +10,16 @@ import (
 type UpdateServersInput struct {
 	// Ids of the instances/vms which has to be updated
 	InstanceIds []string `json:"instanceids"`
-	// Action item that has to be performed on the VM
+	// Action item that has to be performed on the VM, the actions supported by aws are: start, stop, reboot, resize, protect, unprotect,
+	// userdata, securitygroups, monitor and unmonitor.
 	Action string `json:"action"`
-	Cloud  cmn.Cloud
+	// Flavor is the type/size to which the VMs are changed by the action resize ex: t2.medium.
+	Flavor string `json:"flavor"`
+	// UserData is the script which replaces the one of the VMs by the action userdata.
+	UserData string `json:"userdata"`
+	// SecurityGroupIds are the security groups which replaces the ones of the VMs by the action securitygroups.
+	SecurityGroupIds []string `json:"securitygroupids"`
+	Cloud            cmn.Cloud
 }
 
 //Nothing much from this file. This file contains only the structs for server/update
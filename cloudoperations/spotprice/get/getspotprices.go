@@ -0,0 +1,37 @@
+package spotpriceget
+
+import (
+	"context"
+
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+)
+
+// SpotPriceResponse will return the filtered/unfiltered responses of variuos clouds on the spot prices.
+type SpotPriceResponse = support.SpotPriceResponse
+
+// GetSpotPrices fetches the current spot prices of the flavors passed in each of the zones selected, the cheapest are first.
+// This helps in picking the zone in which the spot servers are created, and the MaxPrice with which they are created.
+func (spot *GetSpotPricesInput) GetSpotPrices() (SpotPriceResponse, error) {
+	return spot.GetSpotPricesWithContext(context.Background())
+}
+
+// GetSpotPricesWithContext is same as GetSpotPrices, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (spot *GetSpotPricesInput) GetSpotPricesWithContext(ctx context.Context) (SpotPriceResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetSpotPriceProvider(spot.Cloud.Name)
+	if err != nil {
+		return SpotPriceResponse{}, err
+	}
+	spotin := support.GetSpotPricesInput(*spot)
+	return provider.GetSpotPrices(ctx, &spotin)
+}
+
+// New returns the new GetSpotPricesInput instance with empty values.
+func New() *GetSpotPricesInput {
+	spot := &GetSpotPricesInput{}
+	return spot
+}
@@ -0,0 +1,20 @@
+// Package spotpriceget makes the tool cloud agnostic with respect to fetching the current prices of the spot servers.
+// The decision will be made here to route the request to respective package based on input.
+package spotpriceget
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// GetSpotPricesInput implements method GetSpotPrices and holds parameter for fetching the spot prices.
+type GetSpotPricesInput struct {
+	// Flavors of the servers of which the spot prices has to be fetched ex: t2.micro(aws).
+	Flavors []string `json:"flavors"`
+	// Zones in which the spot prices has to be fetched, all the zones of the region are considered if none are passed.
+	Zones []string `json:"zones"`
+	// Product is the platform of the servers ex: Linux/UNIX (default), Windows.
+	Product string `json:"product"`
+	Cloud   cmn.Cloud
+}
+
+//Nothing much from this file. This file contains only the structs for spotprice/get
@@ -0,0 +1,200 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	imagecreate "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/image/create"
+	createloadbalancer "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/loadbalancer/create"
+	networkcreate "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/network/create"
+	servercreate "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/server/create"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// Error is the error with which the creation of a resource of the stack failed.
+type Error struct {
+	// Resource is the resource of the stack which could not be created.
+	Resource Resource
+	// Err is the error returned by the operation creating the resource.
+	Err error
+}
+
+// Error returns the message of the error prefixed with the resource which could not be created.
+func (e *Error) Error() string {
+	return fmt.Sprintf("creating %s of the stack: %v", e.Resource, e.Err)
+}
+
+// Unwrap returns the error returned by the operation creating the resource.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Apply creates the resources of the stack in the order returned by Order, see ApplyWithContext.
+func (s *Spec) Apply() (Outputs, error) {
+	return s.ApplyWithContext(context.Background())
+}
+
+// ApplyWithContext creates the resources of the stack one after the other, passing the IDs of the resources created to the ones referring to them.
+// On failure the resources created so far are returned along with an Error naming the resource which failed, those are not rolled back.
+// The resources are recorded under the name of the stack if the cloud has a state backend, unless Stack of the cloud says otherwise.
+// The stack does not support DryRun of the cloud yet, as the resources cannot be planned without the IDs of the ones they refer to.
+func (s *Spec) ApplyWithContext(ctx context.Context) (Outputs, error) {
+
+	outputs := Outputs{
+		Networks:      make(map[string]cmn.Network),
+		Servers:       make(map[string][]cmn.Server),
+		Images:        make(map[string][]cmn.Image),
+		Loadbalancers: make(map[string]cmn.LoadBalancer),
+	}
+	if s.Cloud.DryRun {
+		return outputs, cloudyerror.New(cloudyerror.Unsupported, "DryRun is not supported by stack yet, as its resources depend on the IDs of the ones created before them")
+	}
+	order, err := s.Order()
+	if err != nil {
+		return outputs, err
+	}
+
+	// the IDs of the resources are needed to create the ones referring to them, which are left out of the raw responses.
+	cloud := s.Cloud
+	cloud.GetRaw = false
+	if cloud.Stack == "" {
+		cloud.Stack = s.Name
+	}
+	for _, resource := range order {
+		var err error
+		switch resource.Kind {
+		case KindNetwork:
+			err = s.createNetwork(ctx, cloud, s.network(resource.Name), &outputs)
+		case KindServer:
+			err = s.createServer(ctx, cloud, s.server(resource.Name), &outputs)
+		case KindImage:
+			err = s.createImage(ctx, cloud, s.image(resource.Name), &outputs)
+		case KindLoadbalancer:
+			err = s.createLoadbalancer(ctx, cloud, s.loadbalancer(resource.Name), &outputs)
+		}
+		if err != nil {
+			return outputs, &Error{Resource: resource, Err: err}
+		}
+	}
+	return outputs, nil
+}
+
+func (s *Spec) createNetwork(ctx context.Context, cloud cmn.Cloud, network Network, outputs *Outputs) error {
+	netin := networkcreate.NetworkCreateInput{
+		Name:    network.Name,
+		VpcCidr: network.VpcCidr,
+		SubCidr: network.SubCidr,
+		Type:    network.Type,
+		Ports:   network.Ports,
+		Cloud:   cloud,
+	}
+	response, err := netin.CreateNetworkWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	if len(response.Networks) == 0 {
+		return cloudyerror.Newf(cloudyerror.Unknown, "%s did not report the network created", cloud.Name)
+	}
+	outputs.Networks[network.Name] = response.Networks[0]
+	return nil
+}
+
+func (s *Spec) createServer(ctx context.Context, cloud cmn.Cloud, server Server, outputs *Outputs) error {
+	serverin := servercreate.ServerCreateInput{
+		InstanceName:   server.Name,
+		Count:          server.Count,
+		ImageId:        server.ImageId,
+		SubnetId:       server.SubnetId,
+		KeyName:        server.KeyName,
+		Flavor:         server.Flavor,
+		UserData:       server.UserData,
+		AssignPubIp:    server.AssignPubIp,
+		IdempotencyKey: server.IdempotencyKey,
+		Cloud:          cloud,
+	}
+	if serverin.Count == 0 {
+		serverin.Count = 1
+	}
+	if server.Network != "" {
+		subnets := outputs.Networks[server.Network].Subnets
+		if server.Subnet >= len(subnets) {
+			return cloudyerror.Newf(cloudyerror.Unknown, "the network %s was created with %d subnets, the subnet %d is not found", server.Network, len(subnets), server.Subnet)
+		}
+		serverin.SubnetId = subnets[server.Subnet].ID
+	}
+	if server.Image != "" {
+		images := outputs.Images[server.Image]
+		if len(images) == 0 {
+			return cloudyerror.Newf(cloudyerror.Unknown, "no image was captured for the image %s", server.Image)
+		}
+		serverin.ImageId = images[0].ID
+	}
+	response, err := serverin.CreateServerWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	outputs.Servers[server.Name] = response.Servers
+	return nil
+}
+
+func (s *Spec) createImage(ctx context.Context, cloud cmn.Cloud, image Image, outputs *Outputs) error {
+	imgin := imagecreate.CreateImageInput{
+		InstanceIds: serverIDs(outputs.Servers[image.Server]),
+		Cloud:       cloud,
+	}
+	response, err := imgin.CreateImageWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	outputs.Images[image.Name] = response.Images
+	return nil
+}
+
+func (s *Spec) createLoadbalancer(ctx context.Context, cloud cmn.Cloud, lb Loadbalancer, outputs *Outputs) error {
+	lbin := createloadbalancer.LbCreateInput{
+		Name:             lb.Name,
+		Type:             lb.Type,
+		VpcId:            lb.VpcId,
+		SubnetIds:        lb.SubnetIds,
+		SecurityGroupIds: lb.SecurityGroupIds,
+		Scheme:           lb.Scheme,
+		SslCert:          lb.SslCert,
+		SslPolicy:        lb.SslPolicy,
+		LbPort:           lb.LbPort,
+		InstPort:         lb.InstPort,
+		Lbproto:          lb.Lbproto,
+		Instproto:        lb.Instproto,
+		HttpCode:         lb.HttpCode,
+		HealthPath:       lb.HealthPath,
+		IpAddressType:    lb.IpAddressType,
+		Cloud:            cloud,
+	}
+	if lb.Network != "" {
+		network := outputs.Networks[lb.Network]
+		lbin.VpcId = network.ID
+		lbin.SubnetIds = make([]string, 0, len(network.Subnets))
+		for _, subnet := range network.Subnets {
+			lbin.SubnetIds = append(lbin.SubnetIds, subnet.ID)
+		}
+	}
+	for _, target := range lb.Targets {
+		lbin.Targets = append(lbin.Targets, serverIDs(outputs.Servers[target])...)
+	}
+	response, err := lbin.CreateLoadBalancerWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	if len(response.LoadBalancers) != 0 {
+		outputs.Loadbalancers[lb.Name] = response.LoadBalancers[0]
+	}
+	return nil
+}
+
+func serverIDs(servers []cmn.Server) []string {
+	ids := make([]string, 0, len(servers))
+	for _, server := range servers {
+		ids = append(ids, server.ID)
+	}
+	return ids
+}
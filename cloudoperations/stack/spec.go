@@ -0,0 +1,262 @@
+package stack
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// The kinds of the resources of the stack.
+const (
+	KindNetwork      = "network"
+	KindServer       = "server"
+	KindImage        = "image"
+	KindLoadbalancer = "loadbalancer"
+)
+
+// Resource identifies a resource of the spec by its kind and name.
+type Resource struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// String returns the resource in the form kind name ex: network web.
+func (r Resource) String() string {
+	return r.Kind + " " + r.Name
+}
+
+// Load reads the spec from the file passed, see Parse for the formats supported.
+func Load(path string) (*Spec, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse reads the spec from the JSON or YAML passed, the keys of YAML are the same as those of JSON.
+// The spec is validated before it is returned, hence the references which are not resolvable are reported here.
+func Parse(data []byte) (*Spec, error) {
+	spec := new(Spec)
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		if err := json.Unmarshal(trimmed, spec); err != nil {
+			return nil, cloudyerror.Wrap(cloudyerror.InvalidInput, err)
+		}
+	} else if err := yaml.Unmarshal(trimmed, spec); err != nil {
+		return nil, cloudyerror.Wrap(cloudyerror.InvalidInput, err)
+	}
+	if _, err := spec.Order(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// Order validates the spec and returns its resources in the order in which they have to be created,
+// every resource comes after the ones it refers to. Otherwise the resources are ordered as they appear in the spec.
+func (s *Spec) Order() ([]Resource, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	resources := make([]Resource, 0)
+	for _, network := range s.Networks {
+		resources = append(resources, Resource{Kind: KindNetwork, Name: network.Name})
+	}
+	for _, server := range s.Servers {
+		resources = append(resources, Resource{Kind: KindServer, Name: server.Name})
+	}
+	for _, image := range s.Images {
+		resources = append(resources, Resource{Kind: KindImage, Name: image.Name})
+	}
+	for _, lb := range s.Loadbalancers {
+		resources = append(resources, Resource{Kind: KindLoadbalancer, Name: lb.Name})
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[Resource]int)
+	order := make([]Resource, 0, len(resources))
+	var path []string
+	var visit func(resource Resource) error
+	visit = func(resource Resource) error {
+		switch state[resource] {
+		case visited:
+			return nil
+		case visiting:
+			return cloudyerror.Newf(cloudyerror.InvalidInput, "the resources of the stack refer to each other in a cycle: %s -> %s", strings.Join(path, " -> "), resource)
+		}
+		state[resource] = visiting
+		path = append(path, resource.String())
+		for _, dependency := range s.dependencies(resource) {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[resource] = visited
+		order = append(order, resource)
+		return nil
+	}
+	for _, resource := range resources {
+		if err := visit(resource); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// dependencies returns the resources the resource passed refers to.
+func (s *Spec) dependencies(resource Resource) []Resource {
+	dependencies := make([]Resource, 0)
+	switch resource.Kind {
+	case KindServer:
+		server := s.server(resource.Name)
+		if server.Network != "" {
+			dependencies = append(dependencies, Resource{Kind: KindNetwork, Name: server.Network})
+		}
+		if server.Image != "" {
+			dependencies = append(dependencies, Resource{Kind: KindImage, Name: server.Image})
+		}
+	case KindImage:
+		dependencies = append(dependencies, Resource{Kind: KindServer, Name: s.image(resource.Name).Server})
+	case KindLoadbalancer:
+		lb := s.loadbalancer(resource.Name)
+		if lb.Network != "" {
+			dependencies = append(dependencies, Resource{Kind: KindNetwork, Name: lb.Network})
+		}
+		for _, target := range lb.Targets {
+			dependencies = append(dependencies, Resource{Kind: KindServer, Name: target})
+		}
+	}
+	return dependencies
+}
+
+// validate makes sure that the names of the resources are unique and every reference is resolvable.
+func (s *Spec) validate() error {
+	names := make(map[Resource]bool)
+	unique := func(kind, name string) error {
+		if name == "" {
+			return cloudyerror.Newf(cloudyerror.InvalidInput, "every %s of the stack has to be named", kind)
+		}
+		resource := Resource{Kind: kind, Name: name}
+		if names[resource] {
+			return cloudyerror.Newf(cloudyerror.InvalidInput, "the stack has more than one %s", resource)
+		}
+		names[resource] = true
+		return nil
+	}
+	for _, network := range s.Networks {
+		if err := unique(KindNetwork, network.Name); err != nil {
+			return err
+		}
+	}
+	for _, server := range s.Servers {
+		if err := unique(KindServer, server.Name); err != nil {
+			return err
+		}
+	}
+	for _, image := range s.Images {
+		if err := unique(KindImage, image.Name); err != nil {
+			return err
+		}
+	}
+	for _, lb := range s.Loadbalancers {
+		if err := unique(KindLoadbalancer, lb.Name); err != nil {
+			return err
+		}
+	}
+
+	unknown := func(referrer Resource, kind, name string) error {
+		if names[Resource{Kind: kind, Name: name}] {
+			return nil
+		}
+		return cloudyerror.Newf(cloudyerror.InvalidInput, "%s refers to the %s %s, which is not part of the stack", referrer, kind, name)
+	}
+	for _, server := range s.Servers {
+		referrer := Resource{Kind: KindServer, Name: server.Name}
+		switch {
+		case (server.Network == "") == (server.SubnetId == ""):
+			return cloudyerror.Newf(cloudyerror.InvalidInput, "%s has to be placed either in a network of the stack or in the subnet passed", referrer)
+		case (server.Image == "") == (server.ImageId == ""):
+			return cloudyerror.Newf(cloudyerror.InvalidInput, "%s has to be created either from an image of the stack or from the image passed", referrer)
+		}
+		if server.Network != "" {
+			if err := unknown(referrer, KindNetwork, server.Network); err != nil {
+				return err
+			}
+			if subnets := len(s.network(server.Network).SubCidr); server.Subnet < 0 || server.Subnet >= subnets {
+				return cloudyerror.Newf(cloudyerror.InvalidInput, "%s refers to the subnet %d of the network %s, which has only %d subnets", referrer, server.Subnet, server.Network, subnets)
+			}
+		}
+		if server.Image != "" {
+			if err := unknown(referrer, KindImage, server.Image); err != nil {
+				return err
+			}
+		}
+	}
+	for _, image := range s.Images {
+		if err := unknown(Resource{Kind: KindImage, Name: image.Name}, KindServer, image.Server); err != nil {
+			return err
+		}
+	}
+	for _, lb := range s.Loadbalancers {
+		referrer := Resource{Kind: KindLoadbalancer, Name: lb.Name}
+		if lb.Network != "" {
+			if lb.VpcId != "" || lb.SubnetIds != nil {
+				return cloudyerror.Newf(cloudyerror.InvalidInput, "%s has to be placed either in a network of the stack or in the network passed", referrer)
+			}
+			if err := unknown(referrer, KindNetwork, lb.Network); err != nil {
+				return err
+			}
+		}
+		for _, target := range lb.Targets {
+			if err := unknown(referrer, KindServer, target); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Spec) network(name string) Network {
+	for _, network := range s.Networks {
+		if network.Name == name {
+			return network
+		}
+	}
+	return Network{}
+}
+
+func (s *Spec) server(name string) Server {
+	for _, server := range s.Servers {
+		if server.Name == name {
+			return server
+		}
+	}
+	return Server{}
+}
+
+func (s *Spec) image(name string) Image {
+	for _, image := range s.Images {
+		if image.Name == name {
+			return image
+		}
+	}
+	return Image{}
+}
+
+func (s *Spec) loadbalancer(name string) Loadbalancer {
+	for _, lb := range s.Loadbalancers {
+		if lb.Name == name {
+			return lb
+		}
+	}
+	return Loadbalancer{}
+}
@@ -0,0 +1,476 @@
+package stack
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	awsfake "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/fake"
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	addresscreate "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/address/create"
+	addressdelete "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/address/delete"
+	keypaircreate "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/keypair/create"
+	keypairdelete "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/keypair/delete"
+	keypairimport "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/keypair/import"
+	deleteloadbalancer "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/loadbalancer/delete"
+	networkcreate "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/network/create"
+	servercreate "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/server/create"
+	deleteserver "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/server/delete"
+	volumecreate "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/volume/create"
+	volumedelete "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/volume/delete"
+	volumesnapshot "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/volume/snapshot"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+	"github.com/nikhilsbhat/neuron-cloudy/sshkey"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
+	"github.com/nikhilsbhat/neuron-cloudy/wait"
+)
+
+const spec = `
+name: shop
+cloud:
+  name: aws
+  region: us-east-1
+loadbalancers:
+  - name: front
+    type: application
+    network: web
+    targets: [app]
+    lbport: 80
+    instport: 8080
+    lbproto: HTTP
+    instproto: HTTP
+    healthpath: /health
+    httpcode: "200"
+  - name: legacy
+    type: classic
+    network: web
+    targets: [app, golden-app]
+    lbport: 80
+    instport: 8080
+    lbproto: HTTP
+    instproto: HTTP
+servers:
+  - name: golden-app
+    image: golden
+    network: web
+    subnet: 1
+    flavor: t2.micro
+  - name: app
+    count: 2
+    imageid: ami-0123456789
+    network: web
+    flavor: t2.micro
+    assignpubip: true
+images:
+  - name: golden
+    server: app
+networks:
+  - name: web
+    vpccidr: 10.0.0.0/16
+    subcidr: [10.0.1.0/24, 10.0.2.0/24]
+    type: public
+`
+
+func TestOrder(t *testing.T) {
+	parsed, err := Parse([]byte(spec))
+	if err != nil {
+		t.Fatalf("parsing the spec: %v", err)
+	}
+	order, err := parsed.Order()
+	if err != nil {
+		t.Fatalf("ordering the spec: %v", err)
+	}
+	names := make([]string, 0, len(order))
+	for _, resource := range order {
+		names = append(names, resource.String())
+	}
+	expected := "network web,server app,image golden,server golden-app,loadbalancer front,loadbalancer legacy"
+	if got := strings.Join(names, ","); got != expected {
+		t.Errorf("expected the resources in the order %s, got %s", expected, got)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	cases := map[string]string{
+		"unknown network":   `{"servers": [{"name": "app", "network": "web", "imageid": "ami-0123456789"}]}`,
+		"subnet not found":  `{"networks": [{"name": "web", "subcidr": ["10.0.1.0/24"]}], "servers": [{"name": "app", "network": "web", "subnet": 1, "imageid": "ami-0123456789"}]}`,
+		"unknown target":    `{"loadbalancers": [{"name": "front", "vpcid": "vpc-0a1b2c", "targets": ["app"]}]}`,
+		"duplicate name":    `{"networks": [{"name": "web"}, {"name": "web"}]}`,
+		"no image":          `{"servers": [{"name": "app", "subnetid": "subnet-0a1b2c"}]}`,
+		"image and network": `{"servers": [{"name": "app", "subnetid": "subnet-0a1b2c", "network": "web", "imageid": "ami-0123456789"}], "networks": [{"name": "web"}]}`,
+		"cycle":             "servers:\n  - {name: app, subnetid: subnet-0a1b2c, image: golden}\nimages:\n  - {name: golden, server: app}\n",
+	}
+	for name, data := range cases {
+		if _, err := Parse([]byte(data)); !cloudyerror.IsInvalidInput(err) {
+			t.Errorf("%s: expected the spec to be rejected with InvalidInput, got %v", name, err)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	cloud := awsfake.New()
+	parsed, err := Parse([]byte(spec))
+	if err != nil {
+		t.Fatalf("parsing the spec: %v", err)
+	}
+	parsed.Cloud.Client = cloud
+
+	outputs, err := parsed.Apply()
+	if err != nil {
+		t.Fatalf("applying the stack: %v", err)
+	}
+
+	network := outputs.Networks["web"]
+	if network.ID == "" || len(network.Subnets) != 2 {
+		t.Fatalf("expected the network with 2 subnets, got %+v", network)
+	}
+	apps := outputs.Servers["app"]
+	if len(apps) != 2 || apps[0].SubnetID != network.Subnets[0].ID {
+		t.Fatalf("expected 2 servers in the first subnet of the network, got %+v", apps)
+	}
+	golden := outputs.Servers["golden-app"]
+	if len(golden) != 1 || golden[0].SubnetID != network.Subnets[1].ID {
+		t.Fatalf("expected the server in the second subnet of the network, got %+v", golden)
+	}
+	if outputs.Loadbalancers["front"].DNSName == "" || outputs.Loadbalancers["legacy"].DNSName == "" {
+		t.Errorf("expected both the loadbalancers to be created, got %+v", outputs.Loadbalancers)
+	}
+
+	ctx := context.Background()
+	images := outputs.Images["golden"]
+	launched, err := cloud.EC2("us-east-1").DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{InstanceIds: aws.StringSlice([]string{golden[0].ID})})
+	if err != nil {
+		t.Fatalf("describing the server: %v", err)
+	}
+	if len(images) != 2 || *launched.Reservations[0].Instances[0].ImageId != images[0].ID {
+		t.Errorf("expected the server to be created from the image captured, got %s from %+v", *launched.Reservations[0].Instances[0].ImageId, images)
+	}
+
+	groups, err := cloud.ELBV2("us-east-1").DescribeTargetGroupsWithContext(ctx, &elbv2.DescribeTargetGroupsInput{Names: aws.StringSlice([]string{"front-target"})})
+	if err != nil {
+		t.Fatalf("describing the target group: %v", err)
+	}
+	health, err := cloud.ELBV2("us-east-1").DescribeTargetHealthWithContext(ctx, &elbv2.DescribeTargetHealthInput{TargetGroupArn: groups.TargetGroups[0].TargetGroupArn})
+	if err != nil {
+		t.Fatalf("describing the targets: %v", err)
+	}
+	if len(health.TargetHealthDescriptions) != 2 || aws.Int64Value(health.TargetHealthDescriptions[0].Target.Port) != 8080 {
+		t.Errorf("expected the servers of app to be registered on 8080, got %+v", health.TargetHealthDescriptions)
+	}
+
+	classic, err := cloud.ELB("us-east-1").DescribeLoadBalancersWithContext(ctx, &elb.DescribeLoadBalancersInput{LoadBalancerNames: aws.StringSlice([]string{"legacy"})})
+	if err != nil {
+		t.Fatalf("describing the classic loadbalancer: %v", err)
+	}
+	if registered := classic.LoadBalancerDescriptions[0].Instances; len(registered) != 3 {
+		t.Errorf("expected the servers of app and golden-app to be registered, got %+v", registered)
+	}
+}
+
+func TestApplyFailure(t *testing.T) {
+	cloud := awsfake.New()
+	parsed, err := Parse([]byte(spec))
+	if err != nil {
+		t.Fatalf("parsing the spec: %v", err)
+	}
+	parsed.Cloud.Client = cloud
+	cloud.FailNext("CreateImage", cloudyerror.New(cloudyerror.QuotaExceeded, "image limit exceeded"))
+
+	outputs, err := parsed.Apply()
+	stackErr, ok := err.(*Error)
+	if !ok || stackErr.Resource != (Resource{Kind: KindImage, Name: "golden"}) || !cloudyerror.IsQuotaExceeded(err) {
+		t.Fatalf("expected the image golden to fail with QuotaExceeded, got %v", err)
+	}
+	if len(outputs.Servers["app"]) != 2 || len(outputs.Servers["golden-app"]) != 0 || len(outputs.Loadbalancers) != 0 {
+		t.Errorf("expected only the resources created before the failure, got %+v", outputs)
+	}
+}
+
+func TestApplyDryRun(t *testing.T) {
+	parsed, err := Parse([]byte(spec))
+	if err != nil {
+		t.Fatalf("parsing the spec: %v", err)
+	}
+	parsed.Cloud.Client = awsfake.New()
+	parsed.Cloud.DryRun = true
+	if _, err := parsed.Apply(); !cloudyerror.IsUnsupported(err) {
+		t.Errorf("expected DryRun to be unsupported, got %v", err)
+	}
+}
+
+func TestApplyRecordsState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cloudy-stack")
+	if err != nil {
+		t.Fatalf("creating the directory of state: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	backend := state.NewLocal(dir)
+	ctx := context.Background()
+
+	cloud := awsfake.New()
+	parsed, err := Parse([]byte(spec))
+	if err != nil {
+		t.Fatalf("parsing the spec: %v", err)
+	}
+	parsed.Cloud.Client = cloud
+	parsed.Cloud.State = backend
+	parsed.Cloud.Wait = &wait.Poller{Interval: time.Millisecond, Backoff: 1}
+	outputs, err := parsed.Apply()
+	if err != nil {
+		t.Fatalf("applying the stack: %v", err)
+	}
+
+	recorded, err := backend.Read(ctx, "shop")
+	if err != nil {
+		t.Fatalf("reading the state: %v", err)
+	}
+	if len(recorded.IDs(state.KindNetwork)) != 1 || len(recorded.IDs(state.KindSubnet)) != 2 || len(recorded.IDs(state.KindServer)) != 3 ||
+		len(recorded.IDs(state.KindImage)) != 2 || len(recorded.IDs(state.KindLoadbalancer)) != 2 {
+		t.Fatalf("expected every resource of the stack to be recorded, got %+v", recorded.Resources)
+	}
+
+	// the state is all one needs to tear the stack down.
+	for _, lb := range recorded.Of(state.KindLoadbalancer) {
+		del := deleteloadbalancer.LbDeleteInput{Type: lb.Attributes[state.AttributeType], Cloud: parsed.Cloud}
+		if lb.Attributes[state.AttributeType] == "classic" {
+			del.LbNames = []string{lb.ID}
+		} else {
+			del.LbArns = []string{lb.ID}
+		}
+		del.Cloud.Stack = "shop"
+		if _, err := del.DeleteLoadBalancer(); err != nil {
+			t.Fatalf("deleting the loadbalancer %s: %v", lb.ID, err)
+		}
+	}
+	servers := deleteserver.DeleteServersInput{InstanceIds: recorded.IDs(state.KindServer), Cloud: parsed.Cloud}
+	servers.Cloud.Stack = "shop"
+	if _, err := servers.DeleteServer(); err != nil {
+		t.Fatalf("deleting the servers: %v", err)
+	}
+
+	recorded, err = backend.Read(ctx, "shop")
+	if err != nil {
+		t.Fatalf("reading the state: %v", err)
+	}
+	if left := len(recorded.Of(state.KindServer)) + len(recorded.Of(state.KindLoadbalancer)); left != 0 {
+		t.Errorf("expected the servers and loadbalancers deleted to be removed, got %+v", recorded.Resources)
+	}
+	if ids := recorded.IDs(state.KindNetwork); len(ids) != 1 || ids[0] != outputs.Networks["web"].ID {
+		t.Errorf("expected the network to be left as is, got %v", ids)
+	}
+}
+
+func TestVolumesRecordState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cloudy-stack")
+	if err != nil {
+		t.Fatalf("creating the directory of state: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	backend := state.NewLocal(dir)
+	ctx := context.Background()
+
+	cloud := cmn.Cloud{Name: "aws", Region: "us-east-1", Client: awsfake.New(), State: backend, Stack: "shop"}
+	create := volumecreate.CreateVolumeInput{Name: "data", Zone: "us-east-1a", Size: 8, Cloud: cloud}
+	volumes, err := create.CreateVolume()
+	if err != nil {
+		t.Fatalf("creating the volume: %v", err)
+	}
+	capture := volumesnapshot.CreateSnapshotInput{VolumeID: volumes.Volumes[0].ID, Name: "data", Cloud: cloud}
+	snapshots, err := capture.CreateSnapshot()
+	if err != nil {
+		t.Fatalf("capturing the snapshot: %v", err)
+	}
+	restore := volumesnapshot.RestoreSnapshotInput{SnapshotID: snapshots.Snapshots[0].ID, Name: "restored", Zone: "us-east-1a", Cloud: cloud}
+	restored, err := restore.RestoreSnapshot()
+	if err != nil {
+		t.Fatalf("restoring the snapshot: %v", err)
+	}
+
+	recorded, err := backend.Read(ctx, "shop")
+	if err != nil {
+		t.Fatalf("reading the state: %v", err)
+	}
+	if len(recorded.IDs(state.KindVolume)) != 2 || len(recorded.IDs(state.KindSnapshot)) != 1 {
+		t.Fatalf("expected the volumes and snapshot to be recorded, got %+v", recorded.Resources)
+	}
+
+	del := volumedelete.DeleteVolumeInput{VolumeIDs: []string{volumes.Volumes[0].ID, restored.Volumes[0].ID}, Cloud: cloud}
+	if _, err := del.DeleteVolume(); err != nil {
+		t.Fatalf("deleting the volumes: %v", err)
+	}
+	delsnap := volumesnapshot.DeleteSnapshotInput{SnapshotIDs: recorded.IDs(state.KindSnapshot), Cloud: cloud}
+	if _, err := delsnap.DeleteSnapshot(); err != nil {
+		t.Fatalf("deleting the snapshot: %v", err)
+	}
+	recorded, err = backend.Read(ctx, "shop")
+	if err != nil {
+		t.Fatalf("reading the state: %v", err)
+	}
+	if len(recorded.Resources) != 0 {
+		t.Errorf("expected the volumes and snapshot deleted to be removed, got %+v", recorded.Resources)
+	}
+}
+
+func TestAddressesRecordState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cloudy-stack")
+	if err != nil {
+		t.Fatalf("creating the directory of state: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	backend := state.NewLocal(dir)
+	ctx := context.Background()
+
+	client := awsfake.New()
+	cloud := cmn.Cloud{Name: "aws", Region: "us-east-1", Client: client, State: backend, Stack: "shop"}
+	netin := networkcreate.NetworkCreateInput{Name: "web", VpcCidr: "10.0.0.0/16", SubCidr: []string{"10.0.1.0/24"}, Type: "public", Cloud: cloud}
+	networks, err := netin.CreateNetwork()
+	if err != nil {
+		t.Fatalf("creating the network: %v", err)
+	}
+	serverin := servercreate.ServerCreateInput{InstanceName: "app", Count: 1, ImageId: "ami-0123456789", SubnetId: networks.Networks[0].Subnets[0].ID,
+		Flavor: "t2.micro", StaticPublicIp: true, Cloud: cloud}
+	servers, err := serverin.CreateServer()
+	if err != nil {
+		t.Fatalf("creating the server: %v", err)
+	}
+	allocate := addresscreate.AllocateAddressInput{Cloud: cloud}
+	allocated, err := allocate.AllocateAddress()
+	if err != nil {
+		t.Fatalf("allocating the address: %v", err)
+	}
+
+	recorded, err := backend.Read(ctx, "shop")
+	if err != nil {
+		t.Fatalf("reading the state: %v", err)
+	}
+	addresses := recorded.Of(state.KindAddress)
+	if len(addresses) != 2 || addresses[0].Parent != servers.Servers[0].ID || addresses[1].ID != allocated.Addresses[0].ID || addresses[1].Parent != "" {
+		t.Fatalf("expected the address of the server and the one allocated to be recorded, got %+v", addresses)
+	}
+
+	release := addressdelete.ReleaseAddressInput{AddressIDs: []string{allocated.Addresses[0].ID}, Cloud: cloud}
+	if _, err := release.ReleaseAddress(); err != nil {
+		t.Fatalf("releasing the address: %v", err)
+	}
+	del := deleteserver.DeleteServersInput{InstanceIds: []string{servers.Servers[0].ID}, Cloud: cloud}
+	if _, err := del.DeleteServer(); err != nil {
+		t.Fatalf("deleting the server: %v", err)
+	}
+	recorded, err = backend.Read(ctx, "shop")
+	if err != nil {
+		t.Fatalf("reading the state: %v", err)
+	}
+	if left := recorded.Of(state.KindAddress); len(left) != 0 {
+		t.Errorf("expected the addresses released to be removed, got %+v", left)
+	}
+	left, err := client.EC2("us-east-1").DescribeAddressesWithContext(ctx, &ec2.DescribeAddressesInput{})
+	if err != nil {
+		t.Fatalf("describing the addresses: %v", err)
+	}
+	if len(left.Addresses) != 0 {
+		t.Errorf("expected the address of the server deleted to be released, got %v", left.Addresses)
+	}
+}
+
+func TestKeyPairsRecordState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cloudy-stack")
+	if err != nil {
+		t.Fatalf("creating the directory of state: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	backend := state.NewLocal(dir)
+	ctx := context.Background()
+
+	cloud := cmn.Cloud{Name: "aws", Region: "us-east-1", Client: awsfake.New(), State: backend, Stack: "shop"}
+	create := keypaircreate.CreateKeyPairInput{Name: "deploy", Cloud: cloud}
+	if _, err := create.CreateKeyPair(); err != nil {
+		t.Fatalf("creating the key pair: %v", err)
+	}
+	pair, err := sshkey.Generate(1024, "")
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	imp := keypairimport.ImportKeyPairInput{Name: "imported", PublicKey: pair.PublicKey, Cloud: cloud}
+	if _, err := imp.ImportKeyPair(); err != nil {
+		t.Fatalf("importing the key pair: %v", err)
+	}
+	netin := networkcreate.NetworkCreateInput{Name: "web", VpcCidr: "10.0.0.0/16", SubCidr: []string{"10.0.1.0/24"}, Type: "public", Cloud: cloud}
+	networks, err := netin.CreateNetwork()
+	if err != nil {
+		t.Fatalf("creating the network: %v", err)
+	}
+	serverin := servercreate.ServerCreateInput{InstanceName: "app", Count: 1, ImageId: "ami-0123456789", SubnetId: networks.Networks[0].Subnets[0].ID,
+		Flavor: "t2.micro", GenerateKeyPair: true, Cloud: cloud}
+	servers, err := serverin.CreateServer()
+	if err != nil {
+		t.Fatalf("creating the server: %v", err)
+	}
+
+	recorded, err := backend.Read(ctx, "shop")
+	if err != nil {
+		t.Fatalf("reading the state: %v", err)
+	}
+	if ids := recorded.IDs(state.KindKeyPair); len(ids) != 3 || ids[0] != "deploy" || ids[1] != "imported" || ids[2] != servers.KeyPair.Name {
+		t.Fatalf("expected the key pairs created, imported and generated to be recorded, got %v", ids)
+	}
+
+	del := keypairdelete.DeleteKeyPairInput{Names: recorded.IDs(state.KindKeyPair), Cloud: cloud}
+	if _, err := del.DeleteKeyPair(); err != nil {
+		t.Fatalf("deleting the key pairs: %v", err)
+	}
+	recorded, err = backend.Read(ctx, "shop")
+	if err != nil {
+		t.Fatalf("reading the state: %v", err)
+	}
+	if left := recorded.Of(state.KindKeyPair); len(left) != 0 {
+		t.Errorf("expected the key pairs deleted to be removed, got %+v", left)
+	}
+}
+
+func TestAddressNotReleasedRecordState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cloudy-stack")
+	if err != nil {
+		t.Fatalf("creating the directory of state: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	backend := state.NewLocal(dir)
+	ctx := context.Background()
+
+	client := awsfake.New()
+	cloud := cmn.Cloud{Name: "aws", Region: "us-east-1", Client: client, State: backend, Stack: "shop"}
+	netin := networkcreate.NetworkCreateInput{Name: "web", VpcCidr: "10.0.0.0/16", SubCidr: []string{"10.0.1.0/24"}, Type: "public", Cloud: cloud}
+	networks, err := netin.CreateNetwork()
+	if err != nil {
+		t.Fatalf("creating the network: %v", err)
+	}
+	serverin := servercreate.ServerCreateInput{InstanceName: "app", Count: 1, ImageId: "ami-0123456789", SubnetId: networks.Networks[0].Subnets[0].ID,
+		Flavor: "t2.micro", StaticPublicIp: true, Cloud: cloud}
+	servers, err := serverin.CreateServer()
+	if err != nil {
+		t.Fatalf("creating the server: %v", err)
+	}
+
+	client.FailNext("ReleaseAddress", cloudyerror.New(cloudyerror.Conflict, "address in use"))
+	del := deleteserver.DeleteServersInput{InstanceIds: []string{servers.Servers[0].ID}, Cloud: cloud}
+	if _, err := del.DeleteServer(); !cloudyerror.IsConflict(err) {
+		t.Fatalf("expected the release of the address to fail with Conflict, got %v", err)
+	}
+	recorded, err := backend.Read(ctx, "shop")
+	if err != nil {
+		t.Fatalf("reading the state: %v", err)
+	}
+	if left := recorded.Of(state.KindServer); len(left) != 0 {
+		t.Errorf("expected the server deleted to be removed, got %+v", left)
+	}
+	addresses := recorded.Of(state.KindAddress)
+	if len(addresses) != 1 || addresses[0].ID != servers.Servers[0].Extras["allocationid"] || addresses[0].Parent != "" {
+		t.Errorf("expected the address not released to be recorded on its own, got %+v", addresses)
+	}
+}
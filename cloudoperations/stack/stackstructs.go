@@ -0,0 +1,115 @@
+// Package stack creates a whole environment (networks, servers, images and loadbalancers) described in a single spec.
+// The resources of the spec refer to each other by their names, the references decide the order in which the resources
+// are created and the IDs of the resources created (VPC, subnets, instances etc.) are passed on to the ones referring to them.
+// The resources are created with the cloudoperations packages (networkcreate, servercreate etc.), hence the spec is cloud agnostic as those are.
+package stack
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// Spec describes the environment to be created, it is read from JSON or YAML with Parse/Load.
+type Spec struct {
+	// Name of the stack.
+	Name string `json:"name"`
+	// Networks are the networks to be created along with its subnets.
+	Networks []Network `json:"networks,omitempty"`
+	// Servers are the servers to be created in the networks of the stack or in the subnets passed.
+	Servers []Server `json:"servers,omitempty"`
+	// Images are the images to be captured from the servers of the stack.
+	Images []Image `json:"images,omitempty"`
+	// Loadbalancers are the loadbalancers to be created in the networks of the stack, balancing the load across the servers of the stack.
+	Loadbalancers []Loadbalancer `json:"loadbalancers,omitempty"`
+	// All cloud info goes here, every resource of the stack is created in this cloud.
+	Cloud cmn.Cloud `json:"cloud"`
+}
+
+// Network is the network of the stack, it takes the values networkcreate.NetworkCreateInput does.
+type Network struct {
+	// Name of the network, the rest of the resources refer to the network by this name.
+	Name string `json:"name"`
+	// VpcCidr is the CIDR block of the network.
+	VpcCidr string `json:"vpccidr"`
+	// SubCidr are the CIDR blocks of the subnets of the network, the servers refer to the subnets by its index in this list.
+	SubCidr []string `json:"subcidr"`
+	// Type of the network, public or private.
+	Type string `json:"type"`
+	// Ports to be opened for the network, 22 is opened if none are passed.
+	Ports []string `json:"ports,omitempty"`
+}
+
+// Server is the set of servers of the stack, it takes the values servercreate.ServerCreateInput does.
+type Server struct {
+	// Name of the servers, the rest of the resources refer to the servers by this name.
+	Name string `json:"name"`
+	// Count defines the number of servers to be created, 1 is created if it is not set.
+	Count int64 `json:"count,omitempty"`
+	// Image is the name of the image of the stack from which the servers has to be created, use ImageId for the images created elsewhere.
+	Image string `json:"image,omitempty"`
+	// ImageId is the ID of the image from which the servers has to be created.
+	ImageId string `json:"imageid,omitempty"`
+	// Network is the name of the network of the stack in which the servers has to be created, use SubnetId for the networks created elsewhere.
+	Network string `json:"network,omitempty"`
+	// Subnet is the index of the subnet of Network in which the servers has to be created, the first subnet is used by default.
+	Subnet int `json:"subnet,omitempty"`
+	// SubnetId is the ID of the subnet in which the servers has to be created.
+	SubnetId string `json:"subnetid,omitempty"`
+	// KeyName of the ssh keypair to be used to log into the servers.
+	KeyName string `json:"keyname,omitempty"`
+	// Flavor defines the hardware configurations of the servers [ex: t2.micro(aws),n1-standard-2(gcp) etc.]
+	Flavor string `json:"flavor"`
+	// UserData refers to the raw codes that has to be executed immediately after server boots up goes here.
+	UserData string `json:"userdata,omitempty"`
+	// AssignPubIp defines whether a public IP has to be assigned to the servers or not.
+	AssignPubIp bool `json:"assignpubip,omitempty"`
+	// IdempotencyKey if set, makes the creation of the servers safe to retry, see servercreate.ServerCreateInput.
+	IdempotencyKey string `json:"idempotencykey,omitempty"`
+}
+
+// Image is the image of the stack, captured from the servers of the stack.
+type Image struct {
+	// Name of the image, the servers refer to the image by this name.
+	Name string `json:"name"`
+	// Server is the name of the servers of the stack of which the image has to be captured.
+	Server string `json:"server"`
+}
+
+// Loadbalancer is the loadbalancer of the stack, it takes the values createloadbalancer.LbCreateInput does.
+type Loadbalancer struct {
+	// Name of the loadbalancer.
+	Name string `json:"name"`
+	// Type of the loadbalancer ex: classic, application.
+	Type string `json:"type"`
+	// Network is the name of the network of the stack in which the loadbalancer has to be created, it is placed in all the subnets of the network.
+	// Use VpcId and SubnetIds for the networks created elsewhere.
+	Network string `json:"network,omitempty"`
+	// VpcId is the ID of the network in which the loadbalancer has to be created.
+	VpcId string `json:"vpcid,omitempty"`
+	// SubnetIds are the IDs of the subnets in which the loadbalancer has to be created.
+	SubnetIds []string `json:"subnetids,omitempty"`
+	// Targets are the names of the servers of the stack to be registered as the backends of the loadbalancer.
+	Targets []string `json:"targets,omitempty"`
+	// SecurityGroupIds are the IDs of the security groups to be attached to the loadbalancer, the default one of the network is attached if none are passed.
+	SecurityGroupIds []string `json:"securitygroupids,omitempty"`
+	// Scheme of the loadbalancer ex: internal, external.
+	Scheme        string `json:"scheme,omitempty"`
+	SslCert       string `json:"sslcert,omitempty"`
+	SslPolicy     string `json:"sslpolicy,omitempty"`
+	LbPort        int64  `json:"lbport"`
+	InstPort      int64  `json:"instport"`
+	Lbproto       string `json:"lbproto"`
+	Instproto     string `json:"instproto"`
+	HttpCode      string `json:"httpcode,omitempty"`
+	HealthPath    string `json:"healthpath,omitempty"`
+	IpAddressType string `json:"ipaddresstype,omitempty"`
+}
+
+// Outputs holds the resources created by Apply keyed by its name in the spec, in the form common to all the clouds.
+type Outputs struct {
+	Networks      map[string]cmn.Network      `json:"networks,omitempty"`
+	Servers       map[string][]cmn.Server     `json:"servers,omitempty"`
+	Images        map[string][]cmn.Image      `json:"images,omitempty"`
+	Loadbalancers map[string]cmn.LoadBalancer `json:"loadbalancers,omitempty"`
+}
+
+//Nothing much from this file. This file contains only the structs for stack
@@ -0,0 +1,408 @@
+package support
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// The inputs below are the cloud neutral requests handed over to the providers.
+// They mirror the inputs of the respective cloudoperations package field by field,
+// so that the packages can convert their input before routing it to the provider.
+
+// CreateNetworkInput is the request for NetworkProvider.CreateNetwork, mirrors networkcreate.NetworkCreateInput.
+type CreateNetworkInput struct {
+	Name    string       `json:"name"`
+	VpcCidr string       `json:"vpccidr"`
+	SubCidr []string     `json:"subcidr"`
+	Type    string       `json:"type"`
+	Ports   []string     `json:"ports"`
+	Layout  SubnetLayout `json:"layout"`
+	Cloud   cmn.Cloud
+}
+
+// SubnetLayout lays out the subnets of the network when their CIDR blocks are not passed, the blocks of PrefixLength
+// are carved in order out of the block of the network, skipping the ones taken by the subnets present in it.
+// Either Count or the counts per zone has to be set.
+type SubnetLayout struct {
+	// Count is the number of subnets to be created, spread across the zones. The subnets are of the type of the network.
+	Count int `json:"count"`
+	// PublicPerZone is the number of public subnets (routed to the internet gateway) to be created in each zone.
+	PublicPerZone int `json:"publicperzone"`
+	// PrivatePerZone is the number of private subnets to be created in each zone.
+	PrivatePerZone int `json:"privateperzone"`
+	// Zones is the number of zones in which the subnets are created per zone, all the zones of the region are used if not set.
+	Zones int `json:"zones"`
+	// PrefixLength of the blocks of the subnets ex: 24 for /24.
+	PrefixLength int `json:"prefixlength"`
+}
+
+// DeleteNetworkInput is the request for NetworkProvider.DeleteNetwork, mirrors networkdelete.DeleteNetworkInput.
+type DeleteNetworkInput struct {
+	VpcIds      []string `json:"vpcids"`
+	SubnetIds   []string `json:"subnetids"`
+	IgwIds      []string `json:"igwids"`
+	SecurityIds []string `json:"securityids"`
+	Force       bool     `json:"force"`
+	Cloud       cmn.Cloud
+}
+
+// GetNetworksInput is the request for NetworkProvider.GetNetworks, GetAllNetworks and GetSubnets, mirrors networkget.GetNetworksInput.
+type GetNetworksInput struct {
+	SubnetIds []string `json:"subnetids"`
+	ProjectID string
+	NetworkID []string `json:"networkid"`
+	Cloud     cmn.Cloud
+}
+
+// UpdateNetworkInput is the request for NetworkProvider.UpdateNetwork, mirrors networkupdate.NetworkUpdateInput.
+type UpdateNetworkInput struct {
+	Catageory
+	Cloud cmn.Cloud
+}
+
+// Catageory holds the details of the network and its components which has to be updated.
+type Catageory struct {
+	// Resource type that has to be updated.
+	Resource string `json:"resource"`
+	// Action to be performed on the resource
+	// passed in above option.
+	Action string `json:"action"`
+	// Name of the resource that has to be created.
+	Name string `json:"name"`
+	// VpcCidr refers to the CIDR block which will be used to create VPC and this
+	// contains info that how many IP should be present in the network
+	// so decide that in prior before calling this.
+	VpcCidr string `json:"vpccidr"`
+	// SubCidrs refers to the list of CIDR for the subnet that has to be created in the VPC.
+	// Pass an array of CIDR's and neuron will take care of creating
+	// appropriate number of subnets and attaching to created VPC
+	SubCidrs []string `json:"subcidrs"`
+	// Layout carves the CIDR blocks of the subnets to be created out of those of the VPC, when SubCidrs are not passed.
+	Layout SubnetLayout `json:"layout"`
+	// Type of the network that has to be created, public or private.
+	// Accordingly IGW will be created and attached.
+	Type string `json:"type"`
+	// Ports that has to be opened for the network,
+	// if not passed, by default 22 will be made open so that
+	// one can access machines that will be created inside the created network.
+	Ports []string `json:"ports"`
+	// VpcId refers to the Id of the vpc here if you select to update a resource inside it.
+	VpcId string `json:"vpcid"`
+	// Zone name to create subnet in the required zone.
+	Zone string `json:"zone"`
+	// Subnets are the names or IDs of the subnets that has to be deleted from the VPC.
+	Subnets []string `json:"subnets"`
+	// IgwId refers to the Id of the internet gateway to be attached to the VPC,
+	// a new one is created if it is not passed.
+	IgwId string `json:"igwid"`
+}
+
+// CreateServerInput is the request for ServerProvider.CreateServer, mirrors servercreate.ServerCreateInput.
+type CreateServerInput struct {
+	InstanceName         string         `json:"instancename"`
+	Count                int64          `json:"count"`
+	ImageId              string         `json:"imageid"`
+	SubnetId             string         `json:"subnetid"`
+	KeyName              string         `json:"keyname"`
+	GenerateKeyPair      bool           `json:"generatekeypair"`
+	Flavor               string         `json:"flavor"`
+	UserData             string         `json:"userdata"`
+	AssignPubIp          bool           `json:"assignpubip"`
+	StaticPublicIp       bool           `json:"staticpublicip"`
+	IdempotencyKey       string         `json:"idempotencykey,omitempty"`
+	Volumes              []ServerVolume `json:"volumes,omitempty"`
+	MarketType           string         `json:"markettype,omitempty"`
+	MaxPrice             string         `json:"maxprice,omitempty"`
+	InterruptionBehavior string         `json:"interruptionbehavior,omitempty"`
+	Cloud                cmn.Cloud
+}
+
+// ServerVolume is a volume to be created and attached to the server while it is created.
+type ServerVolume struct {
+	// DeviceName is the name of the device at which the volume is attached ex: /dev/sdf.
+	// It defaults to the root device of the image for the root volume, and to the next free one from /dev/sdf for the others.
+	DeviceName string `json:"devicename"`
+	// Root marks the volume as the root volume of the server, the values which are not set are taken from the image.
+	Root bool `json:"root"`
+	// Size of the volume in GiB, it is required for the volumes other than the root one.
+	Size int64 `json:"size"`
+	// Type of the volume gp2, io1 or st1, defaults to gp2.
+	Type string `json:"type"`
+	// Iops to be provisioned for the volume, it is required for io1 and not accepted for the other types.
+	Iops int64 `json:"iops"`
+	// Encrypted encrypts the volume with the key KmsKeyId, or with the default key of the cloud if KmsKeyId is not set.
+	Encrypted bool `json:"encrypted"`
+	// KmsKeyId is the ID or ARN of the key with which the volume is encrypted.
+	KmsKeyId string `json:"kmskeyid"`
+	// DeleteOnTermination decides whether the volume is deleted along with the server, defaults to true.
+	DeleteOnTermination *bool `json:"deleteontermination,omitempty"`
+}
+
+// DeleteServersInput is the request for ServerProvider.DeleteServer, mirrors deleteserver.DeleteServersInput.
+type DeleteServersInput struct {
+	InstanceIds []string `json:"instanceids"`
+	VpcId       string   `json:"vpcid"`
+	Cloud       cmn.Cloud
+}
+
+// GetServersInput is the request for ServerProvider.GetServers and GetAllServers, mirrors getservers.GetServersInput.
+type GetServersInput struct {
+	InstanceIds []string `json:"instanceids"`
+	VpcIds      []string `json:"vpcids"`
+	SubnetIds   []string `json:"subnetids"`
+	Cloud       cmn.Cloud
+}
+
+// UpdateServersInput is the request for ServerProvider.UpdateServers, mirrors updateservers.UpdateServersInput.
+type UpdateServersInput struct {
+	InstanceIds      []string `json:"instanceids"`
+	Action           string   `json:"action"`
+	Flavor           string   `json:"flavor"`
+	UserData         string   `json:"userdata"`
+	SecurityGroupIds []string `json:"securitygroupids"`
+	Cloud            cmn.Cloud
+}
+
+// CreateImageInput is the request for ImageProvider.CreateImage, mirrors imagecreate.CreateImageInput.
+type CreateImageInput struct {
+	InstanceIds []string `json:"instanceids"`
+	Cloud       cmn.Cloud
+}
+
+// DeleteImageInput is the request for ImageProvider.DeleteImage, mirrors imagedelete.DeleteImageInput.
+type DeleteImageInput struct {
+	ImageIds []string `json:"imageids"`
+	Cloud    cmn.Cloud
+}
+
+// GetImagesInput is the request for ImageProvider.GetImages and GetAllImages, mirrors imagesget.GetImagesInput.
+type GetImagesInput struct {
+	ImageIds []string
+	Cloud    cmn.Cloud
+}
+
+// LbCreateInput is the request for LoadbalancerProvider.CreateLoadBalancer, mirrors createloadbalancer.LbCreateInput.
+type LbCreateInput struct {
+	Name              string   `json:"name"`
+	VpcId             string   `json:"vpcid"`
+	SubnetIds         []string `json:"subnetids"`
+	AvailabilityZones []string `json:"availabilityzones"`
+	SecurityGroupIds  []string `json:"securitygroupids"`
+	Scheme            string   `json:"scheme"`
+	Type              string   `json:"type"`
+	SslCert           string   `json:"sslcert"`
+	SslPolicy         string   `json:"sslpolicy"`
+	LbPort            int64    `json:"lbport"`
+	InstPort          int64    `json:"instport"`
+	Lbproto           string   `json:"lbproto"`
+	Instproto         string   `json:"instproto"`
+	HttpCode          string   `json:"httpcode"`
+	HealthPath        string   `json:"healthpath"`
+	IpAddressType     string   `json:"ipaddresstype"`
+	Targets           []string `json:"targets"`
+	Cloud             cmn.Cloud
+}
+
+// LbDeleteInput is the request for LoadbalancerProvider.DeleteLoadBalancer, mirrors deleteloadbalancer.LbDeleteInput.
+type LbDeleteInput struct {
+	LbNames []string `json:"lbnames"`
+	LbArns  []string `json:"lbarns"`
+	Type    string   `json:"type"`
+	Cloud   cmn.Cloud
+}
+
+// GetLoadbalancerInput is the request for LoadbalancerProvider.GetLoadbalancers and GetAllLoadbalancers, mirrors getloadbalancer.GetLoadbalancerInput.
+type GetLoadbalancerInput struct {
+	LbNames []string `json:"lbnames"`
+	LbArns  []string `json:"lbarns"`
+	Type    string   `json:"type"`
+	Cloud   cmn.Cloud
+}
+
+// GetClusterInput is the request for ClusterProvider.GetClusters, mirrors clusters.GetClusterInput.
+type GetClusterInput struct {
+	ClusterName string
+	ProjectID   string
+	Regions     []string
+	Cloud       cmn.Cloud
+}
+
+// GetRegionInput is the request for RegionProvider.GetRegions, mirrors miscoperations.GetRegionInput.
+type GetRegionInput struct {
+	Cloud cmn.Cloud
+}
+
+// CreateSecurityGroupInput is the request for SecurityGroupProvider.CreateSecurityGroup, mirrors securitygroupcreate.CreateSecurityGroupInput.
+type CreateSecurityGroupInput struct {
+	Name        string             `json:"name"`
+	NetworkID   string             `json:"networkid"`
+	Description string             `json:"description"`
+	Rules       []cmn.SecurityRule `json:"rules"`
+	ProjectID   string
+	Cloud       cmn.Cloud
+}
+
+// GetSecurityGroupsInput is the request for SecurityGroupProvider.GetSecurityGroups, mirrors securitygroupget.GetSecurityGroupsInput.
+type GetSecurityGroupsInput struct {
+	SecurityGroupIDs []string `json:"securitygroupids"`
+	NetworkID        string   `json:"networkid"`
+	ProjectID        string
+	Cloud            cmn.Cloud
+}
+
+// UpdateSecurityGroupInput is the request for SecurityGroupProvider.UpdateSecurityGroup, mirrors securitygroupupdate.UpdateSecurityGroupInput.
+type UpdateSecurityGroupInput struct {
+	SecurityGroupIDs []string           `json:"securitygroupids"`
+	Action           string             `json:"action"`
+	Rules            []cmn.SecurityRule `json:"rules"`
+	ProjectID        string
+	Cloud            cmn.Cloud
+}
+
+// DeleteSecurityGroupInput is the request for SecurityGroupProvider.DeleteSecurityGroup, mirrors securitygroupdelete.DeleteSecurityGroupInput.
+type DeleteSecurityGroupInput struct {
+	SecurityGroupIDs []string `json:"securitygroupids"`
+	ProjectID        string
+	Cloud            cmn.Cloud
+}
+
+// CreateVolumeInput is the request for VolumeProvider.CreateVolume, mirrors volumecreate.CreateVolumeInput.
+type CreateVolumeInput struct {
+	Name       string `json:"name"`
+	Zone       string `json:"zone"`
+	Size       int64  `json:"size"`
+	Type       string `json:"type"`
+	Iops       int64  `json:"iops"`
+	Encrypted  bool   `json:"encrypted"`
+	KmsKeyID   string `json:"kmskeyid"`
+	SnapshotID string `json:"snapshotid"`
+	Cloud      cmn.Cloud
+}
+
+// GetVolumesInput is the request for VolumeProvider.GetVolumes, mirrors volumeget.GetVolumesInput.
+type GetVolumesInput struct {
+	VolumeIDs []string `json:"volumeids"`
+	ServerID  string   `json:"serverid"`
+	Cloud     cmn.Cloud
+}
+
+// UpdateVolumeInput is the request for VolumeProvider.UpdateVolume, mirrors volumeupdate.UpdateVolumeInput.
+type UpdateVolumeInput struct {
+	VolumeID   string `json:"volumeid"`
+	Action     string `json:"action"`
+	ServerID   string `json:"serverid"`
+	DeviceName string `json:"devicename"`
+	Force      bool   `json:"force"`
+	Size       int64  `json:"size"`
+	Type       string `json:"type"`
+	Iops       int64  `json:"iops"`
+	Cloud      cmn.Cloud
+}
+
+// DeleteVolumeInput is the request for VolumeProvider.DeleteVolume, mirrors volumedelete.DeleteVolumeInput.
+type DeleteVolumeInput struct {
+	VolumeIDs []string `json:"volumeids"`
+	Cloud     cmn.Cloud
+}
+
+// CreateSnapshotInput is the request for VolumeProvider.CreateSnapshot, mirrors volumesnapshot.CreateSnapshotInput.
+type CreateSnapshotInput struct {
+	VolumeID    string `json:"volumeid"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Cloud       cmn.Cloud
+}
+
+// GetSnapshotsInput is the request for VolumeProvider.GetSnapshots, mirrors volumesnapshot.GetSnapshotsInput.
+type GetSnapshotsInput struct {
+	SnapshotIDs []string `json:"snapshotids"`
+	VolumeID    string   `json:"volumeid"`
+	Cloud       cmn.Cloud
+}
+
+// DeleteSnapshotInput is the request for VolumeProvider.DeleteSnapshot, mirrors volumesnapshot.DeleteSnapshotInput.
+type DeleteSnapshotInput struct {
+	SnapshotIDs []string `json:"snapshotids"`
+	Cloud       cmn.Cloud
+}
+
+// RestoreSnapshotInput is the request for VolumeProvider.RestoreSnapshot, mirrors volumesnapshot.RestoreSnapshotInput.
+type RestoreSnapshotInput struct {
+	SnapshotID string `json:"snapshotid"`
+	Name       string `json:"name"`
+	Zone       string `json:"zone"`
+	Size       int64  `json:"size"`
+	Type       string `json:"type"`
+	Iops       int64  `json:"iops"`
+	Cloud      cmn.Cloud
+}
+
+// AllocateAddressInput is the request for AddressProvider.AllocateAddress, mirrors addresscreate.AllocateAddressInput.
+type AllocateAddressInput struct {
+	Name               string `json:"name"`
+	ServerID           string `json:"serverid"`
+	NetworkInterfaceID string `json:"networkinterfaceid"`
+	Zone               string `json:"zone"`
+	ProjectID          string
+	Cloud              cmn.Cloud
+}
+
+// GetAddressesInput is the request for AddressProvider.GetAddresses, mirrors addressget.GetAddressesInput.
+type GetAddressesInput struct {
+	AddressIDs         []string `json:"addressids"`
+	ServerID           string   `json:"serverid"`
+	NetworkInterfaceID string   `json:"networkinterfaceid"`
+	ProjectID          string
+	Cloud              cmn.Cloud
+}
+
+// UpdateAddressInput is the request for AddressProvider.UpdateAddress, mirrors addressupdate.UpdateAddressInput.
+type UpdateAddressInput struct {
+	AddressID          string `json:"addressid"`
+	Action             string `json:"action"`
+	ServerID           string `json:"serverid"`
+	NetworkInterfaceID string `json:"networkinterfaceid"`
+	Reassociate        bool   `json:"reassociate"`
+	Zone               string `json:"zone"`
+	ProjectID          string
+	Cloud              cmn.Cloud
+}
+
+// ReleaseAddressInput is the request for AddressProvider.ReleaseAddress, mirrors addressdelete.ReleaseAddressInput.
+type ReleaseAddressInput struct {
+	AddressIDs []string `json:"addressids"`
+	ProjectID  string
+	Cloud      cmn.Cloud
+}
+
+// CreateKeyPairInput is the request for KeyPairProvider.CreateKeyPair, mirrors keypaircreate.CreateKeyPairInput.
+type CreateKeyPairInput struct {
+	Name  string `json:"name"`
+	Cloud cmn.Cloud
+}
+
+// ImportKeyPairInput is the request for KeyPairProvider.ImportKeyPair, mirrors keypairimport.ImportKeyPairInput.
+type ImportKeyPairInput struct {
+	Name      string `json:"name"`
+	PublicKey string `json:"publickey"`
+	Cloud     cmn.Cloud
+}
+
+// GetKeyPairsInput is the request for KeyPairProvider.GetKeyPairs, mirrors keypairget.GetKeyPairsInput.
+type GetKeyPairsInput struct {
+	Names []string `json:"names"`
+	Cloud cmn.Cloud
+}
+
+// GetSpotPricesInput is the request for SpotPriceProvider.GetSpotPrices, mirrors spotpriceget.GetSpotPricesInput.
+type GetSpotPricesInput struct {
+	Flavors []string `json:"flavors"`
+	Zones   []string `json:"zones"`
+	Product string   `json:"product"`
+	Cloud   cmn.Cloud
+}
+
+// DeleteKeyPairInput is the request for KeyPairProvider.DeleteKeyPair, mirrors keypairdelete.DeleteKeyPairInput.
+type DeleteKeyPairInput struct {
+	Names []string `json:"names"`
+	Cloud cmn.Cloud
+}
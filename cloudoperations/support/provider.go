@@ -0,0 +1,145 @@
+package support
+
+import (
+	"context"
+)
+
+// Names of the capabilities a provider can implement, these are used while reporting the missing capability.
+const (
+	// NetworkCapability is implemented by the providers satisfying NetworkProvider.
+	NetworkCapability = "network"
+	// ServerCapability is implemented by the providers satisfying ServerProvider.
+	ServerCapability = "server"
+	// ImageCapability is implemented by the providers satisfying ImageProvider.
+	ImageCapability = "image"
+	// LoadbalancerCapability is implemented by the providers satisfying LoadbalancerProvider.
+	LoadbalancerCapability = "loadbalancer"
+	// ClusterCapability is implemented by the providers satisfying ClusterProvider.
+	ClusterCapability = "cluster"
+	// RegionCapability is implemented by the providers satisfying RegionProvider.
+	RegionCapability = "region"
+	// SecurityGroupCapability is implemented by the providers satisfying SecurityGroupProvider.
+	SecurityGroupCapability = "securitygroup"
+	// VolumeCapability is implemented by the providers satisfying VolumeProvider.
+	VolumeCapability = "volume"
+	// AddressCapability is implemented by the providers satisfying AddressProvider.
+	AddressCapability = "address"
+	// KeyPairCapability is implemented by the providers satisfying KeyPairProvider.
+	KeyPairCapability = "keypair"
+	// SpotPriceCapability is implemented by the providers satisfying SpotPriceProvider.
+	SpotPriceCapability = "spotprice"
+)
+
+// Provider is the bare minimum a cloud has to implement to get registered with neuron-cloudy.
+// The capabilities are added by implementing one or more of the capability interfaces
+// (NetworkProvider, ServerProvider etc.), the ones which are not implemented are reported as not implemented.
+type Provider interface {
+	// Name returns the name of the cloud with which the provider is registered, ex: aws, gcp.
+	Name() string
+}
+
+// NetworkProvider is implemented by the providers which can create/delete/update/fetch the network and its components.
+type NetworkProvider interface {
+	Provider
+	CreateNetwork(context.Context, *CreateNetworkInput) (CreateNetworkResponse, error)
+	DeleteNetwork(context.Context, *DeleteNetworkInput) (DeleteNetworkResponse, error)
+	GetNetworks(context.Context, *GetNetworksInput) (GetNetworksResponse, error)
+	GetAllNetworks(context.Context, *GetNetworksInput) ([]GetNetworksResponse, error)
+	GetSubnets(context.Context, *GetNetworksInput) (GetSubnetsResponse, error)
+	UpdateNetwork(context.Context, *UpdateNetworkInput) (UpdateNetworkResponse, error)
+}
+
+// ServerProvider is implemented by the providers which can create/delete/update/fetch the servers.
+type ServerProvider interface {
+	Provider
+	CreateServer(context.Context, *CreateServerInput) (ServerCreateResponse, error)
+	DeleteServer(context.Context, *DeleteServersInput) (DeleteServerResponse, error)
+	GetServers(context.Context, *GetServersInput) (GetServerResponse, error)
+	GetAllServers(context.Context, *GetServersInput) ([]GetServerResponse, error)
+	UpdateServers(context.Context, *UpdateServersInput) (UpdateServersResponse, error)
+}
+
+// ImageProvider is implemented by the providers which can capture/delete/fetch the images.
+type ImageProvider interface {
+	Provider
+	CreateImage(context.Context, *CreateImageInput) (CreateImageResponse, error)
+	DeleteImage(context.Context, *DeleteImageInput) (DeleteImageResponse, error)
+	GetImages(context.Context, *GetImagesInput) (GetImagesResponse, error)
+	GetAllImages(context.Context, *GetImagesInput) ([]GetImagesResponse, error)
+}
+
+// LoadbalancerProvider is implemented by the providers which can create/delete/fetch the loadbalancers.
+type LoadbalancerProvider interface {
+	Provider
+	CreateLoadBalancer(context.Context, *LbCreateInput) (LoadBalanceResponse, error)
+	DeleteLoadBalancer(context.Context, *LbDeleteInput) (LoadBalancerDeleteResponse, error)
+	GetLoadbalancers(context.Context, *GetLoadbalancerInput) (GetLoadbalancerResponse, error)
+	GetAllLoadbalancers(context.Context, *GetLoadbalancerInput) ([]GetLoadbalancerResponse, error)
+}
+
+// ClusterProvider is implemented by the providers which can fetch the kubernetes clusters.
+type ClusterProvider interface {
+	Provider
+	GetClusters(context.Context, *GetClusterInput) (ClusterResponse, error)
+}
+
+// RegionProvider is implemented by the providers which can list the regions of the cloud.
+type RegionProvider interface {
+	Provider
+	GetRegions(context.Context, *GetRegionInput) (GetRegionsResponse, error)
+}
+
+// SecurityGroupProvider is implemented by the providers which can create/delete/update/fetch the security groups and their rules.
+type SecurityGroupProvider interface {
+	Provider
+	CreateSecurityGroup(context.Context, *CreateSecurityGroupInput) (SecurityGroupResponse, error)
+	DeleteSecurityGroup(context.Context, *DeleteSecurityGroupInput) (SecurityGroupResponse, error)
+	GetSecurityGroups(context.Context, *GetSecurityGroupsInput) (SecurityGroupResponse, error)
+	UpdateSecurityGroup(context.Context, *UpdateSecurityGroupInput) (SecurityGroupResponse, error)
+}
+
+// VolumeProvider is implemented by the providers which can create/fetch/update/delete the block storage volumes and their snapshots.
+type VolumeProvider interface {
+	Provider
+	CreateVolume(context.Context, *CreateVolumeInput) (VolumeResponse, error)
+	GetVolumes(context.Context, *GetVolumesInput) (VolumeResponse, error)
+	UpdateVolume(context.Context, *UpdateVolumeInput) (VolumeResponse, error)
+	DeleteVolume(context.Context, *DeleteVolumeInput) (VolumeResponse, error)
+	CreateSnapshot(context.Context, *CreateSnapshotInput) (SnapshotResponse, error)
+	GetSnapshots(context.Context, *GetSnapshotsInput) (SnapshotResponse, error)
+	DeleteSnapshot(context.Context, *DeleteSnapshotInput) (SnapshotResponse, error)
+	RestoreSnapshot(context.Context, *RestoreSnapshotInput) (VolumeResponse, error)
+}
+
+// AddressProvider is implemented by the providers which can allocate/fetch/update/release the static public IP addresses.
+type AddressProvider interface {
+	Provider
+	AllocateAddress(context.Context, *AllocateAddressInput) (AddressResponse, error)
+	GetAddresses(context.Context, *GetAddressesInput) (AddressResponse, error)
+	UpdateAddress(context.Context, *UpdateAddressInput) (AddressResponse, error)
+	ReleaseAddress(context.Context, *ReleaseAddressInput) (AddressResponse, error)
+}
+
+// KeyPairProvider is implemented by the providers which can create/import/fetch/delete the ssh key pairs.
+type KeyPairProvider interface {
+	Provider
+	CreateKeyPair(context.Context, *CreateKeyPairInput) (KeyPairResponse, error)
+	ImportKeyPair(context.Context, *ImportKeyPairInput) (KeyPairResponse, error)
+	GetKeyPairs(context.Context, *GetKeyPairsInput) (KeyPairResponse, error)
+	DeleteKeyPair(context.Context, *DeleteKeyPairInput) (KeyPairResponse, error)
+}
+
+// SpotPriceProvider is implemented by the providers which can report the current prices of the spot servers.
+type SpotPriceProvider interface {
+	Provider
+	GetSpotPrices(context.Context, *GetSpotPricesInput) (SpotPriceResponse, error)
+}
+
+// Planner is implemented by the providers which honor DryRun of the cloud, they answer the requests which
+// create/update/delete the resources with the plan of the actions in place of performing them.
+// The requests with DryRun set are never routed to the providers which does not plan, as they would be performed.
+type Planner interface {
+	Provider
+	// Plans reports whether the provider honors DryRun.
+	Plans() bool
+}
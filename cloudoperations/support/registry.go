@@ -0,0 +1,204 @@
+package support
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	common "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/common"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+var (
+	providersMu sync.RWMutex
+	providers   = make(map[string]Provider)
+)
+
+// Register makes the provider available to cloudoperations under the name returned by its Name.
+// Once registered, a cloud is reported as supported by DoesCloudSupports.
+// If Register is called twice with the same name or if provider is nil, it panics.
+func Register(provider Provider) {
+	if provider == nil {
+		panic("support: Register provider is nil")
+	}
+	name := strings.ToLower(provider.Name())
+
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	if _, dup := providers[name]; dup {
+		panic("support: Register called twice for provider " + name)
+	}
+	providers[name] = provider
+}
+
+// Providers returns the sorted list of the names of the registered providers.
+func Providers() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetProvider returns the provider registered for the cloud passed.
+func GetProvider(cloud string) (Provider, error) {
+	providersMu.RLock()
+	provider, ok := providers[strings.ToLower(cloud)]
+	providersMu.RUnlock()
+	if !ok {
+		return nil, cloudyerror.New(cloudyerror.Unsupported, common.DefaultCloudResponse+"GetProvider")
+	}
+	return provider, nil
+}
+
+// NotImplemented returns the error which has to be reported when a cloud does not implement the capability/operation asked for.
+func NotImplemented(cloud, capability string) error {
+	return cloudyerror.Newf(cloudyerror.Unsupported, common.CapabilityNotImplemented, capability, strings.ToLower(cloud))
+}
+
+// GetNetworkProvider returns the network capability of the provider registered for the cloud passed.
+func GetNetworkProvider(cloud string) (NetworkProvider, error) {
+	provider, err := GetProvider(cloud)
+	if err != nil {
+		return nil, err
+	}
+	if capable, ok := provider.(NetworkProvider); ok {
+		return capable, nil
+	}
+	return nil, NotImplemented(cloud, NetworkCapability)
+}
+
+// GetServerProvider returns the server capability of the provider registered for the cloud passed.
+func GetServerProvider(cloud string) (ServerProvider, error) {
+	provider, err := GetProvider(cloud)
+	if err != nil {
+		return nil, err
+	}
+	if capable, ok := provider.(ServerProvider); ok {
+		return capable, nil
+	}
+	return nil, NotImplemented(cloud, ServerCapability)
+}
+
+// GetImageProvider returns the image capability of the provider registered for the cloud passed.
+func GetImageProvider(cloud string) (ImageProvider, error) {
+	provider, err := GetProvider(cloud)
+	if err != nil {
+		return nil, err
+	}
+	if capable, ok := provider.(ImageProvider); ok {
+		return capable, nil
+	}
+	return nil, NotImplemented(cloud, ImageCapability)
+}
+
+// GetLoadbalancerProvider returns the loadbalancer capability of the provider registered for the cloud passed.
+func GetLoadbalancerProvider(cloud string) (LoadbalancerProvider, error) {
+	provider, err := GetProvider(cloud)
+	if err != nil {
+		return nil, err
+	}
+	if capable, ok := provider.(LoadbalancerProvider); ok {
+		return capable, nil
+	}
+	return nil, NotImplemented(cloud, LoadbalancerCapability)
+}
+
+// GetClusterProvider returns the cluster capability of the provider registered for the cloud passed.
+func GetClusterProvider(cloud string) (ClusterProvider, error) {
+	provider, err := GetProvider(cloud)
+	if err != nil {
+		return nil, err
+	}
+	if capable, ok := provider.(ClusterProvider); ok {
+		return capable, nil
+	}
+	return nil, NotImplemented(cloud, ClusterCapability)
+}
+
+// GetRegionProvider returns the region capability of the provider registered for the cloud passed.
+func GetRegionProvider(cloud string) (RegionProvider, error) {
+	provider, err := GetProvider(cloud)
+	if err != nil {
+		return nil, err
+	}
+	if capable, ok := provider.(RegionProvider); ok {
+		return capable, nil
+	}
+	return nil, NotImplemented(cloud, RegionCapability)
+}
+
+// GetSecurityGroupProvider returns the security group capability of the provider registered for the cloud passed.
+func GetSecurityGroupProvider(cloud string) (SecurityGroupProvider, error) {
+	provider, err := GetProvider(cloud)
+	if err != nil {
+		return nil, err
+	}
+	if capable, ok := provider.(SecurityGroupProvider); ok {
+		return capable, nil
+	}
+	return nil, NotImplemented(cloud, SecurityGroupCapability)
+}
+
+// GetVolumeProvider returns the volume capability of the provider registered for the cloud passed.
+func GetVolumeProvider(cloud string) (VolumeProvider, error) {
+	provider, err := GetProvider(cloud)
+	if err != nil {
+		return nil, err
+	}
+	if capable, ok := provider.(VolumeProvider); ok {
+		return capable, nil
+	}
+	return nil, NotImplemented(cloud, VolumeCapability)
+}
+
+// GetAddressProvider returns the address capability of the provider registered for the cloud passed.
+func GetAddressProvider(cloud string) (AddressProvider, error) {
+	provider, err := GetProvider(cloud)
+	if err != nil {
+		return nil, err
+	}
+	if capable, ok := provider.(AddressProvider); ok {
+		return capable, nil
+	}
+	return nil, NotImplemented(cloud, AddressCapability)
+}
+
+// GetKeyPairProvider returns the keypair capability of the provider registered for the cloud passed.
+func GetKeyPairProvider(cloud string) (KeyPairProvider, error) {
+	provider, err := GetProvider(cloud)
+	if err != nil {
+		return nil, err
+	}
+	if capable, ok := provider.(KeyPairProvider); ok {
+		return capable, nil
+	}
+	return nil, NotImplemented(cloud, KeyPairCapability)
+}
+
+// GetSpotPriceProvider returns the spotprice capability of the provider registered for the cloud passed.
+func GetSpotPriceProvider(cloud string) (SpotPriceProvider, error) {
+	provider, err := GetProvider(cloud)
+	if err != nil {
+		return nil, err
+	}
+	if capable, ok := provider.(SpotPriceProvider); ok {
+		return capable, nil
+	}
+	return nil, NotImplemented(cloud, SpotPriceCapability)
+}
+
+// CheckDryRun returns an error if DryRun is asked for but the provider passed does not plan,
+// this has to be checked before routing the requests which create/update/delete the resources.
+func CheckDryRun(provider Provider, dryRun bool) error {
+	if !dryRun {
+		return nil
+	}
+	if planner, ok := provider.(Planner); ok && planner.Plans() {
+		return nil
+	}
+	return cloudyerror.Newf(cloudyerror.Unsupported, common.DryRunNotImplemented, strings.ToLower(provider.Name()))
+}
@@ -0,0 +1,337 @@
+package support
+
+import (
+	awsoperations "github.com/nikhilsbhat/neuron-cloudy/cloud/aws/operations"
+	gcp "github.com/nikhilsbhat/neuron-cloudy/cloud/gcp/operations"
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// The responses below are given out by the providers, and are the very responses the cloudoperations packages hand back to the caller.
+// Every provider fills the resources of the response (Networks, Servers etc.) in the form common to all the clouds,
+// the fields specific to the cloud (AwsResponse, GCPResponse etc.) are still filled for the callers relying on them.
+
+// CreateNetworkResponse is a struct that will return the filtered/unfiltered responses of variuos clouds.
+type CreateNetworkResponse struct {
+	// Networks holds the network created, in the form common to all the clouds.
+	Networks []cmn.Network `json:"Networks,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse awsoperations.NetworkResponse `json:"AwsResponse,omitempty"`
+	// Contains filtered/unfiltered response of Azure.
+	AzureResponse string `json:"AzureResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"DefaultResponse,omitempty"`
+	// Plan holds the actions which would be performed, this is set in place of the response when DryRun of the cloud is set.
+	Plan *cmn.Plan `json:"Plan,omitempty"`
+}
+
+// DeleteNetworkResponse returns the filtered/unfiltered responses of variuos clouds.
+type DeleteNetworkResponse struct {
+	// Networks holds the networks deleted, in the form common to all the clouds.
+	Networks []cmn.Network `json:"Networks,omitempty"`
+	// Deleted is the log of the deletion, it holds every resource deleted along with the networks in the order they were deleted.
+	Deleted []cmn.DeletedResource `json:"Deleted,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse awsoperations.DeleteNetworkResponse `json:"AwsResponse,omitempty"`
+	// Contains filtered/unfiltered response of Azure.
+	AzureResponse string `json:"AzureResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"DefaultResponse,omitempty"`
+	// Plan holds the actions which would be performed, this is set in place of the response when DryRun of the cloud is set.
+	Plan *cmn.Plan `json:"Plan,omitempty"`
+}
+
+// GetNetworksResponse will return the filtered/unfiltered responses of variuos clouds.
+type GetNetworksResponse struct {
+	// Networks holds the networks fetched, in the form common to all the clouds.
+	Networks []cmn.Network `json:"Networks,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse []awsoperations.NetworkResponse `json:"AwsResponse,omitempty"`
+	// Contains filtered/unfiltered response of Azure.
+	AzureResponse string                `json:"AzureResponse,omitempty"`
+	GCPResponse   []gcp.NetworkResponse `json:"GcpResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"DefaultResponse,omitempty"`
+	// Region is the region the response is of, this is set by the operations spanning all the regions (ex: GetAll*).
+	Region string `json:"Region,omitempty"`
+	// Error is the error with which the operation failed in Region, the rest of the regions are still fetched.
+	Error *cloudyerror.Error `json:"Error,omitempty"`
+}
+
+// GetSubnetsResponse will return the filtered/unfiltered responses of variuos clouds.
+type GetSubnetsResponse struct {
+	// Subnets holds the subnets fetched, in the form common to all the clouds.
+	Subnets []cmn.Subnet `json:"Subnets,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse awsoperations.NetworkResponse `json:"AwsResponse,omitempty"`
+	// Contains filtered/unfiltered response of Azure.
+	AzureResponse string `json:"AzureResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"DefaultResponse,omitempty"`
+}
+
+// UpdateNetworkResponse will return the filtered/unfiltered responses of variuos clouds.
+type UpdateNetworkResponse struct {
+	// Networks holds the network updated, in the form common to all the clouds.
+	Networks []cmn.Network `json:"Networks,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse awsoperations.NetworkResponse `json:"AwsResponse,omitempty"`
+	// Contains filtered/unfiltered response of Azure.
+	AzureResponse string `json:"AzureResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"DefaultResponse,omitempty"`
+	// Plan holds the actions which would be performed, this is set in place of the response when DryRun of the cloud is set.
+	Plan *cmn.Plan `json:"Plan,omitempty"`
+}
+
+// ServerCreateResponse will return the filtered/unfiltered responses of variuos clouds.
+type ServerCreateResponse struct {
+	// Servers holds the servers created, in the form common to all the clouds.
+	Servers []cmn.Server `json:"Servers,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse []awsoperations.ServerResponse `json:"AwsResponse,omitempty"`
+	// Contains filtered/unfiltered response of Azure.
+	AzureResponse string `json:"AzureResponse,omitempty"`
+	// KeyPair is the key pair generated for the servers when asked for GenerateKeyPair, along with its private key.
+	// This is the only time the private key is handed out, it cannot be fetched again.
+	KeyPair *cmn.KeyPair `json:"KeyPair,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse interface{} `json:"DefaultResponse,omitempty"`
+	// Plan holds the actions which would be performed, this is set in place of the response when DryRun of the cloud is set.
+	Plan *cmn.Plan `json:"Plan,omitempty"`
+}
+
+// DeleteServerResponse will return the filtered/unfiltered responses of variuos clouds.
+type DeleteServerResponse struct {
+	// Servers holds the servers deleted, in the form common to all the clouds.
+	Servers []cmn.Server `json:"Servers,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse []awsoperations.ServerResponse `json:"AwsResponse,omitempty"`
+	// Contains filtered/unfiltered response of Azure.
+	AzureResponse string `json:"AzureResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"Response,omitempty"`
+	// Plan holds the actions which would be performed, this is set in place of the response when DryRun of the cloud is set.
+	Plan *cmn.Plan `json:"Plan,omitempty"`
+}
+
+// GetServerResponse will return the filtered/unfiltered responses of variuos clouds.
+type GetServerResponse struct {
+	// Servers holds the servers fetched, in the form common to all the clouds.
+	Servers []cmn.Server `json:"Servers,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse []awsoperations.ServerResponse `json:"AwsResponse,omitempty"`
+	// Contains filtered/unfiltered response of Azure.
+	AzureResponse string `json:"AzureResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"Response,omitempty"`
+	// Region is the region the response is of, this is set by the operations spanning all the regions (ex: GetAll*).
+	Region string `json:"Region,omitempty"`
+	// Error is the error with which the operation failed in Region, the rest of the regions are still fetched.
+	Error *cloudyerror.Error `json:"Error,omitempty"`
+}
+
+// UpdateServersResponse will return the filtered/unfiltered responses of variuos clouds.
+type UpdateServersResponse struct {
+	// Servers holds the servers updated, in the form common to all the clouds.
+	Servers []cmn.Server `json:"Servers,omitempty"`
+	// Changes holds the settings of every server updated before and after the update.
+	Changes []cmn.ServerChange `json:"Changes,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse []awsoperations.ServerResponse `json:"AwsResponse,omitempty"`
+	// Contains filtered/unfiltered response of Azure.
+	AzureResponse string `json:"AzureResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"Response,omitempty"`
+	// Plan holds the actions which would be performed, this is set in place of the response when DryRun of the cloud is set.
+	Plan *cmn.Plan `json:"Plan,omitempty"`
+}
+
+// CreateImageResponse contains the details of the images captured by CreateImage.
+// This also can contain the response from various cloud, but will deliver what was passed to it.
+type CreateImageResponse struct {
+	// Images holds the images captured, in the form common to all the clouds.
+	Images []cmn.Image `json:"Images,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse []awsoperations.ImageResponse `json:"AwsResponse,omitempty"`
+	// Contains filtered/unfiltered response of Azure.
+	AzureResponse string `json:"AzureResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"DefaultResponse,omitempty"`
+	// Plan holds the actions which would be performed, this is set in place of the response when DryRun of the cloud is set.
+	Plan *cmn.Plan `json:"Plan,omitempty"`
+}
+
+// DeleteImageResponse contains the details of the images deleted by DeleteImage.
+// This also can contain the response from various cloud, but will deliver what was passed to it.
+type DeleteImageResponse struct {
+	// Images holds the images deleted, in the form common to all the clouds.
+	Images []cmn.Image `json:"Images,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse []awsoperations.ImageResponse `json:"AwsResponse,omitempty"`
+	// Contains filtered/unfiltered response of Azure.
+	AzureResponse string `json:"AzureResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"Response,omitempty"`
+	// Plan holds the actions which would be performed, this is set in place of the response when DryRun of the cloud is set.
+	Plan *cmn.Plan `json:"Plan,omitempty"`
+}
+
+// GetImagesResponse contains the details of the images collected by GetImage.
+// This also can contain the response from various cloud, but will deliver what was passed to it.
+type GetImagesResponse struct {
+	// Images holds the images fetched, in the form common to all the clouds.
+	Images []cmn.Image `json:"Images,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse []awsoperations.ImageResponse `json:"AwsResponse,omitempty"`
+	// Contains filtered/unfiltered response of Azure.
+	AzureResponse string `json:"AzureResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"DefaultResponse,omitempty"`
+	// Region is the region the response is of, this is set by the operations spanning all the regions (ex: GetAll*).
+	Region string `json:"Region,omitempty"`
+	// Error is the error with which the operation failed in Region, the rest of the regions are still fetched.
+	Error *cloudyerror.Error `json:"Error,omitempty"`
+}
+
+// LoadBalanceResponse will return the filtered/unfiltered responses of variuos clouds.
+type LoadBalanceResponse struct {
+	// LoadBalancers holds the loadbalancer created, in the form common to all the clouds.
+	LoadBalancers []cmn.LoadBalancer `json:"LoadBalancers,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse awsoperations.LoadBalanceResponse `json:"AwsResponse,omitempty"`
+	// Contains filtered/unfiltered response of Azure.
+	AzureResponse string `json:"AzureResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"Response,omitempty"`
+	// Plan holds the actions which would be performed, this is set in place of the response when DryRun of the cloud is set.
+	Plan *cmn.Plan `json:"Plan,omitempty"`
+}
+
+// LoadBalancerDeleteResponse will return the filtered/unfiltered responses of variuos clouds.
+type LoadBalancerDeleteResponse struct {
+	// LoadBalancers holds the loadbalancers deleted, in the form common to all the clouds.
+	LoadBalancers []cmn.LoadBalancer `json:"LoadBalancers,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse []awsoperations.LoadBalanceDeleteResponse `json:"AwsResponse,omitempty"`
+	// Contains filtered/unfiltered response of Azure.
+	AzureResponse string `json:"AzureResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"DefaultResponse,omitempty"`
+	// Plan holds the actions which would be performed, this is set in place of the response when DryRun of the cloud is set.
+	Plan *cmn.Plan `json:"Plan,omitempty"`
+}
+
+// GetLoadbalancerResponse will return the filtered/unfiltered responses of variuos clouds.
+type GetLoadbalancerResponse struct {
+	// LoadBalancers holds the loadbalancers fetched, in the form common to all the clouds.
+	LoadBalancers []cmn.LoadBalancer `json:"LoadBalancers,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse []awsoperations.LoadBalanceResponse `json:"AwsResponse,omitempty"`
+	// Contains filtered/unfiltered response of Azure.
+	AzureResponse string `json:"AzureResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"Response,omitempty"`
+	// Region is the region the response is of, this is set by the operations spanning all the regions (ex: GetAll*).
+	Region string `json:"Region,omitempty"`
+	// Error is the error with which the operation failed in Region, the rest of the regions are still fetched.
+	Error *cloudyerror.Error `json:"Error,omitempty"`
+}
+
+// ClusterResponse returns the filtered/unfiltered responses of variuos clouds.
+type ClusterResponse struct {
+	// Clusters holds the clusters fetched, in the form common to all the clouds.
+	Clusters []cmn.Cluster `json:"Clusters,omitempty"`
+	// Contains filtered/unfiltered response from AWS.
+	AwsResponse string `json:"AwsResponse,omitempty"`
+	// Contains filtered/unfiltered response from Azure.
+	AzureResponse string `json:"AzureResponse,omitempty"`
+	// Contains filtered/unfiltered response from GCP.
+	GCPResponse []gcp.ClusterResponse `json:"GcpResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"DefaultResponse,omitempty"`
+}
+
+// GetRegionsResponse return the filtered/unfiltered responses of variuos clouds.
+type GetRegionsResponse struct {
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse awsoperations.CommonResponse `json:"Regions,omitempty"`
+	// Contains filtered/unfiltered response of Azure.
+	AzureResponse string `json:"AzureResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"DefaultResponse,omitempty"`
+}
+
+// SecurityGroupResponse returns the filtered/unfiltered responses of variuos clouds on the security groups.
+type SecurityGroupResponse struct {
+	// SecurityGroups holds the security groups created/fetched/updated/deleted, in the form common to all the clouds.
+	SecurityGroups []cmn.SecurityGroup `json:"SecurityGroups,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse []awsoperations.SecurityGroupResponse `json:"AwsResponse,omitempty"`
+	// Contains filtered/unfiltered response from GCP.
+	GCPResponse []gcp.FirewallResponse `json:"GcpResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"DefaultResponse,omitempty"`
+	// Plan holds the actions which would be performed, this is set in place of the response when DryRun of the cloud is set.
+	Plan *cmn.Plan `json:"Plan,omitempty"`
+}
+
+// VolumeResponse returns the filtered/unfiltered responses of variuos clouds on the volumes.
+type VolumeResponse struct {
+	// Volumes holds the volumes created/fetched/updated/deleted, in the form common to all the clouds.
+	Volumes []cmn.Volume `json:"Volumes,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse []awsoperations.VolumeResponse `json:"AwsResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"DefaultResponse,omitempty"`
+	// Plan holds the actions which would be performed, this is set in place of the response when DryRun of the cloud is set.
+	Plan *cmn.Plan `json:"Plan,omitempty"`
+}
+
+// SnapshotResponse returns the filtered/unfiltered responses of variuos clouds on the snapshots of the volumes.
+type SnapshotResponse struct {
+	// Snapshots holds the snapshots created/fetched/deleted, in the form common to all the clouds.
+	Snapshots []cmn.Snapshot `json:"Snapshots,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse []awsoperations.SnapshotResponse `json:"AwsResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"DefaultResponse,omitempty"`
+	// Plan holds the actions which would be performed, this is set in place of the response when DryRun of the cloud is set.
+	Plan *cmn.Plan `json:"Plan,omitempty"`
+}
+
+// AddressResponse returns the filtered/unfiltered responses of variuos clouds on the static public IP addresses.
+type AddressResponse struct {
+	// Addresses holds the addresses allocated/fetched/updated/released, in the form common to all the clouds.
+	Addresses []cmn.Address `json:"Addresses,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse []awsoperations.AddressResponse `json:"AwsResponse,omitempty"`
+	// Contains filtered/unfiltered response from GCP.
+	GCPResponse []gcp.AddressResponse `json:"GcpResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"DefaultResponse,omitempty"`
+	// Plan holds the actions which would be performed, this is set in place of the response when DryRun of the cloud is set.
+	Plan *cmn.Plan `json:"Plan,omitempty"`
+}
+
+// KeyPairResponse returns the filtered/unfiltered responses of variuos clouds on the ssh key pairs.
+type KeyPairResponse struct {
+	// KeyPairs holds the key pairs created/imported/fetched/deleted, in the form common to all the clouds.
+	KeyPairs []cmn.KeyPair `json:"KeyPairs,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse []awsoperations.KeyPairResponse `json:"AwsResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"DefaultResponse,omitempty"`
+	// Plan holds the actions which would be performed, this is set in place of the response when DryRun of the cloud is set.
+	Plan *cmn.Plan `json:"Plan,omitempty"`
+}
+
+// SpotPriceResponse returns the filtered/unfiltered responses of variuos clouds on the spot prices.
+type SpotPriceResponse struct {
+	// SpotPrices holds the current prices of the spot servers with the cheapest first, in the form common to all the clouds.
+	SpotPrices []cmn.SpotPrice `json:"SpotPrices,omitempty"`
+	// Contains filtered/unfiltered response of AWS.
+	AwsResponse []awsoperations.SpotPriceResponse `json:"AwsResponse,omitempty"`
+	// Default response if no inputs or matching the values required.
+	DefaultResponse string `json:"DefaultResponse,omitempty"`
+}
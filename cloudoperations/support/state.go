@@ -0,0 +1,376 @@
+package support
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
+)
+
+// Track runs the operation holding the lock of the stack of the cloud, and records the change made by it in the state of the stack (see state.Track).
+// The operation is run as is if either State or Stack of the cloud is not set, or if DryRun is set as nothing is changed then.
+func Track(ctx context.Context, cloud cmn.Cloud, operation func() (state.Change, error)) error {
+	if cloud.State == nil || cloud.Stack == "" || cloud.DryRun {
+		_, err := operation()
+		return err
+	}
+	return state.Track(ctx, cloud.State, cloud.Stack, operation)
+}
+
+// NetworksCreated returns the change of the networks created, the subnets, internet gateway and security groups are recorded as part of the network.
+// The CIDRs of the network and subnets, and the ports opened on the security groups are recorded as their attributes.
+func NetworksCreated(cloud cmn.Cloud, networks []cmn.Network) state.Change {
+	change := state.Change{}
+	for _, network := range networks {
+		recorded := stateOf(cloud, state.KindNetwork, network.Resource, "")
+		withAttribute(&recorded, state.AttributeCidr, network.CIDRs)
+		change.Created = append(change.Created, recorded)
+		for _, subnet := range network.Subnets {
+			recorded := stateOf(cloud, state.KindSubnet, subnet.Resource, network.ID)
+			withAttribute(&recorded, state.AttributeCidr, subnet.CIDRs)
+			change.Created = append(change.Created, recorded)
+		}
+		if igw, ok := network.Extras["igwid"].(string); ok && igw != "" {
+			change.Created = append(change.Created, stateOf(cloud, state.KindInternetGateway, cmn.Resource{ID: igw, Region: network.Region}, network.ID))
+		}
+		for _, group := range network.SecurityGroupIDs {
+			recorded := stateOf(cloud, state.KindSecurityGroup, cmn.Resource{ID: group, Region: network.Region}, network.ID)
+			for _, details := range network.SecurityGroups {
+				if details.ID == group {
+					recorded.Name = details.Name
+					recorded.Attributes = map[string]string{state.AttributePorts: strings.Join(details.Ports, ",")}
+				}
+			}
+			change.Created = append(change.Created, recorded)
+		}
+	}
+	return change
+}
+
+// NetworksDeleted returns the change of the networks deleted, the components of the networks are removed along with them.
+// The resources deleted along with the networks (ex: servers of the network force deleted) are removed as well.
+func NetworksDeleted(networks []cmn.Network, deleted ...cmn.DeletedResource) state.Change {
+	change := state.Change{}
+	for _, resource := range deleted {
+		change.Deleted = append(change.Deleted, resource.ID)
+	}
+	for _, network := range networks {
+		change.Deleted = append(change.Deleted, network.ID)
+	}
+	return change
+}
+
+// NetworkUpdated returns the change of the network updated, the subnets created or deleted are recorded or removed accordingly.
+// The CIDRs of the network and the ports opened on its security groups are recorded afresh when they are updated.
+func NetworkUpdated(cloud cmn.Cloud, update Catageory, networks []cmn.Network) state.Change {
+	change := state.Change{}
+	for _, network := range networks {
+		switch strings.ToLower(update.Resource) {
+		case "subnets":
+			for _, subnet := range network.Subnets {
+				if strings.ToLower(update.Action) == "delete" {
+					change.Deleted = append(change.Deleted, subnet.ID)
+					continue
+				}
+				parent := subnet.NetworkID
+				if parent == "" {
+					parent = update.VpcId
+				}
+				recorded := stateOf(cloud, state.KindSubnet, subnet.Resource, parent)
+				withAttribute(&recorded, state.AttributeCidr, subnet.CIDRs)
+				change.Created = append(change.Created, recorded)
+			}
+		case "vpc":
+			switch strings.ToLower(update.Action) {
+			case "addcidr", "removecidr":
+				recorded := stateOf(cloud, state.KindNetwork, network.Resource, "")
+				withAttribute(&recorded, state.AttributeCidr, network.CIDRs)
+				change.Created = append(change.Created, recorded)
+			}
+		case "securitygroup":
+			for _, group := range network.SecurityGroups {
+				// the default security group is created by the cloud along with the network, it is not recorded.
+				if group.Name == "default" {
+					continue
+				}
+				recorded := stateOf(cloud, state.KindSecurityGroup, group.Resource, update.VpcId)
+				recorded.Attributes = map[string]string{state.AttributePorts: strings.Join(group.Ports, ",")}
+				change.Created = append(change.Created, recorded)
+			}
+		}
+	}
+	return change
+}
+
+// SecurityGroupsCreated returns the change of the security groups created, they are recorded as part of their network along with the ports they open.
+// The security groups updated are recorded afresh with it, as the ports they open would have changed.
+func SecurityGroupsCreated(cloud cmn.Cloud, groups []cmn.SecurityGroup) state.Change {
+	change := state.Change{}
+	for _, group := range groups {
+		recorded := stateOf(cloud, state.KindSecurityGroup, group.Resource, group.NetworkID)
+		recorded.Attributes = map[string]string{state.AttributePorts: strings.Join(group.Ports, ",")}
+		change.Created = append(change.Created, recorded)
+	}
+	return change
+}
+
+// SecurityGroupsDeleted returns the change of the security groups deleted.
+func SecurityGroupsDeleted(groups []cmn.SecurityGroup) state.Change {
+	change := state.Change{}
+	for _, group := range groups {
+		change.Deleted = append(change.Deleted, group.ID)
+	}
+	return change
+}
+
+// ServersCreated returns the change of the servers created, the static public IP allocated for a server is recorded as part of it
+// as it is released along with the server (see AddressesOf).
+func ServersCreated(cloud cmn.Cloud, servers []cmn.Server) state.Change {
+	change := state.Change{}
+	for _, server := range servers {
+		recorded := stateOf(cloud, state.KindServer, server.Resource, "")
+		if server.Flavor != "" {
+			recorded.Attributes = map[string]string{state.AttributeFlavor: server.Flavor}
+		}
+		change.Created = append(change.Created, recorded)
+		if allocation, ok := server.Extras["allocationid"].(string); ok && allocation != "" {
+			change.Created = append(change.Created, stateOf(cloud, state.KindAddress, cmn.Resource{ID: allocation, Name: server.Name, Region: server.Region}, server.ID))
+		}
+	}
+	return change
+}
+
+// ServersUpdated returns the change of the servers updated, the servers recorded in the state of the stack of the cloud are recorded afresh
+// with the flavor they have once updated. The servers which are not part of the stack are left out, as are all of them if the cloud has no state.
+func ServersUpdated(ctx context.Context, cloud cmn.Cloud, changes []cmn.ServerChange) (state.Change, error) {
+	change := state.Change{}
+	if cloud.State == nil || cloud.Stack == "" || cloud.DryRun || len(changes) == 0 {
+		return change, nil
+	}
+	current, err := cloud.State.Read(ctx, cloud.Stack)
+	if err != nil {
+		return change, err
+	}
+	for _, recorded := range current.Of(state.KindServer) {
+		for _, updated := range changes {
+			if updated.ServerID != recorded.ID || updated.After.Flavor == "" {
+				continue
+			}
+			attributes := map[string]string{state.AttributeFlavor: updated.After.Flavor}
+			for attribute, value := range recorded.Attributes {
+				if attribute != state.AttributeFlavor {
+					attributes[attribute] = value
+				}
+			}
+			recorded.Attributes = attributes
+			change.Created = append(change.Created, recorded)
+		}
+	}
+	return change, nil
+}
+
+// ServersDeleted returns the change of the servers deleted.
+func ServersDeleted(servers []cmn.Server) state.Change {
+	change := state.Change{}
+	for _, server := range servers {
+		change.Deleted = append(change.Deleted, server.ID)
+	}
+	return change
+}
+
+// ImagesCreated returns the change of the images captured.
+func ImagesCreated(cloud cmn.Cloud, images []cmn.Image) state.Change {
+	change := state.Change{}
+	for _, image := range images {
+		change.Created = append(change.Created, stateOf(cloud, state.KindImage, image.Resource, ""))
+	}
+	return change
+}
+
+// ImagesDeleted returns the change of the images deleted.
+func ImagesDeleted(images []cmn.Image) state.Change {
+	change := state.Change{}
+	for _, image := range images {
+		change.Deleted = append(change.Deleted, image.ID)
+	}
+	return change
+}
+
+// LoadbalancersCreated returns the change of the loadbalancers created, the type of loadbalancer is recorded as it is required to delete it.
+// The ports on which the loadbalancer listens are recorded along with it.
+func LoadbalancersCreated(cloud cmn.Cloud, loadbalancers []cmn.LoadBalancer) state.Change {
+	change := state.Change{}
+	for _, lb := range loadbalancers {
+		resource := stateOf(cloud, state.KindLoadbalancer, lb.Resource, "")
+		if lb.Type != "" {
+			resource.Attributes = map[string]string{state.AttributeType: lb.Type}
+		}
+		if len(lb.Listeners) != 0 {
+			withAttribute(&resource, state.AttributePorts, ListenerPorts(lb.Listeners))
+		}
+		change.Created = append(change.Created, resource)
+	}
+	return change
+}
+
+// ListenerPorts returns the ports of the listeners passed, sorted.
+func ListenerPorts(listeners []cmn.Listener) []string {
+	ports := make([]string, 0, len(listeners))
+	for _, listener := range listeners {
+		ports = append(ports, strconv.FormatInt(listener.Port, 10))
+	}
+	sort.Strings(ports)
+	return ports
+}
+
+// LoadbalancersDeleted returns the change of the loadbalancers deleted.
+func LoadbalancersDeleted(loadbalancers []cmn.LoadBalancer) state.Change {
+	change := state.Change{}
+	for _, lb := range loadbalancers {
+		change.Deleted = append(change.Deleted, lb.ID)
+	}
+	return change
+}
+
+// VolumesCreated returns the change of the volumes created, either of the size passed or restored out of the snapshots.
+func VolumesCreated(cloud cmn.Cloud, volumes []cmn.Volume) state.Change {
+	change := state.Change{}
+	for _, volume := range volumes {
+		change.Created = append(change.Created, stateOf(cloud, state.KindVolume, volume.Resource, ""))
+	}
+	return change
+}
+
+// VolumesDeleted returns the change of the volumes deleted.
+func VolumesDeleted(volumes []cmn.Volume) state.Change {
+	change := state.Change{}
+	for _, volume := range volumes {
+		change.Deleted = append(change.Deleted, volume.ID)
+	}
+	return change
+}
+
+// SnapshotsCreated returns the change of the snapshots captured, they are not recorded as part of the volume as they outlive it.
+func SnapshotsCreated(cloud cmn.Cloud, snapshots []cmn.Snapshot) state.Change {
+	change := state.Change{}
+	for _, snapshot := range snapshots {
+		change.Created = append(change.Created, stateOf(cloud, state.KindSnapshot, snapshot.Resource, ""))
+	}
+	return change
+}
+
+// SnapshotsDeleted returns the change of the snapshots deleted.
+func SnapshotsDeleted(snapshots []cmn.Snapshot) state.Change {
+	change := state.Change{}
+	for _, snapshot := range snapshots {
+		change.Deleted = append(change.Deleted, snapshot.ID)
+	}
+	return change
+}
+
+// AddressesCreated returns the change of the static public IPs allocated on their own, they are not recorded as part of any server
+// as they are not released along with the servers they are associated with.
+func AddressesCreated(cloud cmn.Cloud, addresses []cmn.Address) state.Change {
+	change := state.Change{}
+	for _, address := range addresses {
+		change.Created = append(change.Created, stateOf(cloud, state.KindAddress, address.Resource, ""))
+	}
+	return change
+}
+
+// AddressesDeleted returns the change of the static public IPs released.
+func AddressesDeleted(addresses []cmn.Address) state.Change {
+	change := state.Change{}
+	for _, address := range addresses {
+		change.Deleted = append(change.Deleted, address.ID)
+	}
+	return change
+}
+
+// KeyPairsCreated returns the change of the key pairs created or imported, the ones generated for the servers are recorded as well
+// as they outlive the servers. The key pairs are identified by their names.
+func KeyPairsCreated(cloud cmn.Cloud, pairs []cmn.KeyPair) state.Change {
+	change := state.Change{}
+	for _, pair := range pairs {
+		change.Created = append(change.Created, stateOf(cloud, state.KindKeyPair, pair.Resource, ""))
+	}
+	return change
+}
+
+// KeyPairsDeleted returns the change of the key pairs deleted.
+func KeyPairsDeleted(pairs []cmn.KeyPair) state.Change {
+	change := state.Change{}
+	for _, pair := range pairs {
+		change.Deleted = append(change.Deleted, pair.ID)
+	}
+	return change
+}
+
+// AddressesOf returns the static public IPs recorded in the state of the stack of the cloud as part of the servers passed, these are
+// the ones to be released along with the servers. None are returned if the cloud has no state.
+func AddressesOf(ctx context.Context, cloud cmn.Cloud, servers []cmn.Server) ([]state.Resource, error) {
+	if cloud.State == nil || cloud.Stack == "" || cloud.DryRun || len(servers) == 0 {
+		return nil, nil
+	}
+	current, err := cloud.State.Read(ctx, cloud.Stack)
+	if err != nil {
+		return nil, err
+	}
+	deleted := make(map[string]bool)
+	for _, server := range servers {
+		deleted[server.ID] = true
+	}
+	addresses := make([]state.Resource, 0)
+	for _, address := range current.Of(state.KindAddress) {
+		if deleted[address.Parent] {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses, nil
+}
+
+// AddressesNotReleased returns the change of the static public IPs of the servers deleted which could not be released along with them.
+// These are no longer part of the servers, they are recorded on their own as the ones allocated by AddressesCreated so that they could be released later.
+func AddressesNotReleased(addresses []state.Resource, released []cmn.Address) state.Change {
+	change := state.Change{}
+	for _, address := range addresses {
+		kept := true
+		for _, done := range released {
+			if done.ID == address.ID {
+				kept = false
+			}
+		}
+		if kept {
+			address.Parent = ""
+			change.Created = append(change.Created, address)
+		}
+	}
+	return change
+}
+
+// withAttribute records the values passed as the attribute of the resource sorted, nothing is recorded if there are no values.
+func withAttribute(resource *state.Resource, attribute string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	if resource.Attributes == nil {
+		resource.Attributes = make(map[string]string)
+	}
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	resource.Attributes[attribute] = strings.Join(sorted, ",")
+}
+
+func stateOf(cloud cmn.Cloud, kind string, resource cmn.Resource, parent string) state.Resource {
+	recorded := state.Resource{Kind: kind, ID: resource.ID, Name: resource.Name, Cloud: resource.Cloud, Region: resource.Region, Parent: parent}
+	if recorded.Cloud == "" {
+		recorded.Cloud = cloud.Name
+	}
+	if recorded.Region == "" {
+		recorded.Region = cloud.Region
+	}
+	return recorded
+}
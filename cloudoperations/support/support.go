@@ -1,17 +1,17 @@
 // Package support will make a call whether this tool supports the invoked cloud.
+// It also hosts the registry of providers, through which cloudoperations routes the request to the respective cloud.
 package support
 
-// Listing the names of the cloud that this tool supports,
-// once the compactable for new cloud is made just make an entry here.
-var clouds = []string{"aws", "azure", "gcp", "openstack"}
+import (
+	"strings"
+)
 
 // DoesCloudSupports is the place where the actual decision for the clous is made and will return status to the called method.
+// A cloud is supported once a provider for it is registered with Register.
 // This has to be used by cloudoperations.
 func DoesCloudSupports(input string) bool {
-	for _, value := range clouds {
-		if input == value {
-			return true
-		}
+	if _, err := GetProvider(strings.ToLower(input)); err != nil {
+		return false
 	}
-	return false
+	return true
 }
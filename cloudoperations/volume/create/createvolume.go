@@ -0,0 +1,48 @@
+package volumecreate
+
+import (
+	"context"
+
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
+)
+
+// VolumeResponse will return the filtered/unfiltered responses of variuos clouds on the volumes.
+type VolumeResponse = support.VolumeResponse
+
+// CreateVolume creates the volume in the zone and cloud passed, either of the size passed or out of the snapshot passed.
+func (vol *CreateVolumeInput) CreateVolume() (VolumeResponse, error) {
+	return vol.CreateVolumeWithContext(context.Background())
+}
+
+// CreateVolumeWithContext is same as CreateVolume, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (vol *CreateVolumeInput) CreateVolumeWithContext(ctx context.Context) (VolumeResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetVolumeProvider(vol.Cloud.Name)
+	if err != nil {
+		return VolumeResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, vol.Cloud.DryRun); err != nil {
+		return VolumeResponse{}, err
+	}
+	volumein := support.CreateVolumeInput(*vol)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response VolumeResponse
+	err = support.Track(ctx, vol.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.CreateVolume(ctx, &volumein)
+		return support.VolumesCreated(vol.Cloud, response.Volumes), opErr
+	})
+	return response, err
+}
+
+// New returns the new CreateVolumeInput instance with empty values.
+func New() *CreateVolumeInput {
+	vol := &CreateVolumeInput{}
+	return vol
+}
@@ -0,0 +1,30 @@
+// Package volumecreate makes the tool cloud agnostic with respect to creation of volumes.
+// The decision will be made here to route the request to respective package based on input.
+package volumecreate
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// CreateVolumeInput implements method CreateVolume and holds parameter for creating volume.
+type CreateVolumeInput struct {
+	// Name of the volume that has to be created.
+	Name string `json:"name"`
+	// Zone is the availability zone in which the volume has to be created, the volume can be attached only to the servers in the same zone.
+	Zone string `json:"zone"`
+	// Size of the volume in GiB, it defaults to the size of the snapshot if the volume is created out of one.
+	Size int64 `json:"size"`
+	// Type of the volume ex: gp2, io1, st1 in aws.
+	Type string `json:"type"`
+	// Iops to be provisioned for the volume, this is valid only for the volumes of type io1 in aws.
+	Iops int64 `json:"iops"`
+	// Encrypted encrypts the volume with the key KmsKeyID, or with the default key of the cloud if KmsKeyID is not set.
+	Encrypted bool `json:"encrypted"`
+	// KmsKeyID is the ID of the key with which the volume has to be encrypted.
+	KmsKeyID string `json:"kmskeyid"`
+	// SnapshotID is the ID of the snapshot out of which the volume has to be created.
+	SnapshotID string `json:"snapshotid"`
+	Cloud      cmn.Cloud
+}
+
+//Nothing much from this file. This file contains only the structs for volume/create
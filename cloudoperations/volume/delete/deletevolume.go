@@ -0,0 +1,48 @@
+package volumedelete
+
+import (
+	"context"
+
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
+)
+
+// VolumeResponse will return the filtered/unfiltered responses of variuos clouds on the volumes.
+type VolumeResponse = support.VolumeResponse
+
+// DeleteVolume deletes the volumes selected, the volumes attached to the servers have to be detached first.
+func (vol *DeleteVolumeInput) DeleteVolume() (VolumeResponse, error) {
+	return vol.DeleteVolumeWithContext(context.Background())
+}
+
+// DeleteVolumeWithContext is same as DeleteVolume, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (vol *DeleteVolumeInput) DeleteVolumeWithContext(ctx context.Context) (VolumeResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetVolumeProvider(vol.Cloud.Name)
+	if err != nil {
+		return VolumeResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, vol.Cloud.DryRun); err != nil {
+		return VolumeResponse{}, err
+	}
+	volumein := support.DeleteVolumeInput(*vol)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response VolumeResponse
+	err = support.Track(ctx, vol.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.DeleteVolume(ctx, &volumein)
+		return support.VolumesDeleted(response.Volumes), opErr
+	})
+	return response, err
+}
+
+// New returns the new DeleteVolumeInput instance with empty values.
+func New() *DeleteVolumeInput {
+	vol := &DeleteVolumeInput{}
+	return vol
+}
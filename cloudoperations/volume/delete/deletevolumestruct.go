@@ -0,0 +1,16 @@
+// Package volumedelete makes the tool cloud agnostic with respect to deletion of volumes.
+// The decision will be made here to route the request to respective package based on input.
+package volumedelete
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// DeleteVolumeInput implements method DeleteVolume and holds parameter for deleting volumes.
+type DeleteVolumeInput struct {
+	// VolumeIDs are the IDs of the volumes to be deleted.
+	VolumeIDs []string `json:"volumeids"`
+	Cloud     cmn.Cloud
+}
+
+//Nothing much from this file. This file contains only the structs for volume/delete
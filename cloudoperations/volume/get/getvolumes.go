@@ -0,0 +1,36 @@
+package volumeget
+
+import (
+	"context"
+
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+)
+
+// VolumeResponse will return the filtered/unfiltered responses of variuos clouds on the volumes.
+type VolumeResponse = support.VolumeResponse
+
+// GetVolumes fetches the volumes selected, the ones attached to the server or all the ones in the region are fetched if the volumes are not passed.
+func (vol *GetVolumesInput) GetVolumes() (VolumeResponse, error) {
+	return vol.GetVolumesWithContext(context.Background())
+}
+
+// GetVolumesWithContext is same as GetVolumes, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (vol *GetVolumesInput) GetVolumesWithContext(ctx context.Context) (VolumeResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetVolumeProvider(vol.Cloud.Name)
+	if err != nil {
+		return VolumeResponse{}, err
+	}
+	volumein := support.GetVolumesInput(*vol)
+	return provider.GetVolumes(ctx, &volumein)
+}
+
+// New returns the new GetVolumesInput instance with empty values.
+func New() *GetVolumesInput {
+	vol := &GetVolumesInput{}
+	return vol
+}
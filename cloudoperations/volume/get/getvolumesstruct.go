@@ -0,0 +1,18 @@
+// Package volumeget makes the tool cloud agnostic in fetching the details of volumes.
+// The decision will be made here to route the request to respective package based on input.
+package volumeget
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// GetVolumesInput implements method GetVolumes and holds parameter for fetching volumes.
+type GetVolumesInput struct {
+	// VolumeIDs are the IDs of the volumes of which information has to be retrieved.
+	VolumeIDs []string `json:"volumeids"`
+	// ServerID is the ID of the server of which the volumes are retrieved if VolumeIDs are not passed.
+	ServerID string `json:"serverid"`
+	Cloud    cmn.Cloud
+}
+
+//Nothing much from this file. This file contains only the structs for volume/get
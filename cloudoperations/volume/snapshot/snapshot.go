@@ -0,0 +1,121 @@
+package volumesnapshot
+
+import (
+	"context"
+
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+	"github.com/nikhilsbhat/neuron-cloudy/state"
+)
+
+// VolumeResponse will return the filtered/unfiltered responses of variuos clouds on the volumes.
+type VolumeResponse = support.VolumeResponse
+
+// SnapshotResponse will return the filtered/unfiltered responses of variuos clouds on the snapshots of the volumes.
+type SnapshotResponse = support.SnapshotResponse
+
+// CreateSnapshot captures the snapshot of the volume passed.
+func (vol *CreateSnapshotInput) CreateSnapshot() (SnapshotResponse, error) {
+	return vol.CreateSnapshotWithContext(context.Background())
+}
+
+// CreateSnapshotWithContext is same as CreateSnapshot, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (vol *CreateSnapshotInput) CreateSnapshotWithContext(ctx context.Context) (SnapshotResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetVolumeProvider(vol.Cloud.Name)
+	if err != nil {
+		return SnapshotResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, vol.Cloud.DryRun); err != nil {
+		return SnapshotResponse{}, err
+	}
+	volumein := support.CreateSnapshotInput(*vol)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response SnapshotResponse
+	err = support.Track(ctx, vol.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.CreateSnapshot(ctx, &volumein)
+		return support.SnapshotsCreated(vol.Cloud, response.Snapshots), opErr
+	})
+	return response, err
+}
+
+// GetSnapshots fetches the snapshots selected, the ones of the volume or all the ones in the region are fetched if the snapshots are not passed.
+func (vol *GetSnapshotsInput) GetSnapshots() (SnapshotResponse, error) {
+	return vol.GetSnapshotsWithContext(context.Background())
+}
+
+// GetSnapshotsWithContext is same as GetSnapshots, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (vol *GetSnapshotsInput) GetSnapshotsWithContext(ctx context.Context) (SnapshotResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetVolumeProvider(vol.Cloud.Name)
+	if err != nil {
+		return SnapshotResponse{}, err
+	}
+	volumein := support.GetSnapshotsInput(*vol)
+	return provider.GetSnapshots(ctx, &volumein)
+}
+
+// DeleteSnapshot deletes the snapshots selected.
+func (vol *DeleteSnapshotInput) DeleteSnapshot() (SnapshotResponse, error) {
+	return vol.DeleteSnapshotWithContext(context.Background())
+}
+
+// DeleteSnapshotWithContext is same as DeleteSnapshot, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (vol *DeleteSnapshotInput) DeleteSnapshotWithContext(ctx context.Context) (SnapshotResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetVolumeProvider(vol.Cloud.Name)
+	if err != nil {
+		return SnapshotResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, vol.Cloud.DryRun); err != nil {
+		return SnapshotResponse{}, err
+	}
+	volumein := support.DeleteSnapshotInput(*vol)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response SnapshotResponse
+	err = support.Track(ctx, vol.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.DeleteSnapshot(ctx, &volumein)
+		return support.SnapshotsDeleted(response.Snapshots), opErr
+	})
+	return response, err
+}
+
+// RestoreSnapshot creates the volume out of the snapshot passed.
+func (vol *RestoreSnapshotInput) RestoreSnapshot() (VolumeResponse, error) {
+	return vol.RestoreSnapshotWithContext(context.Background())
+}
+
+// RestoreSnapshotWithContext is same as RestoreSnapshot, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (vol *RestoreSnapshotInput) RestoreSnapshotWithContext(ctx context.Context) (VolumeResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetVolumeProvider(vol.Cloud.Name)
+	if err != nil {
+		return VolumeResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, vol.Cloud.DryRun); err != nil {
+		return VolumeResponse{}, err
+	}
+	volumein := support.RestoreSnapshotInput(*vol)
+
+	// the change is recorded in the state of the stack, if the cloud has one.
+	var response VolumeResponse
+	err = support.Track(ctx, vol.Cloud, func() (state.Change, error) {
+		var opErr error
+		response, opErr = provider.RestoreSnapshot(ctx, &volumein)
+		return support.VolumesCreated(vol.Cloud, response.Volumes), opErr
+	})
+	return response, err
+}
@@ -0,0 +1,54 @@
+// Package volumesnapshot makes the tool cloud agnostic with respect to the snapshots of the volumes,
+// it captures/fetches/deletes the snapshots and restores them as volumes.
+// The decision will be made here to route the request to respective package based on input.
+package volumesnapshot
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// CreateSnapshotInput implements method CreateSnapshot and holds parameter for capturing snapshot.
+type CreateSnapshotInput struct {
+	// VolumeID is the ID of the volume of which the snapshot has to be captured.
+	VolumeID string `json:"volumeid"`
+	// Name of the snapshot.
+	Name string `json:"name"`
+	// Description of the snapshot.
+	Description string `json:"description"`
+	Cloud       cmn.Cloud
+}
+
+// GetSnapshotsInput implements method GetSnapshots and holds parameter for fetching snapshots.
+type GetSnapshotsInput struct {
+	// SnapshotIDs are the IDs of the snapshots of which information has to be retrieved.
+	SnapshotIDs []string `json:"snapshotids"`
+	// VolumeID is the ID of the volume of which the snapshots are retrieved if SnapshotIDs are not passed.
+	VolumeID string `json:"volumeid"`
+	Cloud    cmn.Cloud
+}
+
+// DeleteSnapshotInput implements method DeleteSnapshot and holds parameter for deleting snapshots.
+type DeleteSnapshotInput struct {
+	// SnapshotIDs are the IDs of the snapshots to be deleted.
+	SnapshotIDs []string `json:"snapshotids"`
+	Cloud       cmn.Cloud
+}
+
+// RestoreSnapshotInput implements method RestoreSnapshot and holds parameter for creating volume out of the snapshot.
+type RestoreSnapshotInput struct {
+	// SnapshotID is the ID of the snapshot to be restored.
+	SnapshotID string `json:"snapshotid"`
+	// Name of the volume that has to be created.
+	Name string `json:"name"`
+	// Zone is the availability zone in which the volume has to be created.
+	Zone string `json:"zone"`
+	// Size of the volume in GiB, it defaults to the size of the snapshot.
+	Size int64 `json:"size"`
+	// Type of the volume ex: gp2, io1, st1 in aws.
+	Type string `json:"type"`
+	// Iops to be provisioned for the volume, this is valid only for the volumes of type io1 in aws.
+	Iops  int64 `json:"iops"`
+	Cloud cmn.Cloud
+}
+
+//Nothing much from this file. This file contains only the structs for volume/snapshot
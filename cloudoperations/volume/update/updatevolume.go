@@ -0,0 +1,39 @@
+package volumeupdate
+
+import (
+	"context"
+
+	// registers the built-in providers.
+	_ "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/providers"
+	support "github.com/nikhilsbhat/neuron-cloudy/cloudoperations/support"
+)
+
+// VolumeResponse will return the filtered/unfiltered responses of variuos clouds on the volumes.
+type VolumeResponse = support.VolumeResponse
+
+// UpdateVolume attaches, detaches or resizes the volume selected as per the action passed.
+func (vol *UpdateVolumeInput) UpdateVolume() (VolumeResponse, error) {
+	return vol.UpdateVolumeWithContext(context.Background())
+}
+
+// UpdateVolumeWithContext is same as UpdateVolume, but the calls made to the cloud are bound to the context passed.
+// The operation is cancelled once the context is cancelled or its deadline expires.
+func (vol *UpdateVolumeInput) UpdateVolumeWithContext(ctx context.Context) (VolumeResponse, error) {
+
+	// Routes the request to the provider registered for the cloud.
+	provider, err := support.GetVolumeProvider(vol.Cloud.Name)
+	if err != nil {
+		return VolumeResponse{}, err
+	}
+	if err := support.CheckDryRun(provider, vol.Cloud.DryRun); err != nil {
+		return VolumeResponse{}, err
+	}
+	volumein := support.UpdateVolumeInput(*vol)
+	return provider.UpdateVolume(ctx, &volumein)
+}
+
+// New returns the new UpdateVolumeInput instance with empty values.
+func New() *UpdateVolumeInput {
+	vol := &UpdateVolumeInput{}
+	return vol
+}
@@ -0,0 +1,31 @@
+// Package volumeupdate makes the tool cloud agnostic for attaching, detaching and resizing the volumes.
+// The decision will be made here to route the request to respective package based on input.
+package volumeupdate
+
+import (
+	cmn "github.com/nikhilsbhat/neuron-cloudy/cloudoperations"
+)
+
+// UpdateVolumeInput implements method UpdateVolume and holds parameter for updating volume.
+type UpdateVolumeInput struct {
+	// VolumeID is the ID of the volume to be updated.
+	VolumeID string `json:"volumeid"`
+	// Action to be performed on the volume, attach attaches it to the server at the device passed,
+	// detach detaches it from the server it is attached to and resize changes its size, type or iops.
+	Action string `json:"action"`
+	// ServerID is the ID of the server to which the volume has to be attached.
+	ServerID string `json:"serverid"`
+	// DeviceName is the name of the device at which the volume has to be attached ex: /dev/sdf.
+	DeviceName string `json:"devicename"`
+	// Force detaches the volume even if the server did not unmount it, the data which is not yet written to the volume is lost.
+	Force bool `json:"force"`
+	// Size in GiB to which the volume has to be resized, the volume can only grow.
+	Size int64 `json:"size"`
+	// Type to which the volume has to be changed.
+	Type string `json:"type"`
+	// Iops to be provisioned for the volume.
+	Iops  int64 `json:"iops"`
+	Cloud cmn.Cloud
+}
+
+//Nothing much from this file. This file contains only the structs for volume/update
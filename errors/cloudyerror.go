@@ -1,10 +1,6 @@
 // Package cloudyerror has various errors which are specifec to cloudy.
 package cloudyerror
 
-import (
-	"errors"
-)
-
 const (
 	emptyStructError = "You provided empty struct to retrive the data, this is not acceptable"
 	notValidSession  = "Did not get session to perform action, cannot proceed further"
@@ -14,20 +10,20 @@ const (
 
 // InvalidSession returns an error if the methods finds the session in not vaild or illegal.
 func InvalidSession() error {
-	return errors.New(notValidSession)
+	return New(InvalidInput, notValidSession)
 }
 
 // EmptyStructError will be thrown if the method encounters an empty structs where it should not have.
 func EmptyStructError() error {
-	return errors.New(emptyStructError)
+	return New(InvalidInput, emptyStructError)
 }
 
 // ImageNotFound will be thrown if entered image does not exists
 func ImageNotFound() error {
-	return errors.New(imageNotFound)
+	return &Error{Code: NotFound, Kind: "image", Message: imageNotFound}
 }
 
 // ServerNotFound will be thrown if the entered server does not exists
 func ServerNotFound() error {
-	return errors.New(instanceNotFound)
+	return &Error{Code: NotFound, Kind: "server", Message: instanceNotFound}
 }
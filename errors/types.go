@@ -0,0 +1,313 @@
+package cloudyerror
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"google.golang.org/api/googleapi"
+)
+
+// Code classifies the errors returned by cloudy, so that the callers can decide on what to do with error
+// (retry, ignore, report etc.) without matching its message.
+type Code string
+
+const (
+	// Unknown is the code of the errors which could not be classified.
+	Unknown Code = "Unknown"
+	// NotFound is the code of the errors raised when the resource asked for does not exists.
+	NotFound Code = "NotFound"
+	// InvalidInput is the code of the errors raised when the input passed is empty or not valid.
+	InvalidInput Code = "InvalidInput"
+	// Unsupported is the code of the errors raised when the cloud/action asked for is not supported.
+	Unsupported Code = "Unsupported"
+	// QuotaExceeded is the code of the errors raised when the limits of the account are reached.
+	QuotaExceeded Code = "QuotaExceeded"
+	// Throttled is the code of the errors raised when the requests to cloud are throttled.
+	Throttled Code = "Throttled"
+	// Timeout is the code of the errors raised when the operation did not complete in the time expected.
+	Timeout Code = "Timeout"
+	// Conflict is the code of the errors raised when the resource is in use or in a state which does not allow the action.
+	Conflict Code = "Conflict"
+	// Unauthorized is the code of the errors raised when the credentials are not valid or not allowed the action/region
+	// (ex: the regions of aws which the account did not opt in to fail with AuthFailure).
+	Unauthorized Code = "Unauthorized"
+)
+
+// Error is the structured error of cloudy, it carries the code along with the details of the resource on which
+// the error occurred and the error returned by the cloud (awserr.Error, *googleapi.Error, autorest.DetailedError etc.).
+type Error struct {
+	Code    Code
+	Cloud   string
+	Kind    string
+	ID      string
+	Message string
+	Err     error
+}
+
+// Error returns the message of the error, the message of the wrapped error is used if the message is not set.
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" && e.Err != nil {
+		msg = e.Err.Error()
+	}
+	if msg == "" {
+		msg = string(e.Code)
+	}
+
+	resource := strings.TrimSpace(strings.Join([]string{e.Cloud, e.Kind, e.ID}, " "))
+	if resource != "" && e.Message == "" {
+		return fmt.Sprintf("%s: %s", resource, msg)
+	}
+	return msg
+}
+
+// MarshalJSON encodes the error along with its message, as the error returned by the cloud does not encode by itself.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Code    Code   `json:"code"`
+		Cloud   string `json:"cloud,omitempty"`
+		Kind    string `json:"kind,omitempty"`
+		ID      string `json:"id,omitempty"`
+		Message string `json:"message"`
+	}{Code: e.Code, Cloud: e.Cloud, Kind: e.Kind, ID: e.ID, Message: e.Error()})
+}
+
+// Unwrap returns the error returned by the cloud, if any.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is an Error of the same code, this makes errors.Is(err, &Error{Code: NotFound}) work.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return t.Code == e.Code
+}
+
+// New returns a new Error of the code and the message passed.
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Newf is same as New, but the message is formatted as per the format specifier.
+func Newf(code Code, format string, a ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, a...)}
+}
+
+// Wrap wraps the error passed into an Error of the code passed, nil is returned if the error passed is nil.
+func Wrap(code Code, err error) *Error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// WithResource sets the cloud and the resource on which the error occurred, it returns the same error for chaining.
+func (e *Error) WithResource(cloud, kind, id string) *Error {
+	e.Cloud = cloud
+	e.Kind = kind
+	e.ID = id
+	return e
+}
+
+// As finds the first Error in the chain of the error passed.
+func As(err error) (*Error, bool) {
+	for err != nil {
+		if e, ok := err.(*Error); ok {
+			return e, true
+		}
+		unwrapper, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return nil, false
+		}
+		err = unwrapper.Unwrap()
+	}
+	return nil, false
+}
+
+// CodeOf returns the code of the error passed, Unknown is returned if the error is not classified.
+func CodeOf(err error) Code {
+	if e, ok := As(err); ok {
+		return e.Code
+	}
+	return Unknown
+}
+
+// Is reports whether the error passed is of the code passed.
+func Is(err error, code Code) bool {
+	return err != nil && CodeOf(err) == code
+}
+
+// IsNotFound reports whether the error passed is of code NotFound.
+func IsNotFound(err error) bool {
+	return Is(err, NotFound)
+}
+
+// IsInvalidInput reports whether the error passed is of code InvalidInput.
+func IsInvalidInput(err error) bool {
+	return Is(err, InvalidInput)
+}
+
+// IsUnsupported reports whether the error passed is of code Unsupported.
+func IsUnsupported(err error) bool {
+	return Is(err, Unsupported)
+}
+
+// IsQuotaExceeded reports whether the error passed is of code QuotaExceeded.
+func IsQuotaExceeded(err error) bool {
+	return Is(err, QuotaExceeded)
+}
+
+// IsThrottled reports whether the error passed is of code Throttled.
+func IsThrottled(err error) bool {
+	return Is(err, Throttled)
+}
+
+// IsTimeout reports whether the error passed is of code Timeout.
+func IsTimeout(err error) bool {
+	return Is(err, Timeout)
+}
+
+// IsConflict reports whether the error passed is of code Conflict.
+func IsConflict(err error) bool {
+	return Is(err, Conflict)
+}
+
+// IsUnauthorized reports whether the error passed is of code Unauthorized.
+func IsUnauthorized(err error) bool {
+	return Is(err, Unauthorized)
+}
+
+// FromAWS classifies the error returned by aws into Error.
+// The errors which are already classified, and the errors which are not from aws are returned as is.
+func FromAWS(err error, kind, id string) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := As(err); ok {
+		return err
+	}
+	if code := fromContext(err); code != Unknown {
+		return Wrap(code, err).WithResource("aws", kind, id)
+	}
+	awsErr, ok := err.(awserr.Error)
+	if !ok {
+		return err
+	}
+
+	code := awsCode(awsErr.Code())
+	if code == Unknown {
+		if reqErr, ok := err.(awserr.RequestFailure); ok {
+			code = fromStatus(reqErr.StatusCode())
+		}
+	}
+	return Wrap(code, err).WithResource("aws", kind, id)
+}
+
+func awsCode(code string) Code {
+	switch {
+	case code == request.CanceledErrorCode || code == request.WaiterResourceNotReadyErrorCode ||
+		code == "RequestTimeout" || code == "RequestTimeoutException":
+		return Timeout
+	case code == "Throttling" || code == "ThrottlingException" || code == "RequestLimitExceeded" ||
+		code == "TooManyRequestsException" || code == "RequestThrottled":
+		return Throttled
+	case strings.HasSuffix(code, "LimitExceeded") || code == "InsufficientInstanceCapacity" ||
+		code == "TooManyLoadBalancers" || code == "TooManyTargetGroups":
+		return QuotaExceeded
+	case code == "AuthFailure" || code == "UnauthorizedOperation" || code == "OptInRequired" || code == "UnrecognizedClientException" ||
+		code == "InvalidClientTokenId" || code == "SignatureDoesNotMatch" || code == "AccessDenied" || code == "AccessDeniedException":
+		return Unauthorized
+	case strings.HasSuffix(code, "NotFound"):
+		return NotFound
+	case code == "DependencyViolation" || code == "IncorrectState" || code == "IncorrectInstanceState" || code == "VolumeInUse" ||
+		strings.HasSuffix(code, ".InUse") || strings.HasSuffix(code, ".Duplicate") || code == "ResourceInUse" ||
+		code == "DuplicateLoadBalancerName" || code == "DuplicateTargetGroupName":
+		return Conflict
+	case code == "UnsupportedOperation" || strings.HasPrefix(code, "Unsupported") || code == "OperationNotPermitted":
+		return Unsupported
+	case strings.HasPrefix(code, "InvalidParameter") || code == "MissingParameter" || code == "ValidationError" ||
+		strings.HasSuffix(code, ".Malformed") || strings.HasPrefix(code, "Invalid"):
+		return InvalidInput
+	}
+	return Unknown
+}
+
+// FromGCP classifies the error returned by gcp into Error.
+// The errors which are already classified, and the errors which are not from gcp are returned as is.
+func FromGCP(err error, kind, id string) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := As(err); ok {
+		return err
+	}
+	if code := fromContext(err); code != Unknown {
+		return Wrap(code, err).WithResource("gcp", kind, id)
+	}
+	gcpErr, ok := err.(*googleapi.Error)
+	if !ok {
+		return err
+	}
+
+	for _, item := range gcpErr.Errors {
+		switch item.Reason {
+		case "quotaExceeded", "limitExceeded":
+			return Wrap(QuotaExceeded, err).WithResource("gcp", kind, id)
+		case "rateLimitExceeded", "userRateLimitExceeded":
+			return Wrap(Throttled, err).WithResource("gcp", kind, id)
+		}
+	}
+	return Wrap(fromStatus(gcpErr.Code), err).WithResource("gcp", kind, id)
+}
+
+// FromStatus classifies the error returned by the cloud into Error with the help of the http status code of the response.
+// This is used for the clouds which report the errors with the status code (ex: autorest.DetailedError of azure).
+// The errors which are already classified are returned as is.
+func FromStatus(cloud, kind, id string, status int, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := As(err); ok {
+		return err
+	}
+	if code := fromContext(err); code != Unknown {
+		return Wrap(code, err).WithResource(cloud, kind, id)
+	}
+	return Wrap(fromStatus(status), err).WithResource(cloud, kind, id)
+}
+
+func fromContext(err error) Code {
+	switch err {
+	case context.DeadlineExceeded, context.Canceled:
+		return Timeout
+	}
+	return Unknown
+}
+
+func fromStatus(status int) Code {
+	switch status {
+	case http.StatusNotFound, http.StatusGone:
+		return NotFound
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return InvalidInput
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		return Conflict
+	case http.StatusTooManyRequests:
+		return Throttled
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return Unauthorized
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return Timeout
+	case http.StatusNotImplemented, http.StatusMethodNotAllowed:
+		return Unsupported
+	}
+	return Unknown
+}
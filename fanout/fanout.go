@@ -0,0 +1,90 @@
+// Package fanout runs the operations spanning the regions of a cloud (ex: fetching all the servers of the account),
+// querying the regions concurrently and collecting the error of every region on its own.
+// This is so that a region which fails (ex: the regions of aws not opted in fail with AuthFailure) neither hides nor kills the results of the rest.
+package fanout
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultConcurrency is the number of regions queried at once, when none is set.
+const DefaultConcurrency = 8
+
+// Error is the error with which the operation failed in a region.
+type Error struct {
+	Region string
+	Err    error
+}
+
+// Error returns the message of the error prefixed with the region in which it occurred.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %v", e.Region, e.Err)
+}
+
+// Unwrap returns the error with which the operation failed in the region.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Errors are the errors of the regions in which the operation failed, in the order of the regions passed to Regions.
+type Errors []*Error
+
+// Error returns the messages of the errors of all the regions which failed.
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("operation failed in %d region(s): %s", len(e), strings.Join(messages, "; "))
+}
+
+// Of returns the error of the region passed, nil if the operation did not fail in it.
+func (e Errors) Of(region string) error {
+	for _, err := range e {
+		if err.Region == region {
+			return err.Err
+		}
+	}
+	return nil
+}
+
+// Regions calls fn for every region passed, with at most concurrency calls running at once (DefaultConcurrency if it is 0 or less).
+// fn is passed the index of the region as well, so that it can place its result without having to synchronize with the other calls.
+// The errors of the regions which failed are returned, nil if none failed. The regions yet to be queried when the context is done fail with its error.
+func Regions(ctx context.Context, regions []string, concurrency int, fn func(ctx context.Context, index int, region string) error) Errors {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	errs := make([]error, len(regions))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for index, region := range regions {
+		select {
+		case <-ctx.Done():
+			errs[index] = ctx.Err()
+			continue
+		case slots <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(index int, region string) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			errs[index] = fn(ctx, index, region)
+		}(index, region)
+	}
+	wg.Wait()
+
+	var failed Errors
+	for index, err := range errs {
+		if err != nil {
+			failed = append(failed, &Error{Region: regions[index], Err: err})
+		}
+	}
+	return failed
+}
@@ -7,6 +7,7 @@ require (
 	github.com/Azure/azure-sdk-for-go v29.0.0+incompatible
 	github.com/Azure/go-autorest v11.2.8+incompatible
 	github.com/aws/aws-sdk-go v1.21.7
+	github.com/ghodss/yaml v1.0.1-0.20190212211648-25d852aebe32
 	github.com/golang/protobuf v1.3.1 // indirect
 	github.com/google/go-cmp v0.3.0 // indirect
 	github.com/nikhilsbhat/config v0.0.0-20191122122325-7cd0985a0270
@@ -18,4 +19,5 @@ require (
 	golang.org/x/text v0.3.2 // indirect
 	google.golang.org/api v0.3.1
 	google.golang.org/grpc v1.19.1 // indirect
+	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
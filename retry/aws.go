@@ -0,0 +1,43 @@
+package retry
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// awsRetryer retries the requests made with the sdk of aws as per the policy it holds.
+type awsRetryer struct {
+	policy Policy
+}
+
+// AWSRetryer returns the retryer of the sdk of aws (the Retryer of aws.Config) which retries the requests as per the policy passed,
+// the errors are classified with AWS unless the policy has a classifier of its own.
+// The lookups (Describe*, Get* and List*) are not retried on the errors of eventual consistency, as the resources asked for may genuinely not exist.
+func AWSRetryer(policy Policy) request.Retryer {
+	return awsRetryer{policy: policy.For(AWS)}
+}
+
+// MaxRetries returns the number of times a request is retried after the first attempt.
+func (r awsRetryer) MaxRetries() int {
+	return r.policy.Retries()
+}
+
+// RetryRules returns the time to wait before retrying the request.
+func (r awsRetryer) RetryRules(req *request.Request) time.Duration {
+	return r.policy.Delay(req.RetryCount + 1)
+}
+
+// ShouldRetry reports whether the failed request has to be retried.
+func (r awsRetryer) ShouldRetry(req *request.Request) bool {
+	if req.Operation != nil && isLookup(req.Operation.Name) && IsEventual(req.Error) {
+		return false
+	}
+	return r.policy.ShouldRetry(req.RetryCount+1, req.Error)
+}
+
+// isLookup reports whether the operation of aws only looks up the resources.
+func isLookup(operation string) bool {
+	return strings.HasPrefix(operation, "Describe") || strings.HasPrefix(operation, "Get") || strings.HasPrefix(operation, "List")
+}
@@ -0,0 +1,104 @@
+package retry
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+	"google.golang.org/api/googleapi"
+)
+
+// Transient reports whether the error is transient irrespective of the cloud it is from:
+// the errors classified Throttled and the temporary failures of the network. This is used by the policies with no classifier.
+func Transient(err error) bool {
+	if err == nil || err == context.Canceled || err == context.DeadlineExceeded {
+		return false
+	}
+	if cloudyerror.IsThrottled(err) {
+		return true
+	}
+	if netErr, ok := unwrap(err).(net.Error); ok {
+		return netErr.Temporary() || netErr.Timeout()
+	}
+	return false
+}
+
+// awsEventualCodes are the codes with which aws reports the resources created moments ago as missing,
+// till the creation propagates across the systems of aws (ex: tagging a vpc right after creating it).
+var awsEventualCodes = map[string]bool{
+	"InvalidVpcID.NotFound":             true,
+	"InvalidSubnetID.NotFound":          true,
+	"InvalidInternetGatewayID.NotFound": true,
+	"InvalidRouteTableID.NotFound":      true,
+	"InvalidGroup.NotFound":             true,
+	"InvalidInstanceID.NotFound":        true,
+	"InvalidAMIID.NotFound":             true,
+	"InvalidSnapshot.NotFound":          true,
+	"InvalidAllocationID.NotFound":      true,
+}
+
+// AWS classifies the errors returned by aws, the throttling, the failures on the side of aws (5xx) and the
+// errors of eventual consistency (ex: InvalidVpcID.NotFound right after CreateVpc) are transient.
+func AWS(err error) bool {
+	if Transient(err) {
+		return true
+	}
+	awsErr, ok := unwrap(err).(awserr.Error)
+	if !ok {
+		return false
+	}
+	if cloudyerror.IsThrottled(cloudyerror.FromAWS(awsErr, "", "")) || awsEventualCodes[awsErr.Code()] {
+		return true
+	}
+	switch awsErr.Code() {
+	case "InternalError", "InternalFailure", "ServiceUnavailable", "Unavailable", "RequestError", "RequestTimeout", "RequestTimeoutException":
+		return true
+	}
+	if reqErr, ok := awsErr.(awserr.RequestFailure); ok {
+		return serverError(reqErr.StatusCode())
+	}
+	return false
+}
+
+// IsEventual reports whether the error is the one aws returns for the resources whose creation did not propagate yet.
+func IsEventual(err error) bool {
+	awsErr, ok := unwrap(err).(awserr.Error)
+	return ok && awsEventualCodes[awsErr.Code()]
+}
+
+// GCP classifies the errors returned by gcp, the throttling (including the rate limits) and the failures on the side of gcp (5xx) are transient.
+func GCP(err error) bool {
+	if Transient(err) {
+		return true
+	}
+	gcpErr, ok := unwrap(err).(*googleapi.Error)
+	if !ok {
+		return false
+	}
+	return cloudyerror.IsThrottled(cloudyerror.FromGCP(gcpErr, "", "")) || serverError(gcpErr.Code)
+}
+
+func serverError(status int) bool {
+	switch status {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// unwrap returns the error returned by the cloud, from the chain of the error passed.
+func unwrap(err error) error {
+	for {
+		unwrapper, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return err
+		}
+		next := unwrapper.Unwrap()
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+}
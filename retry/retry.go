@@ -0,0 +1,131 @@
+// Package retry holds the policy with which the calls made to the clouds are retried on the failures which are transient
+// (throttling, unavailability of the service, eventual consistency etc.), along with the classifiers which tell them apart for every cloud.
+// The policy is set on cmn.Cloud and is applied to all the calls made to the cloud selected, Default() is used if none is set.
+// The clients of azure take the policy through the Retry of their inputs, which the provider of azure is to set from cmn.Cloud.
+package retry
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// Classifier reports whether the error returned by the cloud is transient, hence worth retrying.
+type Classifier func(err error) bool
+
+// Policy decides how many times and how often the failed calls are retried.
+// The delay between the attempts grows exponentially from BaseDelay, and is capped at MaxDelay.
+type Policy struct {
+	// MaxAttempts is the number of times a call is attempted including the first one, the calls are not retried if it is 1 or less.
+	MaxAttempts int `json:"maxattempts"`
+	// BaseDelay is the delay before the first retry, it doubles with every attempt made.
+	BaseDelay time.Duration `json:"basedelay"`
+	// MaxDelay caps the delay between the attempts, the delay is not capped if it is 0.
+	MaxDelay time.Duration `json:"maxdelay"`
+	// Jitter is the fraction (0 to 1) of the delay which is randomized, so that the clients retrying together do not hit the cloud at once.
+	// With 1 the delay is anywhere between 0 and the computed one, with 0 it is exactly the computed one.
+	Jitter float64 `json:"jitter"`
+	// Retryable classifies the failures, the classifier of the cloud (ex: AWS, GCP) is used if it is not set.
+	Retryable Classifier `json:"-"`
+}
+
+// Default returns the policy used when none is set, it attempts the calls 5 times with delays starting at 500ms and capped at 20s, fully jittered.
+func Default() Policy {
+	return Policy{MaxAttempts: 5, BaseDelay: 500 * time.Millisecond, MaxDelay: 20 * time.Second, Jitter: 1}
+}
+
+// Never returns the policy which does not retry the calls.
+func Never() Policy {
+	return Policy{MaxAttempts: 1}
+}
+
+// OrDefault returns the policy passed, or Default() if it is nil.
+func OrDefault(policy *Policy) Policy {
+	if policy == nil {
+		return Default()
+	}
+	return *policy
+}
+
+// For returns the policy with the classifier passed, if the policy does not have one of its own.
+func (p Policy) For(classifier Classifier) Policy {
+	if p.Retryable == nil {
+		p.Retryable = classifier
+	}
+	return p
+}
+
+// Retries returns the number of times a call is retried after the first attempt.
+func (p Policy) Retries() int {
+	if p.MaxAttempts <= 1 {
+		return 0
+	}
+	return p.MaxAttempts - 1
+}
+
+// ShouldRetry reports whether the call has to be attempted again, after the number of attempts passed failed with the error passed.
+func (p Policy) ShouldRetry(attempts int, err error) bool {
+	if err == nil || attempts >= p.MaxAttempts {
+		return false
+	}
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = Transient
+	}
+	return retryable(err)
+}
+
+// Delay returns the time to wait before the next attempt, after the number of attempts passed failed.
+func (p Policy) Delay(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts; i++ {
+		if (p.MaxDelay > 0 && delay >= p.MaxDelay) || delay > math.MaxInt64/2 {
+			break
+		}
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter > 0 && delay > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay -= time.Duration(jitter * random() * float64(delay))
+	}
+	return delay
+}
+
+// Do calls fn till it succeeds, fails with an error which is not transient or the attempts are exhausted; the last error is returned.
+// The waiting between the attempts is cut short once the context is done, and the error of the context is returned.
+func (p Policy) Do(ctx context.Context, fn func() error) error {
+	for attempts := 1; ; attempts++ {
+		err := fn()
+		if !p.ShouldRetry(attempts, err) {
+			return err
+		}
+		timer := time.NewTimer(p.Delay(attempts))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return cloudyerror.Wrap(cloudyerror.Timeout, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+var (
+	randMu  sync.Mutex
+	randSrc = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+func random() float64 {
+	randMu.Lock()
+	defer randMu.Unlock()
+	return randSrc.Float64()
+}
@@ -0,0 +1,105 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+func TestDelay(t *testing.T) {
+	policy := Policy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for i, want := range expected {
+		if got := policy.Delay(i + 1); got != want {
+			t.Errorf("expected delay after attempt %d to be %v, got %v", i+1, want, got)
+		}
+	}
+
+	policy.Jitter = 1
+	for attempts := 1; attempts < 8; attempts++ {
+		if got := policy.Delay(attempts); got < 0 || got > time.Second {
+			t.Errorf("expected jittered delay to be within 0 and 1s, got %v", got)
+		}
+	}
+}
+
+func TestDoRetriesTransient(t *testing.T) {
+	policy := Policy{MaxAttempts: 4, BaseDelay: time.Millisecond}.For(AWS)
+	attempts := 0
+	err := policy.Do(context.Background(), func() error {
+		attempts++
+		if attempts < 3 {
+			return awserr.New("RequestLimitExceeded", "Request limit exceeded.", nil)
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("expected the call to succeed on attempt 3, got %d attempts and error %v", attempts, err)
+	}
+
+	attempts = 0
+	err = policy.Do(context.Background(), func() error {
+		attempts++
+		return awserr.New("InvalidParameterValue", "Value for parameter is invalid.", nil)
+	})
+	if err == nil || attempts != 1 {
+		t.Errorf("expected the errors which are not transient not to be retried, got %d attempts", attempts)
+	}
+
+	attempts = 0
+	err = policy.Do(context.Background(), func() error {
+		attempts++
+		return awserr.New("Throttling", "Rate exceeded", nil)
+	})
+	if err == nil || attempts != 4 {
+		t.Errorf("expected the call to be attempted 4 times, got %d", attempts)
+	}
+}
+
+func TestDoStopsOnContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := Policy{MaxAttempts: 5, BaseDelay: time.Hour, Retryable: func(error) bool { return true }}
+	err := policy.Do(ctx, func() error {
+		cancel()
+		return errors.New("failed")
+	})
+	if !cloudyerror.IsTimeout(err) {
+		t.Errorf("expected the waiting to be cut short by the context, got %v", err)
+	}
+}
+
+func TestGCPTransport(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != `{"name":"neuron"}` {
+			t.Errorf("expected the body to be sent on every attempt, got %q", body)
+		}
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":{"code":503,"message":"backend unavailable"}}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := HTTPClient(&http.Client{}, Policy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"name":"neuron"}`))
+	if err != nil {
+		t.Fatalf("making request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || attempts != 3 {
+		t.Errorf("expected the request to succeed on attempt 3, got status %d after %d attempts", resp.StatusCode, attempts)
+	}
+}
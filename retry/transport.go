@@ -0,0 +1,86 @@
+package retry
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+	"google.golang.org/api/googleapi"
+)
+
+// transport is the http.RoundTripper which retries the requests sent through base as per the policy it holds.
+type transport struct {
+	policy Policy
+	base   http.RoundTripper
+}
+
+// GCPTransport returns the http.RoundTripper which retries the requests made to gcp through the base passed (http.DefaultTransport if nil) as per the policy passed.
+// The failed responses are classified as *googleapi.Error with GCP unless the policy has a classifier of its own.
+// The requests whose body cannot be recreated (GetBody is not set) are not retried.
+func GCPTransport(policy Policy, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{policy: policy.For(GCP), base: base}
+}
+
+// HTTPClient returns a copy of the client passed whose requests to gcp are retried as per the policy passed.
+func HTTPClient(client *http.Client, policy Policy) *http.Client {
+	if client == nil || policy.Retries() == 0 {
+		return client
+	}
+	retrying := *client
+	retrying.Transport = GCPTransport(policy, client.Transport)
+	return &retrying
+}
+
+// RoundTrip sends the request, and retries it as long as it fails with the errors classified transient by the policy.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempts := 1; ; attempts++ {
+		resp, err := t.base.RoundTrip(req)
+		failure := err
+		if err == nil && resp.StatusCode >= http.StatusBadRequest {
+			failure = responseError(resp)
+		}
+		if !t.policy.ShouldRetry(attempts, failure) || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		timer := time.NewTimer(t.policy.Delay(attempts))
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, cloudyerror.Wrap(cloudyerror.Timeout, req.Context().Err())
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return nil, bodyErr
+			}
+			req = req.WithContext(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// responseError returns the error gcp reported in the response passed, the body of the response is left intact to be read by the caller.
+func responseError(resp *http.Response) error {
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	checkErr := googleapi.CheckResponse(resp)
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return checkErr
+}
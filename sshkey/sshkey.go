@@ -0,0 +1,160 @@
+// Package sshkey generates the ssh key pairs handed to the servers, and parses/fingerprints the public keys imported by the user.
+// Only the RSA keys are supported as they are the ones accepted by all the clouds, the public keys are in the OpenSSH
+// authorized_keys format (ex: ssh-rsa AAAAB3NzaC1yc2E... user@host) and the private keys are PEM encoded PKCS#1.
+package sshkey
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+const (
+	// DefaultBits is the size of the keys generated when none is passed.
+	DefaultBits = 2048
+	// rsaType is the type of the RSA keys in the authorized_keys format.
+	rsaType = "ssh-rsa"
+)
+
+// Pair is the ssh key pair generated.
+type Pair struct {
+	// PrivateKey is the PEM encoded PKCS#1 private key, it is the one to be passed to the ssh client (ex: ssh -i).
+	PrivateKey string
+	// PublicKey is the public key in the authorized_keys format, it is the one to be placed on the servers.
+	PublicKey string
+	// Fingerprint is the colon separated SHA-1 digest of the DER encoded PKCS#8 private key, the one aws reports for the key pairs it creates.
+	Fingerprint string
+}
+
+// Generate generates an RSA key pair of the size passed, DefaultBits is used if bits is 0.
+// The comment (ex: the name of the key pair) is appended to the public key, it is left out if empty.
+func Generate(bits int, comment string) (*Pair, error) {
+
+	if bits == 0 {
+		bits = DefaultBits
+	}
+	if bits < 1024 {
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The ssh key has to be atleast of 1024 bits, got %d", bits)
+	}
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	private := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	sum := sha1.Sum(der)
+	return &Pair{PrivateKey: string(private), PublicKey: Marshal(&key.PublicKey, comment), Fingerprint: colonHex(sum[:])}, nil
+}
+
+// Marshal returns the public key in the authorized_keys format, the comment is appended if not empty.
+func Marshal(key *rsa.PublicKey, comment string) string {
+
+	blob := new(bytes.Buffer)
+	writeString(blob, []byte(rsaType))
+	writeString(blob, mpint(big.NewInt(int64(key.E))))
+	writeString(blob, mpint(key.N))
+	authorized := rsaType + " " + base64.StdEncoding.EncodeToString(blob.Bytes())
+	if comment != "" {
+		authorized = authorized + " " + comment
+	}
+	return authorized
+}
+
+// Parse parses the public key in the authorized_keys format, the options preceding the type of the key are not supported.
+func Parse(authorized string) (*rsa.PublicKey, error) {
+
+	fields := strings.Fields(strings.TrimSpace(authorized))
+	if len(fields) < 2 {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "The public key is not in the authorized_keys format, it has to be of the form 'ssh-rsa AAAA... [comment]'")
+	}
+	if fields[0] != rsaType {
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The public keys of type %s are not supported, only the ones of type %s are", fields[0], rsaType)
+	}
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The public key is not base64 encoded: %v", err)
+	}
+
+	reader := bytes.NewReader(blob)
+	keyType, typeErr := readString(reader)
+	exponent, expErr := readString(reader)
+	modulus, modErr := readString(reader)
+	if (typeErr != nil) || (expErr != nil) || (modErr != nil) || (reader.Len() != 0) || (string(keyType) != rsaType) {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "The public key is malformed, the encoded key does not match its type "+rsaType)
+	}
+	e := new(big.Int).SetBytes(exponent)
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, cloudyerror.New(cloudyerror.InvalidInput, "The public key is malformed, its exponent is out of range")
+	}
+	key := &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: int(e.Int64())}
+	if key.N.BitLen() < 1024 {
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "The public key has to be atleast of 1024 bits, got %d", key.N.BitLen())
+	}
+	return key, nil
+}
+
+// Fingerprint returns the colon separated MD5 digest of the DER encoded public key, the one aws reports for the key pairs imported.
+func Fingerprint(key *rsa.PublicKey) (string, error) {
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	sum := md5.Sum(der)
+	return colonHex(sum[:]), nil
+}
+
+// writeString writes the data prefixed with its length as in the wire format of ssh (RFC 4251).
+func writeString(buf *bytes.Buffer, data []byte) {
+	size := make([]byte, 4)
+	binary.BigEndian.PutUint32(size, uint32(len(data)))
+	buf.Write(size)
+	buf.Write(data)
+}
+
+// readString reads the data prefixed with its length as in the wire format of ssh (RFC 4251).
+func readString(reader *bytes.Reader) ([]byte, error) {
+	var size uint32
+	if err := binary.Read(reader, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	if int64(size) > int64(reader.Len()) {
+		return nil, fmt.Errorf("string of %d bytes overflows the key", size)
+	}
+	data := make([]byte, size)
+	if _, err := reader.Read(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// mpint encodes the positive integer as in the wire format of ssh, a leading zero byte keeps the ones with the high bit set positive.
+func mpint(n *big.Int) []byte {
+	data := n.Bytes()
+	if len(data) > 0 && data[0]&0x80 != 0 {
+		return append([]byte{0}, data...)
+	}
+	return data
+}
+
+func colonHex(sum []byte) string {
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(parts, ":")
+}
@@ -0,0 +1,198 @@
+package state
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+	"github.com/nikhilsbhat/neuron-cloudy/wait"
+)
+
+// Local is the backend which keeps the state of every stack in a JSON file of its own, <Dir>/<stack>.json.
+// The stack is locked by creating the file <Dir>/<stack>.lock, which works across the processes sharing the directory.
+// The lock left behind by a process which crashed has to be removed by hand, the file tells who held it.
+type Local struct {
+	// Dir is the directory in which the state is kept, it is created if it does not exist.
+	Dir string
+	// LockTimeout is the time to wait for the stack locked by someone else to be unlocked, Lock fails right away if it is not set.
+	LockTimeout time.Duration
+}
+
+// lockInfo is the content of the lock file, it tells who holds the lock.
+type lockInfo struct {
+	ID       string    `json:"id"`
+	PID      int       `json:"pid"`
+	Hostname string    `json:"hostname"`
+	Created  time.Time `json:"created"`
+}
+
+// NewLocal returns the Local backend keeping the state in the directory passed.
+func NewLocal(dir string) *Local {
+	return &Local{Dir: dir}
+}
+
+// Lock locks the stack by creating its lock file, it waits for LockTimeout if the stack is locked already.
+func (l *Local) Lock(ctx context.Context, stack string) (func() error, error) {
+	if err := l.prepare(stack); err != nil {
+		return nil, err
+	}
+
+	info := lockInfo{ID: lockID(), PID: os.Getpid(), Created: time.Now().UTC()}
+	info.Hostname, _ = os.Hostname()
+	content, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+
+	path := l.path(stack, ".lock")
+	acquire := func(context.Context) (bool, string, error) {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			return false, "locked by " + l.holder(path), nil
+		}
+		if err != nil {
+			return false, "", err
+		}
+		_, err = file.Write(content)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(path)
+			return false, "", err
+		}
+		return true, "", nil
+	}
+
+	if l.LockTimeout <= 0 {
+		done, state, err := acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !done {
+			return nil, cloudyerror.Newf(cloudyerror.Conflict, "stack %s is %s", stack, state)
+		}
+	} else {
+		poller := wait.Poller{Interval: 100 * time.Millisecond, MaxInterval: time.Second, Timeout: l.LockTimeout}
+		if err := poller.Until(ctx, "lock of stack "+stack, acquire); err != nil {
+			if cloudyerror.IsTimeout(err) {
+				return nil, cloudyerror.Newf(cloudyerror.Conflict, "stack %s is locked by %s", stack, l.holder(path))
+			}
+			return nil, err
+		}
+	}
+
+	return func() error {
+		held := new(lockInfo)
+		data, err := ioutil.ReadFile(path)
+		if err == nil {
+			err = json.Unmarshal(data, held)
+		}
+		if err != nil || held.ID != info.ID {
+			return cloudyerror.Newf(cloudyerror.Conflict, "the lock of stack %s is no longer held, it was removed or taken by someone else", stack)
+		}
+		return os.Remove(path)
+	}, nil
+}
+
+// Read reads the state of the stack from its file.
+func (l *Local) Read(ctx context.Context, stack string) (*State, error) {
+	if err := l.prepare(stack); err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(l.path(stack, ".json"))
+	if os.IsNotExist(err) {
+		return &State{Stack: stack, Resources: make([]Resource, 0)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	current := new(State)
+	if err := json.Unmarshal(data, current); err != nil {
+		return nil, cloudyerror.Newf(cloudyerror.InvalidInput, "state of stack %s is corrupt: %v", stack, err)
+	}
+	return current, nil
+}
+
+// Write writes the state to the file of its stack, the file is replaced at once so that it is never left half written.
+func (l *Local) Write(ctx context.Context, current *State) error {
+	if err := l.prepare(current.Stack); err != nil {
+		return err
+	}
+	current.Serial++
+	data, err := json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	temp, err := ioutil.TempFile(l.Dir, current.Stack+".json.")
+	if err != nil {
+		return err
+	}
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), l.path(current.Stack, ".json"))
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+	}
+	return err
+}
+
+// prepare validates the name of the stack, as it is used as the name of the file, and creates Dir if it does not exist.
+func (l *Local) prepare(stack string) error {
+	if !validStack(stack) {
+		return cloudyerror.Newf(cloudyerror.InvalidInput, "invalid name of stack '%s', it can contain only alphanumeric characters, hyphens, underscores and dots", stack)
+	}
+	return os.MkdirAll(l.Dir, 0755)
+}
+
+func (l *Local) path(stack, extension string) string {
+	return filepath.Join(l.Dir, stack+extension)
+}
+
+// holder describes who holds the lock of the file passed.
+func (l *Local) holder(path string) string {
+	held := new(lockInfo)
+	data, err := ioutil.ReadFile(path)
+	if err == nil {
+		err = json.Unmarshal(data, held)
+	}
+	if err != nil {
+		return "an unknown process"
+	}
+	return "process " + strconv.Itoa(held.PID) + " on " + held.Hostname + " since " + held.Created.Format(time.RFC3339)
+}
+
+func validStack(stack string) bool {
+	if stack == "" || stack[0] == '.' || len(stack) > 128 {
+		return false
+	}
+	for _, char := range stack {
+		switch {
+		case char >= 'a' && char <= 'z', char >= 'A' && char <= 'Z', char >= '0' && char <= '9', char == '-', char == '_', char == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// lockID returns the random ID with which the lock is identified, so that only its holder unlocks it.
+func lockID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return time.Now().Format(time.RFC3339Nano)
+	}
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,182 @@
+// Package state records the resources created through cloudy, under the name of the stack (environment) they belong to.
+// The state of a stack is kept in a Backend, Local keeps it in JSON files on the disk. The backend is set on cmn.Cloud along with the name of the stack,
+// every create operation of cloudoperations then adds the resources it created to the state and every delete operation removes the ones it deleted.
+// The operations hold the lock of the stack while they run, so that two processes cannot mutate the same stack at once.
+package state
+
+import (
+	"context"
+)
+
+// The kinds of the resources recorded in the state.
+const (
+	KindNetwork         = "network"
+	KindSubnet          = "subnet"
+	KindInternetGateway = "internetgateway"
+	KindSecurityGroup   = "securitygroup"
+	KindServer          = "server"
+	KindImage           = "image"
+	KindLoadbalancer    = "loadbalancer"
+	KindVolume          = "volume"
+	KindSnapshot        = "snapshot"
+	KindAddress         = "address"
+	KindKeyPair         = "keypair"
+)
+
+// The attributes recorded along with the resources, these are the ones compared against the cloud to detect drift.
+const (
+	// AttributeType is the type of the loadbalancer ex: classic, application.
+	AttributeType = "type"
+	// AttributeCidr are the comma separated blocks of IP addresses of the network/subnet.
+	AttributeCidr = "cidr"
+	// AttributePorts are the comma separated ports opened on the security group, or the ports on which the loadbalancer listens.
+	AttributePorts = "ports"
+	// AttributeFlavor is the type/size of the server ex: t2.micro.
+	AttributeFlavor = "flavor"
+)
+
+// Backend stores the state of the stacks.
+type Backend interface {
+	// Lock locks the stack so that no one else can lock it till unlock is called, it fails with an error of code Conflict if the stack is locked already.
+	Lock(ctx context.Context, stack string) (unlock func() error, err error)
+	// Read returns the state of the stack, an empty state is returned if nothing was recorded for the stack yet.
+	Read(ctx context.Context, stack string) (*State, error)
+	// Write replaces the state of the stack with the one passed.
+	Write(ctx context.Context, state *State) error
+}
+
+// Resource is a resource recorded in the state.
+type Resource struct {
+	// Kind of the resource ex: network, subnet, server.
+	Kind string `json:"kind"`
+	// ID is the unique ID given by the cloud to the resource ex: vpc-0a1b2c, the loadbalancers of aws without an ARN are identified by its name.
+	ID string `json:"id"`
+	// Name of the resource.
+	Name string `json:"name,omitempty"`
+	// Cloud to which the resource belongs to.
+	Cloud string `json:"cloud,omitempty"`
+	// Region in which the resource resides.
+	Region string `json:"region,omitempty"`
+	// Parent is the ID of the resource this one is part of ex: the network of a subnet, it is removed along with its parent.
+	Parent string `json:"parent,omitempty"`
+	// Attributes holds the details required to act on the resource later ex: the type of the loadbalancer,
+	// and the ones the resource is expected to have ex: the flavor of the server.
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// State is the state of a stack.
+type State struct {
+	// Stack is the name of the stack.
+	Stack string `json:"stack"`
+	// Serial is incremented every time the state is written.
+	Serial int64 `json:"serial"`
+	// Resources are the resources of the stack, in the order in which they were recorded.
+	Resources []Resource `json:"resources"`
+}
+
+// Change is the change an operation made to the resources of the stack.
+type Change struct {
+	// Created are the resources created by the operation.
+	Created []Resource
+	// Deleted are the IDs of the resources deleted by the operation.
+	Deleted []string
+}
+
+// Add records the resources passed, the resources recorded already are replaced with the ones passed.
+func (s *State) Add(resources ...Resource) {
+	for _, resource := range resources {
+		if resource.ID == "" {
+			continue
+		}
+		replaced := false
+		for index := range s.Resources {
+			if s.Resources[index].Kind == resource.Kind && s.Resources[index].ID == resource.ID {
+				s.Resources[index] = resource
+				replaced = true
+			}
+		}
+		if !replaced {
+			s.Resources = append(s.Resources, resource)
+		}
+	}
+}
+
+// Remove removes the resources of the IDs passed along with the resources which are part of them (ex: subnets of the network), the resources removed are returned.
+func (s *State) Remove(ids ...string) []Resource {
+	removing := make(map[string]bool)
+	for _, id := range ids {
+		removing[id] = true
+	}
+	removed := make([]Resource, 0)
+	for {
+		kept := make([]Resource, 0, len(s.Resources))
+		for _, resource := range s.Resources {
+			if removing[resource.ID] || (resource.Parent != "" && removing[resource.Parent]) {
+				removing[resource.ID] = true
+				removed = append(removed, resource)
+				continue
+			}
+			kept = append(kept, resource)
+		}
+		done := len(kept) == len(s.Resources)
+		s.Resources = kept
+		if done {
+			return removed
+		}
+	}
+}
+
+// Of returns the resources of the kind passed.
+func (s *State) Of(kind string) []Resource {
+	resources := make([]Resource, 0)
+	for _, resource := range s.Resources {
+		if resource.Kind == kind {
+			resources = append(resources, resource)
+		}
+	}
+	return resources
+}
+
+// IDs returns the IDs of the resources of the kind passed, ex: IDs(KindServer) are the InstanceIds to be passed to deleteserver.DeleteServersInput.
+func (s *State) IDs(kind string) []string {
+	ids := make([]string, 0)
+	for _, resource := range s.Of(kind) {
+		ids = append(ids, resource.ID)
+	}
+	return ids
+}
+
+// Apply records the change passed.
+func (s *State) Apply(change Change) {
+	s.Add(change.Created...)
+	s.Remove(change.Deleted...)
+}
+
+// Track runs the operation passed holding the lock of the stack, and records the change made by it in the state of the stack.
+// The change is recorded even when the operation fails, as the operation may have created/deleted some of the resources before failing.
+// The error of the operation takes precedence over the one of recording the change.
+func Track(ctx context.Context, backend Backend, stack string, operation func() (Change, error)) (err error) {
+	unlock, err := backend.Lock(ctx, stack)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if unlockErr := unlock(); err == nil {
+			err = unlockErr
+		}
+	}()
+
+	change, opErr := operation()
+	if len(change.Created) == 0 && len(change.Deleted) == 0 {
+		return opErr
+	}
+	current, err := backend.Read(ctx, stack)
+	if err == nil {
+		current.Apply(change)
+		err = backend.Write(ctx, current)
+	}
+	if opErr != nil {
+		return opErr
+	}
+	return err
+}
@@ -0,0 +1,138 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+func tempLocal(t *testing.T) (*Local, func()) {
+	dir, err := ioutil.TempDir("", "cloudy-state")
+	if err != nil {
+		t.Fatalf("creating the directory of state: %v", err)
+	}
+	return NewLocal(dir), func() { os.RemoveAll(dir) }
+}
+
+func TestRemove(t *testing.T) {
+	current := &State{Stack: "shop"}
+	current.Add(
+		Resource{Kind: KindNetwork, ID: "vpc-1"},
+		Resource{Kind: KindSubnet, ID: "subnet-1", Parent: "vpc-1"},
+		Resource{Kind: KindServer, ID: "i-1"},
+		Resource{Kind: KindNetwork, ID: "vpc-2"},
+		Resource{Kind: KindSubnet, ID: "subnet-1", Parent: "vpc-1"},
+	)
+	if len(current.Resources) != 4 {
+		t.Fatalf("expected the resource recorded twice to be replaced, got %+v", current.Resources)
+	}
+
+	removed := current.Remove("vpc-1")
+	if len(removed) != 2 {
+		t.Errorf("expected the network to be removed along with its subnet, got %+v", removed)
+	}
+	if ids := current.IDs(KindNetwork); !reflect.DeepEqual(ids, []string{"vpc-2"}) {
+		t.Errorf("expected only vpc-2 to be left, got %v", ids)
+	}
+	if ids := current.IDs(KindServer); !reflect.DeepEqual(ids, []string{"i-1"}) {
+		t.Errorf("expected the server to be left as is, got %v", ids)
+	}
+}
+
+func TestLocalReadWrite(t *testing.T) {
+	local, cleanup := tempLocal(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	current, err := local.Read(ctx, "shop")
+	if err != nil || current.Stack != "shop" || len(current.Resources) != 0 {
+		t.Fatalf("expected an empty state for the stack not recorded yet, got %+v, %v", current, err)
+	}
+	current.Add(Resource{Kind: KindServer, ID: "i-1", Cloud: "aws", Region: "us-east-1"})
+	if err := local.Write(ctx, current); err != nil {
+		t.Fatalf("writing the state: %v", err)
+	}
+	read, err := local.Read(ctx, "shop")
+	if err != nil {
+		t.Fatalf("reading the state: %v", err)
+	}
+	if read.Serial != 1 || !reflect.DeepEqual(read.Resources, current.Resources) {
+		t.Errorf("expected the state written, got %+v", read)
+	}
+
+	if _, err := local.Read(ctx, "../shop"); !cloudyerror.IsInvalidInput(err) {
+		t.Errorf("expected the name of stack with path separators to be rejected, got %v", err)
+	}
+}
+
+func TestLocalLock(t *testing.T) {
+	local, cleanup := tempLocal(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	unlock, err := local.Lock(ctx, "shop")
+	if err != nil {
+		t.Fatalf("locking the stack: %v", err)
+	}
+	if _, err := NewLocal(local.Dir).Lock(ctx, "shop"); !cloudyerror.IsConflict(err) {
+		t.Fatalf("expected the stack locked to fail with Conflict, got %v", err)
+	}
+	if other, err := local.Lock(ctx, "web"); err != nil {
+		t.Errorf("expected the other stacks to be locked independently, got %v", err)
+	} else {
+		other()
+	}
+
+	waiting := &Local{Dir: local.Dir, LockTimeout: 5 * time.Second}
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		unlock()
+	}()
+	relock, err := waiting.Lock(ctx, "shop")
+	if err != nil {
+		t.Fatalf("expected the lock to be taken once released, got %v", err)
+	}
+	if err := unlock(); !cloudyerror.IsConflict(err) {
+		t.Errorf("expected the lock taken by someone else not to be released, got %v", err)
+	}
+	if err := relock(); err != nil {
+		t.Errorf("releasing the lock: %v", err)
+	}
+}
+
+func TestTrack(t *testing.T) {
+	local, cleanup := tempLocal(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	failure := errors.New("quota exceeded")
+	err := Track(ctx, local, "shop", func() (Change, error) {
+		if _, err := local.Lock(ctx, "shop"); !cloudyerror.IsConflict(err) {
+			t.Errorf("expected the stack to be locked while the operation runs, got %v", err)
+		}
+		return Change{Created: []Resource{{Kind: KindServer, ID: "i-1"}}}, failure
+	})
+	if err != failure {
+		t.Fatalf("expected the error of the operation, got %v", err)
+	}
+	current, err := local.Read(ctx, "shop")
+	if err != nil {
+		t.Fatalf("reading the state: %v", err)
+	}
+	if ids := current.IDs(KindServer); !reflect.DeepEqual(ids, []string{"i-1"}) {
+		t.Errorf("expected the server created before the failure to be recorded, got %v", ids)
+	}
+
+	if err := Track(ctx, local, "shop", func() (Change, error) { return Change{Deleted: []string{"i-1"}}, nil }); err != nil {
+		t.Fatalf("recording the deletion: %v", err)
+	}
+	if current, _ = local.Read(ctx, "shop"); len(current.Resources) != 0 || current.Serial != 2 {
+		t.Errorf("expected the server to be removed, got %+v", current)
+	}
+}
@@ -0,0 +1,53 @@
+package wait
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// AWSWaiter is the waiter of the sdk of aws (ex: ec2.WaitUntilVpcAvailableWithContext bound to its input).
+type AWSWaiter func(ctx aws.Context, options ...request.WaiterOption) error
+
+// AWS waits on the resource passed with the waiter of the sdk of aws, polling as per the poller instead of the delays and attempts built into the waiter.
+// The waiting is bound by Timeout, on which an error of code Timeout is returned in place of the one of the sdk.
+func (p Poller) AWS(ctx context.Context, resource string, waiter AWSWaiter) error {
+	p = p.withDefaults()
+	waitCtx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := waiter(waitCtx, p.AWSWaiterOptions(resource, start)...)
+	if err != nil && waitCtx.Err() != nil {
+		return p.expired(ctx, resource, "", time.Since(start))
+	}
+	return err
+}
+
+// AWSWaiterOptions returns the options with which the waiters of the sdk of aws poll as per the poller, reporting the progress from the time passed.
+// The attempts of the waiter are not capped, it is the context carrying Timeout which is expected to bound the waiting.
+func (p Poller) AWSWaiterOptions(resource string, start time.Time) []request.WaiterOption {
+	p = p.withDefaults()
+	attempt := 0
+	return []request.WaiterOption{
+		request.WithWaiterMaxAttempts(0),
+		request.WithWaiterDelay(func(attempt int) time.Duration { return p.Delay(attempt) }),
+		request.WithWaiterRequestOptions(func(req *request.Request) {
+			req.Handlers.Complete.PushBack(func(req *request.Request) {
+				attempt++
+				p.report(Progress{Resource: resource, Attempt: attempt, Elapsed: time.Since(start), State: awsState(req.Error)})
+			})
+		}),
+	}
+}
+
+// awsState returns the code of the error with which aws answered the poll, as that is all the waiters of the sdk let one know of the resource.
+func awsState(err error) string {
+	if awsErr, ok := err.(awserr.Error); ok {
+		return awsErr.Code()
+	}
+	return ""
+}
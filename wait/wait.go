@@ -0,0 +1,140 @@
+// Package wait holds the poller with which cloudy waits on the resources of the clouds to reach the state expected
+// (ex: subnet to be deleted, instance to be running). The poller is set on cmn.Cloud, and Default() is used if none is set.
+// The clients of azure take the poller through the Wait of their inputs, which the provider of azure is to set from cmn.Cloud.
+package wait
+
+import (
+	"context"
+	"time"
+
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+// Poller polls the resource till it reaches the state expected, the interval between the polls grows by Backoff up to MaxInterval.
+// The fields left zero are taken from Default(), hence one can override only the ones required (ex: Timeout).
+type Poller struct {
+	// Interval is the time to wait between the first and the second poll.
+	Interval time.Duration `json:"interval"`
+	// MaxInterval caps the interval between the polls.
+	MaxInterval time.Duration `json:"maxinterval"`
+	// Backoff is the factor by which the interval grows after every poll, use 1 to poll at the constant Interval.
+	Backoff float64 `json:"backoff"`
+	// Timeout is the maximum time to wait for the resource, before giving up with an error of code Timeout.
+	Timeout time.Duration `json:"timeout"`
+	// Progress if set, is called after every poll with the progress made so far.
+	Progress func(Progress) `json:"-"`
+}
+
+// Progress is the progress of waiting on a resource, reported after every poll.
+type Progress struct {
+	// Resource is the resource waited on ex: subnet subnet-0a1b2c.
+	Resource string
+	// Attempt is the number of polls made so far.
+	Attempt int
+	// Elapsed is the time spent waiting so far.
+	Elapsed time.Duration
+	// State is the state of the resource as observed in the last poll, empty if it is not known.
+	State string
+}
+
+// Condition polls the resource once and reports whether it reached the state expected, along with the state observed.
+// The error returned stops the waiting and is returned as is.
+type Condition func(ctx context.Context) (done bool, state string, err error)
+
+// Default returns the poller used when none is set, it polls every 2s to begin with growing by 1.5 times up to 15s, for at most 10 minutes.
+func Default() Poller {
+	return Poller{Interval: 2 * time.Second, MaxInterval: 15 * time.Second, Backoff: 1.5, Timeout: 10 * time.Minute}
+}
+
+// OrDefault returns the poller passed with its zero fields taken from Default(), Default() is returned if it is nil.
+func OrDefault(poller *Poller) Poller {
+	if poller == nil {
+		return Default()
+	}
+	return poller.withDefaults()
+}
+
+func (p Poller) withDefaults() Poller {
+	defaults := Default()
+	if p.Interval <= 0 {
+		p.Interval = defaults.Interval
+	}
+	if p.MaxInterval <= 0 {
+		p.MaxInterval = defaults.MaxInterval
+	}
+	if p.MaxInterval < p.Interval {
+		p.MaxInterval = p.Interval
+	}
+	if p.Backoff < 1 {
+		p.Backoff = defaults.Backoff
+	}
+	if p.Timeout <= 0 {
+		p.Timeout = defaults.Timeout
+	}
+	return p
+}
+
+// Delay returns the time to wait after the number of polls passed, before polling again.
+func (p Poller) Delay(attempt int) time.Duration {
+	p = p.withDefaults()
+	delay := float64(p.Interval)
+	for i := 1; i < attempt && delay < float64(p.MaxInterval); i++ {
+		delay *= p.Backoff
+	}
+	if delay > float64(p.MaxInterval) {
+		return p.MaxInterval
+	}
+	return time.Duration(delay)
+}
+
+// Until polls the resource passed with the condition till it is met, the condition fails, the Timeout expires or the context is done.
+// The context passed to the condition expires along with Timeout, so that the calls made by the condition do not outlive it.
+func (p Poller) Until(ctx context.Context, resource string, condition Condition) error {
+	p = p.withDefaults()
+	waitCtx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	state := ""
+	for attempt := 1; ; attempt++ {
+		done, observed, err := condition(waitCtx)
+		if err != nil {
+			if waitCtx.Err() != nil {
+				return p.expired(ctx, resource, state, time.Since(start))
+			}
+			return err
+		}
+		state = observed
+		p.report(Progress{Resource: resource, Attempt: attempt, Elapsed: time.Since(start), State: state})
+		if done {
+			return nil
+		}
+
+		timer := time.NewTimer(p.Delay(attempt))
+		select {
+		case <-waitCtx.Done():
+			timer.Stop()
+			return p.expired(ctx, resource, state, time.Since(start))
+		case <-timer.C:
+		}
+	}
+}
+
+// report calls the Progress callback, if one is set.
+func (p Poller) report(progress Progress) {
+	if p.Progress != nil {
+		p.Progress(progress)
+	}
+}
+
+// expired returns the error for the waiting which ended without the resource reaching the state expected,
+// either because the context of the caller was done or the Timeout of the poller expired.
+func (p Poller) expired(ctx context.Context, resource, state string, elapsed time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return cloudyerror.Wrap(cloudyerror.Timeout, err)
+	}
+	if state != "" {
+		return cloudyerror.Newf(cloudyerror.Timeout, "gave up waiting on %s after %v, it was last seen %s", resource, elapsed.Round(time.Millisecond), state)
+	}
+	return cloudyerror.Newf(cloudyerror.Timeout, "gave up waiting on %s after %v", resource, elapsed.Round(time.Millisecond))
+}
@@ -0,0 +1,51 @@
+package wait
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudyerror "github.com/nikhilsbhat/neuron-cloudy/errors"
+)
+
+func TestDelay(t *testing.T) {
+	poller := Poller{Interval: 100 * time.Millisecond, MaxInterval: time.Second, Backoff: 2}
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for i, want := range expected {
+		if got := poller.Delay(i + 1); got != want {
+			t.Errorf("expected delay after poll %d to be %v, got %v", i+1, want, got)
+		}
+	}
+	if got := (Poller{}).Delay(1); got != Default().Interval {
+		t.Errorf("expected the zero poller to poll at the default interval, got %v", got)
+	}
+}
+
+func TestUntil(t *testing.T) {
+	var progress []Progress
+	poller := Poller{Interval: time.Millisecond, Timeout: time.Second, Progress: func(p Progress) { progress = append(progress, p) }}
+	err := poller.Until(context.Background(), "subnet", func(context.Context) (bool, string, error) {
+		if len(progress) < 2 {
+			return false, "pending", nil
+		}
+		return true, "available", nil
+	})
+	if err != nil {
+		t.Fatalf("expected the condition to be met, got %v", err)
+	}
+	if len(progress) != 3 || progress[2].Attempt != 3 || progress[2].State != "available" {
+		t.Errorf("expected the progress of 3 polls, got %+v", progress)
+	}
+}
+
+func TestUntilStopsOnContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	poller := Poller{Interval: time.Hour}
+	err := poller.Until(ctx, "subnet", func(context.Context) (bool, string, error) {
+		cancel()
+		return false, "pending", nil
+	})
+	if !cloudyerror.IsTimeout(err) {
+		t.Errorf("expected the waiting to be cut short by the context, got %v", err)
+	}
+}